@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// aliasPrefix marks the first argument as the name of a configured alias.
+const aliasPrefix = "@"
+
+// expandAlias replaces a leading "@name" argument with the arguments stored
+// under app.cli.aliases.name in the config, keeping any arguments after it.
+// It returns nil when args do not start with an alias. An "@name" that is not
+// an alias but an existing file is left alone, so such files still open.
+func expandAlias(args []string) ([]string, error) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], aliasPrefix) || len(args[0]) == len(aliasPrefix) {
+		return nil, nil
+	}
+	name := strings.TrimPrefix(args[0], aliasPrefix)
+
+	cfg, err := loadMergedConfig(resolveConfigPath(configFileFromArgs(args[1:])))
+	if err != nil {
+		return nil, err
+	}
+	value, ok := cfg.CLI.Aliases[name]
+	if !ok {
+		if _, statErr := os.Stat(args[0]); statErr == nil {
+			return nil, nil
+		}
+		return nil, unknownAliasError(name, cfg.CLI.Aliases)
+	}
+	expanded, err := splitAliasArgs(value)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s%s: %w", aliasPrefix, name, err)
+	}
+	return append(expanded, args[1:]...), nil
+}
+
+// configFileFromArgs returns the --config-file value from args, which are
+// not parsed yet when aliases are expanded.
+func configFileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--config-file="); ok {
+			return v
+		}
+		if arg == "--config-file" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func unknownAliasError(name string, aliases map[string]string) error {
+	if len(aliases) == 0 {
+		return fmt.Errorf("unknown alias %s%s (no aliases defined; add them under app.cli.aliases in the config)", aliasPrefix, name)
+	}
+	names := make([]string, 0, len(aliases))
+	for n := range aliases {
+		names = append(names, aliasPrefix+n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown alias %s%s (available: %s)", aliasPrefix, name, strings.Join(names, ", "))
+}
+
+// splitAliasArgs splits an alias value into arguments the way a POSIX shell
+// would: whitespace separates arguments, single quotes keep everything
+// literally, and inside double quotes or unquoted text a backslash escapes
+// the next character.
+func splitAliasArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAliasConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `app:
+  cli:
+    aliases:
+      names: "-e '_.items.map(i, i.name)' -o json"
+      broken: "-e 'oops"
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestCLI_Alias(t *testing.T) {
+	cfgPath := writeAliasConfig(t)
+	out := runCLI(t, []string{"kvx", "@names", filepath.Join("..", "tests", "sample.yaml"), "--config-file", cfgPath})
+	assert.Equal(t, "[\n  \"chamomile\",\n  \"earl-grey\",\n  \"matcha\"\n]\n", out)
+}
+
+func TestExpandAlias(t *testing.T) {
+	cfgPath := writeAliasConfig(t)
+
+	args, err := expandAlias([]string{"@names", "data.json", "--config-file=" + cfgPath})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-e", "_.items.map(i, i.name)", "-o", "json", "data.json", "--config-file=" + cfgPath}, args)
+
+	args, err = expandAlias([]string{"data.json", "@names"})
+	require.NoError(t, err)
+	assert.Nil(t, args, "only a leading argument is an alias")
+
+	_, err = expandAlias([]string{"@nope", "--config-file", cfgPath})
+	require.EqualError(t, err, "unknown alias @nope (available: @broken, @names)")
+
+	_, err = expandAlias([]string{"@broken", "--config-file", cfgPath})
+	require.EqualError(t, err, "alias @broken: unterminated quote or escape")
+}
+
+func TestExpandAlias_ExistingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	require.NoError(t, os.WriteFile("@data.json", []byte(`{}`), 0o600))
+
+	args, err := expandAlias([]string{"@data.json"})
+	require.NoError(t, err)
+	assert.Nil(t, args)
+
+	_, err = expandAlias([]string{"@missing"})
+	require.EqualError(t, err, "unknown alias @missing (no aliases defined; add them under app.cli.aliases in the config)")
+}
+
+func TestConfigFileFromArgs(t *testing.T) {
+	assert.Equal(t, "a.yaml", configFileFromArgs([]string{"x", "--config-file", "a.yaml"}))
+	assert.Equal(t, "b.yaml", configFileFromArgs([]string{"--config-file=b.yaml"}))
+	assert.Equal(t, "", configFileFromArgs([]string{"--", "--config-file", "c.yaml"}))
+	assert.Equal(t, "", configFileFromArgs([]string{"--config-file"}))
+}
+
+func TestSplitAliasArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "-o  table\t-i", want: []string{"-o", "table", "-i"}},
+		{in: `-e '_.items.map(i, i["a b"])'`, want: []string{"-e", `_.items.map(i, i["a b"])`}},
+		{in: `-w "_.kind == \"Pod\""`, want: []string{"-w", `_.kind == "Pod"`}},
+		{in: `a\ b 'c\d' ""`, want: []string{"a b", `c\d`, ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitAliasArgs(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := splitAliasArgs(`-e "open`)
+	require.Error(t, err)
+	_, err = splitAliasArgs(`trailing\`)
+	require.Error(t, err)
+}
+
+func TestLoadMergedConfig_MergesAliases(t *testing.T) {
+	cfg, err := loadMergedConfig(writeAliasConfig(t))
+	require.NoError(t, err)
+	assert.Equal(t, "-e '_.items.map(i, i.name)' -o json", cfg.CLI.Aliases["names"])
+	assert.NotEmpty(t, cfg.CLI.HelpUsage, "other CLI settings keep their defaults")
+}
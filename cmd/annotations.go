@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// loadAnnotations reads the annotations saved in the --annotations file,
+// so a triage can be resumed. A file that does not exist yet holds none.
+func loadAnnotations(path string) ([]ui.Annotation, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	}
+	var annotations []ui.Annotation
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("failed to parse annotations %s: %w", path, err)
+	}
+	return annotations, nil
+}
+
+// configureAnnotations starts the TUI with the loaded annotations and
+// stores the annotations made before it exits in out.
+func configureAnnotations(m *ui.Model, loaded []ui.Annotation, out *[]ui.Annotation) {
+	m.Annotations = loaded
+	m.OnExitAnnotations = func(a []ui.Annotation) {
+		*out = a
+	}
+}
+
+// writeAnnotations exports the annotations as a JSON list of paths and
+// notes: to the --annotations file, replacing it, or to w when there are
+// any and no file was given.
+func writeAnnotations(w io.Writer, annotations []ui.Annotation) error {
+	if annotationsFile == "" && len(annotations) == 0 {
+		return nil
+	}
+	if annotations == nil {
+		annotations = []ui.Annotation{}
+	}
+	data, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if annotationsFile == "" {
+		_, err = w.Write(data)
+		return err
+	}
+	if err := os.WriteFile(annotationsFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func setAnnotationsFile(t *testing.T, path string) {
+	t.Helper()
+	prev := annotationsFile
+	t.Cleanup(func() { annotationsFile = prev })
+	annotationsFile = path
+}
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "triage.json")
+	setAnnotationsFile(t, path)
+
+	loaded, err := loadAnnotations(path)
+	require.NoError(t, err)
+	assert.Nil(t, loaded, "a missing file holds no annotations")
+
+	want := []ui.Annotation{{Path: "_.findings[3]", Note: "false positive"}, {Path: "_.findings[7]"}}
+	var stdout bytes.Buffer
+	require.NoError(t, writeAnnotations(&stdout, want))
+	assert.Empty(t, stdout.String())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"path":"_.findings[3]","note":"false positive"},{"path":"_.findings[7]"}]`, string(data))
+
+	loaded, err = loadAnnotations(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, loaded)
+
+	// Removing every annotation empties the file.
+	require.NoError(t, writeAnnotations(&stdout, nil))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+}
+
+func TestWriteAnnotations_Stdout(t *testing.T) {
+	setAnnotationsFile(t, "")
+	var buf bytes.Buffer
+	require.NoError(t, writeAnnotations(&buf, nil))
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, writeAnnotations(&buf, []ui.Annotation{{Path: "_.a", Note: "check"}}))
+	assert.JSONEq(t, `[{"path":"_.a","note":"check"}]`, buf.String())
+}
+
+func TestLoadAnnotations_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err := loadAnnotations(path)
+	assert.ErrorContains(t, err, "failed to parse annotations")
+}
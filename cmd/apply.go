@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/patch"
+)
+
+var (
+	applyPatchFile string
+	applyMerge     bool
+	applyOutput    string
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply --patch FILE [file]",
+	Short: "Apply a JSON Patch or merge patch to a document",
+	Long: `Apply an RFC 6902 JSON Patch or an RFC 7386 JSON Merge Patch, such as one
+printed by 'kvx patch', to a document and print the result in the document's
+format (JSON, YAML, multi-document YAML, NDJSON, or TOML). Without a file the
+document is read from stdin.
+
+A patch that is a list of operation objects is applied as a JSON Patch; any
+other patch as a merge patch, or always with --merge. A failing operation,
+including a failed "test", stops the patch and nothing is printed.`,
+	Example: `  kvx apply --patch changes.json config.yaml
+  kvx patch --from a.json --to b.json | kvx apply --patch - a.json
+  cat config.json | kvx apply --patch overrides.yaml --merge -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		input := "-"
+		if len(args) == 1 {
+			input = args[0]
+		}
+		return runApply(os.Stdout, input)
+	},
+}
+
+func runApply(w io.Writer, input string) error {
+	if applyPatchFile == "" {
+		return errors.New("apply needs --patch")
+	}
+	if applyPatchFile == "-" && input == "-" {
+		return errors.New("the patch and the document cannot both be read from stdin")
+	}
+	p, err := loadDocumentArg(applyPatchFile)
+	if err != nil {
+		return err
+	}
+	doc, err := loadDocumentArg(input)
+	if err != nil {
+		return err
+	}
+
+	var result interface{}
+	if !applyMerge && patch.IsDocument(p.Root) {
+		ops, err := patch.ParseDocument(p.Root)
+		if err != nil {
+			return fmt.Errorf("invalid JSON Patch: %w", err)
+		}
+		if result, err = patch.Apply(doc.Root, ops); err != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+	} else {
+		result = patch.ApplyMerge(doc.Root, p.Root)
+	}
+	return writeApplyResult(w, result, applyOutputFormat(applyOutput, doc.Format))
+}
+
+// applyOutputFormat returns the -o format, or the one matching the format
+// the document was read in.
+func applyOutputFormat(flag, inputFormat string) string {
+	if flag != "" {
+		return flag
+	}
+	switch inputFormat {
+	case "YAML":
+		return "yaml"
+	case "multi-doc YAML":
+		return "yaml-documents"
+	case "NDJSON":
+		return "ndjson"
+	case "TOML":
+		return "toml"
+	default:
+		return "json"
+	}
+}
+
+func writeApplyResult(w io.Writer, v interface{}, format string) error {
+	cfg, err := loadMergedConfig(resolveConfigPath(configFile))
+	if err != nil {
+		return err
+	}
+	yamlOpts := yamlFormatOptionsFromConfig(cfg)
+	yamlOpts.PreserveSource = false
+
+	switch format {
+	case "json":
+		return formatter.WriteJSON(w, v, formatter.SerializeOptions{})
+	case "yaml":
+		return formatter.WriteYAML(w, v, yamlOpts)
+	case "yaml-documents":
+		for i, d := range documentsOf(v) {
+			s, err := formatter.FormatYAML(d, yamlOpts)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				s = "---\n" + s
+			}
+			if _, err := io.WriteString(w, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "ndjson":
+		for _, d := range documentsOf(v) {
+			s, err := formatter.FormatJSON(d, formatter.SerializeOptions{Compact: true})
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "toml":
+		s, err := formatter.FormatTOML(v)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	default:
+		return fmt.Errorf("invalid apply output format %q (expected json, yaml, ndjson, or toml)", format)
+	}
+}
+
+// documentsOf returns the documents of multi-document input, whose root is
+// the list of its documents.
+func documentsOf(v interface{}) []interface{} {
+	if docs, ok := v.([]interface{}); ok {
+		return docs
+	}
+	return []interface{}{v}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetApplyFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		applyPatchFile = ""
+		applyMerge = false
+		applyOutput = ""
+	})
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestCLI_ApplyJSONPatchKeepsFormat(t *testing.T) {
+	resetApplyFlags(t)
+	data := writeTempFile(t, "deploy.yaml", "name: web\nspec:\n  replicas: 1\n  paused: true\n")
+	p := writeTempFile(t, "patch.json", `[
+		{"op": "test", "path": "/name", "value": "web"},
+		{"op": "remove", "path": "/spec/paused"},
+		{"op": "replace", "path": "/spec/replicas", "value": 3}
+	]`)
+
+	out := runCLI(t, []string{"kvx", "apply", "--patch", p, data})
+	assert.Equal(t, "name: web\nspec:\n  replicas: 3\n", out)
+
+	out = runCLI(t, []string{"kvx", "apply", "--patch", p, data, "-o", "json"})
+	assert.JSONEq(t, `{"name": "web", "spec": {"replicas": 3}}`, out)
+}
+
+func TestCLI_ApplyMergePatch(t *testing.T) {
+	resetApplyFlags(t)
+	data := writeTempFile(t, "app.json", `{"name": "web", "labels": {"tier": "1", "old": "x"}}`)
+	p := writeTempFile(t, "merge.yaml", "labels:\n  old: null\n  team: core\n")
+
+	out := runCLI(t, []string{"kvx", "apply", "--patch", p, data})
+	assert.Equal(t, "{\n  \"labels\": {\n    \"team\": \"core\",\n    \"tier\": \"1\"\n  },\n  \"name\": \"web\"\n}\n", out)
+}
+
+func TestRunApply_PatchRoundTrip(t *testing.T) {
+	resetApplyFlags(t)
+	resetPatchFlags(t)
+	from := writeTempFile(t, "from.ndjson", "{\"id\": 1, \"on\": true}\n{\"id\": 2, \"on\": false}\n")
+	to := writeTempFile(t, "to.ndjson", "{\"id\": 1, \"on\": false}\n{\"id\": 2, \"on\": false}\n{\"id\": 3}\n")
+
+	patchFrom, patchTo = from, to
+	var p strings.Builder
+	require.NoError(t, runPatch(&p))
+	applyPatchFile = writeTempFile(t, "patch.json", p.String())
+
+	var out strings.Builder
+	require.NoError(t, runApply(&out, from))
+	assert.Equal(t, "{\"id\":1,\"on\":false}\n{\"id\":2,\"on\":false}\n{\"id\":3}\n", out.String())
+}
+
+func TestRunApply_Errors(t *testing.T) {
+	resetApplyFlags(t)
+	assert.ErrorContains(t, runApply(os.Stdout, "-"), "needs --patch")
+
+	applyPatchFile = "-"
+	assert.ErrorContains(t, runApply(os.Stdout, "-"), "both be read from stdin")
+
+	data := writeTempFile(t, "data.json", `{"a": 1}`)
+	applyPatchFile = writeTempFile(t, "patch.json", `[{"op": "remove", "path": "/b"}]`)
+	assert.ErrorContains(t, runApply(os.Stdout, data), `no member "b"`)
+}
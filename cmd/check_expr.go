@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
+	"github.com/oakwood-commons/kvx/internal/placeholder"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// runCheckExpr implements --check-expr: it type-checks the -e and -w
+// expressions against the types described by the JSON Schema from --schema
+// (or the config schema) without loading any input, so stored queries can be
+// linted in CI. Each expression gets an "ok" line on stdout; the returned
+// error lists every expression that failed. Placeholders take their --param
+// value, or stand for a value of any type.
+func runCheckExpr() error {
+	if expression == "" && whereExpr == "" {
+		return errors.New("--check-expr needs an expression to check (-e and/or -w)")
+	}
+	cfg, _ := loadMergedConfig(resolveConfigPath(configFile))
+	schema, err := loadCheckSchema(cfg)
+	if err != nil {
+		return err
+	}
+	where, err := checkPlaceholders(whereExpr)
+	if err != nil {
+		return err
+	}
+	expr, err := checkPlaceholders(expression)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if whereExpr != "" {
+		if err := celhelper.CheckWhere(where, schema); err != nil {
+			errs = append(errs, fmt.Errorf("-w: %w", err))
+		} else {
+			fmt.Printf("ok: -w %s\n", whereExpr) //nolint:forbidigo
+		}
+	}
+	if expression != "" {
+		typ, err := celhelper.CheckExpression(expr, schema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("-e: %w", err))
+		} else {
+			fmt.Printf("ok: -e %s returns %s\n", expression, typ) //nolint:forbidigo
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkPlaceholders replaces the placeholders of expr with their --param
+// values, and the others with dyn(null) so any use of them type-checks.
+func checkPlaceholders(expr string) (string, error) {
+	specs, err := placeholder.Parse(expr)
+	if err != nil || len(specs) == 0 {
+		return expr, err
+	}
+	params, err := parseParams(exprParams)
+	if err != nil {
+		return "", err
+	}
+	values := make(map[string]interface{}, len(specs))
+	for _, p := range specs {
+		if text, ok := params[p.Name]; ok {
+			values[p.Name] = placeholder.Value(text)
+		} else {
+			values[p.Name] = placeholder.Raw("dyn(null)")
+		}
+	}
+	return placeholder.Expand(expr, values)
+}
+
+// loadCheckSchema returns the JSON Schema used to type '_' for
+// --check-expr, with the same precedence as the table hints: --schema, then
+// the config schema_file, then the inline config schema. It returns nil when
+// no schema is configured.
+func loadCheckSchema(cfg ui.ThemeConfigFile) (map[string]any, error) {
+	schemaPath := schemaFile
+	if schemaPath == "" && cfg.Formatting.Table.SchemaFile != nil {
+		schemaPath = *cfg.Formatting.Table.SchemaFile
+	}
+	var data []byte
+	switch {
+	case schemaPath != "":
+		raw, err := os.ReadFile(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read schema file %s: %w", schemaPath, err)
+		}
+		data = raw
+	case len(cfg.Formatting.Table.Schema) > 0:
+		raw, err := json.Marshal(cfg.Formatting.Table.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("cannot serialize inline schema: %w", err)
+		}
+		data = raw
+	default:
+		return nil, nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return schema, nil
+}
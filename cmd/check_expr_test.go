@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+const checkExprTestSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "name": {"type": "string"},
+          "available": {"type": "boolean"}
+        }
+      }
+    }
+  }
+}`
+
+func writeCheckExprSchema(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(checkExprTestSchema), 0o600))
+	return path
+}
+
+func TestCLI_CheckExpr(t *testing.T) {
+	schema := writeCheckExprSchema(t)
+
+	out := runCLI(t, []string{"kvx", "--check-expr", "--schema", schema, "-e", "_.items.filter(i, i.available).map(i, i.name)"})
+	assert.Equal(t, "ok: -e _.items.filter(i, i.available).map(i, i.name) returns list(string)\n", out)
+}
+
+func TestRunCheckExpr_ReportsTypeErrors(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	schemaFile = writeCheckExprSchema(t)
+	expression = "_.items.map(i, i.nam)"
+	whereExpr = `_.kind == "Pod"`
+
+	var err error
+	out := captureOutput(t, func() { err = runCheckExpr() })
+	assert.Equal(t, "ok: -w _.kind == \"Pod\"\n", out, "the root is not a list, so items are untyped")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-e: compilation error")
+	assert.Contains(t, err.Error(), "undefined field 'nam'")
+}
+
+func TestRunCheckExpr_NoSchema(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	expression = "_.a.b + 1"
+	out := captureOutput(t, func() { require.NoError(t, runCheckExpr()) })
+	assert.Equal(t, "ok: -e _.a.b + 1 returns int\n", out)
+
+	expression = "_.a.nosuch()"
+	require.ErrorContains(t, runCheckExpr(), "undeclared reference to 'nosuch'")
+
+	expression = "_.items.filter(i, i.env == {{env}} && i.count > {{min=1}})"
+	out = captureOutput(t, func() { require.NoError(t, runCheckExpr()) })
+	assert.Equal(t, "ok: -e "+expression+" returns list(dyn)\n", out)
+
+	expression = ""
+	require.EqualError(t, runCheckExpr(), "--check-expr needs an expression to check (-e and/or -w)")
+}
+
+func TestLoadCheckSchema(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+
+	schema, err := loadCheckSchema(ui.ThemeConfigFile{})
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+
+	var cfg ui.ThemeConfigFile
+	cfg.Formatting.Table.Schema = map[string]any{"type": "array"}
+	schema, err = loadCheckSchema(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "array", schema["type"])
+
+	schemaFile = writeCheckExprSchema(t)
+	schema, err = loadCheckSchema(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "object", schema["type"], "--schema wins over the config")
+
+	schemaFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = loadCheckSchema(cfg)
+	require.ErrorContains(t, err, "cannot read schema file")
+}
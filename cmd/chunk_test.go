@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeChunkFixtures(t *testing.T, items int) (dataPath, cfgPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	list := make([]map[string]int, items)
+	for i := range list {
+		list[i] = map[string]int{"id": i}
+	}
+	data, err := json.Marshal(list)
+	require.NoError(t, err)
+	dataPath = filepath.Join(dir, "items.json")
+	require.NoError(t, os.WriteFile(dataPath, data, 0o600))
+	cfgPath = filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("ui:\n  performance:\n    array_chunk_size: 10\n    array_chunk_threshold: 20\n"), 0o600))
+	return dataPath, cfgPath
+}
+
+func TestCLI_TableChunksLargeArrays(t *testing.T) {
+	dataPath, cfgPath := writeChunkFixtures(t, 1234)
+
+	out := runCLI(t, []string{"kvx", dataPath, "--config-file", cfgPath, "--no-color", "-o", "table"})
+	assert.Contains(t, out, "… 1,224 more (--chunk-size 0 prints them all)")
+	assert.Contains(t, out, "list: 1/10")
+
+	out = runCLI(t, []string{"kvx", dataPath, "--config-file", cfgPath, "--no-color", "-o", "table", "--chunk-size", "0"})
+	assert.NotContains(t, out, "more (--chunk-size")
+	assert.Contains(t, out, "list: 1/1234")
+
+	// Summary rows aggregate every row, so their tables stay whole.
+	out = runCLI(t, []string{"kvx", dataPath, "--config-file", cfgPath, "--no-color", "-o", "table", "--summary", "id=count"})
+	assert.NotContains(t, out, "more (--chunk-size")
+}
+
+func TestCLI_TableKeepsSmallArraysWhole(t *testing.T) {
+	dataPath, cfgPath := writeChunkFixtures(t, 20)
+	out := runCLI(t, []string{"kvx", dataPath, "--config-file", cfgPath, "--no-color", "-o", "table"})
+	assert.NotContains(t, out, "more (--chunk-size")
+}
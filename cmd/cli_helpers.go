@@ -147,3 +147,15 @@ func loadConfigState(path string, cliTheme string, themeFlagSet bool, applyExamp
 
 	return cfg, nil
 }
+
+// flagPathSteps splits a path given to a flag, such as spec.tags,
+// _.spec.tags, or items[0]["odd.key"], into its keys and indexes. The
+// leading "_." is optional. It reports false for paths that do not only
+// navigate from the root.
+func flagPathSteps(path string) ([]string, bool) {
+	expr := strings.TrimSpace(path)
+	if expr != "" && expr != "_" && !strings.HasPrefix(expr, "_.") && !strings.HasPrefix(expr, "_[") {
+		expr = "_." + expr
+	}
+	return navigator.PathSteps(expr)
+}
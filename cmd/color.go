@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/colorprofile"
+
+	"github.com/oakwood-commons/kvx/internal/termcolor"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// colorProfile is how styled output of the current run is colored. Run sets
+// it from resolveColorProfile before anything is rendered, as do subcommands
+// that print styled tables; it stays Unknown otherwise, which leaves output
+// unconverted.
+var colorProfile colorprofile.Profile
+
+// resolveColorProfile combines --color, --no-color, the environment and
+// terminal detection into the color profile for this run. Interactive and
+// snapshot sessions render for a terminal even when stdout is piped. Legacy
+// Windows consoles that cannot display escape sequences count as no terminal.
+func resolveColorProfile() (colorprofile.Profile, error) {
+	mode, err := termcolor.ParseMode(colorMode)
+	if err != nil {
+		return colorprofile.Unknown, err
+	}
+	if noColor || (deterministic && mode == termcolor.ModeAuto) {
+		mode = termcolor.ModeNever
+	}
+	tty := interactive || renderSnapshot || (!stdoutIsPiped() && ui.ColorSupported(os.Stdout))
+	return termcolor.Detect(mode, tty, os.Environ()), nil
+}
+
+// plainOutput reports whether the run renders without styles, as asked for
+// by --no-color, --color=never, NO_COLOR and the like. Renderers check it
+// instead of taking their own flag; output that is merely piped is styled and
+// stripped by colorStdout.
+func plainOutput() bool {
+	return termcolor.Plain(colorProfile)
+}
+
+// colorStdout returns stdout converted to the run's color profile.
+func colorStdout() io.Writer {
+	return termcolor.Writer(os.Stdout, colorProfile)
+}
+
+// colorProgramOptions passes the run's color profile on to Bubble Tea.
+func colorProgramOptions() []tea.ProgramOption {
+	if colorProfile == colorprofile.Unknown {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithColorProfile(colorProfile)}
+}
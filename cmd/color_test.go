@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveColorProfile(t *testing.T) {
+	origPiped, origMode, origNoColor, origInteractive := stdoutIsPiped, colorMode, noColor, interactive
+	t.Cleanup(func() {
+		stdoutIsPiped, colorMode, noColor, interactive = origPiped, origMode, origNoColor, origInteractive
+	})
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	colorMode, noColor, interactive = "auto", false, false
+
+	stdoutIsPiped = func() bool { return true }
+	p, err := resolveColorProfile()
+	require.NoError(t, err)
+	assert.Equal(t, colorprofile.NoTTY, p, "piped output is stripped of escapes")
+
+	interactive = true
+	p, err = resolveColorProfile()
+	require.NoError(t, err)
+	assert.Equal(t, colorprofile.ANSI256, p, "the TUI renders for the terminal even when stdout is piped")
+
+	interactive = false
+	colorMode = "always"
+	p, err = resolveColorProfile()
+	require.NoError(t, err)
+	assert.Equal(t, colorprofile.ANSI256, p)
+
+	noColor = true
+	p, err = resolveColorProfile()
+	require.NoError(t, err)
+	assert.Equal(t, colorprofile.ASCII, p, "--no-color wins over --color")
+
+	noColor = false
+	colorMode = "rainbow"
+	_, err = resolveColorProfile()
+	assert.Error(t, err)
+}
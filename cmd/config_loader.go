@@ -80,7 +80,7 @@ func (l configLoader) loadMergedConfig(cfgPath string) (ui.ThemeConfigFile, erro
 			App ui.AppConfig `yaml:"app"`
 			UI  uiBlock      `yaml:"ui"`
 		}
-		if err := yaml.Unmarshal(data, &nested); err == nil && (nested.UI.Theme.Default != "" || nested.UI.Defaults != (uiDefaults{}) || len(nested.UI.Themes) > 0 || menuHasData(nested.UI.Menu) || nested.App.Debug.MaxEvents != nil || nested.App.About.Name != "") {
+		if err := yaml.Unmarshal(data, &nested); err == nil && (nested.UI.Theme.Default != "" || nested.UI.Defaults != (uiDefaults{}) || len(nested.UI.Themes) > 0 || len(nested.UI.Layouts) > 0 || menuHasData(nested.UI.Menu) || nested.UI.Features != (ui.FeaturesConfig{}) || nested.UI.Behavior != (ui.BehaviorConfig{}) || nested.UI.Performance != (ui.PerformanceConfig{}) || formattingHasData(nested.UI.Formatting) || nested.App.Debug.MaxEvents != nil || nested.App.About.Name != "" || len(nested.App.CLI.Aliases) > 0) {
 			// Merge user config on top of defaults
 			cfg = mergeConfigFromNested(nested, cfg)
 			// Continue to populate themes if needed
@@ -221,6 +221,7 @@ func (l configLoader) sanitizeConfig(cfg ui.ThemeConfigFile) outputConfig {
 			Formatting:   cfg.Formatting,
 			Popup:        cfg.Popup,
 			Themes:       cfg.Themes,
+			Layouts:      cfg.Layouts,
 			Menu:         cfg.Menu,
 		},
 	}
@@ -273,6 +274,12 @@ func mergeConfigFromNested(nested struct {
 	if nested.App.CLI.HelpUsage != "" {
 		cfg.CLI.HelpUsage = nested.App.CLI.HelpUsage
 	}
+	if len(nested.App.CLI.Aliases) > 0 {
+		aliases := make(map[string]string, len(cfg.CLI.Aliases)+len(nested.App.CLI.Aliases))
+		maps.Copy(aliases, cfg.CLI.Aliases)
+		maps.Copy(aliases, nested.App.CLI.Aliases)
+		cfg.CLI.Aliases = aliases
+	}
 	// Merge UI-level settings - new structure
 	if len(nested.UI.Help.CEL.FunctionExamples) > 0 {
 		if cfg.Help.CEL.FunctionExamples == nil {
@@ -294,18 +301,51 @@ func mergeConfigFromNested(nested struct {
 	if nested.UI.Features.AllowFilter != nil {
 		cfg.Features.AllowFilter = nested.UI.Features.AllowFilter
 	}
+	if nested.UI.Features.KeyMode != nil {
+		cfg.Features.KeyMode = nested.UI.Features.KeyMode
+	}
+	if nested.UI.Features.Hyperlinks != nil {
+		cfg.Features.Hyperlinks = nested.UI.Features.Hyperlinks
+	}
+	if nested.UI.Features.Notify != nil {
+		cfg.Features.Notify = nested.UI.Features.Notify
+	}
+	if nested.UI.Features.Bell != nil {
+		cfg.Features.Bell = nested.UI.Features.Bell
+	}
+	if nested.UI.Features.VisualBell != nil {
+		cfg.Features.VisualBell = nested.UI.Features.VisualBell
+	}
 	if nested.UI.Features.AllowSuggestions != nil {
 		cfg.Features.AllowSuggestions = nested.UI.Features.AllowSuggestions
 	}
 	if nested.UI.Features.AllowIntellisense != nil {
 		cfg.Features.AllowIntellisense = nested.UI.Features.AllowIntellisense
 	}
+	if nested.UI.Intellisense.MatchMode != nil {
+		cfg.Intellisense.MatchMode = nested.UI.Intellisense.MatchMode
+	}
 	if nested.UI.Display.KeyColWidth != nil {
 		cfg.Display.KeyColWidth = nested.UI.Display.KeyColWidth
 	}
 	if nested.UI.Display.Sort != nil {
 		cfg.Display.Sort = nested.UI.Display.Sort
 	}
+	if nested.UI.Behavior.IdleTimeout != nil {
+		cfg.Behavior.IdleTimeout = nested.UI.Behavior.IdleTimeout
+	}
+	if nested.UI.Behavior.IdlePrint != nil {
+		cfg.Behavior.IdlePrint = nested.UI.Behavior.IdlePrint
+	}
+	if len(nested.UI.Layouts) > 0 {
+		cfg.Layouts = maps.Clone(cfg.Layouts)
+		if cfg.Layouts == nil {
+			cfg.Layouts = make(map[string]ui.LayoutConfig, len(nested.UI.Layouts))
+		}
+		maps.Copy(cfg.Layouts, nested.UI.Layouts)
+	}
+	cfg.Performance = mergePerformanceConfig(cfg.Performance, nested.UI.Performance)
+	cfg.Formatting = mergeFormattingConfig(cfg.Formatting, nested.UI.Formatting)
 	if ui.InfoPopupHasData(nested.UI.Popup.InfoPopup) {
 		cfg.Popup.InfoPopup = mergeInfoPopup(cfg.Popup.InfoPopup, nested.UI.Popup.InfoPopup)
 	}
@@ -377,6 +417,7 @@ func processConfigTemplates(cfg ui.ThemeConfigFile) ui.ThemeConfigFile {
 					"help_header_template": cfg.CLI.HelpHeaderTemplate,
 					"help_description":     cfg.CLI.HelpDescription,
 					"help_usage":           cfg.CLI.HelpUsage,
+					"aliases":              cfg.CLI.Aliases,
 				},
 				"debug": map[string]interface{}{
 					"max_events": cfg.Debug.MaxEvents,
@@ -394,6 +435,10 @@ func processConfigTemplates(cfg ui.ThemeConfigFile) ui.ThemeConfigFile {
 					"allow_filter":       cfg.Features.AllowFilter,
 					"allow_suggestions":  cfg.Features.AllowSuggestions,
 					"allow_intellisense": cfg.Features.AllowIntellisense,
+					"hyperlinks":         cfg.Features.Hyperlinks,
+					"notify":             cfg.Features.Notify,
+					"bell":               cfg.Features.Bell,
+					"visual_bell":        cfg.Features.VisualBell,
 				},
 				"display": map[string]interface{}{
 					"key_col_width": cfg.Display.KeyColWidth,
@@ -476,7 +521,8 @@ func processTemplateString(text string, data map[string]interface{}) string {
 func menuHasData(menu ui.MenuConfigYAML) bool {
 	// Check action-based menu items (new format)
 	actionItems := []ui.MenuItemConfig{
-		menu.Help, menu.Search, menu.Filter, menu.Copy, menu.Expr, menu.Quit, menu.Custom,
+		menu.Help, menu.Search, menu.Filter, menu.Copy, menu.Expr, menu.Quit,
+		menu.Edit, menu.OpenURL, menu.Wrap, menu.SearchSelection, menu.Columns, menu.ColumnFilter, menu.ColumnManager, menu.Annotate, menu.Peek, menu.Detail, menu.Recent, menu.Custom,
 	}
 	for _, it := range actionItems {
 		if it.Label != "" || it.Action != "" || it.Enabled != nil || it.PopupText != "" || ui.InfoPopupHasData(it.Popup) || it.Keys.Function != "" || it.Keys.Vim != "" || it.Keys.Emacs != "" {
@@ -496,6 +542,134 @@ func menuHasData(menu ui.MenuConfigYAML) bool {
 	return false
 }
 
+// mergePerformanceConfig returns base with the settings override sets.
+func mergePerformanceConfig(base, override ui.PerformanceConfig) ui.PerformanceConfig {
+	out := base
+	if override.FilterDebounceMs != nil {
+		out.FilterDebounceMs = override.FilterDebounceMs
+	}
+	if override.SearchResultLimit != nil {
+		out.SearchResultLimit = override.SearchResultLimit
+	}
+	if override.ScrollBufferRows != nil {
+		out.ScrollBufferRows = override.ScrollBufferRows
+	}
+	if override.VirtualScrolling != nil {
+		out.VirtualScrolling = override.VirtualScrolling
+	}
+	if override.ArrayChunkSize != nil {
+		out.ArrayChunkSize = override.ArrayChunkSize
+	}
+	if override.ArrayChunkThreshold != nil {
+		out.ArrayChunkThreshold = override.ArrayChunkThreshold
+	}
+	return out
+}
+
+// formattingHasData reports whether f sets any formatting option.
+func formattingHasData(f ui.FormattingConfig) bool {
+	t := f.Table
+	return f.YAML != (ui.YAMLFormattingConfig{}) || f.Tree != (ui.TreeFormattingConfig{}) || f.Mermaid != (ui.MermaidFormattingConfig{}) ||
+		t.ArrayStyle != nil || t.ColumnarMode != nil || len(t.ColumnOrder) > 0 || len(t.HiddenColumns) > 0 || len(t.Summary) > 0 ||
+		t.WidthPercentile != nil || t.MaxValueLines != nil || t.WrapValues != nil || t.Ellipsis != nil || t.SchemaFile != nil || len(t.Schema) > 0
+}
+
+// mergeFormattingConfig returns base with the settings override sets.
+func mergeFormattingConfig(base, override ui.FormattingConfig) ui.FormattingConfig {
+	out := base
+	if override.YAML.Indent != nil {
+		out.YAML.Indent = override.YAML.Indent
+	}
+	if override.YAML.LiteralBlockStrings != nil {
+		out.YAML.LiteralBlockStrings = override.YAML.LiteralBlockStrings
+	}
+	if override.YAML.ExpandEscapedNewlines != nil {
+		out.YAML.ExpandEscapedNewlines = override.YAML.ExpandEscapedNewlines
+	}
+	if override.YAML.Fidelity != nil {
+		out.YAML.Fidelity = override.YAML.Fidelity
+	}
+	out.Table = mergeTableFormattingConfig(out.Table, override.Table)
+	out.Tree = mergeTreeFormattingConfig(out.Tree, override.Tree)
+	if override.Mermaid.Direction != nil {
+		out.Mermaid.Direction = override.Mermaid.Direction
+	}
+	if override.Mermaid.MaxDepth != nil {
+		out.Mermaid.MaxDepth = override.Mermaid.MaxDepth
+	}
+	if override.Mermaid.MaxStringLength != nil {
+		out.Mermaid.MaxStringLength = override.Mermaid.MaxStringLength
+	}
+	if override.Mermaid.MaxArrayInline != nil {
+		out.Mermaid.MaxArrayInline = override.Mermaid.MaxArrayInline
+	}
+	if override.Mermaid.ExpandArrays != nil {
+		out.Mermaid.ExpandArrays = override.Mermaid.ExpandArrays
+	}
+	if override.Mermaid.NoValues != nil {
+		out.Mermaid.NoValues = override.Mermaid.NoValues
+	}
+	return out
+}
+
+func mergeTableFormattingConfig(base, override ui.TableFormattingConfig) ui.TableFormattingConfig {
+	out := base
+	if override.ArrayStyle != nil {
+		out.ArrayStyle = override.ArrayStyle
+	}
+	if override.ColumnarMode != nil {
+		out.ColumnarMode = override.ColumnarMode
+	}
+	if len(override.ColumnOrder) > 0 {
+		out.ColumnOrder = override.ColumnOrder
+	}
+	if len(override.HiddenColumns) > 0 {
+		out.HiddenColumns = override.HiddenColumns
+	}
+	if len(override.Summary) > 0 {
+		out.Summary = override.Summary
+	}
+	if override.WidthPercentile != nil {
+		out.WidthPercentile = override.WidthPercentile
+	}
+	if override.MaxValueLines != nil {
+		out.MaxValueLines = override.MaxValueLines
+	}
+	if override.WrapValues != nil {
+		out.WrapValues = override.WrapValues
+	}
+	if override.Ellipsis != nil {
+		out.Ellipsis = override.Ellipsis
+	}
+	if override.SchemaFile != nil {
+		out.SchemaFile = override.SchemaFile
+	}
+	if len(override.Schema) > 0 {
+		out.Schema = override.Schema
+	}
+	return out
+}
+
+func mergeTreeFormattingConfig(base, override ui.TreeFormattingConfig) ui.TreeFormattingConfig {
+	out := base
+	if override.MaxDepth != nil {
+		out.MaxDepth = override.MaxDepth
+	}
+	if override.MaxStringLength != nil {
+		out.MaxStringLength = override.MaxStringLength
+	}
+	if override.MaxArrayInline != nil {
+		out.MaxArrayInline = override.MaxArrayInline
+	}
+	if override.ExpandArrays != nil {
+		out.ExpandArrays = override.ExpandArrays
+	}
+	if override.NoValues != nil {
+		out.NoValues = override.NoValues
+	}
+	return out
+}
+
 func mergeInfoPopup(base, override ui.InfoPopupConfig) ui.InfoPopupConfig {
 	out := base
 	if override.Text != "" {
@@ -563,6 +737,17 @@ func mergeMenuConfig(base, override ui.MenuConfigYAML) ui.MenuConfigYAML {
 	apply(override.Copy, &out.Copy)
 	apply(override.Expr, &out.Expr)
 	apply(override.Quit, &out.Quit)
+	apply(override.Edit, &out.Edit)
+	apply(override.OpenURL, &out.OpenURL)
+	apply(override.Wrap, &out.Wrap)
+	apply(override.SearchSelection, &out.SearchSelection)
+	apply(override.Columns, &out.Columns)
+	apply(override.ColumnFilter, &out.ColumnFilter)
+	apply(override.ColumnManager, &out.ColumnManager)
+	apply(override.Annotate, &out.Annotate)
+	apply(override.Peek, &out.Peek)
+	apply(override.Detail, &out.Detail)
+	apply(override.Recent, &out.Recent)
 	apply(override.Custom, &out.Custom)
 	// Legacy F-key based items
 	apply(override.F1, &out.F1)
@@ -667,18 +852,19 @@ type outputConfig struct {
 
 // uiBlock groups UI config for nested output/inputs.
 type uiBlock struct {
-	Theme        ui.ThemeSelectionConfig   `yaml:"theme,omitempty" json:"theme,omitempty"`
-	Features     ui.FeaturesConfig         `yaml:"features,omitempty" json:"features,omitempty"`
-	Intellisense ui.IntellisenseConfig     `yaml:"intellisense,omitempty" json:"intellisense,omitempty"`
-	Help         ui.HelpConfig             `yaml:"help,omitempty" json:"help,omitempty"`
-	Display      ui.DisplayConfig          `yaml:"display,omitempty" json:"display,omitempty"`
-	Behavior     ui.BehaviorConfig         `yaml:"behavior,omitempty" json:"behavior,omitempty"`
-	Performance  ui.PerformanceConfig      `yaml:"performance,omitempty" json:"performance,omitempty"`
-	Search       ui.SearchConfig           `yaml:"search,omitempty" json:"search,omitempty"`
-	Formatting   ui.FormattingConfig       `yaml:"formatting,omitempty" json:"formatting,omitempty"`
-	Popup        ui.PopupConfig            `yaml:"popup,omitempty" json:"popup,omitempty"`
-	Themes       map[string]ui.ThemeConfig `yaml:"themes,omitempty" json:"themes,omitempty"`
-	Menu         ui.MenuConfigYAML         `yaml:"menu,omitempty" json:"menu,omitempty"`
+	Theme        ui.ThemeSelectionConfig    `yaml:"theme,omitempty" json:"theme,omitempty"`
+	Features     ui.FeaturesConfig          `yaml:"features,omitempty" json:"features,omitempty"`
+	Intellisense ui.IntellisenseConfig      `yaml:"intellisense,omitempty" json:"intellisense,omitempty"`
+	Help         ui.HelpConfig              `yaml:"help,omitempty" json:"help,omitempty"`
+	Display      ui.DisplayConfig           `yaml:"display,omitempty" json:"display,omitempty"`
+	Behavior     ui.BehaviorConfig          `yaml:"behavior,omitempty" json:"behavior,omitempty"`
+	Performance  ui.PerformanceConfig       `yaml:"performance,omitempty" json:"performance,omitempty"`
+	Search       ui.SearchConfig            `yaml:"search,omitempty" json:"search,omitempty"`
+	Formatting   ui.FormattingConfig        `yaml:"formatting,omitempty" json:"formatting,omitempty"`
+	Popup        ui.PopupConfig             `yaml:"popup,omitempty" json:"popup,omitempty"`
+	Themes       map[string]ui.ThemeConfig  `yaml:"themes,omitempty" json:"themes,omitempty"`
+	Layouts      map[string]ui.LayoutConfig `yaml:"layouts,omitempty" json:"layouts,omitempty"`
+	Menu         ui.MenuConfigYAML          `yaml:"menu,omitempty" json:"menu,omitempty"`
 	// Legacy fields for backward compatibility
 	Defaults uiDefaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 }
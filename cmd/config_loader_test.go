@@ -59,6 +59,18 @@ ui:
 	require.Equal(t, "Custom help text", strings.TrimSpace(cfg.Menu.F1.Popup.Text))
 }
 
+func TestConfigLoaderMergesPerformance(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("ui:\n  performance:\n    array_chunk_threshold: 20\n"), 0o600))
+
+	cfg, err := loadMergedConfig(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Performance.ArrayChunkThreshold)
+	assert.Equal(t, 20, *cfg.Performance.ArrayChunkThreshold)
+	require.NotNil(t, cfg.Performance.ArrayChunkSize, "settings left out keep their defaults")
+	assert.Equal(t, 500, *cfg.Performance.ArrayChunkSize)
+}
+
 func TestSanitizeConfigClearsDynamicFields(t *testing.T) {
 	cfg, err := loadMergedConfig("")
 	require.NoError(t, err)
@@ -257,3 +269,32 @@ func TestMergeMenuConfig_EmptyOverride(t *testing.T) {
 	assert.Equal(t, "Help", result.Help.Label)
 	assert.Equal(t, "Show help", result.Help.HelpText)
 }
+
+func TestConfigLoaderMergesFormatting(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("ui:\n  formatting:\n    table:\n      ellipsis: \"…\"\n      array_style: bullet\n"), 0o600))
+
+	cfg, err := loadMergedConfig(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Formatting.Table.Ellipsis)
+	assert.Equal(t, "…", *cfg.Formatting.Table.Ellipsis)
+	require.NotNil(t, cfg.Formatting.Table.ArrayStyle)
+	assert.Equal(t, "bullet", *cfg.Formatting.Table.ArrayStyle)
+	require.NotNil(t, cfg.Formatting.YAML.Indent, "settings left out keep their defaults")
+	assert.Equal(t, 2, *cfg.Formatting.YAML.Indent)
+}
+
+func TestConfigLoaderMergesFeaturesAndBehavior(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	configYAML := "ui:\n  features:\n    bell: error\n  behavior:\n    idle_timeout: 30m\n"
+	require.NoError(t, os.WriteFile(cfgPath, []byte(configYAML), 0o600))
+
+	cfg, err := loadMergedConfig(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Features.Bell)
+	assert.Equal(t, "error", *cfg.Features.Bell)
+	require.NotNil(t, cfg.Behavior.IdleTimeout)
+	assert.Equal(t, "30m", *cfg.Behavior.IdleTimeout)
+	require.NotNil(t, cfg.Behavior.IdlePrint, "settings left out keep their defaults")
+	assert.False(t, *cfg.Behavior.IdlePrint)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+// applyDeterministic turns off what --deterministic rules out: the pager and
+// the view layout remembered per input, which depend on the terminal and on
+// earlier runs. The terminal size, colors, --sample seed, key order, and the
+// TUI's timed elements are fixed where they are decided.
+func applyDeterministic() {
+	if !deterministic {
+		return
+	}
+	noPager = true
+	noViewState = true
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func TestCLI_DeterministicSnapshot(t *testing.T) {
+	t.Cleanup(func() {
+		resetRootCmdState()
+		applyFunctionExamples(ui.ThemeConfigFile{}, true)
+	})
+	args := []string{"kvx", "../tests/sample.yaml", "--snapshot", "--deterministic", "--sort", "none"}
+	first := runCLI(t, args)
+	assert.True(t, noPager)
+	assert.True(t, noViewState)
+	lines := strings.Split(strings.TrimRight(first, "\n"), "\n")
+	assert.Len(t, lines, ui.DeterministicHeight)
+	for range 3 {
+		assert.Equal(t, first, runCLI(t, args))
+	}
+	assert.Equal(t, first, runCLI(t, append(args, "--no-color")), "no color unless asked for")
+}
+
+func TestDeterministicDefaults(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	deterministic = true
+
+	w, h := detectTerminalSize()
+	assert.Equal(t, ui.DeterministicWidth, w)
+	assert.Equal(t, ui.DeterministicHeight, h)
+
+	sortOrder = "none"
+	order, err := resolveSortOrder(ui.ThemeConfigFile{})
+	require.NoError(t, err)
+	assert.Equal(t, navigator.SortAscending, order)
+	sortOrder = "descending"
+	order, err = resolveSortOrder(ui.ThemeConfigFile{})
+	require.NoError(t, err)
+	assert.Equal(t, navigator.SortDescending, order)
+
+	assert.Nil(t, startProgress("rendering"))
+}
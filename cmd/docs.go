@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// docsDir is the --dir flag of the docs subcommands.
+var docsDir string
+
+// celFunctionsMarkdownFile is the markdown page listing the CEL functions.
+const celFunctionsMarkdownFile = "kvx_cel_functions.md"
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate man pages and a markdown CLI reference",
+	Long: `Generate documentation from the command definitions built into this binary,
+so packages can ship docs that always match the installed version.
+
+Every visible command gets a page. The kvx page also lists the CEL functions
+available in expressions, with their signatures, descriptions, and examples.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	},
+}
+
+var docsManCmd = &cobra.Command{
+	Use:     "man",
+	Short:   "Generate man pages",
+	Example: "  kvx docs man --dir /usr/share/man/man1",
+	Args:    cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return generateDocs(rootCmd, docsDir, manPageName, renderManPage)
+	},
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:     "markdown",
+	Short:   "Generate a markdown CLI reference",
+	Example: "  kvx docs markdown --dir docs/cli",
+	Args:    cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if err := generateDocs(rootCmd, docsDir, markdownPageName, renderMarkdownPage); err != nil {
+			return err
+		}
+		functions, err := docsFunctionCatalog()
+		if err != nil {
+			return err
+		}
+		return writeDocFile(docsDir, celFunctionsMarkdownFile, renderFunctionsMarkdown(functions))
+	},
+}
+
+// docsFunctionCatalog returns the CEL functions documented by the docs
+// commands. Only the built-in config is used, so the output does not depend
+// on the user generating it.
+func docsFunctionCatalog() ([]functionDoc, error) {
+	cfg, err := loadMergedConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return celFunctionCatalog(cfg)
+}
+
+// generateDocs writes one page for cmd and for every visible subcommand.
+func generateDocs(cmd *cobra.Command, dir string, name func(*cobra.Command) string, render func(*cobra.Command) (string, error)) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, c := range docCommands(cmd) {
+		page, err := render(c)
+		if err != nil {
+			return err
+		}
+		if err := writeDocFile(dir, name(c), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeDocFile(dir, name, content string) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil { //nolint:gosec // docs are meant to be world-readable
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// docCommands returns cmd and its documented descendants, depth first.
+func docCommands(cmd *cobra.Command) []*cobra.Command {
+	out := []*cobra.Command{cmd}
+	for _, c := range docChildren(cmd) {
+		out = append(out, docCommands(c)...)
+	}
+	return out
+}
+
+// docChildren returns the subcommands of cmd that get a page: visible ones,
+// without the help command.
+func docChildren(cmd *cobra.Command) []*cobra.Command {
+	var out []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() && !c.IsAdditionalHelpTopicCommand() {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
+
+func manPageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-") + ".1"
+}
+
+func markdownPageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".md"
+}
+
+// docsDate returns the date printed in generated pages. SOURCE_DATE_EPOCH
+// makes it reproducible for package builds.
+func docsDate() time.Time {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if sec, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC()
+		}
+	}
+	return time.Now().UTC()
+}
+
+// visibleFlags returns the flags of fs that are not hidden, sorted by name.
+func visibleFlags(fs *pflag.FlagSet) []*pflag.Flag {
+	var out []*pflag.Flag
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden {
+			out = append(out, f)
+		}
+	})
+	return out
+}
+
+// docShort returns the short description of cmd without a leading
+// "name - ", which the root command's help includes.
+func docShort(cmd *cobra.Command) string {
+	return strings.TrimPrefix(strings.TrimSpace(cmd.Short), cmd.Name()+" - ")
+}
+
+// docDescription returns the long description of cmd, or its short one.
+func docDescription(cmd *cobra.Command) string {
+	if long := strings.TrimSpace(cmd.Long); long != "" {
+		return long
+	}
+	return docShort(cmd)
+}
+
+// --- man pages ---
+
+func renderManPage(cmd *cobra.Command) (string, error) {
+	var b bytes.Buffer
+	title := strings.ToUpper(strings.ReplaceAll(cmd.CommandPath(), " ", "-"))
+	fmt.Fprintf(&b, ".TH %q \"1\" %q %q \"User Commands\"\n",
+		title, docsDate().Format("Jan 2006"), "kvx "+stringValue(buildVersionData(nil)["Version"]))
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", manEscape(strings.ReplaceAll(cmd.CommandPath(), " ", "-")), manEscape(docShort(cmd)))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", manEscape(cmd.UseLine()))
+
+	b.WriteString(".SH DESCRIPTION\n")
+	manParagraphs(&b, docDescription(cmd))
+
+	manFlags(&b, "OPTIONS", cmd.NonInheritedFlags())
+	manFlags(&b, "OPTIONS INHERITED FROM PARENT COMMANDS", cmd.InheritedFlags())
+
+	if ex := strings.Trim(cmd.Example, "\n"); ex != "" {
+		b.WriteString(".SH EXAMPLES\n.PP\n.nf\n.RS\n")
+		for _, line := range strings.Split(ex, "\n") {
+			b.WriteString(manEscape(strings.TrimPrefix(line, "  ")) + "\n")
+		}
+		b.WriteString(".RE\n.fi\n")
+	}
+
+	if !cmd.HasParent() {
+		functions, err := docsFunctionCatalog()
+		if err != nil {
+			return "", err
+		}
+		manFunctions(&b, functions)
+	}
+
+	var related []*cobra.Command
+	if cmd.HasParent() {
+		related = append(related, cmd.Parent())
+	}
+	related = append(related, docChildren(cmd)...)
+	if len(related) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		refs := make([]string, len(related))
+		for i, c := range related {
+			refs[i] = fmt.Sprintf("\\fB%s\\fP(1)", manEscape(strings.ReplaceAll(c.CommandPath(), " ", "-")))
+		}
+		b.WriteString(strings.Join(refs, ", ") + "\n")
+	}
+	return b.String(), nil
+}
+
+func manFlags(b *bytes.Buffer, section string, fs *pflag.FlagSet) {
+	flags := visibleFlags(fs)
+	if len(flags) == 0 {
+		return
+	}
+	fmt.Fprintf(b, ".SH %s\n", section)
+	for _, f := range flags {
+		b.WriteString(".TP\n")
+		name := "\\fB\\-\\-" + manEscape(f.Name) + "\\fP"
+		if f.Shorthand != "" {
+			name = "\\fB\\-" + manEscape(f.Shorthand) + "\\fP, " + name
+		}
+		if varname, _ := pflag.UnquoteUsage(f); varname != "" {
+			name += "=\\fI" + manEscape(varname) + "\\fP"
+		}
+		b.WriteString(name + "\n")
+		_, usage := pflag.UnquoteUsage(f)
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" && f.DefValue != "0" && !strings.Contains(usage, "default") {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		b.WriteString(manEscape(usage) + "\n")
+	}
+}
+
+func manFunctions(b *bytes.Buffer, functions []functionDoc) {
+	if len(functions) == 0 {
+		return
+	}
+	b.WriteString(".SH CEL FUNCTIONS\n")
+	b.WriteString(".PP\nFunctions available in \\fB\\-e\\fP, \\fB\\-w\\fP and the interactive expression mode. Methods are called on a value, e.g. \\fB_.name.startsWith(\"a\")\\fP.\n")
+	for _, cat := range functionCategories(functions) {
+		fmt.Fprintf(b, ".SS %s\n", manEscape(cat.name))
+		for _, fn := range cat.functions {
+			b.WriteString(".TP\n")
+			fmt.Fprintf(b, "\\fB%s\\fP\n", manEscape(fn.Name))
+			if fn.Description != "" {
+				b.WriteString(manEscape(fn.Description) + "\n")
+			}
+			for _, sig := range fn.Signatures {
+				b.WriteString(".br\n" + manEscape(sig) + "\n")
+			}
+			for _, ex := range fn.Examples {
+				b.WriteString(".br\n\\fIe.g.\\fP " + manEscape(ex) + "\n")
+			}
+		}
+	}
+}
+
+// manParagraphs writes text as roff paragraphs, one per blank-line-separated
+// block, keeping indented lines verbatim.
+func manParagraphs(b *bytes.Buffer, text string) {
+	for _, para := range strings.Split(text, "\n\n") {
+		para = strings.Trim(para, "\n")
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+		if strings.HasPrefix(para, " ") || strings.HasPrefix(para, "\t") {
+			b.WriteString(".PP\n.nf\n")
+			for _, line := range strings.Split(para, "\n") {
+				b.WriteString(manEscape(line) + "\n")
+			}
+			b.WriteString(".fi\n")
+			continue
+		}
+		b.WriteString(".PP\n" + manEscape(strings.Join(strings.Fields(para), " ")) + "\n")
+	}
+}
+
+// manEscape escapes text for roff: backslashes, dashes, and control
+// characters at the start of a line.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// --- markdown ---
+
+func renderMarkdownPage(cmd *cobra.Command) (string, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", cmd.CommandPath(), docShort(cmd))
+	if long := strings.TrimSpace(cmd.Long); long != "" {
+		fmt.Fprintf(&b, "### Synopsis\n\n%s\n\n", long)
+	}
+	fmt.Fprintf(&b, "```\n%s\n```\n\n", cmd.UseLine())
+	if ex := strings.Trim(cmd.Example, "\n"); ex != "" {
+		fmt.Fprintf(&b, "### Examples\n\n```\n%s\n```\n\n", ex)
+	}
+	if fs := cmd.NonInheritedFlags(); fs.HasAvailableFlags() {
+		fmt.Fprintf(&b, "### Options\n\n```\n%s```\n\n", fs.FlagUsages())
+	}
+	if fs := cmd.InheritedFlags(); fs.HasAvailableFlags() {
+		fmt.Fprintf(&b, "### Options inherited from parent commands\n\n```\n%s```\n\n", fs.FlagUsages())
+	}
+
+	b.WriteString("### SEE ALSO\n\n")
+	if cmd.HasParent() {
+		p := cmd.Parent()
+		fmt.Fprintf(&b, "* [%s](%s) - %s\n", p.CommandPath(), markdownPageName(p), docShort(p))
+	} else {
+		fmt.Fprintf(&b, "* [CEL functions](%s) - functions available in expressions\n", celFunctionsMarkdownFile)
+	}
+	for _, c := range docChildren(cmd) {
+		fmt.Fprintf(&b, "* [%s](%s) - %s\n", c.CommandPath(), markdownPageName(c), docShort(c))
+	}
+	return b.String(), nil
+}
+
+func renderFunctionsMarkdown(functions []functionDoc) string {
+	var b strings.Builder
+	b.WriteString("## CEL functions\n\n")
+	b.WriteString("Functions available in `-e`, `-w`, and the interactive expression mode. ")
+	b.WriteString("Methods are called on a value, e.g. `_.name.startsWith(\"a\")`.\n\n")
+	for _, cat := range functionCategories(functions) {
+		fmt.Fprintf(&b, "### %s\n\n", cat.name)
+		for _, fn := range cat.functions {
+			fmt.Fprintf(&b, "#### `%s`\n\n", fn.Name)
+			if fn.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", fn.Description)
+			}
+			for _, sig := range fn.Signatures {
+				fmt.Fprintf(&b, "- `%s`\n", sig)
+			}
+			b.WriteString("\n")
+			if len(fn.Examples) > 0 {
+				b.WriteString("```\n" + strings.Join(fn.Examples, "\n") + "\n```\n\n")
+			}
+		}
+	}
+	b.WriteString("### SEE ALSO\n\n* [kvx](kvx.md) - " + rootCmd.Short + "\n")
+	return b.String()
+}
+
+type functionCategory struct {
+	name      string
+	functions []functionDoc
+}
+
+// functionCategories groups functions by category, in alphabetical order.
+func functionCategories(functions []functionDoc) []functionCategory {
+	index := make(map[string]int)
+	var cats []functionCategory
+	for _, fn := range functions {
+		i, ok := index[fn.Category]
+		if !ok {
+			i = len(cats)
+			index[fn.Category] = i
+			cats = append(cats, functionCategory{name: fn.Category})
+		}
+		cats[i].functions = append(cats[i].functions, fn)
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].name < cats[j].name })
+	return cats
+}
+
+// stringValue returns v if it is a string, or "".
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readDoc(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCLI_DocsMan(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1704067200") // 2024-01-01
+	dir := t.TempDir()
+	runCLI(t, []string{"kvx", "docs", "man", "--dir", dir})
+
+	for _, name := range []string{"kvx.1", "kvx-version.1", "kvx-config.1", "kvx-config-get.1", "kvx-docs-man.1"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "kvx-themes.1"), "hidden commands get no page")
+	assert.NoFileExists(t, filepath.Join(dir, "kvx-help.1"))
+
+	root := readDoc(t, dir, "kvx.1")
+	assert.Contains(t, root, `.TH "KVX" "1" "Jan 2024"`)
+	assert.Contains(t, root, ".SH NAME\nkvx \\- YAML/JSON data explorer\n")
+	assert.Contains(t, root, `\fB\-e\fP, \fB\-\-expression\fP=\fIstring\fP`)
+	assert.NotContains(t, root, `\-\-snapshot\-width`, "hidden flags are left out")
+	assert.Contains(t, root, ".SH CEL FUNCTIONS")
+	assert.Contains(t, root, "\\fBfilter\\fP\n")
+	assert.Contains(t, root, `\fBkvx\-version\fP(1)`)
+
+	sub := readDoc(t, dir, "kvx-config-get.1")
+	assert.Contains(t, sub, ".SH OPTIONS INHERITED FROM PARENT COMMANDS")
+	assert.Contains(t, sub, `\fBkvx\-config\fP(1)`)
+	assert.NotContains(t, sub, "CEL FUNCTIONS")
+}
+
+func TestCLI_DocsMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	runCLI(t, []string{"kvx", "docs", "markdown", "--dir", dir})
+
+	root := readDoc(t, dir, "kvx.md")
+	assert.Contains(t, root, "## kvx\n\nYAML/JSON data explorer\n")
+	assert.Contains(t, root, "* [CEL functions](kvx_cel_functions.md)")
+	assert.Contains(t, root, "* [kvx version](kvx_version.md) - Print kvx version")
+
+	version := readDoc(t, dir, "kvx_version.md")
+	assert.Contains(t, version, "```\nkvx version [flags]\n```")
+	assert.Contains(t, version, "-o, --output string")
+	assert.Contains(t, version, "* [kvx](kvx.md)")
+
+	functions := readDoc(t, dir, celFunctionsMarkdownFile)
+	assert.Contains(t, functions, "### string\n")
+	assert.Contains(t, functions, "#### `contains`\n")
+	assert.Contains(t, functions, "- `string.contains(string) -> bool`\n")
+}
+
+func TestManEscape(t *testing.T) {
+	assert.Equal(t, `\-\-dir`, manEscape("--dir"))
+	assert.Equal(t, `C:\eUsers`, manEscape(`C:\Users`))
+	assert.Equal(t, `\&.hidden`, manEscape(".hidden"))
+	assert.Equal(t, `\&'quoted'`, manEscape("'quoted'"))
+}
+
+func TestCelFunctionCatalog(t *testing.T) {
+	cfg, err := loadMergedConfig("")
+	require.NoError(t, err)
+	functions, err := celFunctionCatalog(cfg)
+	require.NoError(t, err)
+	require.NotEmpty(t, functions)
+
+	names := make([]string, len(functions))
+	byName := make(map[string]functionDoc, len(functions))
+	for i, fn := range functions {
+		names[i] = fn.Name
+		byName[fn.Name] = fn
+		assert.NotEmpty(t, fn.Signatures, fn.Name)
+		assert.NotEmpty(t, fn.Category, fn.Name)
+	}
+	assert.True(t, sort.StringsAreSorted(names))
+	assert.Len(t, byName, len(functions), "overloads are merged into one entry")
+
+	contains := byName["contains"]
+	assert.True(t, contains.Method)
+	assert.Contains(t, contains.Signatures, "string.contains(string) -> bool")
+
+	filter := byName["filter"]
+	assert.Equal(t, []string{".filter(...)"}, filter.Signatures, "macros get a placeholder signature")
+	assert.NotEmpty(t, filter.Description, "descriptions come from the help config")
+	assert.NotEmpty(t, filter.Examples)
+}
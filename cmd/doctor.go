@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/termcolor"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// doctorOutput is the -o flag of the doctor command.
+var doctorOutput string
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config, theme, key bindings, schema, clipboard, and terminal",
+	Long: `Check the setup kvx runs with and print a pass/fail report, for
+troubleshooting and bug reports:
+
+  config        the config file parses
+  theme         the default theme (or --theme) exists
+  key bindings  no key runs two actions, and the key mode is valid
+  schema        the --schema or schema_file JSON Schema reads and parses
+  clipboard     a clipboard command is installed for copying
+  terminal      stdout is a terminal, with its size and colors
+
+kvx doctor exits 1 when a check fails; warnings do not fail it.`,
+	Example: `  kvx doctor
+  kvx doctor --config-file ./kvx.yaml --schema users.schema.json
+  kvx doctor -o json`,
+	Args: cobra.NoArgs,
+	// The report already says what failed.
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runDoctor(os.Stdout, doctorOutput)
+	},
+}
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	Detail string `json:"detail" yaml:"detail"`
+}
+
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+func runDoctor(w io.Writer, format string) error {
+	switch format {
+	case "", "text", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid doctor output format %q (expected text, json, or yaml)", format)
+	}
+	checks := doctorChecks()
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal doctor report: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(checks)
+		if err != nil {
+			return fmt.Errorf("failed to marshal doctor report: %w", err)
+		}
+		fmt.Fprint(w, string(data))
+	default:
+		for _, c := range checks {
+			lines := strings.Split(c.Detail, "\n")
+			fmt.Fprintf(w, "%-4s  %-12s  %s\n", c.Status, c.Name, lines[0])
+			for _, line := range lines[1:] {
+				fmt.Fprintf(w, "%20s%s\n", "", line)
+			}
+		}
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// doctorChecks runs the checks of the doctor report. A config that fails to
+// load is replaced by the defaults, so the other checks still run.
+func doctorChecks() []doctorCheck {
+	cfg, config := checkDoctorConfig(resolveConfigPath(configFile))
+	return []doctorCheck{
+		config,
+		checkDoctorTheme(cfg),
+		checkDoctorKeys(cfg),
+		checkDoctorSchema(cfg),
+		checkDoctorClipboard(),
+		checkDoctorTerminal(cfg),
+	}
+}
+
+func checkDoctorConfig(path string) (ui.ThemeConfigFile, doctorCheck) {
+	check := doctorCheck{Name: "config", Status: doctorOK, Detail: path}
+	if path == "" {
+		check.Detail = "no config file, using the defaults (looked for " + strings.Join(ui.ConfigFileCandidates(), ", ") + ")"
+	}
+	cfg, err := loadMergedConfig(path)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("%s: %v", path, err)
+		cfg, _ = loadMergedConfig("")
+	}
+	return cfg, check
+}
+
+func checkDoctorTheme(cfg ui.ThemeConfigFile) doctorCheck {
+	check := doctorCheck{Name: "theme", Status: doctorOK}
+	if err := ui.InitializeThemes(&cfg); err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	themeFlagSet := strings.TrimSpace(themeName) != ""
+	if err := applyThemeFromConfig(cfg, themeName, themeFlagSet); err != nil {
+		check.Status = doctorFail
+		var themeErr themeSelectionError
+		if errors.As(err, &themeErr) {
+			check.Detail = fmt.Sprintf("unknown theme %q (available: %s)", themeErr.Selected, strings.Join(themeErr.Available, ", "))
+		} else {
+			check.Detail = err.Error()
+		}
+		return check
+	}
+	check.Detail = defaultThemeName(cfg)
+	if themeFlagSet {
+		check.Detail = strings.TrimSpace(themeName)
+	}
+	return check
+}
+
+func checkDoctorKeys(cfg ui.ThemeConfigFile) doctorCheck {
+	check := doctorCheck{Name: "key bindings", Status: doctorOK}
+	var problems []string
+	if cfg.Features.KeyMode != nil && !ui.IsValidKeyMode(*cfg.Features.KeyMode) {
+		problems = append(problems, fmt.Sprintf("invalid key_mode %q (expected vim, emacs, or function)", *cfg.Features.KeyMode))
+	}
+	if env := os.Getenv("KVX_KEY_MODE"); env != "" && !ui.IsValidKeyMode(env) {
+		problems = append(problems, fmt.Sprintf("invalid KVX_KEY_MODE %q (expected vim, emacs, or function)", env))
+	}
+	for _, c := range ui.KeyConflicts(ui.MenuFromConfig(cfg.Menu, cfg.Features.AllowEditInput)) {
+		problems = append(problems, fmt.Sprintf("%s mode: %q is bound to %s", c.Mode, c.Key, strings.Join(c.Actions, " and ")))
+	}
+	if len(problems) > 0 {
+		check.Status, check.Detail = doctorFail, strings.Join(problems, "\n")
+		return check
+	}
+	check.Detail = "no conflicts, " + string(effectiveKeyMode(cfg)) + " mode"
+	return check
+}
+
+func checkDoctorSchema(cfg ui.ThemeConfigFile) doctorCheck {
+	check := doctorCheck{Name: "schema", Status: doctorOK}
+	schema, err := loadCheckSchema(cfg)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, err.Error()
+	case schema == nil:
+		check.Detail = "none configured"
+	case schemaFile != "":
+		check.Detail = schemaFile
+	case cfg.Formatting.Table.SchemaFile != nil && *cfg.Formatting.Table.SchemaFile != "":
+		check.Detail = *cfg.Formatting.Table.SchemaFile
+	default:
+		check.Detail = "inline schema of the config"
+	}
+	return check
+}
+
+func checkDoctorClipboard() doctorCheck {
+	if cmd := ui.ClipboardCommand(); cmd != "" {
+		return doctorCheck{Name: "clipboard", Status: doctorOK, Detail: cmd}
+	}
+	detail := "no clipboard command found, so copying fails"
+	if runtime.GOOS == "linux" {
+		detail += " (install xclip, xsel, or wl-clipboard)"
+	}
+	return doctorCheck{Name: "clipboard", Status: doctorWarn, Detail: detail}
+}
+
+func checkDoctorTerminal(cfg ui.ThemeConfigFile) doctorCheck {
+	check := doctorCheck{Name: "terminal", Status: doctorOK}
+	termEnv := os.Getenv("TERM")
+	if termEnv == "" {
+		termEnv = "TERM unset"
+	}
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("stdout is not a terminal (%s); run kvx doctor where the TUI runs", termEnv)
+		return check
+	}
+	mode, err := termcolor.ParseMode(colorMode)
+	if err != nil {
+		mode = termcolor.ModeAuto
+	}
+	profile := termcolor.Detect(mode, true, os.Environ())
+	hyperlinks := "off"
+	if hyperlinksEnabled(cfg, true) {
+		hyperlinks = "on"
+	}
+	check.Detail = fmt.Sprintf("%s, %s colors, hyperlinks %s", termEnv, profile, hyperlinks)
+	if w, h, err := term.GetSize(fd); err == nil {
+		check.Detail = fmt.Sprintf("%s, %dx%d, %s colors, hyperlinks %s", termEnv, w, h, profile, hyperlinks)
+	}
+	if termEnv == "dumb" {
+		check.Status = doctorWarn
+		check.Detail += "; TERM=dumb cannot draw the TUI"
+	}
+	return check
+}
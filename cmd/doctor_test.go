@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func resetDoctorFlags(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KVX_KEY_MODE", "")
+	configFile, themeName, schemaFile = "", "", ""
+	// Loading a menu rebinds the keys of its actions.
+	vim, emacs := maps.Clone(ui.VimKeyBindings), maps.Clone(ui.EmacsKeyBindings)
+	t.Cleanup(func() {
+		configFile, themeName, schemaFile = "", "", ""
+		ui.VimKeyBindings, ui.EmacsKeyBindings = vim, emacs
+		ui.SetMenuConfig(ui.DefaultMenuConfig())
+	})
+}
+
+func TestRunDoctorDefaults(t *testing.T) {
+	resetDoctorFlags(t)
+	var out bytes.Buffer
+	require.NoError(t, runDoctor(&out, "text"))
+	assert.Contains(t, out.String(), "ok    config        no config file, using the defaults")
+	assert.Contains(t, out.String(), "ok    theme         midnight\n")
+	assert.Contains(t, out.String(), "ok    key bindings  no conflicts, vim mode\n")
+	assert.Contains(t, out.String(), "ok    schema        none configured\n")
+}
+
+func TestRunDoctorFailures(t *testing.T) {
+	resetDoctorFlags(t)
+	configFile = writeTempFile(t, "config.yaml", `ui:
+  theme:
+    default: nope
+  features:
+    key_mode: vi
+  menu:
+    peek:
+      keys:
+        vim: j
+`)
+	schemaFile = filepath.Join(t.TempDir(), "missing.json")
+
+	var out bytes.Buffer
+	require.EqualError(t, runDoctor(&out, "text"), "3 of 6 checks failed")
+	assert.Contains(t, out.String(), "ok    config        "+configFile+"\n")
+	assert.Contains(t, out.String(), `fail  theme         unknown theme "nope" (available: cool, dark, midnight, warm)`)
+	assert.Contains(t, out.String(), `fail  key bindings  invalid key_mode "vi" (expected vim, emacs, or function)
+                    vim mode: "j" is bound to down and peek
+`)
+	assert.Contains(t, out.String(), "fail  schema        cannot read schema file "+schemaFile)
+
+	out.Reset()
+	require.Error(t, runDoctor(&out, "json"))
+	var checks []doctorCheck
+	require.NoError(t, json.Unmarshal(out.Bytes(), &checks))
+	require.Len(t, checks, 6)
+	assert.Equal(t, doctorCheck{Name: "config", Status: doctorOK, Detail: configFile}, checks[0])
+	assert.Equal(t, doctorFail, checks[1].Status)
+}
+
+func TestRunDoctorBrokenConfig(t *testing.T) {
+	resetDoctorFlags(t)
+	configFile = writeTempFile(t, "config.yaml", "ui:\n  theme: [\n")
+	var out bytes.Buffer
+	require.EqualError(t, runDoctor(&out, "text"), "1 of 6 checks failed")
+	assert.Contains(t, out.String(), "fail  config        "+configFile+": yaml:")
+	assert.Contains(t, out.String(), "ok    theme         midnight\n", "the other checks use the defaults")
+}
+
+func TestRunDoctorInvalidFormat(t *testing.T) {
+	assert.EqualError(t, runDoctor(&bytes.Buffer{}, "xml"), `invalid doctor output format "xml" (expected text, json, or yaml)`)
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/pkg/core"
+)
+
+var (
+	dupesBy         []string
+	dupesExpression string
+	dupesUnique     bool
+	dupesOutput     string
+)
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes FILE",
+	Short: "Report duplicate records in an array",
+	Long: `Find the records of an array that repeat the same values for the --by fields,
+or repeat whole when --by is not given, and print each group of duplicates
+with its count and the indexes of its records. "-" reads the document from
+stdin, and -e selects the array inside it.
+
+--by takes field names or paths into the records, such as name,email or
+owner.email. Records missing a field are never duplicates, and a field that
+no record has is an error.
+
+With --unique, the array is printed without its duplicates instead, keeping
+the first record of each group, in the format of the input (-o to choose
+another).`,
+	Example: `  kvx dupes users.json --by name,email
+  kvx dupes deploy.yaml -e '_.spec.containers' --by image -o json
+  kvx dupes users.json --by email --unique > users-unique.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runDupes(os.Stdout, args[0])
+	},
+}
+
+// dupeGroup is one set of records with the same key.
+type dupeGroup struct {
+	Key     map[string]interface{} `json:"key" yaml:"key"`
+	Count   int                    `json:"count" yaml:"count"`
+	Indexes []int                  `json:"indexes" yaml:"indexes"`
+}
+
+func runDupes(w io.Writer, input string) error {
+	doc, err := loadDocumentArg(input)
+	if err != nil {
+		return err
+	}
+	root := doc.Root
+	if strings.TrimSpace(dupesExpression) != "" {
+		engine, err := core.New()
+		if err != nil {
+			return fmt.Errorf("failed to init evaluator: %w", err)
+		}
+		if root, err = engine.Evaluate(dupesExpression, root); err != nil {
+			return fmt.Errorf("expression error: %w", err)
+		}
+	}
+	records, ok := root.([]interface{})
+	if !ok {
+		return fmt.Errorf("dupes needs an array, got %s; select one with -e", cliNodeTypeLabel(root))
+	}
+	fields := make([][]string, len(dupesBy))
+	for i, f := range dupesBy {
+		steps, ok := flagPathSteps(f)
+		if !ok || len(steps) == 0 {
+			return fmt.Errorf("invalid --by field %q", f)
+		}
+		fields[i] = steps
+	}
+
+	groups, err := findDupes(records, dupesBy, fields)
+	if err != nil {
+		return err
+	}
+	if dupesUnique {
+		return writeApplyResult(w, uniqueRecords(records, groups), applyOutputFormat(dupesOutput, doc.Format))
+	}
+	switch dupesOutput {
+	case "", "table":
+		if len(groups) == 0 {
+			_, err := fmt.Fprintln(w, "No duplicates")
+			return err
+		}
+		profile, err := resolveColorProfile()
+		if err != nil {
+			return err
+		}
+		colorProfile = profile
+		out := w
+		if w == os.Stdout {
+			out = colorStdout()
+		}
+		_, err = fmt.Fprint(out, renderDupesTable(groups, dupesBy))
+		return err
+	case "json":
+		if groups == nil {
+			groups = []dupeGroup{}
+		}
+		data, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal duplicates: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "yaml":
+		if groups == nil {
+			groups = []dupeGroup{}
+		}
+		data, err := yaml.Marshal(groups)
+		if err != nil {
+			return fmt.Errorf("failed to marshal duplicates: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("invalid dupes output format %q (expected table, json, or yaml)", dupesOutput)
+	}
+}
+
+// findDupes groups the records that share the values at fields, named by
+// names, in the order their first record appears. Without fields, records
+// are compared whole and the key holds the record as "record". Records
+// missing one of the fields are left out, and a field that no record has
+// is an error.
+func findDupes(records []interface{}, names []string, fields [][]string) ([]dupeGroup, error) {
+	var order []string
+	byKey := map[string]*dupeGroup{}
+	seen := make([]bool, len(fields))
+	for i, rec := range records {
+		key := map[string]interface{}{}
+		if len(fields) == 0 {
+			key["record"] = rec
+		}
+		complete := true
+		for j, steps := range fields {
+			v, ok := valueAtSteps(rec, steps)
+			seen[j] = seen[j] || ok
+			complete = complete && ok
+			key[names[j]] = v
+		}
+		if !complete {
+			continue
+		}
+		id := dupeKeyID(key)
+		g, ok := byKey[id]
+		if !ok {
+			g = &dupeGroup{Key: key}
+			byKey[id] = g
+			order = append(order, id)
+		}
+		g.Count++
+		g.Indexes = append(g.Indexes, i)
+	}
+	if len(records) > 0 {
+		for j, ok := range seen {
+			if !ok {
+				return nil, fmt.Errorf("--by field %q is not in any record", names[j])
+			}
+		}
+	}
+	var groups []dupeGroup
+	for _, id := range order {
+		if g := byKey[id]; g.Count > 1 {
+			groups = append(groups, *g)
+		}
+	}
+	return groups, nil
+}
+
+// dupeKeyID returns a string equal for equal keys. encoding/json writes map
+// keys sorted, so equal records give equal text whatever their key order.
+func dupeKeyID(key map[string]interface{}) string {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Sprintf("%#v", key)
+	}
+	return string(data)
+}
+
+// valueAtSteps returns the value at steps inside v and whether it exists.
+func valueAtSteps(v interface{}, steps []string) (interface{}, bool) {
+	for _, step := range steps {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, ok := node[step]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(step)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// uniqueRecords returns records without the later records of each group.
+func uniqueRecords(records []interface{}, groups []dupeGroup) []interface{} {
+	drop := map[int]bool{}
+	for _, g := range groups {
+		for _, i := range g.Indexes[1:] {
+			drop[i] = true
+		}
+	}
+	out := make([]interface{}, 0, len(records)-len(drop))
+	for i, rec := range records {
+		if !drop[i] {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// renderDupesTable renders one row per group: the key fields, the count,
+// and the indexes of its records.
+func renderDupesTable(groups []dupeGroup, names []string) string {
+	columns := names
+	if len(columns) == 0 {
+		columns = []string{"record"}
+	}
+	rows := make([]interface{}, 0, len(groups))
+	for _, g := range groups {
+		row := map[string]interface{}{}
+		for _, c := range columns {
+			row[c] = g.Key[c]
+		}
+		indexes := make([]string, len(g.Indexes))
+		for i, idx := range g.Indexes {
+			indexes[i] = fmt.Sprint(idx)
+		}
+		row["count"] = g.Count
+		row["indexes"] = strings.Join(indexes, ", ")
+		rows = append(rows, row)
+	}
+	opts := formatter.TableFormatOptions{
+		ArrayStyle:    "none",
+		ColumnarMode:  "always",
+		ColumnOrder:   append(append([]string(nil), columns...), "count", "indexes"),
+		TypeInference: true,
+	}
+	return renderColumnarBorderedTable(rows, 0, "kvx", "dupes", opts) + "\n"
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetDupesFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		dupesBy = nil
+		dupesExpression, dupesOutput = "", ""
+		dupesUnique = false
+	})
+}
+
+const dupesUsers = `[
+	{"name": "ann", "email": "a@x", "n": 1},
+	{"name": "bob", "email": "b@x", "n": 2},
+	{"name": "ann", "email": "a@x", "n": 3},
+	{"name": "ann", "email": "other", "n": 4},
+	{"name": "bob", "email": "b@x", "n": 5}
+]`
+
+func TestCLI_DupesTable(t *testing.T) {
+	resetDupesFlags(t)
+	t.Setenv("NO_COLOR", "1")
+	path := writeTempFile(t, "users.json", dupesUsers)
+
+	out := runCLI(t, []string{"kvx", "dupes", path, "--by", "name,email"})
+	assert.Contains(t, out, "name  email  count  indexes")
+	assert.Contains(t, out, "ann   a@x        2  0, 2")
+	assert.Contains(t, out, "bob   b@x        2  1, 4")
+	assert.NotContains(t, out, "other")
+}
+
+func TestRunDupes_JSON(t *testing.T) {
+	resetDupesFlags(t)
+	path := writeTempFile(t, "doc.yaml", "users:\n  - {name: ann, team: {id: 1}}\n  - {name: bob, team: {id: 2}}\n  - {name: cy, team: {id: 1}}\n")
+	dupesBy = []string{"team.id"}
+	dupesExpression = "_.users"
+	dupesOutput = "json"
+
+	var buf bytes.Buffer
+	require.NoError(t, runDupes(&buf, path))
+	assert.JSONEq(t, `[{"key": {"team.id": 1}, "count": 2, "indexes": [0, 2]}]`, buf.String())
+}
+
+func TestRunDupes_Unique(t *testing.T) {
+	resetDupesFlags(t)
+	path := writeTempFile(t, "users.json", dupesUsers)
+	dupesBy = []string{"email"}
+	dupesUnique = true
+
+	var buf bytes.Buffer
+	require.NoError(t, runDupes(&buf, path))
+	assert.JSONEq(t, `[
+		{"name": "ann", "email": "a@x", "n": 1},
+		{"name": "bob", "email": "b@x", "n": 2},
+		{"name": "ann", "email": "other", "n": 4}
+	]`, buf.String(), "the first record of each group is kept, in the input format")
+}
+
+func TestRunDupes_WholeRecords(t *testing.T) {
+	resetDupesFlags(t)
+	path := writeTempFile(t, "list.json", `[{"a": 1, "b": 2}, {"b": 2, "a": 1}, {"a": 2}]`)
+
+	var buf bytes.Buffer
+	require.NoError(t, runDupes(&buf, path))
+	assert.Contains(t, buf.String(), "0, 1")
+
+	path = writeTempFile(t, "none.json", `[1, 2, 3]`)
+	buf.Reset()
+	require.NoError(t, runDupes(&buf, path))
+	assert.Equal(t, "No duplicates\n", buf.String())
+}
+
+func TestRunDupes_MissingFields(t *testing.T) {
+	resetDupesFlags(t)
+	path := writeTempFile(t, "users.json", `[
+		{"name": "ann", "email": "a@x"},
+		{"name": "bob"},
+		{"name": "cy"},
+		{"name": "dee", "email": "a@x"},
+		{"name": "eve", "email": null},
+		{"name": "fay", "email": null}
+	]`)
+	dupesBy = []string{"email"}
+	dupesOutput = "json"
+
+	var buf bytes.Buffer
+	require.NoError(t, runDupes(&buf, path))
+	assert.JSONEq(t, `[
+		{"key": {"email": "a@x"}, "count": 2, "indexes": [0, 3]},
+		{"key": {"email": null}, "count": 2, "indexes": [4, 5]}
+	]`, buf.String(), "records missing the field are not duplicates of each other")
+
+	dupesBy = []string{"email", "phone"}
+	assert.ErrorContains(t, runDupes(&bytes.Buffer{}, path), `--by field "phone" is not in any record`)
+}
+
+func TestRunDupes_Errors(t *testing.T) {
+	resetDupesFlags(t)
+	path := writeTempFile(t, "obj.json", `{"a": [1]}`)
+	assert.ErrorContains(t, runDupes(&bytes.Buffer{}, path), "needs an array, got map")
+
+	dupesExpression = "_.a"
+	dupesBy = []string{"x..y"}
+	assert.ErrorContains(t, runDupes(&bytes.Buffer{}, path), `invalid --by field "x..y"`)
+
+	dupesBy = nil
+	dupesOutput = "csv"
+	assert.ErrorContains(t, runDupes(&bytes.Buffer{}, path), "invalid dupes output format")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	ui "github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/pkg/intellisense"
+)
+
+// functionDoc describes one CEL function with all of its overloads, as listed
+// in the generated documentation.
+type functionDoc struct {
+	Name        string   `json:"name" yaml:"name"`
+	Category    string   `json:"category" yaml:"category"`
+	Method      bool     `json:"method" yaml:"method"`
+	Signatures  []string `json:"signatures" yaml:"signatures"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// celFunctionCatalog returns the CEL functions known to the intellisense
+// provider, one entry per name with its overloads merged, sorted by name.
+// Descriptions and examples come from the help section of cfg, the same text
+// the TUI shows while typing an expression.
+func celFunctionCatalog(cfg ui.ThemeConfigFile) ([]functionDoc, error) {
+	provider, err := intellisense.NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	examples := cfg.Help.CEL.FunctionExamples
+	if len(examples) == 0 {
+		examples = cfg.Help.FunctionExamples
+	}
+
+	byName := make(map[string]*functionDoc)
+	var names []string
+	for _, fn := range provider.DiscoverFunctions() {
+		doc, ok := byName[fn.Name]
+		if !ok {
+			doc = &functionDoc{Name: fn.Name, Category: fn.Category}
+			byName[fn.Name] = doc
+			names = append(names, fn.Name)
+		}
+		doc.Method = doc.Method || fn.IsMethod
+		if doc.Category == "" || doc.Category == "general" {
+			doc.Category = fn.Category
+		}
+		// The provider describes overloads by their signature, e.g.
+		// "string.contains(string) -> bool"; other text is a description.
+		switch desc := strings.TrimSpace(fn.Description); {
+		case strings.Contains(desc, "->"):
+			doc.Signatures = appendUnique(doc.Signatures, desc)
+		case desc != "" && desc != "CEL function" && doc.Description == "":
+			doc.Description = desc
+		}
+		for _, ex := range fn.Examples {
+			doc.Examples = appendUnique(doc.Examples, ex)
+		}
+	}
+
+	sort.Strings(names)
+	out := make([]functionDoc, 0, len(names))
+	for _, name := range names {
+		doc := byName[name]
+		if ex, ok := examples[name]; ok {
+			if strings.TrimSpace(ex.Description) != "" {
+				doc.Description = strings.TrimSpace(ex.Description)
+			}
+			for _, e := range ex.Examples {
+				doc.Examples = appendUnique(doc.Examples, e)
+			}
+		}
+		if len(doc.Signatures) == 0 {
+			doc.Signatures = []string{placeholderSignature(doc)}
+		}
+		if doc.Category == "" {
+			doc.Category = "general"
+		}
+		out = append(out, *doc)
+	}
+	return out, nil
+}
+
+// placeholderSignature returns the signature shown for functions the
+// provider does not describe, such as the list macros.
+func placeholderSignature(doc *functionDoc) string {
+	if doc.Method {
+		return "." + doc.Name + "(...)"
+	}
+	return doc.Name + "(...)"
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
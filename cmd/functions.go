@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+)
+
+var (
+	functionsOutput string
+	functionsType   string
+)
+
+// receiverTypeAliases maps the short type names used on the command line to
+// the names the CEL runtime reports in signatures.
+var receiverTypeAliases = map[string]string{
+	"timestamp": "google.protobuf.Timestamp",
+	"duration":  "google.protobuf.Duration",
+}
+
+var functionsCmd = &cobra.Command{
+	Use:   "functions",
+	Short: "List the available CEL functions",
+	Long: `List the CEL functions available in -e, -w, and the interactive expression
+mode, with their signatures, descriptions, and examples. This is the same data
+the TUI uses for completion.
+
+Use --type to show only the methods callable on a receiver type, e.g.
+--type string or --type list; --type global lists the functions that are not
+called on a value.`,
+	Example: `  kvx functions
+  kvx functions --type string
+  kvx functions -o json | jq '.[].name'`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runFunctions(functionsOutput, functionsType)
+	},
+}
+
+func runFunctions(format, receiver string) error {
+	cfg, err := loadMergedConfig(resolveConfigPath(configFile))
+	if err != nil {
+		return err
+	}
+	functions, err := celFunctionCatalog(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load CEL functions: %w", err)
+	}
+	if strings.TrimSpace(receiver) != "" {
+		functions = filterFunctionsByReceiver(functions, receiver)
+		if len(functions) == 0 {
+			return fmt.Errorf("no CEL functions found for type %q", receiver)
+		}
+	}
+
+	switch format {
+	case "", "table":
+		profile, err := resolveColorProfile()
+		if err != nil {
+			return err
+		}
+		colorProfile = profile
+		_, err = fmt.Fprint(colorStdout(), renderFunctionsTable(functions))
+		return err
+	case "json":
+		data, err := json.MarshalIndent(functions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal functions: %w", err)
+		}
+		fmt.Println(string(data)) //nolint:forbidigo
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(functions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal functions: %w", err)
+		}
+		fmt.Print(string(data)) //nolint:forbidigo
+		return nil
+	default:
+		return fmt.Errorf("invalid functions output format %q (expected table, json, or yaml)", format)
+	}
+}
+
+// receiverTypes returns the types a function can be called on as a method,
+// read from its signatures ("string.contains(string) -> bool" gives
+// "string"). Macros have no typed signature and fall back to their category.
+func receiverTypes(fn functionDoc) []string {
+	var types []string
+	for _, sig := range fn.Signatures {
+		if t := signatureReceiver(fn, sig); t != "" {
+			types = appendUnique(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// signatureReceiver returns the receiver type of one signature of fn, or ""
+// for a global overload.
+func signatureReceiver(fn functionDoc, sig string) string {
+	switch i := strings.Index(sig, "."+fn.Name+"("); {
+	case i > 0:
+		return sig[:i]
+	case i == 0:
+		return fn.Category
+	default:
+		return ""
+	}
+}
+
+// filterFunctionsByReceiver keeps the functions callable on the given type,
+// trimmed to the overloads for that type. The receiver "global" keeps the
+// overloads called without a value, e.g. size(x) or math.abs(x).
+func filterFunctionsByReceiver(functions []functionDoc, receiver string) []functionDoc {
+	receiver = strings.ToLower(strings.TrimSpace(receiver))
+	if alias, ok := receiverTypeAliases[receiver]; ok {
+		receiver = strings.ToLower(alias)
+	}
+	if receiver == "global" {
+		receiver = ""
+	}
+	var out []functionDoc
+	for _, fn := range functions {
+		var sigs []string
+		for _, sig := range fn.Signatures {
+			if strings.ToLower(signatureReceiver(fn, sig)) == receiver {
+				sigs = append(sigs, sig)
+			}
+		}
+		if len(sigs) > 0 {
+			fn.Signatures = sigs
+			out = append(out, fn)
+		}
+	}
+	return out
+}
+
+// renderFunctionsTable renders one row per overload, with the description
+// on the first row of each function.
+func renderFunctionsTable(functions []functionDoc) string {
+	var rows []interface{}
+	for _, fn := range functions {
+		for i, sig := range fn.Signatures {
+			receiver := signatureReceiver(fn, sig)
+			if receiver == "" {
+				receiver = "-"
+			}
+			desc := ""
+			if i == 0 {
+				desc = fn.Description
+			}
+			rows = append(rows, map[string]interface{}{
+				"name":        fn.Name,
+				"receiver":    receiver,
+				"signature":   sig,
+				"description": desc,
+			})
+		}
+	}
+	opts := formatter.TableFormatOptions{
+		ArrayStyle:   "none",
+		ColumnarMode: "always",
+		ColumnOrder:  []string{"name", "receiver", "signature", "description"},
+		ColumnHints: map[string]formatter.ColumnHint{
+			"description": {Flex: true},
+		},
+	}
+	return renderColumnarBorderedTable(rows, 0, "kvx", "functions", opts) + "\n"
+}
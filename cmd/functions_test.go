@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func resetFunctionsFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		functionsOutput = "table"
+		functionsType = ""
+	})
+}
+
+func TestCLI_FunctionsJSON(t *testing.T) {
+	resetFunctionsFlags(t)
+
+	out := runCLI(t, []string{"kvx", "functions", "-o", "json"})
+	var functions []functionDoc
+	require.NoError(t, json.Unmarshal([]byte(out), &functions), out)
+	require.NotEmpty(t, functions)
+
+	byName := make(map[string]functionDoc, len(functions))
+	for _, fn := range functions {
+		byName[fn.Name] = fn
+	}
+	assert.Contains(t, byName["startsWith"].Signatures, "string.startsWith(string) -> bool")
+	assert.NotEmpty(t, byName["filter"].Examples)
+}
+
+func TestCLI_FunctionsTypeFilter(t *testing.T) {
+	resetFunctionsFlags(t)
+
+	out := runCLI(t, []string{"kvx", "functions", "--type", "string", "-o", "yaml"})
+	var functions []functionDoc
+	require.NoError(t, yaml.Unmarshal([]byte(out), &functions), out)
+	require.NotEmpty(t, functions)
+	for _, fn := range functions {
+		assert.Equal(t, []string{"string"}, receiverTypes(fn), fn.Name)
+	}
+}
+
+func TestCLI_FunctionsTable(t *testing.T) {
+	resetFunctionsFlags(t)
+	origPiped := stdoutIsPiped
+	t.Cleanup(func() { stdoutIsPiped = origPiped })
+	stdoutIsPiped = func() bool { return true }
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	out := runCLI(t, []string{"kvx", "functions", "-t", "timestamp"})
+	assert.NotContains(t, out, "\x1b[", "piped output is not colored")
+	assert.Contains(t, out, "name")
+	assert.Contains(t, out, "signature")
+	assert.Contains(t, out, "google.protobuf.Timestamp.getFullYear() -> int")
+	assert.NotContains(t, out, "startsWith")
+}
+
+func TestRunFunctions_Errors(t *testing.T) {
+	require.EqualError(t, runFunctions("csv", ""), `invalid functions output format "csv" (expected table, json, or yaml)`)
+	require.EqualError(t, runFunctions("json", "nosuchtype"), `no CEL functions found for type "nosuchtype"`)
+}
+
+func TestFilterFunctionsByReceiver(t *testing.T) {
+	functions := []functionDoc{
+		{Name: "size", Category: "list", Signatures: []string{"list.size() -> int", "size(list) -> int", "string.size() -> int"}},
+		{Name: "filter", Category: "list", Method: true, Signatures: []string{".filter(...)"}},
+		{Name: "math.abs", Category: "math", Signatures: []string{"math.abs(int) -> int"}},
+		{Name: "getHours", Category: "datetime", Signatures: []string{"google.protobuf.Timestamp.getHours() -> int"}},
+	}
+
+	list := filterFunctionsByReceiver(functions, "list")
+	require.Len(t, list, 2)
+	assert.Equal(t, []string{"list.size() -> int"}, list[0].Signatures, "overloads for other receivers are dropped")
+	assert.Equal(t, "filter", list[1].Name, "macros match on their category")
+
+	global := filterFunctionsByReceiver(functions, "global")
+	require.Len(t, global, 2)
+	assert.Equal(t, []string{"size(list) -> int"}, global[0].Signatures)
+	assert.Equal(t, "math.abs", global[1].Name)
+
+	timestamps := filterFunctionsByReceiver(functions, "Timestamp")
+	require.Len(t, timestamps, 1)
+	assert.Equal(t, "getHours", timestamps[0].Name)
+
+	assert.Equal(t, []string{"list", "string"}, receiverTypes(functions[0]))
+	assert.Len(t, functions[0].Signatures, 3, "filtering does not modify the input")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+var (
+	keyRecorder  *ui.KeyRecorder // records the session for --record-keys
+	replayEvents []ui.KeyEvent   // the session --replay-keys replays
+)
+
+// prepareKeyLog creates the --record-keys file and reads the --replay-keys
+// file before the TUI starts. The returned func closes the recording.
+func prepareKeyLog() (func(), error) {
+	keyRecorder, replayEvents = nil, nil
+	if recordKeysFile != "" && renderSnapshot {
+		return nil, errors.New("--record-keys records the interactive TUI; it cannot be combined with --snapshot")
+	}
+	if replayKeysFile != "" {
+		f, err := os.Open(replayKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key recording: %w", err)
+		}
+		replayEvents, err = ui.ReadKeyLog(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key recording %s: %w", replayKeysFile, err)
+		}
+	}
+	if recordKeysFile == "" {
+		return func() {}, nil
+	}
+	f, err := os.Create(recordKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key recording: %w", err)
+	}
+	keyRecorder = ui.NewKeyRecorder(f)
+	return func() {
+		if err := errors.Join(keyRecorder.Err(), f.Close()); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write key recording %s: %v\n", recordKeysFile, err)
+		}
+	}, nil
+}
+
+// configureKeyLog records the session for --record-keys and replays the
+// --replay-keys recording once the TUI starts.
+func configureKeyLog(m *ui.Model) {
+	m.KeyRecorder = keyRecorder
+	if len(replayEvents) > 0 {
+		m.Replay = ui.NewKeyReplay(replayEvents)
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLI_ReplayKeysSnapshot(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	// j, then l: open the second key of the sample.
+	keys := writeTempFile(t, "keys.jsonl", `{"at_ms":120,"key":"j","code":106,"text":"j"}
+{"at_ms":480,"key":"l","code":108,"text":"l"}
+`)
+	want := runCLI(t, []string{"kvx", "../tests/sample.yaml", "--snapshot", "--no-color", "--width", "80", "--height", "14", "--press", "jl"})
+	out := runCLI(t, []string{"kvx", "../tests/sample.yaml", "--snapshot", "--no-color", "--width", "80", "--height", "14", "--replay-keys", keys})
+	assert.Equal(t, want, out)
+}
+
+func TestPrepareKeyLog(t *testing.T) {
+	t.Cleanup(func() {
+		recordKeysFile, replayKeysFile, renderSnapshot = "", "", false
+		keyRecorder, replayEvents = nil, nil
+	})
+	dir := t.TempDir()
+
+	recordKeysFile, renderSnapshot = filepath.Join(dir, "keys.jsonl"), true
+	_, err := prepareKeyLog()
+	assert.EqualError(t, err, "--record-keys records the interactive TUI; it cannot be combined with --snapshot")
+
+	renderSnapshot = false
+	closeKeyLog, err := prepareKeyLog()
+	require.NoError(t, err)
+	require.NotNil(t, keyRecorder)
+	closeKeyLog()
+	_, err = os.Stat(recordKeysFile)
+	require.NoError(t, err)
+
+	recordKeysFile, replayKeysFile = "", filepath.Join(dir, "missing.jsonl")
+	_, err = prepareKeyLog()
+	assert.ErrorContains(t, err, "failed to read key recording")
+	replayKeysFile = writeTempFile(t, "bad.jsonl", "nope\n")
+	_, err = prepareKeyLog()
+	assert.ErrorContains(t, err, "failed to parse key recording")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// validateKioskFlags checks --kiosk, which opens the TUI read-only.
+// Picking, annotating, and saving a layout print or save what the user did,
+// which kiosk mode rules out.
+func validateKioskFlags() error {
+	if pickMode != "" {
+		return errors.New("--kiosk cannot be combined with --pick")
+	}
+	if annotationsFile != "" {
+		return errors.New("--kiosk cannot be combined with --annotations")
+	}
+	if saveLayoutName != "" {
+		return errors.New("--kiosk cannot be combined with --save-layout")
+	}
+	return nil
+}
+
+// configureKiosk turns on kiosk mode for --kiosk, after the other settings
+// it overrides. The remembered view layout is restored but not saved.
+func configureKiosk(m *ui.Model) {
+	if !kiosk {
+		return
+	}
+	m.SetKiosk(true)
+	m.OnExitViewState = nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func TestValidateKioskFlags(t *testing.T) {
+	prevPick, prevAnnotations, prevSave := pickMode, annotationsFile, saveLayoutName
+	t.Cleanup(func() { pickMode, annotationsFile, saveLayoutName = prevPick, prevAnnotations, prevSave })
+
+	pickMode, annotationsFile = "", ""
+	require.NoError(t, validateKioskFlags())
+	pickMode = "value"
+	assert.EqualError(t, validateKioskFlags(), "--kiosk cannot be combined with --pick")
+	pickMode, annotationsFile = "", "notes.json"
+	assert.EqualError(t, validateKioskFlags(), "--kiosk cannot be combined with --annotations")
+	annotationsFile, saveLayoutName = "", "ops"
+	assert.EqualError(t, validateKioskFlags(), "--kiosk cannot be combined with --save-layout")
+}
+
+func TestConfigureKiosk(t *testing.T) {
+	t.Cleanup(func() { kiosk = false })
+	m := ui.InitialModel(map[string]any{"a": 1})
+	m.OnExitViewState = func(ui.ViewState) {}
+
+	configureKiosk(&m)
+	assert.False(t, m.Kiosk, "off without --kiosk")
+
+	kiosk = true
+	configureKiosk(&m)
+	assert.True(t, m.Kiosk)
+	assert.False(t, m.AllowEditInput)
+	assert.Nil(t, m.OnExitViewState, "the view layout is not saved")
+}
+
+func TestCLI_KioskSnapshotFooter(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	t.Cleanup(func() { kiosk = false })
+	out := runCLI(t, []string{"kvx", "../tests/sample.yaml", "--kiosk", "--snapshot", "--no-color", "--width", "100", "--height", "14"})
+	assert.Contains(t, out, "/ search")
+	assert.Contains(t, out, "q quit")
+	assert.NotContains(t, out, "y copy")
+	assert.NotContains(t, out, ": expr")
+}
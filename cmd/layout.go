@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// resolveLayout returns the layout --layout names from ui.layouts in the
+// config, or the zero layout without --layout.
+func resolveLayout(cfg ui.ThemeConfigFile) (ui.LayoutConfig, error) {
+	if layoutName == "" {
+		return ui.LayoutConfig{}, nil
+	}
+	layout, ok := cfg.Layouts[layoutName]
+	if !ok {
+		if len(cfg.Layouts) == 0 {
+			return layout, fmt.Errorf("unknown layout %q: the config defines no ui.layouts", layoutName)
+		}
+		names := make([]string, 0, len(cfg.Layouts))
+		for name := range cfg.Layouts {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return layout, fmt.Errorf("unknown layout %q (available: %s)", layoutName, strings.Join(names, ", "))
+	}
+	switch layout.ViewMode {
+	case "", ui.ViewModeTable, ui.ViewModeColumns:
+	default:
+		return layout, fmt.Errorf("layout %q: invalid view_mode %q (expected %q or %q)", layoutName, layout.ViewMode, ui.ViewModeTable, ui.ViewModeColumns)
+	}
+	if _, err := parseSortOrder(layout.Sort); err != nil {
+		return layout, fmt.Errorf("layout %q: %w", layoutName, err)
+	}
+	return layout, nil
+}
+
+// applyLayout makes the expression, sort order, and key column width of
+// layout the defaults for the session; -e and --sort still win.
+func applyLayout(layout ui.LayoutConfig, cfg *ui.ThemeConfigFile) {
+	if strings.TrimSpace(expression) == "" {
+		expression = layout.Expression
+	}
+	if layout.Sort != "" {
+		cfg.Display.Sort = &layout.Sort
+	}
+	if layout.KeyColWidth != nil {
+		cfg.Display.KeyColWidth = layout.KeyColWidth
+	}
+}
+
+// configureLayout restores the view mode and columns of layout on m, keeping
+// --column-order when it was given. With --save-layout, the session is saved
+// as a layout with the sort order it ran with when the TUI exits.
+func configureLayout(m *ui.Model, layout ui.LayoutConfig, sort navigator.SortOrder) {
+	state := layout.ViewState()
+	if len(columnOrder) > 0 {
+		state.ColumnOrder = nil
+	}
+	m.ApplyViewState(state)
+
+	if saveLayoutName == "" {
+		return
+	}
+	path := configFile
+	if path == "" {
+		if candidates := ui.ConfigFileCandidates(); len(candidates) > 0 {
+			path = candidates[0]
+		}
+	}
+	m.OnExitLayout = func(saved ui.LayoutConfig) {
+		if sort != navigator.SortNone {
+			saved.Sort = string(sort)
+		}
+		saved.KeyColWidth = layout.KeyColWidth
+		if err := saveLayout(path, saveLayoutName, saved); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "saved layout %q to %s\n", saveLayoutName, path)
+	}
+}
+
+// saveLayout writes layout to ui.layouts.NAME in the config file at path,
+// creating the file when it does not exist. The rest of the file, comments
+// included, is kept.
+func saveLayout(path, name string, layout ui.LayoutConfig) error {
+	if path == "" {
+		return errors.New("save layout: no config file location")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("save layout: parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("save layout: %s is not a YAML mapping", path)
+	}
+	var value yaml.Node
+	if err := value.Encode(layout); err != nil {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	setMappingValue(mappingChild(mappingChild(root, "ui"), "layouts"), name, &value)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("save layout: %w", err)
+	}
+	return nil
+}
+
+// mappingChild returns the mapping under key in the YAML mapping node,
+// adding it, or replacing a value that is not a mapping, as needed.
+func mappingChild(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			if child := node.Content[i+1]; child.Kind == yaml.MappingNode {
+				return child
+			}
+			node.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode}
+			return node.Content[i+1]
+		}
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingValue(node, key, child)
+	return child
+}
+
+// setMappingValue sets key to value in the YAML mapping node.
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
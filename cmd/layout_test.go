@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func TestResolveLayout(t *testing.T) {
+	t.Cleanup(func() { layoutName = "" })
+	cfg := ui.ThemeConfigFile{Layouts: map[string]ui.LayoutConfig{
+		"ops":    {Expression: "_.items", ViewMode: "columns"},
+		"triage": {Sort: "descending"},
+		"broken": {ViewMode: "grid"},
+	}}
+
+	layout, err := resolveLayout(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ui.LayoutConfig{}, layout, "no --layout")
+
+	layoutName = "ops"
+	layout, err = resolveLayout(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "_.items", layout.Expression)
+
+	layoutName = "nope"
+	_, err = resolveLayout(cfg)
+	assert.EqualError(t, err, `unknown layout "nope" (available: broken, ops, triage)`)
+	_, err = resolveLayout(ui.ThemeConfigFile{})
+	assert.EqualError(t, err, `unknown layout "nope": the config defines no ui.layouts`)
+
+	layoutName = "broken"
+	_, err = resolveLayout(cfg)
+	assert.ErrorContains(t, err, `invalid view_mode "grid"`)
+}
+
+func TestApplyLayout(t *testing.T) {
+	t.Cleanup(func() { expression = "" })
+	width := 20
+	layout := ui.LayoutConfig{Expression: "_.items", Sort: "descending", KeyColWidth: &width}
+
+	expression = ""
+	var cfg ui.ThemeConfigFile
+	applyLayout(layout, &cfg)
+	assert.Equal(t, "_.items", expression)
+	require.NotNil(t, cfg.Display.Sort)
+	assert.Equal(t, "descending", *cfg.Display.Sort)
+	assert.Equal(t, &width, cfg.Display.KeyColWidth)
+
+	expression = "_.other"
+	applyLayout(layout, &cfg)
+	assert.Equal(t, "_.other", expression, "-e wins")
+}
+
+func TestConfigureLayout_Save(t *testing.T) {
+	prevConfig := configFile
+	t.Cleanup(func() { configFile, saveLayoutName = prevConfig, "" })
+	configFile = filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("# mine\nui:\n  theme:\n    default: dark\n"), 0o600))
+	saveLayoutName = "ops"
+
+	m := ui.InitialModel(map[string]any{"items": []any{map[string]any{"name": "a"}}})
+	configureLayout(&m, ui.LayoutConfig{ViewMode: "columns", HiddenColumns: []string{"age"}}, navigator.SortAscending)
+	assert.True(t, m.ColumnarView)
+	require.NotNil(t, m.OnExitLayout)
+
+	m.PathInput.SetValue("_.items")
+	m.OnExitLayout(m.CurrentLayout())
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# mine
+ui:
+  theme:
+    default: dark
+  layouts:
+    ops:
+      expression: _.items
+      view_mode: columns
+      hidden_columns:
+        - age
+      sort: ascending
+`, string(data))
+
+	cfg, err := loadMergedConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "_.items", cfg.Layouts["ops"].Expression)
+}
+
+func TestSaveLayout_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kvx", "config.yaml")
+	require.NoError(t, saveLayout(path, "a", ui.LayoutConfig{Expression: "_.x"}))
+	require.NoError(t, saveLayout(path, "a", ui.LayoutConfig{Expression: "_.y"}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "ui:\n  layouts:\n    a:\n      expression: _.y\n", string(data))
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oakwood-commons/kvx/internal/patch"
+)
+
+var (
+	mergeStrategy     string
+	mergePathStrategy []string
+	mergeArrayKeys    []string
+	mergeSchema       string
+	mergeOutput       string
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge base overlay [overlay...]",
+	Short: "Deep-merge layered documents into one",
+	Long: `Merge each overlay into the documents before it and print the result in the
+format of the base document (-o to choose another), for layered configuration
+such as defaults, environment, and local overrides. "-" reads one of the
+documents from stdin.
+
+Objects are merged recursively; members only one side has are kept. Where both
+sides set another value, --strategy decides:
+
+  override  the later value wins (default)
+  append    arrays are concatenated; other values are overridden
+  unique    arrays are concatenated without repeating equal elements
+  error     differing values stop the merge with an error naming the path
+
+--path-strategy sets the strategy for one path and everything below it, e.g.
+--path-strategy spec.tags=append. Arrays of objects identified by an
+--array-key field, or by the x-kvx-key fields of a --schema, are merged element
+by element instead, with new elements appended.`,
+	Example: `  kvx merge defaults.yaml prod.yaml
+  kvx merge base.json local.json --strategy unique -o yaml
+  kvx merge base.yaml team.yaml --strategy error --path-strategy metadata.labels=override
+  kvx merge deploy.yaml patch.yaml --array-key name`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runMerge(os.Stdout, args)
+	},
+}
+
+func runMerge(w io.Writer, inputs []string) error {
+	stdin := 0
+	for _, in := range inputs {
+		if in == "-" {
+			stdin++
+		}
+	}
+	if stdin > 1 {
+		return errors.New("only one document can be read from stdin")
+	}
+	opts, err := mergeOptionsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	base, err := loadDocumentArg(inputs[0])
+	if err != nil {
+		return err
+	}
+	result := base.Root
+	for _, in := range inputs[1:] {
+		overlay, err := loadDocumentArg(in)
+		if err != nil {
+			return err
+		}
+		if result, err = patch.DeepMerge(result, overlay.Root, opts); err != nil {
+			return fmt.Errorf("failed to merge %s: %w", in, err)
+		}
+	}
+	return writeApplyResult(w, result, applyOutputFormat(mergeOutput, base.Format))
+}
+
+// mergeOptionsFromFlags builds the merge options of --strategy,
+// --path-strategy, --array-key, and --schema.
+func mergeOptionsFromFlags() (patch.MergeOptions, error) {
+	var opts patch.MergeOptions
+	var err error
+	if opts.Strategy, err = patch.ParseStrategy(mergeStrategy); err != nil {
+		return opts, err
+	}
+	for _, spec := range mergePathStrategy {
+		path, name, ok := strings.Cut(spec, "=")
+		if !ok {
+			return opts, fmt.Errorf("invalid --path-strategy %q (expected PATH=STRATEGY)", spec)
+		}
+		pointer, err := mergePathPointer(path)
+		if err != nil {
+			return opts, err
+		}
+		s, err := patch.ParseStrategy(name)
+		if err != nil {
+			return opts, err
+		}
+		if opts.Paths == nil {
+			opts.Paths = map[string]patch.Strategy{}
+		}
+		opts.Paths[pointer] = s
+	}
+	opts.ArrayKeys, err = arrayKeys(mergeArrayKeys, mergeSchema)
+	return opts, err
+}
+
+// mergePathPointer converts a --path-strategy path, such as spec.tags,
+// _.spec.tags, or _["odd.key"], to a JSON Pointer.
+func mergePathPointer(path string) (string, error) {
+	steps, ok := flagPathSteps(path)
+	if !ok {
+		return "", fmt.Errorf("invalid --path-strategy path %q", path)
+	}
+	var b strings.Builder
+	for _, step := range steps {
+		b.WriteString("/" + patch.EscapePointer(step))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetMergeFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		mergeStrategy = "override"
+		mergePathStrategy, mergeArrayKeys = nil, nil
+		mergeSchema, mergeOutput = "", ""
+	})
+}
+
+func TestCLI_MergeLayers(t *testing.T) {
+	resetMergeFlags(t)
+	base := writeTempFile(t, "base.yaml", "name: web\ntags: [a, b]\nspec:\n  replicas: 1\n  ports: [80]\n")
+	env := writeTempFile(t, "prod.json", `{"tags": ["b", "c"], "spec": {"replicas": 3, "ports": [443]}}`)
+	local := writeTempFile(t, "local.yaml", "debug: true\n")
+
+	out := runCLI(t, []string{"kvx", "merge", base, env, local, "--strategy", "unique", "--path-strategy", "spec.ports=override"})
+	assert.Equal(t, "debug: true\nname: web\nspec:\n  ports:\n    - 443\n  replicas: 3\ntags:\n  - a\n  - b\n  - c\n", out)
+}
+
+func TestCLI_MergeArrayKeyToJSON(t *testing.T) {
+	resetMergeFlags(t)
+	base := writeTempFile(t, "base.yaml", "containers:\n  - {name: app, image: 'app:1', port: 80}\n")
+	overlay := writeTempFile(t, "overlay.yaml", "containers:\n  - {name: app, image: 'app:2'}\n  - {name: proxy, image: 'proxy:1'}\n")
+
+	out := runCLI(t, []string{"kvx", "merge", base, overlay, "--array-key", "name", "-o", "json"})
+	assert.JSONEq(t, `{"containers": [
+		{"name": "app", "image": "app:2", "port": 80},
+		{"name": "proxy", "image": "proxy:1"}
+	]}`, out)
+}
+
+func TestRunMerge_Errors(t *testing.T) {
+	resetMergeFlags(t)
+	base := writeTempFile(t, "base.json", `{"a": {"b": 1}}`)
+	overlay := writeTempFile(t, "overlay.json", `{"a": {"b": 2}}`)
+
+	mergeStrategy = "error"
+	assert.ErrorContains(t, runMerge(os.Stdout, []string{base, overlay}), "conflicting values at /a/b")
+
+	mergeStrategy = "deep"
+	assert.ErrorContains(t, runMerge(os.Stdout, []string{base, overlay}), "unknown merge strategy")
+
+	mergeStrategy = "override"
+	mergePathStrategy = []string{"a.b"}
+	assert.ErrorContains(t, runMerge(os.Stdout, []string{base, overlay}), "PATH=STRATEGY")
+
+	mergePathStrategy = nil
+	assert.ErrorContains(t, runMerge(os.Stdout, []string{"-", base, "-"}), "only one")
+}
+
+func TestMergePathPointer(t *testing.T) {
+	for path, want := range map[string]string{
+		"":                "",
+		"_":               "",
+		"spec.tags":       "/spec/tags",
+		"_.spec.tags":     "/spec/tags",
+		`_["a/b"].c`:      "/a~1b/c",
+		`metadata["x.y"]`: "/metadata/x.y",
+	} {
+		got, err := mergePathPointer(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, got, path)
+	}
+	_, err := mergePathPointer("spec..tags")
+	assert.Error(t, err)
+}
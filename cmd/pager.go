@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/termcolor"
+)
+
+// runPager pipes text into the pager command; swapped out in tests.
+var runPager = func(cmd *exec.Cmd, text string) error {
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// printTable writes rendered table output to w, sending it through a pager
+// instead when it does not fit on the screen (see shouldPage). If the pager
+// cannot be started the text is written as-is.
+func printTable(w io.Writer, sp *progressSpinner, text string) {
+	if shouldPage(text) {
+		if cmd := pagerCommand(); cmd != nil {
+			sp.Stop()
+			if err := runPager(cmd, termcolor.Convert(text, colorProfile)); err == nil {
+				return
+			}
+		}
+	}
+	fmt.Fprint(w, text)
+}
+
+// shouldPage reports whether text is taller than the terminal stdout is
+// attached to. Like git, paging only happens on a TTY and can be turned off
+// with --no-pager.
+func shouldPage(text string) bool {
+	if noPager || stdoutIsPiped() {
+		return false
+	}
+	_, h, err := termGetSize(int(os.Stdout.Fd()))
+	if err != nil || h <= 0 {
+		return false
+	}
+	return strings.Count(strings.TrimSuffix(text, "\n"), "\n")+1 > h
+}
+
+// pagerCommand builds the pager from $PAGER, defaulting to less. An empty
+// $PAGER or "cat" disables paging. When $LESS is unset it is set to FRX so
+// less exits on output that fits after all, keeps colors, and leaves the
+// text on screen when it quits.
+func pagerCommand() *exec.Cmd {
+	pager, set := os.LookupEnv("PAGER")
+	if !set {
+		pager = "less"
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return nil
+	}
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return nil
+	}
+	cmd := exec.Command(path, fields[1:]...) //nolint:gosec // the user chose the pager
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	return cmd
+}
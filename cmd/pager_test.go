@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTTY makes stdout look like a terminal of the given height.
+func fakeTTY(t *testing.T, height int) {
+	t.Helper()
+	origPiped, origSize, origNoPager := stdoutIsPiped, termGetSize, noPager
+	stdoutIsPiped = func() bool { return false }
+	termGetSize = func(_ int) (int, int, error) { return 80, height, nil }
+	noPager = false
+	t.Cleanup(func() {
+		stdoutIsPiped, termGetSize, noPager = origPiped, origSize, origNoPager
+	})
+}
+
+func TestShouldPage(t *testing.T) {
+	fakeTTY(t, 3)
+
+	assert.False(t, shouldPage("a\nb\nc\n"), "output that fits is printed")
+	assert.True(t, shouldPage("a\nb\nc\nd\n"))
+
+	noPager = true
+	assert.False(t, shouldPage("a\nb\nc\nd\n"), "--no-pager disables paging")
+
+	noPager = false
+	stdoutIsPiped = func() bool { return true }
+	assert.False(t, shouldPage("a\nb\nc\nd\n"), "never page into a pipe")
+}
+
+func TestPagerCommand(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	t.Setenv("PAGER", "sh -c true")
+	t.Setenv("LESS", "")
+	cmd := pagerCommand()
+	require.NotNil(t, cmd)
+	assert.Equal(t, []string{sh, "-c", "true"}, cmd.Args)
+	assert.NotContains(t, cmd.Env, "LESS=FRX", "an explicit $LESS is kept")
+
+	t.Setenv("PAGER", "cat")
+	assert.Nil(t, pagerCommand())
+	t.Setenv("PAGER", "")
+	assert.Nil(t, pagerCommand())
+	t.Setenv("PAGER", "kvx-no-such-pager")
+	assert.Nil(t, pagerCommand())
+}
+
+func TestPrintTableUsesPager(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	fakeTTY(t, 2)
+	t.Setenv("PAGER", "sh")
+
+	var paged string
+	origRun := runPager
+	runPager = func(_ *exec.Cmd, text string) error {
+		paged = text
+		return nil
+	}
+	t.Cleanup(func() { runPager = origRun })
+
+	table := strings.Repeat("row\n", 5)
+	var direct strings.Builder
+	printTable(&direct, nil, table)
+	assert.Equal(t, table, paged)
+	assert.Empty(t, direct.String())
+
+	noPager = true
+	printTable(&direct, nil, table)
+	assert.Equal(t, table, direct.String())
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/patch"
+	"github.com/oakwood-commons/kvx/pkg/core"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+	"github.com/oakwood-commons/kvx/pkg/tui"
+)
+
+var (
+	patchFrom       string
+	patchTo         string
+	patchExpression string
+	patchMerge      bool
+	patchOutput     string
+	patchArrayKeys  []string
+	patchSchema     string
+)
+
+var patchCmd = &cobra.Command{
+	Use:   "patch --from FILE --to FILE",
+	Short: "Print the JSON Patch that turns one document into another",
+	Long: `Compare two documents and print the changes between them as an RFC 6902
+JSON Patch, or with --merge as an RFC 7386 JSON Merge Patch, for tools that
+apply changes rather than display them.
+
+The documents may be in any input format kvx reads; "-" reads one of them from
+stdin. With -e both documents are replaced by the result of the expression
+first, so the patch covers only that part of them.
+
+Array elements are compared by index unless --array-key names a field that
+identifies them, such as name or id, or --schema gives a JSON Schema whose
+arrays declare one with x-kvx-key. Keyed arrays are matched by that field, so
+reordering a list of named objects produces move operations. Merge patches
+always replace changed arrays whole, and cannot set a member to null, as null
+means removal there; --merge fails when the target has such a member.`,
+	Example: `  kvx patch --from old.json --to new.json
+  kvx patch --from old.yaml --to new.yaml --merge -o yaml
+  kvx patch --from a.json --to b.json -e '_.items.filter(x, x.enabled)'
+  kvx patch --from old.yaml --to new.yaml --array-key name`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runPatch(os.Stdout)
+	},
+}
+
+func runPatch(w io.Writer) error {
+	if patchFrom == "" || patchTo == "" {
+		return errors.New("patch needs both --from and --to")
+	}
+	if patchFrom == "-" && patchTo == "-" {
+		return errors.New("only one of --from and --to can read stdin")
+	}
+	var engine *core.Engine
+	if strings.TrimSpace(patchExpression) != "" {
+		var err error
+		if engine, err = core.New(); err != nil {
+			return fmt.Errorf("failed to init evaluator: %w", err)
+		}
+	}
+	from, err := loadPatchInput(patchFrom, engine)
+	if err != nil {
+		return err
+	}
+	to, err := loadPatchInput(patchTo, engine)
+	if err != nil {
+		return err
+	}
+
+	keys, err := arrayKeys(patchArrayKeys, patchSchema)
+	if err != nil {
+		return err
+	}
+
+	var result interface{}
+	if patchMerge {
+		if result, err = patch.MergePatch(from, to); err != nil {
+			return fmt.Errorf("%w; run without --merge for a JSON Patch, which can set null", err)
+		}
+	} else {
+		result = patch.Document(patch.DiffWithOptions(from, to, patch.Options{ArrayKeys: keys}))
+	}
+	switch patchOutput {
+	case "", "json":
+		return formatter.WriteJSON(w, result, formatter.SerializeOptions{})
+	case "yaml":
+		return formatter.WriteYAML(w, result, formatter.YAMLFormatOptions{})
+	default:
+		return fmt.Errorf("invalid patch output format %q (expected json or yaml)", patchOutput)
+	}
+}
+
+// arrayKeys returns the fields of --array-key followed by the x-kvx-key
+// fields declared in the --schema file, if any.
+func arrayKeys(flagKeys []string, schemaPath string) ([]string, error) {
+	keys := append([]string(nil), flagKeys...)
+	if schemaPath == "" {
+		return keys, nil
+	}
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	schemaKeys, err := tui.ParseSchemaArrayKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", schemaPath, err)
+	}
+	for _, k := range schemaKeys {
+		if !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// loadPatchInput reads one side of a patch, from stdin for "-", and
+// evaluates -e against it when engine is set.
+func loadPatchInput(path string, engine *core.Engine) (interface{}, error) {
+	doc, err := loadDocumentArg(path)
+	if err != nil {
+		return nil, err
+	}
+	if engine == nil {
+		return doc.Root, nil
+	}
+	result, err := engine.Evaluate(patchExpression, doc.Root)
+	if err != nil {
+		return nil, fmt.Errorf("expression error for %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// loadDocumentArg loads the file named by a flag or argument, or stdin for "-".
+func loadDocumentArg(path string) (*loader.Document, error) {
+	var doc *loader.Document
+	var err error
+	if path == "-" {
+		var data []byte
+		if data, err = io.ReadAll(os.Stdin); err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		doc, err = loader.LoadDocument(string(data), loader.DocumentOptions{})
+	} else {
+		doc, err = loader.LoadFileDocument(path, loader.DocumentOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return doc, nil
+}
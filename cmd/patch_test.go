@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetPatchFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		patchFrom, patchTo, patchExpression = "", "", ""
+		patchMerge = false
+		patchOutput = "json"
+		patchArrayKeys, patchSchema = nil, ""
+	})
+}
+
+func writePatchInputs(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	from := filepath.Join(dir, "from.json")
+	to := filepath.Join(dir, "to.yaml")
+	require.NoError(t, os.WriteFile(from, []byte(`{"name": "web", "spec": {"replicas": 1, "paused": true}}`), 0o600))
+	require.NoError(t, os.WriteFile(to, []byte("name: web\nspec:\n  replicas: 3\n"), 0o600))
+	return from, to
+}
+
+func TestCLI_PatchJSON(t *testing.T) {
+	resetPatchFlags(t)
+	from, to := writePatchInputs(t)
+
+	out := runCLI(t, []string{"kvx", "patch", "--from", from, "--to", to})
+	assert.JSONEq(t, `[
+		{"op": "remove", "path": "/spec/paused"},
+		{"op": "replace", "path": "/spec/replicas", "value": 3}
+	]`, out)
+}
+
+func TestCLI_PatchMergeWithExpression(t *testing.T) {
+	resetPatchFlags(t)
+	from, to := writePatchInputs(t)
+
+	out := runCLI(t, []string{"kvx", "patch", "--from", from, "--to", to, "--merge", "-e", "_.spec", "-o", "yaml"})
+	assert.Equal(t, "paused: null\nreplicas: 3\n", out)
+}
+
+func TestRunPatch_Errors(t *testing.T) {
+	resetPatchFlags(t)
+	assert.ErrorContains(t, runPatch(os.Stdout), "both --from and --to")
+
+	patchFrom, patchTo = "-", "-"
+	assert.ErrorContains(t, runPatch(os.Stdout), "only one")
+
+	patchFrom, patchTo = filepath.Join(t.TempDir(), "missing.json"), "-"
+	assert.ErrorContains(t, runPatch(os.Stdout), "missing.json")
+
+	dir := t.TempDir()
+	patchFrom, patchTo = filepath.Join(dir, "from.json"), filepath.Join(dir, "to.json")
+	require.NoError(t, os.WriteFile(patchFrom, []byte(`{"old": 1}`), 0o600))
+	require.NoError(t, os.WriteFile(patchTo, []byte(`{"old": 1, "new": null}`), 0o600))
+	patchMerge = true
+	assert.ErrorContains(t, runPatch(os.Stdout), "/new is null, which a merge patch cannot express")
+}
+
+func TestCLI_PatchArrayKey(t *testing.T) {
+	resetPatchFlags(t)
+	dir := t.TempDir()
+	from := filepath.Join(dir, "from.yaml")
+	to := filepath.Join(dir, "to.yaml")
+	require.NoError(t, os.WriteFile(from, []byte("- {name: a, port: 1}\n- {name: b, port: 2}\n"), 0o600))
+	require.NoError(t, os.WriteFile(to, []byte("- {name: b, port: 2}\n- {name: a, port: 3}\n"), 0o600))
+	want := `[
+		{"op": "move", "from": "/1", "path": "/0"},
+		{"op": "replace", "path": "/1/port", "value": 3}
+	]`
+
+	out := runCLI(t, []string{"kvx", "patch", "--from", from, "--to", to, "--array-key", "name"})
+	assert.JSONEq(t, want, out)
+
+	patchArrayKeys = nil
+	schema := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(schema, []byte(`{"type": "array", "x-kvx-key": "name"}`), 0o600))
+	out = runCLI(t, []string{"kvx", "patch", "--from", from, "--to", to, "--schema", schema})
+	assert.JSONEq(t, want, out)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// validatePickFlags checks --pick, which opens the TUI.
+func validatePickFlags() error {
+	switch pickMode {
+	case "value", "path":
+	default:
+		return fmt.Errorf("invalid --pick value %q (expected value or path)", pickMode)
+	}
+	if renderSnapshot {
+		return errors.New("--pick cannot be combined with --snapshot")
+	}
+	return nil
+}
+
+// configurePick turns on pick mode for --pick and stores the picks made
+// before the TUI exits in picks.
+func configurePick(m *ui.Model, picks *[]ui.Pick) {
+	if pickMode == "" {
+		return
+	}
+	m.PickMode = true
+	m.PickMulti = pickMulti
+	m.OnExitPicks = func(p []ui.Pick) {
+		*picks = p
+	}
+}
+
+// writePicks prints what --pick picked: the path of each pick for
+// --pick=path, otherwise its value in the -o format, or as plain text for
+// scalars and compact JSON for maps and lists. With --multi and -o json or
+// yaml, the picks are written as one list.
+func writePicks(w io.Writer, picks []ui.Pick) error {
+	if pickMulti && (output == "json" || output == "yaml") {
+		list := make([]interface{}, len(picks))
+		for i, p := range picks {
+			list[i] = p.Value
+			if pickMode == "path" {
+				list[i] = p.Path
+			}
+		}
+		return writePickedValue(w, list)
+	}
+	for _, p := range picks {
+		if pickMode == "path" {
+			if _, err := fmt.Fprintln(w, p.Path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writePickedValue(w, p.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writePickedValue(w io.Writer, v interface{}) error {
+	switch output {
+	case "json":
+		return formatter.WriteJSON(w, v, serializeOptionsFromFlags())
+	case "yaml":
+		return formatter.WriteYAML(w, v, formatter.YAMLFormatOptions{SerializeOptions: serializeOptionsFromFlags()})
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		s, err := formatter.FormatJSON(v, formatter.SerializeOptions{Compact: true})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	}
+	_, err := fmt.Fprintln(w, formatter.StringifyPreserveNewlines(v))
+	return err
+}
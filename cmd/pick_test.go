@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func setPickFlags(t *testing.T, mode, format string) {
+	t.Helper()
+	prevMode, prevOutput := pickMode, output
+	t.Cleanup(func() { pickMode, output = prevMode, prevOutput })
+	pickMode, output = mode, format
+}
+
+func TestWritePicks(t *testing.T) {
+	picks := []ui.Pick{
+		{Path: "_.name", Value: "web"},
+		{Path: "_.spec", Value: map[string]interface{}{"replicas": 3}},
+	}
+	for _, tc := range []struct {
+		mode, output, want string
+	}{
+		{"value", "auto", "web\n{\"replicas\":3}\n"},
+		{"path", "auto", "_.name\n_.spec\n"},
+		{"path", "json", "_.name\n_.spec\n"},
+		{"value", "yaml", "web\nreplicas: 3\n"},
+	} {
+		setPickFlags(t, tc.mode, tc.output)
+		var buf bytes.Buffer
+		require.NoError(t, writePicks(&buf, picks))
+		assert.Equal(t, tc.want, buf.String(), "%s/%s", tc.mode, tc.output)
+	}
+}
+
+func TestWritePicks_Multi(t *testing.T) {
+	picks := []ui.Pick{{Path: "_[0]", Value: "dev"}, {Path: "_[2]", Value: "prod"}}
+	pickMulti = true
+	t.Cleanup(func() { pickMulti = false })
+	for _, tc := range []struct {
+		mode, output, want string
+	}{
+		{"value", "auto", "dev\nprod\n"},
+		{"value", "json", "[\n  \"dev\",\n  \"prod\"\n]\n"},
+		{"path", "json", "[\n  \"_[0]\",\n  \"_[2]\"\n]\n"},
+		{"value", "yaml", "- dev\n- prod\n"},
+	} {
+		setPickFlags(t, tc.mode, tc.output)
+		var buf bytes.Buffer
+		require.NoError(t, writePicks(&buf, picks))
+		assert.Equal(t, tc.want, buf.String(), "%s/%s", tc.mode, tc.output)
+	}
+}
+
+func TestConfigurePick(t *testing.T) {
+	setPickFlags(t, "value", "auto")
+	var picks []ui.Pick
+	m := ui.InitialModel(nil)
+	configurePick(&m, &picks)
+	require.True(t, m.PickMode)
+	assert.False(t, m.PickMulti)
+	m.OnExitPicks([]ui.Pick{{Path: "_"}})
+	assert.Len(t, picks, 1)
+
+	pickMode = ""
+	m = ui.InitialModel(nil)
+	configurePick(&m, &picks)
+	assert.False(t, m.PickMode)
+}
+
+func TestValidatePickFlags(t *testing.T) {
+	setPickFlags(t, "value", "auto")
+	assert.NoError(t, validatePickFlags())
+
+	pickMode = "key"
+	assert.ErrorContains(t, validatePickFlags(), "--pick")
+
+	pickMode = "path"
+	renderSnapshot = true
+	t.Cleanup(func() { renderSnapshot = false })
+	assert.ErrorContains(t, validatePickFlags(), "--snapshot")
+}
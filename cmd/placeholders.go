@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/oakwood-commons/kvx/internal/placeholder"
+	"github.com/oakwood-commons/kvx/pkg/core"
+)
+
+// maxListedChoices is how many choices a placeholder prompt lists.
+const maxListedChoices = 20
+
+// openPlaceholderPromptFn opens the terminal placeholder values are asked
+// on: stdin and stderr, or the terminal device when stdin carries the input.
+var openPlaceholderPromptFn = openPlaceholderPrompt
+
+func openPlaceholderPrompt() (io.Reader, io.Writer, func(), error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return os.Stdin, os.Stderr, func() {}, nil
+	}
+	in, out, err := openTerminalIOFn()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if out == nil {
+		out = in
+	}
+	return in, out, func() {
+		_ = in.Close()
+		if out != in {
+			_ = out.Close()
+		}
+	}, nil
+}
+
+// expandPlaceholders replaces the {{name}} placeholders of -e and --where
+// with CEL literals. Values come from --param, or are asked for on the
+// terminal; without one the placeholder's default is used.
+func expandPlaceholders(engine *core.Engine, root interface{}) error {
+	specs, err := placeholder.Parse(expression + "\n" + whereExpr)
+	if err != nil || len(specs) == 0 {
+		return err
+	}
+	params, err := parseParams(exprParams)
+	if err != nil {
+		return err
+	}
+
+	var prompt *bufio.Reader
+	var promptOut io.Writer
+	closePrompt := func() {}
+	defer func() { closePrompt() }()
+	values := make(map[string]interface{}, len(specs))
+	for _, p := range specs {
+		choices, err := placeholderChoices(engine, p, root)
+		if err != nil {
+			return err
+		}
+		if text, ok := params[p.Name]; ok {
+			values[p.Name] = choiceValue(text, choices)
+			continue
+		}
+		if prompt == nil {
+			in, out, closeFn, err := openPlaceholderPromptFn()
+			if err != nil {
+				if p.HasDefault {
+					values[p.Name] = choiceValue(p.Default, choices)
+					continue
+				}
+				return fmt.Errorf("placeholder {{%s}} needs a value: pass --param %s=VALUE", p.Name, p.Name)
+			}
+			prompt, promptOut, closePrompt = bufio.NewReader(in), out, closeFn
+		}
+		if values[p.Name], err = askPlaceholder(prompt, promptOut, p, choices); err != nil {
+			return err
+		}
+	}
+
+	if expression, err = placeholder.Expand(expression, values); err != nil {
+		return err
+	}
+	whereExpr, err = placeholder.Expand(whereExpr, values)
+	return err
+}
+
+// parseParams parses --param NAME=VALUE flags.
+func parseParams(flags []string) (map[string]string, error) {
+	params := make(map[string]string, len(flags))
+	for _, f := range flags {
+		name, value, ok := strings.Cut(f, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --param %q (expected NAME=VALUE)", f)
+		}
+		params[strings.TrimSpace(name)] = value
+	}
+	return params, nil
+}
+
+// placeholderChoices evaluates the choice list of p against root and
+// returns its distinct values in order.
+func placeholderChoices(engine *core.Engine, p placeholder.Placeholder, root interface{}) ([]interface{}, error) {
+	if p.Choices == "" {
+		return nil, nil
+	}
+	result, err := engine.Evaluate(p.Choices, root)
+	if err != nil {
+		return nil, fmt.Errorf("choices of placeholder {{%s}}: %w", p.Name, err)
+	}
+	list, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("choices of placeholder {{%s}} must be a list, got %T", p.Name, result)
+	}
+	seen := make(map[string]bool, len(list))
+	var choices []interface{}
+	for _, v := range list {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("choices of placeholder {{%s}} must be scalars", p.Name)
+		}
+		if text := choiceText(v); !seen[text] {
+			seen[text] = true
+			choices = append(choices, v)
+		}
+	}
+	return choices, nil
+}
+
+// askPlaceholder prompts for the value of p until one is given. An empty
+// answer takes the default; a number picks from the listed choices.
+func askPlaceholder(in *bufio.Reader, w io.Writer, p placeholder.Placeholder, choices []interface{}) (interface{}, error) {
+	label := p.Name
+	if len(choices) > 0 {
+		fmt.Fprintf(w, "%s:\n", p.Name)
+		for i, c := range choices {
+			if i == maxListedChoices {
+				fmt.Fprintf(w, "  ... %d more\n", len(choices)-i)
+				break
+			}
+			fmt.Fprintf(w, "  %d) %s\n", i+1, choiceText(c))
+		}
+		label += fmt.Sprintf(" [1-%d]", min(len(choices), maxListedChoices))
+	}
+	if p.HasDefault {
+		label += fmt.Sprintf(" (default %s)", p.Default)
+	}
+	for {
+		fmt.Fprintf(w, "%s: ", label)
+		line, err := in.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read placeholder {{%s}}: %w", p.Name, err)
+		}
+		answer := strings.TrimSpace(line)
+		switch {
+		case answer == "" && p.HasDefault:
+			return choiceValue(p.Default, choices), nil
+		case answer != "":
+			if v, ok := choiceByText(answer, choices); ok {
+				return v, nil
+			}
+			if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(choices) {
+				return choices[n-1], nil
+			}
+			return placeholder.Value(answer), nil
+		}
+		if err != nil {
+			fmt.Fprintln(w)
+			return nil, fmt.Errorf("no value for placeholder {{%s}}", p.Name)
+		}
+	}
+}
+
+// choiceValue returns the choice written as text, keeping its type from
+// the data, or text read as a value when it is not one of the choices.
+func choiceValue(text string, choices []interface{}) interface{} {
+	if v, ok := choiceByText(strings.TrimSpace(text), choices); ok {
+		return v
+	}
+	return placeholder.Value(text)
+}
+
+func choiceByText(text string, choices []interface{}) (interface{}, bool) {
+	for _, c := range choices {
+		if choiceText(c) == text {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func choiceText(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
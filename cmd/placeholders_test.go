@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/placeholder"
+	"github.com/oakwood-commons/kvx/pkg/core"
+)
+
+// usePlaceholderPrompt answers placeholder prompts from input, or fails to
+// open a terminal when input is nil.
+func usePlaceholderPrompt(t *testing.T, input *string) *bytes.Buffer {
+	t.Helper()
+	orig := openPlaceholderPromptFn
+	t.Cleanup(func() { openPlaceholderPromptFn = orig })
+	var out bytes.Buffer
+	openPlaceholderPromptFn = func() (io.Reader, io.Writer, func(), error) {
+		if input == nil {
+			return nil, nil, nil, errors.New("no terminal")
+		}
+		return strings.NewReader(*input), &out, func() {}, nil
+	}
+	return &out
+}
+
+func TestCLI_PlaceholderParam(t *testing.T) {
+	usePlaceholderPrompt(t, nil)
+	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-o", "json",
+		"-e", "_.items.filter(i, i.origin == {{origin}} && i.stock >= {{min=100}}).map(i, i.name)",
+		"--param", "origin=england"})
+	assert.JSONEq(t, `["earl-grey"]`, out)
+}
+
+func TestCLI_PlaceholderPrompt(t *testing.T) {
+	answers := "2\n\n"
+	prompts := usePlaceholderPrompt(t, &answers)
+	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-o", "json",
+		"-e", "_.items.filter(i, i.origin == {{origin in _.items.map(i, i.origin)}} && i.stock >= {{min=100}}).map(i, i.name)"})
+	assert.JSONEq(t, `["earl-grey"]`, out)
+	assert.Contains(t, prompts.String(), "origin:\n  1) egypt\n  2) england\n")
+	assert.Contains(t, prompts.String(), "min (default 100): ")
+}
+
+func TestExpandPlaceholders_NeedsValue(t *testing.T) {
+	resetRootCmdState()
+	usePlaceholderPrompt(t, nil)
+	engine, err := core.New()
+	require.NoError(t, err)
+
+	expression = "_.name == {{name}}"
+	t.Cleanup(resetRootCmdState)
+	err = expandPlaceholders(engine, map[string]interface{}{"name": "web"})
+	assert.EqualError(t, err, "placeholder {{name}} needs a value: pass --param name=VALUE")
+
+	exprParams = []string{"name"}
+	err = expandPlaceholders(engine, map[string]interface{}{"name": "web"})
+	assert.EqualError(t, err, `invalid --param "name" (expected NAME=VALUE)`)
+}
+
+func TestAskPlaceholder(t *testing.T) {
+	choices := []interface{}{int64(80), int64(443)}
+	p := placeholder.Placeholder{Name: "port"}
+	for _, tc := range []struct {
+		input string
+		want  interface{}
+	}{
+		{"443\n", int64(443)},
+		{"1\n", int64(80)},
+		{"\n8080\n", int64(8080)},
+		{`"80"` + "\n", "80"},
+	} {
+		var out bytes.Buffer
+		got, err := askPlaceholder(bufio.NewReader(strings.NewReader(tc.input)), &out, p, choices)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.want, got, tc.input)
+	}
+
+	_, err := askPlaceholder(bufio.NewReader(strings.NewReader("")), io.Discard, p, nil)
+	assert.EqualError(t, err, "no value for placeholder {{port}}")
+}
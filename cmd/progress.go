@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	"golang.org/x/term"
+)
+
+// progressDelay is how long rendering may take before the spinner appears,
+// so fast output never flashes one.
+const progressDelay = 300 * time.Millisecond
+
+var stderrIsTerminal = func() bool { return term.IsTerminal(int(os.Stderr.Fd())) }
+
+// progressSpinner draws a spinner on stderr while non-interactive output is
+// being rendered. A nil spinner is valid and does nothing.
+type progressSpinner struct {
+	once sync.Once
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// startProgress starts a spinner with the given label on stderr, shown once
+// rendering has taken longer than progressDelay. It returns nil when stderr
+// is not a terminal or the run is --deterministic.
+func startProgress(label string) *progressSpinner {
+	if deterministic || !stderrIsTerminal() {
+		return nil
+	}
+	p := &progressSpinner{done: make(chan struct{})}
+	p.wg.Add(1)
+	go p.run(os.Stderr, label)
+	return p
+}
+
+func (p *progressSpinner) run(w io.Writer, label string) {
+	defer p.wg.Done()
+	select {
+	case <-p.done:
+		return
+	case <-time.After(progressDelay):
+	}
+	frames := spinner.Dot.Frames
+	ticker := time.NewTicker(spinner.Dot.FPS)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		fmt.Fprintf(w, "\r%s %s", frames[i%len(frames)], label)
+		select {
+		case <-p.done:
+			fmt.Fprint(w, "\r\x1b[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop removes the spinner and waits until its line is cleared. It is safe
+// to call more than once.
+func (p *progressSpinner) Stop() {
+	if p == nil {
+		return
+	}
+	p.once.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+// progressWriter writes to w, stopping the spinner before the first write so
+// the output never interleaves with it.
+type progressWriter struct {
+	w  io.Writer
+	sp *progressSpinner
+}
+
+func (pw progressWriter) Write(b []byte) (int, error) {
+	pw.sp.Stop()
+	return pw.w.Write(b)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressSpinnerDrawsAfterDelayAndClears(t *testing.T) {
+	var buf strings.Builder
+	p := &progressSpinner{done: make(chan struct{})}
+	p.wg.Add(1)
+	go p.run(&buf, "rendering json output")
+	time.Sleep(progressDelay + 150*time.Millisecond)
+	p.Stop()
+	p.Stop()
+
+	out := buf.String()
+	assert.Contains(t, out, "rendering json output")
+	assert.True(t, strings.HasSuffix(out, "\r\x1b[K"), "the spinner line is cleared on stop")
+}
+
+func TestProgressSpinnerQuietWhenFast(t *testing.T) {
+	var buf strings.Builder
+	p := &progressSpinner{done: make(chan struct{})}
+	p.wg.Add(1)
+	go p.run(&buf, "rendering")
+	p.Stop()
+	assert.Empty(t, buf.String())
+}
+
+func TestStartProgressNeedsTerminal(t *testing.T) {
+	orig := stderrIsTerminal
+	stderrIsTerminal = func() bool { return false }
+	t.Cleanup(func() { stderrIsTerminal = orig })
+
+	sp := startProgress("rendering")
+	assert.Nil(t, sp)
+	sp.Stop()
+
+	var buf strings.Builder
+	_, err := progressWriter{w: &buf, sp: sp}.Write([]byte("ok"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", buf.String())
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/oakwood-commons/kvx/pkg/core"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
+
+// validateRefreshFlags checks --refresh, which reads the input again on an
+// interval. Stdin can only be read once, so it needs a single input file.
+func validateRefreshFlags(args []string) error {
+	if refreshInterval < 0 {
+		return fmt.Errorf("--refresh must not be negative, got %s", refreshInterval)
+	}
+	if refreshInterval == 0 {
+		return nil
+	}
+	if len(args) != 1 || args[0] == "-" || strings.TrimSpace(args[0]) == "" {
+		return errors.New("--refresh needs a single input file to read again")
+	}
+	return nil
+}
+
+// inputRefresher returns the function --refresh calls to read the input
+// again, decoding it with --auto-decode=eager and filtering it with --where
+// as at startup. Errors are returned for the status bar rather than exiting.
+func inputRefresher(args []string, record loader.DocumentOptions, lgr logr.Logger) func() (interface{}, error) {
+	var engine *core.Engine
+	return func() (interface{}, error) {
+		doc, _, err := loadInputDocument(args, expression, record, false, nil, lgr)
+		if err != nil {
+			return nil, err
+		}
+		root := doc.Root
+		if autoDecode == "eager" {
+			root = loader.RecursiveDecode(root)
+		}
+		if whereExpr == "" {
+			return root, nil
+		}
+		if engine == nil {
+			if engine, err = core.New(); err != nil {
+				return nil, err
+			}
+		}
+		filtered, err := engine.EvaluateWhere(whereExpr, root)
+		if err != nil {
+			return nil, fmt.Errorf("where filter: %w", err)
+		}
+		return filtered, nil
+	}
+}
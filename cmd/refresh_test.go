@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
+
+func TestValidateRefreshFlags(t *testing.T) {
+	t.Cleanup(func() { refreshInterval = 0 })
+	refreshInterval = 0
+	require.NoError(t, validateRefreshFlags(nil))
+
+	refreshInterval = 5 * time.Second
+	require.NoError(t, validateRefreshFlags([]string{"data.json"}))
+	assert.EqualError(t, validateRefreshFlags(nil), "--refresh needs a single input file to read again")
+	assert.EqualError(t, validateRefreshFlags([]string{"-"}), "--refresh needs a single input file to read again")
+
+	refreshInterval = -time.Second
+	assert.EqualError(t, validateRefreshFlags([]string{"data.json"}), "--refresh must not be negative, got -1s")
+}
+
+func TestInputRefresher(t *testing.T) {
+	t.Cleanup(func() { whereExpr = "" })
+	path := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"n":1},{"n":2}]`), 0o600))
+	refresh := inputRefresher([]string{path}, loader.DocumentOptions{}, logr.Discard())
+
+	root, err := refresh()
+	require.NoError(t, err)
+	assert.Len(t, root, 2)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"n":1},{"n":2},{"n":3}]`), 0o600))
+	whereExpr = "_.n > 1"
+	root, err = refresh()
+	require.NoError(t, err)
+	assert.Len(t, root, 2, "--where applies to the new data")
+
+	require.NoError(t, os.Remove(path))
+	_, err = refresh()
+	assert.ErrorContains(t, err, "failed to read file")
+}
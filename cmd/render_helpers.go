@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/x/ansi"
+	"github.com/oakwood-commons/kvx/internal/navigator"
 	ui "github.com/oakwood-commons/kvx/internal/ui"
 )
 
@@ -82,7 +83,7 @@ func isURL(s string) bool {
 }
 
 // renderSnapshotOutput centralizes snapshot sizing, help loading, and model configuration.
-func renderSnapshotOutput(cfg ui.ThemeConfigFile, renderRoot interface{}, root interface{}, appName string, startKeys []string, expr string, noColor bool, widthFlag, heightFlag int, detectedW, detectedH int, configPath string, debugLog bool, dc *debugCollector, debugLabel string, keyMode ui.KeyMode) string {
+func renderSnapshotOutput(cfg ui.ThemeConfigFile, renderRoot interface{}, root interface{}, appName string, startKeys []string, expr string, widthFlag, heightFlag int, detectedW, detectedH int, configPath string, debugLog bool, dc *debugCollector, debugLabel string, keyMode ui.KeyMode) string {
 	sizing := resolveSnapshotSize(widthFlag, heightFlag, detectedW, detectedH)
 	if debugLog && dc != nil {
 		if debugLabel != "" {
@@ -93,10 +94,12 @@ func renderSnapshotOutput(cfg ui.ThemeConfigFile, renderRoot interface{}, root i
 	}
 
 	helpTitle, helpText := loadHelp(configPath, keyMode)
-	return renderSnapshotView(renderRoot, root, appName, helpTitle, helpText, startKeys, expr, noColor, sizing, func(m *ui.Model) {
+	return renderSnapshotView(renderRoot, root, appName, helpTitle, helpText, startKeys, expr, sizing, func(m *ui.Model) {
 		applySnapshotConfigToModel(m, cfg)
-		if parsedDisplaySchema != nil {
-			m.DisplaySchema = parsedDisplaySchema
+		applySchemaToModel(m)
+		if layout, err := resolveLayout(cfg); err == nil {
+			configureLayout(m, layout, navigator.SortNone)
 		}
+		configureKiosk(m)
 	})
 }
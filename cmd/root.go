@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/csv"
@@ -8,12 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"runtime"
 	rdebug "runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -26,10 +30,13 @@ import (
 	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/jq"
 	"github.com/oakwood-commons/kvx/internal/limiter"
 	"github.com/oakwood-commons/kvx/internal/navigator"
 	"github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/internal/yamlsource"
 	"github.com/oakwood-commons/kvx/pkg/core"
 	"github.com/oakwood-commons/kvx/pkg/loader"
 	"github.com/oakwood-commons/kvx/pkg/logger"
@@ -99,6 +106,7 @@ func init() {
 				limitRecords = 0
 				offsetRecords = 0
 				tailRecords = 0
+				sampleRecords = 0
 				cmd.Run(cmd, cmd.Flags().Args())
 			}
 			return
@@ -155,23 +163,50 @@ func resolveSnapshotSize(flagWidth, flagHeight, detectedWidth, detectedHeight in
 	}
 }
 
-func renderSnapshotView(renderRoot interface{}, root interface{}, appName, helpTitle, helpText string, startKeys []string, initialExpr string, noColor bool, sizing snapshotSize, configure func(*ui.Model)) string {
+// scriptErrors collects the failures of the --press script of the last
+// snapshot, such as an unmet <expect:...>, which the status bar of a
+// snapshot does not show.
+var scriptErrors []error
+
+func renderSnapshotView(renderRoot interface{}, root interface{}, appName, helpTitle, helpText string, startKeys []string, initialExpr string, sizing snapshotSize, configure func(*ui.Model)) string {
 	helpVisible := snapshotHelpVisible(startKeys)
+	scriptErrors = nil
 	return ui.RenderModelSnapshot(renderRoot, ui.ModelSnapshotConfig{
 		Width:       sizing.Width,
 		Height:      sizing.Height,
-		NoColor:     noColor,
+		NoColor:     plainOutput(),
 		HelpVisible: helpVisible,
 		StartKeys:   startKeys,
+		Replay:      replayEvents,
 		InitialExpr: initialExpr,
-		Configure:   configure,
-		Root:        root,
-		AppName:     appName,
-		HelpTitle:   helpTitle,
-		HelpText:    helpText,
+		Configure: func(m *ui.Model) {
+			if configure != nil {
+				configure(m)
+			}
+			m.OnScriptError = func(err error) {
+				scriptErrors = append(scriptErrors, err)
+			}
+			m.SetDeterministic(deterministic)
+		},
+		Root:      root,
+		AppName:   appName,
+		HelpTitle: helpTitle,
+		HelpText:  helpText,
 	})
 }
 
+// exitOnScriptErrors prints the failures of the --press script of the
+// snapshot and exits 1 if there were any.
+func exitOnScriptErrors() {
+	if len(scriptErrors) == 0 {
+		return
+	}
+	for _, err := range scriptErrors {
+		fmt.Fprintf(os.Stderr, "--press: %v\n", err)
+	}
+	os.Exit(1)
+}
+
 func snapshotHelpVisible(keys []string) bool {
 	for _, k := range keys {
 		if strings.EqualFold(k, "<f1>") || strings.EqualFold(k, "f1") {
@@ -186,15 +221,26 @@ var (
 	output          string // for rootCmd (default: table)
 	configOutput    string // for configCmd (default: yaml)
 	expression      string
+	queryLang       string // cel, jq
 	whereExpr       string
+	exprParams      []string // --param NAME=VALUE values for {{name}} placeholders
 	searchTerm      string
+	searchOutput    string // table, paths, count
 	themeName       string
 	configFile      string
 	configMode      bool
 	debug           bool
 	noColor         bool
+	colorMode       string // auto, always, never
 	arrayStyle      string // index, numbered, bullet, none
 	columnOrder     []string
+	summarySpecs    []string
+	widthPercentile int
+	sortByColumn    string
+	chunkSize       int
+	chunkSizeSet    bool // --chunk-size was given; otherwise performance.array_chunk_size
+	wrapValues      bool
+	ellipsisText    string
 	renderSnapshot  bool
 	helpInteractive bool //nolint:unused // preserved for tests
 	startKeys       []string
@@ -204,12 +250,25 @@ var (
 	limitRecords    int
 	offsetRecords   int
 	tailRecords     int
+	sampleRecords   int
+	sampleSeed      uint64 // --seed, or a random seed drawn when it is not given
 	sortOrder       string
 	schemaFile      string
+	invalidOnly     bool
 	keyMode         string // empty = use config, "vim"/"emacs"/"function" = override
 
 	// Parsed display schema (extracted from --schema JSON Schema x-kvx-* extensions)
 	parsedDisplaySchema *tui.DisplaySchema
+	// parsedSchemaDetail is the detail layout of a --schema JSON Schema,
+	// opened on an array element with the detail key.
+	parsedSchemaDetail *tui.DetailDisplayConfig
+	// parsedSchemaDocs holds the titles and descriptions of a --schema JSON
+	// Schema, shown for the selected key in the TUI.
+	parsedSchemaDocs *tui.SchemaDoc
+
+	// Column order and hidden columns of the table options, for the TUI's columnar view
+	tuiColumnOrder   []string
+	tuiHiddenColumns []string
 
 	// Tree output options
 	treeNoValues     bool
@@ -217,11 +276,71 @@ var (
 	treeExpandArrays bool
 	treeMaxStringLen int // 0 = auto; -1 = explicit unlimited; >0 = explicit limit
 
+	// YAML output options
+	yamlFidelity bool
+
+	// JSON/YAML layout options
+	indentWidth       int // 0 = formatting.yaml.indent for YAML, 2 for JSON
+	sortKeys          bool
+	sortKeysSet       bool // --sort-keys was given; otherwise keys follow --sort
+	compactJSON       bool
+	noTrailingNewline bool
+
 	// Mermaid output options
 	mermaidDirection string
 
+	// Env and shell output options
+	envPrefix      string
+	envStripPrefix bool
+	envKeyCase     string // "" = keep, "upper-snake"
+
 	// Decode options
 	autoDecode string // "" = manual only, "lazy" = on navigate, "eager" = at load
+
+	// Pager options
+	noPager bool
+
+	// Same output on every run, for snapshot comparisons in CI
+	deterministic bool
+
+	// View state options
+	noViewState bool // neither restore nor save the TUI view layout remembered per input
+
+	// Type-check -e/-w without loading input
+	checkExpr bool
+
+	// Picker options
+	pickMode  string // "" = off, "value" or "path" = print what enter picks in the TUI
+	pickMulti bool   // space marks several rows to pick
+
+	// Annotations (a in the TUI)
+	annotationsFile string // JSON file annotations are loaded from and saved to; "" prints them on exit
+
+	// Read-only TUI for dashboards and demos
+	kiosk bool
+
+	// Recording and replaying the keys of a TUI session
+	recordKeysFile string // --record-keys: file every input event is logged to
+	sessionSummary string // --session-summary: print or save the stats of the TUI session on exit
+	replayKeysFile string // --replay-keys: recording replayed on the same input
+
+	// Idle exit of the TUI
+	idleTimeout    time.Duration
+	idleTimeoutSet bool // --timeout was given; otherwise ui.behavior.idle_timeout applies
+	idlePrint      bool
+
+	// Reading the input again in the TUI
+	refreshInterval time.Duration
+
+	// Reading NDJSON input while the TUI shows it
+	streamInput bool
+
+	// Reading the input again in the TUI when the file changes
+	watchInput bool
+
+	// Named layouts (ui.layouts in the config)
+	layoutName     string // --layout: layout to open with
+	saveLayoutName string // --save-layout: layout to save the session as on exit
 )
 
 var (
@@ -377,6 +496,17 @@ func isCSVFile(filePath string) bool {
 // It returns the parsed root object and whether stdin was used.
 // The logger is forwarded to the loader so fallback parse attempts are logged.
 func loadInputData(args []string, expr string, debugLog bool, dc *debugCollector, lgr logr.Logger) (interface{}, bool, error) {
+	doc, fromStdin, err := loadInputDocument(args, expr, loader.DocumentOptions{}, debugLog, dc, lgr)
+	if err != nil {
+		return nil, fromStdin, err
+	}
+	return doc.Root, fromStdin, nil
+}
+
+// loadInputDocument is loadInputData returning the loaded document, which
+// also holds the source positions and YAML source nodes record asks for.
+// Key order and logging are set here.
+func loadInputDocument(args []string, expr string, record loader.DocumentOptions, debugLog bool, dc *debugCollector, lgr logr.Logger) (*loader.Document, bool, error) {
 	var data []byte
 	var fromStdin bool
 	var err error
@@ -475,7 +605,7 @@ func loadInputData(args []string, expr string, debugLog bool, dc *debugCollector
 		if debugLog {
 			dc.Printf("DBG: Parsed successfully, root type: %T\n", root)
 		}
-		return root, fromStdin, nil
+		return &loader.Document{Root: root}, fromStdin, nil
 	}
 
 	// Parse using the loader which honours file extensions, applies
@@ -483,14 +613,22 @@ func loadInputData(args []string, expr string, debugLog bool, dc *debugCollector
 	if debugLog {
 		dc.Println("DBG: Parsing input (auto-detect with fallback)...")
 	}
+	// Key order is only recorded when it is displayed; it belongs to this
+	// document and replaces the order of any previously loaded one.
+	opts := record
+	opts.KeyOrder = navigator.CurrentSortOrder() == navigator.SortInsertion || keepsDocumentKeyOrder()
+	opts.Logger = lgr
+	var doc *loader.Document
 	if filePath != "" {
-		root, err = core.LoadFileWithLogger(filePath, lgr)
+		doc, err = loader.LoadFileDocument(filePath, opts)
 	} else {
-		root, err = core.LoadRootBytesWithLogger(data, lgr)
+		doc, err = loader.LoadDocument(string(data), opts)
 	}
 	if err != nil {
 		return nil, fromStdin, fmt.Errorf("failed to parse input: %w", err)
 	}
+	navigator.SetDocumentOrder(doc.Order)
+	root = doc.Root
 	if debugLog {
 		switch v := root.(type) {
 		case []interface{}:
@@ -499,19 +637,20 @@ func loadInputData(args []string, expr string, debugLog bool, dc *debugCollector
 			dc.Println("DBG: Parsed 1 document")
 		}
 	}
-	return root, fromStdin, nil
+	return doc, fromStdin, nil
 }
 
 // renderBorderedTable creates a bordered table view without the shell panels.
 // Shows just the table with top border (title), data, and bottom border (footer).
 // widthHint allows callers to respect --width flags when stdout size is not the desired width.
 // path shows the current CEL path in the footer (left side)
-func renderBorderedTable(node interface{}, noColor bool, keyColWidth, valueColWidth int, widthHint int, appName string, path string) string {
-	return renderBorderedTableWithOptions(node, noColor, keyColWidth, valueColWidth, widthHint, appName, path, formatter.DefaultTableFormatOptions())
+func renderBorderedTable(node interface{}, keyColWidth, valueColWidth int, widthHint int, appName string, path string) string {
+	return renderBorderedTableWithOptions(node, keyColWidth, valueColWidth, widthHint, appName, path, formatter.DefaultTableFormatOptions())
 }
 
 // renderBorderedTableWithOptions renders a KEY/VALUE table with array style and columnar options.
-func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth, valueColWidth int, widthHint int, appName string, path string, tableOpts formatter.TableFormatOptions) string {
+func renderBorderedTableWithOptions(node interface{}, keyColWidth, valueColWidth int, widthHint int, appName string, path string, tableOpts formatter.TableFormatOptions) string {
+	plain := plainOutput()
 	termWidth := widthHint
 	if termWidth <= 0 {
 		w, _ := detectTerminalSize()
@@ -575,11 +714,13 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 			KeyColor:       th.KeyColor,
 			ValueColor:     th.ValueColor,
 			SeparatorColor: th.SeparatorColor,
+			OKColor:        th.StatusSuccess,
+			ErrorColor:     th.StatusError,
 		})
-		tableView = formatter.RenderTableFitContent(rows, noColor, tableWidth-2, tableOpts.ColumnOrder)
+		tableView = formatter.RenderTableFitContent(rows, plain, tableWidth-2, tableOpts.ColumnOrder)
 	} else {
 		// Use standard layout-based rendering for wide data
-		tableView = renderTableFromNode(node, noColor, keyColWidth, valueColWidth, tableWidth, tableOpts)
+		tableView = renderTableFromNode(node, keyColWidth, valueColWidth, tableWidth, tableOpts)
 	}
 
 	// Get theme colors for header and footer
@@ -591,8 +732,8 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 	// Center the app name
 	availableWidth := tableWidth - 4 // -4 for "╭─" and "─╮"
 	titleText := appName
-	leftDashes := (availableWidth - len(titleText)) / 2
-	rightDashes := availableWidth - len(titleText) - leftDashes
+	leftDashes := (availableWidth - lipgloss.Width(titleText)) / 2
+	rightDashes := availableWidth - lipgloss.Width(titleText) - leftDashes
 	topBorderLine := fmt.Sprintf("╭%s %s %s╮",
 		strings.Repeat(borderChar, leftDashes),
 		titleText,
@@ -600,7 +741,7 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 
 	// Apply theme colors to top border (foreground only, no background)
 	var borderStyle lipgloss.Style
-	if !noColor && theme.SeparatorColor != nil {
+	if !plain && theme.SeparatorColor != nil {
 		borderStyle = lipgloss.NewStyle().
 			Foreground(theme.SeparatorColor)
 		topBorderLine = borderStyle.Render(topBorderLine)
@@ -614,7 +755,7 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 
 	// Build bottom border with theme colors applied to match the TUI footer label styling.
 	var bottomBorderLine string
-	if !noColor {
+	if !plain {
 		leftText := pathWithSpace
 		rightText := countWithSpace
 		if theme.StatusColor != nil {
@@ -641,7 +782,7 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 	output := topBorderLine + "\n"
 
 	// Create style for side borders to match the TUI border color (foreground only).
-	if !noColor && theme.SeparatorColor != nil {
+	if !plain && theme.SeparatorColor != nil {
 		borderStyle = lipgloss.NewStyle().
 			Foreground(theme.SeparatorColor)
 	}
@@ -660,7 +801,7 @@ func renderBorderedTableWithOptions(node interface{}, noColor bool, keyColWidth,
 
 		// Add side borders with theme colors
 		borderedLine := "│" + line + "│"
-		if !noColor && theme.SeparatorColor != nil {
+		if !plain && theme.SeparatorColor != nil {
 			// Apply theme color only to the border characters
 			leftBorder := borderStyle.Render("│")
 			rightBorder := borderStyle.Render("│")
@@ -702,7 +843,8 @@ func autoKeyWidthFromRows(rows [][]string, maxPreset int) int {
 	return maxKey
 }
 
-func renderTableFromRows(rows [][]string, noColor bool, keyColWidth, valueColWidth int, widthHint int) string {
+func renderTableFromRows(rows [][]string, keyColWidth, valueColWidth int, widthHint int) string {
+	plain := plainOutput()
 	termWidth := widthHint
 	if termWidth <= 0 {
 		w, _ := detectTerminalSize()
@@ -729,15 +871,18 @@ func renderTableFromRows(rows [][]string, noColor bool, keyColWidth, valueColWid
 		KeyColor:       th.KeyColor,
 		ValueColor:     th.ValueColor,
 		SeparatorColor: th.SeparatorColor,
+		OKColor:        th.StatusSuccess,
+		ErrorColor:     th.StatusError,
 	})
-	output := formatter.RenderRows(rows, noColor, keyW, valueW)
+	output := formatter.RenderRows(rows, plain, keyW, valueW)
 	if !strings.HasSuffix(output, "\n") {
 		output += "\n"
 	}
 	return output
 }
 
-func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueColWidth int, widthHint int, appName string, path string, nodeForType interface{}) string {
+func renderBorderedTableRows(rows [][]string, keyColWidth, valueColWidth int, widthHint int, appName string, path string, nodeForType interface{}) string {
+	plain := plainOutput()
 	termWidth := widthHint
 	if termWidth <= 0 {
 		w, _ := detectTerminalSize()
@@ -765,7 +910,7 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 		tableWidth = minTableWidth
 	}
 
-	tableView := renderTableFromRows(rows, noColor, keyColWidth, valueColWidth, tableWidth)
+	tableView := renderTableFromRows(rows, keyColWidth, valueColWidth, tableWidth)
 
 	// Get theme colors for header and footer
 	theme := ui.CurrentTheme()
@@ -774,15 +919,15 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 
 	availableWidth := tableWidth - 4 // -4 for "╭─" and "─╮"
 	titleText := appName
-	leftDashes := (availableWidth - len(titleText)) / 2
-	rightDashes := availableWidth - len(titleText) - leftDashes
+	leftDashes := (availableWidth - lipgloss.Width(titleText)) / 2
+	rightDashes := availableWidth - lipgloss.Width(titleText) - leftDashes
 	topBorderLine := fmt.Sprintf("╭%s %s %s╮",
 		strings.Repeat(borderChar, leftDashes),
 		titleText,
 		strings.Repeat(borderChar, rightDashes))
 
 	var borderStyle lipgloss.Style
-	if !noColor && theme.SeparatorColor != nil {
+	if !plain && theme.SeparatorColor != nil {
 		borderStyle = lipgloss.NewStyle().
 			Foreground(theme.SeparatorColor)
 		topBorderLine = borderStyle.Render(topBorderLine)
@@ -809,7 +954,7 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 	}
 
 	var bottomBorderLine string
-	if !noColor {
+	if !plain {
 		leftText := pathWithSpace
 		rightText := countWithSpace
 		if theme.StatusColor != nil {
@@ -834,7 +979,7 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 	lines := strings.Split(tableView, "\n")
 	output := topBorderLine + "\n"
 
-	if !noColor && theme.SeparatorColor != nil {
+	if !plain && theme.SeparatorColor != nil {
 		borderStyle = lipgloss.NewStyle().
 			Foreground(theme.SeparatorColor)
 	}
@@ -849,7 +994,7 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 		}
 
 		borderedLine := "│" + line + "│"
-		if !noColor && theme.SeparatorColor != nil {
+		if !plain && theme.SeparatorColor != nil {
 			leftBorder := borderStyle.Render("│")
 			rightBorder := borderStyle.Render("│")
 			borderedLine = leftBorder + line + rightBorder
@@ -866,7 +1011,7 @@ func renderBorderedTableRows(rows [][]string, noColor bool, keyColWidth, valueCo
 
 // cliNodeTypeLabel maps a node to a simple CEL-like type label for CLI footer display.
 func cliNodeTypeLabel(node interface{}) string {
-	switch node.(type) {
+	switch v := node.(type) {
 	case []interface{}:
 		return "list"
 	case map[string]interface{}:
@@ -881,13 +1026,18 @@ func cliNodeTypeLabel(node interface{}) string {
 		return "uint"
 	case float32, float64:
 		return "double"
+	case json.Number:
+		return celhelper.NumberType(v)
+	case []byte:
+		return "bytes"
 	default:
 		return "any"
 	}
 }
 
 // renderColumnarBorderedTable renders a homogeneous array as a multi-column table with borders.
-func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int, appName string, path string, tableOpts formatter.TableFormatOptions) string {
+func renderColumnarBorderedTable(node interface{}, widthHint int, appName string, path string, tableOpts formatter.TableFormatOptions) string {
+	plain := plainOutput()
 	termWidth := widthHint
 	if termWidth <= 0 {
 		w, _ := detectTerminalSize()
@@ -902,15 +1052,24 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 	columns, rows := navigator.ExtractColumnarData(node, tableOpts.EffectiveColumnOrder())
 	if columns == nil {
 		// Fall back to regular table rendering
-		return renderBorderedTable(node, noColor, 0, 0, termWidth, appName, path)
+		return renderBorderedTable(node, 0, 0, termWidth, appName, path)
 	}
 
 	// When SelectColumns is set, hide any column not in the selected set.
 	tableOpts.ApplySelectColumns(columns)
 
+	summary, err := formatter.SummaryRow(columns, rows, node, tableOpts.Summary, ui.EvaluateExpression)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	widthRows := rows
+	if summary != nil {
+		widthRows = append(rows[:len(rows):len(rows)], summary)
+	}
+
 	// Calculate natural content width accounting for hidden columns and display name overrides
 	showRowNum := tableOpts.ArrayStyle != "none"
-	naturalContentWidth := formatter.CalculateNaturalColumnarWidthWithHints(columns, rows, showRowNum, len(rows), tableOpts.ColumnHints, tableOpts.HiddenColumns)
+	naturalContentWidth := formatter.CalculateNaturalColumnarWidthWithPercentile(columns, widthRows, showRowNum, len(rows), tableOpts.ColumnHints, tableOpts.HiddenColumns, tableOpts.WidthPercentile)
 
 	// Table width: use natural width if it fits, otherwise use terminal width.
 	// When any column has Flex: true, always fill the terminal width so that
@@ -942,16 +1101,23 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 		KeyColor:       th.KeyColor,
 		ValueColor:     th.ValueColor,
 		SeparatorColor: th.SeparatorColor,
+		OKColor:        th.StatusSuccess,
+		ErrorColor:     th.StatusError,
 	})
 
 	// Render columnar table (content only, we add borders)
 	tableView := formatter.RenderColumnarTable(columns, rows, formatter.ColumnarOptions{
-		NoColor:        noColor,
-		TotalWidth:     tableWidth - 2, // Content width (accounting for borders)
-		RowNumberStyle: tableOpts.ArrayStyle,
-		ColumnOrder:    tableOpts.ColumnOrder,
-		HiddenColumns:  tableOpts.HiddenColumns,
-		ColumnHints:    tableOpts.ColumnHints,
+		NoColor:         plain,
+		TotalWidth:      tableWidth - 2, // Content width (accounting for borders)
+		RowNumberStyle:  tableOpts.ArrayStyle,
+		ColumnOrder:     tableOpts.ColumnOrder,
+		HiddenColumns:   tableOpts.HiddenColumns,
+		ColumnHints:     tableOpts.ColumnHints,
+		Summary:         summary,
+		WidthPercentile: tableOpts.WidthPercentile,
+		TypeInference:   tableOpts.TypeInference,
+		SortBy:          tableOpts.SortBy,
+		SortDesc:        tableOpts.SortDesc,
 	})
 
 	// Add borders
@@ -964,15 +1130,15 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 	// Top border with app name
 	availableWidth := tableWidth - 4
 	titleText := appName
-	leftDashes := (availableWidth - len(titleText)) / 2
-	rightDashes := availableWidth - len(titleText) - leftDashes
+	leftDashes := (availableWidth - lipgloss.Width(titleText)) / 2
+	rightDashes := availableWidth - lipgloss.Width(titleText) - leftDashes
 	topBorderLine := fmt.Sprintf("╭%s %s %s╮",
 		strings.Repeat(borderChar, leftDashes),
 		titleText,
 		strings.Repeat(borderChar, rightDashes))
 
 	var borderStyle lipgloss.Style
-	if !noColor && theme.SeparatorColor != nil {
+	if !plain && theme.SeparatorColor != nil {
 		borderStyle = lipgloss.NewStyle().Foreground(theme.SeparatorColor)
 		topBorderLine = borderStyle.Render(topBorderLine)
 	}
@@ -984,7 +1150,7 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 	}
 
 	var bottomBorderLine string
-	if !noColor {
+	if !plain {
 		leftText := pathWithSpace
 		rightText := countWithSpace
 		if theme.StatusColor != nil {
@@ -1019,7 +1185,7 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 		}
 
 		borderedLine := "│" + line + "│"
-		if !noColor && theme.SeparatorColor != nil {
+		if !plain && theme.SeparatorColor != nil {
 			leftBorder := borderStyle.Render("│")
 			rightBorder := borderStyle.Render("│")
 			borderedLine = leftBorder + line + rightBorder
@@ -1037,10 +1203,11 @@ func renderColumnarBorderedTable(node interface{}, noColor bool, widthHint int,
 // renderTableFromNode creates a minimal TUI model and renders just the table component.
 // This ensures CLI table output uses the same rendering code as snapshot mode, honoring themes.
 // widthHint allows callers to respect --width flags when stdout size is not the desired width.
-func renderTableFromNode(node interface{}, noColor bool, keyColWidth, valueColWidth int, widthHint int, tableOpts formatter.TableFormatOptions) string {
+func renderTableFromNode(node interface{}, keyColWidth, valueColWidth int, widthHint int, tableOpts formatter.TableFormatOptions) string {
+	plain := plainOutput()
 	// Create a minimal model for table rendering
 	m := ui.InitialModel(node)
-	m.NoColor = noColor
+	m.NoColor = plain
 	m.Root = node
 	m.Node = node
 
@@ -1090,7 +1257,7 @@ func renderTableFromNode(node interface{}, noColor bool, keyColWidth, valueColWi
 		valueW--
 	}
 	output := tui.RenderTable(node, tui.TableOptions{
-		NoColor:       noColor,
+		NoColor:       plain,
 		KeyColWidth:   keyW,
 		ValueColWidth: valueW,
 		Width:         termWidth,
@@ -1119,6 +1286,56 @@ func effectiveKeyMode(cfg ui.ThemeConfigFile) ui.KeyMode {
 	return ui.DefaultKeyMode
 }
 
+// hyperlinksEnabled reports whether URL values should be rendered as OSC 8
+// hyperlinks. tty says whether the output goes to a terminal. Setting
+// ui.features.hyperlinks to false always wins; otherwise FORCE_HYPERLINK=1 or
+// FORCE_HYPERLINK=0 overrides terminal detection.
+func hyperlinksEnabled(cfg ui.ThemeConfigFile, tty bool) bool {
+	if cfg.Features.Hyperlinks != nil && !*cfg.Features.Hyperlinks {
+		return false
+	}
+	if v := os.Getenv("FORCE_HYPERLINK"); v != "" {
+		return v != "0"
+	}
+	if !tty || plainOutput() {
+		return false
+	}
+	// The Linux console and dumb terminals print OSC 8 sequences as garbage;
+	// terminals that do not know them otherwise ignore them.
+	switch os.Getenv("TERM") {
+	case "dumb", "linux":
+		return false
+	}
+	return true
+}
+
+// applySchemaToModel gives the model the display schema, detail layout, and
+// docs that tableFormatOptionsFromConfig parsed from --schema or the config.
+func applySchemaToModel(m *ui.Model) {
+	if parsedDisplaySchema != nil {
+		m.DisplaySchema = parsedDisplaySchema
+	}
+	m.SchemaDetail = parsedSchemaDetail
+	m.SchemaDocs = parsedSchemaDocs
+}
+
+// idleSettings returns the idle timeout of the TUI and whether an idle exit
+// prints the current expression result: --timeout and --timeout-print,
+// else ui.behavior.idle_timeout and idle_print.
+func idleSettings(cfg ui.ThemeConfigFile) (time.Duration, bool) {
+	timeout := idleTimeout
+	if !idleTimeoutSet && cfg.Behavior.IdleTimeout != nil && *cfg.Behavior.IdleTimeout != "" {
+		d, err := time.ParseDuration(*cfg.Behavior.IdleTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: ignoring ui.behavior.idle_timeout %q (expected a duration such as 30m)\n", *cfg.Behavior.IdleTimeout)
+		} else {
+			timeout = d
+		}
+	}
+	printResult := idlePrint || (cfg.Behavior.IdlePrint != nil && *cfg.Behavior.IdlePrint)
+	return timeout, printResult
+}
+
 func applySnapshotConfigToModel(m *ui.Model, cfg ui.ThemeConfigFile) {
 	if m == nil {
 		return
@@ -1135,6 +1352,21 @@ func applySnapshotConfigToModel(m *ui.Model, cfg ui.ThemeConfigFile) {
 	if cfg.Features.AllowIntellisense != nil {
 		m.AllowIntellisense = *cfg.Features.AllowIntellisense
 	}
+	if cfg.Features.Notify != nil {
+		m.Notify = *cfg.Features.Notify
+	}
+	if cfg.Features.Bell != nil && ui.IsValidBellMode(*cfg.Features.Bell) {
+		m.Bell = ui.BellMode(*cfg.Features.Bell)
+	}
+	if cfg.Features.VisualBell != nil {
+		m.VisualBell = *cfg.Features.VisualBell
+	}
+	m.IdleTimeout, m.IdlePrint = idleSettings(cfg)
+	if cfg.Intellisense.MatchMode != nil {
+		if err := m.SetCompletionMatchMode(*cfg.Intellisense.MatchMode); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: ignoring ui.intellisense.match_mode %q (expected fuzzy or prefix)\n", *cfg.Intellisense.MatchMode)
+		}
+	}
 	// Apply key mode: CLI flag > env var > config > default (vim)
 	if keyMode != "" && ui.IsValidKeyMode(keyMode) {
 		m.KeyMode = ui.KeyMode(keyMode)
@@ -1144,6 +1376,8 @@ func applySnapshotConfigToModel(m *ui.Model, cfg ui.ThemeConfigFile) {
 		m.KeyMode = ui.KeyMode(*cfg.Features.KeyMode)
 	}
 	// Note: default is already KeyModeVim from InitialModel
+	m.ColumnOrder = slices.Clone(tuiColumnOrder)
+	m.HiddenColumns = slices.Clone(tuiHiddenColumns)
 	if cfg.Display.KeyColWidth != nil {
 		m.ConfiguredKeyColWidth = *cfg.Display.KeyColWidth
 	}
@@ -1168,19 +1402,42 @@ func applySnapshotConfigToModel(m *ui.Model, cfg ui.ThemeConfigFile) {
 	if cfg.Performance.VirtualScrolling != nil {
 		m.VirtualScrolling = *cfg.Performance.VirtualScrolling
 	}
+	m.ChunkSize, m.ChunkThreshold = chunkSettings(cfg, m.ChunkSize, m.ChunkThreshold)
 	// Apply auto-decode setting from CLI flag
 	if autoDecode != "" {
 		m.AutoDecode = autoDecode
 	}
 }
 
-func printEvalResult(node interface{}, output string, noColor bool, keyColWidth, valueColWidth int, _ int, width int, appName string, path string, yamlOpts formatter.YAMLFormatOptions, tableOpts formatter.TableFormatOptions, treeOpts formatter.TreeOptions, mermaidOpts formatter.MermaidOptions, displaySchema *tui.DisplaySchema) {
+func printEvalResult(node interface{}, output string, keyColWidth, valueColWidth int, _ int, width int, appName string, path string, yamlOpts formatter.YAMLFormatOptions, tableOpts formatter.TableFormatOptions, treeOpts formatter.TreeOptions, mermaidOpts formatter.MermaidOptions, displaySchema *tui.DisplaySchema) {
+	if report := formatter.EnumReport(node, tableOpts.ColumnHints); report != "" {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", report)
+	}
+	if invalidOnly {
+		if formatter.HasEnumHints(tableOpts.ColumnHints) {
+			node = formatter.InvalidRows(node, tableOpts.ColumnHints)
+		} else {
+			fmt.Fprintln(os.Stderr, "warning: --invalid-only has no effect: the schema declares no enum values")
+		}
+	}
+	plain := plainOutput()
+	sp := startProgress("rendering " + output + " output")
+	defer sp.Stop()
+	bw := bufio.NewWriter(progressWriter{w: colorStdout(), sp: sp})
+	defer func() { _ = bw.Flush() }()
+	// exit flushes what was written so far and clears the spinner first.
+	exit := func(code int) {
+		_ = bw.Flush()
+		sp.Stop()
+		os.Exit(code)
+	}
+
 	switch output {
 	case "table":
 		// Schema-aware rendering for single objects with a detail schema.
 		if displaySchema != nil && displaySchema.Detail != nil {
 			if _, ok := node.(map[string]interface{}); ok {
-				fmt.Print(tui.RenderSchemaView(node, displaySchema, width, noColor)) //nolint:forbidigo
+				fmt.Fprint(bw, tui.RenderSchemaView(node, displaySchema, width, plain))
 				return
 			}
 		}
@@ -1191,47 +1448,45 @@ func printEvalResult(node interface{}, output string, noColor bool, keyColWidth,
 		case isSimpleArray:
 			// Print each scalar element on its own line
 			for _, elem := range node.([]interface{}) { //nolint:forcetypeassert
-				fmt.Println(formatter.StringifyPreserveNewlines(elem)) //nolint:forbidigo
+				fmt.Fprintln(bw, formatter.StringifyPreserveNewlines(elem))
 			}
 		case !isCollection:
-			fmt.Println(formatter.StringifyPreserveNewlines(node)) //nolint:forbidigo
+			fmt.Fprintln(bw, formatter.StringifyPreserveNewlines(node))
 		default:
+			var moreRows string
+			node, moreRows = chunkTable(node, tableOpts)
 			// Check if we should use columnar rendering for homogeneous arrays
 			if shouldUseColumnar(node, tableOpts.ColumnarMode) {
-				fmt.Print(renderColumnarBorderedTable(node, noColor, width, appName, path, tableOpts)) //nolint:forbidigo
+				printTable(bw, sp, renderColumnarBorderedTable(node, width, appName, path, tableOpts)+moreRows)
 			} else {
 				// Non-interactive mode: render bordered table with header and footer
-				fmt.Print(renderBorderedTableWithOptions(node, noColor, keyColWidth, valueColWidth, width, appName, path, tableOpts)) //nolint:forbidigo
+				printTable(bw, sp, renderBorderedTableWithOptions(node, keyColWidth, valueColWidth, width, appName, path, tableOpts)+moreRows)
 			}
 		}
 	case "csv":
-		fmt.Print(formatter.FormatAsCSV(node)) //nolint:forbidigo
+		fmt.Fprint(bw, formatter.FormatAsCSV(node))
 	case "yaml", "raw":
-		if s, err := formatter.FormatYAML(node, yamlOpts); err == nil {
-			fmt.Print(s) //nolint:forbidigo
-		} else {
+		if err := formatter.WriteYAML(bw, node, yamlOpts); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal yaml: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 	case "json":
-		if b, err := json.MarshalIndent(node, "", "  "); err == nil {
-			fmt.Print(string(b) + "\n") //nolint:forbidigo
-		} else {
+		if err := formatter.WriteJSON(bw, node, serializeOptionsFromFlags()); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to marshal json: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 	case "toml":
 		if s, err := formatter.FormatTOML(node); err == nil {
-			fmt.Print(s) //nolint:forbidigo
+			fmt.Fprint(bw, s)
 		} else {
 			fmt.Fprintf(os.Stderr, "failed to marshal toml: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 	case "auto":
 		// Schema-aware rendering for single objects with a detail schema.
 		if displaySchema != nil && displaySchema.Detail != nil {
 			if _, ok := node.(map[string]interface{}); ok {
-				fmt.Print(tui.RenderSchemaView(node, displaySchema, width, noColor)) //nolint:forbidigo
+				fmt.Fprint(bw, tui.RenderSchemaView(node, displaySchema, width, plain))
 				return
 			}
 		}
@@ -1241,16 +1496,18 @@ func printEvalResult(node interface{}, output string, noColor bool, keyColWidth,
 		switch {
 		case isSimpleArray:
 			for _, elem := range node.([]interface{}) { //nolint:forcetypeassert
-				fmt.Println(formatter.StringifyPreserveNewlines(elem)) //nolint:forbidigo
+				fmt.Fprintln(bw, formatter.StringifyPreserveNewlines(elem))
 			}
 		case !isCollection:
-			fmt.Println(formatter.StringifyPreserveNewlines(node)) //nolint:forbidigo
+			fmt.Fprintln(bw, formatter.StringifyPreserveNewlines(node))
 		default:
+			var moreRows string
+			node, moreRows = chunkTable(node, tableOpts)
 			if shouldUseColumnar(node, tableOpts.ColumnarMode) {
 				// When the display schema projects specific columns, skip the
 				// readability check — the schema author explicitly chose them.
 				if len(tableOpts.SelectColumns) > 0 {
-					fmt.Print(renderColumnarBorderedTable(node, noColor, width, appName, path, tableOpts)) //nolint:forbidigo
+					printTable(bw, sp, renderColumnarBorderedTable(node, width, appName, path, tableOpts)+moreRows)
 				} else {
 					// Check if columnar table is readable at current terminal width
 					termWidth := width
@@ -1266,49 +1523,96 @@ func printEvalResult(node interface{}, output string, noColor bool, keyColWidth,
 					columns, rows := navigator.ExtractColumnarData(node, tableOpts.EffectiveColumnOrder())
 					tableOpts.ApplySelectColumns(columns)
 					readableOpts := formatter.IsColumnarReadableOpts{
-						HiddenColumns:  tableOpts.HiddenColumns,
-						RowNumberStyle: tableOpts.ArrayStyle,
+						HiddenColumns:   tableOpts.HiddenColumns,
+						RowNumberStyle:  tableOpts.ArrayStyle,
+						WidthPercentile: tableOpts.WidthPercentile,
 					}
 					if columns != nil && !formatter.IsColumnarReadable(columns, rows, termWidth-2, tableOpts.ColumnHints, readableOpts) {
 						// Try dropping low-priority columns to keep table view
 						toDrop := formatter.ColumnsToDropForReadability(columns, rows, termWidth-2, tableOpts.ColumnHints, readableOpts)
 						if toDrop != nil {
 							tableOpts.HiddenColumns = append(tableOpts.HiddenColumns, toDrop...)
-							fmt.Print(renderColumnarBorderedTable(node, noColor, termWidth, appName, path, tableOpts)) //nolint:forbidigo
+							printTable(bw, sp, renderColumnarBorderedTable(node, termWidth, appName, path, tableOpts)+moreRows)
 						} else {
 							// Table would be unreadable even after dropping columns — fall back to list view
 							listOpts := formatter.ListOptions{
-								NoColor:       noColor,
+								NoColor:       plain,
 								ArrayStyle:    arrayStyle,
 								ColumnOrder:   tableOpts.ColumnOrder,
 								HiddenColumns: tableOpts.HiddenColumns,
 							}
-							fmt.Print(formatter.FormatAsList(node, listOpts)) //nolint:forbidigo
+							printTable(bw, sp, formatter.FormatAsList(node, listOpts)+moreRows)
 						}
 					} else {
-						fmt.Print(renderColumnarBorderedTable(node, noColor, termWidth, appName, path, tableOpts)) //nolint:forbidigo
+						printTable(bw, sp, renderColumnarBorderedTable(node, termWidth, appName, path, tableOpts)+moreRows)
 					}
 				}
 			} else {
-				fmt.Print(renderBorderedTableWithOptions(node, noColor, keyColWidth, valueColWidth, width, appName, path, tableOpts)) //nolint:forbidigo
+				printTable(bw, sp, renderBorderedTableWithOptions(node, keyColWidth, valueColWidth, width, appName, path, tableOpts)+moreRows)
 			}
 		}
 	case "list":
 		listOpts := formatter.ListOptions{
-			NoColor:       noColor,
+			NoColor:       plain,
 			ArrayStyle:    arrayStyle,
 			ColumnOrder:   tableOpts.ColumnOrder,
 			HiddenColumns: tableOpts.HiddenColumns,
 		}
-		fmt.Print(formatter.FormatAsList(node, listOpts)) //nolint:forbidigo
+		fmt.Fprint(bw, formatter.FormatAsList(node, listOpts))
 	case "tree":
-		fmt.Print(formatter.FormatAsTree(node, treeOpts)) //nolint:forbidigo
+		fmt.Fprint(bw, formatter.FormatAsTree(node, treeOpts))
 	case "mermaid":
-		fmt.Print(formatter.FormatAsMermaid(node, mermaidOpts)) //nolint:forbidigo
+		fmt.Fprint(bw, formatter.FormatAsMermaid(node, mermaidOpts))
+	case "env", "shell":
+		opts, err := envOptionsFromFlags()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exit(2)
+		}
+		format := formatter.FormatEnv
+		if output == "shell" {
+			format = formatter.FormatShell
+		}
+		if s, err := format(node, opts); err == nil {
+			fmt.Fprint(bw, s)
+		} else {
+			fmt.Fprintf(os.Stderr, "failed to format %s: %v\n", output, err)
+			exit(1)
+		}
 	default:
 		fmt.Fprintf(os.Stderr, "invalid output: %s\n", output)
-		os.Exit(2)
+		exit(2)
+	}
+}
+
+// chunkSettings returns the chunk size and threshold of large arrays from
+// the performance config, starting from the given defaults. --chunk-size
+// overrides the size.
+func chunkSettings(cfg ui.ThemeConfigFile, size, threshold int) (int, int) {
+	if cfg.Performance.ArrayChunkSize != nil {
+		size = *cfg.Performance.ArrayChunkSize
+	}
+	if cfg.Performance.ArrayChunkThreshold != nil {
+		threshold = *cfg.Performance.ArrayChunkThreshold
+	}
+	if chunkSizeSet {
+		size = chunkSize
+	}
+	return size, threshold
+}
+
+// chunkTable cuts a large array rendered as a table to its first chunk and
+// returns the sentinel row to print under it, or "". Summary rows need
+// every row, and so does sorting, so such tables are rendered whole.
+func chunkTable(node interface{}, opts formatter.TableFormatOptions) (interface{}, string) {
+	if len(opts.Summary) > 0 || opts.SortBy != "" {
+		return node, ""
 	}
+	shown, more := formatter.ChunkArray(node, opts.ChunkSize, opts.ChunkThreshold)
+	if more == 0 {
+		return node, ""
+	}
+	return shown, formatter.MoreRowsLine(more) + " (--chunk-size 0 prints them all)\n"
 }
 
 // shouldUseColumnar determines if columnar rendering should be used for the node.
@@ -1344,17 +1648,68 @@ func yamlFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.YAMLFormatOpt
 	if cfg.Formatting.YAML.ExpandEscapedNewlines != nil {
 		expandEscaped = *cfg.Formatting.YAML.ExpandEscapedNewlines
 	}
+	fidelity := yamlFidelity
+	if !fidelity && cfg.Formatting.YAML.Fidelity != nil {
+		fidelity = *cfg.Formatting.YAML.Fidelity
+	}
+	serialize := serializeOptionsFromFlags()
+	if serialize.Indent <= 0 {
+		serialize.Indent = indent
+	}
 	return formatter.YAMLFormatOptions{
-		Indent:                indent,
+		SerializeOptions:      serialize,
 		LiteralBlockStrings:   literal,
 		ExpandEscapedNewlines: expandEscaped,
+		PreserveSource:        fidelity,
 	}
 }
 
+// serializeOptionsFromFlags returns the layout of -o json and -o yaml
+// output set by --indent, --sort-keys, --compact, and --no-trailing-newline.
+func serializeOptionsFromFlags() formatter.SerializeOptions {
+	opts := formatter.SerializeOptions{
+		Indent:            indentWidth,
+		Compact:           compactJSON,
+		NoTrailingNewline: noTrailingNewline,
+	}
+	if sortKeysSet {
+		sorted := sortKeys
+		opts.SortKeys = &sorted
+	}
+	return opts
+}
+
+// envOptionsFromFlags returns the -o env and -o shell options of --prefix,
+// --strip-prefix, and --key-case.
+func envOptionsFromFlags() (formatter.EnvOptions, error) {
+	opts := formatter.EnvOptions{StripPrefix: envStripPrefix}
+	switch envKeyCase {
+	case "", "keep":
+	case formatter.KeyCaseUpperSnake:
+		opts.KeyCase = formatter.KeyCaseUpperSnake
+	default:
+		return opts, fmt.Errorf("invalid --key-case %q (expected keep or upper-snake)", envKeyCase)
+	}
+	steps, ok := flagPathSteps(envPrefix)
+	if !ok {
+		return opts, fmt.Errorf("invalid --prefix path %q", envPrefix)
+	}
+	opts.Prefix = steps
+	return opts, nil
+}
+
+// keepsDocumentKeyOrder reports whether --sort-keys=false asks for keys in
+// input order, which the loader then has to record.
+func keepsDocumentKeyOrder() bool {
+	return sortKeysSet && !sortKeys
+}
+
 func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatOptions {
 	// Reset any previously cached display schema so stale state from an
 	// earlier call does not leak into runs that do not specify a schema.
 	parsedDisplaySchema = nil
+	parsedSchemaDetail = nil
+	parsedSchemaDocs = nil
 
 	opts := formatter.DefaultTableFormatOptions()
 	if cfg.Formatting.Table.ArrayStyle != nil {
@@ -1373,6 +1728,39 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 	if len(cfg.Formatting.Table.HiddenColumns) > 0 {
 		opts.HiddenColumns = cfg.Formatting.Table.HiddenColumns
 	}
+	if cfg.Formatting.Table.WidthPercentile != nil {
+		opts.WidthPercentile = *cfg.Formatting.Table.WidthPercentile
+	}
+	// CLI --width-percentile flag overrides config
+	if widthPercentile > 0 {
+		opts.WidthPercentile = widthPercentile
+	}
+	opts.TypeInference = true
+	if sortByColumn != "" {
+		col, dir, _ := strings.Cut(sortByColumn, ":")
+		opts.SortBy = col
+		switch dir {
+		case "", "asc":
+		case "desc":
+			opts.SortDesc = true
+		default:
+			fmt.Fprintf(os.Stderr, "warning: ignoring --sort-by direction %q (expected asc or desc)\n", dir)
+		}
+	}
+	opts.ChunkSize, opts.ChunkThreshold = chunkSettings(cfg, 0, 0)
+	// CLI --summary entries are added to (and override) config summaries
+	if len(cfg.Formatting.Table.Summary) > 0 || len(summarySpecs) > 0 {
+		opts.Summary = make(map[string]string, len(cfg.Formatting.Table.Summary)+len(summarySpecs))
+		maps.Copy(opts.Summary, cfg.Formatting.Table.Summary)
+		for _, spec := range summarySpecs {
+			col, agg, ok := strings.Cut(spec, "=")
+			if !ok || strings.TrimSpace(col) == "" || strings.TrimSpace(agg) == "" {
+				fmt.Fprintf(os.Stderr, "warning: ignoring --summary %q (expected column=aggregate)\n", spec)
+				continue
+			}
+			opts.Summary[strings.TrimSpace(col)] = strings.TrimSpace(agg)
+		}
+	}
 
 	// Apply multi-line value cap from config, or reset to the formatter
 	// default so state from an earlier call cannot leak into this run.
@@ -1381,10 +1769,22 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 	} else {
 		formatter.SetMaxValueLines(formatter.DefaultMaxValueLines())
 	}
+	// CLI --wrap flag overrides config
+	formatter.SetWrapValues(wrapValues || (cfg.Formatting.Table.WrapValues != nil && *cfg.Formatting.Table.WrapValues))
+	// CLI --ellipsis flag overrides config; neither restores the default.
+	switch {
+	case ellipsisText != "":
+		formatter.SetEllipsis(ellipsisText)
+	case cfg.Formatting.Table.Ellipsis != nil:
+		formatter.SetEllipsis(*cfg.Formatting.Table.Ellipsis)
+	default:
+		formatter.SetEllipsis("")
+	}
 
 	// Load JSON Schema for column display hints.
 	// Priority: CLI --schema flag > config schema_file > config inline schema.
 	var schemaHints map[string]tui.ColumnHint
+	var schemaData []byte // JSON Schema the hints came from
 	schemaPath := schemaFile
 	if schemaPath == "" && cfg.Formatting.Table.SchemaFile != nil {
 		schemaPath = *cfg.Formatting.Table.SchemaFile
@@ -1404,6 +1804,8 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 				schemaHints, ds, err = tui.ParseSchemaWithDisplay(data)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "warning: cannot parse schema file %s: %v\n", schemaPath, err)
+				} else {
+					schemaData = data
 				}
 				if ds != nil {
 					parsedDisplaySchema = ds
@@ -1425,6 +1827,8 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 			schemaHints, ds, err = tui.ParseSchemaWithDisplay(data)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "warning: cannot parse inline schema: %v\n", err)
+			} else {
+				schemaData = data
 			}
 			if ds != nil {
 				parsedDisplaySchema = ds
@@ -1441,12 +1845,28 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 				DisplayName: h.DisplayName,
 				Hidden:      h.Hidden,
 				Flex:        h.Flex,
+				Wrap:        h.Wrap,
+				Enum:        h.Enum,
 			}
 			if h.Hidden {
 				opts.HiddenColumns = append(opts.HiddenColumns, k)
 			}
 		}
 	}
+	// x-kvx-columnOrder (as exported by the TUI column manager) orders the
+	// columns unless the config or --column-order already does.
+	if len(schemaData) > 0 && len(opts.ColumnOrder) == 0 {
+		if order, err := tui.ParseSchemaColumnOrder(schemaData); err == nil && len(order) > 0 {
+			opts.ColumnOrder = order
+		}
+	}
+	if len(schemaData) > 0 {
+		parsedSchemaDetail, _ = tui.ParseSchemaDetail(schemaData)
+		parsedSchemaDocs, _ = tui.ParseSchemaDocs(schemaData)
+	}
+	// Values of x-kvx-wrap fields wrap in KEY/VALUE tables; reset otherwise
+	// so state from an earlier call cannot leak into this run.
+	formatter.SetWrapKeys(formatter.WrapKeysFromHints(opts.ColumnHints))
 
 	// Derive column order and hidden columns from the display schema.
 	// This uses the same logic as tui.DeriveTableOptionsFromSchema but
@@ -1467,7 +1887,22 @@ func tableFormatOptionsFromConfig(cfg ui.ThemeConfigFile) formatter.TableFormatO
 				}
 			}
 		}
+		// Badge states color the cells of their columns.
+		if parsedDisplaySchema.List != nil {
+			for field, states := range parsedDisplaySchema.List.BadgeStates {
+				if opts.ColumnHints == nil {
+					opts.ColumnHints = map[string]formatter.ColumnHint{}
+				}
+				h := opts.ColumnHints[field]
+				h.States = states
+				opts.ColumnHints[field] = h
+			}
+		}
 	}
+
+	// --sort schema orders map keys by the same column order.
+	navigator.SetSchemaOrder(opts.EffectiveColumnOrder())
+	tuiColumnOrder, tuiHiddenColumns = opts.EffectiveColumnOrder(), opts.HiddenColumns
 	return opts
 }
 
@@ -1698,7 +2133,7 @@ func classifyNode(node interface{}) (isCollection bool, isSimpleArray bool) {
 // omits the required '_' root variable but the first path token exists at the root.
 func buildSuggestion(expr string, root interface{}) string {
 	// Only suggest when '_' is missing and the expression looks like a simple path
-	if strings.Contains(expr, "_") {
+	if queryLang == ui.QueryLangJQ || strings.Contains(expr, "_") {
 		return ""
 	}
 	// Special case: root is an array and user started with an index like "[0]"
@@ -1787,12 +2222,7 @@ const defaultFallbackTermWidth = 120
 
 // validateLimitingFlags checks that limiting flags are not in conflict and returns an error if they are.
 func validateLimitingFlags() error {
-	cfg := limiter.Config{
-		Limit:  limitRecords,
-		Offset: offsetRecords,
-		Tail:   tailRecords,
-	}
-	return cfg.Validate()
+	return limiterConfig().Validate()
 }
 
 // applyWhereFilter applies the --where per-item boolean filter if set.
@@ -1851,15 +2281,70 @@ func buildWhereHint(err error, expr string) string {
 
 // applyLimiting applies the record-limiting configuration to data.
 func applyLimiting(data interface{}) interface{} {
-	cfg := limiter.Config{
+	cfg := limiterConfig()
+	if !cfg.IsActive() {
+		return data
+	}
+	return cfg.Apply(data)
+}
+
+func limiterConfig() limiter.Config {
+	return limiter.Config{
 		Limit:  limitRecords,
 		Offset: offsetRecords,
 		Tail:   tailRecords,
+		Sample: sampleRecords,
+		Seed:   sampleSeed,
 	}
-	if !cfg.IsActive() {
-		return data
+}
+
+// yamlSourceNodes returns the YAML source nodes of the value expr reads from
+// the loaded input, so --yaml-fidelity can write it as it was written. It
+// returns nil when the output is not that value unchanged: the expression
+// computes it, or eager decoding, --where, or limiting rewrote the data.
+func yamlSourceNodes(src *yamlsource.Source, expr string) []*yaml.Node {
+	if src == nil || autoDecode == "eager" || whereExpr != "" || limiterConfig().IsActive() {
+		return nil
 	}
-	return cfg.Apply(data)
+	steps, ok := navigator.PathSteps(expr)
+	if queryLang == ui.QueryLangJQ {
+		steps, ok = jqPathSteps(expr)
+	}
+	if !ok {
+		return nil
+	}
+	nodes, _ := src.Lookup(steps)
+	return nodes
+}
+
+// jqPathSteps splits a jq program that only reads a path from the input,
+// such as `.items[0]."bad-key"`, into its keys and indexes like
+// navigator.PathSteps; "" is the root.
+func jqPathSteps(expr string) ([]string, bool) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, true
+	}
+	jqSteps, ok := jq.SplitPath(expr)
+	if !ok {
+		return nil, false
+	}
+	steps := make([]string, 0, len(jqSteps))
+	for _, step := range jqSteps {
+		if step.IsIndex {
+			steps = append(steps, strconv.Itoa(step.Index))
+		} else {
+			steps = append(steps, step.Key)
+		}
+	}
+	return steps, true
+}
+
+// evaluateExpression evaluates -e against root in the --query-lang language.
+func evaluateExpression(engine *core.Engine, expr string, root interface{}) (interface{}, error) {
+	if queryLang == ui.QueryLangJQ {
+		return core.EvaluateJQ(expr, root)
+	}
+	return engine.Evaluate(expr, root)
 }
 
 func parseSortOrder(value string) (navigator.SortOrder, error) {
@@ -1867,16 +2352,32 @@ func parseSortOrder(value string) (navigator.SortOrder, error) {
 	switch s {
 	case "", "none":
 		return navigator.SortNone, nil
-	case "asc", "ascending":
+	case "asc", "ascending", "alpha":
 		return navigator.SortAscending, nil
 	case "desc", "descending":
 		return navigator.SortDescending, nil
+	case "insertion", "original", "document":
+		return navigator.SortInsertion, nil
+	case "schema":
+		return navigator.SortSchema, nil
+	case "natural":
+		return navigator.SortNatural, nil
 	default:
-		return navigator.SortNone, fmt.Errorf("invalid sort order %q (expected ascending, descending, or none)", value)
+		return navigator.SortNone, fmt.Errorf("invalid sort order %q (expected ascending, descending, natural, insertion, schema, or none)", value)
 	}
 }
 
 func resolveSortOrder(cfg ui.ThemeConfigFile) (navigator.SortOrder, error) {
+	order, err := configuredSortOrder(cfg)
+	if err == nil && order == navigator.SortNone && deterministic {
+		// Unsorted rows follow map iteration order, which changes from run
+		// to run.
+		order = navigator.SortAscending
+	}
+	return order, err
+}
+
+func configuredSortOrder(cfg ui.ThemeConfigFile) (navigator.SortOrder, error) {
 	// CLI flag takes precedence when provided
 	if strings.TrimSpace(sortOrder) != "" {
 		return parseSortOrder(sortOrder)
@@ -1900,19 +2401,9 @@ func buildVersionData(cfg *ui.ThemeConfigFile) map[string]interface{} {
 	buildOS := runtime.GOOS
 	buildArch := runtime.GOARCH
 	gitCommit := ""
+	buildDate := ""
 
 	if ok {
-		if info.Main.Version != "" && info.Main.Version != "(devel)" {
-			version = info.Main.Version
-		} else {
-			for _, s := range info.Settings {
-				if s.Key == "vcs.revision" && len(s.Value) >= 7 {
-					gitCommit = s.Value[:7]
-					version = gitCommit
-					break
-				}
-			}
-		}
 		if info.GoVersion != "" {
 			goVersion = info.GoVersion
 		}
@@ -1923,8 +2414,29 @@ func buildVersionData(cfg *ui.ThemeConfigFile) map[string]interface{} {
 				buildOS = s.Value
 			case "GOARCH":
 				buildArch = s.Value
+			case "vcs.revision":
+				if len(s.Value) >= 7 {
+					gitCommit = s.Value[:7]
+				}
+			case "vcs.time":
+				buildDate = s.Value
 			}
 		}
+		if info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		} else if gitCommit != "" {
+			version = gitCommit
+		}
+	}
+	// Release builds stamp these through main; they win over build info.
+	if buildVersion != "" {
+		version = buildVersion
+	}
+	if buildCommit != "" {
+		gitCommit = buildCommit
+	}
+	if buildTime != "" {
+		buildDate = buildTime
 	}
 
 	name := "kvx"
@@ -1938,6 +2450,7 @@ func buildVersionData(cfg *ui.ThemeConfigFile) map[string]interface{} {
 		"BuildOS":   buildOS,
 		"BuildArch": buildArch,
 		"GitCommit": gitCommit,
+		"BuildDate": buildDate,
 		"Name":      name,
 	}
 }
@@ -1984,6 +2497,24 @@ func searchCLI(node interface{}, query string) map[string]interface{} {
 	return hits
 }
 
+// printSearchSummary prints the --search matches of node as paths (one per
+// line) or as a count when --search-output asks for it, and reports whether
+// it did.
+func printSearchSummary(node interface{}) bool {
+	switch searchOutput {
+	case "paths":
+		for _, p := range ui.SearchPaths(node, searchTerm) {
+			fmt.Println(p) //nolint:forbidigo
+		}
+		return true
+	case "count":
+		fmt.Println(len(ui.SearchPaths(node, searchTerm))) //nolint:forbidigo
+		return true
+	default:
+		return false
+	}
+}
+
 func joinSearchPath(prefix, seg string) string {
 	if prefix == "" {
 		return seg
@@ -1997,6 +2528,9 @@ func joinSearchPath(prefix, seg string) string {
 // detectTerminalSize returns the best-effort terminal width/height by probing
 // stdout, stderr, and stdin, then falling back to $COLUMNS.
 func detectTerminalSize() (int, int) {
+	if deterministic {
+		return ui.DeterministicWidth, ui.DeterministicHeight
+	}
 	fds := []uintptr{os.Stdout.Fd(), os.Stderr.Fd(), os.Stdin.Fd()}
 	for _, fd := range fds {
 		if w, h, err := term.GetSize(int(fd)); err == nil && (w > 0 || h > 0) {
@@ -2136,15 +2670,6 @@ func helpAboutHeader() string {
 	return processTemplateString(headerTemplate, templateData) + "\n"
 }
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print kvx version",
-	RunE: func(_ *cobra.Command, _ []string) error {
-		fmt.Println(cliVersionString()) //nolint:forbidigo
-		return nil
-	},
-}
-
 // configCmd groups configuration-related subcommands similar to gh-style CLIs.
 var configCmd = &cobra.Command{
 	Use:   "config",
@@ -2278,7 +2803,12 @@ func runConfigView(_ *cobra.Command) error {
 				}
 			}
 		}
-		fmt.Print(renderBorderedTable(obj, noColor, keyW, valueW, outputWidth, appName, "_")) //nolint:forbidigo
+		profile, err := resolveColorProfile()
+		if err != nil {
+			return err
+		}
+		colorProfile = profile
+		fmt.Fprint(colorStdout(), renderBorderedTable(obj, keyW, valueW, outputWidth, appName, "_"))
 		return nil
 	default:
 		return fmt.Errorf("invalid output for config: %s (use yaml|json|table|raw)", configOutput)
@@ -2330,9 +2860,11 @@ func runConfigGetInteractive(_ *cobra.Command) error {
 
 	if err := ui.RunModel(appName, root, helpTitle, helpText, false, sink, "", runW, runH, nil, false, "", nil, func(m *ui.Model) {
 		applySnapshotConfigToModel(m, mergedCfg)
-		if parsedDisplaySchema != nil {
-			m.DisplaySchema = parsedDisplaySchema
-		}
+		applySchemaToModel(m)
+		configureKiosk(m)
+		configureKeyLog(m)
+		m.SetDeterministic(deterministic)
+		configureSessionSummary(m)
 	}, opts...); err != nil {
 		return err
 	}
@@ -2341,10 +2873,11 @@ func runConfigGetInteractive(_ *cobra.Command) error {
 
 // getProgramOptions handles piped stdin by reopening the terminal for interactive input/output.
 // This allows Bubble Tea to work properly with piped data while still receiving keyboard input
-// and resize events on platforms like Windows.
+// and resize events on platforms like Windows. With --pick, captured stdout is handled the same
+// way, so the TUI draws on the terminal and only the pick reaches stdout.
 // Returns tea.ProgramOption values (plus a cleanup) that should be passed to tea.NewProgram.
 func getProgramOptions() ([]tea.ProgramOption, func()) {
-	isPiped := stdinIsPiped()
+	isPiped := stdinIsPiped() || (pickMode != "" && stdoutIsPiped())
 	cleanup := func() {}
 
 	if !isPiped {
@@ -2460,11 +2993,18 @@ var rootCmd = &cobra.Command{
 		rootCtx = logger.WithLogger(context.Background(), lgr)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		sortKeysSet = cmd.Flags().Changed("sort-keys")
+		idleTimeoutSet = cmd.Flags().Changed("timeout")
+		chunkSizeSet = cmd.Flags().Changed("chunk-size")
 		// Validate record-limiting flags first
 		if err := validateLimitingFlags(); err != nil {
 			fmt.Fprintf(os.Stderr, "record limiting error: %v\n", err)
 			os.Exit(2)
 		}
+		applyDeterministic()
+		if !cmd.Flags().Changed("seed") && !deterministic {
+			sampleSeed = rand.Uint64() //nolint:gosec // sampling, not security
+		}
 
 		// Validate array-style flag
 		if err := formatter.ValidateArrayStyle(arrayStyle); err != nil {
@@ -2472,24 +3012,103 @@ var rootCmd = &cobra.Command{
 			os.Exit(2)
 		}
 
+		// Validate search-output flag
+		if searchOutput != "table" && searchOutput != "paths" && searchOutput != "count" {
+			fmt.Fprintf(os.Stderr, "Error: invalid --search-output value %q (expected 'table', 'paths', or 'count')\n", searchOutput)
+			os.Exit(2)
+		}
+
+		// Validate query-lang flag; the TUI's expression bar follows it too
+		if err := ui.SetQueryLang(queryLang); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --query-lang value %q (expected 'cel' or 'jq')\n", queryLang)
+			os.Exit(2)
+		}
+
 		// Validate auto-decode flag
 		if autoDecode != "" && autoDecode != "lazy" && autoDecode != "eager" && autoDecode != "disabled" {
 			fmt.Fprintf(os.Stderr, "Error: invalid --auto-decode value %q (expected 'lazy', 'eager', or 'disabled')\n", autoDecode)
 			os.Exit(2)
 		}
 
-		limitCfg := limiter.Config{
-			Limit:  limitRecords,
-			Offset: offsetRecords,
-			Tail:   tailRecords,
+		if pickMulti && pickMode == "" {
+			pickMode = "value"
+		}
+		if pickMode != "" {
+			if err := validatePickFlags(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			interactive = true
+		}
+
+		if err := validateRefreshFlags(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		if err := validateStreamFlags(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
 		}
-		ui.SetLimiterConfig(limitCfg)
+
+		if err := validateWatchFlags(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		if (layoutName != "" || saveLayoutName != "" || refreshInterval > 0 || streamInput || watchInput) && !renderSnapshot {
+			interactive = true
+		}
+
+		if kiosk {
+			if err := validateKioskFlags(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			if !renderSnapshot {
+				interactive = true
+			}
+		}
+
+		if recordKeysFile != "" || replayKeysFile != "" {
+			closeKeyLog, err := prepareKeyLog()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			defer closeKeyLog()
+			if !renderSnapshot {
+				interactive = true
+			}
+		}
+
+		if err := validateSessionSummary(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		if checkExpr {
+			if err := runCheckExpr(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			return
+		}
+
+		ui.SetLimiterConfig(limiterConfig())
 
 		// Snapshot rendering is handled separately.
 		if renderSnapshot {
 			interactive = false
 		}
 
+		profile, colorErr := resolveColorProfile()
+		if colorErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", colorErr)
+			os.Exit(2)
+		}
+		colorProfile = profile
+
 		themeFlagSet := cmd.Flags().Changed("theme")
 		debugLog := debug
 		if interactive && strings.TrimSpace(searchTerm) != "" {
@@ -2550,20 +3169,50 @@ var rootCmd = &cobra.Command{
 				printThemeSelectionError(os.Stderr, err)
 				os.Exit(2)
 			}
+			layout, err := resolveLayout(cfg)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			applyLayout(layout, &cfg)
 			order, err := resolveSortOrder(cfg)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, err)
 				os.Exit(2)
 			}
 			navigator.SetSortOrder(order)
+			// Snapshots ignore the saved layout so their output is reproducible,
+			// and --layout replaces it.
+			var view savedView
+			if interactive && !renderSnapshot && layoutName == "" {
+				view = loadSavedView(args, cfg)
+				view.applySort()
+			}
+			formatter.SetHyperlinks(interactive && hyperlinksEnabled(cfg, true))
 			if menuHasData(cfg.Menu) {
 				ui.SetMenuConfig(ui.MenuFromConfig(cfg.Menu, cfg.Features.AllowEditInput))
 			}
+			if kiosk {
+				ui.SetMenuConfig(ui.KioskMenu(ui.CurrentMenuConfig()))
+			}
 			appName := cfg.About.Name
 			if appName == "" {
 				appName = "kvx"
 			}
-			rootData, _, err := loadInputData(args, expression, debugLog, dc, *logger.FromContext(rootCtx))
+			// Record where each value was written so the status bar can show it.
+			// Snapshots leave it off so their output does not depend on the file.
+			// Positions would go stale when --refresh or --watch reads the file again.
+			record := loader.DocumentOptions{
+				Positions:  interactive && refreshInterval == 0 && !watchInput,
+				YAMLSource: yamlFormatOptionsFromConfig(cfg).PreserveSource,
+			}
+			var doc *loader.Document
+			var stream *ui.RecordStream
+			if streamInput {
+				doc, stream, err = startInputStream(args)
+			} else {
+				doc, _, err = loadInputDocument(args, expression, record, debugLog, dc, *logger.FromContext(rootCtx))
+			}
 			if err != nil {
 				if errors.Is(err, errShowHelp) {
 					// When --help was explicitly requested with -i, use empty data so
@@ -2571,7 +3220,7 @@ var rootCmd = &cobra.Command{
 					// back into cmd.Help().
 					helpFlag := cmd.Flags().Lookup("help")
 					if helpFlag != nil && helpFlag.Changed {
-						rootData = map[string]any{}
+						doc = &loader.Document{Root: map[string]any{}}
 					} else {
 						_ = cmd.Help()
 						return
@@ -2581,9 +3230,11 @@ var rootCmd = &cobra.Command{
 					os.Exit(2)
 				}
 			}
+			rootData := doc.Root
 			// Eager auto-decode: recursively decode all serialized scalars before
 			// expression evaluation so that CEL can see the decoded structures.
-			if autoDecode == "eager" {
+			// Streamed records are decoded and filtered as they arrive.
+			if autoDecode == "eager" && stream == nil {
 				rootData = loader.RecursiveDecode(rootData)
 			}
 
@@ -2594,8 +3245,14 @@ var rootCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "failed to init evaluator: %v\n", err)
 				os.Exit(1)
 			}
+			if err := expandPlaceholders(engine, rootData); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
 
-			rootData = applyWhereFilter(engine, rootData, debugLog, dc)
+			if stream == nil {
+				rootData = applyWhereFilter(engine, rootData, debugLog, dc)
+			}
 
 			// Evaluate expression for snapshot parity with CLI limiting
 			snapshotNode := rootData
@@ -2603,7 +3260,7 @@ var rootCmd = &cobra.Command{
 				if debug {
 					dc.Printf("DBG: Evaluating expression for snapshot: %s\n", expression)
 				}
-				n, err := engine.Evaluate(expression, rootData)
+				n, err := evaluateExpression(engine, expression, rootData)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "explore expression error: %v\n", err)
 					if hint := buildSuggestion(expression, rootData); hint != "" {
@@ -2636,11 +3293,12 @@ var rootCmd = &cobra.Command{
 
 			// Support snapshot rendering
 			if renderSnapshot {
-				view := renderSnapshotOutput(cfg, snapshotNode, snapshotNode, appName, startKeys, expression, noColor, snapshotWidth, snapshotHeight, detectedTermWidth, detectedTermHeight, configFile, debugLog, dc, "", effectiveKeyMode(cfg))
-				fmt.Print(view) //nolint:forbidigo
+				view := renderSnapshotOutput(cfg, snapshotNode, snapshotNode, appName, startKeys, expression, snapshotWidth, snapshotHeight, detectedTermWidth, detectedTermHeight, configFile, debugLog, dc, "", effectiveKeyMode(cfg))
+				fmt.Fprint(colorStdout(), view)
 				if debugLog {
 					printDebugEvents(dc.events)
 				}
+				exitOnScriptErrors()
 				return
 			}
 
@@ -2672,7 +3330,7 @@ var rootCmd = &cobra.Command{
 						fmt.Fprintf(os.Stderr, "failed to init evaluator: %v\n", err)
 						os.Exit(1)
 					}
-					n, err := engine.Evaluate(expression, rootData)
+					n, err := evaluateExpression(engine, expression, rootData)
 					if err != nil {
 						fmt.Fprintf(os.Stderr, "explore expression error: %v\n", err)
 						if hint := buildSuggestion(expression, rootData); hint != "" {
@@ -2690,6 +3348,7 @@ var rootCmd = &cobra.Command{
 
 				// Apply record limiting after expression evaluation
 				node = applyLimiting(node)
+				yamlSource := yamlSourceNodes(doc.YAML, expression)
 
 				// Get column widths from config (or use defaults)
 				keyW := 30
@@ -2720,18 +3379,22 @@ var rootCmd = &cobra.Command{
 					if debug {
 						dc.Printf("DBG: Running CLI search for %q\n", searchTerm)
 					}
-					rows := ui.SearchRows(node, searchTerm)
-					if len(rows) == 0 {
-						fmt.Println("No matches found.") //nolint:forbidigo
-					} else {
-						// Non-interactive search: render bordered table
-						outputWidth := snapshotWidth
-						if outputWidth <= 0 {
-							if detectedTermWidth > 0 {
-								outputWidth = detectedTermWidth
+					// --search-output paths or count print on their own
+					// and skip the row search.
+					if !printSearchSummary(node) {
+						rows := ui.SearchRows(node, searchTerm)
+						if len(rows) == 0 {
+							fmt.Println("No matches found.") //nolint:forbidigo
+						} else {
+							// Non-interactive search: render bordered table
+							outputWidth := snapshotWidth
+							if outputWidth <= 0 {
+								if detectedTermWidth > 0 {
+									outputWidth = detectedTermWidth
+								}
 							}
+							fmt.Fprint(colorStdout(), renderBorderedTableRows(rows, keyW, valueW, outputWidth, appName, "_", node))
 						}
-						fmt.Print(renderBorderedTableRows(rows, noColor, keyW, valueW, outputWidth, appName, "_", node)) //nolint:forbidigo
 					}
 					if debugLog && len(dc.events) > 0 {
 						printDebugEvents(dc.events)
@@ -2745,11 +3408,12 @@ var rootCmd = &cobra.Command{
 					}
 				}
 				yamlOpts := yamlFormatOptionsFromConfig(cfg)
+				yamlOpts.Source = yamlSource
 				tableOpts := tableFormatOptionsFromConfig(cfg)
 				// Only apply status fallback for auto/table; explicit formats (json/yaml/csv) are honored
 				if output == "auto" || output == "table" {
 					if text, ok := renderPlainTextStatus(node, parsedDisplaySchema); ok {
-						fmt.Print(text) //nolint:forbidigo
+						fmt.Fprint(colorStdout(), text)
 						if debugLog && len(dc.events) > 0 {
 							printDebugEvents(dc.events)
 						}
@@ -2758,19 +3422,43 @@ var rootCmd = &cobra.Command{
 				}
 				treeOpts := treeFormatOptionsFromConfig(cfg, outputWidth, stdoutIsPiped())
 				mermaidOpts := mermaidFormatOptionsFromConfig(cfg)
-				printEvalResult(node, output, noColor, keyW, valueW, outputHeight, outputWidth, appName, "_", yamlOpts, tableOpts, treeOpts, mermaidOpts, parsedDisplaySchema)
+				printEvalResult(node, output, keyW, valueW, outputHeight, outputWidth, appName, "_", yamlOpts, tableOpts, treeOpts, mermaidOpts, parsedDisplaySchema)
 				if debugLog && len(dc.events) > 0 {
 					printDebugEvents(dc.events)
 				}
 			}
+			loadedAnnotations, err := loadAnnotations(annotationsFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
 			helpTitle, helpText := loadHelp(configFile, effectiveKeyMode(cfg))
 			opts, cleanup := getProgramOptions()
 			defer cleanup()
-			if err := ui.RunModel(appName, rootData, helpTitle, helpText, debugLog, sink, expression, runW, runH, startKeys, noColor, "", nil, func(m *ui.Model) {
+			opts = append(opts, colorProgramOptions()...)
+			var picks []ui.Pick
+			var annotations []ui.Annotation
+			if err := ui.RunModel(appName, rootData, helpTitle, helpText, debugLog, sink, expression, runW, runH, startKeys, plainOutput(), "", nil, func(m *ui.Model) {
 				applySnapshotConfigToModel(m, cfg)
-				if parsedDisplaySchema != nil {
-					m.DisplaySchema = parsedDisplaySchema
+				m.Positions = doc.Positions
+				applySchemaToModel(m)
+				view.configure(m)
+				configureLayout(m, layout, order)
+				if refreshInterval > 0 {
+					m.Refresh = inputRefresher(args, record, *logger.FromContext(rootCtx))
+					m.RefreshInterval = refreshInterval
+				}
+				if watchInput {
+					m.WatchPath = args[0]
+					m.WatchLoad = inputRefresher(args, record, *logger.FromContext(rootCtx))
 				}
+				m.Stream = stream
+				configurePick(m, &picks)
+				configureAnnotations(m, loadedAnnotations, &annotations)
+				configureKiosk(m)
+				configureKeyLog(m)
+				m.SetDeterministic(deterministic)
+				configureSessionSummary(m)
 			}, opts...); err != nil {
 				fmt.Fprintln(os.Stderr, err)
 				os.Exit(1)
@@ -2778,6 +3466,23 @@ var rootCmd = &cobra.Command{
 			if debugLog {
 				printDebugEvents(dc.events)
 			}
+			// With --pick, stdout holds the picks, so annotations go to
+			// their file only.
+			if pickMode == "" || annotationsFile != "" {
+				if err := writeAnnotations(os.Stdout, annotations); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+			if pickMode != "" {
+				if err := writePicks(os.Stdout, picks); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				if len(picks) == 0 {
+					os.Exit(1)
+				}
+			}
 			return
 		}
 
@@ -2860,11 +3565,12 @@ var rootCmd = &cobra.Command{
 
 			if renderSnapshot {
 				limitedRoot := applyLimiting(root)
-				view := renderSnapshotOutput(mergedCfg, limitedRoot, root, appName, startKeys, expression, noColor, snapshotWidth, snapshotHeight, detectedTermWidth, detectedTermHeight, configFile, debugLog, dc, "config mode", effectiveKeyMode(mergedCfg))
-				fmt.Print(view) //nolint:forbidigo
+				view := renderSnapshotOutput(mergedCfg, limitedRoot, root, appName, startKeys, expression, snapshotWidth, snapshotHeight, detectedTermWidth, detectedTermHeight, configFile, debugLog, dc, "config mode", effectiveKeyMode(mergedCfg))
+				fmt.Fprint(colorStdout(), view)
 				if debugLog {
 					printDebugEvents(dc.events)
 				}
+				exitOnScriptErrors()
 				return
 			}
 
@@ -2876,11 +3582,14 @@ var rootCmd = &cobra.Command{
 				}
 				opts, cleanup := getProgramOptions()
 				defer cleanup()
-				if err := ui.RunModel(appName, root, helpTitle, helpText, debugLog, sink, expression, runW, runH, startKeys, noColor, "", nil, func(m *ui.Model) {
+				opts = append(opts, colorProgramOptions()...)
+				if err := ui.RunModel(appName, root, helpTitle, helpText, debugLog, sink, expression, runW, runH, startKeys, plainOutput(), "", nil, func(m *ui.Model) {
 					applySnapshotConfigToModel(m, mergedCfg)
-					if parsedDisplaySchema != nil {
-						m.DisplaySchema = parsedDisplaySchema
-					}
+					applySchemaToModel(m)
+					configureKiosk(m)
+					configureKeyLog(m)
+					m.SetDeterministic(deterministic)
+					configureSessionSummary(m)
 				}, opts...); err != nil {
 					fmt.Fprintln(os.Stderr, err)
 					os.Exit(1)
@@ -2925,7 +3634,7 @@ var rootCmd = &cobra.Command{
 						appName = "kvx"
 					}
 					// Render bordered table with footer parity (includes type label)
-					fmt.Print(renderBorderedTable(obj, noColor, keyW, valueW, outputWidth, appName, "_")) //nolint:forbidigo
+					fmt.Fprint(colorStdout(), renderBorderedTable(obj, keyW, valueW, outputWidth, appName, "_"))
 				case "json":
 					data, err := json.MarshalIndent(sanitized, "", "  ")
 					if err != nil {
@@ -2974,7 +3683,8 @@ var rootCmd = &cobra.Command{
 			cfg = cfgFile
 		}
 
-		root, _, err = loadInputData(args, expression, debugLog, dc, *logger.FromContext(rootCtx))
+		record := loader.DocumentOptions{YAMLSource: yamlFormatOptionsFromConfig(cfg).PreserveSource}
+		doc, _, err := loadInputDocument(args, expression, record, debugLog, dc, *logger.FromContext(rootCtx))
 		if err != nil {
 			if errors.Is(err, errShowHelp) {
 				_ = cmd.Help()
@@ -2983,6 +3693,7 @@ var rootCmd = &cobra.Command{
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(2)
 		}
+		root = doc.Root
 
 		// Eager auto-decode: recursively decode all serialized scalars before
 		// expression evaluation so that CEL can see the decoded structures.
@@ -2997,6 +3708,10 @@ var rootCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "failed to init evaluator: %v\n", err)
 			os.Exit(1)
 		}
+		if err := expandPlaceholders(engine, root); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
 
 		root = applyWhereFilter(engine, root, debugLog, dc)
 
@@ -3015,7 +3730,8 @@ var rootCmd = &cobra.Command{
 				dc.Printf("DBG: Evaluating expression: %s\n", expression)
 			}
 			// Strict CLI mode: evaluate explore as CEL; require explicit '_' or valid CEL
-			n, err := engine.Evaluate(expression, root)
+			// (or a jq program with --query-lang jq)
+			n, err := evaluateExpression(engine, expression, root)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "explore expression error: %v\n", err)
 				if hint := buildSuggestion(expression, root); hint != "" {
@@ -3031,11 +3747,14 @@ var rootCmd = &cobra.Command{
 			dc.Println("DBG: No expression provided, using root")
 		}
 
+		yamlSource := yamlSourceNodes(doc.YAML, expression)
+
 		// Lazy auto-decode: decode the expression result if it's a serialized scalar
 		if autoDecode == "lazy" {
 			if s, ok := node.(string); ok {
 				if decoded, ok := loader.TryDecode(s); ok {
 					node = decoded
+					yamlSource = nil
 				}
 			}
 		}
@@ -3089,13 +3808,16 @@ var rootCmd = &cobra.Command{
 			if debug {
 				dc.Printf("DBG: Running CLI search for %q\n", searchTerm)
 			}
+			if printSearchSummary(node) {
+				return
+			}
 			if output == "table" {
 				rows := ui.SearchRows(node, searchTerm)
 				if len(rows) == 0 {
 					fmt.Println("No matches found.") //nolint:forbidigo
 					return
 				}
-				fmt.Print(renderBorderedTableRows(rows, noColor, keyW, valueW, outputWidth, appNameVal, "_", node)) //nolint:forbidigo
+				fmt.Fprint(colorStdout(), renderBorderedTableRows(rows, keyW, valueW, outputWidth, appNameVal, "_", node))
 				if debugLog && len(dc.events) > 0 {
 					printDebugEvents(dc.events)
 				}
@@ -3108,14 +3830,17 @@ var rootCmd = &cobra.Command{
 			}
 			// Replace node with search results so output formatting (yaml/json/table) is honored
 			node = results
+			yamlSource = nil
 		}
 		yamlOpts := yamlFormatOptionsFromConfig(cfg)
+		yamlOpts.Source = yamlSource
 		tableOpts := tableFormatOptionsFromConfig(cfg)
+		formatter.SetHyperlinks(hyperlinksEnabled(cfg, !stdoutIsPiped()))
 		// If a status display schema is present and output is auto/table, render plain-text
 		// status output. Explicit formats (json/yaml/csv) are honored for pipeline compatibility.
 		if output == "auto" || output == "table" {
 			if text, ok := renderPlainTextStatus(node, parsedDisplaySchema); ok {
-				fmt.Print(text) //nolint:forbidigo
+				fmt.Fprint(colorStdout(), text)
 				if debugLog && len(dc.events) > 0 {
 					printDebugEvents(dc.events)
 				}
@@ -3124,7 +3849,7 @@ var rootCmd = &cobra.Command{
 		}
 		treeOpts := treeFormatOptionsFromConfig(cfg, outputWidth, stdoutIsPiped())
 		mermaidOpts := mermaidFormatOptionsFromConfig(cfg)
-		printEvalResult(node, output, noColor, keyW, valueW, outputHeight, outputWidth, appNameVal, "_", yamlOpts, tableOpts, treeOpts, mermaidOpts, parsedDisplaySchema)
+		printEvalResult(node, output, keyW, valueW, outputHeight, outputWidth, appNameVal, "_", yamlOpts, tableOpts, treeOpts, mermaidOpts, parsedDisplaySchema)
 		if debugLog && len(dc.events) > 0 {
 			printDebugEvents(dc.events)
 		}
@@ -3133,37 +3858,82 @@ var rootCmd = &cobra.Command{
 
 func init() { //nolint:gochecknoinits
 	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "start interactive TUI")
-	rootCmd.Flags().StringVarP(&output, "output", "o", "auto", "output format: auto|table|list|tree|mermaid|yaml|json|toml|csv|raw")
-	rootCmd.Flags().StringVarP(&expression, "expression", "e", "", "CEL expression using '_' as root. Examples: '_.items[0].name', 'type(_)'. For special keys use bracket notation: '_.metadata[\"bad-key\"]'.")
+	rootCmd.Flags().StringVar(&pickMode, "pick", "", "open the TUI as a picker: enter prints the selected value (--pick=path: its path) and exits; quitting without a pick exits 1")
+	rootCmd.Flags().Lookup("pick").NoOptDefVal = "value"
+	rootCmd.Flags().StringVar(&recordKeysFile, "record-keys", "", "log every key, paste, and resize of the TUI session to this file, one JSON event per line with its time, for bug reports; implies -i")
+	rootCmd.Flags().StringVar(&sessionSummary, "session-summary", "", "when the TUI exits, print the session's stats (time spent, expressions run, deepest path, slowest evaluation) to stderr, or with =save append them to sessions.jsonl in the state directory; nothing leaves the machine")
+	rootCmd.Flags().Lookup("session-summary").NoOptDefVal = "print"
+	rootCmd.Flags().StringVar(&replayKeysFile, "replay-keys", "", "replay a --record-keys file against the same input: at the recorded pace in the TUI (keys pressed meanwhile are ignored), or at once with --snapshot; implies -i")
+	rootCmd.Flags().BoolVar(&kiosk, "kiosk", false, "open the TUI read-only for dashboards and demos: navigation and search only, with no expression editing, output on quit, clipboard, editor, or browser; implies -i")
+	rootCmd.Flags().DurationVar(&idleTimeout, "timeout", 0, "exit the interactive TUI after this long without input, e.g. 30m (0 = never; default from ui.behavior.idle_timeout)")
+	rootCmd.Flags().DurationVar(&refreshInterval, "refresh", 0, "read the input file again every interval, e.g. 5s, and re-evaluate the current expression on it, for a live view of a changing file; implies -i")
+	rootCmd.Flags().BoolVar(&watchInput, "watch", false, "read the input file again whenever it changes and re-evaluate the current expression on it, keeping the current path and row, for a live view of a file being edited; implies -i")
+	rootCmd.Flags().BoolVar(&streamInput, "stream", false, "read NDJSON input (a file or stdin) record by record while the TUI shows the records read so far, for inputs too large to read whole; implies -i")
+	rootCmd.Flags().StringVar(&layoutName, "layout", "", "open with the named layout from ui.layouts in the config: its expression, sort, view mode, columns, and key column width (-e, --sort, and --column-order win); implies -i")
+	rootCmd.Flags().StringVar(&saveLayoutName, "save-layout", "", "on exit, save the expression, sort, view mode, and columns of the session to ui.layouts.NAME in the config file; implies -i")
+	rootCmd.Flags().BoolVar(&idlePrint, "timeout-print", false, "on a --timeout exit, print the result of the current expression like F10")
+	rootCmd.Flags().StringVar(&annotationsFile, "annotations", "", "JSON file of row annotations (a in the TUI) to resume from and save to on exit; without it, annotations are printed as JSON on exit")
+	rootCmd.Flags().BoolVar(&pickMulti, "multi", false, "with --pick, space marks rows and enter prints every marked one, one per line (a list with -o json/yaml); implies --pick")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "auto", "output format: auto|table|list|tree|mermaid|yaml|json|toml|csv|env|shell|raw. json and yaml stream the items of a top-level array; other formats are written once fully rendered")
+	rootCmd.Flags().StringVarP(&expression, "expression", "e", "", "CEL expression using '_' as root. Examples: '_.items[0].name', 'type(_)', '_.items | map(x, x.name)'. For special keys use bracket notation: '_.metadata[\"bad-key\"]'. With --query-lang jq, a jq program such as '.items[] | select(.available) | .name'.")
+	rootCmd.Flags().StringVar(&queryLang, "query-lang", "cel", "language of -e and the TUI expression bar: cel|jq (--where stays CEL)")
+	rootCmd.Flags().StringArrayVar(&exprParams, "param", nil, "NAME=VALUE: value of a {{NAME}} placeholder in -e or --where, instead of asking for it (repeatable)")
 	rootCmd.Flags().StringVarP(&whereExpr, "where", "w", "", "Per-item CEL boolean filter for list data. '_' refers to the current item. Example: '_.type == \"oci\"'")
 	rootCmd.Flags().StringVar(&searchTerm, "search", "", "Search keys and values (case-insensitive) and display matches")
+	rootCmd.Flags().StringVar(&searchOutput, "search-output", "table", "How --search prints matches: table|paths|count (paths prints one _-rooted path per line)")
 	// --sort requires a value; default comes from config (or none)
-	rootCmd.Flags().StringVar(&sortOrder, "sort", "", "Sort map keys: ascending|asc|descending|desc|none (default from config or none)")
+	rootCmd.Flags().StringVar(&sortOrder, "sort", "", "Sort map keys: ascending|asc|alpha|descending|desc|natural|insertion|schema|none (default from config or none)")
 	// No static default here so help doesn't misstate it; default comes from config
 	rootCmd.Flags().StringVar(&themeName, "theme", "", "theme name (default from config; see 'kvx themes')")
 	rootCmd.Flags().StringVar(&configFile, "config-file", "", "path to a YAML config file (themes, settings)")
 	rootCmd.Flags().BoolVar(&configMode, "config", false, "output the merged config (or view in TUI with -i)")
 	rootCmd.Flags().BoolVar(&debug, "debug", false, "show debug info in status bar")
 	rootCmd.Flags().IntVar(&debugMaxEvents, "debug-max-events", 200, "maximum number of debug events to keep (default: 200)")
-	rootCmd.Flags().BoolVar(&noColor, "no-color", false, "disable color output")
+	rootCmd.Flags().BoolVar(&noColor, "no-color", false, "disable color output (same as --color=never)")
+	rootCmd.Flags().StringVar(&colorMode, "color", "auto", "when to use colors: auto|always|never (auto honors NO_COLOR, FORCE_COLOR, CLICOLOR and CLICOLOR_FORCE)")
+	rootCmd.Flags().BoolVar(&deterministic, "deterministic", false, "render the same output on every run, for snapshot comparisons in CI: an 80x24 terminal unless --width/--height are given, no color unless --color=always, keys sorted instead of unordered, a fixed --sample seed, no pager or remembered view layout, and no blinking, spinning, or fading in the TUI")
+	rootCmd.Flags().BoolVar(&noPager, "no-pager", false, "do not pipe table output taller than the terminal into $PAGER (default less)")
+	rootCmd.Flags().BoolVar(&noViewState, "no-view-state", false, "do not restore or save the TUI view layout (view mode, columns, sort) remembered per input file name or schema")
 	rootCmd.Flags().StringVar(&arrayStyle, "array-style", "none", "Array index style: none, index, numbered, bullet")
+	rootCmd.Flags().BoolVar(&wrapValues, "wrap", false, "Wrap long values in KEY/VALUE tables instead of truncating them (toggle with w in the TUI)")
+	rootCmd.Flags().StringVar(&ellipsisText, "ellipsis", "", "Marker for values cut to fit a column, such as … (default \"...\")")
+	rootCmd.Flags().IntVar(&chunkSize, "chunk-size", 0, "Show arrays longer than performance.array_chunk_threshold this many items at a time (0 = whole arrays)")
+	rootCmd.Flags().StringVar(&sortByColumn, "sort-by", "", "Sort table rows by this column, as numbers, dates, or text in natural order (append :desc to reverse)")
+	rootCmd.Flags().IntVar(&widthPercentile, "width-percentile", 0, "Size table columns to this percentile of their value widths (e.g. 90), truncating outliers (0 = longest value)")
+	rootCmd.Flags().StringArrayVar(&summarySpecs, "summary", nil, "Add a footer row to columnar tables: column=count|sum|avg|min|max or column=<CEL over _> (repeatable)")
 	rootCmd.Flags().StringSliceVar(&columnOrder, "column-order", nil, "Preferred key display order (comma-separated). Keys not listed are appended alphabetically")
 	rootCmd.Flags().BoolVar(&renderSnapshot, "snapshot", false, "render a single TUI snapshot and exit (dev/test); honors --width/--height")
 	rootCmd.Flags().StringVar(&keyMode, "keymap", "", "keybinding mode: vim (default), emacs, or function")
-	rootCmd.Flags().StringArrayVar(&startKeys, "press", nil, "Simulate keys on startup. Use <Key> for special keys (e.g. <F3>, <F6>, <Enter>, <Esc>, <Tab>). Literal text types normally. Examples: --press \"<F3>search\" or --press \"<F6>_.items[0]\"")
+	rootCmd.Flags().StringArrayVar(&startKeys, "press", nil, "Simulate keys on startup. Use <Key> for special keys (e.g. <F3>, <F6>, <Enter>, <Esc>, <Tab>). Literal text types normally. <sleep:200ms> waits, <type:TEXT> types TEXT as is, and <expect:\"TEXT\"> fails a --snapshot (exit 1) unless the screen shows TEXT. Examples: --press \"<F3>search\" or --press \"<F6>_.items[0]\"")
 	rootCmd.Flags().IntVar(&snapshotWidth, "width", 0, "Output width in columns (affects formatting and TUI layout)")
 	rootCmd.Flags().IntVar(&snapshotHeight, "height", 0, "Output height in rows (affects formatting and TUI layout)")
 	rootCmd.Flags().IntVar(&limitRecords, "limit", 0, "Limit total number of records displayed")
+	rootCmd.Flags().IntVar(&limitRecords, "head", 0, "Show the first N records (same as --limit)")
 	rootCmd.Flags().IntVar(&offsetRecords, "offset", 0, "Skip the first N records")
 	rootCmd.Flags().IntVar(&tailRecords, "tail", 0, "Show the last N records (mutually exclusive with --limit; ignores --offset)")
+	rootCmd.Flags().IntVar(&sampleRecords, "sample", 0, "Show N records picked at random, in their original order (after --head, --offset, or --tail); L in the TUI loads all")
+	rootCmd.Flags().Uint64Var(&sampleSeed, "seed", 0, "Seed for --sample, to pick the same records again (default random)")
 	rootCmd.Flags().StringVar(&schemaFile, "schema", "", "path to a JSON Schema file for column/display hints; supports x-kvx-* extensions for card-list and detail views in interactive mode")
+	rootCmd.Flags().BoolVar(&invalidOnly, "invalid-only", false, "Print only the array items with a value outside its --schema enum (non-interactive output)")
 	// Tree output options
 	rootCmd.Flags().BoolVar(&treeNoValues, "tree-no-values", false, "Show structure only (hide values) in tree output")
 	rootCmd.Flags().IntVar(&treeMaxDepth, "tree-depth", 0, "Limit tree depth (0 = unlimited)")
 	rootCmd.Flags().BoolVar(&treeExpandArrays, "tree-expand-arrays", false, "Expand all array elements instead of showing inline/summary")
 	rootCmd.Flags().IntVar(&treeMaxStringLen, "tree-max-string", 0, "Max string length in tree output (0=auto, -1=unlimited)")
+	// YAML output options
+	rootCmd.Flags().BoolVar(&yamlFidelity, "yaml-fidelity", false, "Keep comments, anchors, key order, and quoting of YAML input in -o yaml output for the input or a plain path into it")
+	// JSON/YAML layout options
+	rootCmd.Flags().IntVar(&indentWidth, "indent", 0, "Spaces per indentation level in -o json/yaml output (0 = formatting.yaml.indent, or 2)")
+	rootCmd.Flags().BoolVar(&sortKeys, "sort-keys", false, "Sort object keys alphabetically in -o json/yaml output; --sort-keys=false keeps input order (default: follow --sort)")
+	rootCmd.Flags().BoolVar(&compactJSON, "compact", false, "Write -o json output on a single line")
+	rootCmd.Flags().BoolVar(&noTrailingNewline, "no-trailing-newline", false, "Omit the newline at the end of -o json/yaml output")
 	// Mermaid output options
 	rootCmd.Flags().StringVar(&mermaidDirection, "mermaid-direction", "TD", "Mermaid diagram direction: TD, LR, BT, RL")
+	// Env and shell output options
+	rootCmd.Flags().StringVar(&envPrefix, "prefix", "", "Only write the values below this path in -o env/shell output, e.g. services.api")
+	rootCmd.Flags().BoolVar(&envStripPrefix, "strip-prefix", false, "Leave the --prefix path out of -o env/shell keys")
+	rootCmd.Flags().StringVar(&envKeyCase, "key-case", "keep", "Case of -o env/shell keys: keep|upper-snake")
+	rootCmd.Flags().BoolVar(&checkExpr, "check-expr", false, "type-check -e and -w without reading input and exit; field types come from --schema when given")
 	rootCmd.Flags().StringVar(&autoDecode, "auto-decode", "", "Auto-decode serialized scalars: 'lazy' (on navigate), 'eager' (at load), or 'disabled' (default, manual via Enter)")
 	_ = rootCmd.Flags().MarkHidden("snapshot-width")
 	_ = rootCmd.Flags().MarkHidden("snapshot-height")
@@ -3171,7 +3941,49 @@ func init() { //nolint:gochecknoinits
 	_ = rootCmd.Flags().MarkHidden("config")
 	rootCmd.Version = cliVersionString()
 	rootCmd.SetVersionTemplate("{{.Version}}\n")
+	versionCmd.Flags().StringVarP(&versionOutput, "output", "o", "text", "output format: text|json|yaml")
 	rootCmd.AddCommand(versionCmd)
+	docsCmd.PersistentFlags().StringVarP(&docsDir, "dir", "d", ".", "directory to write the generated files to")
+	docsCmd.AddCommand(docsManCmd, docsMarkdownCmd)
+	rootCmd.AddCommand(docsCmd)
+	functionsCmd.Flags().StringVarP(&functionsOutput, "output", "o", "table", "output format: table|json|yaml")
+	functionsCmd.Flags().StringVarP(&functionsType, "type", "t", "", "only methods callable on this receiver type (string, list, map, timestamp, ...), or 'global'")
+	rootCmd.AddCommand(functionsCmd)
+	patchCmd.Flags().StringVar(&patchFrom, "from", "", "the original document (\"-\" for stdin)")
+	patchCmd.Flags().StringVar(&patchTo, "to", "", "the changed document (\"-\" for stdin)")
+	patchCmd.Flags().StringVarP(&patchExpression, "expression", "e", "", "CEL expression applied to both documents before comparing them")
+	patchCmd.Flags().BoolVar(&patchMerge, "merge", false, "print an RFC 7386 JSON Merge Patch instead of an RFC 6902 JSON Patch")
+	patchCmd.Flags().StringVarP(&patchOutput, "output", "o", "json", "output format: json|yaml")
+	patchCmd.Flags().StringSliceVar(&patchArrayKeys, "array-key", nil, "field identifying array elements, e.g. name or id; arrays whose elements all have distinct values for it are matched by it instead of by index (repeatable)")
+	patchCmd.Flags().StringVar(&patchSchema, "schema", "", "JSON Schema whose x-kvx-key array extensions name the fields identifying array elements")
+	rootCmd.AddCommand(patchCmd)
+	applyCmd.Flags().StringVar(&applyPatchFile, "patch", "", "the JSON Patch or merge patch to apply (\"-\" for stdin)")
+	applyCmd.Flags().BoolVar(&applyMerge, "merge", false, "apply the patch as an RFC 7386 JSON Merge Patch even if it is a list of operations")
+	applyCmd.Flags().StringVarP(&applyOutput, "output", "o", "", "output format: json|yaml|ndjson|toml (default: the document's format)")
+	rootCmd.AddCommand(applyCmd)
+	mergeCmd.Flags().StringVar(&mergeStrategy, "strategy", "override", "how values both documents set are combined: override|append|unique|error")
+	mergeCmd.Flags().StringArrayVar(&mergePathStrategy, "path-strategy", nil, "PATH=STRATEGY: strategy for one path and everything below it, e.g. spec.tags=append (repeatable)")
+	mergeCmd.Flags().StringSliceVar(&mergeArrayKeys, "array-key", nil, "field identifying array elements, e.g. name or id; such arrays of objects are merged element by element (repeatable)")
+	mergeCmd.Flags().StringVar(&mergeSchema, "schema", "", "JSON Schema whose x-kvx-key array extensions name the fields identifying array elements")
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "output format: json|yaml|ndjson|toml (default: the base document's format)")
+	rootCmd.AddCommand(mergeCmd)
+	dupesCmd.Flags().StringSliceVar(&dupesBy, "by", nil, "fields identifying a record, e.g. name,email or owner.email (default: whole records)")
+	dupesCmd.Flags().StringVarP(&dupesExpression, "expression", "e", "", "CEL expression selecting the array to check")
+	dupesCmd.Flags().BoolVar(&dupesUnique, "unique", false, "print the array without its duplicates, keeping the first record of each group")
+	dupesCmd.Flags().StringVarP(&dupesOutput, "output", "o", "", "output format: table|json|yaml, or with --unique json|yaml|ndjson|toml (default: table, or the document's format)")
+	rootCmd.AddCommand(dupesCmd)
+	tutorialCmd.Flags().StringVar(&keyMode, "keymap", "", "keybinding mode the tutorial teaches: vim (default), emacs, or function")
+	rootCmd.AddCommand(tutorialCmd)
+	sampleCmd.Flags().StringVar(&sampleShape, "shape", "users", "shape of the document: users|k8s|metrics")
+	sampleCmd.Flags().IntVar(&sampleRows, "rows", 10, "number of records to generate")
+	sampleCmd.Flags().Uint64Var(&sampleDataSeed, "seed", 0, "seed to generate the same document again (default random)")
+	sampleCmd.Flags().StringVarP(&sampleOutput, "output", "o", "json", "output format: json|yaml|ndjson|toml")
+	rootCmd.AddCommand(sampleCmd)
+	doctorCmd.Flags().StringVar(&configFile, "config-file", "", "path to the YAML config file to check (default: the one kvx loads)")
+	doctorCmd.Flags().StringVar(&themeName, "theme", "", "theme to check instead of the config's default")
+	doctorCmd.Flags().StringVar(&schemaFile, "schema", "", "JSON Schema file to check instead of the config's schema_file")
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "text", "output format: text|json|yaml")
+	rootCmd.AddCommand(doctorCmd)
 	// Wire config command group
 	// Provide --config-file for config commands
 	configCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "path to a YAML config file (themes, settings)")
@@ -3190,7 +4002,15 @@ func init() { //nolint:gochecknoinits
 	rootCmd.AddCommand(themesCmd)
 }
 
+// Execute runs the kvx command line, expanding a leading @alias first.
 func Execute() error {
+	args, err := expandAlias(os.Args[1:])
+	if err != nil {
+		return err
+	}
+	if args != nil {
+		rootCmd.SetArgs(args)
+	}
 	return rootCmd.Execute()
 }
 
@@ -3205,19 +4025,13 @@ func hasF10(keys []string) bool {
 }
 
 // resolveConfigPath returns the explicit configFile if set, otherwise the XDG path
-// ($XDG_CONFIG_HOME/kvx/config.yaml) or ~/.config/kvx/config.yaml if present.
+// ($XDG_CONFIG_HOME/kvx/config.yaml), ~/.config/kvx/config.yaml or, on Windows,
+// %APPDATA%\kvx\config.yaml if present.
 func resolveConfigPath(explicit string) string {
 	if explicit != "" {
-		return explicit
-	}
-	xdg := os.Getenv("XDG_CONFIG_HOME")
-	candidate := ""
-	if xdg != "" {
-		candidate = filepath.Join(xdg, "kvx", "config.yaml")
-	} else if home, err := os.UserHomeDir(); err == nil {
-		candidate = filepath.Join(home, ".config", "kvx", "config.yaml")
+		return ui.ExpandHome(explicit)
 	}
-	if candidate != "" {
+	for _, candidate := range ui.ConfigFileCandidates() {
 		if st, err := os.Stat(candidate); err == nil && !st.IsDir() {
 			return candidate
 		}
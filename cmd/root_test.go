@@ -19,6 +19,8 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
 	runewidth "github.com/mattn/go-runewidth"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
@@ -136,6 +138,7 @@ func resetRootCmdState() {
 	output = "auto"
 	expression = ""
 	whereExpr = ""
+	exprParams = nil
 	searchTerm = ""
 	debug = false
 	noColor = false
@@ -143,12 +146,17 @@ func resetRootCmdState() {
 	helpInteractive = false
 	configMode = false
 	columnOrder = nil
+	summarySpecs = nil
 	startKeys = nil
 	snapshotWidth = 0
 	snapshotHeight = 0
 	configFile = ""
 	themeName = ""
 	schemaFile = ""
+	keyRecorder, replayEvents = nil, nil
+	deterministic = false
+	streamInput = false
+	watchInput = false
 	ui.SetMenuConfig(ui.DefaultMenuConfig())
 
 	rootCmd.SetArgs(nil)
@@ -158,7 +166,9 @@ func resetRootCmdState() {
 	// StringSliceVar flags reset to "[]" which cobra parses as []string{"[]"};
 	// explicitly clear slice vars after VisitAll.
 	columnOrder = nil
+	summarySpecs = nil
 	startKeys = nil
+	exprParams = nil
 }
 
 func runCLI(t *testing.T, args []string) string {
@@ -183,6 +193,14 @@ func runCLI(t *testing.T, args []string) string {
 	})
 }
 
+// useColorProfile sets the run's color profile for the rest of the test.
+func useColorProfile(t *testing.T, p colorprofile.Profile) {
+	t.Helper()
+	orig := colorProfile
+	colorProfile = p
+	t.Cleanup(func() { colorProfile = orig })
+}
+
 func TestCLI_TablePrintsScalarPlain(t *testing.T) {
 	// kvx tests/sample.yaml --no-color -e '_.name'
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-e", "_.name"})
@@ -192,6 +210,15 @@ func TestCLI_TablePrintsScalarPlain(t *testing.T) {
 	}
 }
 
+func TestCLI_ExpressionPipeline(t *testing.T) {
+	// kvx tests/sample.yaml --no-color -e '_.items | filter(x, x.available) | map(x, x.name) | sort() | _[0]'
+	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-e", "_.items | filter(x, x.available) | map(x, x.name) | sort() | _[0]"})
+	expected := "chamomile\n"
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
 func TestCLI_TablePrintsSimpleArrayLines(t *testing.T) {
 	// kvx tests/sample.yaml --no-color -e '_.items[0].tags'
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-e", "_.items[0].tags"})
@@ -202,11 +229,12 @@ func TestCLI_TablePrintsSimpleArrayLines(t *testing.T) {
 }
 
 func TestRenderBorderedTableWithOptionsColumnarNever(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	node := []interface{}{
 		map[string]interface{}{"name": "Alice", "age": 30},
 	}
 
-	out := renderBorderedTableWithOptions(node, true, 0, 0, 80, "kvx", "_", formatter.TableFormatOptions{
+	out := renderBorderedTableWithOptions(node, 0, 0, 80, "kvx", "_", formatter.TableFormatOptions{
 		ColumnarMode: "never",
 		ArrayStyle:   "index",
 	})
@@ -215,6 +243,34 @@ func TestRenderBorderedTableWithOptionsColumnarNever(t *testing.T) {
 	}
 }
 
+func TestRenderBorderedTable_WideCharactersAlign(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+	tables := map[string]string{
+		"keyvalue": renderBorderedTableWithOptions(map[string]interface{}{
+			"名前":         "東京タワー",
+			"flag":       family + " family",
+			"cafe\u0301": "e\u0301tude",
+		}, 0, 0, 60, "kvx", "_", formatter.TableFormatOptions{}),
+		"columnar": renderColumnarBorderedTable([]interface{}{
+			map[string]interface{}{"名前": "東京タワー", "v": family},
+			map[string]interface{}{"名前": "a", "v": "e\u0301"},
+		}, 60, "kvx", "_", formatter.TableFormatOptions{}),
+	}
+	for name, out := range tables {
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		want := lipgloss.Width(lines[0])
+		for _, line := range lines {
+			if w := lipgloss.Width(line); w != want {
+				t.Errorf("%s: line %q is %d cells wide, want %d\n%s", name, line, w, want, out)
+			}
+		}
+		if !strings.Contains(out, "東京タワー") || !strings.Contains(out, family) {
+			t.Errorf("%s: wide values were cut:\n%s", name, out)
+		}
+	}
+}
+
 func TestCLI_JSONScalarIsValid(t *testing.T) {
 	// kvx tests/sample.yaml -o json -e '_.name'
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "-o", "json", "-e", "_.name"})
@@ -233,6 +289,67 @@ func TestCLI_YAMLArrayIsValid(t *testing.T) {
 	}
 }
 
+func TestCLI_YAMLFidelityUsesExpressionPath(t *testing.T) {
+	// Equal subtrees are told apart by the path the expression reads.
+	path := filepath.Join(t.TempDir(), "dup.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("a: {x: 1} # A\nb: {x: 1} # B\n"), 0o600))
+
+	out := runCLI(t, []string{"kvx", path, "-o", "yaml", "-e", "_.b", "--yaml-fidelity"})
+	assert.Equal(t, "{x: 1} # B\n", out)
+
+	// A computed value has no source and renders normally.
+	out = runCLI(t, []string{"kvx", path, "-o", "yaml", "-e", `_.filter(k, k == "b")`, "--yaml-fidelity"})
+	assert.Equal(t, "- b\n", out)
+}
+
+func TestCLI_QueryLangJQ(t *testing.T) {
+	t.Cleanup(ui.ResetExpressionProvider)
+	sample := filepath.Join("..", "tests", "sample.yaml")
+
+	// kvx tests/sample.yaml --no-color --query-lang jq -e '.items[] | select(.available) | .name'
+	out := runCLI(t, []string{"kvx", sample, "--no-color", "--query-lang", "jq", "-e", ".items[] | select(.available) | .name"})
+	assert.Equal(t, "chamomile\nearl-grey\n", out)
+
+	out = runCLI(t, []string{"kvx", sample, "-o", "json", "--query-lang", "jq", "-e", ".items[0] | {name, origin}"})
+	assert.JSONEq(t, `{"name": "chamomile", "origin": "egypt"}`, out)
+
+	// Plain jq paths keep the YAML source
+	path := filepath.Join(t.TempDir(), "dup.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("a: {x: 1} # A\nb: {x: 1} # B\n"), 0o600))
+	out = runCLI(t, []string{"kvx", path, "-o", "yaml", "--query-lang", "jq", "-e", ".b", "--yaml-fidelity"})
+	assert.Equal(t, "{x: 1} # B\n", out)
+}
+
+func TestJQPathSteps(t *testing.T) {
+	steps, ok := jqPathSteps(`.items[0]."bad-key"`)
+	require.True(t, ok)
+	assert.Equal(t, []string{"items", "0", "bad-key"}, steps)
+
+	steps, ok = jqPathSteps("")
+	assert.True(t, ok)
+	assert.Empty(t, steps)
+
+	_, ok = jqPathSteps(".items[] | .name")
+	assert.False(t, ok)
+}
+
+func TestCLI_SerializeLayoutFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("zeta: 1\nalpha: [a, b]\n"), 0o600))
+
+	out := runCLI(t, []string{"kvx", path, "-o", "json", "--compact", "--no-trailing-newline"})
+	assert.Equal(t, `{"alpha":["a","b"],"zeta":1}`, out)
+
+	out = runCLI(t, []string{"kvx", path, "-o", "json", "--indent", "4", "--sort-keys=false"})
+	assert.Equal(t, "{\n    \"zeta\": 1,\n    \"alpha\": [\n        \"a\",\n        \"b\"\n    ]\n}\n", out)
+
+	out = runCLI(t, []string{"kvx", path, "-o", "yaml", "--sort-keys", "--sort", "descending", "--indent", "4"})
+	assert.Equal(t, "alpha:\n    - a\n    - b\nzeta: 1\n", out)
+
+	out = runCLI(t, []string{"kvx", path, "-o", "yaml", "--sort", "descending"})
+	assert.Equal(t, "zeta: 1\nalpha:\n  - a\n  - b\n", out)
+}
+
 func TestCLI_EvaluatesArrayLiteralExpression(t *testing.T) {
 	// kvx --no-color -e '[1,2][0]'
 	out := runCLI(t, []string{"kvx", "--no-color", "-e", "[1,2][0]"})
@@ -385,6 +502,38 @@ func TestCLI_EvaluatesMapLiteralWithJSONOutput(t *testing.T) {
 	}
 }
 
+func TestCLI_SchemaEnumInvalidOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataFile := filepath.Join(tmpDir, "data.json")
+	require.NoError(t, os.WriteFile(dataFile, []byte(`[
+		{"id": "a", "status": "active"},
+		{"id": "b", "status": "archived"},
+		{"id": "c", "status": "paused"}
+	]`), 0o644))
+	schemaFile := filepath.Join(tmpDir, "schema.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"status": {"type": "string", "enum": ["active", "paused"]}
+			}
+		}
+	}`), 0o644))
+
+	out := runCLI(t, []string{"kvx", dataFile, "--schema", schemaFile, "--invalid-only", "-o", "json"})
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "b", items[0]["id"])
+
+	out = runCLI(t, []string{"kvx", dataFile, "--no-color", "--schema", schemaFile, "-o", "table"})
+	// The enum caps the column at its longest value, "active"/"paused"
+	assert.Contains(t, out, "arc...")
+	assert.Contains(t, out, "paused")
+}
+
 func TestCLI_SchemaAppliesColumnHints(t *testing.T) {
 	// Create temp directory with test data and schema
 	tmpDir := t.TempDir()
@@ -773,6 +922,34 @@ func TestCLI_LimitingTailIgnoresOffsetJSON(t *testing.T) {
 	require.Equal(t, "matcha", arr[0]["name"])
 }
 
+func TestCLI_LimitingHeadJSON(t *testing.T) {
+	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "-o", "json", "-e", "_.items", "--head", "1"})
+	var arr []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &arr))
+	require.Len(t, arr, 1)
+	require.Equal(t, "chamomile", arr[0]["name"])
+}
+
+func TestCLI_SampleWithSeed(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(`{"id":%d}`, i)
+	}
+	path := writeTempFile(t, "big.ndjson", strings.Join(lines, "\n")+"\n")
+	sample := func(seed string) []map[string]interface{} {
+		out := runCLI(t, []string{"kvx", path, "-o", "json", "--sample", "5", "--seed", seed})
+		var arr []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &arr))
+		return arr
+	}
+	first := sample("42")
+	require.Len(t, first, 5)
+	for i := 1; i < len(first); i++ {
+		assert.Less(t, first[i-1]["id"], first[i]["id"], "records keep their order")
+	}
+	assert.Equal(t, first, sample("42"), "the same seed picks the same records")
+}
+
 func TestCLI_WhereFiltersNDJSON(t *testing.T) {
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.ndjson"), "-o", "json", "-w", `_.level == "ERROR"`})
 	var arr []map[string]interface{}
@@ -1060,7 +1237,7 @@ func TestSnapshotAndInteractiveUseSameMenuConfig(t *testing.T) {
 		filepath.Join("..", "tests", "sample.yaml"),
 		"--snapshot",
 		"--width", "80",
-		"--height", "30",
+		"--height", "43", // tall enough for the help overlay plus the data panel
 		"--press", "<f1>",
 		"--no-color",
 	})
@@ -2002,6 +2179,11 @@ func TestParseSortOrder(t *testing.T) {
 		{"desc", navigator.SortDescending, false},
 		{"descending", navigator.SortDescending, false},
 		{"DESC", navigator.SortDescending, false},
+		{"alpha", navigator.SortAscending, false},
+		{"insertion", navigator.SortInsertion, false},
+		{"original", navigator.SortInsertion, false},
+		{"schema", navigator.SortSchema, false},
+		{"natural", navigator.SortNatural, false},
 		{"invalid", navigator.SortNone, true},
 	}
 	for _, tt := range tests {
@@ -2018,31 +2200,34 @@ func TestParseSortOrder(t *testing.T) {
 }
 
 func TestRenderTableFromRows(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	rows := [][]string{
 		{"name", "Alice"},
 		{"age", "30"},
 	}
-	result := renderTableFromRows(rows, true, 10, 20, 80)
+	result := renderTableFromRows(rows, 10, 20, 80)
 	assert.Contains(t, result, "name")
 	assert.Contains(t, result, "Alice")
 	assert.Contains(t, result, "age")
 }
 
 func TestRenderTableFromRows_AutoWidth(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	rows := [][]string{
 		{"key", "value"},
 	}
 	// widthHint=0 triggers auto-detection
-	result := renderTableFromRows(rows, true, 0, 0, 0)
+	result := renderTableFromRows(rows, 0, 0, 0)
 	assert.Contains(t, result, "key")
 }
 
 func TestRenderBorderedTableRows(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	rows := [][]string{
 		{"name", "test"},
 		{"status", "ok"},
 	}
-	result := renderBorderedTableRows(rows, true, 10, 20, 80, "kvx", "_", nil)
+	result := renderBorderedTableRows(rows, 10, 20, 80, "kvx", "_", nil)
 	assert.Contains(t, result, "name")
 	assert.Contains(t, result, "kvx")
 }
@@ -2089,11 +2274,111 @@ func TestCLI_TableWithSearchTerm(t *testing.T) {
 	assert.Contains(t, out, "chamomile")
 }
 
+func TestCLI_SearchOutput(t *testing.T) {
+	sample := filepath.Join("..", "tests", "sample.yaml")
+
+	out := runCLI(t, []string{"kvx", sample, "--search", "egypt", "--search-output", "paths"})
+	paths := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Contains(t, paths, "_.items[0].origin")
+	assert.Contains(t, paths, "_.items[0].vendor.name")
+
+	out = runCLI(t, []string{"kvx", sample, "--search", "egypt", "--search-output", "count"})
+	assert.Equal(t, fmt.Sprint(len(paths)), strings.TrimSpace(out))
+
+	out = runCLI(t, []string{"kvx", sample, "--search", "no-such-value", "--search-output", "count"})
+	assert.Equal(t, "0", strings.TrimSpace(out))
+
+	quoted := filepath.Join(t.TempDir(), "quoted.json")
+	require.NoError(t, os.WriteFile(quoted, []byte(`{"bad-key": "egypt", "ok": {"odd key": "egypt"}}`), 0o600))
+	out = runCLI(t, []string{"kvx", quoted, "--search", "egypt", "--search-output", "paths"})
+	assert.Equal(t, "_[\"bad-key\"]\n_.ok\n_.ok[\"odd key\"]\n", out)
+}
+
 func TestCLI_TableColumnarAuto(t *testing.T) {
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-e", "_.items"})
 	assert.Contains(t, out, "name")
 }
 
+func TestCLI_TableSummary(t *testing.T) {
+	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.yaml"), "--no-color", "-o", "table",
+		"-e", "_.items", "--column-order", "name,price,stock",
+		"--summary", "price=max", "--summary", "stock=sum", "--summary", "name=size(_.filter(i, i.available))"})
+	assert.Regexp(t, `│Σ\s+2\s+25\.5\s+350\s`, out)
+}
+
+func TestCLI_TableWidthPercentile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rows.json")
+	data := `[{"id":1,"note":"a"},{"id":2,"note":"b"},{"id":3,"note":"c"},{"id":4,"note":"` + strings.Repeat("q", 90) + `"}]`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	out := runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--width-percentile", "75"})
+	assert.Contains(t, out, "qqqqq...")
+	assert.NotContains(t, out, strings.Repeat("q", 9))
+}
+
+func TestCLI_TableSortBy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rows.json")
+	data := `[{"name":"item10","size":10},{"name":"item2","size":2},{"name":"item1","size":100}]`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	order := func(out string) []int {
+		return []int{strings.Index(out, "item1 "), strings.Index(out, "item2 "), strings.Index(out, "item10")}
+	}
+	out := runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--sort-by", "size"})
+	pos := order(out)
+	assert.Less(t, pos[1], pos[2], "2 before 10")
+	assert.Less(t, pos[2], pos[0], "10 before 100")
+
+	out = runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--sort-by", "name:desc"})
+	pos = order(out)
+	assert.Less(t, pos[2], pos[1], "natural order, reversed")
+	assert.Less(t, pos[1], pos[0])
+}
+
+func TestCLI_SortNatural(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"item10":1,"item2":2,"item1":3}`), 0o600))
+
+	for _, format := range []string{"table", "tree", "list"} {
+		out := runCLI(t, []string{"kvx", path, "--no-color", "-o", format, "--sort", "natural"})
+		i1, i2, i10 := strings.Index(out, "item1"), strings.Index(out, "item2"), strings.Index(out, "item10")
+		assert.Less(t, i1, i2, format)
+		assert.Less(t, i2, i10, format)
+	}
+}
+
+func TestCLI_TableWrap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.yaml")
+	data := "desc: the quick brown fox jumps over the lazy dog and keeps on running far beyond the fence\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	out := runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--width", "50"})
+	assert.NotContains(t, out, "fence")
+
+	out = runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--width", "50", "--wrap"})
+	assert.Contains(t, out, "fence")
+	assert.Equal(t, 1, strings.Count(out, "desc"), "continuation lines leave the key blank")
+}
+
+func TestCLI_TableEllipsis(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.yaml")
+	data := "desc: the quick brown fox jumps over the lazy dog and keeps on running far beyond the fence\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	out := runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--width", "50", "--ellipsis", "…"})
+	assert.Contains(t, out, "…")
+	assert.NotContains(t, out, "...")
+
+	// The next run without the flag is back to the default.
+	out = runCLI(t, []string{"kvx", path, "--no-color", "-o", "table", "--width", "50"})
+	assert.Contains(t, out, "...")
+}
+
 func TestCLI_NDJSONFile(t *testing.T) {
 	out := runCLI(t, []string{"kvx", filepath.Join("..", "tests", "sample.ndjson"), "--no-color"})
 	assert.NotEmpty(t, out)
@@ -2195,6 +2480,51 @@ func TestEffectiveKeyMode_InvalidFlag(t *testing.T) {
 	assert.Equal(t, ui.DefaultKeyMode, effectiveKeyMode(cfg))
 }
 
+func TestHyperlinksEnabled(t *testing.T) {
+	useColorProfile(t, colorprofile.ANSI)
+	t.Setenv("FORCE_HYPERLINK", "")
+	t.Setenv("TERM", "xterm-256color")
+	cfg := ui.ThemeConfigFile{}
+
+	assert.True(t, hyperlinksEnabled(cfg, true))
+	assert.False(t, hyperlinksEnabled(cfg, false), "no hyperlinks when piped")
+
+	t.Setenv("TERM", "dumb")
+	assert.False(t, hyperlinksEnabled(cfg, true))
+
+	t.Setenv("FORCE_HYPERLINK", "1")
+	assert.True(t, hyperlinksEnabled(cfg, false), "FORCE_HYPERLINK overrides detection")
+
+	off := false
+	cfg.Features.Hyperlinks = &off
+	assert.False(t, hyperlinksEnabled(cfg, true), "config toggle wins")
+}
+
+func TestIdleSettings(t *testing.T) {
+	t.Cleanup(resetRootCmdState)
+	cfg := ui.ThemeConfigFile{}
+	timeout, printResult := idleSettings(cfg)
+	assert.Zero(t, timeout)
+	assert.False(t, printResult)
+
+	thirty, yes := "30m", true
+	cfg.Behavior.IdleTimeout = &thirty
+	cfg.Behavior.IdlePrint = &yes
+	timeout, printResult = idleSettings(cfg)
+	assert.Equal(t, 30*time.Minute, timeout)
+	assert.True(t, printResult)
+
+	idleTimeout, idleTimeoutSet = 0, true
+	timeout, _ = idleSettings(cfg)
+	assert.Zero(t, timeout, "--timeout 0 turns off the configured timeout")
+	idleTimeoutSet = false
+
+	bad := "soon"
+	cfg.Behavior.IdleTimeout = &bad
+	timeout, _ = idleSettings(cfg)
+	assert.Zero(t, timeout)
+}
+
 // --- tableFormatOptionsFromConfig tests ---
 
 func TestTableFormatOptionsFromConfig_Defaults(t *testing.T) {
@@ -2249,6 +2579,34 @@ func TestTableFormatOptionsFromConfig_HiddenColumns(t *testing.T) {
 	assert.Contains(t, opts.HiddenColumns, "secret")
 }
 
+func TestTableFormatOptionsFromConfig_SchemaColumnOrder(t *testing.T) {
+	cfg := ui.ThemeConfigFile{
+		Formatting: ui.FormattingConfig{
+			Table: ui.TableFormattingConfig{
+				Schema: map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":              "object",
+						"x-kvx-columnOrder": []any{"name", "tier", "status"},
+						"properties": map[string]any{
+							"status": map[string]any{"deprecated": true},
+						},
+					},
+				},
+			},
+		},
+	}
+	opts := tableFormatOptionsFromConfig(cfg)
+	assert.Equal(t, []string{"name", "tier", "status"}, opts.ColumnOrder)
+	assert.Contains(t, opts.HiddenColumns, "status")
+	assert.Nil(t, parsedDisplaySchema, "x-kvx-columnOrder is not a display schema")
+
+	// An explicit column order wins.
+	cfg.Formatting.Table.ColumnOrder = []string{"tier"}
+	opts = tableFormatOptionsFromConfig(cfg)
+	assert.Equal(t, []string{"tier"}, opts.ColumnOrder)
+}
+
 // --- yamlFormatOptionsFromConfig tests ---
 
 func TestYAMLFormatOptionsFromConfig_Defaults(t *testing.T) {
@@ -2281,18 +2639,20 @@ func TestYAMLFormatOptionsFromConfig_Custom(t *testing.T) {
 // --- renderTableFromNode test ---
 
 func TestRenderTableFromNode_Map(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	node := map[string]interface{}{"key": "value", "count": 42}
-	out := renderTableFromNode(node, true, 0, 0, 80, formatter.DefaultTableFormatOptions())
+	out := renderTableFromNode(node, 0, 0, 80, formatter.DefaultTableFormatOptions())
 	assert.Contains(t, out, "key")
 	assert.Contains(t, out, "value")
 }
 
 func TestRenderTableFromNode_Array(t *testing.T) {
+	useColorProfile(t, colorprofile.ASCII)
 	node := []interface{}{
 		map[string]interface{}{"name": "alice"},
 		map[string]interface{}{"name": "bob"},
 	}
-	out := renderTableFromNode(node, true, 0, 0, 80, formatter.DefaultTableFormatOptions())
+	out := renderTableFromNode(node, 0, 0, 80, formatter.DefaultTableFormatOptions())
 	assert.Contains(t, out, "alice")
 }
 
@@ -2489,6 +2849,18 @@ func TestCLI_AutoFallsBackToListExtremeNarrow(t *testing.T) {
 	assert.NotEmpty(t, out)
 }
 
+func TestCLI_SchemaDetailView(t *testing.T) {
+	out := runCLI(t, []string{
+		"kvx", filepath.Join("..", "examples", "data", "users.json"),
+		"--schema", filepath.Join("..", "examples", "data", "users_schema.json"),
+		"--snapshot", "--no-color", "--width", "80", "--height", "24", "--press", "jd",
+	})
+	out = ansiStripRe.ReplaceAllString(out, "")
+	assert.Contains(t, out, "Name   Bob Jones")
+	assert.Contains(t, out, "User's full name")
+	assert.NotContains(t, out, "Y456", "deprecated fields stay hidden")
+}
+
 func TestCLI_ColumnOrderFlag_Table(t *testing.T) {
 	out := runCLI(t, []string{
 		"kvx", filepath.Join("..", "tests", "sample.yaml"),
@@ -2537,3 +2909,44 @@ func TestCLI_ColumnOrderFlag_List(t *testing.T) {
 	assert.True(t, strings.HasPrefix(lines[1], "price:"))
 	assert.True(t, strings.HasPrefix(lines[2], "stock:"))
 }
+
+func TestCLI_OutputEnv(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("services:\n  api:\n    port: 8080\n    dbUrl: postgres://db/app\n  web:\n    port: 80\n"), 0o600))
+
+	out := runCLI(t, []string{"kvx", file, "-o", "env"})
+	assert.Equal(t, "services_api_dbUrl=postgres://db/app\nservices_api_port=8080\nservices_web_port=80\n", out)
+
+	out = runCLI(t, []string{"kvx", file, "-o", "env", "--prefix", "services.api", "--strip-prefix", "--key-case", "upper-snake"})
+	assert.Equal(t, "DB_URL=postgres://db/app\nPORT=8080\n", out)
+}
+
+func TestCLI_OutputShell(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("database:\n  host: db.local\n  password: \"it's secret\"\n"), 0o600))
+
+	out := runCLI(t, []string{"kvx", file, "-e", "_.database", "-o", "shell", "--key-case", "upper-snake"})
+	assert.Equal(t, "HOST='db.local'\nPASSWORD='it'\\''s secret'\n", out)
+}
+
+func TestEnvOptionsFromFlags_Errors(t *testing.T) {
+	t.Cleanup(func() { envPrefix, envKeyCase = "", "keep" })
+	envKeyCase = "camel"
+	_, err := envOptionsFromFlags()
+	assert.ErrorContains(t, err, "--key-case")
+
+	envKeyCase, envPrefix = "keep", "a..b"
+	_, err = envOptionsFromFlags()
+	assert.ErrorContains(t, err, "--prefix")
+}
+
+func TestCLI_PressScript(t *testing.T) {
+	resetRootCmdState()
+	t.Cleanup(resetRootCmdState)
+	out := runCLI(t, []string{"kvx", "../tests/sample.yaml", "--snapshot", "--no-color", "--width", "80", "--height", "14",
+		"--press", `<type:/chamomile><sleep:1ms><expect:"chamomile"><Esc>`})
+	assert.Contains(t, out, "chamomile")
+	assert.Empty(t, scriptErrors)
+}
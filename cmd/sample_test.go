@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetSampleFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		sampleShape, sampleRows, sampleDataSeed, sampleOutput = "users", 10, 0, "json"
+		sampleCmd.Flags().Lookup("seed").Changed = false
+	})
+}
+
+func TestCLI_Sample(t *testing.T) {
+	resetSampleFlags(t)
+	out := runCLI(t, []string{"kvx", "sample", "--shape", "metrics", "--rows", "3", "--seed", "42", "-o", "ndjson"})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"timestamp":"2024-01-01T00:00:00Z"`)
+
+	again := runCLI(t, []string{"kvx", "sample", "--shape", "metrics", "--rows", "3", "--seed", "42", "-o", "ndjson"})
+	assert.Equal(t, out, again)
+}
+
+func TestCLI_SampleK8sYAML(t *testing.T) {
+	resetSampleFlags(t)
+	out := runCLI(t, []string{"kvx", "sample", "--shape", "k8s", "--rows", "2", "-o", "yaml"})
+	assert.Contains(t, out, "kind: List\n")
+	assert.Contains(t, out, "kind: Pod\n")
+}
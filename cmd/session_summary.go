@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// sessionSummaryFile is the file in the state directory that
+// --session-summary=save appends to.
+const sessionSummaryFile = "sessions.jsonl"
+
+// sessionSummaryRecord is a line of the sessions file.
+type sessionSummaryRecord struct {
+	Start         time.Time `json:"start"`
+	DurationMs    int64     `json:"duration_ms"`
+	Expressions   int       `json:"expressions"`
+	Failed        int       `json:"failed_expressions"`
+	Visits        int       `json:"visits"`
+	DeepestPath   string    `json:"deepest_path,omitempty"`
+	DeepestDepth  int       `json:"deepest_depth"`
+	SlowestExpr   string    `json:"slowest_expression,omitempty"`
+	SlowestEvalMs float64   `json:"slowest_eval_ms"`
+}
+
+func validateSessionSummary() error {
+	switch sessionSummary {
+	case "", "print", "save":
+		return nil
+	}
+	return fmt.Errorf("invalid --session-summary %q (expected print or save)", sessionSummary)
+}
+
+// configureSessionSummary counts the session for --session-summary and
+// reports it when the TUI exits.
+func configureSessionSummary(m *ui.Model) {
+	if sessionSummary == "" {
+		return
+	}
+	m.Stats = ui.NewSessionStats()
+	m.OnExitStats = func(s ui.SessionStats) {
+		var err error
+		if sessionSummary == "save" {
+			err = saveSessionSummary(ui.StateDir(), s)
+		} else {
+			err = printSessionSummary(os.Stderr, s)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+}
+
+// printSessionSummary writes the summary of a session for people to read.
+func printSessionSummary(w io.Writer, s ui.SessionStats) error {
+	failed := ""
+	if s.Failed > 0 {
+		failed = fmt.Sprintf(" (%d failed)", s.Failed)
+	}
+	_, err := fmt.Fprintf(w, "session: %s, %s%s, %s visited\n",
+		s.Duration.Round(time.Second), plural(s.Expressions, "expression"), failed, plural(s.Visits, "path"))
+	if err == nil && s.DeepestPath != "" {
+		_, err = fmt.Fprintf(w, "deepest path: %s (depth %d)\n", s.DeepestPath, s.DeepestDepth)
+	}
+	if err == nil && s.SlowestExpr != "" {
+		_, err = fmt.Fprintf(w, "slowest evaluation: %s for %s\n", s.SlowestEval.Round(time.Millisecond/10), s.SlowestExpr)
+	}
+	return err
+}
+
+// saveSessionSummary appends the summary of a session to the sessions file
+// in dir, one JSON record per line.
+func saveSessionSummary(dir string, s ui.SessionStats) error {
+	if dir == "" {
+		return errors.New("save session summary: no state directory")
+	}
+	line, err := json.Marshal(sessionSummaryRecord{
+		Start:         s.Start,
+		DurationMs:    s.Duration.Milliseconds(),
+		Expressions:   s.Expressions,
+		Failed:        s.Failed,
+		Visits:        s.Visits,
+		DeepestPath:   s.DeepestPath,
+		DeepestDepth:  s.DeepestDepth,
+		SlowestExpr:   s.SlowestExpr,
+		SlowestEvalMs: float64(s.SlowestEval.Microseconds()) / 1000,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("save session summary: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, sessionSummaryFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("save session summary: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("save session summary: %w", err)
+	}
+	return nil
+}
+
+// plural returns n and noun, adding an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
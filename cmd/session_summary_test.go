@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func testSessionStats() ui.SessionStats {
+	return ui.SessionStats{
+		Start:        time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC),
+		Duration:     4*time.Minute + 12*time.Second + 300*time.Millisecond,
+		Expressions:  7,
+		Failed:       1,
+		Visits:       23,
+		DeepestPath:  "_.spec.containers[0].env",
+		DeepestDepth: 4,
+		SlowestExpr:  "_.items.filter(x, x.size > 10)",
+		SlowestEval:  412*time.Millisecond + 345*time.Microsecond,
+	}
+}
+
+func TestPrintSessionSummary(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printSessionSummary(&out, testSessionStats()))
+	assert.Equal(t, `session: 4m12s, 7 expressions (1 failed), 23 paths visited
+deepest path: _.spec.containers[0].env (depth 4)
+slowest evaluation: 412.3ms for _.items.filter(x, x.size > 10)
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, printSessionSummary(&out, ui.SessionStats{Duration: time.Second, Visits: 1}))
+	assert.Equal(t, "session: 1s, 0 expressions, 1 path visited\n", out.String())
+}
+
+func TestSaveSessionSummary(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "kvx")
+	require.NoError(t, saveSessionSummary(dir, testSessionStats()))
+	require.NoError(t, saveSessionSummary(dir, ui.SessionStats{}))
+
+	f, err := os.Open(filepath.Join(dir, sessionSummaryFile))
+	require.NoError(t, err)
+	defer f.Close()
+	var records []sessionSummaryRecord
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r sessionSummaryRecord
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.Len(t, records, 2, "each session appends a line")
+	assert.Equal(t, int64(252300), records[0].DurationMs)
+	assert.Equal(t, 7, records[0].Expressions)
+	assert.Equal(t, "_.spec.containers[0].env", records[0].DeepestPath)
+	assert.InDelta(t, 412.345, records[0].SlowestEvalMs, 0.0001)
+
+	assert.EqualError(t, saveSessionSummary("", ui.SessionStats{}), "save session summary: no state directory")
+}
+
+func TestValidateSessionSummary(t *testing.T) {
+	t.Cleanup(func() { sessionSummary = "" })
+	for _, v := range []string{"", "print", "save"} {
+		sessionSummary = v
+		assert.NoError(t, validateSessionSummary())
+	}
+	sessionSummary = "stdout"
+	assert.EqualError(t, validateSessionSummary(), `invalid --session-summary "stdout" (expected print or save)`)
+}
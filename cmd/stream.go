@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/pkg/core"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
+
+// validateStreamFlags checks --stream, which reads NDJSON input while the
+// TUI shows the records read so far.
+func validateStreamFlags(args []string) error {
+	if !streamInput {
+		return nil
+	}
+	switch {
+	case renderSnapshot:
+		return errors.New("--stream cannot be used with --snapshot")
+	case refreshInterval > 0:
+		return errors.New("--stream cannot be used with --refresh")
+	case len(args) > 1:
+		return errors.New("--stream reads a single input")
+	case (len(args) == 0 || args[0] == "-") && !stdinIsPiped():
+		return errors.New("--stream needs an NDJSON file or piped input")
+	}
+	return nil
+}
+
+// startInputStream opens the --stream input, reads its first record, and
+// reads the rest in the background into the returned stream. Records are
+// decoded with --auto-decode=eager and filtered with --where as they
+// arrive; the document holds those read so far.
+func startInputStream(args []string) (*loader.Document, *ui.RecordStream, error) {
+	in := io.ReadCloser(os.Stdin)
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file %s: %w", args[0], err)
+		}
+		in = f
+	}
+	src := loader.NewNDJSONStream(in, loader.DocumentOptions{
+		KeyOrder: navigator.CurrentSortOrder() == navigator.SortInsertion || keepsDocumentKeyOrder(),
+	})
+	navigator.SetDocumentOrder(src.Order())
+	prepare, err := streamRecordFilter()
+	if err != nil {
+		_ = in.Close()
+		return nil, nil, err
+	}
+
+	stream := ui.NewRecordStream()
+	first, err := src.Next()
+	switch {
+	case errors.Is(err, io.EOF):
+		_ = in.Close()
+		stream.Close(nil)
+		return &loader.Document{Root: stream.Records()}, stream, nil
+	case err != nil:
+		_ = in.Close()
+		return nil, nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	records, err := prepare(first)
+	if err != nil {
+		_ = in.Close()
+		return nil, nil, err
+	}
+	stream.Append(records...)
+
+	go func() {
+		defer in.Close()
+		for {
+			rec, err := src.Next()
+			if errors.Is(err, io.EOF) {
+				stream.Close(nil)
+				return
+			}
+			if err == nil {
+				var records []any
+				if records, err = prepare(rec); err == nil {
+					stream.Append(records...)
+					continue
+				}
+			}
+			stream.Close(fmt.Errorf("line %d: %w", src.Line(), err))
+			return
+		}
+	}()
+	return &loader.Document{Root: stream.Records(), Order: src.Order()}, stream, nil
+}
+
+// streamRecordFilter returns the function that prepares a streamed record:
+// it decodes the record with --auto-decode=eager and returns it, or nothing
+// when --where filters it out.
+func streamRecordFilter() (func(any) ([]any, error), error) {
+	var engine *core.Engine
+	if whereExpr != "" {
+		var err error
+		if engine, err = core.New(); err != nil {
+			return nil, fmt.Errorf("failed to init evaluator: %w", err)
+		}
+	}
+	return func(rec any) ([]any, error) {
+		if autoDecode == "eager" {
+			rec = loader.RecursiveDecode(rec)
+		}
+		if engine == nil {
+			return []any{rec}, nil
+		}
+		kept, err := engine.EvaluateWhere(whereExpr, []any{rec})
+		if err != nil {
+			return nil, fmt.Errorf("where filter: %w", err)
+		}
+		return kept, nil
+	}, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStreamFlags(t *testing.T) {
+	origPiped := stdinIsPiped
+	t.Cleanup(func() {
+		streamInput, renderSnapshot, refreshInterval = false, false, 0
+		stdinIsPiped = origPiped
+	})
+	stdinIsPiped = func() bool { return false }
+
+	streamInput = false
+	require.NoError(t, validateStreamFlags(nil))
+
+	streamInput = true
+	require.NoError(t, validateStreamFlags([]string{"logs.jsonl"}))
+	assert.EqualError(t, validateStreamFlags(nil), "--stream needs an NDJSON file or piped input")
+	assert.EqualError(t, validateStreamFlags([]string{"a.jsonl", "b.jsonl"}), "--stream reads a single input")
+	stdinIsPiped = func() bool { return true }
+	require.NoError(t, validateStreamFlags([]string{"-"}))
+
+	renderSnapshot = true
+	assert.EqualError(t, validateStreamFlags(nil), "--stream cannot be used with --snapshot")
+	renderSnapshot = false
+	refreshInterval = time.Second
+	assert.EqualError(t, validateStreamFlags(nil), "--stream cannot be used with --refresh")
+}
+
+func TestStartInputStream(t *testing.T) {
+	t.Cleanup(func() { whereExpr = "" })
+	path := filepath.Join(t.TempDir(), "logs.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"level\":\"info\"}\n{\"level\":\"error\"}\n{\"level\":\"error\"}\n"), 0o600))
+	whereExpr = `_.level == "error"`
+
+	doc, stream, err := startInputStream([]string{path})
+	require.NoError(t, err)
+	assert.Empty(t, doc.Root, "the first record is filtered out by --where")
+	require.Eventually(t, func() bool { return stream.Len() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []any{map[string]any{"level": "error"}, map[string]any{"level": "error"}}, stream.Records())
+
+	_, _, err = startInputStream([]string{filepath.Join(t.TempDir(), "missing.jsonl")})
+	assert.ErrorContains(t, err, "failed to read file")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
+
+var tutorialCmd = &cobra.Command{
+	Use:   "tutorial",
+	Short: "Learn the TUI step by step on a sample document",
+	Long: `Open the TUI on a bundled sample document and walk through navigation,
+search, filtering, and CEL expressions. A panel above the table says what to
+do next and moves on once it is done; the status bar confirms each step.
+
+The steps use the keys of --keymap (or the key mode of the config).`,
+	Example: `  kvx tutorial
+  kvx tutorial --keymap emacs`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runTutorial()
+	},
+}
+
+func runTutorial() error {
+	if keyMode != "" && !ui.IsValidKeyMode(keyMode) {
+		return fmt.Errorf("invalid --keymap %q (expected vim, emacs, or function)", keyMode)
+	}
+	resolved := resolveConfigPath(configFile)
+	cfg, err := loadMergedConfig(resolved)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	_ = ui.InitializeThemes(&cfg)
+	if th, ok := cfg.Themes[cfg.Theme.Default]; ok {
+		ui.SetTheme(ui.ThemeFromConfig(th))
+	} else if th, ok := ui.GetTheme(cfg.Theme.Default); ok {
+		ui.SetTheme(th)
+	}
+	root, err := loader.LoadRootBytes(ui.TutorialDocument())
+	if err != nil {
+		return fmt.Errorf("failed to load the tutorial document: %w", err)
+	}
+
+	appName := cfg.About.Name
+	if strings.TrimSpace(appName) == "" {
+		appName = "kvx"
+	}
+	mode := effectiveKeyMode(cfg)
+	helpTitle, helpText := loadHelp(resolved, mode)
+	opts, cleanup := getProgramOptions()
+	defer cleanup()
+
+	return ui.RunModel(appName, root, helpTitle, helpText, false, func(string) {}, "", 0, 0, nil, false, "", nil, func(m *ui.Model) {
+		applySnapshotConfigToModel(m, cfg)
+		m.Tutorial = ui.NewTutorial(m.KeyMode)
+	}, opts...)
+}
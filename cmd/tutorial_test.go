@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
+
+func TestRunTutorialInvalidKeymap(t *testing.T) {
+	old := keyMode
+	t.Cleanup(func() { keyMode = old })
+	keyMode = "nonsense"
+	assert.EqualError(t, runTutorial(), `invalid --keymap "nonsense" (expected vim, emacs, or function)`)
+}
+
+func TestTutorialDocumentLoads(t *testing.T) {
+	root, err := loader.LoadRootBytes(ui.TutorialDocument())
+	require.NoError(t, err)
+	doc, ok := root.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, doc, "services")
+	assert.Contains(t, doc, "config")
+}
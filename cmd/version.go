@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	rdebug "runtime/debug"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/termcolor"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// Build metadata stamped into release binaries. goreleaser sets the main
+// package variables with -ldflags -X and main hands them over through
+// SetBuildInfo; development builds leave them empty and fall back to the Go
+// build info.
+var (
+	buildVersion string
+	buildCommit  string
+	buildTime    string
+)
+
+// versionOutput is the -o flag of the version command.
+var versionOutput string
+
+// SetBuildInfo records the version, commit, and build date of a release
+// build. Call it before Execute.
+func SetBuildInfo(version, commit, date string) {
+	buildVersion = version
+	buildCommit = commit
+	buildTime = date
+	rootCmd.Version = cliVersionString()
+}
+
+// versionInfo is the machine-readable report of `kvx version -o json|yaml`.
+type versionInfo struct {
+	Name         string            `json:"name" yaml:"name"`
+	Version      string            `json:"version" yaml:"version"`
+	Commit       string            `json:"commit" yaml:"commit"`
+	BuildDate    string            `json:"build_date" yaml:"build_date"`
+	GoVersion    string            `json:"go_version" yaml:"go_version"`
+	OS           string            `json:"os" yaml:"os"`
+	Arch         string            `json:"arch" yaml:"arch"`
+	Features     map[string]bool   `json:"features" yaml:"features"`
+	Clipboard    string            `json:"clipboard" yaml:"clipboard"`
+	ColorProfile string            `json:"color_profile" yaml:"color_profile"`
+	Config       versionConfigInfo `json:"config" yaml:"config"`
+}
+
+// versionConfigInfo reports where kvx looks for its config file.
+type versionConfigInfo struct {
+	File        string   `json:"file" yaml:"file"`
+	SearchPaths []string `json:"search_paths" yaml:"search_paths"`
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print kvx version",
+	Long: `Print kvx version.
+
+With -o json or -o yaml the report also lists the commit, build date, Go
+version, the optional features enabled for this build and terminal, and the
+config file paths, for bug reports and support diagnostics.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runVersion(versionOutput)
+	},
+}
+
+func runVersion(format string) error {
+	switch format {
+	case "", "text":
+		fmt.Println(cliVersionString()) //nolint:forbidigo
+		return nil
+	case "json":
+		data, err := json.MarshalIndent(collectVersionInfo(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal version info: %w", err)
+		}
+		fmt.Println(string(data)) //nolint:forbidigo
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(collectVersionInfo())
+		if err != nil {
+			return fmt.Errorf("failed to marshal version info: %w", err)
+		}
+		fmt.Print(string(data)) //nolint:forbidigo
+		return nil
+	default:
+		return fmt.Errorf("invalid version output format %q (expected text, json, or yaml)", format)
+	}
+}
+
+// collectVersionInfo gathers the version report from the build info, the
+// merged config, and the current environment.
+func collectVersionInfo() versionInfo {
+	resolved := resolveConfigPath(configFile)
+	cfg, _ := loadMergedConfig(resolved)
+	build := buildVersionData(&cfg)
+
+	tty := !stdoutIsPiped() && ui.ColorSupported(os.Stdout)
+	mode, err := termcolor.ParseMode(colorMode)
+	if err != nil {
+		mode = termcolor.ModeAuto
+	}
+	profile := termcolor.Detect(mode, tty, os.Environ())
+	clipboard := ui.ClipboardCommand()
+
+	searchPaths := ui.ConfigFileCandidates()
+	if searchPaths == nil {
+		searchPaths = []string{}
+	}
+	return versionInfo{
+		Name:      stringValue(build["Name"]),
+		Version:   stringValue(build["Version"]),
+		Commit:    stringValue(build["GitCommit"]),
+		BuildDate: stringValue(build["BuildDate"]),
+		GoVersion: stringValue(build["GoVersion"]),
+		OS:        stringValue(build["BuildOS"]),
+		Arch:      stringValue(build["BuildArch"]),
+		Features: map[string]bool{
+			"cgo":           cgoEnabled(),
+			"clipboard":     clipboard != "",
+			"color":         profile > colorprofile.ASCII,
+			"expressions":   cfg.Features.AllowEditInput == nil || *cfg.Features.AllowEditInput,
+			"hyperlinks":    hyperlinksEnabled(cfg, tty),
+			"suggestions":   cfg.Features.AllowSuggestions == nil || *cfg.Features.AllowSuggestions,
+			"yaml_fidelity": cfg.Formatting.YAML.Fidelity != nil && *cfg.Formatting.YAML.Fidelity,
+		},
+		Clipboard:    clipboard,
+		ColorProfile: profile.String(),
+		Config: versionConfigInfo{
+			File:        resolved,
+			SearchPaths: searchPaths,
+		},
+	}
+}
+
+// cgoEnabled reports whether the binary was built with cgo.
+func cgoEnabled() bool {
+	info, ok := rdebug.ReadBuildInfo()
+	if !ok {
+		return false
+	}
+	for _, s := range info.Settings {
+		if s.Key == "CGO_ENABLED" {
+			return s.Value == "1"
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func setTestBuildInfo(t *testing.T, version, commit, date string) {
+	t.Helper()
+	origVersion, origCommit, origTime := buildVersion, buildCommit, buildTime
+	t.Cleanup(func() {
+		SetBuildInfo(origVersion, origCommit, origTime)
+		versionOutput = "text"
+	})
+	SetBuildInfo(version, commit, date)
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	setTestBuildInfo(t, "1.2.3", "abc1234", "2026-01-02T03:04:05Z")
+
+	assert.Contains(t, rootCmd.Version, "kvx 1.2.3")
+	data := buildVersionData(nil)
+	assert.Equal(t, "1.2.3", data["Version"])
+	assert.Equal(t, "abc1234", data["GitCommit"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", data["BuildDate"])
+}
+
+func TestCLI_VersionText(t *testing.T) {
+	setTestBuildInfo(t, "1.2.3", "abc1234", "")
+
+	out := runCLI(t, []string{"kvx", "version"})
+	assert.Regexp(t, `^kvx 1\.2\.3 \(go .+\)\n$`, out)
+}
+
+func TestCLI_VersionJSON(t *testing.T) {
+	setTestBuildInfo(t, "1.2.3", "abc1234", "2026-01-02T03:04:05Z")
+	origPiped := stdoutIsPiped
+	t.Cleanup(func() { stdoutIsPiped = origPiped })
+	stdoutIsPiped = func() bool { return true }
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	out := runCLI(t, []string{"kvx", "version", "-o", "json"})
+	var info versionInfo
+	require.NoError(t, json.Unmarshal([]byte(out), &info), out)
+
+	assert.Equal(t, "kvx", info.Name)
+	assert.Equal(t, "1.2.3", info.Version)
+	assert.Equal(t, "abc1234", info.Commit)
+	assert.Equal(t, "2026-01-02T03:04:05Z", info.BuildDate)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.NotEmpty(t, info.OS)
+	assert.NotEmpty(t, info.Arch)
+	for _, feature := range []string{"cgo", "clipboard", "color", "expressions", "hyperlinks", "suggestions", "yaml_fidelity"} {
+		assert.Contains(t, info.Features, feature)
+	}
+	assert.False(t, info.Features["color"], "piped output is not colored")
+	assert.Equal(t, "NoTTY", info.ColorProfile)
+	assert.Empty(t, info.Config.File, "runCLI isolates from user config")
+	require.NotEmpty(t, info.Config.SearchPaths)
+	assert.Equal(t, "config.yaml", filepath.Base(info.Config.SearchPaths[0]))
+}
+
+func TestCLI_VersionYAML(t *testing.T) {
+	setTestBuildInfo(t, "1.2.3", "abc1234", "")
+
+	out := runCLI(t, []string{"kvx", "version", "-o", "yaml"})
+	var info map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &info), out)
+	assert.Equal(t, "1.2.3", info["version"])
+	assert.Contains(t, info, "features")
+	assert.Contains(t, info, "config")
+}
+
+func TestRunVersion_InvalidFormat(t *testing.T) {
+	err := runVersion("xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected text, json, or yaml")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+// savedView is the view layout remembered for the input of an interactive
+// session; key is "" when nothing is remembered (stdin, --no-view-state).
+type savedView struct {
+	key   string
+	state ui.ViewState
+}
+
+// loadSavedView looks up the view layout saved for the input.
+func loadSavedView(args []string, cfg ui.ThemeConfigFile) savedView {
+	if noViewState {
+		return savedView{}
+	}
+	schemaPath := schemaFile
+	if schemaPath == "" && cfg.Formatting.Table.SchemaFile != nil {
+		schemaPath = *cfg.Formatting.Table.SchemaFile
+	}
+	v := savedView{key: viewStateKey(args, schemaPath)}
+	if v.key != "" {
+		v.state, _ = ui.LoadViewState(v.key)
+	}
+	return v
+}
+
+// viewStateKey returns the key a view layout is remembered under. With a
+// schema it is the schema's $id (or its file name), otherwise the input's
+// file name, so every package.json or Chart.yaml shares one layout.
+// Returns "" for stdin and multiple inputs.
+func viewStateKey(args []string, schemaPath string) string {
+	if schemaPath != "" {
+		var schema struct {
+			ID string `json:"$id"`
+		}
+		if data, err := os.ReadFile(schemaPath); err == nil && json.Unmarshal(data, &schema) == nil && schema.ID != "" {
+			return "schema:" + schema.ID
+		}
+		return "schema:" + filepath.Base(schemaPath)
+	}
+	if len(args) != 1 || args[0] == "-" || strings.TrimSpace(args[0]) == "" {
+		return ""
+	}
+	return "file:" + filepath.Base(args[0])
+}
+
+// applySort restores the saved sort order unless --sort was given.
+func (v savedView) applySort() {
+	if v.key == "" || v.state.Sort == "" || strings.TrimSpace(sortOrder) != "" {
+		return
+	}
+	if order, err := parseSortOrder(v.state.Sort); err == nil {
+		navigator.SetSortOrder(order)
+	}
+}
+
+// configure restores the saved layout on m, keeping --column-order when it
+// was given, and saves the layout again when the TUI exits.
+func (v savedView) configure(m *ui.Model) {
+	if v.key == "" {
+		return
+	}
+	state := v.state
+	if len(columnOrder) > 0 {
+		state.ColumnOrder = nil
+	}
+	m.ApplyViewState(state)
+
+	sort := v.state.Sort
+	if strings.TrimSpace(sortOrder) != "" {
+		if order, err := parseSortOrder(sortOrder); err == nil {
+			sort = string(order)
+		}
+	}
+	m.OnExitViewState = func(vs ui.ViewState) {
+		vs.Sort = sort
+		if err := ui.SaveViewState(v.key, vs); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/ui"
+)
+
+func TestViewStateKey(t *testing.T) {
+	assert.Equal(t, "file:package.json", viewStateKey([]string{filepath.Join("a", "b", "package.json")}, ""))
+	assert.Empty(t, viewStateKey(nil, ""), "stdin")
+	assert.Empty(t, viewStateKey([]string{"-"}, ""))
+	assert.Empty(t, viewStateKey([]string{"a.json", "b.json"}, ""))
+
+	dir := t.TempDir()
+	withID := filepath.Join(dir, "pods.schema.json")
+	require.NoError(t, os.WriteFile(withID, []byte(`{"$id": "https://example.com/pods", "type": "array"}`), 0o600))
+	assert.Equal(t, "schema:https://example.com/pods", viewStateKey([]string{"x.json"}, withID))
+	withoutID := filepath.Join(dir, "nodes.schema.json")
+	require.NoError(t, os.WriteFile(withoutID, []byte(`{"type": "array"}`), 0o600))
+	assert.Equal(t, "schema:nodes.schema.json", viewStateKey([]string{"x.json"}, withoutID))
+}
+
+func TestSavedViewRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	prevSort := navigator.SetSortOrder(navigator.SortNone)
+	t.Cleanup(func() {
+		navigator.SetSortOrder(prevSort)
+		sortOrder, columnOrder, noViewState = "", nil, false
+	})
+	args := []string{"pods.json"}
+
+	// First session: --sort is remembered with the layout chosen in the TUI.
+	sortOrder = "desc"
+	view := loadSavedView(args, ui.ThemeConfigFile{})
+	m := ui.InitialModel([]interface{}{})
+	view.configure(&m)
+	m.ColumnarView = true
+	m.HiddenColumns = []string{"uid"}
+	m.OnExitViewState(m.CurrentViewState())
+
+	// Second session restores both.
+	sortOrder = ""
+	view = loadSavedView(args, ui.ThemeConfigFile{})
+	view.applySort()
+	assert.Equal(t, navigator.SortDescending, navigator.CurrentSortOrder())
+	m = ui.InitialModel([]interface{}{})
+	view.configure(&m)
+	assert.True(t, m.ColumnarView)
+	assert.Equal(t, []string{"uid"}, m.HiddenColumns)
+
+	// --no-view-state skips it.
+	noViewState = true
+	view = loadSavedView(args, ui.ThemeConfigFile{})
+	m = ui.InitialModel([]interface{}{})
+	view.configure(&m)
+	assert.False(t, m.ColumnarView)
+	assert.Nil(t, m.OnExitViewState)
+}
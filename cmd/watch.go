@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+)
+
+// validateWatchFlags checks --watch, which reads the input file again
+// whenever it changes. Like --refresh it needs a single input file.
+func validateWatchFlags(args []string) error {
+	if !watchInput {
+		return nil
+	}
+	switch {
+	case refreshInterval > 0:
+		return errors.New("--watch cannot be used with --refresh")
+	case streamInput:
+		return errors.New("--watch cannot be used with --stream")
+	case len(args) != 1 || args[0] == "-" || strings.TrimSpace(args[0]) == "":
+		return errors.New("--watch needs a single input file to watch")
+	}
+	return nil
+}
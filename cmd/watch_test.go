@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWatchFlags(t *testing.T) {
+	t.Cleanup(func() { watchInput, refreshInterval, streamInput = false, 0, false })
+	watchInput = false
+	require.NoError(t, validateWatchFlags(nil))
+
+	watchInput = true
+	require.NoError(t, validateWatchFlags([]string{"config.yaml"}))
+	assert.EqualError(t, validateWatchFlags(nil), "--watch needs a single input file to watch")
+	assert.EqualError(t, validateWatchFlags([]string{"-"}), "--watch needs a single input file to watch")
+	assert.EqualError(t, validateWatchFlags([]string{"a.yaml", "b.yaml"}), "--watch needs a single input file to watch")
+
+	refreshInterval = time.Second
+	assert.EqualError(t, validateWatchFlags([]string{"config.yaml"}), "--watch cannot be used with --refresh")
+	refreshInterval = 0
+	streamInput = true
+	assert.EqualError(t, validateWatchFlags([]string{"config.yaml"}), "--watch cannot be used with --stream")
+}
@@ -20,6 +20,8 @@ func main() {
 		"url":   "https://microsoft.com/devicelogin",
 		"code":  "EH5HFPGJJ",
 		"user":  "user@example.com",
+		// Seconds the code is valid, as in a device authorization response
+		"expires_in": 900,
 		"messages": []any{
 			"Already authenticated as user@example.com",
 			"Use 'myapp auth logout entra' to sign out first",
@@ -32,6 +34,10 @@ func main() {
 			TitleField:     "title",
 			MessageField:   "messages",
 			WaitMessage:    "Waiting for authentication...",
+			DeadlineField:  "expires_in",
+			ExpiredMessage: "The code has expired",
+			FailOnExpiry:   true,
+			QRField:        "url",
 			SuccessMessage: "Authenticated successfully!",
 			DoneBehavior:   tui.DoneBehaviorExitAfterDelay,
 			DoneDelay:      "2s",
@@ -72,17 +78,24 @@ func main() {
 		return
 	}
 
-	done := make(chan tui.StatusResult, 1)
+	// Report progress while the simulated sign-in runs, then finish.
+	status := tui.NewStatusController()
 	go func() {
-		time.Sleep(*timeout)
-		done <- tui.StatusResult{Message: "Authenticated as user@example.com"}
+		const steps = 10
+		for i := range steps {
+			status.SetProgress(float64(i) / steps)
+			time.Sleep(*timeout / steps)
+		}
+		status.SetProgress(1)
+		status.AddLine("Token received")
+		status.Finish(tui.StatusResult{Message: "Authenticated as user@example.com"})
 	}()
 
 	cfg := tui.DefaultConfig()
 	cfg.AppName = "myapp"
 	cfg.DisplaySchema = schema
 	cfg.KeyMode = *keyMode
-	cfg.Done = done
+	cfg.StatusController = status
 
 	if err := tui.Run(data, cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
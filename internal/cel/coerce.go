@@ -0,0 +1,270 @@
+package cel
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// coerceLib adds lenient conversions and lookups that do not fail on
+// missing keys, for arrays whose items do not all have the same shape:
+//
+//	toInt(x[, fallback])      ints, doubles (truncated), bools, numeric text
+//	toDouble(x[, fallback])   numbers, bools, numeric text
+//	toBool(x[, fallback])     bools, numbers (non-zero), text such as yes/no/on/off
+//	toString(x)               text; lists and maps as JSON, null as ""
+//	get(x, path, fallback)    value at a path such as "a.b[0]", or fallback
+//	hasPath(x, path)          whether that path exists
+//
+// get and hasPath are also methods: _.get("a.b", 0), _.hasPath("a.b").
+// Without a fallback, a value that cannot be converted is an error.
+func coerceLib() cel.EnvOption {
+	return cel.Lib(coerceLibrary{})
+}
+
+type coerceLibrary struct{}
+
+func (coerceLibrary) LibraryName() string { return "kvx.lib.coerce" }
+
+func (coerceLibrary) CompileOptions() []cel.EnvOption {
+	dyn, str := cel.DynType, cel.StringType
+	conversion := func(name string, out *cel.Type, convert func(ref.Val) (ref.Val, bool)) cel.EnvOption {
+		return cel.Function(name,
+			cel.Overload(name+"_dyn", []*cel.Type{dyn}, out, cel.UnaryBinding(func(v ref.Val) ref.Val {
+				if c, ok := convert(v); ok {
+					return c
+				}
+				return types.NewErr("%s: cannot convert %s %s", name, v.Type().TypeName(), describe(v))
+			})),
+			cel.Overload(name+"_dyn_"+out.String(), []*cel.Type{dyn, out}, out, cel.BinaryBinding(func(v, fallback ref.Val) ref.Val {
+				if c, ok := convert(v); ok {
+					return c
+				}
+				return fallback
+			})),
+		)
+	}
+	return []cel.EnvOption{
+		conversion("toInt", cel.IntType, toInt),
+		conversion("toDouble", cel.DoubleType, toDouble),
+		conversion("toBool", cel.BoolType, toBool),
+		cel.Function("toString",
+			cel.Overload("toString_dyn", []*cel.Type{dyn}, str, cel.UnaryBinding(toText)),
+		),
+		cel.Function("get",
+			cel.Overload("get_dyn_string_dyn", []*cel.Type{dyn, str, dyn}, dyn,
+				cel.FunctionBinding(getPath)),
+			cel.MemberOverload("dyn_get_string_dyn", []*cel.Type{dyn, str, dyn}, dyn,
+				cel.FunctionBinding(getPath)),
+		),
+		cel.Function("hasPath",
+			cel.Overload("hasPath_dyn_string", []*cel.Type{dyn, str}, cel.BoolType,
+				cel.BinaryBinding(hasPath)),
+			cel.MemberOverload("dyn_hasPath_string", []*cel.Type{dyn, str}, cel.BoolType,
+				cel.BinaryBinding(hasPath)),
+		),
+	}
+}
+
+func (coerceLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// describe quotes text values for error messages.
+func describe(v ref.Val) string {
+	if s, ok := v.(types.String); ok {
+		return strconv.Quote(string(s))
+	}
+	return "value"
+}
+
+func toInt(v ref.Val) (ref.Val, bool) {
+	switch n := v.(type) {
+	case types.Int:
+		return n, true
+	case types.Bool:
+		if n {
+			return types.Int(1), true
+		}
+		return types.Int(0), true
+	case types.String:
+		text := strings.TrimSpace(string(n))
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return types.Int(i), true
+		}
+		v = types.String(text)
+	}
+	f, ok := toDouble(v)
+	if !ok {
+		return nil, false
+	}
+	d := math.Trunc(float64(f.(types.Double)))
+	if math.IsNaN(d) || d < math.MinInt64 || d >= math.MaxInt64 {
+		return nil, false
+	}
+	return types.Int(d), true
+}
+
+func toDouble(v ref.Val) (ref.Val, bool) {
+	switch n := v.(type) {
+	case types.Double:
+		return n, true
+	case types.Int:
+		return types.Double(n), true
+	case types.Uint:
+		return types.Double(n), true
+	case types.Bool:
+		if n {
+			return types.Double(1), true
+		}
+		return types.Double(0), true
+	case types.String:
+		text := strings.ReplaceAll(strings.TrimSpace(string(n)), "_", "")
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return types.Double(f), true
+		}
+	}
+	return nil, false
+}
+
+func toBool(v ref.Val) (ref.Val, bool) {
+	switch b := v.(type) {
+	case types.Bool:
+		return b, true
+	case types.Int, types.Uint, types.Double:
+		f, _, _ := number(b)
+		return types.Bool(f != 0), true
+	case types.String:
+		switch strings.ToLower(strings.TrimSpace(string(b))) {
+		case "true", "yes", "y", "on", "1":
+			return types.True, true
+		case "false", "no", "n", "off", "0", "":
+			return types.False, true
+		}
+	case types.Null:
+		return types.False, true
+	}
+	return nil, false
+}
+
+func toText(v ref.Val) ref.Val {
+	switch t := v.(type) {
+	case types.String:
+		return t
+	case types.Null:
+		return types.String("")
+	case types.Double:
+		return types.String(strconv.FormatFloat(float64(t), 'f', -1, 64))
+	case traits.Lister, traits.Mapper:
+		out, err := json.Marshal(ToGo(v))
+		if err != nil {
+			return types.NewErr("toString: %v", err)
+		}
+		return types.String(out)
+	}
+	if s, ok := v.ConvertToType(types.StringType).(types.String); ok {
+		return s
+	}
+	return types.NewErr("toString: cannot convert %s", v.Type().TypeName())
+}
+
+// getPath is get(x, path, fallback).
+func getPath(args ...ref.Val) ref.Val {
+	path, ok := args[1].(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(args[1])
+	}
+	if v, found := lookupPath(args[0], string(path)); found {
+		return v
+	}
+	return args[2]
+}
+
+func hasPath(v, path ref.Val) ref.Val {
+	p, ok := path.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(path)
+	}
+	_, found := lookupPath(v, string(p))
+	return types.Bool(found)
+}
+
+// lookupPath follows a path of keys and indexes such as `a.b[0]["x.y"]`
+// (optionally starting with "_") from v.
+func lookupPath(v ref.Val, path string) (ref.Val, bool) {
+	segments, ok := pathSegments(path)
+	if !ok {
+		return nil, false
+	}
+	for _, seg := range segments {
+		switch c := v.(type) {
+		case traits.Mapper:
+			if v, ok = c.Find(types.String(seg)); !ok {
+				return nil, false
+			}
+		case traits.Lister:
+			i, err := strconv.Atoi(seg)
+			n := int(c.Size().(types.Int))
+			if err != nil || i < 0 || i >= n {
+				return nil, false
+			}
+			v = c.Get(types.Int(i))
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// pathSegments splits a path into its keys and indexes.
+func pathSegments(path string) ([]string, bool) {
+	path = strings.TrimSpace(path)
+	if path == "_" || strings.HasPrefix(path, "_.") || strings.HasPrefix(path, "_[") {
+		path = path[1:]
+	}
+	var segments []string
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+		case strings.HasPrefix(path, `["`):
+			quoted := path[1:]
+			end := strings.Index(quoted, `"]`)
+			for end > 0 && quoted[end-1] == '\\' {
+				next := strings.Index(quoted[end+1:], `"]`)
+				if next < 0 {
+					return nil, false
+				}
+				end += next + 1
+			}
+			if end < 0 {
+				return nil, false
+			}
+			key, err := strconv.Unquote(quoted[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			segments = append(segments, key)
+			path = quoted[end+2:]
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, false
+			}
+			segments = append(segments, strings.TrimSpace(path[1:end]))
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			segments = append(segments, path[:end])
+			path = path[end:]
+		}
+	}
+	return segments, true
+}
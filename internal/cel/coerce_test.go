@@ -0,0 +1,59 @@
+package cel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "a", "port": "8080", "enabled": "yes", "meta": map[string]interface{}{"dot.key": int64(1)}},
+			map[string]interface{}{"id": "b", "port": int64(9090), "enabled": true},
+			map[string]interface{}{"id": "c", "port": 7.9},
+		},
+		"big":   json.Number("12345678901234567890"),
+		"price": " 12.50 ",
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`_.items.map(x, toInt(x.port))`, []interface{}{int64(8080), int64(9090), int64(7)}},
+		{`toInt("010")`, int64(10)},
+		{`toInt(true) + toInt("3.9")`, int64(4)},
+		{`toInt("n/a", -1)`, int64(-1)},
+		{`toDouble(_.price)`, 12.5},
+		{`toDouble("1_000.5")`, 1000.5},
+		{`toDouble(null, 0.0)`, 0.0},
+		{`_.items.map(x, toBool(get(x, "enabled", false)))`, []interface{}{true, true, false}},
+		{`toBool("OFF")`, false},
+		{`toBool(2)`, true},
+		{`toBool("maybe", true)`, true},
+		{`toString(1.5) + toString(2) + toString(null) + toString("x")`, "1.52x"},
+		{`toString([1, {"a": true}])`, `[1,{"a":true}]`},
+		{`get(_, "items[0].meta[\"dot.key\"]", 0)`, int64(1)},
+		{`_.get("_.items[2].meta", "none")`, "none"},
+		{`_.items.filter(x, x.hasPath("meta")).map(x, x.id)`, []interface{}{"a"}},
+		{`hasPath(_, "items[5]")`, false},
+		{`hasPath(_, "items[1].port")`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`toInt("n/a")`, data)
+	require.ErrorContains(t, err, `toInt: cannot convert string "n/a"`)
+	_, err = eval.Evaluate(`toBool([1])`, data)
+	require.ErrorContains(t, err, "toBool: cannot convert list value")
+}
@@ -0,0 +1,128 @@
+package cel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"gopkg.in/yaml.v3"
+)
+
+// documentsLib adds functions that decode and encode documents embedded as
+// text, such as JSON payloads stored in a string field:
+//
+//	parseJSON(s)    value of the JSON text s
+//	parseYAML(s)    value of the YAML text s (the first document)
+//	toJSON(x)       x as compact JSON
+//	toYAML(x)       x as block-style YAML, without a trailing newline
+//
+// Decoded numbers follow the loader's rules: integers become int, other
+// numbers double.
+func documentsLib() cel.EnvOption {
+	return cel.Lib(documentsLibrary{})
+}
+
+type documentsLibrary struct{}
+
+func (documentsLibrary) LibraryName() string { return "kvx.lib.documents" }
+
+func (documentsLibrary) CompileOptions() []cel.EnvOption {
+	dyn, str := cel.DynType, cel.StringType
+	return []cel.EnvOption{
+		cel.Function("parseJSON",
+			cel.Overload("parseJSON_string", []*cel.Type{str}, dyn, cel.UnaryBinding(parseJSON)),
+		),
+		cel.Function("parseYAML",
+			cel.Overload("parseYAML_string", []*cel.Type{str}, dyn, cel.UnaryBinding(parseYAML)),
+		),
+		cel.Function("toJSON",
+			cel.Overload("toJSON_dyn", []*cel.Type{dyn}, str, cel.UnaryBinding(toJSON)),
+		),
+		cel.Function("toYAML",
+			cel.Overload("toYAML_dyn", []*cel.Type{dyn}, str, cel.UnaryBinding(toYAML)),
+		),
+	}
+}
+
+func (documentsLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+func parseJSON(s ref.Val) ref.Val {
+	text, ok := s.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(s)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(text)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return types.NewErr("parseJSON: %v", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return types.NewErr("parseJSON: invalid character after top-level value")
+	}
+	return numberAdapter{}.NativeToValue(plainValues(v))
+}
+
+func parseYAML(s ref.Val) ref.Val {
+	text, ok := s.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(s)
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(text), &v); err != nil {
+		return types.NewErr("parseYAML: %v", err)
+	}
+	return numberAdapter{}.NativeToValue(plainValues(v))
+}
+
+// plainValues converts decoded JSON numbers to int64 or float64, and the
+// maps YAML decodes for non-string keys such as `1: a` to maps keyed by the
+// keys' text, so results print like loaded data.
+func plainValues(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		return ToGo(numberValue(t))
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = plainValues(e)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = plainValues(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range t {
+			t[i] = plainValues(e)
+		}
+	}
+	return v
+}
+
+func toJSON(v ref.Val) ref.Val {
+	out, err := json.Marshal(ToGo(v))
+	if err != nil {
+		return types.NewErr("toJSON: %v", err)
+	}
+	return types.String(out)
+}
+
+func toYAML(v ref.Val) ref.Val {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(ToGo(v)); err != nil {
+		return types.NewErr("toYAML: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		return types.NewErr("toYAML: %v", err)
+	}
+	return types.String(strings.TrimSuffix(buf.String(), "\n"))
+}
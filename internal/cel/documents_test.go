@@ -0,0 +1,51 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"events": []interface{}{
+			map[string]interface{}{"payload": `{"type": "created", "id": 12345678901}`},
+			map[string]interface{}{"payload": `{"type": "deleted", "id": 2.5}`},
+		},
+		"manifest": "name: web\nreplicas: 3\nports: [80, 443]\n1: one\n",
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`_.events.map(e, parseJSON(e.payload).type)`, []interface{}{"created", "deleted"}},
+		{`_.events.map(e, type(parseJSON(e.payload).id))`, []interface{}{"int", "double"}},
+		{`parseJSON("[1, null]")`, []interface{}{int64(1), nil}},
+		{`parseYAML(_.manifest).replicas + 1`, int64(4)},
+		{`parseYAML(_.manifest).ports[1]`, int64(443)},
+		{`parseYAML(_.manifest)["1"]`, "one"},
+		{`parseYAML("")`, nil},
+		{`toJSON({"a": [1, 2.5, "x", null]})`, `{"a":[1,2.5,"x",null]}`},
+		{`toJSON(parseJSON(_.events[0].payload))`, `{"id":12345678901,"type":"created"}`},
+		{`toYAML({"name": "web", "ports": [80]})`, "name: web\nports:\n  - 80"},
+		{`toYAML("plain")`, "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`parseJSON("{")`, data)
+	require.ErrorContains(t, err, "parseJSON:")
+	_, err = eval.Evaluate(`parseJSON("1 2")`, data)
+	require.ErrorContains(t, err, "parseJSON: invalid character after top-level value")
+	_, err = eval.Evaluate(`parseYAML("a: [")`, data)
+	require.ErrorContains(t, err, "parseYAML:")
+}
@@ -0,0 +1,99 @@
+package cel
+
+import (
+	"crypto/md5" //nolint:gosec // md5 is offered for matching existing checksums, not for security
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// encodingLib adds hashes and encodings beside the base64 functions of the
+// encoders extension, for correlating IDs and debugging webhooks:
+//
+//	sha256(s) / md5(s)    hex digest of a string or bytes
+//	hex.encode(s)         hex text of a string or bytes
+//	hex.decode(s)         bytes of hex text
+//	urlEncode(s)          s escaped for a URL query
+//	urlDecode(s)          s with URL escapes undone
+func encodingLib() cel.EnvOption {
+	return cel.Lib(encodingLibrary{})
+}
+
+type encodingLibrary struct{}
+
+func (encodingLibrary) LibraryName() string { return "kvx.lib.encoding" }
+
+func (encodingLibrary) CompileOptions() []cel.EnvOption {
+	str, byts := cel.StringType, cel.BytesType
+	digest := func(name string, sum func([]byte) string) cel.EnvOption {
+		binding := cel.UnaryBinding(func(v ref.Val) ref.Val {
+			b, errVal := rawBytes(v)
+			if errVal != nil {
+				return errVal
+			}
+			return types.String(sum(b))
+		})
+		return cel.Function(name,
+			cel.Overload(name+"_string", []*cel.Type{str}, str, binding),
+			cel.Overload(name+"_bytes", []*cel.Type{byts}, str, binding),
+		)
+	}
+	return []cel.EnvOption{
+		digest("sha256", func(b []byte) string {
+			sum := sha256.Sum256(b)
+			return hex.EncodeToString(sum[:])
+		}),
+		digest("md5", func(b []byte) string {
+			sum := md5.Sum(b) //nolint:gosec // see import
+			return hex.EncodeToString(sum[:])
+		}),
+		digest("hex.encode", hex.EncodeToString),
+		cel.Function("hex.decode",
+			cel.Overload("hex.decode_string", []*cel.Type{str}, byts, cel.UnaryBinding(func(v ref.Val) ref.Val {
+				s, ok := v.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(v)
+				}
+				b, err := hex.DecodeString(string(s))
+				if err != nil {
+					return types.NewErr("hex.decode: %v", err)
+				}
+				return types.Bytes(b)
+			})),
+		),
+		cel.Function("urlEncode",
+			cel.Overload("urlEncode_string", []*cel.Type{str}, str,
+				cel.UnaryBinding(stringUnary(url.QueryEscape))),
+		),
+		cel.Function("urlDecode",
+			cel.Overload("urlDecode_string", []*cel.Type{str}, str, cel.UnaryBinding(func(v ref.Val) ref.Val {
+				s, ok := v.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(v)
+				}
+				out, err := url.QueryUnescape(string(s))
+				if err != nil {
+					return types.NewErr("urlDecode: %v", err)
+				}
+				return types.String(out)
+			})),
+		),
+	}
+}
+
+func (encodingLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// rawBytes returns the bytes of a string or bytes value.
+func rawBytes(v ref.Val) ([]byte, ref.Val) {
+	switch b := v.(type) {
+	case types.String:
+		return []byte(b), nil
+	case types.Bytes:
+		return []byte(b), nil
+	}
+	return nil, types.MaybeNoSuchOverloadErr(v)
+}
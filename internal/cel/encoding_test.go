@@ -0,0 +1,44 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodingFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"id":       "order-42",
+		"callback": "https://example.com/hook?x=a%20b&y=%E2%9C%93",
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`sha256("abc")`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`sha256(b"abc") == sha256("abc")`, true},
+		{`md5(_.id)`, "4f416b0c12aa8c754209da955fb755cc"},
+		{`hex.encode("hi")`, "6869"},
+		{`hex.encode(b"\xff\x00")`, "ff00"},
+		{`string(hex.decode("6869"))`, "hi"},
+		{`urlEncode("a b&c=d/é")`, "a+b%26c%3Dd%2F%C3%A9"},
+		{`urlDecode(_.callback.split("?")[1])`, "x=a b&y=✓"},
+		{`base64.encode(bytes(_.id))`, "b3JkZXItNDI="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`hex.decode("zz")`, data)
+	require.ErrorContains(t, err, "hex.decode: encoding/hex: invalid byte")
+	_, err = eval.Evaluate(`urlDecode("%zz")`, data)
+	require.ErrorContains(t, err, "urlDecode: invalid URL escape")
+}
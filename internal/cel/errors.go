@@ -0,0 +1,246 @@
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/parser"
+)
+
+// ErrorKind classifies an ExprError.
+type ErrorKind int
+
+const (
+	// KindEval is a runtime failure that is not classified further.
+	KindEval ErrorKind = iota
+	// KindParse is a syntax error or a check-time error such as an
+	// undeclared reference.
+	KindParse
+	// KindNoSuchKey is a lookup of a map key that does not exist.
+	KindNoSuchKey
+	// KindTypeMismatch is an operation applied to values of the wrong type.
+	KindTypeMismatch
+)
+
+// ExprError describes why an expression failed, located in its source. It
+// is built from cel-go's issue list and AST rather than its error wording;
+// Error returns the underlying error text unchanged.
+type ExprError struct {
+	Kind   ErrorKind
+	Pos    int    // Rune offset in the expression, or -1 if unknown
+	Msg    string // First issue message without location (KindParse)
+	Key    string // Missing key (KindNoSuchKey)
+	Parent string // Expression for the object Key was looked up on ("" for the root)
+	Op     string // Function or operator (KindTypeMismatch), when known
+	Want   string // Expected type (KindTypeMismatch), when known
+	Got    string // Actual type or argument types (KindTypeMismatch), when known
+	Err    error  // Underlying error
+}
+
+func (e *ExprError) Error() string { return e.Err.Error() }
+func (e *ExprError) Unwrap() error { return e.Err }
+
+// compileError describes issues reported while compiling expr in env. The
+// first issue is used; a failure at a call to a declared function is a type
+// mismatch, anything else a parse error.
+func compileError(env *cel.Env, expr string, data interface{}, issues *cel.Issues, err error) *ExprError {
+	out := &ExprError{Kind: KindParse, Pos: -1, Err: err}
+	errs := issues.Errors()
+	if len(errs) == 0 {
+		return out
+	}
+	first := errs[0]
+	out.Msg = first.Message
+	if first.Location != nil {
+		out.Pos = runeOffset(expr, first.Location.Line(), first.Location.Column())
+	}
+	parsed, iss := env.Parse(expr)
+	if iss != nil && iss.Err() != nil {
+		return out
+	}
+	node := findExpr(parsed.NativeRep().Expr(), first.ExprID)
+	if node == nil || node.Kind() != ast.CallKind || !env.HasFunction(node.AsCall().FunctionName()) {
+		return out
+	}
+	out.Kind = KindTypeMismatch
+	out.Op = node.AsCall().FunctionName()
+	out.Got = argTypes(env, node.AsCall(), parsed.NativeRep().SourceInfo(), data)
+	return out
+}
+
+// evalError describes a runtime failure of the compiled expression checked
+// against data. The failing node is taken from the error's expression id: a
+// call is a type mismatch, and a field or index chain rooted at _ is a
+// missing key when one of its map lookups fails on data.
+func evalError(env *cel.Env, checked *cel.Ast, data interface{}, err error) *ExprError {
+	out := &ExprError{Kind: KindEval, Pos: -1, Err: err}
+	var celErr *types.Err
+	if !errors.As(err, &celErr) {
+		return out
+	}
+	info := checked.NativeRep().SourceInfo()
+	node := findExpr(checked.NativeRep().Expr(), celErr.NodeID())
+	if node == nil {
+		return out
+	}
+	if node.Kind() == ast.CallKind && node.AsCall().FunctionName() != "_[_]" {
+		out.Kind = KindTypeMismatch
+		out.Op = node.AsCall().FunctionName()
+		out.Got = argTypes(env, node.AsCall(), info, data)
+		if o, ok := info.GetOffsetRange(node.ID()); ok {
+			out.Pos = int(o.Start)
+		}
+		return out
+	}
+	if missing, operand, ok := missingStep(node, data); ok {
+		out.Kind = KindNoSuchKey
+		out.Key = missing.key
+		out.Pos = missing.pos(info)
+		if operand.Kind() != ast.IdentKind {
+			out.Parent, _ = parser.Unparse(operand, info)
+		}
+	}
+	return out
+}
+
+// notBoolError reports a where filter whose result has type got.
+func notBoolError(got string) *ExprError {
+	return &ExprError{
+		Kind: KindTypeMismatch,
+		Pos:  -1,
+		Want: "bool",
+		Got:  got,
+		Err:  fmt.Errorf("where filter expression must return a boolean, got %s", got),
+	}
+}
+
+// chainStep is one map lookup in a field or index chain.
+type chainStep struct {
+	id    int64 // Select node, or the string literal of an index
+	key   string
+	index bool
+}
+
+// pos returns the rune offset of the step's key: the field name after the
+// dot, or the first character inside the index quotes.
+func (s chainStep) pos(info *ast.SourceInfo) int {
+	o, ok := info.GetOffsetRange(s.id)
+	if !ok {
+		return -1
+	}
+	return int(o.Start) + 1
+}
+
+// missingStep walks the lookup chain ending at node down to the root _ and
+// replays it on data, returning the first step whose key is absent and the
+// expression it was looked up on.
+func missingStep(node ast.Expr, data interface{}) (chainStep, ast.Expr, bool) {
+	var steps []chainStep
+	var operands []ast.Expr
+	cur := node
+	for cur.Kind() != ast.IdentKind {
+		switch {
+		case cur.Kind() == ast.SelectKind:
+			sel := cur.AsSelect()
+			steps = append(steps, chainStep{id: cur.ID(), key: sel.FieldName()})
+			operands = append(operands, sel.Operand())
+			cur = sel.Operand()
+		case cur.Kind() == ast.CallKind && cur.AsCall().FunctionName() == "_[_]" && len(cur.AsCall().Args()) == 2:
+			args := cur.AsCall().Args()
+			step := chainStep{id: args[1].ID(), index: true}
+			if args[1].Kind() == ast.LiteralKind {
+				if s, ok := args[1].AsLiteral().(types.String); ok {
+					step.key = string(s)
+				}
+			}
+			steps = append(steps, step)
+			operands = append(operands, args[0])
+			cur = args[0]
+		default:
+			return chainStep{}, nil, false
+		}
+	}
+	if cur.AsIdent() != "_" {
+		return chainStep{}, nil, false
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		obj, ok := data.(map[string]interface{})
+		if !ok || (steps[i].index && steps[i].key == "") {
+			return chainStep{}, nil, false
+		}
+		v, found := obj[steps[i].key]
+		if !found {
+			return steps[i], operands[i], true
+		}
+		data = v
+	}
+	return chainStep{}, nil, false
+}
+
+// argTypes lists the types of a call's target and arguments, e.g.
+// "int, string". Each operand is evaluated against data for its runtime
+// type; operands that cannot be evaluated on their own (lambda variables)
+// fall back to their static type.
+func argTypes(env *cel.Env, call ast.CallExpr, info *ast.SourceInfo, data interface{}) string {
+	operands := call.Args()
+	if call.IsMemberFunction() {
+		operands = append([]ast.Expr{call.Target()}, operands...)
+	}
+	names := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		names = append(names, operandType(env, operand, info, data))
+	}
+	return strings.Join(names, ", ")
+}
+
+func operandType(env *cel.Env, operand ast.Expr, info *ast.SourceInfo, data interface{}) string {
+	text, err := parser.Unparse(operand, info)
+	if err != nil {
+		return "dyn"
+	}
+	checked, issues := env.Compile(text)
+	if issues != nil && issues.Err() != nil {
+		return "dyn"
+	}
+	if prg, err := env.Program(checked); err == nil {
+		if out, _, err := prg.Eval(map[string]interface{}{"_": data}); err == nil {
+			return out.Type().TypeName()
+		}
+	}
+	return checked.OutputType().String()
+}
+
+// findExpr returns the node of root with the given id, or nil.
+func findExpr(root ast.Expr, id int64) ast.Expr {
+	var found ast.Expr
+	ast.PreOrderVisit(root, ast.NewExprVisitor(func(e ast.Expr) {
+		if found == nil && e.ID() == id {
+			found = e
+		}
+	}))
+	return found
+}
+
+// runeOffset converts a 1-based line and 0-based rune column into a rune
+// offset within expr, clamped to its length.
+func runeOffset(expr string, line, col int) int {
+	lines := strings.Split(expr, "\n")
+	if line < 1 || line > len(lines) {
+		return -1
+	}
+	offset := 0
+	for _, l := range lines[:line-1] {
+		offset += len([]rune(l)) + 1
+	}
+	if col < 0 {
+		col = 0
+	}
+	if n := len([]rune(lines[line-1])); col > n {
+		col = n
+	}
+	return offset + col
+}
@@ -2,9 +2,12 @@ package cel
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/decls"
@@ -18,9 +21,15 @@ type Evaluator struct {
 	env *cel.Env
 }
 
+// standardEnv is the standard CEL environment, built on first use and shared
+// by every Evaluator: a cel.Env is immutable and safe for concurrent use, and
+// building one dominates the cost of NewEvaluator.
+var standardEnv = sync.OnceValues(func() (*cel.Env, error) { return newStandardCELEnv() })
+
 // NewEvaluator creates a new CEL evaluator with standard library functions.
+// Evaluators share one environment and are safe for concurrent use.
 func NewEvaluator() (*Evaluator, error) {
-	env, err := newStandardCELEnv()
+	env, err := standardEnv()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
@@ -35,14 +44,29 @@ func (e *Evaluator) GetEnvironment() *cel.Env {
 // newStandardCELEnv creates a standard CEL environment with common extensions.
 // Additional options can be provided to extend the environment (e.g., custom functions).
 func newStandardCELEnv(opts ...cel.EnvOption) (*cel.Env, error) {
-	allOpts := make([]cel.EnvOption, 0, 5+len(opts))
+	return newTypedCELEnv(cel.DynType, opts...)
+}
+
+// newTypedCELEnv is newStandardCELEnv with '_' declared as root instead of dyn.
+func newTypedCELEnv(root *cel.Type, opts ...cel.EnvOption) (*cel.Env, error) {
+	allOpts := make([]cel.EnvOption, 0, 15+len(opts))
 	allOpts = append(allOpts,
-		cel.Variable("_", cel.DynType),
+		cel.Variable("_", root),
+		// Keep loader-preserved json.Number values numeric
+		cel.CustomTypeAdapter(numberAdapter{}),
 		// Enable common extension libraries so discovery surfaces richer functions
 		celext.Strings(),
 		celext.Encoders(),
 		celext.Lists(),
 		celext.Math(),
+		findLib(),
+		setsLib(),
+		stringsLib(),
+		timesLib(),
+		statsLib(),
+		coerceLib(),
+		documentsLib(),
+		encodingLib(),
 		// Note: Maps/Sets/Bytes extensions not available in our cel-go version
 	)
 	allOpts = append(allOpts, opts...)
@@ -52,11 +76,26 @@ func newStandardCELEnv(opts ...cel.EnvOption) (*cel.Env, error) {
 // EvaluateExpressionWithEnv evaluates a CEL expression using the given environment.
 // It handles compilation, program creation, evaluation, and result conversion.
 // This is a shared helper used by both Evaluator and celEnvProvider for consistency.
+//
+// Expressions may be chained with '|': each stage's result becomes '_' for
+// the next, e.g. "_.items | filter(x, x.ok) | map(x, x.name) | sort()".
 func EvaluateExpressionWithEnv(env *cel.Env, expr string, data interface{}) (interface{}, error) {
+	if stages := SplitPipeline(expr); len(stages) > 1 {
+		return evaluatePipeline(env, stages, data)
+	}
+	return evaluateExpression(env, expr, data)
+}
+
+// evaluateExpression evaluates a single CEL expression.
+func evaluateExpression(env *cel.Env, expr string, data interface{}) (interface{}, error) {
 	// Compile the expression (parse + type check)
 	ast, issues := env.Compile(expr)
 	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("compilation error: %w", issues.Err())
+		return nil, compileError(env, expr, data, issues, fmt.Errorf("compilation error: %w", issues.Err()))
+	}
+
+	if n, ok := selectedNumber(ast, data); ok {
+		return n, nil
 	}
 
 	// Create program
@@ -70,7 +109,7 @@ func EvaluateExpressionWithEnv(env *cel.Env, expr string, data interface{}) (int
 		"_": data,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("eval error: %w", err)
+		return nil, evalError(env, ast, data, fmt.Errorf("eval error: %w", err))
 	}
 
 	// Convert CEL result back to Go types
@@ -89,6 +128,7 @@ func EvaluateExpressionWithEnv(env *cel.Env, expr string, data interface{}) (int
 // Evaluate evaluates a CEL expression against data.
 // The expression can reference the data with the variable name "_".
 // Example: "_.items[0]" or "_.items.filter(x, x.available == true)"
+// Stages chained with '|' are evaluated in turn, see EvaluateExpressionWithEnv.
 func (e *Evaluator) Evaluate(expr string, data interface{}) (interface{}, error) {
 	return EvaluateExpressionWithEnv(e.env, expr, data)
 }
@@ -109,12 +149,12 @@ func (e *Evaluator) EvaluateWhere(expr string, data interface{}) ([]interface{},
 	// Compile the program once and reuse for each item.
 	ast, issues := e.env.Compile(expr)
 	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("where filter compilation error: %w", issues.Err())
+		return nil, compileError(e.env, expr, nil, issues, fmt.Errorf("where filter compilation error: %w", issues.Err()))
 	}
 
 	// Reject expressions whose compile-time output type is known and non-boolean.
 	if ot := ast.OutputType(); ot != nil && ot.String() != "bool" && ot.String() != "dyn" {
-		return nil, fmt.Errorf("where filter expression must return a boolean, got %s", ot)
+		return nil, notBoolError(ot.String())
 	}
 
 	prg, err := e.env.Program(ast)
@@ -136,12 +176,12 @@ func (e *Evaluator) EvaluateWhere(expr string, data interface{}) ([]interface{},
 			if strings.Contains(err.Error(), "no such key: ") {
 				continue
 			}
-			return nil, fmt.Errorf("where filter eval error: %w", err)
+			return nil, evalError(e.env, ast, item, fmt.Errorf("where filter eval error: %w", err))
 		}
 
 		b, ok := out.Value().(bool)
 		if !ok {
-			return nil, fmt.Errorf("where filter expression must return a boolean, got %s", out.Type().TypeName())
+			return nil, notBoolError(out.Type().TypeName())
 		}
 
 		if b {
@@ -189,6 +229,8 @@ func ToGo(val ref.Val) interface{} {
 		return string(v)
 	case types.Bytes:
 		return []byte(v)
+	case types.Null:
+		return nil
 	}
 
 	// Handle CEL collections - try to extract using Value() method
@@ -206,18 +248,7 @@ func ToGo(val ref.Val) interface{} {
 
 		// If Value() returns a slice, recursively convert elements
 		if slice, ok := innerVal.([]interface{}); ok {
-			result := make([]interface{}, len(slice))
-			for i, elem := range slice {
-				// Recursively convert each element
-				if refVal, ok := elem.(ref.Val); ok {
-					result[i] = ToGo(refVal)
-				} else if elemMap, ok := elem.(map[string]interface{}); ok {
-					// Recursively convert map elements
-					result[i] = convertMapValues(elemMap)
-				} else {
-					result[i] = elem
-				}
-			}
+			result, _ := convertSliceValues(slice)
 			return result
 		}
 
@@ -251,29 +282,62 @@ func ToGo(val ref.Val) interface{} {
 	return val
 }
 
-// convertMapValues recursively converts map values from CEL types
+// convertMapValues recursively converts map values from CEL types. Maps
+// holding no CEL values are returned as-is, so values taken from the input
+// keep their identity and with it their recorded key order and positions.
 func convertMapValues(m map[string]interface{}) map[string]interface{} {
-	result := make(map[string]interface{})
+	result, _ := convertMap(m)
+	return result
+}
+
+// convertMap is convertMapValues, also reporting whether a copy was made.
+func convertMap(m map[string]interface{}) (map[string]interface{}, bool) {
+	var result map[string]interface{}
 	for k, v := range m {
-		if refVal, ok := v.(ref.Val); ok {
-			result[k] = ToGo(refVal)
-		} else if innerMap, ok := v.(map[string]interface{}); ok {
-			result[k] = convertMapValues(innerMap)
-		} else if slice, ok := v.([]interface{}); ok {
-			converted := make([]interface{}, len(slice))
-			for i, elem := range slice {
-				if refVal, ok := elem.(ref.Val); ok {
-					converted[i] = ToGo(refVal)
-				} else {
-					converted[i] = elem
-				}
-			}
-			result[k] = converted
-		} else {
-			result[k] = v
+		converted, changed := convertValue(v)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = maps.Clone(m)
 		}
+		result[k] = converted
 	}
-	return result
+	if result == nil {
+		return m, false
+	}
+	return result, true
+}
+
+// convertSliceValues converts the elements of slice like convertMap.
+func convertSliceValues(slice []interface{}) ([]interface{}, bool) {
+	var result []interface{}
+	for i, elem := range slice {
+		converted, changed := convertValue(elem)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = slices.Clone(slice)
+		}
+		result[i] = converted
+	}
+	if result == nil {
+		return slice, false
+	}
+	return result, true
+}
+
+func convertValue(v interface{}) (interface{}, bool) {
+	switch t := v.(type) {
+	case ref.Val:
+		return ToGo(t), true
+	case map[string]interface{}:
+		return convertMap(t)
+	case []interface{}:
+		return convertSliceValues(t)
+	}
+	return v, false
 }
 
 // IsCELExpression detects if a string contains CEL operators or functions.
@@ -289,6 +353,10 @@ func IsCELExpression(expr string) bool {
 			return true
 		}
 	}
+	// Check for pipelines such as "_.items | size()"
+	if len(SplitPipeline(expr)) > 1 {
+		return true
+	}
 	// Check for CEL operators
 	celOps := []string{"==", "!=", "<=", ">=", "<", ">", "&&", "||", "!"}
 	for _, op := range celOps {
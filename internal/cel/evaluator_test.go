@@ -7,6 +7,7 @@ import (
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 // Ensure DiscoverCELFunctions returns a rich set when extension libs are loaded.
@@ -827,3 +828,27 @@ func TestTypeLabel_WithType(t *testing.T) {
 		t.Errorf("typeLabel(BoolType) should return a type name, got %q", result)
 	}
 }
+
+func TestEvaluatePreservesRecordedKeyOrder(t *testing.T) {
+	inner := map[string]interface{}{"zeta": 1, "alpha": 2}
+	order := keyorder.NewOrder()
+	order.Record(inner, []string{"zeta", "alpha"})
+	root := map[string]interface{}{"spec": inner}
+
+	eval, err := NewEvaluator()
+	if err != nil {
+		t.Fatalf("NewEvaluator() error: %v", err)
+	}
+	got, err := eval.Evaluate("_.spec", root)
+	if err != nil {
+		t.Fatalf("Evaluate() error: %v", err)
+	}
+	m, ok := got.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map result, got %T", got)
+	}
+	// Values taken from the input keep their identity, and with it their order.
+	if keys, ok := order.Keys(m); !ok || keys[0] != "zeta" {
+		t.Fatalf("expected recorded order [zeta alpha], got %v", keys)
+	}
+}
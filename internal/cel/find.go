@@ -0,0 +1,57 @@
+package cel
+
+import (
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// FindKey returns every value stored under key at any depth below node,
+// walking maps in sorted key order and lists in index order. A match is
+// still searched, so nested values under the same key are returned too.
+func FindKey(node interface{}, key string) []interface{} {
+	results := []interface{}{}
+	var walk func(n interface{})
+	walk = func(n interface{}) {
+		switch t := n.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if k == key {
+					results = append(results, t[k])
+				}
+				walk(t[k])
+			}
+		case []interface{}:
+			for _, v := range t {
+				walk(v)
+			}
+		}
+	}
+	walk(node)
+	return results
+}
+
+// findLib declares find(node, key), the CEL form of the ..key recursive
+// descent path: find(_, "name") lists every "name" value in the document.
+func findLib() cel.EnvOption {
+	return cel.Function("find",
+		cel.Overload("find_dyn_string",
+			[]*cel.Type{cel.DynType, cel.StringType},
+			cel.ListType(cel.DynType),
+			cel.BinaryBinding(func(node, key ref.Val) ref.Val {
+				k, ok := key.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(key)
+				}
+				return types.NewDynamicList(numberAdapter{}, FindKey(ToGo(node), string(k)))
+			}),
+		),
+	)
+}
@@ -0,0 +1,51 @@
+package cel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindKey(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "root",
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "meta": map[string]interface{}{"name": "a-meta"}},
+			map[string]interface{}{"id": 2},
+		},
+	}
+	got := FindKey(data, "name")
+	want := []interface{}{"a-meta", "a", "root"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindKey = %#v, want %#v", got, want)
+	}
+	if got := FindKey(data, "missing"); len(got) != 0 {
+		t.Fatalf("expected no matches, got %#v", got)
+	}
+}
+
+func TestEvaluateFind(t *testing.T) {
+	eval, err := NewEvaluator()
+	if err != nil {
+		t.Fatalf("NewEvaluator() error: %v", err)
+	}
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "x"},
+			map[string]interface{}{"child": map[string]interface{}{"id": "y"}},
+		},
+	}
+	got, err := eval.Evaluate(`find(_, "id")`, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []interface{}{"x", "y"}) {
+		t.Fatalf(`find(_, "id") = %#v`, got)
+	}
+	got, err = eval.Evaluate(`find(_.items[1], "id").size()`, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != int64(1) {
+		t.Fatalf("size = %#v, want 1", got)
+	}
+}
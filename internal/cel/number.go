@@ -0,0 +1,98 @@
+package cel
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// numberAdapter adapts json.Number values, which the loader keeps for numbers
+// float64 cannot represent exactly, to plain CEL values so they work on
+// either side of an operator and in every function: integers that fit int64
+// become int, other numbers double, and numbers beyond the double range a
+// string holding their digits. Maps and lists are wrapped with this adapter
+// so nested values are adapted too; everything else goes to the default
+// adapter.
+type numberAdapter struct{}
+
+func (numberAdapter) NativeToValue(value any) ref.Val {
+	switch v := value.(type) {
+	case json.Number:
+		return numberValue(v)
+	case map[string]any:
+		return types.NewStringInterfaceMap(numberAdapter{}, v)
+	case []any:
+		return types.NewDynamicList(numberAdapter{}, v)
+	}
+	return types.DefaultTypeAdapter.NativeToValue(value)
+}
+
+func numberValue(n json.Number) ref.Val {
+	if i, err := n.Int64(); err == nil {
+		return types.Int(i)
+	}
+	if f, err := n.Float64(); err == nil && !math.IsInf(f, 0) {
+		return types.Double(f)
+	}
+	return types.String(n)
+}
+
+// NumberType is the CEL type name a json.Number from the loader has in
+// expressions: int, double, or string.
+func NumberType(n json.Number) string {
+	return numberValue(n).Type().TypeName()
+}
+
+// selectedNumber returns the json.Number an expression such as `_.price` or
+// `_.items[0]["v"]` selects unchanged from data. Inside CEL the number is an
+// int or double that may not hold every digit, so a plain selection reads it
+// from data instead to keep the original.
+func selectedNumber(checked *cel.Ast, data interface{}) (json.Number, bool) {
+	var steps []ref.Val
+	cur := checked.NativeRep().Expr()
+	for cur.Kind() != ast.IdentKind {
+		switch {
+		case cur.Kind() == ast.SelectKind && !cur.AsSelect().IsTestOnly():
+			steps = append(steps, types.String(cur.AsSelect().FieldName()))
+			cur = cur.AsSelect().Operand()
+		case cur.Kind() == ast.CallKind && cur.AsCall().FunctionName() == "_[_]" && len(cur.AsCall().Args()) == 2:
+			args := cur.AsCall().Args()
+			if args[1].Kind() != ast.LiteralKind {
+				return "", false
+			}
+			steps = append(steps, args[1].AsLiteral())
+			cur = args[0]
+		default:
+			return "", false
+		}
+	}
+	if cur.AsIdent() != "_" || len(steps) == 0 {
+		return "", false
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		switch step := steps[i].(type) {
+		case types.String:
+			m, ok := data.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			if data, ok = m[string(step)]; !ok {
+				return "", false
+			}
+		case types.Int:
+			arr, ok := data.([]interface{})
+			if !ok || step < 0 || int(step) >= len(arr) {
+				return "", false
+			}
+			data = arr[step]
+		default:
+			return "", false
+		}
+	}
+	n, ok := data.(json.Number)
+	return n, ok
+}
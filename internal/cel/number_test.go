@@ -0,0 +1,54 @@
+package cel
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEvaluateJSONNumber(t *testing.T) {
+	eval, err := NewEvaluator()
+	if err != nil {
+		t.Fatalf("NewEvaluator() error: %v", err)
+	}
+	data := map[string]interface{}{
+		"price": json.Number("12345678901234567.891"),
+		"id":    json.Number("9007199254740993"),
+		"huge":  json.Number("1e400"),
+		"items": []interface{}{map[string]interface{}{"v": json.Number("0.10000000000000000001")}},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"_.price", json.Number("12345678901234567.891")},
+		{"_.items[0].v", json.Number("0.10000000000000000001")},
+		{`_.items[0]["v"]`, json.Number("0.10000000000000000001")},
+		{"_.id", json.Number("9007199254740993")},
+		{"_.id == 9007199254740993", true},
+		{"_.id + 1", int64(9007199254740994)},
+		{"_.price > 1.0", true},
+		{"_.price == _.price", true},
+		{"_.price + 1.0", 12345678901234568.0},
+		{"string(_.id)", "9007199254740993"},
+		{"_.items[0].v * 1.0", 0.1},
+		// Numbers on the right-hand side and as function arguments.
+		{"1.0 + _.price", 12345678901234568.0},
+		{"100.0 > _.price", false},
+		{"9007199254740994 > _.id", true},
+		{"1 + _.id", int64(9007199254740994)},
+		{"math.greatest(_.price, 1.0)", 12345678901234568.0},
+		{"math.ceil(_.items[0].v)", 1.0},
+		{"type(_.price) == double", true},
+		{"type(_.huge)", "string"},
+	}
+	for _, tt := range tests {
+		got, err := eval.Evaluate(tt.expr, data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+)
+
+// PipelineStage is one stage of an expression pipeline such as
+// `_.items | filter(x, x.ok) | map(x, x.name) | sort()`.
+type PipelineStage struct {
+	Text   string // Stage as written, trimmed
+	Offset int    // Rune offset of Text in the pipeline
+}
+
+// stageCallPattern matches a stage that starts with a function call.
+var stageCallPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*\(`)
+
+// SplitPipeline splits expr at every '|' that is not part of '||' and not
+// inside a string literal or brackets. An expression without one is a single
+// stage.
+func SplitPipeline(expr string) []PipelineStage {
+	runes := []rune(expr)
+	var stages []PipelineStage
+	start, depth := 0, 0
+	var quote string
+	cut := func(end int) {
+		text := string(runes[start:end])
+		trimmed := strings.TrimLeft(text, " \t\r\n")
+		offset := start + len([]rune(text)) - len([]rune(trimmed))
+		stages = append(stages, PipelineStage{Text: strings.TrimSpace(trimmed), Offset: offset})
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != "" {
+			switch {
+			case r == '\\':
+				i++
+			case strings.HasPrefix(string(runes[i:]), quote):
+				i += len(quote) - 1
+				quote = ""
+			}
+			continue
+		}
+		switch r {
+		case '"', '\'':
+			quote = string(r)
+			if triple := strings.Repeat(quote, 3); strings.HasPrefix(string(runes[i:]), triple) {
+				quote = triple
+				i += 2
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case '|':
+			if i+1 < len(runes) && runes[i+1] == '|' {
+				i++
+				continue
+			}
+			if depth == 0 {
+				cut(i)
+				start = i + 1
+			}
+		}
+	}
+	cut(len(runes))
+	return stages
+}
+
+// Source returns the stage as a CEL expression over '_' and the number of
+// runes added in front of Text. A stage starting with a field selection
+// (".name") or with a call that never mentions '_' ("filter(x, x.ok)",
+// "sort()") is applied to the previous result.
+func (s PipelineStage) Source(env *cel.Env) (string, int) {
+	if strings.HasPrefix(s.Text, ".") && !strings.HasPrefix(s.Text, "..") {
+		return "_" + s.Text, 1
+	}
+	if !stageCallPattern.MatchString(s.Text) {
+		return s.Text, 0
+	}
+	parsed, issues := env.Parse(s.Text)
+	if issues != nil && issues.Err() != nil {
+		return s.Text, 0
+	}
+	uses := ast.MatchDescendants(ast.NavigateAST(parsed.NativeRep()), func(e ast.NavigableExpr) bool {
+		return e.Kind() == ast.IdentKind && e.AsIdent() == "_"
+	})
+	if len(uses) > 0 {
+		return s.Text, 0
+	}
+	return "_." + s.Text, 2
+}
+
+// evaluatePipeline evaluates each stage with the result of the one before it
+// bound to '_'.
+func evaluatePipeline(env *cel.Env, stages []PipelineStage, data interface{}) (interface{}, error) {
+	for i, stage := range stages {
+		if stage.Text == "" {
+			return nil, &ExprError{
+				Kind: KindParse,
+				Pos:  stage.Offset,
+				Msg:  "empty pipeline stage",
+				Err:  fmt.Errorf("compilation error: pipeline stage %d is empty", i+1),
+			}
+		}
+		src, added := stage.Source(env)
+		out, err := evaluateExpression(env, src, data)
+		if err != nil {
+			return nil, stageError(err, stages[:i], stage, added)
+		}
+		data = out
+	}
+	return data, nil
+}
+
+// stageError locates an error of a pipeline stage in the whole pipeline: its
+// position is moved to where the stage was written, and the object a missing
+// key was looked up on is given as the pipeline that produces it.
+func stageError(err error, before []PipelineStage, stage PipelineStage, added int) error {
+	var ee *ExprError
+	if !errors.As(err, &ee) {
+		return err
+	}
+	located := *ee
+	if located.Pos >= 0 {
+		located.Pos = stage.Offset + max(located.Pos-added, 0)
+	}
+	if len(before) > 0 && located.Kind == KindNoSuchKey {
+		texts := make([]string, 0, len(before)+1)
+		for _, s := range before {
+			texts = append(texts, s.Text)
+		}
+		if located.Parent != "" {
+			texts = append(texts, located.Parent)
+		}
+		located.Parent = strings.Join(texts, " | ")
+	}
+	return &located
+}
+
+// checkPipeline type-checks every stage of expr in order, typing '_' in each
+// stage as the result of the one before it. The first stage is checked in
+// env; later stages in the environment next returns for the previous type.
+func checkPipeline(env *cel.Env, expr string, next func(*cel.Type) (*cel.Env, error)) (*cel.Ast, error) {
+	stages := SplitPipeline(expr)
+	var checked *cel.Ast
+	for i, stage := range stages {
+		if stage.Text == "" && len(stages) > 1 {
+			return nil, fmt.Errorf("compilation error: pipeline stage %d is empty", i+1)
+		}
+		if i > 0 {
+			var err error
+			if env, err = next(checked.OutputType()); err != nil {
+				return nil, err
+			}
+		}
+		src := expr
+		if len(stages) > 1 {
+			src, _ = stage.Source(env)
+		}
+		var issues *cel.Issues
+		checked, issues = env.Compile(src)
+		if issues != nil && issues.Err() != nil {
+			if len(stages) > 1 {
+				return nil, fmt.Errorf("compilation error in pipeline stage %d: %w", i+1, issues.Err())
+			}
+			return nil, fmt.Errorf("compilation error: %w", issues.Err())
+		}
+	}
+	return checked, nil
+}
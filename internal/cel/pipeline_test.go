@@ -0,0 +1,85 @@
+package cel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPipeline(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`_.items`, []string{`_.items`}},
+		{`_.a || _.b`, []string{`_.a || _.b`}},
+		{`_.items | filter(x, x.ok) | size()`, []string{`_.items`, `filter(x, x.ok)`, `size()`}},
+		{`_.items.filter(x, x.a || x.b) | size()`, []string{`_.items.filter(x, x.a || x.b)`, `size()`}},
+		{`_["a|b"] | '|' | """|"""`, []string{`_["a|b"]`, `'|'`, `"""|"""`}},
+		{`"\"|" | _`, []string{`"\"|"`, `_`}},
+		{`_ |`, []string{`_`, ``}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			var got []string
+			for _, s := range SplitPipeline(tt.expr) {
+				got = append(got, s.Text)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluatePipeline(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "b", "ok": true},
+			map[string]interface{}{"name": "c", "ok": false},
+			map[string]interface{}{"name": "a", "ok": true},
+		},
+		"meta": map[string]interface{}{"owner": "x"},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`_.items | filter(x, x.ok) | map(x, x.name) | sort()`, []interface{}{"a", "b"}},
+		{`_.items | size()`, int64(3)},
+		{`_.items | size(_) * 2`, int64(6)},
+		{`_.meta | .owner`, "x"},
+		{`_.items[0] | _.ok || false`, true},
+		{`_.meta | {"owner": _.owner + "!"}`, map[string]interface{}{"owner": "x!"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluatePipeline_Errors(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{"meta": map[string]interface{}{"owner": "x"}}
+
+	_, err = eval.Evaluate(`_.meta | .ownr`, data)
+	var ee *ExprError
+	require.True(t, errors.As(err, &ee), "got %v", err)
+	assert.Equal(t, KindNoSuchKey, ee.Kind)
+	assert.Equal(t, "ownr", ee.Key)
+	assert.Equal(t, "_.meta", ee.Parent)
+	assert.Equal(t, 10, ee.Pos)
+
+	_, err = eval.Evaluate(`_.meta |  | size()`, data)
+	require.True(t, errors.As(err, &ee), "got %v", err)
+	assert.Equal(t, KindParse, ee.Kind)
+	assert.Equal(t, 10, ee.Pos)
+	assert.EqualError(t, err, "compilation error: pipeline stage 2 is empty")
+}
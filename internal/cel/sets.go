@@ -0,0 +1,222 @@
+package cel
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// setsLib declares global helpers for deduplicating and comparing lists,
+// such as the IDs found in two API dumps:
+//
+//	distinct(list)       elements in first-seen order, without duplicates
+//	union(a, b)          distinct elements of a, then those only in b
+//	intersect(a, b)      distinct elements of a that are also in b
+//	difference(a, b)     distinct elements of a that are not in b
+//	sortBy(list, "a.b")  elements sorted by the value at a dotted key path
+//
+// Elements are compared with CEL equality, so 1 and 1.0 are the same
+// element. The method forms list.distinct() and list.sortBy(x, expr) come
+// from the lists extension.
+func setsLib() cel.EnvOption {
+	return cel.Lib(setsLibrary{})
+}
+
+type setsLibrary struct{}
+
+func (setsLibrary) LibraryName() string { return "kvx.lib.sets" }
+
+func (setsLibrary) CompileOptions() []cel.EnvOption {
+	listT := cel.ListType(cel.TypeParamType("T"))
+	return []cel.EnvOption{
+		cel.Function("distinct",
+			cel.Overload("distinct_list", []*cel.Type{listT}, listT,
+				cel.UnaryBinding(func(list ref.Val) ref.Val { return distinctOf(list) }),
+			),
+		),
+		cel.Function("union",
+			cel.Overload("union_list_list", []*cel.Type{listT, listT}, listT,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val { return distinctOf(a, b) }),
+			),
+		),
+		cel.Function("intersect",
+			cel.Overload("intersect_list_list", []*cel.Type{listT, listT}, listT,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val { return filterBy(a, b, true) }),
+			),
+		),
+		cel.Function("difference",
+			cel.Overload("difference_list_list", []*cel.Type{listT, listT}, listT,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val { return filterBy(a, b, false) }),
+			),
+		),
+		cel.Function("sortBy",
+			cel.Overload("sortBy_list_string", []*cel.Type{listT, cel.StringType}, listT,
+				cel.BinaryBinding(sortByPath),
+			),
+		),
+	}
+}
+
+func (setsLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// valueSet holds distinct CEL values. Strings, the usual IDs, are looked up
+// in a map; other values are compared one by one.
+type valueSet struct {
+	strs   map[types.String]bool
+	others []ref.Val
+}
+
+func (s *valueSet) has(v ref.Val) bool {
+	if str, ok := v.(types.String); ok {
+		return s.strs[str]
+	}
+	for _, o := range s.others {
+		if v.Equal(o) == types.True {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds v and reports whether it was not in the set yet.
+func (s *valueSet) add(v ref.Val) bool {
+	if s.has(v) {
+		return false
+	}
+	if str, ok := v.(types.String); ok {
+		if s.strs == nil {
+			s.strs = map[types.String]bool{}
+		}
+		s.strs[str] = true
+	} else {
+		s.others = append(s.others, v)
+	}
+	return true
+}
+
+// listElems returns the elements of a CEL list.
+func listElems(v ref.Val) ([]ref.Val, bool) {
+	l, ok := v.(traits.Lister)
+	if !ok {
+		return nil, false
+	}
+	n := int(l.Size().(types.Int))
+	elems := make([]ref.Val, n)
+	for i := range n {
+		elems[i] = l.Get(types.Int(i))
+	}
+	return elems, true
+}
+
+// distinctOf returns the elements of lists in order, without duplicates.
+func distinctOf(lists ...ref.Val) ref.Val {
+	var seen valueSet
+	out := []ref.Val{}
+	for _, list := range lists {
+		elems, ok := listElems(list)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(list)
+		}
+		for _, v := range elems {
+			if seen.add(v) {
+				out = append(out, v)
+			}
+		}
+	}
+	return types.NewRefValList(numberAdapter{}, out)
+}
+
+// filterBy returns the distinct elements of a that are in b when in is true,
+// or not in b otherwise.
+func filterBy(a, b ref.Val, in bool) ref.Val {
+	elems, ok := listElems(a)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(a)
+	}
+	others, ok := listElems(b)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(b)
+	}
+	var inB, seen valueSet
+	for _, v := range others {
+		inB.add(v)
+	}
+	out := []ref.Val{}
+	for _, v := range elems {
+		if inB.has(v) == in && seen.add(v) {
+			out = append(out, v)
+		}
+	}
+	return types.NewRefValList(numberAdapter{}, out)
+}
+
+// sortByPath sorts list by the value each element holds at a dotted key
+// path. The sort is stable, and elements without the key or with a null
+// value there come last.
+func sortByPath(list, path ref.Val) ref.Val {
+	elems, ok := listElems(list)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(list)
+	}
+	p, ok := path.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(path)
+	}
+	segments := strings.Split(string(p), ".")
+	keys := make([]ref.Val, len(elems))
+	for i, v := range elems {
+		keys[i] = valueAtPath(v, segments)
+	}
+	var failed ref.Val
+	order := make([]int, len(elems))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		ki, kj := keys[order[i]], keys[order[j]]
+		if ki == nil || kj == nil {
+			return kj == nil && ki != nil
+		}
+		c, ok := ki.(traits.Comparer)
+		if !ok {
+			failed = types.NewErr("sortBy: %s values cannot be ordered", ki.Type().TypeName())
+			return false
+		}
+		r := c.Compare(kj)
+		if types.IsError(r) {
+			failed = types.NewErr("sortBy: cannot compare %s with %s", ki.Type().TypeName(), kj.Type().TypeName())
+			return false
+		}
+		return r == types.IntNegOne
+	})
+	if failed != nil {
+		return failed
+	}
+	out := make([]ref.Val, len(elems))
+	for i, idx := range order {
+		out[i] = elems[idx]
+	}
+	return types.NewRefValList(numberAdapter{}, out)
+}
+
+// valueAtPath returns the value v holds at the map keys in segments, or nil
+// when a key is missing or the value is null.
+func valueAtPath(v ref.Val, segments []string) ref.Val {
+	for _, seg := range segments {
+		m, ok := v.(traits.Mapper)
+		if !ok {
+			return nil
+		}
+		if v, ok = m.Find(types.String(seg)); !ok {
+			return nil
+		}
+	}
+	if v == types.NullValue {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"old":  []interface{}{"a", "b", "b", "c"},
+		"new":  []interface{}{"c", "d", "a", "d"},
+		"nums": []interface{}{int64(1), 1.0, int64(2)},
+		"items": []interface{}{
+			map[string]interface{}{"id": "x", "meta": map[string]interface{}{"rank": int64(3)}},
+			map[string]interface{}{"id": "y"},
+			map[string]interface{}{"id": "z", "meta": map[string]interface{}{"rank": int64(1)}},
+			map[string]interface{}{"id": "w", "meta": map[string]interface{}{"rank": 2.5}},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`distinct(_.old)`, []interface{}{"a", "b", "c"}},
+		{`distinct(_.nums)`, []interface{}{int64(1), int64(2)}},
+		{`distinct([])`, []interface{}{}},
+		{`union(_.old, _.new)`, []interface{}{"a", "b", "c", "d"}},
+		{`intersect(_.old, _.new)`, []interface{}{"a", "c"}},
+		{`difference(_.old, _.new)`, []interface{}{"b"}},
+		{`difference(_.new, _.old)`, []interface{}{"d"}},
+		{`_.old.distinct()`, []interface{}{"a", "b", "c"}},
+		{`sortBy(_.items, "id").map(x, x.id)`, []interface{}{"w", "x", "y", "z"}},
+		{`sortBy(_.items, "meta.rank").map(x, x.id)`, []interface{}{"z", "w", "x", "y"}},
+		{`_.items.sortBy(x, x.id).map(x, x.id)`, []interface{}{"w", "x", "y", "z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`sortBy([{"k": 1}, {"k": "a"}], "k")`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sortBy: cannot compare")
+}
+
+func TestSetFunctions_Typed(t *testing.T) {
+	typ, err := CheckExpression(`union(["a"], ["b"])`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "list(string)", typ.String())
+
+	_, err = CheckExpression(`intersect(["a"], 1)`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "found no matching overload for 'intersect'")
+}
@@ -0,0 +1,220 @@
+package cel
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// statsLib adds numeric helpers for quick analysis of metric dumps:
+//
+//	sum(list)             int when every element is an int, otherwise double
+//	avg(list)             arithmetic mean
+//	median(list)          middle value, or the mean of the middle two
+//	percentile(list, p)   p-th percentile (0-100), interpolated between ranks
+//	round(x, digits)      x rounded half away from zero to digits decimals
+//	clamp(x, lo, hi)      x limited to [lo, hi]
+//
+// The list functions can also be called as methods, e.g. list.sum(), so they
+// work as pipeline stages: _.items | map(x, x.n) | sum().
+// Elements may be ints, uints, or doubles; anything else is an error.
+func statsLib() cel.EnvOption {
+	return cel.Lib(statsLibrary{})
+}
+
+type statsLibrary struct{}
+
+func (statsLibrary) LibraryName() string { return "kvx.lib.stats" }
+
+func (statsLibrary) CompileOptions() []cel.EnvOption {
+	list, dyn, dbl := cel.ListType(cel.DynType), cel.DynType, cel.DoubleType
+	return []cel.EnvOption{
+		cel.Function("sum",
+			cel.Overload("sum_list", []*cel.Type{list}, dyn, cel.UnaryBinding(sum)),
+			cel.MemberOverload("list_sum", []*cel.Type{list}, dyn, cel.UnaryBinding(sum)),
+		),
+		cel.Function("avg",
+			cel.Overload("avg_list", []*cel.Type{list}, dbl, cel.UnaryBinding(avg)),
+			cel.MemberOverload("list_avg", []*cel.Type{list}, dbl, cel.UnaryBinding(avg)),
+		),
+		cel.Function("median",
+			cel.Overload("median_list", []*cel.Type{list}, dbl, cel.UnaryBinding(median)),
+			cel.MemberOverload("list_median", []*cel.Type{list}, dbl, cel.UnaryBinding(median)),
+		),
+		cel.Function("percentile",
+			cel.Overload("percentile_list_dyn", []*cel.Type{list, dyn}, dbl, cel.BinaryBinding(listPercentile)),
+			cel.MemberOverload("list_percentile_dyn", []*cel.Type{list, dyn}, dbl, cel.BinaryBinding(listPercentile)),
+		),
+		cel.Function("round",
+			cel.Overload("round_dyn_int", []*cel.Type{dyn, cel.IntType}, dbl, cel.BinaryBinding(round)),
+		),
+		cel.Function("clamp",
+			cel.Overload("clamp_dyn_dyn_dyn", []*cel.Type{dyn, dyn, dyn}, dyn, cel.FunctionBinding(clamp)),
+		),
+	}
+}
+
+func (statsLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// number returns v as a float64 and whether it is an int.
+func number(v ref.Val) (float64, bool, bool) {
+	switch n := v.(type) {
+	case types.Int:
+		return float64(n), true, true
+	case types.Uint:
+		return float64(n), false, true
+	case types.Double:
+		return float64(n), false, true
+	}
+	return 0, false, false
+}
+
+// numbers returns the elements of a non-empty list of numbers.
+func numbers(fn string, v ref.Val) ([]float64, ref.Val) {
+	elems, ok := listElems(v)
+	if !ok {
+		return nil, types.MaybeNoSuchOverloadErr(v)
+	}
+	if len(elems) == 0 {
+		return nil, types.NewErr("%s of an empty list", fn)
+	}
+	nums := make([]float64, len(elems))
+	for i, e := range elems {
+		f, _, ok := number(e)
+		if !ok {
+			return nil, types.NewErr("%s: element %d is a %s, not a number", fn, i, e.Type().TypeName())
+		}
+		nums[i] = f
+	}
+	return nums, nil
+}
+
+func sum(v ref.Val) ref.Val {
+	elems, ok := listElems(v)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(v)
+	}
+	var ints types.Int
+	var total float64
+	allInts := true
+	for i, e := range elems {
+		f, isInt, ok := number(e)
+		if !ok {
+			return types.NewErr("sum: element %d is a %s, not a number", i, e.Type().TypeName())
+		}
+		if isInt {
+			ints += e.(types.Int)
+		} else {
+			allInts = false
+		}
+		total += f
+	}
+	if allInts {
+		return ints
+	}
+	return types.Double(total)
+}
+
+func avg(l ref.Val) ref.Val {
+	nums, errVal := numbers("avg", l)
+	if errVal != nil {
+		return errVal
+	}
+	return types.Double(mean(nums))
+}
+
+func median(l ref.Val) ref.Val {
+	return percentile("median", l, types.Double(50))
+}
+
+func listPercentile(l, p ref.Val) ref.Val {
+	return percentile("percentile", l, p)
+}
+
+func mean(nums []float64) float64 {
+	var total float64
+	for _, n := range nums {
+		total += n
+	}
+	return total / float64(len(nums))
+}
+
+// percentile interpolates linearly between the closest ranks, so the 50th
+// percentile of an even-sized list is the mean of its middle two values.
+func percentile(fn string, l, p ref.Val) ref.Val {
+	nums, errVal := numbers(fn, l)
+	if errVal != nil {
+		return errVal
+	}
+	rank, _, ok := number(p)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(p)
+	}
+	if rank < 0 || rank > 100 {
+		return types.NewErr("%s: percentile must be between 0 and 100, got %v", fn, rank)
+	}
+	sort.Float64s(nums)
+	pos := rank / 100 * float64(len(nums)-1)
+	lo := int(math.Floor(pos))
+	if lo == len(nums)-1 {
+		return types.Double(nums[lo])
+	}
+	return types.Double(nums[lo] + (nums[lo+1]-nums[lo])*(pos-float64(lo)))
+}
+
+func round(x, digits ref.Val) ref.Val {
+	f, _, ok := number(x)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(x)
+	}
+	d, ok := digits.(types.Int)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(digits)
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return types.NewErr("round: %v is not a finite number", f)
+	}
+	if d < -maxRoundDigits {
+		// Every finite double is below half of 10^309.
+		return types.Double(0)
+	}
+	scale := math.Pow(10, float64(d))
+	scaled := f * scale
+	if math.IsInf(scaled, 0) || math.IsNaN(scaled) {
+		// x has no digits that far past the decimal point.
+		return types.Double(f)
+	}
+	r := math.Round(scaled) / scale
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return types.NewErr("round: rounding %v to %d digits is not a finite number", f, d)
+	}
+	return types.Double(r)
+}
+
+// maxRoundDigits is the largest power of ten below the largest double.
+const maxRoundDigits = 308
+
+// clamp limits x to [lo, hi]. The result is an int when all three are ints.
+func clamp(args ...ref.Val) ref.Val {
+	var vals [3]float64
+	allInts := true
+	for i, a := range args {
+		f, isInt, ok := number(a)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(a)
+		}
+		vals[i] = f
+		allInts = allInts && isInt
+	}
+	x, lo, hi := vals[0], vals[1], vals[2]
+	if lo > hi {
+		return types.NewErr("clamp: lower bound %v is above upper bound %v", lo, hi)
+	}
+	if allInts {
+		return types.Int(min(max(args[0].(types.Int), args[1].(types.Int)), args[2].(types.Int)))
+	}
+	return types.Double(min(max(x, lo), hi))
+}
@@ -0,0 +1,98 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"counts":    []interface{}{int64(3), int64(1), int64(4), int64(2)},
+		"latencies": []interface{}{12.5, 3.0, int64(7), 100.25, 9.5},
+		"cpu":       97.3,
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`sum(_.counts)`, int64(10)},
+		{`sum(_.latencies)`, 132.25},
+		{`sum([])`, int64(0)},
+		{`avg(_.counts)`, 2.5},
+		{`median(_.counts)`, 2.5},
+		{`median(_.latencies)`, 9.5},
+		{`percentile(_.latencies, 0)`, 3.0},
+		{`percentile(_.latencies, 100)`, 100.25},
+		{`percentile(_.counts, 25)`, 1.75},
+		{`percentile([5], 90.0)`, 5.0},
+		{`round(_.cpu / 3.0, 2)`, 32.43},
+		{`round(1234.5, -2)`, 1200.0},
+		{`round(2, 1)`, 2.0},
+		{`round(1e300, 400)`, 1e300},
+		{`round(1e300, 10)`, 1e300},
+		{`round(0, 400)`, 0.0},
+		{`round(1e300, -400)`, 0.0},
+		{`_.counts.sum()`, int64(10)},
+		{`_.latencies.avg()`, 26.45},
+		{`_.counts.median()`, 2.5},
+		{`_.counts.percentile(25)`, 1.75},
+		{`clamp(_.cpu, 0, 90)`, 90.0},
+		{`clamp(-5, 0, 10)`, int64(0)},
+		{`clamp(5, 0, 10)`, int64(5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for expr, msg := range map[string]string{
+		`avg([])`:                   "avg of an empty list",
+		`sum([1, "2"])`:             "sum: element 1 is a string, not a number",
+		`percentile([1, 2], 101)`:   "percentile must be between 0 and 100",
+		`clamp(1, 10, 0)`:           "clamp: lower bound 10 is above upper bound 0",
+		`median(["a"])`:             "median: element 0 is a string, not a number",
+		`percentile(_.counts, "x")`: "no such overload",
+		`round(double("inf"), 2)`:   "round: +Inf is not a finite number",
+	} {
+		_, err := eval.Evaluate(expr, data)
+		require.ErrorContains(t, err, msg, expr)
+	}
+}
+
+func TestStatsFunctions_Pipeline(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"n": int64(4)},
+			map[string]interface{}{"n": int64(1)},
+			map[string]interface{}{"n": int64(7)},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`_.items | map(x, x.n) | sum()`, int64(12)},
+		{`_.items.map(x, x.n).sum()`, int64(12)},
+		{`_.items | map(x, x.n) | avg()`, 4.0},
+		{`_.items | map(x, x.n) | median()`, 4.0},
+		{`_.items | map(x, x.n) | percentile(100)`, 7.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package cel
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// stringsLib adds string helpers the strings extension lacks:
+//
+//	s.padLeft(width[, pad]) / s.padRight(width[, pad])
+//	s.trimPrefix(prefix) / s.trimSuffix(suffix)
+//	s.replaceAll(regex, replacement)   with $1-style group references
+//	s.toSnakeCase() / s.toCamelCase()
+//	format(pattern, args...)           global form of pattern.format([args...])
+//
+// Widths count characters, not bytes.
+func stringsLib() cel.EnvOption {
+	return cel.Lib(stringsLibrary{})
+}
+
+type stringsLibrary struct{}
+
+func (stringsLibrary) LibraryName() string { return "kvx.lib.strings" }
+
+func (stringsLibrary) CompileOptions() []cel.EnvOption {
+	str, num := cel.StringType, cel.IntType
+	return []cel.EnvOption{
+		cel.Function("padLeft",
+			cel.MemberOverload("string_padLeft_int", []*cel.Type{str, num}, str,
+				cel.BinaryBinding(func(s, width ref.Val) ref.Val { return pad(s, width, types.String(" "), true) })),
+			cel.MemberOverload("string_padLeft_int_string", []*cel.Type{str, num, str}, str,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val { return pad(args[0], args[1], args[2], true) })),
+		),
+		cel.Function("padRight",
+			cel.MemberOverload("string_padRight_int", []*cel.Type{str, num}, str,
+				cel.BinaryBinding(func(s, width ref.Val) ref.Val { return pad(s, width, types.String(" "), false) })),
+			cel.MemberOverload("string_padRight_int_string", []*cel.Type{str, num, str}, str,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val { return pad(args[0], args[1], args[2], false) })),
+		),
+		cel.Function("trimPrefix",
+			cel.MemberOverload("string_trimPrefix_string", []*cel.Type{str, str}, str,
+				cel.BinaryBinding(stringBinary(strings.TrimPrefix))),
+		),
+		cel.Function("trimSuffix",
+			cel.MemberOverload("string_trimSuffix_string", []*cel.Type{str, str}, str,
+				cel.BinaryBinding(stringBinary(strings.TrimSuffix))),
+		),
+		cel.Function("replaceAll",
+			cel.MemberOverload("string_replaceAll_string_string", []*cel.Type{str, str, str}, str,
+				cel.FunctionBinding(replaceAll)),
+		),
+		cel.Function("toSnakeCase",
+			cel.MemberOverload("string_toSnakeCase", []*cel.Type{str}, str,
+				cel.UnaryBinding(stringUnary(toSnakeCase))),
+		),
+		cel.Function("toCamelCase",
+			cel.MemberOverload("string_toCamelCase", []*cel.Type{str}, str,
+				cel.UnaryBinding(stringUnary(toCamelCase))),
+		),
+		cel.Macros(cel.GlobalVarArgMacro("format", formatMacro)),
+	}
+}
+
+func (stringsLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// formatMacro rewrites format(pattern, a, b) to pattern.format([a, b]).
+func formatMacro(eh cel.MacroExprFactory, _ ast.Expr, args []ast.Expr) (ast.Expr, *common.Error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	return eh.NewMemberCall("format", args[0], eh.NewList(args[1:]...)), nil
+}
+
+func stringUnary(fn func(string) string) func(ref.Val) ref.Val {
+	return func(s ref.Val) ref.Val {
+		str, ok := s.(types.String)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(s)
+		}
+		return types.String(fn(string(str)))
+	}
+}
+
+func stringBinary(fn func(string, string) string) func(ref.Val, ref.Val) ref.Val {
+	return func(a, b ref.Val) ref.Val {
+		s, ok := a.(types.String)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(a)
+		}
+		t, ok := b.(types.String)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(b)
+		}
+		return types.String(fn(string(s), string(t)))
+	}
+}
+
+// pad fills s with repetitions of fill up to width characters, on the left
+// or the right. Strings already as wide are returned unchanged.
+func pad(s, width, fill ref.Val, left bool) ref.Val {
+	str, ok := s.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(s)
+	}
+	w, ok := width.(types.Int)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(width)
+	}
+	f, ok := fill.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(fill)
+	}
+	if f == "" {
+		return types.NewErr("pad string must not be empty")
+	}
+	missing := int(w) - len([]rune(string(str)))
+	if missing <= 0 {
+		return str
+	}
+	fillRunes := []rune(string(f))
+	padding := make([]rune, missing)
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+	if left {
+		return types.String(string(padding)) + str
+	}
+	return str + types.String(string(padding))
+}
+
+// replaceAll replaces every match of a regular expression; the replacement
+// may refer to groups as $1 or ${name}.
+func replaceAll(args ...ref.Val) ref.Val {
+	strs := make([]string, len(args))
+	for i, a := range args {
+		s, ok := a.(types.String)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(a)
+		}
+		strs[i] = string(s)
+	}
+	re, err := regexp.Compile(strs[1])
+	if err != nil {
+		return types.NewErr("replaceAll: invalid regular expression: %v", err)
+	}
+	return types.String(re.ReplaceAllString(strs[0], strs[2]))
+}
+
+// caseWords splits s into lower-cased words at separators (anything but
+// letters and digits) and at case changes, so "HTTPServer_url" and
+// "http server URL" both give http, server, url.
+func caseWords(s string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := cur[len(cur)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return words
+}
+
+func toSnakeCase(s string) string {
+	return strings.Join(caseWords(s), "_")
+}
+
+func toCamelCase(s string) string {
+	words := caseWords(s)
+	for i := 1; i < len(words); i++ {
+		r := []rune(words[i])
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,48 @@
+package cel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	data := map[string]interface{}{"id": int64(7), "name": "kvx", "key": "HTTPServer_url"}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`string(_.id).padLeft(3, "0")`, "007"},
+		{`_.name.padLeft(5)`, "  kvx"},
+		{`_.name.padRight(6, "-=")`, "kvx-=-"},
+		{`"héllo".padLeft(5, "*")`, "héllo"},
+		{`"v1.2.3".trimPrefix("v")`, "1.2.3"},
+		{`"app.log".trimSuffix(".log")`, "app"},
+		{`"a1b22c333".replaceAll("[0-9]+", "#")`, "a#b#c#"},
+		{`"2024-01-31".replaceAll("(\\d+)-(\\d+)-(\\d+)", "$3/$2/$1")`, "31/01/2024"},
+		{`_.key.toSnakeCase()`, "http_server_url"},
+		{`"userID".toSnakeCase()`, "user_id"},
+		{`"already_snake-case words".toSnakeCase()`, "already_snake_case_words"},
+		{`_.key.toCamelCase()`, "httpServerUrl"},
+		{`"created at".toCamelCase()`, "createdAt"},
+		{`format("%s-%d", _.name, _.id)`, "kvx-7"},
+		{`format("plain")`, "plain"},
+		{`"%s!".format([_.name])`, "kvx!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`"a".padLeft(3, "")`, nil)
+	require.ErrorContains(t, err, "pad string must not be empty")
+	_, err = eval.Evaluate(`"a".replaceAll("(", "")`, nil)
+	require.ErrorContains(t, err, "replaceAll: invalid regular expression")
+}
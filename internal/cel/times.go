@@ -0,0 +1,187 @@
+package cel
+
+import (
+	"time"
+	_ "time/tzdata" // zone names must resolve without a system zoneinfo database
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// timesLib adds date and time helpers, so filters such as "created in the
+// last 24 hours" need no preprocessing:
+//
+//	now()                              current time
+//	parseTime(s, layout[, zone])       timestamp from text in a Go layout
+//	formatTime(t, layout[, zone])      text of t in a Go layout
+//	durationBetween(a, b)              duration from a to b
+//	truncateToDay(t[, zone])           midnight at the start of t's day
+//	inTimezone(t, zone)                t in an IANA zone such as "Europe/Paris"
+//
+// Layouts are Go reference layouts ("2006-01-02 15:04") or the name of a Go
+// layout constant ("RFC3339", "DateOnly", ...). Without a zone, text without
+// an offset is read as UTC and days start at midnight UTC.
+func timesLib() cel.EnvOption {
+	return cel.Lib(timesLibrary{})
+}
+
+type timesLibrary struct{}
+
+func (timesLibrary) LibraryName() string { return "kvx.lib.times" }
+
+func (timesLibrary) CompileOptions() []cel.EnvOption {
+	ts, dur, str := cel.TimestampType, cel.DurationType, cel.StringType
+	return []cel.EnvOption{
+		cel.Function("now",
+			cel.Overload("now", nil, ts,
+				cel.FunctionBinding(func(...ref.Val) ref.Val { return types.Timestamp{Time: time.Now()} })),
+		),
+		cel.Function("parseTime",
+			cel.Overload("parseTime_string_string", []*cel.Type{str, str}, ts,
+				cel.BinaryBinding(func(s, layout ref.Val) ref.Val { return parseTime(s, layout, types.String("UTC")) })),
+			cel.Overload("parseTime_string_string_string", []*cel.Type{str, str, str}, ts,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val { return parseTime(args[0], args[1], args[2]) })),
+		),
+		cel.Function("formatTime",
+			cel.Overload("formatTime_timestamp_string", []*cel.Type{ts, str}, str,
+				cel.BinaryBinding(func(t, layout ref.Val) ref.Val { return formatTime(t, layout, nil) })),
+			cel.Overload("formatTime_timestamp_string_string", []*cel.Type{ts, str, str}, str,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val { return formatTime(args[0], args[1], args[2]) })),
+		),
+		cel.Function("durationBetween",
+			cel.Overload("durationBetween_timestamp_timestamp", []*cel.Type{ts, ts}, dur,
+				cel.BinaryBinding(durationBetween)),
+		),
+		cel.Function("truncateToDay",
+			cel.Overload("truncateToDay_timestamp", []*cel.Type{ts}, ts,
+				cel.UnaryBinding(func(t ref.Val) ref.Val { return truncateToDay(t, types.String("UTC")) })),
+			cel.Overload("truncateToDay_timestamp_string", []*cel.Type{ts, str}, ts,
+				cel.BinaryBinding(truncateToDay)),
+		),
+		cel.Function("inTimezone",
+			cel.Overload("inTimezone_timestamp_string", []*cel.Type{ts, str}, ts,
+				cel.BinaryBinding(func(t, zone ref.Val) ref.Val {
+					tm, ok := t.(types.Timestamp)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(t)
+					}
+					loc, errVal := location(zone)
+					if errVal != nil {
+						return errVal
+					}
+					return types.Timestamp{Time: tm.In(loc)}
+				})),
+		),
+	}
+}
+
+func (timesLibrary) ProgramOptions() []cel.ProgramOption { return nil }
+
+// timeLayouts are the Go layout constants that may be named instead of
+// written out.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+func timeLayout(v ref.Val) (string, ref.Val) {
+	s, ok := v.(types.String)
+	if !ok {
+		return "", types.MaybeNoSuchOverloadErr(v)
+	}
+	if layout, ok := timeLayouts[string(s)]; ok {
+		return layout, nil
+	}
+	return string(s), nil
+}
+
+func location(v ref.Val) (*time.Location, ref.Val) {
+	s, ok := v.(types.String)
+	if !ok {
+		return nil, types.MaybeNoSuchOverloadErr(v)
+	}
+	loc, err := time.LoadLocation(string(s))
+	if err != nil {
+		return nil, types.NewErr("unknown time zone %q", string(s))
+	}
+	return loc, nil
+}
+
+func parseTime(s, layout, zone ref.Val) ref.Val {
+	text, ok := s.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(s)
+	}
+	l, errVal := timeLayout(layout)
+	if errVal != nil {
+		return errVal
+	}
+	loc, errVal := location(zone)
+	if errVal != nil {
+		return errVal
+	}
+	t, err := time.ParseInLocation(l, string(text), loc)
+	if err != nil {
+		return types.NewErr("parseTime: %v", err)
+	}
+	return types.Timestamp{Time: t}
+}
+
+// formatTime writes t in layout, converted to zone when it is not nil.
+func formatTime(t, layout, zone ref.Val) ref.Val {
+	tm, ok := t.(types.Timestamp)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(t)
+	}
+	l, errVal := timeLayout(layout)
+	if errVal != nil {
+		return errVal
+	}
+	if zone != nil {
+		loc, errVal := location(zone)
+		if errVal != nil {
+			return errVal
+		}
+		tm.Time = tm.In(loc)
+	}
+	return types.String(tm.Format(l))
+}
+
+func durationBetween(a, b ref.Val) ref.Val {
+	from, ok := a.(types.Timestamp)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(a)
+	}
+	to, ok := b.(types.Timestamp)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(b)
+	}
+	return types.Duration{Duration: to.Sub(from.Time)}
+}
+
+func truncateToDay(t, zone ref.Val) ref.Val {
+	tm, ok := t.(types.Timestamp)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(t)
+	}
+	loc, errVal := location(zone)
+	if errVal != nil {
+		return errVal
+	}
+	local := tm.In(loc)
+	y, m, d := local.Date()
+	return types.Timestamp{Time: time.Date(y, m, d, 0, 0, 0, 0, loc)}
+}
@@ -0,0 +1,49 @@
+package cel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFunctions(t *testing.T) {
+	eval, err := NewEvaluator()
+	require.NoError(t, err)
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	data := map[string]interface{}{
+		"created": "2024-03-10T22:30:00Z",
+		"items": []interface{}{
+			map[string]interface{}{"id": "old", "at": "2020-01-01T00:00:00Z"},
+			map[string]interface{}{"id": "new", "at": recent},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`formatTime(parseTime("10/03/2024 22:30", "02/01/2006 15:04"), "RFC3339")`, "2024-03-10T22:30:00Z"},
+		{`formatTime(parseTime("2024-03-10", "DateOnly", "Europe/Paris"), "RFC3339")`, "2024-03-10T00:00:00+01:00"},
+		{`formatTime(timestamp(_.created), "DateTime", "Asia/Tokyo")`, "2024-03-11 07:30:00"},
+		{`formatTime(inTimezone(timestamp(_.created), "America/New_York"), "15:04 MST")`, "18:30 EDT"},
+		{`durationBetween(timestamp("2024-01-01T00:00:00Z"), timestamp(_.created)) > duration("1000h")`, true},
+		{`string(durationBetween(timestamp(_.created), timestamp("2024-03-11T00:00:00Z")))`, "5400s"},
+		{`formatTime(truncateToDay(timestamp(_.created)), "RFC3339")`, "2024-03-10T00:00:00Z"},
+		{`formatTime(truncateToDay(timestamp(_.created), "Asia/Tokyo"), "RFC3339")`, "2024-03-11T00:00:00+09:00"},
+		{`_.items.filter(x, durationBetween(timestamp(x.at), now()) < duration("24h")).map(x, x.id)`, []interface{}{"new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := eval.Evaluate(tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = eval.Evaluate(`parseTime("nope", "DateOnly")`, nil)
+	require.ErrorContains(t, err, "parseTime: ")
+	_, err = eval.Evaluate(`inTimezone(now(), "Mars/Olympus")`, nil)
+	require.ErrorContains(t, err, `unknown time zone "Mars/Olympus"`)
+}
@@ -0,0 +1,309 @@
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// schemaRootTypeName is the CEL type name of the document root described by
+// a JSON Schema. Nested objects are named after their path below it, e.g.
+// "schema.metadata" or "schema.items[]".
+const schemaRootTypeName = "schema"
+
+// identifierPattern matches keys that can be selected with dot notation.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CheckExpression parses and type-checks expr without evaluating it and
+// returns the type of its result. When schema is non-nil, '_' gets the type
+// the JSON Schema describes, so unknown fields and mismatched operand types
+// are reported; otherwise '_' is dynamic and only syntax, function names,
+// and literal types are checked. Each stage of a pipeline is checked with '_'
+// typed as the result of the stage before it.
+func CheckExpression(expr string, schema map[string]any) (*types.Type, error) {
+	provider, err := newSchemaTypeProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	env, err := newSchemaCELEnvWith(provider, schema, false)
+	if err != nil {
+		return nil, err
+	}
+	ast, err := checkPipeline(env, expr, func(root *cel.Type) (*cel.Env, error) {
+		return newTypedCELEnv(root, cel.CustomTypeProvider(provider))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ast.OutputType(), nil
+}
+
+// CheckWhere type-checks a --where filter like [Evaluator.EvaluateWhere]
+// would run it: '_' is one item of the list the schema describes, and the
+// expression must return a boolean.
+func CheckWhere(expr string, schema map[string]any) error {
+	env, err := newSchemaCELEnv(schema, true)
+	if err != nil {
+		return err
+	}
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("where filter compilation error: %w", issues.Err())
+	}
+	if ot := ast.OutputType(); ot != nil && ot.String() != "bool" && ot.String() != "dyn" {
+		return fmt.Errorf("where filter expression must return a boolean, got %s", ot)
+	}
+	return nil
+}
+
+// newSchemaCELEnv returns the standard environment with '_' typed from
+// schema. With item set, '_' is the element type of a list schema.
+func newSchemaCELEnv(schema map[string]any, item bool) (*cel.Env, error) {
+	provider, err := newSchemaTypeProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	return newSchemaCELEnvWith(provider, schema, item)
+}
+
+// newSchemaCELEnvWith is newSchemaCELEnv declaring the schema's types in
+// provider.
+func newSchemaCELEnvWith(provider *schemaTypeProvider, schema map[string]any, item bool) (*cel.Env, error) {
+	root := types.DynType
+	if schema != nil {
+		root = provider.typeOf(schema, schema, schemaRootTypeName)
+	}
+	if item {
+		if root.Kind() == types.ListKind {
+			root = root.Parameters()[0]
+		} else {
+			root = types.DynType
+		}
+	}
+	env, err := newTypedCELEnv(root, cel.CustomTypeProvider(provider))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	return env, nil
+}
+
+// schemaTypeProvider declares the objects of a JSON Schema as CEL struct
+// types so the type checker knows their fields. Everything else is left to
+// the default registry.
+type schemaTypeProvider struct {
+	*types.Registry
+	structs   map[string]map[string]*types.Type
+	resolving map[string]bool
+}
+
+func newSchemaTypeProvider() (*schemaTypeProvider, error) {
+	reg, err := types.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return &schemaTypeProvider{
+		Registry:  reg,
+		structs:   make(map[string]map[string]*types.Type),
+		resolving: make(map[string]bool),
+	}, nil
+}
+
+// FindStructType implements types.Provider.
+func (p *schemaTypeProvider) FindStructType(structType string) (*types.Type, bool) {
+	if _, ok := p.structs[structType]; ok {
+		return types.NewTypeTypeWithParam(types.NewObjectType(structType)), true
+	}
+	return p.Registry.FindStructType(structType)
+}
+
+// FindStructFieldNames implements types.Provider.
+func (p *schemaTypeProvider) FindStructFieldNames(structType string) ([]string, bool) {
+	fields, ok := p.structs[structType]
+	if !ok {
+		return p.Registry.FindStructFieldNames(structType)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+// FindStructFieldType implements types.Provider. The field accessors are
+// never called because schema types are only used for checking.
+func (p *schemaTypeProvider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	fields, ok := p.structs[structType]
+	if !ok {
+		return p.Registry.FindStructFieldType(structType, fieldName)
+	}
+	t, ok := fields[fieldName]
+	if !ok {
+		return nil, false
+	}
+	return &types.FieldType{
+		Type:    t,
+		IsSet:   func(any) bool { return false },
+		GetFrom: func(any) (any, error) { return nil, errors.New("schema types cannot be evaluated") },
+	}, true
+}
+
+// typeOf returns the CEL type of the values schema describes. Objects
+// closed with "additionalProperties": false become struct types named name;
+// other objects become maps, see objectType. Numbers are dynamic because the
+// loader yields ints, doubles, or json.Number depending on the input format.
+func (p *schemaTypeProvider) typeOf(schema, root map[string]any, name string) *types.Type {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, refName := resolveSchemaRef(root, ref)
+		if target == nil {
+			return types.DynType
+		}
+		name = schemaRootTypeName + "." + refName
+		if _, ok := p.structs[name]; ok {
+			return types.NewObjectType(name)
+		}
+		if p.resolving[name] {
+			// A recursive reference that is not an object type.
+			return types.DynType
+		}
+		p.resolving[name] = true
+		defer delete(p.resolving, name)
+		schema = target
+	}
+
+	switch schemaTypeName(schema) {
+	case "string":
+		return types.StringType
+	case "boolean":
+		return types.BoolType
+	case "array":
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return types.NewListType(types.DynType)
+		}
+		return types.NewListType(p.typeOf(items, root, name+"[]"))
+	case "object":
+		return p.objectType(schema, root, name)
+	default:
+		return types.DynType
+	}
+}
+
+// objectType returns a struct type only for objects closed with
+// "additionalProperties": false whose keys can all be selected with dot
+// notation, so unknown fields are reported. Any other object may hold keys
+// the schema does not list and is a map, which keeps size() and bracket
+// access working: its value type is the one all known fields and additional
+// properties share, or dyn.
+func (p *schemaTypeProvider) objectType(schema, root map[string]any, name string) *types.Type {
+	props, _ := schema["properties"].(map[string]any)
+	additional := schema["additionalProperties"]
+	closed := additional == false
+	if closed && len(props) > 0 && allIdentifiers(props) {
+		// Register the type before its fields so recursive references resolve.
+		fields := make(map[string]*types.Type, len(props))
+		p.structs[name] = fields
+		for key, raw := range props {
+			fields[key] = p.propertyType(raw, root, name+"."+key)
+		}
+		return types.NewObjectType(name)
+	}
+
+	values, typed := additional.(map[string]any)
+	if !typed && !closed {
+		// Additional properties of any type are allowed.
+		return types.NewMapType(types.StringType, types.DynType)
+	}
+	var known []*types.Type
+	for key, raw := range props {
+		known = append(known, p.propertyType(raw, root, name+"."+key))
+	}
+	if typed {
+		known = append(known, p.typeOf(values, root, name+"[]"))
+	}
+	return types.NewMapType(types.StringType, commonType(known))
+}
+
+// propertyType returns the type of one entry of "properties".
+func (p *schemaTypeProvider) propertyType(raw any, root map[string]any, name string) *types.Type {
+	prop, ok := raw.(map[string]any)
+	if !ok {
+		return types.DynType
+	}
+	return p.typeOf(prop, root, name)
+}
+
+// commonType returns the type every element of ts has, or dyn when they
+// differ or there are none.
+func commonType(ts []*types.Type) *types.Type {
+	if len(ts) == 0 {
+		return types.DynType
+	}
+	for _, t := range ts[1:] {
+		if !t.IsExactType(ts[0]) {
+			return types.DynType
+		}
+	}
+	return ts[0]
+}
+
+// schemaTypeName returns the JSON Schema type of schema, ignoring "null" in
+// type lists and inferring object or array from properties and items.
+func schemaTypeName(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+		return ""
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// resolveSchemaRef follows a local reference such as "#/$defs/pod" and
+// returns the referenced schema and its path with dots, "$defs.pod".
+func resolveSchemaRef(root map[string]any, ref string) (map[string]any, string) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, ""
+	}
+	var node any = root
+	segments := strings.Split(path, "/")
+	for _, seg := range segments {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, ""
+		}
+		seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+		node = m[seg]
+	}
+	target, ok := node.(map[string]any)
+	if !ok {
+		return nil, ""
+	}
+	return target, strings.Join(segments, ".")
+}
+
+func allIdentifiers(props map[string]any) bool {
+	for key := range props {
+		if !identifierPattern.MatchString(key) {
+			return false
+		}
+	}
+	return true
+}
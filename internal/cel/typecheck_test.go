@@ -0,0 +1,127 @@
+package cel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCheckSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string"},
+    "count": {"type": "integer"},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "meta": {"type": "object", "additionalProperties": false, "properties": {"bad-key": {"type": "string"}}},
+    "open": {"type": "object", "properties": {"name": {"type": "string"}}},
+    "env": {"type": "object", "properties": {"PATH": {"type": "string"}}, "additionalProperties": {"type": "string"}},
+    "items": {"type": "array", "items": {"$ref": "#/$defs/item"}}
+  },
+  "$defs": {
+    "item": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "id": {"type": "string"},
+        "children": {"type": "array", "items": {"$ref": "#/$defs/item"}}
+      }
+    },
+    "loop": {"type": "array", "items": {"$ref": "#/$defs/loop"}}
+  }
+}`
+
+func parseTestSchema(t *testing.T, data string) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal([]byte(data), &schema))
+	return schema
+}
+
+func TestCheckExpression(t *testing.T) {
+	schema := parseTestSchema(t, testCheckSchema)
+
+	tests := []struct {
+		expr     string
+		wantType string
+		wantErr  string
+	}{
+		{expr: `_.name`, wantType: "string"},
+		{expr: `_.tags.map(t, t.upperAscii())`, wantType: "list(string)"},
+		{expr: `_.labels.app`, wantType: "string"},
+		{expr: `_.items.map(i, i.id)`, wantType: "list(string)"},
+		{expr: `_.items[0].children[0].id`, wantType: "string"},
+		{expr: `has(_.name) && size(_.tags) > 0`, wantType: "bool"},
+		{expr: `_.meta["bad-key"]`, wantType: "string"},
+		{expr: `size(_.open)`, wantType: "int"},
+		{expr: `_.open["extra"]`, wantType: "dyn"},
+		{expr: `_.open.extra`, wantType: "dyn"},
+		{expr: `_.env.HOME`, wantType: "string"},
+		{expr: `size(_.env) + size(_.labels)`, wantType: "int"},
+		{expr: `size(_)`, wantErr: "found no matching overload for 'size'"},
+		{expr: `_.count`, wantType: "dyn"},
+		{expr: `_.nme`, wantErr: "undefined field 'nme'"},
+		{expr: `_.items[0].idd`, wantErr: "undefined field 'idd'"},
+		{expr: `_.name + 1`, wantErr: "found no matching overload for '_+_' applied to '(string, int)'"},
+		{expr: `_.name.nosuch()`, wantErr: "undeclared reference to 'nosuch'"},
+		{expr: `1 +`, wantErr: "Syntax error"},
+		{expr: `_.items | map(i, i.id) | sort()`, wantType: "list(string)"},
+		{expr: `_.items[0] | .children | size()`, wantType: "int"},
+		{expr: `_.items | map(i, i.idd)`, wantErr: "compilation error in pipeline stage 2"},
+		{expr: `_.name | `, wantErr: "pipeline stage 2 is empty"},
+		{expr: `type(1)`, wantType: "type(int)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			typ, err := CheckExpression(tt.expr, schema)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, typ.String())
+		})
+	}
+}
+
+func TestCheckExpression_NoSchema(t *testing.T) {
+	typ, err := CheckExpression(`_.anything.goes`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "dyn", typ.String())
+
+	_, err = CheckExpression(`_.x.nosuch()`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undeclared reference to 'nosuch'")
+}
+
+func TestCheckExpression_RecursiveListRef(t *testing.T) {
+	schema := parseTestSchema(t, `{"$ref": "#/$defs/loop", "$defs": {"loop": {"type": "array", "items": {"$ref": "#/$defs/loop"}}}}`)
+	typ, err := CheckExpression(`_[0][0]`, schema)
+	require.NoError(t, err)
+	assert.Equal(t, "dyn", typ.String())
+}
+
+func TestCheckWhere(t *testing.T) {
+	schema := parseTestSchema(t, `{"type": "array", "items": {"type": "object", "additionalProperties": false, "properties": {"id": {"type": "string"}}}}`)
+
+	require.NoError(t, CheckWhere(`_.id == "a"`, schema))
+	require.NoError(t, CheckWhere(`_.anything`, nil), "without a schema the result type is unknown")
+
+	err := CheckWhere(`_.idd == "a"`, schema)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined field 'idd'")
+
+	err = CheckWhere(`_.id`, schema)
+	require.EqualError(t, err, "where filter expression must return a boolean, got string")
+}
+
+func TestCheckWhere_OpenItems(t *testing.T) {
+	schema := parseTestSchema(t, `{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "string"}}}}`)
+
+	require.NoError(t, CheckWhere(`_.id == "a" && _["other-key"] != null && size(_) > 1`, schema),
+		"items without additionalProperties may hold undeclared keys")
+}
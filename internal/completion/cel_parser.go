@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/cel-go/cel"
 	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
 )
 
 // ParsedExpression represents a parsed CEL expression with extracted navigation info
@@ -107,6 +109,55 @@ func ParseCELExpression(input string, env *cel.Env) (*ParsedExpression, error) {
 	return result, nil
 }
 
+// SyntaxError parses expr with the CEL parser and returns the rune offset and
+// message of the first reported error. ok is false when expr parses cleanly
+// or the environment cannot be created. The stages of a '|' pipeline are
+// parsed one by one.
+func (p *CELProvider) SyntaxError(expr string) (pos int, msg string, ok bool) {
+	if strings.TrimSpace(expr) == "" {
+		return 0, "", false
+	}
+	env := p.parseEnv()
+	if env == nil {
+		return 0, "", false
+	}
+	if stages := celhelper.SplitPipeline(expr); len(stages) > 1 {
+		for _, stage := range stages {
+			if stage.Text == "" {
+				return stage.Offset, "empty pipeline stage", true
+			}
+			src, added := stage.Source(env)
+			if pos, msg, ok := parseError(env, src); ok {
+				return stage.Offset + max(pos-added, 0), msg, true
+			}
+		}
+		return 0, "", false
+	}
+	return parseError(env, expr)
+}
+
+// parseError returns the rune offset and message of the first error the CEL
+// parser reports for expr.
+func parseError(env *cel.Env, expr string) (pos int, msg string, ok bool) {
+	_, issues := env.Parse(expr)
+	if issues == nil || issues.Err() == nil {
+		return 0, "", false
+	}
+	errs := issues.Errors()
+	if len(errs) == 0 {
+		return 0, "", false
+	}
+	first := errs[0]
+	pos = 0
+	if first.Location != nil {
+		pos = first.Location.Column()
+	}
+	if n := len([]rune(expr)); pos > n {
+		pos = n
+	}
+	return pos, first.Message, true
+}
+
 // hasFunctionCallsInProto checks if expression contains function calls using proto inspection
 func hasFunctionCallsInProto(expr *exprpb.Expr) bool {
 	if expr == nil {
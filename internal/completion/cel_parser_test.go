@@ -31,3 +31,39 @@ func TestIsOperator(t *testing.T) {
 	assert.False(t, isOperator("size"))
 	assert.False(t, isOperator(""))
 }
+
+func TestCELProviderSyntaxError(t *testing.T) {
+	p, err := NewCELProvider()
+	assert.NoError(t, err)
+
+	_, _, ok := p.SyntaxError("_.items.filter(x, x.age > 30)")
+	assert.False(t, ok)
+
+	_, _, ok = p.SyntaxError("")
+	assert.False(t, ok)
+
+	pos, msg, ok := p.SyntaxError("_.a +* 1")
+	assert.True(t, ok)
+	assert.NotEmpty(t, msg)
+	// The error points at the stray operator, not the end of the input.
+	assert.True(t, pos >= 4 && pos < 8, "pos=%d", pos)
+
+	// Pipeline stages are parsed on their own.
+	_, _, ok = p.SyntaxError("_.items | filter(x, x.ok) | .name")
+	assert.False(t, ok)
+	pos, _, ok = p.SyntaxError("_.items | _.a +* 1 | size()")
+	assert.True(t, ok)
+	assert.True(t, pos >= 14 && pos < 18, "pos=%d", pos)
+}
+
+func TestCELProviderSyntaxErrorReusesEnv(t *testing.T) {
+	p, err := NewCELProvider()
+	assert.NoError(t, err)
+
+	p.SyntaxError("_.a +* 1")
+	env := p.env
+	assert.NotNil(t, env)
+	p.SyntaxError("_.b +* 2")
+	p.FilterCompletions("_.items.", CompletionContext{})
+	assert.Same(t, env, p.env)
+}
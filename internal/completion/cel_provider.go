@@ -1,11 +1,13 @@
 package completion
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 
 	celhelper "github.com/oakwood-commons/kvx/internal/cel"
@@ -64,6 +66,9 @@ type CELProvider struct {
 	functions     []FunctionMetadata
 	functionDocs  map[string]string
 	functionCache []string // cached function list for UI
+
+	envOnce sync.Once
+	env     *cel.Env // parse environment, built on first use
 }
 
 // NewCELProvider creates a new CEL completion provider.
@@ -88,6 +93,16 @@ func newCELEnv() (*cel.Env, error) {
 	return eval.GetEnvironment(), nil
 }
 
+// parseEnv returns the provider's CEL environment, building it on first use.
+// Building one loads every extension, so callers on the render path must not
+// create their own. It returns nil if the environment cannot be created.
+func (p *CELProvider) parseEnv() *cel.Env {
+	p.envOnce.Do(func() {
+		p.env, _ = newCELEnv()
+	})
+	return p.env
+}
+
 // discoverFunctions programmatically discovers CEL functions using the evaluator.
 func (p *CELProvider) discoverFunctions() {
 	// Get function documentation (includes examples and signatures)
@@ -196,8 +211,7 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 	// Try AST-based parsing for the base expression (before trailing . or [)
 	// This gives us accurate function call detection for complete sub-expressions
 	if !useStringParsing {
-		env, _ := newCELEnv()
-		if env != nil {
+		if env := p.parseEnv(); env != nil {
 			parsed, _ := ParseCELExpression(input, env)
 			if parsed != nil {
 				hasRoot = parsed.HasRoot
@@ -292,7 +306,6 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 	if context.PartialToken != "" {
 		partial = context.PartialToken
 	}
-	partialLower := strings.ToLower(partial)
 	completions := []Completion{}
 
 	// If partial is empty and we have numeric indices, include the base expression itself
@@ -317,7 +330,7 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 	// Add field/key completions
 	keys := listKeys(currentNode)
 	for _, key := range keys {
-		if partialLower == "" || strings.HasPrefix(strings.ToLower(key), partialLower) {
+		if matchScore, matches, ok := Match(key, partial, context.MatchMode); ok {
 			// For old model mode (no underscore), just return the key name
 			// For expression mode (with underscore), return the full path
 			var completionText string
@@ -344,13 +357,13 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 				Display: key,
 				Kind:    CompletionField,
 				Detail:  fmt.Sprintf("field: %s", key),
-				Score:   100, // Fields get higher priority
+				Score:   100 + matchScore, // Fields get higher priority within a match tier
+				Matches: matches,
 			})
 		}
 	}
 
 	// Add function completions
-	funcPartial := strings.ToLower(partial)
 	seenFn := make(map[string]bool)
 
 	for _, meta := range p.functions {
@@ -358,8 +371,7 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 			continue
 		}
 
-		fnLower := strings.ToLower(meta.Name)
-		if funcPartial == "" || strings.HasPrefix(fnLower, funcPartial) {
+		if matchScore, matches, ok := Match(meta.Name, partial, context.MatchMode); ok {
 			norm := normalizeFuncName(meta.Name)
 			if seenFn[norm] {
 				continue
@@ -409,14 +421,9 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 				completionText = meta.Name
 			}
 
-			// Boost score for better prefix matches
-			score := 50 // Base score for functions
-			if funcPartial != "" && strings.HasPrefix(fnLower, funcPartial) {
-				// Exact prefix match - boost significantly
-				if len(funcPartial) > 0 {
-					score += len(funcPartial) * 10 // More typed chars = higher score
-				}
-			}
+			// Base score for functions; the match score ranks prefix over
+			// substring over fuzzy hits
+			score := 50 + matchScore
 
 			completions = append(completions, Completion{
 				Text:        completionText,
@@ -426,6 +433,7 @@ func (p *CELProvider) FilterCompletions(input string, context CompletionContext)
 				Description: meta.Description,
 				Score:       score,
 				Function:    &meta,
+				Matches:     matches,
 			})
 		}
 	}
@@ -489,6 +497,8 @@ func inferGoType(value interface{}) string {
 		return "uint"
 	case float32, float64:
 		return "double"
+	case json.Number:
+		return celhelper.NumberType(v)
 	case []interface{}:
 		return "list"
 	case map[string]interface{}:
@@ -713,7 +723,7 @@ func inferNodeType(node interface{}) string {
 	if node == nil {
 		return "null"
 	}
-	switch node.(type) {
+	switch v := node.(type) {
 	case map[string]interface{}:
 		return "map"
 	case []interface{}:
@@ -724,6 +734,8 @@ func inferNodeType(node interface{}) string {
 		return "bool"
 	case float64:
 		return "double"
+	case json.Number:
+		return celhelper.NumberType(v)
 	case int, int64:
 		return "int"
 	case uint, uint64:
@@ -771,7 +783,7 @@ func categorizeFunction(name, desc string) string {
 	if contains(nameLower, "regex", "matches") {
 		return "regex"
 	}
-	if contains(nameLower, "base64", "encode", "decode") {
+	if contains(nameLower, "base64", "encode", "decode", "sha256", "md5", "hex") {
 		return "encoding"
 	}
 	if contains(nameLower, "keys", "values", "has") {
@@ -825,6 +837,13 @@ var knownMethods = map[string]bool{
 	"lowerAscii":  true,
 	"upperAscii":  true,
 	"format":      true,
+	"padLeft":     true,
+	"padRight":    true,
+	"trimPrefix":  true,
+	"trimSuffix":  true,
+	"replaceAll":  true,
+	"toSnakeCase": true,
+	"toCamelCase": true,
 
 	// Map methods
 	"keys":   true,
@@ -284,3 +284,28 @@ func TestCELProvider_EnrichWithExamples(t *testing.T) {
 	assert.Equal(t, "Filter elements", funcs[0].Description)
 	assert.Contains(t, funcs[0].Examples, "_.items.filter(x, x > 0)")
 }
+
+func TestFilterCompletions_FuzzyFieldMatch(t *testing.T) {
+	p, err := NewCELProvider()
+	require.NoError(t, err)
+	data := map[string]any{"podStatus": "Running", "name": "web", "state": "ok"}
+
+	comps := p.FilterCompletions("_.stat", CompletionContext{CurrentNode: data, CurrentType: "map", PartialToken: "stat"})
+	var fields []string
+	for _, c := range comps {
+		if c.Kind == CompletionField {
+			fields = append(fields, c.Display)
+		}
+	}
+	require.Contains(t, fields, "podStatus")
+	for _, c := range comps {
+		if c.Display == "podStatus" {
+			assert.Equal(t, []int{3, 4, 5, 6}, c.Matches)
+		}
+	}
+
+	strict := p.FilterCompletions("_.stat", CompletionContext{CurrentNode: data, CurrentType: "map", PartialToken: "stat", MatchMode: MatchPrefix})
+	for _, c := range strict {
+		assert.NotEqual(t, "podStatus", c.Display, "prefix mode should not return substring matches")
+	}
+}
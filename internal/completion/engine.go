@@ -27,6 +27,21 @@ type Provider interface {
 	IsExpression(expr string) bool
 }
 
+// SyntaxChecker is an optional Provider extension that reports the position
+// of the first syntax error in an expression, for inline error markers.
+type SyntaxChecker interface {
+	// SyntaxError returns the rune offset and message of the first parse
+	// error in expr. ok is false when expr parses cleanly.
+	SyntaxError(expr string) (pos int, msg string, ok bool)
+}
+
+// Tokenizer is an optional Provider extension that splits expressions in the
+// provider's language into tokens for syntax highlighting. Providers without
+// it are highlighted with Tokenize.
+type Tokenizer interface {
+	Tokenize(expr string) []Token
+}
+
 // FunctionMetadata describes a function available in the expression language.
 type FunctionMetadata struct {
 	Name        string   // Function name (e.g., "contains", "map", "filter")
@@ -48,6 +63,7 @@ type Completion struct {
 	Description string            // Longer description for help panel
 	Score       int               // Relevance score for sorting (higher = more relevant)
 	Function    *FunctionMetadata // Optional: full function metadata if Kind == CompletionFunction
+	Matches     []int             // Rune indexes in Display matched by the partial token (for highlighting)
 }
 
 // CompletionKind indicates the type of completion.
@@ -83,12 +99,17 @@ type CompletionContext struct {
 
 	// IsAfterDot indicates if completion is happening after a "." operator
 	IsAfterDot bool
+
+	// MatchMode controls how PartialToken is matched against candidates.
+	// The engine fills this in from its own setting.
+	MatchMode MatchMode
 }
 
 // CompletionEngine wraps a Provider and adds common filtering/scoring logic.
 type CompletionEngine struct {
-	provider Provider
-	registry *FunctionRegistry
+	provider  Provider
+	registry  *FunctionRegistry
+	matchMode MatchMode
 }
 
 //revive:enable:exported
@@ -108,9 +129,59 @@ func NewEngine(provider Provider) *CompletionEngine {
 
 // GetCompletions returns filtered and scored completions for the current input.
 func (e *CompletionEngine) GetCompletions(input string, context CompletionContext) []Completion {
+	context.MatchMode = e.matchMode
 	return e.provider.FilterCompletions(input, context)
 }
 
+// SignatureHelp returns parameter hints for the innermost unclosed function
+// call in input, using the registry's function metadata. The second return
+// value is false when the cursor is not inside a known call or the function
+// has no discoverable parameter list.
+func (e *CompletionEngine) SignatureHelp(input string) (SignatureHelp, bool) {
+	name, argIndex, ok := ActiveCall(input)
+	if !ok || e.registry == nil {
+		return SignatureHelp{}, false
+	}
+	fn := e.registry.GetFunction(name)
+	if fn == nil {
+		return SignatureHelp{}, false
+	}
+	params := ParamNames(*fn)
+	if len(params) == 0 {
+		return SignatureHelp{}, false
+	}
+	return SignatureHelp{Function: *fn, Params: params, ArgIndex: argIndex}, true
+}
+
+// SyntaxError reports the first parse error in expr when the provider
+// implements SyntaxChecker.
+func (e *CompletionEngine) SyntaxError(expr string) (pos int, msg string, ok bool) {
+	checker, isChecker := e.provider.(SyntaxChecker)
+	if !isChecker {
+		return 0, "", false
+	}
+	return checker.SyntaxError(expr)
+}
+
+// Tokenize splits expr into highlighting tokens with the provider's
+// Tokenizer, or with Tokenize when it has none.
+func (e *CompletionEngine) Tokenize(expr string) []Token {
+	if tokenizer, ok := e.provider.(Tokenizer); ok {
+		return tokenizer.Tokenize(expr)
+	}
+	return Tokenize(expr)
+}
+
+// SetMatchMode sets how partial tokens are matched (fuzzy by default).
+func (e *CompletionEngine) SetMatchMode(mode MatchMode) {
+	e.matchMode = mode
+}
+
+// MatchMode returns the engine's current match mode.
+func (e *CompletionEngine) MatchMode() MatchMode {
+	return e.matchMode
+}
+
 // GetFunctions returns all available functions (deduplicated via registry).
 func (e *CompletionEngine) GetFunctions() []FunctionMetadata {
 	return e.registry.GetAll()
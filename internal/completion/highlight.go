@@ -0,0 +1,217 @@
+package completion
+
+import (
+	"unicode"
+)
+
+// TokenKind classifies a lexical token in an expression for syntax highlighting.
+type TokenKind int
+
+const (
+	TokenSpace    TokenKind = iota // Whitespace
+	TokenIdent                     // Field or variable name
+	TokenRoot                      // The root reference "_"
+	TokenFunction                  // Identifier followed by "("
+	TokenKeyword                   // true, false, null, in
+	TokenString                    // Quoted string literal (may be unterminated)
+	TokenNumber                    // Integer, unsigned, or floating point literal
+	TokenBracket                   // One of ()[]{}
+	TokenOperator                  // Any other punctuation
+)
+
+// Token is a lexical token with rune offsets into the source expression.
+type Token struct {
+	Kind  TokenKind
+	Start int // Rune index of the first rune
+	End   int // Rune index one past the last rune
+	Text  string
+}
+
+var highlightKeywords = map[string]bool{
+	"true":  true,
+	"false": true,
+	"null":  true,
+	"in":    true,
+}
+
+// Tokenize splits expr into tokens for syntax highlighting. It is a forgiving
+// lexer intended for partially typed input: unterminated strings run to the
+// end of the input and unknown characters become operators.
+func Tokenize(expr string) []Token {
+	runes := []rune(expr)
+	var tokens []Token
+	emit := func(kind TokenKind, start, end int) {
+		tokens = append(tokens, Token{Kind: kind, Start: start, End: end, Text: string(runes[start:end])})
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+			emit(TokenSpace, start, i)
+		case r == '"' || r == '\'':
+			i = scanString(runes, i)
+			emit(TokenString, start, i)
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) && !afterOperand(tokens)):
+			i = scanNumber(runes, i)
+			emit(TokenNumber, start, i)
+		case r == '_' || unicode.IsLetter(r):
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := TokenIdent
+			switch {
+			case word == "_":
+				kind = TokenRoot
+			case highlightKeywords[word]:
+				kind = TokenKeyword
+			case nextNonSpace(runes, i) == '(':
+				kind = TokenFunction
+			}
+			emit(kind, start, i)
+		case isBracket(r):
+			i++
+			emit(TokenBracket, start, i)
+		default:
+			i++
+			emit(TokenOperator, start, i)
+		}
+	}
+	return tokens
+}
+
+// MatchingBracket returns the rune index of the bracket that pairs with the
+// bracket at pos, or -1 if pos is not a bracket or has no partner. Brackets
+// inside string literals are ignored.
+func MatchingBracket(expr string, pos int) int {
+	runes := []rune(expr)
+	if pos < 0 || pos >= len(runes) || !isBracket(runes[pos]) {
+		return -1
+	}
+	// Collect brackets outside strings, in order.
+	var brackets []int
+	for _, tok := range Tokenize(expr) {
+		if tok.Kind == TokenBracket {
+			brackets = append(brackets, tok.Start)
+		}
+	}
+	var stack []int
+	pairs := make(map[int]int)
+	for _, idx := range brackets {
+		switch runes[idx] {
+		case '(', '[', '{':
+			stack = append(stack, idx)
+		default:
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			if closingFor(runes[open]) != runes[idx] {
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			pairs[open] = idx
+			pairs[idx] = open
+		}
+	}
+	if partner, ok := pairs[pos]; ok {
+		return partner
+	}
+	return -1
+}
+
+func isBracket(r rune) bool {
+	switch r {
+	case '(', ')', '[', ']', '{', '}':
+		return true
+	}
+	return false
+}
+
+func closingFor(r rune) rune {
+	switch r {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	}
+	return 0
+}
+
+func scanString(runes []rune, i int) int {
+	quote := runes[i]
+	i++
+	for i < len(runes) {
+		switch runes[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			return i + 1
+		}
+		i++
+	}
+	if i > len(runes) {
+		return len(runes)
+	}
+	return i
+}
+
+func scanNumber(runes []rune, i int) int {
+	if runes[i] == '0' && i+1 < len(runes) && (runes[i+1] == 'x' || runes[i+1] == 'X') {
+		i += 2
+		for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.Is(unicode.ASCII_Hex_Digit, runes[i])) {
+			i++
+		}
+		return scanNumberSuffix(runes, i)
+	}
+	for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+		if runes[i] == '.' && (i+1 >= len(runes) || !unicode.IsDigit(runes[i+1])) {
+			break
+		}
+		i++
+	}
+	return scanNumberSuffix(runes, i)
+}
+
+func scanNumberSuffix(runes []rune, i int) int {
+	if i < len(runes) && (runes[i] == 'u' || runes[i] == 'U') {
+		i++
+	}
+	return i
+}
+
+func nextNonSpace(runes []rune, i int) rune {
+	for i < len(runes) {
+		if !unicode.IsSpace(runes[i]) {
+			return runes[i]
+		}
+		i++
+	}
+	return 0
+}
+
+// afterOperand reports whether the previous non-space token ends a value, in
+// which case a following '.' is member access rather than a number.
+func afterOperand(tokens []Token) bool {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		switch tokens[i].Kind {
+		case TokenSpace:
+			continue
+		case TokenIdent, TokenRoot, TokenNumber, TokenString, TokenKeyword:
+			return true
+		case TokenBracket:
+			t := tokens[i].Text
+			return t == ")" || t == "]" || t == "}"
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	toks := Tokenize(`_.items.filter(x, x.name == "a(b" && x.n > 1.5)`)
+	kinds := map[string]TokenKind{}
+	for _, tok := range toks {
+		kinds[tok.Text] = tok.Kind
+	}
+	assert.Equal(t, TokenRoot, kinds["_"])
+	assert.Equal(t, TokenIdent, kinds["items"])
+	assert.Equal(t, TokenFunction, kinds["filter"])
+	assert.Equal(t, TokenString, kinds[`"a(b"`])
+	assert.Equal(t, TokenNumber, kinds["1.5"])
+	assert.Equal(t, TokenBracket, kinds["("])
+	assert.Equal(t, TokenOperator, kinds["=="[:1]])
+
+	// Token offsets cover the input contiguously.
+	end := 0
+	for _, tok := range toks {
+		assert.Equal(t, end, tok.Start)
+		end = tok.End
+	}
+	assert.Equal(t, len([]rune(`_.items.filter(x, x.name == "a(b" && x.n > 1.5)`)), end)
+}
+
+func TestTokenize_KeywordsAndUnterminatedString(t *testing.T) {
+	toks := Tokenize(`x in [true, null] && _.s == 'open`)
+	var kw []string
+	for _, tok := range toks {
+		if tok.Kind == TokenKeyword {
+			kw = append(kw, tok.Text)
+		}
+	}
+	assert.Equal(t, []string{"in", "true", "null"}, kw)
+	last := toks[len(toks)-1]
+	assert.Equal(t, TokenString, last.Kind)
+	assert.Equal(t, "'open", last.Text)
+}
+
+func TestTokenize_IndexAfterDotIsNumber(t *testing.T) {
+	toks := Tokenize("_.items.0")
+	assert.Equal(t, TokenNumber, toks[len(toks)-1].Kind)
+	assert.Equal(t, "0", toks[len(toks)-1].Text)
+}
+
+func TestMatchingBracket(t *testing.T) {
+	expr := `_.a.filter(x, x["k)"] > 1)`
+	open := 10
+	assert.Equal(t, '(', []rune(expr)[open])
+	closeIdx := len([]rune(expr)) - 1
+	assert.Equal(t, closeIdx, MatchingBracket(expr, open))
+	assert.Equal(t, open, MatchingBracket(expr, closeIdx))
+
+	// Brackets in strings are ignored; the index bracket pairs normally.
+	lb := 15
+	assert.Equal(t, '[', []rune(expr)[lb])
+	assert.Equal(t, 20, MatchingBracket(expr, lb))
+
+	assert.Equal(t, -1, MatchingBracket("_.a.filter(x", 10))
+	assert.Equal(t, -1, MatchingBracket("_.a", 1))
+	assert.Equal(t, -1, MatchingBracket("_.a", 10))
+}
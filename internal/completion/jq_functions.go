@@ -0,0 +1,148 @@
+package completion
+
+import "strings"
+
+// jqFunction builds the metadata of a jq builtin from its signature, e.g.
+// "sort_by(f)" or "range(from; upto)".
+func jqFunction(signature, category, description string, examples ...string) FunctionMetadata {
+	name, params, _ := strings.Cut(signature, "(")
+	fn := FunctionMetadata{
+		Name:        name,
+		Signature:   signature,
+		Description: description,
+		Category:    category,
+		ParamTypes:  []string{},
+		Examples:    examples,
+	}
+	if params = strings.TrimSuffix(params, ")"); params != "" {
+		for _, p := range strings.Split(params, ";") {
+			fn.ParamTypes = append(fn.ParamTypes, strings.TrimSpace(p))
+		}
+	}
+	return fn
+}
+
+// jqFunctions lists the jq builtins offered by completion and the function
+// palette in jq mode. Every entry is checked against gojq in the tests.
+var jqFunctions = []FunctionMetadata{
+	jqFunction("length", "general", "Number of elements, keys, or characters; absolute value of a number", ".items | length"),
+	jqFunction("utf8bytelength", "string", "Number of bytes of a string in UTF-8"),
+	jqFunction("not", "general", "true when the input is false or null"),
+	jqFunction("keys", "map", "Sorted keys of an object, or indexes of an array", "keys"),
+	jqFunction("values", "general", "The input unless it is null"),
+	jqFunction("has(key)", "map", "Whether an object has the key, or an array the index", `has("name")`),
+	jqFunction("in(object)", "map", "Whether the input key is in the object"),
+	jqFunction("map(f)", "list", "Applies f to each element of an array", "map(.name)"),
+	jqFunction("map_values(f)", "map", "Applies f to each value of an object or array", "map_values(. * 2)"),
+	jqFunction("path(f)", "general", "Paths of the values f selects, as arrays of keys"),
+	jqFunction("paths", "general", "Paths of every value below the input"),
+	jqFunction("paths(f)", "general", "Paths of the values below the input for which f is true", "paths(type == \"number\")"),
+	jqFunction("getpath(path)", "general", "Value at a path given as an array of keys", `getpath(["a", "b"])`),
+	jqFunction("setpath(path; value)", "general", "Sets the value at a path"),
+	jqFunction("delpaths(paths)", "general", "Deletes the values at the paths"),
+	jqFunction("del(f)", "general", "Deletes the values f selects", "del(.password)"),
+	jqFunction("pick(f)", "map", "Keeps only the values f selects, at their paths", "pick(.name, .id)"),
+	jqFunction("to_entries", "map", "Converts an object to an array of {key, value}"),
+	jqFunction("from_entries", "map", "Converts an array of {key, value} to an object"),
+	jqFunction("with_entries(f)", "map", "Applies f to the {key, value} entries of an object", "with_entries(select(.value != null))"),
+	jqFunction("select(f)", "list", "The input when f is true, otherwise nothing", "select(.size > 10)"),
+	jqFunction("recurse", "general", "The input and every value below it"),
+	jqFunction("recurse(f)", "general", "The input and the values f produces, recursively"),
+	jqFunction("env", "general", "Environment variables as an object"),
+	jqFunction("type", "conversion", "Type name of the input: null, boolean, number, string, array, or object"),
+	jqFunction("arrays", "list", "The input when it is an array"),
+	jqFunction("objects", "map", "The input when it is an object"),
+	jqFunction("iterables", "general", "The input when it is an array or object"),
+	jqFunction("booleans", "general", "The input when it is a boolean"),
+	jqFunction("numbers", "math", "The input when it is a number"),
+	jqFunction("strings", "string", "The input when it is a string"),
+	jqFunction("nulls", "general", "The input when it is null"),
+	jqFunction("scalars", "general", "The input when it is not an array or object"),
+	jqFunction("empty", "general", "Produces no output"),
+	jqFunction("error(message)", "general", "Raises an error with the message"),
+	jqFunction("add", "list", "Sums numbers, concatenates strings and arrays, or merges objects", "[.items[].size] | add"),
+	jqFunction("any", "list", "Whether any element of an array is true"),
+	jqFunction("any(f)", "list", "Whether f is true for any element of an array"),
+	jqFunction("all", "list", "Whether every element of an array is true"),
+	jqFunction("all(f)", "list", "Whether f is true for every element of an array"),
+	jqFunction("flatten", "list", "Flattens nested arrays"),
+	jqFunction("flatten(depth)", "list", "Flattens nested arrays up to depth levels"),
+	jqFunction("range(upto)", "list", "Numbers from 0 below upto"),
+	jqFunction("range(from; upto)", "list", "Numbers from from below upto"),
+	jqFunction("floor", "math", "Rounds a number down"),
+	jqFunction("ceil", "math", "Rounds a number up"),
+	jqFunction("round", "math", "Rounds a number to the nearest integer"),
+	jqFunction("sqrt", "math", "Square root of a number"),
+	jqFunction("abs", "math", "Absolute value of a number"),
+	jqFunction("tostring", "conversion", "Converts the input to a string; strings are kept"),
+	jqFunction("tonumber", "conversion", "Parses a string as a number; numbers are kept"),
+	jqFunction("tojson", "encoding", "Encodes the input as JSON text"),
+	jqFunction("fromjson", "encoding", "Parses JSON text"),
+	jqFunction("ascii_downcase", "string", "Lowercases the ASCII letters of a string"),
+	jqFunction("ascii_upcase", "string", "Uppercases the ASCII letters of a string"),
+	jqFunction("ltrimstr(prefix)", "string", "Removes a prefix from a string"),
+	jqFunction("rtrimstr(suffix)", "string", "Removes a suffix from a string"),
+	jqFunction("trim", "string", "Removes leading and trailing whitespace from a string"),
+	jqFunction("startswith(prefix)", "string", "Whether a string starts with the prefix", `select(.name | startswith("web"))`),
+	jqFunction("endswith(suffix)", "string", "Whether a string ends with the suffix"),
+	jqFunction("split(separator)", "string", "Splits a string on a separator", `split(",")`),
+	jqFunction("join(separator)", "string", "Joins an array of strings with a separator", `join(", ")`),
+	jqFunction("contains(value)", "general", "Whether the input contains the value, recursively"),
+	jqFunction("inside(value)", "general", "Whether the value contains the input, recursively"),
+	jqFunction("indices(value)", "list", "Indexes where the value occurs in a string or array"),
+	jqFunction("index(value)", "list", "First index of the value in a string or array"),
+	jqFunction("rindex(value)", "list", "Last index of the value in a string or array"),
+	jqFunction("test(regex)", "regex", "Whether a string matches the regular expression", `select(.name | test("^web-"))`),
+	jqFunction("match(regex)", "regex", "Matches of a regular expression, with offsets and captures"),
+	jqFunction("capture(regex)", "regex", "Named captures of a regular expression as an object"),
+	jqFunction("scan(regex)", "regex", "Every match of a regular expression in a string"),
+	jqFunction("splits(regex)", "regex", "Splits a string on a regular expression"),
+	jqFunction("sub(regex; replacement)", "regex", "Replaces the first match of a regular expression"),
+	jqFunction("gsub(regex; replacement)", "regex", "Replaces every match of a regular expression", `gsub("-"; "_")`),
+	jqFunction("sort", "list", "Sorts an array"),
+	jqFunction("sort_by(f)", "list", "Sorts an array by f", "sort_by(.name)"),
+	jqFunction("group_by(f)", "list", "Groups the elements of an array by f", "group_by(.kind)"),
+	jqFunction("unique", "list", "Sorted array without duplicates"),
+	jqFunction("unique_by(f)", "list", "Keeps the first element for each value of f"),
+	jqFunction("min", "math", "Smallest element of an array"),
+	jqFunction("max", "math", "Largest element of an array"),
+	jqFunction("min_by(f)", "math", "Element of an array with the smallest f"),
+	jqFunction("max_by(f)", "math", "Element of an array with the largest f"),
+	jqFunction("reverse", "list", "Reverses an array or string"),
+	jqFunction("first", "list", "First element of an array"),
+	jqFunction("last", "list", "Last element of an array"),
+	jqFunction("first(f)", "list", "First output of f"),
+	jqFunction("last(f)", "list", "Last output of f"),
+	jqFunction("nth(n)", "list", "Element n of an array"),
+	jqFunction("limit(n; f)", "list", "First n outputs of f", "limit(5; .items[])"),
+	jqFunction("until(cond; next)", "general", "Applies next until cond is true"),
+	jqFunction("walk(f)", "general", "Applies f to every value, bottom up"),
+	jqFunction("transpose", "list", "Transposes an array of arrays"),
+	jqFunction("tostream", "general", "Streams the input as [path, leaf] events"),
+	jqFunction("fromstream(f)", "general", "Rebuilds values from [path, leaf] events"),
+	jqFunction("splits(regex; flags)", "regex", "Splits a string on a regular expression with flags"),
+	jqFunction("explode", "string", "Code points of a string"),
+	jqFunction("implode", "string", "String of an array of code points"),
+	jqFunction("todate", "datetime", "Formats Unix seconds as an ISO 8601 date"),
+	jqFunction("fromdate", "datetime", "Parses an ISO 8601 date to Unix seconds"),
+	jqFunction("now", "datetime", "Current time in Unix seconds"),
+	jqFunction("strftime(format)", "datetime", "Formats Unix seconds or broken-down time", `strftime("%Y-%m-%d")`),
+	jqFunction("strptime(format)", "datetime", "Parses a string to broken-down time"),
+	jqFunction("mktime", "datetime", "Converts broken-down time to Unix seconds"),
+	jqFunction("gmtime", "datetime", "Converts Unix seconds to broken-down time"),
+	jqFunction("@base64", "encoding", "Encodes a string as base64"),
+	jqFunction("@base64d", "encoding", "Decodes a base64 string"),
+	jqFunction("@csv", "encoding", "Formats an array as a CSV row"),
+	jqFunction("@tsv", "encoding", "Formats an array as a TSV row"),
+	jqFunction("@json", "encoding", "Encodes the input as JSON text"),
+	jqFunction("@uri", "encoding", "Percent-encodes a string for URLs"),
+	jqFunction("@html", "encoding", "Escapes a string for HTML"),
+	jqFunction("@sh", "encoding", "Quotes a string for POSIX shells"),
+}
+
+// jqKeywords are the words of jq syntax, offered by completion alongside the
+// builtins.
+var jqKeywords = []string{
+	"and", "or", "if", "then", "elif", "else", "end", "as", "def",
+	"reduce", "foreach", "try", "catch", "label", "true", "false", "null",
+}
@@ -0,0 +1,495 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/oakwood-commons/kvx/internal/jq"
+)
+
+// jqProbeTimeout bounds the partial programs run while typing, so a slow or
+// endless program never stalls the input.
+const jqProbeTimeout = 100 * time.Millisecond
+
+// jqProbeKey marks the value a completion probe raises, so it is not
+// mistaken for an error the program raises itself.
+const jqProbeKey = "__kvx_probe__"
+
+// jqProbe raises the keys of an object, or the length of an array, as an
+// error, ending the program at the first value that reaches it.
+const jqProbe = `error({"` + jqProbeKey + `": (if type == "object" then keys elif type == "array" then length else null end)})`
+
+var jqBindingPattern = regexp.MustCompile(`\bas\s*(\$[A-Za-z_]\w*|\[[^\]]*\]|\{[^}]*\})`)
+var jqVariablePattern = regexp.MustCompile(`\$([A-Za-z_]\w*)`)
+
+// JQProvider implements Provider for jq programs. Field completions come
+// from running the typed program, up to the path being completed, against
+// the data.
+type JQProvider struct{}
+
+// NewJQProvider creates a jq completion provider.
+func NewJQProvider() *JQProvider {
+	return &JQProvider{}
+}
+
+// DiscoverFunctions returns the jq builtins.
+func (p *JQProvider) DiscoverFunctions() []FunctionMetadata {
+	return jqFunctions
+}
+
+// FilterCompletions completes the token at the end of input. Each
+// completion's Text is the whole input with that token completed. The data
+// the program runs against is context.CurrentNode.
+func (p *JQProvider) FilterCompletions(input string, context CompletionContext) []Completion {
+	start := len(input)
+	for start > 0 && isJQIdentByte(input[start-1]) {
+		start--
+	}
+	partial := input[start:]
+	if (partial != "" && unicode.IsDigit(rune(partial[0]))) || insideJQString(input[:start]) {
+		return []Completion{}
+	}
+	var pre byte
+	if start > 0 {
+		pre = input[start-1]
+	}
+
+	var completions []Completion
+	switch pre {
+	case '.':
+		completions = jqFieldCompletions(input, start-1, partial, context)
+	case '$':
+		completions = jqVariableCompletions(input, start-1, partial, context.MatchMode)
+	case '@':
+		completions = jqFunctionCompletions(input[:start-1], "@", partial, context.MatchMode)
+	default:
+		if partial != "" || endsJQTerm(input[:start]) {
+			completions = jqFunctionCompletions(input[:start], "", partial, context.MatchMode)
+		}
+	}
+	if completions == nil {
+		completions = []Completion{}
+	}
+	sort.SliceStable(completions, func(i, j int) bool {
+		if completions[i].Score != completions[j].Score {
+			return completions[i].Score > completions[j].Score
+		}
+		return completions[i].Display < completions[j].Display
+	})
+	return completions
+}
+
+// jqFieldCompletions completes the key after the dot at input[dot]. It runs
+// the program up to the path before the dot and offers the keys of the first
+// object it reaches, or iteration of the first array.
+func jqFieldCompletions(input string, dot int, partial string, context CompletionContext) []Completion {
+	runStart := jqPathStart(input, dot)
+	run := input[runStart:dot]
+	if strings.HasSuffix(run, ".") {
+		return nil
+	}
+	if strings.TrimSpace(run) == "" {
+		run = "."
+	}
+	prefix := input[:runStart]
+	program := prefix + "(" + run + " | " + jqProbe + ")" + jqClosers(prefix)
+	probed, ok := runJQProbe(program, context.CurrentNode)
+	if !ok {
+		return nil
+	}
+
+	base := input[:dot]
+	var completions []Completion
+	switch v := probed.(type) {
+	case []any:
+		for _, k := range v {
+			key, isString := k.(string)
+			if !isString {
+				continue
+			}
+			score, matches, ok := Match(key, partial, context.MatchMode)
+			if !ok {
+				continue
+			}
+			completions = append(completions, Completion{
+				Text:    base + jq.FormatPath([]jq.PathStep{{Key: key}}),
+				Display: key,
+				Kind:    CompletionField,
+				Detail:  "field: " + key,
+				Score:   100 + score,
+				Matches: matches,
+			})
+		}
+	case int:
+		if partial != "" {
+			return nil
+		}
+		if strings.TrimSpace(input[runStart:dot]) == "" {
+			base += "."
+		}
+		completions = append(completions,
+			Completion{Text: base + "[]", Display: "[]", Kind: CompletionIndex, Detail: "each element", Score: 100},
+			Completion{Text: base + "[0]", Display: "[0]", Kind: CompletionIndex, Detail: "first element", Score: 90},
+		)
+	}
+	return completions
+}
+
+// runJQProbe runs a probe program and returns the value its probe raised.
+func runJQProbe(program string, root any) (any, bool) {
+	ctx, cancel := contextWithProbeTimeout()
+	defer cancel()
+	_, err := jq.Run(ctx, program, root)
+	raised, ok := jq.ErrorValue(err)
+	if !ok {
+		return nil, false
+	}
+	marker, ok := raised.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	probed, ok := marker[jqProbeKey]
+	return probed, ok
+}
+
+// jqVariableCompletions completes the variable name after the '$' at
+// input[dollar]: $ENV, $__loc__, and variables bound with "as" earlier in
+// the program.
+func jqVariableCompletions(input string, dollar int, partial string, mode MatchMode) []Completion {
+	names := []string{"ENV", "__loc__"}
+	for _, binding := range jqBindingPattern.FindAllStringSubmatch(input[:dollar], -1) {
+		for _, m := range jqVariablePattern.FindAllStringSubmatch(binding[1], -1) {
+			names = append(names, m[1])
+		}
+	}
+	var completions []Completion
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		score, matches, ok := Match(name, partial, mode)
+		if !ok {
+			continue
+		}
+		completions = append(completions, Completion{
+			Text:    input[:dollar] + "$" + name,
+			Display: "$" + name,
+			Kind:    CompletionVariable,
+			Detail:  "variable",
+			Score:   100 + score,
+			Matches: shiftMatches(matches, 1),
+		})
+	}
+	return completions
+}
+
+// jqFunctionCompletions completes a builtin or keyword after base. With
+// sigil "@" only the formats are offered, matched without the '@'.
+func jqFunctionCompletions(base, sigil, partial string, mode MatchMode) []Completion {
+	var completions []Completion
+	seen := make(map[string]bool)
+	for _, fn := range jqFunctions {
+		name := fn.Name
+		if strings.HasPrefix(name, "@") != (sigil == "@") {
+			continue
+		}
+		score, matches, ok := Match(strings.TrimPrefix(name, sigil), partial, mode)
+		if !ok {
+			continue
+		}
+		text := base + name
+		if len(fn.ParamTypes) > 0 {
+			text += "("
+		}
+		if seen[text] {
+			continue
+		}
+		seen[text] = true
+		detail := fn.Description
+		if len(fn.Examples) > 0 {
+			detail += "\ne.g. " + fn.Examples[0]
+		}
+		completions = append(completions, Completion{
+			Text:        text,
+			Display:     fn.Signature,
+			Kind:        CompletionFunction,
+			Detail:      detail,
+			Description: fn.Description,
+			Score:       50 + score,
+			Function:    &fn,
+			Matches:     shiftMatches(matches, len(sigil)),
+		})
+	}
+	if sigil != "" || partial == "" {
+		return completions
+	}
+	for _, kw := range jqKeywords {
+		score, matches, ok := Match(kw, partial, mode)
+		if !ok {
+			continue
+		}
+		completions = append(completions, Completion{
+			Text:    base + kw,
+			Display: kw,
+			Kind:    CompletionKeyword,
+			Detail:  "keyword",
+			Score:   40 + score,
+			Matches: matches,
+		})
+	}
+	return completions
+}
+
+// EvaluateType runs expr against context.CurrentNode and returns the type of
+// its result, or "" when it fails or runs too long.
+func (p *JQProvider) EvaluateType(expr string, context CompletionContext) string {
+	if strings.TrimSpace(expr) == "" || context.CurrentNode == nil {
+		return ""
+	}
+	ctx, cancel := contextWithProbeTimeout()
+	defer cancel()
+	outputs, err := jq.Run(ctx, expr, context.CurrentNode)
+	if err != nil {
+		return ""
+	}
+	return inferGoType(jq.Collapse(outputs))
+}
+
+// Evaluate runs the jq program expr against root.
+func (p *JQProvider) Evaluate(expr string, root interface{}) (interface{}, error) {
+	return jq.Evaluate(expr, root)
+}
+
+// IsExpression reports whether expr is more than a plain path of field and
+// index steps.
+func (p *JQProvider) IsExpression(expr string) bool {
+	_, ok := jq.SplitPath(expr)
+	return !ok
+}
+
+// SyntaxError reports the first parse error in expr.
+func (p *JQProvider) SyntaxError(expr string) (pos int, msg string, ok bool) {
+	if strings.TrimSpace(expr) == "" {
+		return 0, "", false
+	}
+	var se *jq.SyntaxError
+	if !errors.As(jq.Check(expr), &se) {
+		return 0, "", false
+	}
+	return se.Pos, se.Msg, true
+}
+
+// Tokenize splits expr into jq tokens for syntax highlighting.
+func (p *JQProvider) Tokenize(expr string) []Token {
+	return TokenizeJQ(expr)
+}
+
+// contextWithProbeTimeout bounds a run with jqProbeTimeout.
+func contextWithProbeTimeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), jqProbeTimeout)
+}
+
+// TokenizeJQ splits a jq program into tokens for syntax highlighting, as
+// forgivingly as Tokenize: "." and ".." are the root, field names after a
+// dot and $variables are identifiers, keywords are keywords, and other
+// names, including @formats, are functions. Comments are drawn as spaces.
+func TokenizeJQ(expr string) []Token {
+	runes := []rune(expr)
+	var tokens []Token
+	emit := func(kind TokenKind, start, end int) {
+		tokens = append(tokens, Token{Kind: kind, Start: start, End: end, Text: string(runes[start:end])})
+	}
+	isIdentStart := func(i int) bool {
+		return i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]))
+	}
+	scanIdent := func(i int) int {
+		for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			i++
+		}
+		return i
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r) || r == '#':
+			for i < len(runes) && (unicode.IsSpace(runes[i]) || runes[i] == '#') {
+				if runes[i] == '#' {
+					for i < len(runes) && runes[i] != '\n' {
+						i++
+					}
+					continue
+				}
+				i++
+			}
+			emit(TokenSpace, start, i)
+		case r == '"':
+			i = scanString(runes, i)
+			emit(TokenString, start, i)
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) && !afterOperand(tokens)):
+			i = scanNumber(runes, i)
+			emit(TokenNumber, start, i)
+		case r == '.':
+			i++
+			if isIdentStart(i) || (i < len(runes) && runes[i] == '"') {
+				emit(TokenOperator, start, i)
+				if runes[i] != '"' {
+					end := scanIdent(i)
+					emit(TokenIdent, i, end)
+					i = end
+				}
+				continue
+			}
+			if i < len(runes) && runes[i] == '.' {
+				i++
+			}
+			emit(TokenRoot, start, i)
+		case (r == '$' || r == '@') && isIdentStart(i+1):
+			i = scanIdent(i + 1)
+			kind := TokenIdent
+			if r == '@' {
+				kind = TokenFunction
+			}
+			emit(kind, start, i)
+		case isIdentStart(i):
+			i = scanIdent(i)
+			word := string(runes[start:i])
+			kind := TokenFunction
+			switch {
+			case nextNonSpace(runes, i) == ':':
+				kind = TokenIdent
+			case isJQKeyword(word):
+				kind = TokenKeyword
+			}
+			emit(kind, start, i)
+		case isBracket(r):
+			i++
+			emit(TokenBracket, start, i)
+		default:
+			i++
+			emit(TokenOperator, start, i)
+		}
+	}
+	return tokens
+}
+
+func isJQKeyword(word string) bool {
+	for _, kw := range jqKeywords {
+		if kw == word {
+			return true
+		}
+	}
+	return false
+}
+
+func isJQIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// jqPathStart returns where the path ending at input[end] starts, scanning
+// back over names, dots, variables, '?', and bracketed or quoted steps.
+func jqPathStart(input string, end int) int {
+	i := end
+	for i > 0 {
+		c := input[i-1]
+		switch {
+		case isJQIdentByte(c) || c == '.' || c == '$' || c == '?':
+			i--
+		case c == ']' || c == ')':
+			open := jqOpeningBracket(input, i-1)
+			if open < 0 {
+				return i
+			}
+			i = open
+		case c == '"':
+			open := strings.LastIndexByte(input[:i-1], '"')
+			if open < 0 {
+				return i
+			}
+			i = open
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// jqOpeningBracket returns the index of the bracket opening the one at
+// input[closeAt], or -1.
+func jqOpeningBracket(input string, closeAt int) int {
+	depth := 0
+	for i := closeAt; i >= 0; i-- {
+		switch input[i] {
+		case ')', ']', '}':
+			depth++
+		case '(', '[', '{':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// jqClosers returns the brackets that close the ones left open in prefix.
+func jqClosers(prefix string) string {
+	var stack []rune
+	runes := []rune(prefix)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '"':
+			i = scanString(runes, i) - 1
+		case r == '(' || r == '[' || r == '{':
+			stack = append(stack, closingFor(r))
+		case (r == ')' || r == ']' || r == '}') && len(stack) > 0:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	var b strings.Builder
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteRune(stack[i])
+	}
+	return b.String()
+}
+
+// insideJQString reports whether s ends inside a string literal.
+func insideJQString(s string) bool {
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case inString && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inString = !inString
+		}
+	}
+	return inString
+}
+
+// endsJQTerm reports whether s is empty or ends where a new term starts:
+// after a pipe, comma, semicolon, or opening parenthesis.
+func endsJQTerm(s string) bool {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	return s == "" || strings.ContainsAny(s[len(s)-1:], "|,;(")
+}
+
+// shiftMatches offsets match positions by n runes, for displays that add a
+// sigil before the matched name.
+func shiftMatches(matches []int, n int) []int {
+	if n == 0 || matches == nil {
+		return matches
+	}
+	shifted := make([]int, len(matches))
+	for i, m := range matches {
+		shifted[i] = m + n
+	}
+	return shifted
+}
@@ -0,0 +1,156 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/itchyny/gojq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jqTestData() map[string]interface{} {
+	return map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "size": 1, "bad-key": true},
+			map[string]interface{}{"name": "b", "size": 2},
+		},
+		"meta": map[string]interface{}{"owner": "x"},
+	}
+}
+
+func completionTexts(completions []Completion) []string {
+	texts := make([]string, 0, len(completions))
+	for _, c := range completions {
+		texts = append(texts, c.Text)
+	}
+	return texts
+}
+
+func TestJQProviderFieldCompletions(t *testing.T) {
+	p := NewJQProvider()
+	ctx := CompletionContext{CurrentNode: jqTestData()}
+
+	assert.ElementsMatch(t, []string{".items", ".meta"}, completionTexts(p.FilterCompletions(".", ctx)))
+	assert.Equal(t, []string{".meta"}, completionTexts(p.FilterCompletions(".me", ctx)))
+	assert.Equal(t, []string{".meta.owner"}, completionTexts(p.FilterCompletions(".meta.", ctx)))
+	assert.Equal(t, []string{".items[]", ".items[0]"}, completionTexts(p.FilterCompletions(".items.", ctx)))
+
+	got := completionTexts(p.FilterCompletions(".items[0].", ctx))
+	assert.ElementsMatch(t, []string{`.items[0]."bad-key"`, ".items[0].name", ".items[0].size"}, got)
+
+	got = completionTexts(p.FilterCompletions(".items | map(.na", ctx))
+	assert.Equal(t, []string{".items | map(.name"}, got, "the probe runs inside the unclosed call")
+
+	got = completionTexts(p.FilterCompletions(".items[] as $it | $it.si", ctx))
+	assert.Equal(t, []string{".items[] as $it | $it.size"}, got)
+
+	assert.Empty(t, p.FilterCompletions(".nope.", ctx), "paths to missing values offer nothing")
+	assert.Empty(t, p.FilterCompletions(`.meta | "x.`, ctx), "no completion inside strings")
+	assert.Empty(t, p.FilterCompletions(`error("boom") | .`, ctx), "the program's own errors offer nothing")
+}
+
+func TestJQProviderFunctionCompletions(t *testing.T) {
+	p := NewJQProvider()
+	ctx := CompletionContext{CurrentNode: jqTestData(), MatchMode: MatchPrefix}
+
+	completions := p.FilterCompletions(".items | sort_b", ctx)
+	require.NotEmpty(t, completions)
+	assert.Equal(t, ".items | sort_by(", completions[0].Text)
+	assert.Equal(t, CompletionFunction, completions[0].Kind)
+	require.NotNil(t, completions[0].Function)
+	assert.Equal(t, "sort_by", completions[0].Function.Name)
+
+	texts := completionTexts(p.FilterCompletions(".items | len", ctx))
+	assert.Equal(t, []string{".items | length"}, texts, "functions without parameters are completed whole")
+
+	texts = completionTexts(p.FilterCompletions("if . the", ctx))
+	assert.Contains(t, texts, "if . then")
+
+	texts = completionTexts(p.FilterCompletions(".items[0].name | @base", ctx))
+	assert.Equal(t, []string{".items[0].name | @base64", ".items[0].name | @base64d"}, texts)
+
+	texts = completionTexts(p.FilterCompletions(".items[] as [$a, $b] | $", ctx))
+	assert.ElementsMatch(t, []string{
+		".items[] as [$a, $b] | $ENV", ".items[] as [$a, $b] | $__loc__",
+		".items[] as [$a, $b] | $a", ".items[] as [$a, $b] | $b",
+	}, texts)
+
+	assert.NotEmpty(t, p.FilterCompletions(".items | ", ctx), "a new pipeline stage lists the builtins")
+	assert.Empty(t, p.FilterCompletions(".items ", ctx))
+}
+
+func TestJQFunctionsExist(t *testing.T) {
+	for _, fn := range jqFunctions {
+		expr := fn.Name
+		if len(fn.ParamTypes) > 0 {
+			args := make([]string, len(fn.ParamTypes))
+			for i := range args {
+				args[i] = "."
+			}
+			expr += "(" + strings.Join(args, "; ") + ")"
+		}
+		query, err := gojq.Parse(expr)
+		require.NoError(t, err, fn.Signature)
+		_, err = gojq.Compile(query)
+		assert.NoError(t, err, "%s is not a gojq builtin", fn.Signature)
+	}
+}
+
+func TestJQProviderEvaluate(t *testing.T) {
+	p := NewJQProvider()
+	data := jqTestData()
+
+	got, err := p.Evaluate(".items | length", data)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+
+	assert.Equal(t, "list", p.EvaluateType(".items", CompletionContext{CurrentNode: data}))
+	assert.Equal(t, "int", p.EvaluateType(".items[0].size", CompletionContext{CurrentNode: data}))
+	assert.Empty(t, p.EvaluateType("repeat(1)", CompletionContext{CurrentNode: data}), "runs are bounded")
+
+	assert.False(t, p.IsExpression(".items[0].name"))
+	assert.True(t, p.IsExpression(".items[] | .name"))
+
+	pos, msg, ok := p.SyntaxError(".a ] .b")
+	assert.True(t, ok)
+	assert.Equal(t, 3, pos)
+	assert.Contains(t, msg, "]")
+	_, _, ok = p.SyntaxError(".items | length")
+	assert.False(t, ok)
+}
+
+func TestTokenizeJQ(t *testing.T) {
+	type tok struct {
+		kind TokenKind
+		text string
+	}
+	var got []tok
+	for _, tk := range TokenizeJQ(`.items[] | select(.size > 1) as $x | {name: $x.name, "k": @base64} # note`) {
+		if tk.Kind != TokenSpace {
+			got = append(got, tok{tk.Kind, tk.Text})
+		}
+	}
+	assert.Equal(t, []tok{
+		{TokenOperator, "."}, {TokenIdent, "items"}, {TokenBracket, "["}, {TokenBracket, "]"},
+		{TokenOperator, "|"},
+		{TokenFunction, "select"}, {TokenBracket, "("}, {TokenOperator, "."}, {TokenIdent, "size"},
+		{TokenOperator, ">"}, {TokenNumber, "1"}, {TokenBracket, ")"},
+		{TokenKeyword, "as"}, {TokenIdent, "$x"}, {TokenOperator, "|"},
+		{TokenBracket, "{"}, {TokenIdent, "name"}, {TokenOperator, ":"}, {TokenIdent, "$x"},
+		{TokenOperator, "."}, {TokenIdent, "name"}, {TokenOperator, ","},
+		{TokenString, `"k"`}, {TokenOperator, ":"}, {TokenFunction, "@base64"}, {TokenBracket, "}"},
+	}, got)
+
+	roots := TokenizeJQ(". | .. | .[0]")
+	assert.Equal(t, TokenRoot, roots[0].Kind)
+	assert.Equal(t, "..", roots[4].Text)
+	assert.Equal(t, TokenRoot, roots[4].Kind)
+	assert.Equal(t, TokenRoot, roots[8].Kind)
+
+	engine := NewEngine(NewJQProvider())
+	assert.Equal(t, TokenRoot, engine.Tokenize(".")[0].Kind, "the engine uses the provider's tokenizer")
+	cel, err := NewCELProvider()
+	require.NoError(t, err)
+	assert.Equal(t, TokenOperator, NewEngine(cel).Tokenize(".")[0].Kind)
+}
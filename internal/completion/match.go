@@ -0,0 +1,231 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchMode controls how a partial token is matched against candidate names.
+type MatchMode int
+
+const (
+	// MatchFuzzy accepts prefix, substring, and in-order subsequence matches,
+	// ranked in that order (default).
+	MatchFuzzy MatchMode = iota
+	// MatchPrefix only accepts candidates that start with the partial token.
+	MatchPrefix
+)
+
+// Match scores for each tier. Bonuses within a tier are clamped to
+// matchBonusMax so a weaker tier never outranks a stronger one, and callers
+// can add small per-kind weights (fields vs functions) without crossing tiers.
+const (
+	matchScorePrefix    = 3000
+	matchScoreSubstring = 2000
+	matchScoreFuzzy     = 1000
+	matchBonusMax       = 500
+)
+
+// ParseMatchMode converts a config string ("fuzzy" or "prefix") to a MatchMode.
+// An empty value selects MatchFuzzy; unknown values are an error.
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "fuzzy":
+		return MatchFuzzy, nil
+	case "prefix", "strict", "strict_prefix":
+		return MatchPrefix, nil
+	default:
+		return MatchFuzzy, fmt.Errorf("unknown match mode %q: must be fuzzy or prefix", s)
+	}
+}
+
+// String returns the config name of the match mode.
+func (m MatchMode) String() string {
+	if m == MatchPrefix {
+		return "prefix"
+	}
+	return "fuzzy"
+}
+
+// Match reports whether pattern matches candidate under the given mode.
+// It returns a relevance score (higher is better) and the rune indexes in
+// candidate that matched, for highlighting. Matching is case-insensitive.
+// An empty pattern matches everything with a zero score.
+func Match(candidate, pattern string, mode MatchMode) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+	cand := foldRunes(candidate)
+	pat := foldRunes(pattern)
+	if len(pat) > len(cand) {
+		return 0, nil, false
+	}
+
+	// Prefix: best tier. Shorter candidates rank higher so that an exact
+	// match beats a longer name sharing the same prefix.
+	if hasRunePrefix(cand, pat) {
+		return matchScorePrefix + clampBonus(matchBonusMax-(len(cand)-len(pat))), runeRange(0, len(pat)), true
+	}
+	if mode == MatchPrefix {
+		return 0, nil, false
+	}
+
+	boundary := wordBoundaries(candidate)
+
+	// Substring: prefer an occurrence that starts on a word boundary
+	// (e.g. "stat" in "podStatus" or "pod_status").
+	if idx := indexRunes(cand, pat); idx >= 0 {
+		for i := idx; i+len(pat) <= len(cand); i++ {
+			if boundary[i] && hasRunePrefix(cand[i:], pat) {
+				return matchScoreSubstring + clampBonus(matchBonusMax-i), runeRange(i, len(pat)), true
+			}
+		}
+		return matchScoreSubstring + clampBonus(matchBonusMax/2-idx), runeRange(idx, len(pat)), true
+	}
+
+	// Fuzzy subsequence: every pattern rune must appear in order.
+	// Consecutive runs and word-boundary hits are rewarded; gaps are penalised.
+	positions = make([]int, 0, len(pat))
+	bonus := matchBonusMax / 2
+	pi := 0
+	last := -1
+	for ci := 0; ci < len(cand) && pi < len(pat); ci++ {
+		if cand[ci] != pat[pi] {
+			continue
+		}
+		switch {
+		case last >= 0 && ci == last+1:
+			bonus += 5
+		case boundary[ci]:
+			bonus += 3
+		case last >= 0:
+			bonus -= ci - last - 1
+		}
+		positions = append(positions, ci)
+		last = ci
+		pi++
+	}
+	if pi < len(pat) {
+		return 0, nil, false
+	}
+	return matchScoreFuzzy + clampBonus(bonus), positions, true
+}
+
+func clampBonus(b int) int {
+	switch {
+	case b < 0:
+		return 0
+	case b > matchBonusMax:
+		return matchBonusMax
+	default:
+		return b
+	}
+}
+
+// HighlightMatches renders the runes of s at the given positions with match
+// and everything else with rest (nil leaves it unstyled). Adjacent runes are
+// rendered together so styling escape codes are not emitted per character.
+// Positions outside s are ignored.
+func HighlightMatches(s string, positions []int, match, rest func(string) string) string {
+	if match == nil {
+		return s
+	}
+	if rest == nil {
+		rest = func(v string) string { return v }
+	}
+	if len(positions) == 0 {
+		return rest(s)
+	}
+	hit := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		hit[p] = true
+	}
+	var b strings.Builder
+	var run []rune
+	inMatch := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString(match(string(run)))
+		} else {
+			b.WriteString(rest(string(run)))
+		}
+		run = run[:0]
+	}
+	i := 0
+	for _, r := range s {
+		if hit[i] != inMatch {
+			flush()
+			inMatch = hit[i]
+		}
+		run = append(run, r)
+		i++
+	}
+	flush()
+	return b.String()
+}
+
+// foldRunes lower-cases s one rune at a time. Unlike strings.ToLower it never
+// changes the rune count, so match positions index the original string.
+func foldRunes(s string) []rune {
+	out := []rune(s)
+	for i, r := range out {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if hasRunePrefix(s[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func runeRange(start, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = start + i
+	}
+	return out
+}
+
+// wordBoundaries marks rune indexes that start a word: the first rune,
+// an upper-case rune after a lower-case one (camelCase), and any rune
+// following a separator such as '_', '-', '.', or a space.
+func wordBoundaries(s string) []bool {
+	out := make([]bool, 0, utf8.RuneCountInString(s))
+	prev := rune(0)
+	for i, r := range []rune(s) {
+		switch {
+		case i == 0:
+			out = append(out, true)
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			out = append(out, true)
+		case prev == '_' || prev == '-' || prev == '.' || prev == ' ':
+			out = append(out, true)
+		default:
+			out = append(out, false)
+		}
+		prev = r
+	}
+	return out
+}
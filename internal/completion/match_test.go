@@ -0,0 +1,102 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch_Tiers(t *testing.T) {
+	prefix, prefixPos, ok := Match("status", "stat", MatchFuzzy)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1, 2, 3}, prefixPos)
+
+	sub, subPos, ok := Match("podStatus", "stat", MatchFuzzy)
+	assert.True(t, ok)
+	assert.Equal(t, []int{3, 4, 5, 6}, subPos)
+
+	fuzzy, fuzzyPos, ok := Match("startsWith", "stw", MatchFuzzy)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1, 6}, fuzzyPos)
+
+	assert.Greater(t, prefix, sub)
+	assert.Greater(t, sub, fuzzy)
+}
+
+func TestMatch_PrefixMode(t *testing.T) {
+	_, _, ok := Match("podStatus", "stat", MatchPrefix)
+	assert.False(t, ok)
+
+	_, pos, ok := Match("Status", "stat", MatchPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1, 2, 3}, pos)
+}
+
+func TestMatch_NoMatch(t *testing.T) {
+	_, _, ok := Match("name", "xyz", MatchFuzzy)
+	assert.False(t, ok)
+	_, _, ok = Match("ab", "abc", MatchFuzzy)
+	assert.False(t, ok)
+}
+
+func TestMatch_EmptyPattern(t *testing.T) {
+	score, pos, ok := Match("anything", "", MatchPrefix)
+	assert.True(t, ok)
+	assert.Zero(t, score)
+	assert.Nil(t, pos)
+}
+
+func TestMatch_PrefersWordBoundary(t *testing.T) {
+	// "size" appears mid-word in "resized" and on a boundary in "max_size".
+	boundary, _, _ := Match("max_size", "size", MatchFuzzy)
+	mid, _, _ := Match("resized", "size", MatchFuzzy)
+	assert.Greater(t, boundary, mid)
+}
+
+func TestMatch_ShorterPrefixWins(t *testing.T) {
+	exact, _, _ := Match("size", "size", MatchFuzzy)
+	longer, _, _ := Match("sizeOf", "size", MatchFuzzy)
+	assert.Greater(t, exact, longer)
+}
+
+func TestMatch_PositionsIndexOriginalRunes(t *testing.T) {
+	// strings.ToLower turns "İ" into two runes; positions must still
+	// index the candidate as given.
+	_, pos, ok := Match("İstanbul", "bul", MatchFuzzy)
+	require.True(t, ok)
+	assert.Equal(t, []int{5, 6, 7}, pos)
+
+	_, pos, ok = Match("İstanbul", "ib", MatchFuzzy)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 5}, pos)
+
+	mark := func(s string) string { return "[" + s + "]" }
+	assert.Equal(t, "İstan[bul]", HighlightMatches("İstanbul", []int{5, 6, 7}, mark, nil))
+}
+
+func TestParseMatchMode(t *testing.T) {
+	for in, want := range map[string]MatchMode{
+		"prefix":   MatchPrefix,
+		" Strict ": MatchPrefix,
+		"fuzzy":    MatchFuzzy,
+		"":         MatchFuzzy,
+	} {
+		got, err := ParseMatchMode(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseMatchMode("exact")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be fuzzy or prefix")
+	assert.Equal(t, "prefix", MatchPrefix.String())
+	assert.Equal(t, "fuzzy", MatchFuzzy.String())
+}
+
+func TestHighlightMatches(t *testing.T) {
+	mark := func(s string) string { return "[" + s + "]" }
+	assert.Equal(t, "pod[Stat]us", HighlightMatches("podStatus", []int{3, 4, 5, 6}, mark, nil))
+	assert.Equal(t, "[s]ta[r]ts", HighlightMatches("starts", []int{0, 3}, mark, nil))
+	assert.Equal(t, "<plain>", HighlightMatches("plain", nil, mark, func(s string) string { return "<" + s + ">" }))
+	assert.Equal(t, "plain", HighlightMatches("plain", []int{0}, nil, nil))
+}
@@ -0,0 +1,180 @@
+package completion
+
+import (
+	"strings"
+)
+
+// SignatureHelp describes the function call the cursor is currently inside of.
+type SignatureHelp struct {
+	Function FunctionMetadata // Metadata for the function being called
+	Params   []string         // Parameter names (or types when names are unknown)
+	ArgIndex int              // Zero-based index of the argument under the cursor
+}
+
+// ActiveCall finds the innermost unclosed function call in input and returns
+// the function name and the zero-based index of the argument being typed.
+// Parentheses inside string literals and nested (), [], {} groups are skipped,
+// so the hint stays on the outer call until its closing paren is typed.
+func ActiveCall(input string) (name string, argIndex int, ok bool) {
+	type frame struct {
+		open  byte
+		name  string
+		comma int
+	}
+	var stack []frame
+	var quote byte
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(':
+			stack = append(stack, frame{open: c, name: identBefore(input[:i])})
+		case '[', '{':
+			stack = append(stack, frame{open: c})
+		case ')', ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].comma++
+			}
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		f := stack[i]
+		if f.open != '(' {
+			// Inside a list or map literal: the enclosing call is still
+			// the relevant one, but its comma count is unaffected.
+			continue
+		}
+		if f.name == "" {
+			// Plain grouping parens, keep looking outward.
+			continue
+		}
+		return f.name, f.comma, true
+	}
+	return "", 0, false
+}
+
+// identBefore returns the identifier immediately preceding s's end,
+// ignoring trailing whitespace (e.g. "_.items.filter" -> "filter").
+func identBefore(s string) string {
+	s = strings.TrimRight(s, " \t")
+	end := len(s)
+	start := end
+	for start > 0 && isIdentByte(s[start-1]) {
+		start--
+	}
+	if start == end || (s[start] >= '0' && s[start] <= '9') {
+		return ""
+	}
+	return s[start:end]
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ParamNames extracts parameter names for fn from its signature, falling back
+// to the description and examples (e.g. "Method: list.filter(x, condition)").
+// Returns nil if no parameter list can be found.
+func ParamNames(fn FunctionMetadata) []string {
+	sources := []string{fn.Signature, fn.Description}
+	for _, src := range sources {
+		if params, ok := paramsFrom(src, fn.Name); ok {
+			return params
+		}
+	}
+	if len(fn.ParamTypes) > 0 {
+		return append([]string(nil), fn.ParamTypes...)
+	}
+	return nil
+}
+
+// paramsFrom finds "name(" in text and splits the balanced argument list.
+// Empty argument lists are skipped so a bare "name()" signature does not hide
+// a richer description.
+func paramsFrom(text, name string) ([]string, bool) {
+	if text == "" || name == "" {
+		return nil, false
+	}
+	for off := 0; off < len(text); {
+		idx := strings.Index(text[off:], name+"(")
+		if idx < 0 {
+			return nil, false
+		}
+		idx += off
+		// Require a word boundary so "map(" does not match inside "flatMap(".
+		if idx > 0 && isIdentByte(text[idx-1]) {
+			off = idx + len(name)
+			continue
+		}
+		open := idx + len(name)
+		depth := 0
+		for j := open; j < len(text); j++ {
+			switch text[j] {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth--
+			}
+			if depth == 0 {
+				inner := strings.TrimSpace(text[open+1 : j])
+				if inner == "" {
+					break
+				}
+				return splitTopLevel(inner), true
+			}
+		}
+		off = open + 1
+	}
+	return nil, false
+}
+
+// splitTopLevel splits s on commas that are not nested inside brackets.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// FormatSignatureHelp renders a call hint such as "filter(x, condition)" with
+// the active parameter passed through highlight. When the cursor is past the
+// last known parameter (variadic calls), the last parameter stays highlighted.
+func FormatSignatureHelp(h SignatureHelp, highlight func(string) string) string {
+	params := make([]string, len(h.Params))
+	copy(params, h.Params)
+	active := h.ArgIndex
+	if active >= len(params) {
+		active = len(params) - 1
+	}
+	if active >= 0 && highlight != nil {
+		params[active] = highlight(params[active])
+	}
+	return h.Function.Name + "(" + strings.Join(params, ", ") + ")"
+}
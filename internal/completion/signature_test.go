@@ -0,0 +1,90 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveCall(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+		arg   int
+		ok    bool
+	}{
+		{"_.items.filter(", "filter", 0, true},
+		{"_.items.filter(x, ", "filter", 1, true},
+		{"_.items.filter(x, x.tags.exists(t, ", "exists", 1, true},
+		{"_.items.filter(x, x.tags.exists(t, t == 'a')", "filter", 1, true},
+		{"_.items.filter(x, x.name in ['a', 'b'", "filter", 1, true},
+		{"_.items.filter(x, x.name == 'a,(b'", "filter", 1, true},
+		{"_.items.filter(x, x > 1)", "", 0, false},
+		{"_.items.size()", "", 0, false},
+		{"(_.a + ", "", 0, false},
+		{"size(", "size", 0, true},
+	}
+	for _, tt := range tests {
+		name, arg, ok := ActiveCall(tt.input)
+		assert.Equal(t, tt.ok, ok, tt.input)
+		assert.Equal(t, tt.name, name, tt.input)
+		assert.Equal(t, tt.arg, arg, tt.input)
+	}
+}
+
+func TestParamNames(t *testing.T) {
+	fromDesc := FunctionMetadata{
+		Name:        "filter",
+		Signature:   "filter()",
+		Description: "Method: list.filter(x, condition). Filter array elements based on a condition.",
+	}
+	assert.Equal(t, []string{"x", "condition"}, ParamNames(fromDesc))
+
+	fromSig := FunctionMetadata{Name: "contains", Signature: "string.contains(string) -> bool"}
+	assert.Equal(t, []string{"string"}, ParamNames(fromSig))
+
+	// "map(" inside "flatMap(" must not match.
+	boundary := FunctionMetadata{Name: "map", Description: "see flatMap(a, b) or list.map(x, expr)"}
+	assert.Equal(t, []string{"x", "expr"}, ParamNames(boundary))
+
+	nested := FunctionMetadata{Name: "f", Signature: "f(list(int), map(string, int))"}
+	assert.Equal(t, []string{"list(int)", "map(string, int)"}, ParamNames(nested))
+
+	assert.Nil(t, ParamNames(FunctionMetadata{Name: "size", Signature: "size()"}))
+	assert.Equal(t, []string{"int"}, ParamNames(FunctionMetadata{Name: "g", ParamTypes: []string{"int"}}))
+}
+
+func TestFormatSignatureHelp(t *testing.T) {
+	h := SignatureHelp{
+		Function: FunctionMetadata{Name: "filter"},
+		Params:   []string{"x", "condition"},
+		ArgIndex: 1,
+	}
+	mark := func(s string) string { return "[" + s + "]" }
+	assert.Equal(t, "filter(x, [condition])", FormatSignatureHelp(h, mark))
+	assert.Equal(t, "filter(x, condition)", FormatSignatureHelp(h, nil))
+
+	// Extra arguments keep the last parameter highlighted.
+	h.ArgIndex = 5
+	assert.Equal(t, "filter(x, [condition])", FormatSignatureHelp(h, mark))
+}
+
+func TestEngineSignatureHelp(t *testing.T) {
+	e := NewEngine(nil)
+	e.GetRegistry().LoadFunctions([]FunctionMetadata{{
+		Name:        "filter",
+		Description: "Method: list.filter(x, condition). Filter array elements.",
+		IsMethod:    true,
+	}})
+
+	sig, ok := e.SignatureHelp("_.items.filter(x, ")
+	assert.True(t, ok)
+	assert.Equal(t, "filter", sig.Function.Name)
+	assert.Equal(t, []string{"x", "condition"}, sig.Params)
+	assert.Equal(t, 1, sig.ArgIndex)
+
+	_, ok = e.SignatureHelp("_.items.filter(x, x > 1)")
+	assert.False(t, ok)
+	_, ok = e.SignatureHelp("_.items.unknown(")
+	assert.False(t, ok)
+}
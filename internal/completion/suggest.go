@@ -0,0 +1,56 @@
+package completion
+
+import (
+	"errors"
+	"strconv"
+
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
+)
+
+// MissingKey returns the missing-key error in err's chain, as produced by
+// CEL evaluation or by simple path navigation. Its Key names the missing
+// key; Pos and Parent locate it in the expression when known.
+func MissingKey(err error) (*celhelper.ExprError, bool) {
+	var exprErr *celhelper.ExprError
+	if !errors.As(err, &exprErr) || exprErr.Kind != celhelper.KindNoSuchKey || exprErr.Key == "" {
+		return nil, false
+	}
+	return exprErr, true
+}
+
+// LocateKey finds the last reference to key in expr, either as ".key" (or a
+// bare leading "key") or as a quoted index ["key"]. It returns the rune offset
+// of the key's first character, or -1 when not found, and the expression for
+// the object the key was looked up on (e.g. "_.spec" for "_.spec.nmae").
+func LocateKey(expr, key string) (int, string) {
+	runes := []rune(expr)
+	best, parentEnd := -1, -1
+	for _, tok := range Tokenize(expr) {
+		switch tok.Kind {
+		case TokenIdent:
+			if tok.Text != key {
+				continue
+			}
+		case TokenString:
+			if unq, err := strconv.Unquote(tok.Text); err != nil || unq != key {
+				continue
+			}
+		default:
+			continue
+		}
+		start := tok.Start
+		switch {
+		case tok.Kind == TokenIdent && start > 0 && runes[start-1] == '.':
+			best, parentEnd = start, start-1
+		case tok.Kind == TokenIdent && start == 0:
+			best, parentEnd = start, 0
+		case tok.Kind == TokenString && start > 0 && runes[start-1] == '[':
+			// Point inside the quotes at the key itself.
+			best, parentEnd = start+1, start-1
+		}
+	}
+	if best < 0 {
+		return -1, ""
+	}
+	return best, string(runes[:parentEnd])
+}
@@ -0,0 +1,56 @@
+package completion
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
+)
+
+func TestMissingKey(t *testing.T) {
+	root := map[string]interface{}{"spec": map[string]interface{}{"name": "x"}}
+	eval, err := celhelper.NewEvaluator()
+	require.NoError(t, err)
+
+	_, evalErr := eval.Evaluate("_.spec.nmae", root)
+	missing, ok := MissingKey(fmt.Errorf("wrapped: %w", evalErr))
+	require.True(t, ok)
+	assert.Equal(t, "nmae", missing.Key)
+	assert.Equal(t, "_.spec", missing.Parent)
+	assert.Equal(t, 7, missing.Pos)
+
+	_, evalErr = eval.Evaluate(`_["bad-key"]`, root)
+	missing, ok = MissingKey(evalErr)
+	require.True(t, ok)
+	assert.Equal(t, "bad-key", missing.Key)
+
+	// The text of an error is not enough.
+	_, ok = MissingKey(errors.New("no such key: nmae"))
+	assert.False(t, ok)
+	_, evalErr = eval.Evaluate("_.spec.name / 0", root)
+	_, ok = MissingKey(evalErr)
+	assert.False(t, ok)
+	_, ok = MissingKey(nil)
+	assert.False(t, ok)
+}
+
+func TestLocateKey(t *testing.T) {
+	pos, parent := LocateKey("_.spec.nmae", "nmae")
+	assert.Equal(t, 7, pos)
+	assert.Equal(t, "_.spec", parent)
+
+	pos, parent = LocateKey(`_.labels["tire"]`, "tire")
+	assert.Equal(t, 10, pos)
+	assert.Equal(t, "_.labels", parent)
+
+	pos, parent = LocateKey("nmae", "nmae")
+	assert.Equal(t, 0, pos)
+	assert.Equal(t, "", parent)
+
+	pos, _ = LocateKey("_.name", "missing")
+	assert.Equal(t, -1, pos)
+}
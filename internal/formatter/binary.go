@@ -0,0 +1,119 @@
+package formatter
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// binaryPreviewBytes is how many leading bytes the placeholder shows.
+	binaryPreviewBytes = 8
+	// maxHexdumpBytes caps the bytes rendered by Hexdump.
+	maxHexdumpBytes = 4096
+)
+
+// BinaryBytes reports whether v holds binary data and returns its bytes.
+// []byte values are always binary; strings are binary only when they are not
+// valid UTF-8 (e.g. YAML !!binary or raw file content). Valid text is never
+// binary, even with control characters such as NUL, so JSON "\u0000" stays
+// a string.
+func BinaryBytes(v any) ([]byte, bool) {
+	switch t := v.(type) {
+	case []byte:
+		return t, true
+	case string:
+		if !utf8.ValidString(t) {
+			return []byte(t), true
+		}
+	}
+	return nil, false
+}
+
+// BinaryPlaceholder describes binary data in one line: its size and first
+// bytes in hex, e.g. "<binary 4.0 KiB: 89 50 4e 47 0d 0a 1a 0a ...>". It is
+// plain ASCII so it can be measured and truncated like any cell.
+func BinaryPlaceholder(b []byte) string {
+	preview := b
+	more := ""
+	if len(preview) > binaryPreviewBytes {
+		preview = preview[:binaryPreviewBytes]
+		more = " ..."
+	}
+	if len(preview) == 0 {
+		return "<binary 0 bytes>"
+	}
+	return fmt.Sprintf("<binary %s: % x%s>", byteCount(len(b)), preview, more)
+}
+
+// Hexdump renders binary data as offset/hex/ASCII lines (like hexdump -C),
+// truncated to the first 4 KiB.
+func Hexdump(b []byte) string {
+	if len(b) <= maxHexdumpBytes {
+		return hex.Dump(b)
+	}
+	return hex.Dump(b[:maxHexdumpBytes]) + fmt.Sprintf("... %d more bytes\n", len(b)-maxHexdumpBytes)
+}
+
+func byteCount(n int) string {
+	switch {
+	case n == 1:
+		return "1 byte"
+	case n < 1024:
+		return fmt.Sprintf("%d bytes", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KiB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1024*1024))
+	}
+}
+
+// containsBinary reports whether v or any value nested in it is binary.
+func containsBinary(v any) bool {
+	if _, ok := BinaryBytes(v); ok {
+		return true
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for _, e := range t {
+			if containsBinary(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range t {
+			if containsBinary(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeBinary returns a copy of v with binary values replaced by their
+// base64 encoding, for formats without a binary type. v is returned as-is
+// when it holds no binary data.
+func encodeBinary(v any) any {
+	if !containsBinary(v) {
+		return v
+	}
+	if b, ok := BinaryBytes(v); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[k] = encodeBinary(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = encodeBinary(e)
+		}
+		return out
+	}
+	return v
+}
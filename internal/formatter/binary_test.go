@@ -0,0 +1,78 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0x0d}
+
+func TestBinaryBytes(t *testing.T) {
+	b, ok := BinaryBytes(pngHeader)
+	assert.True(t, ok)
+	assert.Equal(t, pngHeader, b)
+
+	_, ok = BinaryBytes(string(pngHeader))
+	assert.True(t, ok, "invalid UTF-8 string")
+	_, ok = BinaryBytes("a\x00b")
+	assert.False(t, ok, "valid UTF-8 with NUL is text")
+
+	_, ok = BinaryBytes("héllo\n\tworld")
+	assert.False(t, ok)
+	_, ok = BinaryBytes(42)
+	assert.False(t, ok)
+}
+
+func TestBinaryPlaceholder(t *testing.T) {
+	assert.Equal(t, "<binary 12 bytes: 89 50 4e 47 0d 0a 1a 0a ...>", BinaryPlaceholder(pngHeader))
+	assert.Equal(t, "<binary 1 byte: ff>", BinaryPlaceholder([]byte{0xff}))
+	assert.Equal(t, "<binary 0 bytes>", BinaryPlaceholder(nil))
+	assert.Equal(t, "<binary 2.0 KiB: 00 00 00 00 00 00 00 00 ...>", BinaryPlaceholder(make([]byte, 2048)))
+}
+
+func TestHexdump(t *testing.T) {
+	out := Hexdump(pngHeader)
+	assert.True(t, strings.HasPrefix(out, "00000000  89 50 4e 47 0d 0a 1a 0a  00 00 00 0d"), out)
+	assert.Contains(t, out, "|.PNG........|")
+
+	big := Hexdump(make([]byte, maxHexdumpBytes+10))
+	assert.True(t, strings.HasSuffix(big, "... 10 more bytes\n"), big[len(big)-40:])
+}
+
+func TestStringify_Binary(t *testing.T) {
+	assert.Equal(t, BinaryPlaceholder(pngHeader), Stringify(pngHeader))
+	assert.Equal(t, BinaryPlaceholder(pngHeader), Stringify(string(pngHeader)))
+}
+
+func TestStructuredOutputs_Base64(t *testing.T) {
+	data := map[string]any{"name": "logo", "data": string(pngHeader)}
+
+	js, err := FormatJSON(data, SerializeOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, js, `"data": "iVBORw0KGgoAAAAN"`)
+
+	y, err := FormatYAML(data, YAMLFormatOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, y, "data: !!binary iVBORw0KGgoAAAAN")
+
+	csv := FormatAsCSV([]any{data})
+	assert.Contains(t, csv, "iVBORw0KGgoAAAAN,logo")
+
+	// The input is not modified.
+	assert.Equal(t, string(pngHeader), data["data"])
+}
+
+func TestStructuredOutputs_TextWithNUL(t *testing.T) {
+	data := map[string]any{"s": "a\x00b"}
+
+	js, err := FormatJSON(data, SerializeOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, js, `"s": "a\u0000b"`)
+
+	y, err := FormatYAML(data, YAMLFormatOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, y, "!!binary")
+}
@@ -0,0 +1,41 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ChunkArray returns the first size items of an array holding more than
+// threshold items, and how many items it left out. Other nodes, and arrays
+// of at most threshold or size items, come back whole with 0. A size of 0
+// turns chunking off.
+func ChunkArray(node any, size, threshold int) (any, int) {
+	arr, ok := node.([]any)
+	if !ok || size <= 0 || len(arr) <= threshold || len(arr) <= size {
+		return node, 0
+	}
+	return arr[:size:size], len(arr) - size
+}
+
+// MoreRowsLine is the sentinel row shown under a table holding the first
+// items of a longer array, such as "… 9,500 more".
+func MoreRowsLine(more int) string {
+	return "… " + GroupDigits(more) + " more"
+}
+
+// GroupDigits writes n with commas between groups of three digits.
+func GroupDigits(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String()
+}
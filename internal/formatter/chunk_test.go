@@ -0,0 +1,60 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkArray(t *testing.T) {
+	arr := make([]any, 10)
+	for i := range arr {
+		arr[i] = i
+	}
+
+	chunk, more := ChunkArray(arr, 3, 5)
+	assert.Equal(t, []any{0, 1, 2}, chunk)
+	assert.Equal(t, 7, more)
+
+	chunk, more = ChunkArray(arr, 3, 10)
+	assert.Len(t, chunk, 10, "arrays within the threshold stay whole")
+	assert.Zero(t, more)
+
+	chunk, more = ChunkArray(arr, 0, 5)
+	assert.Len(t, chunk, 10, "a size of 0 turns chunking off")
+	assert.Zero(t, more)
+
+	chunk, more = ChunkArray(arr, 20, 5)
+	assert.Len(t, chunk, 10)
+	assert.Zero(t, more)
+
+	m := map[string]any{"a": 1}
+	chunk, more = ChunkArray(m, 1, 0)
+	assert.Equal(t, m, chunk)
+	assert.Zero(t, more)
+}
+
+func TestChunkArrayDoesNotShareCapacity(t *testing.T) {
+	arr := []any{1, 2, 3, 4}
+	chunk, _ := ChunkArray(arr, 2, 2)
+	_ = append(chunk.([]any), 99) //nolint:forcetypeassert
+	assert.Equal(t, 3, arr[2])
+}
+
+func TestMoreRowsLine(t *testing.T) {
+	assert.Equal(t, "… 9,500 more", MoreRowsLine(9500))
+	assert.Equal(t, "… 12 more", MoreRowsLine(12))
+}
+
+func TestGroupDigits(t *testing.T) {
+	for n, want := range map[int]string{
+		0:        "0",
+		999:      "999",
+		1000:     "1,000",
+		123456:   "123,456",
+		1234567:  "1,234,567",
+		-1234567: "-1,234,567",
+	} {
+		assert.Equal(t, want, GroupDigits(n))
+	}
+}
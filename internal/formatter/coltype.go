@@ -0,0 +1,200 @@
+package formatter
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// ColumnType is the type inferred for the cells of a table column.
+type ColumnType string
+
+// Column types, from the most to the least specific.
+const (
+	ColumnTypeInt    ColumnType = "int"
+	ColumnTypeFloat  ColumnType = "float"
+	ColumnTypeBool   ColumnType = "bool"
+	ColumnTypeDate   ColumnType = "date"
+	ColumnTypeString ColumnType = "string"
+)
+
+// dateLayouts are the date formats recognized in cells, tried in order.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Numeric reports whether values of t are numbers.
+func (t ColumnType) Numeric() bool {
+	return t == ColumnTypeInt || t == ColumnTypeFloat
+}
+
+// InferColumnType returns the type every non-empty cell parses as, or
+// ColumnTypeString when they differ. Ints count as floats when mixed.
+// Empty and "null" cells are skipped; a column of them is a string column.
+func InferColumnType(cells []string) ColumnType {
+	var t ColumnType
+	for _, c := range cells {
+		if c == "" || c == "null" {
+			continue
+		}
+		ct := cellType(c)
+		switch {
+		case t == "" || t == ct:
+			t = ct
+		case t.Numeric() && ct.Numeric():
+			t = ColumnTypeFloat
+		default:
+			return ColumnTypeString
+		}
+	}
+	if t == "" {
+		return ColumnTypeString
+	}
+	return t
+}
+
+// InferColumnTypes returns the type of each of the n columns of rows.
+func InferColumnTypes(rows [][]string, n int) []ColumnType {
+	types := make([]ColumnType, n)
+	for i := range types {
+		types[i] = InferColumnType(columnCells(rows, i))
+	}
+	return types
+}
+
+func cellType(c string) ColumnType {
+	if _, err := strconv.ParseInt(c, 10, 64); err == nil {
+		return ColumnTypeInt
+	}
+	if _, ok := parseFloatCell(c); ok {
+		return ColumnTypeFloat
+	}
+	if c == "true" || c == "false" {
+		return ColumnTypeBool
+	}
+	if _, ok := parseDateCell(c); ok {
+		return ColumnTypeDate
+	}
+	return ColumnTypeString
+}
+
+// parseFloatCell parses decimal numbers, leaving out the "inf" and "nan"
+// spellings strconv accepts, which are words in a table.
+func parseFloatCell(c string) (float64, bool) {
+	if c == "" || strings.ContainsAny(c, "iInN") {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(c, 64)
+	return f, err == nil
+}
+
+func parseDateCell(c string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, c); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CompareCells compares two cells of a column of type t: numbers by value,
+// dates by time, false before true, and text in natural order. Cells that
+// do not parse as t sort before those that do, in natural order.
+func CompareCells(a, b string, t ColumnType) int {
+	switch t {
+	case ColumnTypeInt, ColumnTypeFloat:
+		fa, okA := parseFloatCell(a)
+		fb, okB := parseFloatCell(b)
+		if okA && okB {
+			return cmp.Compare(fa, fb)
+		}
+		return compareParsed(okA, okB, a, b)
+	case ColumnTypeDate:
+		ta, okA := parseDateCell(a)
+		tb, okB := parseDateCell(b)
+		if okA && okB {
+			return ta.Compare(tb)
+		}
+		return compareParsed(okA, okB, a, b)
+	}
+	return keyorder.CompareNatural(a, b)
+}
+
+func compareParsed(okA, okB bool, a, b string) int {
+	switch {
+	case okA:
+		return 1
+	case okB:
+		return -1
+	}
+	return keyorder.CompareNatural(a, b)
+}
+
+// columnCells returns the cells of column col of rows.
+func columnCells(rows [][]string, col int) []string {
+	cells := make([]string, len(rows))
+	for i, row := range rows {
+		if col < len(row) {
+			cells[i] = row[col]
+		}
+	}
+	return cells
+}
+
+// SortRows stably sorts rows by their cells in column col, compared as t.
+func SortRows(rows [][]string, col int, t ColumnType, desc bool) {
+	cell := func(row []string) string {
+		if col < len(row) {
+			return row[col]
+		}
+		return ""
+	}
+	slices.SortStableFunc(rows, func(a, b []string) int {
+		c := CompareCells(cell(a), cell(b), t)
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// ColumnStats summarizes the cells of a table column.
+type ColumnStats struct {
+	Type     ColumnType // Type inferred for the column (see InferColumnType)
+	Count    int        // Cells in the column
+	Distinct int        // Distinct non-null cells
+	Nulls    int        // Empty and "null" cells
+	Min, Max string     // Smallest and largest non-null cells, compared as Type
+}
+
+// ComputeColumnStats returns the stats of a column's cells. Empty and
+// "null" cells count as nulls, as InferColumnType skips them.
+func ComputeColumnStats(cells []string) ColumnStats {
+	s := ColumnStats{Type: InferColumnType(cells), Count: len(cells)}
+	seen := make(map[string]bool, len(cells))
+	for _, c := range cells {
+		if c == "" || c == "null" {
+			s.Nulls++
+			continue
+		}
+		if seen[c] {
+			continue
+		}
+		if len(seen) == 0 || CompareCells(c, s.Min, s.Type) < 0 {
+			s.Min = c
+		}
+		if len(seen) == 0 || CompareCells(c, s.Max, s.Type) > 0 {
+			s.Max = c
+		}
+		seen[c] = true
+	}
+	s.Distinct = len(seen)
+	return s
+}
@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferColumnType(t *testing.T) {
+	for want, cells := range map[ColumnType][]string{
+		ColumnTypeInt:    {"1", "-20", "", "null"},
+		ColumnTypeFloat:  {"1", "2.5", "1e3"},
+		ColumnTypeBool:   {"true", "false"},
+		ColumnTypeDate:   {"2024-01-15", "2024-03-01T10:00:00Z"},
+		ColumnTypeString: {"1", "two"},
+	} {
+		assert.Equal(t, want, InferColumnType(cells), "%v", cells)
+	}
+	assert.Equal(t, ColumnTypeString, InferColumnType([]string{"", "null"}))
+	assert.Equal(t, ColumnTypeString, InferColumnType([]string{"inf", "nan"}), "inf and nan are words")
+}
+
+func TestInferColumnTypes(t *testing.T) {
+	rows := [][]string{{"a", "1"}, {"b", "2.5"}, {"c"}}
+	assert.Equal(t, []ColumnType{ColumnTypeString, ColumnTypeFloat}, InferColumnTypes(rows, 2))
+}
+
+func TestSortRows(t *testing.T) {
+	rows := [][]string{{"10"}, {"2"}, {"n/a"}, {"1.5"}}
+	SortRows(rows, 0, ColumnTypeFloat, false)
+	assert.Equal(t, [][]string{{"n/a"}, {"1.5"}, {"2"}, {"10"}}, rows)
+
+	rows = [][]string{{"2024-01-15"}, {"2023-12-25T08:00:00Z"}, {"2024-03-01"}}
+	SortRows(rows, 0, ColumnTypeDate, true)
+	assert.Equal(t, [][]string{{"2024-03-01"}, {"2024-01-15"}, {"2023-12-25T08:00:00Z"}}, rows)
+
+	rows = [][]string{{"b", "1"}, {"a"}, {"c", "0"}}
+	SortRows(rows, 1, ColumnTypeInt, false)
+	assert.Equal(t, [][]string{{"a"}, {"c", "0"}, {"b", "1"}}, rows, "short rows have an empty cell")
+}
+
+func TestComputeColumnStats(t *testing.T) {
+	s := ComputeColumnStats([]string{"10", "9", "", "120", "9", "null"})
+	assert.Equal(t, ColumnStats{Type: ColumnTypeInt, Count: 6, Distinct: 3, Nulls: 2, Min: "9", Max: "120"}, s)
+
+	s = ComputeColumnStats([]string{"beta", "item10", "item2", "alpha"})
+	assert.Equal(t, ColumnTypeString, s.Type)
+	assert.Equal(t, "alpha", s.Min)
+	assert.Equal(t, "item10", s.Max)
+
+	s = ComputeColumnStats([]string{"", "null"})
+	assert.Equal(t, ColumnStats{Type: ColumnTypeString, Count: 2, Nulls: 2}, s)
+}
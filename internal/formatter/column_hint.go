@@ -1,5 +1,11 @@
 package formatter
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // ColumnHint provides display hints for a specific column in columnar table rendering.
 // This is the internal (formatter-level) representation; the public API exposes
 // [tui.ColumnHint] which maps to this type.
@@ -29,4 +35,133 @@ type ColumnHint struct {
 	// initial sizing, not a cap on expansion.
 	// If no column is marked Flex, columns keep their natural width.
 	Flex bool
+
+	// Wrap wraps this field's value onto continuation lines in key-value
+	// tables instead of truncating it (see SetWrapKeys).
+	Wrap bool
+
+	// Enum lists the values the column allows. Cells holding any other
+	// value are drawn in the warning color. Values are compared by their
+	// displayed text, so the number 1 and the string "1" are the same.
+	Enum []any
+
+	// States maps the column's cells to semantic states, which color
+	// them (see StateStyle). Cells outside Enum keep the warning color.
+	States *ValueStates
+}
+
+// AllowsText reports whether a cell's text is one of the hint's Enum
+// values. Empty cells and columns without an enum always pass.
+func (h ColumnHint) AllowsText(text string) bool {
+	if len(h.Enum) == 0 || text == "" {
+		return true
+	}
+	for _, e := range h.Enum {
+		if Stringify(e) == text {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows is AllowsText for a value; null values always pass.
+func (h ColumnHint) Allows(v any) bool {
+	if v == nil {
+		return true
+	}
+	return h.AllowsText(Stringify(v))
+}
+
+// HasEnumHints reports whether any hint declares Enum values.
+func HasEnumHints(hints map[string]ColumnHint) bool {
+	for _, h := range hints {
+		if len(h.Enum) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidFields returns the sorted names of the fields of obj whose values
+// are outside their hint's Enum.
+func InvalidFields(obj map[string]any, hints map[string]ColumnHint) []string {
+	var fields []string
+	for name, h := range hints {
+		if v, ok := obj[name]; ok && !h.Allows(v) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// EnumReport describes the rows of an array whose fields hold values
+// outside their hint's Enum, as "2 of 5 rows have values outside the schema
+// enum: status (2), tier (1)". It is empty when every value is allowed or
+// data is not an array of objects.
+func EnumReport(data any, hints map[string]ColumnHint) string {
+	items, ok := data.([]any)
+	if !ok || !HasEnumHints(hints) {
+		return ""
+	}
+	counts := map[string]int{}
+	invalidRows := 0
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		fields := InvalidFields(obj, hints)
+		if len(fields) > 0 {
+			invalidRows++
+		}
+		for _, f := range fields {
+			counts[f]++
+		}
+	}
+	if invalidRows == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	verb := "have"
+	if invalidRows == 1 {
+		verb = "has"
+	}
+	return fmt.Sprintf("%d of %d rows %s values outside the schema enum: %s", invalidRows, len(items), verb, strings.Join(parts, ", "))
+}
+
+// InvalidRows returns the elements of an array of objects with at least one
+// field outside its hint's Enum. Other data is returned unchanged.
+func InvalidRows(data any, hints map[string]ColumnHint) any {
+	items, ok := data.([]any)
+	if !ok {
+		return data
+	}
+	out := []any{}
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok && len(InvalidFields(obj, hints)) > 0 {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// WrapKeysFromHints returns the sorted names of the hints with Wrap set.
+func WrapKeysFromHints(hints map[string]ColumnHint) []string {
+	var keys []string
+	for name, h := range hints {
+		if h.Wrap {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
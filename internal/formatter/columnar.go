@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -32,6 +33,28 @@ type TableFormatOptions struct {
 	// ColumnHints provides per-column display hints derived from a JSON Schema.
 	// Keys are the original JSON field names.
 	ColumnHints map[string]ColumnHint
+
+	// Summary maps column names to an aggregate shown in a footer row under
+	// columnar tables: count, sum, avg, min, max, or a CEL expression over
+	// the rendered array '_'. See SummaryRow.
+	Summary map[string]string
+
+	// WidthPercentile sizes columnar table columns to this percentile of
+	// their value widths (e.g. 90) instead of the longest value. 0 = longest.
+	WidthPercentile int
+
+	// ChunkSize cuts arrays of more than ChunkThreshold items to their first
+	// ChunkSize items, with a sentinel row counting the rest. 0 = whole arrays.
+	ChunkSize      int
+	ChunkThreshold int
+
+	// TypeInference right-aligns numeric columns and sorts SortBy by the
+	// type inferred for its cells. See ColumnarOptions.TypeInference.
+	TypeInference bool
+
+	// SortBy sorts columnar table rows by this column; SortDesc reverses it.
+	SortBy   string
+	SortDesc bool
 }
 
 // DefaultTableFormatOptions returns sensible defaults for table formatting.
@@ -82,6 +105,13 @@ func CalculateNaturalColumnarWidth(columns []string, rows [][]string, showRowNum
 // CalculateNaturalColumnarWidthWithHints is like CalculateNaturalColumnarWidth but
 // accounts for hidden columns, display-name overrides, and MaxWidth caps from hints.
 func CalculateNaturalColumnarWidthWithHints(columns []string, rows [][]string, showRowNum bool, numRows int, hints map[string]ColumnHint, hiddenColumns []string) int {
+	return CalculateNaturalColumnarWidthWithPercentile(columns, rows, showRowNum, numRows, hints, hiddenColumns, 0)
+}
+
+// CalculateNaturalColumnarWidthWithPercentile is like
+// CalculateNaturalColumnarWidthWithHints but sizes each column to the given
+// percentile of its value widths (see ColumnarOptions.WidthPercentile).
+func CalculateNaturalColumnarWidthWithPercentile(columns []string, rows [][]string, showRowNum bool, numRows int, hints map[string]ColumnHint, hiddenColumns []string, percentile int) int {
 	if len(columns) == 0 {
 		return 0
 	}
@@ -109,16 +139,7 @@ func CalculateNaturalColumnarWidthWithHints(columns []string, rows [][]string, s
 		}
 		colWidths[i] = lipgloss.Width(header)
 	}
-	for _, row := range visRows {
-		for i, val := range row {
-			if i < len(colWidths) {
-				w := lipgloss.Width(val)
-				if w > colWidths[i] {
-					colWidths[i] = w
-				}
-			}
-		}
-	}
+	fitDataWidths(colWidths, visRows, percentile)
 
 	// Apply MaxWidth caps from hints
 	for i, col := range visCols {
@@ -166,21 +187,82 @@ type ColumnarOptions struct {
 	// ColumnHints provides per-column display hints for width, priority, and alignment.
 	// Keys are the original field names (before any display name remapping).
 	ColumnHints map[string]ColumnHint
+
+	// Summary, when non-nil, is a footer row with one cell per column (see
+	// SummaryRow), rendered below a separator under the data rows.
+	Summary []string
+
+	// WidthPercentile sizes each column to this percentile of its value
+	// widths instead of the longest value, so a few outliers are truncated
+	// with an ellipsis rather than starving the other columns. 0 (or 100
+	// and above) uses the longest value.
+	WidthPercentile int
+
+	// Filters, when non-nil, replaces the separator under the header with
+	// one cell of filter text per column (aligned with columns like
+	// Summary). The table is rendered even when no rows match.
+	Filters []string
+
+	// FilterFocus is the index in columns of the filter cell being edited,
+	// or -1 for none. It is marked with "›" and drawn in the header style.
+	FilterFocus int
+
+	// FocusedHeader is the name of the column whose header has the cursor,
+	// or "" for none. It is marked with "›" and drawn in reverse video.
+	FocusedHeader string
+
+	// TypeInference infers the type of each column from its cells (see
+	// InferColumnType): numeric columns without an Align hint are
+	// right-aligned, and SortBy compares cells as their column's type.
+	TypeInference bool
+
+	// SortBy sorts the rows by this column, in natural order or, with
+	// TypeInference, as the column's type; SortDesc reverses the order.
+	// Unknown columns leave the rows in input order.
+	SortBy   string
+	SortDesc bool
 }
 
 // RenderColumnarTable renders data as a multi-column table with field names as headers.
 // columns: the field names (column headers)
 // rows: the data rows (each row has values corresponding to columns)
 func RenderColumnarTable(columns []string, rows [][]string, opts ColumnarOptions) string {
-	if len(columns) == 0 || len(rows) == 0 {
+	if len(columns) == 0 || (len(rows) == 0 && opts.Filters == nil) {
 		return ""
 	}
 
-	// Filter hidden columns
-	visibleCols, visibleRows := filterColumns(columns, rows, opts.HiddenColumns)
+	if col := slices.Index(columns, opts.SortBy); opts.SortBy != "" && col >= 0 {
+		rows = slices.Clone(rows)
+		t := ColumnTypeString
+		if opts.TypeInference {
+			t = InferColumnType(columnCells(rows, col))
+		}
+		SortRows(rows, col, t, opts.SortDesc)
+	}
+
+	// Filter hidden columns, keeping the summary and filter rows aligned
+	// with the data
+	allRows := rows
+	if opts.Filters != nil {
+		allRows = append(rows[:len(rows):len(rows)], opts.Filters)
+	}
+	if opts.Summary != nil {
+		allRows = append(allRows[:len(allRows):len(allRows)], opts.Summary)
+	}
+	visibleCols, visibleRows := filterColumns(columns, allRows, opts.HiddenColumns)
 	if len(visibleCols) == 0 {
 		return ""
 	}
+	var summary []string
+	if opts.Summary != nil {
+		summary = visibleRows[len(visibleRows)-1]
+		visibleRows = visibleRows[:len(visibleRows)-1]
+	}
+	var filters []string
+	if opts.Filters != nil {
+		filters = visibleRows[len(visibleRows)-1]
+		visibleRows = visibleRows[:len(visibleRows)-1]
+	}
 
 	// Apply DisplayName overrides to visible columns for headers.
 	// Keep track of original names for hint lookup.
@@ -191,6 +273,14 @@ func RenderColumnarTable(columns []string, rows [][]string, opts ColumnarOptions
 			displayCols[i] = h.DisplayName
 		}
 	}
+	// The focused header's marker is sized like the rest of its name.
+	headerFocus := -1
+	if opts.FocusedHeader != "" {
+		headerFocus = slices.Index(visibleCols, opts.FocusedHeader)
+	}
+	if headerFocus >= 0 {
+		displayCols[headerFocus] = "›" + displayCols[headerFocus]
+	}
 
 	// Build per-column alignment lookup.
 	// visibleCols are original field names, matching ColumnHints keys.
@@ -202,6 +292,13 @@ func RenderColumnarTable(columns []string, rows [][]string, opts ColumnarOptions
 			}
 		}
 	}
+	if opts.TypeInference {
+		for i, t := range InferColumnTypes(visibleRows, len(visibleCols)) {
+			if colAligns[i] == "" && t.Numeric() {
+				colAligns[i] = "right"
+			}
+		}
+	}
 
 	// Determine total width
 	totalWidth := opts.TotalWidth
@@ -227,12 +324,17 @@ func RenderColumnarTable(columns []string, rows [][]string, opts ColumnarOptions
 	if showRowNum {
 		availableWidth -= sepWidth
 	}
-	colWidths := calculateColumnWidths(displayCols, visibleRows, availableWidth, resolveHints(visibleCols, opts))
+	widthRows := visibleRows
+	if summary != nil {
+		widthRows = append(visibleRows[:len(visibleRows):len(visibleRows)], summary)
+	}
+	hints := resolveHints(visibleCols, opts)
+	colWidths := calculateColumnWidths(displayCols, widthRows, availableWidth, hints, opts.WidthPercentile)
 
 	var b strings.Builder
 
 	// Render header
-	headerRow := renderHeader(displayCols, colWidths, sepWidth, rowNumWidth, showRowNum, opts.NoColor)
+	headerRow := renderHeader(displayCols, colWidths, sepWidth, rowNumWidth, showRowNum, opts.NoColor, headerFocus)
 	b.WriteString(headerRow + "\n")
 
 	// Separator line - width needs to match header width including row number separator
@@ -250,17 +352,111 @@ func RenderColumnarTable(columns []string, rows [][]string, opts ColumnarOptions
 	if !opts.NoColor {
 		separator = separatorStyle.Render(separator)
 	}
-	b.WriteString(separator + "\n")
+	if filters != nil {
+		focus := visibleIndex(columns, opts.HiddenColumns, opts.FilterFocus)
+		b.WriteString(renderFilterRow(filters, colWidths, sepWidth, rowNumWidth, showRowNum, opts.NoColor, focus) + "\n")
+	} else {
+		b.WriteString(separator + "\n")
+	}
 
 	// Render data rows
 	for i, row := range visibleRows {
-		rowStr := renderDataRow(i, row, colWidths, sepWidth, rowNumWidth, opts.RowNumberStyle, opts.NoColor, colAligns)
+		rowStr := renderDataRow(i, row, colWidths, sepWidth, rowNumWidth, opts.RowNumberStyle, opts.NoColor, colAligns, hints)
 		b.WriteString(rowStr + "\n")
 	}
 
+	if summary != nil {
+		b.WriteString(separator + "\n")
+		b.WriteString(renderSummaryRow(summary, colWidths, sepWidth, rowNumWidth, showRowNum, opts.NoColor, colAligns) + "\n")
+	}
+
 	return b.String()
 }
 
+// renderSummaryRow renders the footer row in the header style, labeled with
+// SummaryLabel in the row number column.
+func renderSummaryRow(values []string, widths []int, sepWidth, rowNumWidth int, showRowNum, noColor bool, colAligns []string) string {
+	parts := make([]string, 0, len(values)+1)
+	if showRowNum {
+		label := padRight(truncate(SummaryLabel, rowNumWidth), rowNumWidth)
+		if !noColor {
+			label = headerStyle.Render(label)
+		}
+		parts = append(parts, label)
+	}
+	for i, val := range values {
+		if i >= len(widths) {
+			break
+		}
+		w := widths[i]
+		var valStr string
+		if i < len(colAligns) && colAligns[i] == "right" {
+			valStr = padLeft(truncate(val, w), w)
+		} else {
+			valStr = padRight(truncate(val, w), w)
+		}
+		if !noColor {
+			valStr = headerStyle.Render(valStr)
+		}
+		parts = append(parts, valStr)
+	}
+	return strings.Join(parts, strings.Repeat(" ", sepWidth))
+}
+
+// renderFilterRow renders the per-column filter text in place of the
+// separator line: columns without a filter keep the separator rule.
+func renderFilterRow(values []string, widths []int, sepWidth, rowNumWidth int, showRowNum, noColor bool, focus int) string {
+	parts := make([]string, 0, len(values)+1)
+	if showRowNum {
+		rule := strings.Repeat("─", rowNumWidth)
+		if !noColor {
+			rule = separatorStyle.Render(rule)
+		}
+		parts = append(parts, rule)
+	}
+	for i, val := range values {
+		if i >= len(widths) {
+			break
+		}
+		w := widths[i]
+		var cell string
+		switch {
+		case i == focus:
+			cell = padRight(truncate("›"+val, w), w)
+			if !noColor {
+				cell = headerStyle.Render(cell)
+			}
+		case val != "":
+			cell = padRight(truncate(val, w), w)
+			if !noColor {
+				cell = keyStyle.Render(cell)
+			}
+		default:
+			cell = strings.Repeat("─", w)
+			if !noColor {
+				cell = separatorStyle.Render(cell)
+			}
+		}
+		parts = append(parts, cell)
+	}
+	return strings.Join(parts, strings.Repeat(" ", sepWidth))
+}
+
+// visibleIndex maps an index into columns to its index among the columns
+// that are not hidden, or -1 when it is out of range or hidden.
+func visibleIndex(columns, hidden []string, i int) int {
+	if i < 0 || i >= len(columns) || slices.Contains(hidden, columns[i]) {
+		return -1
+	}
+	n := 0
+	for _, col := range columns[:i] {
+		if !slices.Contains(hidden, col) {
+			n++
+		}
+	}
+	return n
+}
+
 func filterColumns(columns []string, rows [][]string, hidden []string) ([]string, [][]string) {
 	if len(hidden) == 0 {
 		return columns, rows
@@ -296,7 +492,7 @@ func filterColumns(columns []string, rows [][]string, hidden []string) ([]string
 	return visibleCols, visibleRows
 }
 
-func calculateColumnWidths(columns []string, rows [][]string, availableWidth int, hints []ColumnHint) []int {
+func calculateColumnWidths(columns []string, rows [][]string, availableWidth int, hints []ColumnHint, percentile int) []int {
 	numCols := len(columns)
 	if numCols == 0 {
 		return nil
@@ -309,16 +505,7 @@ func calculateColumnWidths(columns []string, rows [][]string, availableWidth int
 	}
 
 	// Expand to fit data
-	for _, row := range rows {
-		for i, val := range row {
-			if i < numCols {
-				w := lipgloss.Width(val)
-				if w > widths[i] {
-					widths[i] = w
-				}
-			}
-		}
-	}
+	fitDataWidths(widths, rows, percentile)
 
 	// Apply MaxWidth caps from hints before any shrinking
 	for i := range columns {
@@ -374,6 +561,49 @@ func calculateColumnWidths(columns []string, rows [][]string, availableWidth int
 	return widths
 }
 
+// fitDataWidths widens each column in widths to the given percentile of the
+// display widths of its values in rows; percentiles outside 1-99 use the
+// widest value.
+func fitDataWidths(widths []int, rows [][]string, percentile int) {
+	if percentile <= 0 || percentile >= 100 {
+		for _, row := range rows {
+			for i, val := range row {
+				if i < len(widths) {
+					if w := lipgloss.Width(val); w > widths[i] {
+						widths[i] = w
+					}
+				}
+			}
+		}
+		return
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	// Nearest-rank percentile of each column's value widths. Columns with
+	// truncated outliers keep at least minReadableWidth so the outliers
+	// still show a readable prefix.
+	rank := (percentile*len(rows)+99)/100 - 1
+	values := make([]int, len(rows))
+	for i := range widths {
+		for r, row := range rows {
+			values[r] = 0
+			if i < len(row) {
+				values[r] = lipgloss.Width(row[i])
+			}
+		}
+		sort.Ints(values)
+		w := values[rank]
+		if longest := values[len(values)-1]; longest > w {
+			w = max(w, min(longest, minReadableWidth))
+		}
+		if w > widths[i] {
+			widths[i] = w
+		}
+	}
+}
+
 // totalUsed returns the sum of all column widths.
 func totalUsed(widths []int) int {
 	t := 0
@@ -442,7 +672,7 @@ func ColumnarOverhead(numCols int, showRowNum bool, numRows int) int {
 	return overhead
 }
 
-func renderHeader(columns []string, widths []int, sepWidth, rowNumWidth int, showRowNum, noColor bool) string {
+func renderHeader(columns []string, widths []int, sepWidth, rowNumWidth int, showRowNum, noColor bool, focus int) string {
 	sep := strings.Repeat(" ", sepWidth)
 	sliceCap := len(columns)
 	if showRowNum {
@@ -467,7 +697,11 @@ func renderHeader(columns []string, widths []int, sepWidth, rowNumWidth int, sho
 		w := widths[i]
 		header := padRight(truncate(col, w), w)
 		if !noColor {
-			header = headerStyle.Render(header)
+			style := headerStyle
+			if i == focus {
+				style = style.Reverse(true)
+			}
+			header = style.Render(header)
 		}
 		parts = append(parts, header)
 	}
@@ -475,7 +709,7 @@ func renderHeader(columns []string, widths []int, sepWidth, rowNumWidth int, sho
 	return strings.Join(parts, sep)
 }
 
-func renderDataRow(rowIndex int, values []string, widths []int, sepWidth, rowNumWidth int, rowNumStyle string, noColor bool, colAligns []string) string {
+func renderDataRow(rowIndex int, values []string, widths []int, sepWidth, rowNumWidth int, rowNumStyle string, noColor bool, colAligns []string, hints []ColumnHint) string {
 	sep := strings.Repeat(" ", sepWidth)
 	sliceCap := len(values)
 	if rowNumStyle != "none" {
@@ -512,12 +746,21 @@ func renderDataRow(rowIndex int, values []string, widths []int, sepWidth, rowNum
 		w := widths[i]
 		var valStr string
 		if i < len(colAligns) && colAligns[i] == "right" {
-			valStr = padLeft(truncate(val, w), w)
+			valStr = padLeft(truncate(Hyperlink(val), w), w)
 		} else {
-			valStr = padRight(truncate(val, w), w)
+			valStr = padRight(truncate(Hyperlink(val), w), w)
 		}
 		if !noColor {
-			valStr = valueStyle.Render(valStr)
+			var hint ColumnHint
+			if i < len(hints) {
+				hint = hints[i]
+			}
+			// Cells outside the enum warn; others take their state's color.
+			style, _ := StateStyle(hint.States.State(val))
+			if !hint.AllowsText(val) {
+				style = warningStyle
+			}
+			valStr = style.Render(valStr)
 		}
 		parts = append(parts, valStr)
 	}
@@ -731,8 +974,9 @@ const minReadableWidth = 8
 
 // IsColumnarReadableOpts configures the readability check to match the renderer.
 type IsColumnarReadableOpts struct {
-	HiddenColumns  []string // columns to exclude (same as ColumnarOptions.HiddenColumns)
-	RowNumberStyle string   // "none" means no row number column; otherwise accounts for its width
+	HiddenColumns   []string // columns to exclude (same as ColumnarOptions.HiddenColumns)
+	RowNumberStyle  string   // "none" means no row number column; otherwise accounts for its width
+	WidthPercentile int      // column sizing percentile (same as ColumnarOptions.WidthPercentile)
 }
 
 // IsColumnarReadable checks whether a columnar table can be rendered readably
@@ -789,15 +1033,7 @@ func IsColumnarReadable(columns []string, rows [][]string, availableWidth int, h
 		displayCols[i] = header
 		naturalWidths[i] = lipgloss.Width(header)
 	}
-	for _, row := range visibleRows {
-		for i, val := range row {
-			if i < len(naturalWidths) {
-				if w := lipgloss.Width(val); w > naturalWidths[i] {
-					naturalWidths[i] = w
-				}
-			}
-		}
-	}
+	fitDataWidths(naturalWidths, visibleRows, opts.WidthPercentile)
 
 	// Cap naturalWidths at MaxWidth when set: columns with a MaxWidth
 	// hint are intentionally narrow (e.g. enum-only fields), so their
@@ -822,7 +1058,7 @@ func IsColumnarReadable(columns []string, rows [][]string, availableWidth int, h
 
 	// Calculate assigned widths using the same algorithm the renderer uses.
 	// Pass displayCols so header widths match the renderer exactly.
-	assigned := calculateColumnWidths(displayCols, visibleRows, effectiveWidth, hintSlice)
+	assigned := calculateColumnWidths(displayCols, visibleRows, effectiveWidth, hintSlice, opts.WidthPercentile)
 
 	// A column is unreadable when its allocated width drops below
 	// minReadableWidth. We only flag columns whose content naturally
@@ -895,8 +1131,9 @@ func ColumnsToDropForReadability(columns []string, rows [][]string, availableWid
 		toDrop = append(toDrop, cp.name)
 		hidden = append(hidden, cp.name)
 		checkOpts := IsColumnarReadableOpts{
-			HiddenColumns:  hidden,
-			RowNumberStyle: opts.RowNumberStyle,
+			HiddenColumns:   hidden,
+			RowNumberStyle:  opts.RowNumberStyle,
+			WidthPercentile: opts.WidthPercentile,
 		}
 		if IsColumnarReadable(columns, rows, availableWidth, hints, checkOpts) {
 			return toDrop
@@ -137,7 +137,7 @@ func TestCalculateColumnWidths(t *testing.T) {
 		columns := []string{"name", "id"}
 		rows := [][]string{{"Alice", "123"}}
 
-		widths := calculateColumnWidths(columns, rows, 100, nil)
+		widths := calculateColumnWidths(columns, rows, 100, nil, 0)
 		require.Len(t, widths, 2)
 		// Widths should be at least header width
 		assert.GreaterOrEqual(t, widths[0], 4) // "name"
@@ -148,7 +148,7 @@ func TestCalculateColumnWidths(t *testing.T) {
 		columns := []string{"x"}
 		rows := [][]string{{"very long value here"}}
 
-		widths := calculateColumnWidths(columns, rows, 100, nil)
+		widths := calculateColumnWidths(columns, rows, 100, nil, 0)
 		require.Len(t, widths, 1)
 		// Should expand to fit data (or hit cap)
 		assert.GreaterOrEqual(t, widths[0], 1)
@@ -164,7 +164,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 			{MaxWidth: 10},
 		}
 
-		widths := calculateColumnWidths(columns, rows, 100, hints)
+		widths := calculateColumnWidths(columns, rows, 100, hints, 0)
 		require.Len(t, widths, 2)
 		assert.LessOrEqual(t, widths[1], 10, "long_col should be capped at MaxWidth 10")
 	})
@@ -182,7 +182,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 		}
 
 		// Available width is much less than needed (30+30+2sep = 62, give only 40)
-		widths := calculateColumnWidths(columns, rows, 40, hints)
+		widths := calculateColumnWidths(columns, rows, 40, hints, 0)
 		require.Len(t, widths, 2)
 		// Important column should be wider than unimportant
 		assert.Greater(t, widths[0], widths[1],
@@ -202,7 +202,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 		// Natural widths: status=6("status"), message=9("short msg")
 		// Total natural = 6+9 = 15, seps = 2, so 17 needed.
 		// Give 60 chars available → 43 surplus should go to message.
-		widths := calculateColumnWidths(columns, rows, 60, hints)
+		widths := calculateColumnWidths(columns, rows, 60, hints, 0)
 		require.Len(t, widths, 2)
 		assert.Equal(t, 6, widths[0], "fixed column stays at natural width")
 		assert.Equal(t, 52, widths[1], "flex column absorbs remaining space (60-2sep-6)")
@@ -221,7 +221,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 		// Natural: id=2, description=11("description" header)
 		// MaxWidth caps initial size to 30, but flex expands beyond that.
 		// Give 100 chars → flex gets 100 - 2sep - 2 = 96.
-		widths := calculateColumnWidths(columns, rows, 100, hints)
+		widths := calculateColumnWidths(columns, rows, 100, hints, 0)
 		require.Len(t, widths, 2)
 		assert.Equal(t, 2, widths[0], "fixed column stays at natural width")
 		assert.Equal(t, 96, widths[1], "flex column expands beyond MaxWidth to fill space")
@@ -238,7 +238,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 
 		// Natural: a=1, b=1, c=1, seps=4 → 7 total. Give 27 → 20 surplus
 		// split between b and c.
-		widths := calculateColumnWidths(columns, rows, 27, hints)
+		widths := calculateColumnWidths(columns, rows, 27, hints, 0)
 		require.Len(t, widths, 3)
 		assert.Equal(t, 1, widths[0], "fixed column unchanged")
 		total := widths[1] + widths[2]
@@ -255,7 +255,7 @@ func TestCalculateColumnWidths_WithHints(t *testing.T) {
 
 		// Natural: name=5("Alice"), age=3("age"), seps=2 → 10 total.
 		// Give 100 chars → no expansion since no flex columns.
-		widths := calculateColumnWidths(columns, rows, 100, hints)
+		widths := calculateColumnWidths(columns, rows, 100, hints, 0)
 		require.Len(t, widths, 2)
 		assert.Equal(t, 5, widths[0], "non-flex stays at natural width")
 		assert.Equal(t, 3, widths[1], "non-flex stays at natural width")
@@ -949,3 +949,188 @@ func TestColumnsToDropForReadability_SingleColumn(t *testing.T) {
 	result := ColumnsToDropForReadability(columns, rows, 3, nil, IsColumnarReadableOpts{})
 	assert.Nil(t, result, "should not drop the only column")
 }
+
+func TestRenderColumnarTable_Summary(t *testing.T) {
+	columns := []string{"name", "amount", "secret"}
+	rows := [][]string{
+		{"a", "10", "x"},
+		{"b", "2500", "y"},
+	}
+
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		NoColor:        true,
+		TotalWidth:     80,
+		RowNumberStyle: "numbered",
+		HiddenColumns:  []string{"secret"},
+		Summary:        []string{"", "2510", "hidden"},
+	})
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	require.Len(t, lines, 6) // header, separator, 2 rows, separator, summary
+	assert.Equal(t, lines[1], lines[4])
+	assert.Equal(t, "Σ          2510", strings.TrimRight(lines[5], " "))
+	assert.NotContains(t, result, "hidden")
+}
+
+func TestRenderColumnarTable_Filters(t *testing.T) {
+	columns := []string{"name", "secret", "status"}
+	rows := [][]string{
+		{"alpha", "x", "active"},
+		{"beta", "y", "archived"},
+	}
+
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		NoColor:        true,
+		TotalWidth:     80,
+		RowNumberStyle: "none",
+		HiddenColumns:  []string{"secret"},
+		Filters:        []string{"al", "hidden", "act"},
+		FilterFocus:    2,
+	})
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	require.Len(t, lines, 4) // header, filter row, 2 rows
+	assert.Equal(t, "al     ›act", strings.TrimRight(lines[1], " "))
+	assert.NotContains(t, result, "hidden")
+
+	// An empty cell keeps the rule, and a table with no rows keeps its
+	// header and filter row so the filter can be edited.
+	result = RenderColumnarTable(columns[:1], nil, ColumnarOptions{
+		NoColor:        true,
+		TotalWidth:     80,
+		RowNumberStyle: "none",
+		Filters:        []string{""},
+		FilterFocus:    -1,
+	})
+	assert.Equal(t, "name\n────\n", result)
+}
+
+func TestRenderColumnarTable_FocusedHeader(t *testing.T) {
+	columns := []string{"name", "status"}
+	rows := [][]string{{"alpha", "active"}}
+
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		NoColor:        true,
+		TotalWidth:     80,
+		RowNumberStyle: "none",
+		FocusedHeader:  "status",
+	})
+	lines := strings.Split(result, "\n")
+	assert.Equal(t, "name   ›status", strings.TrimRight(lines[0], " "))
+
+	result = RenderColumnarTable(columns, rows, ColumnarOptions{
+		NoColor:        true,
+		TotalWidth:     80,
+		RowNumberStyle: "none",
+		FocusedHeader:  "missing",
+	})
+	assert.NotContains(t, result, "›")
+}
+
+func TestFitDataWidths_Percentile(t *testing.T) {
+	rows := make([][]string, 0, 10)
+	for i := 0; i < 9; i++ {
+		rows = append(rows, []string{"ok", "abcdefghijkl"})
+	}
+	rows = append(rows, []string{strings.Repeat("x", 100), "abcdefghijkl"})
+
+	widths := []int{3, 3}
+	fitDataWidths(widths, rows, 0)
+	assert.Equal(t, []int{100, 12}, widths)
+
+	widths = []int{3, 3}
+	fitDataWidths(widths, rows, 90)
+	assert.Equal(t, []int{minReadableWidth, 12}, widths, "outlier truncated, readable prefix kept")
+
+	widths = []int{3, 3}
+	fitDataWidths(widths, rows, 100)
+	assert.Equal(t, []int{100, 12}, widths)
+}
+
+func TestRenderColumnarTable_WidthPercentile(t *testing.T) {
+	columns := []string{"id", "msg"}
+	rows := [][]string{{"1", "ok"}, {"2", "ok"}, {"3", "ok"}, {"4", strings.Repeat("y", 60)}}
+
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		NoColor:         true,
+		TotalWidth:      120,
+		RowNumberStyle:  "none",
+		WidthPercentile: 75,
+	})
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, "4   yyyyy...", lines[5])
+	assert.Equal(t, CalculateNaturalColumnarWidthWithPercentile(columns, rows, false, len(rows), nil, nil, 75), len(lines[1])/len("─"))
+}
+
+func TestRenderColumnarTable_EnumWarning(t *testing.T) {
+	SetTableTheme(TableColors{})
+	columns := []string{"name", "status"}
+	rows := [][]string{
+		{"a", "active"},
+		{"b", "gone"},
+		{"c", ""},
+	}
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		TotalWidth:     40,
+		RowNumberStyle: "none",
+		ColumnHints: map[string]ColumnHint{
+			"status": {Enum: []any{"active", "paused"}},
+		},
+	})
+	assert.Contains(t, result, warningStyle.Render(padRight("gone", 6)))
+	assert.NotContains(t, result, warningStyle.Render(padRight("active", 6)))
+	assert.NotContains(t, result, warningStyle.Render(padRight("", 6)))
+}
+
+func TestEnumHints(t *testing.T) {
+	hints := map[string]ColumnHint{
+		"status": {Enum: []any{"active", "paused"}},
+		"tier":   {Enum: []any{float64(1), float64(2)}},
+		"name":   {},
+	}
+	assert.True(t, hints["tier"].Allows(int64(2)))
+	assert.True(t, hints["tier"].Allows("1"))
+	assert.False(t, hints["tier"].Allows(3.5))
+	assert.True(t, hints["status"].Allows(nil))
+	assert.True(t, hints["name"].Allows("anything"))
+	assert.True(t, HasEnumHints(hints))
+	assert.False(t, HasEnumHints(map[string]ColumnHint{"name": {}}))
+
+	data := []any{
+		map[string]any{"name": "a", "status": "active", "tier": int64(1)},
+		map[string]any{"name": "b", "status": "gone", "tier": int64(7)},
+		map[string]any{"name": "c", "status": "closed"},
+		"not an object",
+	}
+	assert.Equal(t, []string{"status", "tier"}, InvalidFields(data[1].(map[string]any), hints))
+	assert.Equal(t, "2 of 4 rows have values outside the schema enum: status (2), tier (1)", EnumReport(data, hints))
+	assert.Equal(t, []any{data[1], data[2]}, InvalidRows(data, hints))
+	assert.Empty(t, EnumReport(data[:1], hints))
+	assert.Empty(t, EnumReport(map[string]any{"status": "gone"}, hints))
+}
+
+func TestRenderColumnarTable_States(t *testing.T) {
+	SetTableTheme(TableColors{})
+	columns := []string{"name", "ms"}
+	rows := [][]string{
+		{"a", "42"},
+		{"b", "250"},
+		{"c", "900"},
+	}
+	result := RenderColumnarTable(columns, rows, ColumnarOptions{
+		TotalWidth:     40,
+		RowNumberStyle: "none",
+		ColumnHints: map[string]ColumnHint{
+			"ms": {States: &ValueStates{
+				Thresholds: []StateThreshold{{Below: 100, State: StateOK}, {Below: 300, State: StateWarning}},
+				Else:       StateError,
+			}},
+		},
+	})
+	assert.Contains(t, result, okStyle.Render(padRight("42", 3)))
+	assert.Contains(t, result, warningStyle.Render(padRight("250", 3)))
+	assert.Contains(t, result, errorStyle.Render(padRight("900", 3)))
+	assert.NotContains(t, result, okStyle.Render(padRight("a", 4)))
+}
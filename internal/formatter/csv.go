@@ -1,8 +1,10 @@
 package formatter
 
 import (
-	"sort"
+	"encoding/base64"
 	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 // escapeCSVField escapes a CSV field according to RFC 4180.
@@ -51,19 +53,7 @@ func FormatAsCSV(node any) string {
 			return ""
 		}
 		if _, ok := v[0].(map[string]any); ok {
-			keySet := make(map[string]bool)
-			for _, elem := range v {
-				if obj, ok := elem.(map[string]any); ok {
-					for k := range obj {
-						keySet[k] = true
-					}
-				}
-			}
-			keys := make([]string, 0, len(keySet))
-			for k := range keySet {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
+			keys := unionKeys(v)
 
 			writeCSVRow(keys)
 
@@ -72,7 +62,7 @@ func FormatAsCSV(node any) string {
 					row := make([]string, len(keys))
 					for i, key := range keys {
 						if val, ok := obj[key]; ok {
-							row[i] = Stringify(val)
+							row[i] = csvCell(val)
 						}
 					}
 					writeCSVRow(row)
@@ -81,23 +71,51 @@ func FormatAsCSV(node any) string {
 		} else {
 			writeCSVRow([]string{"value"})
 			for _, elem := range v {
-				writeCSVRow([]string{Stringify(elem)})
+				writeCSVRow([]string{csvCell(elem)})
 			}
 		}
 	case map[string]any:
 		writeCSVRow([]string{"key", "value"})
-		keys := make([]string, 0, len(v))
-		for k := range v {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			writeCSVRow([]string{k, Stringify(v[k])})
+		for _, k := range keyorder.Keys(v) {
+			writeCSVRow([]string{k, csvCell(v[k])})
 		}
 	default:
 		writeCSVRow([]string{"value"})
-		writeCSVRow([]string{Stringify(node)})
+		writeCSVRow([]string{csvCell(node)})
 	}
 
 	return b.String()
 }
+
+// unionKeys returns every key used by the objects in arr. In insertion mode
+// keys keep the order they first appear in; otherwise the active key order
+// is applied to the whole set.
+func unionKeys(arr []any) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, elem := range arr {
+		obj, ok := elem.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, k := range keyorder.Keys(obj) {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	if keyorder.CurrentMode() != keyorder.Insertion {
+		keyorder.Sort(keys)
+	}
+	return keys
+}
+
+// csvCell stringifies a cell value. Binary data is exported as base64
+// rather than the table placeholder so no data is lost.
+func csvCell(v any) string {
+	if b, ok := BinaryBytes(v); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return Stringify(v)
+}
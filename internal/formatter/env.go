@@ -0,0 +1,220 @@
+package formatter
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// KeyCaseUpperSnake is the EnvOptions.KeyCase that writes keys in
+// UPPER_SNAKE_CASE.
+const KeyCaseUpperSnake = "upper-snake"
+
+// EnvOptions control dotenv and shell assignment output.
+type EnvOptions struct {
+	// Prefix keeps only the values below this path, such as
+	// ["services", "api"]. Values outside it are left out.
+	Prefix []string
+	// StripPrefix leaves Prefix out of the keys, so services.api.port is
+	// written as port rather than services_api_port.
+	StripPrefix bool
+	// KeyCase transforms keys: "" keeps their case, KeyCaseUpperSnake
+	// writes them in UPPER_SNAKE_CASE.
+	KeyCase string
+}
+
+// flatEntry is one scalar of a flattened document and the keys and
+// indexes leading to it.
+type flatEntry struct {
+	path  []string
+	value any
+}
+
+// flatten appends the scalars below node in key order. Empty maps and
+// lists have no scalars and are left out.
+func flatten(node any, path []string, out *[]flatEntry) {
+	switch v := node.(type) {
+	case map[string]any:
+		for _, k := range keyorder.Keys(v) {
+			flatten(v[k], append(path[:len(path):len(path)], k), out)
+		}
+	case []any:
+		for i, e := range v {
+			flatten(e, append(path[:len(path):len(path)], strconv.Itoa(i)), out)
+		}
+	default:
+		*out = append(*out, flatEntry{path: path, value: node})
+	}
+}
+
+// flattenPrefix flattens the value below opts.Prefix, with the prefix
+// left out of the paths when StripPrefix is set. A prefix that is not in
+// node yields nothing.
+func flattenPrefix(node any, opts EnvOptions) []flatEntry {
+	for _, step := range opts.Prefix {
+		switch v := node.(type) {
+		case map[string]any:
+			child, ok := v[step]
+			if !ok {
+				return nil
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(step)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			node = v[i]
+		default:
+			return nil
+		}
+	}
+	var base []string
+	if !opts.StripPrefix {
+		base = opts.Prefix
+	}
+	var entries []flatEntry
+	flatten(node, base, &entries)
+	return entries
+}
+
+// FormatEnv renders a map or list as dotenv lines, KEY=value, one per
+// scalar. Keys are the path to the scalar joined with "_"; characters that
+// cannot appear in an environment variable name become "_", and keys that
+// would start with a digit, such as those of list items, are prefixed with
+// "_". Values are double-quoted when they contain anything beyond letters,
+// digits, and ./:@%+,_- characters. A scalar whose key would be empty, such as the
+// value of a stripped prefix that names a scalar, is left out.
+func FormatEnv(node any, opts EnvOptions) (string, error) {
+	switch node.(type) {
+	case map[string]any, []any:
+	default:
+		return "", errors.New("env output needs a map or list")
+	}
+	return formatAssignments(node, opts, quoteEnvValue), nil
+}
+
+// FormatShell renders a map as POSIX shell assignments, KEY='value', for
+// eval. Keys are built as in FormatEnv; values are always single-quoted,
+// so nothing in them is expanded.
+func FormatShell(node any, opts EnvOptions) (string, error) {
+	if _, ok := node.(map[string]any); !ok {
+		return "", errors.New("shell output needs a map")
+	}
+	return formatAssignments(node, opts, quoteShellValue), nil
+}
+
+// formatAssignments writes a key=value line for each scalar below the
+// prefix, with the value passed through quote.
+func formatAssignments(node any, opts EnvOptions, quote func(string) string) string {
+	var b strings.Builder
+	for _, e := range flattenPrefix(node, opts) {
+		key := envKey(e.path, opts.KeyCase)
+		if key == "" {
+			continue
+		}
+		b.WriteString(envName(key))
+		b.WriteByte('=')
+		b.WriteString(quote(envValue(e.value)))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// envKey joins path into an environment variable name.
+func envKey(path []string, keyCase string) string {
+	parts := make([]string, 0, len(path))
+	for _, step := range path {
+		if keyCase == KeyCaseUpperSnake {
+			step = UpperSnake(step)
+		} else {
+			step = strings.Map(func(r rune) rune {
+				if r == '_' || isASCIIAlnum(r) {
+					return r
+				}
+				return '_'
+			}, step)
+		}
+		if step != "" {
+			parts = append(parts, step)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// UpperSnake converts a key such as "maxRetries", "max-retries", or
+// "HTTPServer" to UPPER_SNAKE_CASE ("MAX_RETRIES", "HTTP_SERVER"). Word
+// boundaries are case changes and any character other than a letter or
+// digit, which is dropped.
+func UpperSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	pendingSep := false
+	for i, r := range runes {
+		if !isASCIIAlnum(r) {
+			pendingSep = b.Len() > 0
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) && b.Len() > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				pendingSep = true
+			}
+		}
+		if pendingSep {
+			b.WriteByte('_')
+			pendingSep = false
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// envValue stringifies a scalar without the escaping of table cells.
+// Binary data is written as base64, as in CSV.
+func envValue(v any) string {
+	if b, ok := BinaryBytes(v); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return Stringify(v)
+}
+
+// quoteEnvValue double-quotes a dotenv value unless it only has characters
+// that need no quoting, escaping backslashes, quotes, "$", backticks, and
+// line breaks.
+func quoteEnvValue(s string) string {
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !isASCIIAlnum(r) && !strings.ContainsRune("./:@%+,_-", r)
+	}) < 0 {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// envName makes an environment variable name a valid identifier, which
+// cannot start with a digit.
+func envName(key string) string {
+	if key[0] >= '0' && key[0] <= '9' {
+		return "_" + key
+	}
+	return key
+}
+
+// quoteShellValue single-quotes s for a POSIX shell. Each single quote in
+// s closes the quoted string, is written escaped, and reopens it.
+func quoteShellValue(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
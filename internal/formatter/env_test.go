@@ -0,0 +1,92 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEnv(t *testing.T) {
+	data := map[string]any{
+		"database": map[string]any{"host": "db.local", "port": 5432, "password": `p@ss "word" $1`},
+		"services": map[string]any{
+			"api": map[string]any{"maxRetries": 3, "allowed-hosts": []any{"a", "b"}, "motd": "hello world\nbye"},
+		},
+		"debug": true,
+		"empty": map[string]any{},
+		"unset": nil,
+	}
+
+	out, err := FormatEnv(data, EnvOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, `database_host=db.local
+database_password="p@ss \"word\" \$1"
+database_port=5432
+debug=true
+services_api_allowed_hosts_0=a
+services_api_allowed_hosts_1=b
+services_api_maxRetries=3
+services_api_motd="hello world\nbye"
+unset=
+`, out)
+
+	out, err = FormatEnv(data, EnvOptions{Prefix: []string{"services", "api"}, StripPrefix: true, KeyCase: KeyCaseUpperSnake})
+	require.NoError(t, err)
+	assert.Equal(t, "ALLOWED_HOSTS_0=a\nALLOWED_HOSTS_1=b\nMAX_RETRIES=3\nMOTD=\"hello world\\nbye\"\n", out)
+
+	out, err = FormatEnv(data, EnvOptions{Prefix: []string{"database"}, KeyCase: KeyCaseUpperSnake})
+	require.NoError(t, err)
+	assert.Equal(t, "DATABASE_HOST=db.local\nDATABASE_PASSWORD=\"p@ss \\\"word\\\" \\$1\"\nDATABASE_PORT=5432\n", out)
+
+	out, err = FormatEnv(data, EnvOptions{Prefix: []string{"missing"}})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	_, err = FormatEnv("scalar", EnvOptions{})
+	assert.Error(t, err)
+}
+
+func TestFormatEnv_Escaping(t *testing.T) {
+	out, err := FormatEnv(map[string]any{"cmd": "echo `id` $HOME"}, EnvOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "cmd=\"echo \\`id\\` \\$HOME\"\n", out)
+
+	out, err = FormatEnv([]any{"a", map[string]any{"2fa": true}}, EnvOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "_0=a\n_1_2fa=true\n", out)
+
+	out, err = FormatEnv(map[string]any{"9lives": 9}, EnvOptions{KeyCase: KeyCaseUpperSnake})
+	require.NoError(t, err)
+	assert.Equal(t, "_9LIVES=9\n", out)
+}
+
+func TestUpperSnake(t *testing.T) {
+	for in, want := range map[string]string{
+		"host":        "HOST",
+		"maxRetries":  "MAX_RETRIES",
+		"max-retries": "MAX_RETRIES",
+		"HTTPServer":  "HTTP_SERVER",
+		"api.v2Key":   "API_V2_KEY",
+		"--odd  key":  "ODD_KEY",
+		"ALREADY_SET": "ALREADY_SET",
+	} {
+		assert.Equal(t, want, UpperSnake(in), in)
+	}
+}
+
+func TestFormatShell(t *testing.T) {
+	data := map[string]any{
+		"host":     "db.local",
+		"password": "it's $HOME `x`",
+		"port":     5432,
+		"3d":       "yes",
+		"tags":     []any{"a"},
+	}
+	out, err := FormatShell(data, EnvOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "_3d='yes'\nhost='db.local'\npassword='it'\\''s $HOME `x`'\nport='5432'\ntags_0='a'\n", out)
+
+	_, err = FormatShell([]any{"a"}, EnvOptions{})
+	assert.Error(t, err)
+}
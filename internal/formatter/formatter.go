@@ -6,9 +6,13 @@ import (
 	"image/color"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 	"golang.org/x/term"
 )
 
@@ -18,11 +22,17 @@ var (
 	defaultKeyColor   = lipgloss.Color("14")
 	defaultValueColor = lipgloss.Color("248")
 	defaultSeparator  = lipgloss.Color("240")
+	defaultWarning    = lipgloss.Color("214")
+	defaultOK         = lipgloss.Color("114")
+	defaultError      = lipgloss.Color("203")
 
 	headerStyle    lipgloss.Style
 	keyStyle       lipgloss.Style
 	valueStyle     lipgloss.Style
 	separatorStyle lipgloss.Style
+	warningStyle   lipgloss.Style
+	okStyle        lipgloss.Style
+	errorStyle     lipgloss.Style
 
 	// maxValueLines caps how many lines a multi-line value renders in
 	// the key-value table view. 0 disables multi-line (escapes newlines).
@@ -32,8 +42,21 @@ var (
 	// defaultMaxValueLines is the initial value of maxValueLines, used to
 	// reset the global when config omits the setting.
 	defaultMaxValueLines = 10
+
+	// wrapValues wraps long values in the key-value table view onto
+	// continuation lines instead of truncating them.
+	wrapValues = false
+
+	// wrapKeys lists keys whose values wrap even when wrapValues is off.
+	wrapKeys map[string]bool
+
+	// ellipsis ends table cells cut to fit their column.
+	ellipsis = DefaultEllipsis
 )
 
+// DefaultEllipsis is the ellipsis used until SetEllipsis sets another one.
+const DefaultEllipsis = "..."
+
 // TableColors controls the rendered colors for the formatter table.
 // Empty fields fall back to legacy defaults (ANSI 256 codes).
 type TableColors struct {
@@ -42,6 +65,9 @@ type TableColors struct {
 	KeyColor       color.Color
 	ValueColor     color.Color
 	SeparatorColor color.Color
+	WarningColor   color.Color // cells outside their column's enum, and the warning state
+	OKColor        color.Color // cells in the ok state (see ValueStates)
+	ErrorColor     color.Color // cells in the error state
 }
 
 func applyTableTheme(tc TableColors) {
@@ -50,6 +76,9 @@ func applyTableTheme(tc TableColors) {
 	kc := tc.KeyColor
 	vc := tc.ValueColor
 	sep := tc.SeparatorColor
+	warn := tc.WarningColor
+	ok := tc.OKColor
+	bad := tc.ErrorColor
 	if hfg == nil {
 		hfg = defaultHeaderFG
 	}
@@ -65,11 +94,23 @@ func applyTableTheme(tc TableColors) {
 	if sep == nil {
 		sep = defaultSeparator
 	}
+	if warn == nil {
+		warn = defaultWarning
+	}
+	if ok == nil {
+		ok = defaultOK
+	}
+	if bad == nil {
+		bad = defaultError
+	}
 
 	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(hfg).Background(hbg)
 	keyStyle = lipgloss.NewStyle().Foreground(kc)
 	valueStyle = lipgloss.NewStyle().Foreground(vc)
 	separatorStyle = lipgloss.NewStyle().Foreground(sep)
+	warningStyle = lipgloss.NewStyle().Foreground(warn)
+	okStyle = lipgloss.NewStyle().Foreground(ok)
+	errorStyle = lipgloss.NewStyle().Foreground(bad)
 }
 
 // SetTableTheme overrides the global table styles. Callers can pass zero-valued
@@ -96,6 +137,61 @@ func DefaultMaxValueLines() int {
 	return defaultMaxValueLines
 }
 
+// SetWrapValues turns word wrapping of long values in the key-value table
+// view on or off. Wrapped lines count toward the MaxValueLines cap.
+func SetWrapValues(on bool) {
+	wrapValues = on
+}
+
+// WrapValues reports whether long values wrap in the key-value table view.
+func WrapValues() bool {
+	return wrapValues
+}
+
+// SetEllipsis sets the text that ends table cells cut to fit their column,
+// such as "…". An empty text restores DefaultEllipsis.
+func SetEllipsis(s string) {
+	if s == "" {
+		s = DefaultEllipsis
+	}
+	ellipsis = s
+}
+
+// Ellipsis returns the text that ends cut table cells.
+func Ellipsis() string {
+	return ellipsis
+}
+
+// SetWrapKeys sets the keys whose values wrap in the key-value table view
+// regardless of SetWrapValues, e.g. from ColumnHint.Wrap. nil clears them.
+func SetWrapKeys(keys []string) {
+	wrapKeys = nil
+	if len(keys) > 0 {
+		wrapKeys = make(map[string]bool, len(keys))
+		for _, k := range keys {
+			wrapKeys[k] = true
+		}
+	}
+}
+
+// WrapKeys returns the keys set with SetWrapKeys, sorted.
+func WrapKeys() []string {
+	if len(wrapKeys) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(wrapKeys))
+	for k := range wrapKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shouldWrap reports whether the value of key wraps.
+func shouldWrap(key string) bool {
+	return wrapValues || wrapKeys[key]
+}
+
 //nolint:gochecknoinits // initialize default table theme for package consumers
 func init() {
 	applyTableTheme(TableColors{})
@@ -106,14 +202,24 @@ func Stringify(v any) string {
 	if v == nil {
 		return ""
 	}
+	if b, ok := BinaryBytes(v); ok {
+		return BinaryPlaceholder(b)
+	}
 	switch t := v.(type) {
 	case string:
 		return escapeScalarString(t)
-	case bool, int, int64, float64:
-		return fmt.Sprint(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		// Same text as fmt.Sprint, without its allocations
+		return strconv.FormatFloat(t, 'g', -1, 64)
 	case map[string]any, []any:
 		// marshal to compact JSON for readability in single column
-		if b, err := json.Marshal(t); err == nil {
+		if b, err := marshalOrdered(t, keyorder.Active()); err == nil {
 			return string(b)
 		}
 		return fmt.Sprintf("%v", t)
@@ -142,6 +248,18 @@ func Stringify(v any) string {
 	}
 }
 
+// StringifySorted is Stringify with the keys of maps in the order of s
+// instead of the active key order.
+func StringifySorted(v any, s keyorder.Sorter) string {
+	switch t := v.(type) {
+	case map[string]any, []any:
+		if b, err := marshalOrdered(t, s); err == nil {
+			return string(b)
+		}
+	}
+	return Stringify(v)
+}
+
 // StringifyPreserveNewlines returns a string representation while keeping real line breaks
 // for scalar strings (used in scalar view so users can read multiline values).
 // Non-string types fall back to Stringify for consistency.
@@ -149,6 +267,9 @@ func StringifyPreserveNewlines(v any) string {
 	if v == nil {
 		return ""
 	}
+	if b, ok := BinaryBytes(v); ok {
+		return BinaryPlaceholder(b)
+	}
 	switch t := v.(type) {
 	case string:
 		return normalizeScalarString(t, false, true)
@@ -157,6 +278,15 @@ func StringifyPreserveNewlines(v any) string {
 	}
 }
 
+// stringifyPreserveNewlinesSorted is StringifyPreserveNewlines with the keys
+// of maps in the order of s.
+func stringifyPreserveNewlinesSorted(v any, s keyorder.Sorter) string {
+	if _, ok := v.(string); ok {
+		return StringifyPreserveNewlines(v)
+	}
+	return StringifySorted(v, s)
+}
+
 // escapeScalarString flattens control characters in scalar strings so table rows stay single-line.
 func escapeScalarString(s string) string {
 	return normalizeScalarString(s, true, false)
@@ -194,44 +324,23 @@ func normalizeScalarString(s string, escapeNewlines bool, expandEscapedNewlines
 	return s
 }
 
-// truncate truncates a string to maxLen and adds ellipsis if needed
+// truncate truncates a string to maxLen display cells and adds the ellipsis
+// if needed. Grapheme clusters (wide characters, emoji sequences, combining
+// accents) are never split.
 func truncate(s string, maxLen int) string {
 	if maxLen <= 0 {
 		return s
 	}
-	// Measure display width using lipgloss (handles wide chars and ANSI codes)
-	w := lipgloss.Width(s)
-	if w <= maxLen {
-		return s
-	}
-	if maxLen < 3 {
-		// Too short for ellipsis - truncate to exact width
-		// Strip ANSI and truncate by visual width
-		result := ""
-		width := 0
-		for _, r := range s {
-			rw := lipgloss.Width(string(r))
-			if width+rw > maxLen {
-				break
-			}
-			result += string(r)
-			width += rw
-		}
-		return result
-	}
-	// Truncate to fit width, leaving room for ellipsis
-	target := maxLen - 3
-	result := ""
-	width := 0
-	for _, r := range s {
-		rw := lipgloss.Width(string(r))
-		if width+rw > target {
-			break
-		}
-		result += string(r)
-		width += rw
+	return TruncateCell(s, maxLen)
+}
+
+// TruncateCell cuts s to at most width display cells, ending it with the
+// ellipsis when it is cut. Widths too narrow for the ellipsis cut s bare.
+func TruncateCell(s string, width int) string {
+	if width < textwidth.Width(ellipsis) {
+		return textwidth.Truncate(s, width, "")
 	}
-	return result + "..."
+	return textwidth.Truncate(s, width, ellipsis)
 }
 
 // getTerminalWidth returns the terminal width, or a default if detection fails
@@ -381,7 +490,7 @@ func RenderTableFitContent(rows [][]string, noColor bool, maxWidth int, columnOr
 			val = row[1]
 		}
 		keyStr := padRight(truncate(key, keyWidth), keyWidth)
-		renderMultilineRow(&b, keyStr, val, keyWidth, valueWidth, noColor, sep)
+		renderMultilineRow(&b, keyStr, val, keyWidth, valueWidth, noColor, sep, shouldWrap(key))
 	}
 
 	return b.String()
@@ -392,6 +501,26 @@ func RenderTableFitContent(rows [][]string, noColor bool, maxWidth int, columnOr
 // keyColWidth: width for KEY column (0 = use default 30)
 // valueColWidth: width for VALUE column (0 = auto-calculate from remaining space)
 func RenderTable(node any, noColor bool, keyColWidth, valueColWidth int, columnOrder []string) string {
+	table, _ := RenderTableRowSpans(node, noColor, keyColWidth, valueColWidth, columnOrder)
+	return table
+}
+
+// RenderTableRowSpans renders like RenderTable and also returns the
+// [start, end) range of body lines, counted from the line below the
+// separator, that each row was written on. A row spans several lines when
+// its value is multi-line or wraps.
+func RenderTableRowSpans(node any, noColor bool, keyColWidth, valueColWidth int, columnOrder []string) (string, [][2]int) {
+	return renderTableRowSpans(node, noColor, keyColWidth, valueColWidth, columnOrder, keyorder.Active())
+}
+
+// RenderTableSorted is RenderTable with the keys of maps in the order of s
+// instead of the active key order.
+func RenderTableSorted(node any, noColor bool, keyColWidth, valueColWidth int, columnOrder []string, s keyorder.Sorter) string {
+	table, _ := renderTableRowSpans(node, noColor, keyColWidth, valueColWidth, columnOrder, s)
+	return table
+}
+
+func renderTableRowSpans(node any, noColor bool, keyColWidth, valueColWidth int, columnOrder []string, s keyorder.Sorter) (string, [][2]int) {
 	// Caller supplies column widths based on their layout (panel width). Do not
 	// recompute from terminal width here or the rendered rows will overflow the
 	// caller's panel (causing wrapping in interactive mode).
@@ -432,20 +561,30 @@ func RenderTable(node any, noColor bool, keyColWidth, valueColWidth int, columnO
 	}
 	b.WriteString(separator + "\n")
 
+	var spans [][2]int
+	addRow := func(keyStr, valRaw string, wrap bool) {
+		first := 0
+		if len(spans) > 0 {
+			first = spans[len(spans)-1][1]
+		}
+		n := renderMultilineRow(&b, keyStr, valRaw, keyWidth, valueWidth, noColor, sep, wrap)
+		spans = append(spans, [2]int{first, first + n})
+	}
+
 	switch t := node.(type) {
 	case map[string]any:
-		keys := orderedMapKeys(t, columnOrder)
+		keys := orderedMapKeys(t, columnOrder, s)
 		for _, k := range keys {
 			v := t[k]
 			keyStr := padRight(truncate(k, keyWidth), keyWidth)
-			valRaw := StringifyPreserveNewlines(v)
-			renderMultilineRow(&b, keyStr, valRaw, keyWidth, valueWidth, noColor, sep)
+			valRaw := stringifyPreserveNewlinesSorted(v, s)
+			addRow(keyStr, valRaw, shouldWrap(k))
 		}
 	case []any:
 		for i, v := range t {
 			keyStr := padRight(fmt.Sprintf("[%d]", i), keyWidth)
-			valRaw := StringifyPreserveNewlines(v)
-			renderMultilineRow(&b, keyStr, valRaw, keyWidth, valueWidth, noColor, sep)
+			valRaw := stringifyPreserveNewlinesSorted(v, s)
+			addRow(keyStr, valRaw, wrapValues)
 		}
 	default:
 		// Check if it's a slice type (could be []map, []string, etc.)
@@ -454,18 +593,18 @@ func RenderTable(node any, noColor bool, keyColWidth, valueColWidth int, columnO
 			for i := 0; i < sliceVal.Len(); i++ {
 				v := sliceVal.Index(i).Interface()
 				keyStr := padRight(fmt.Sprintf("[%d]", i), keyWidth)
-				valRaw := StringifyPreserveNewlines(v)
-				renderMultilineRow(&b, keyStr, valRaw, keyWidth, valueWidth, noColor, sep)
+				valRaw := stringifyPreserveNewlinesSorted(v, s)
+				addRow(keyStr, valRaw, wrapValues)
 			}
 		} else {
 			// scalar value - must match navigator.ScalarValueKey (can't import due to cycle)
 			keyStr := padRight("(value)", keyWidth)
-			valRaw := StringifyPreserveNewlines(node)
-			renderMultilineRow(&b, keyStr, valRaw, keyWidth, valueWidth, noColor, sep)
+			valRaw := stringifyPreserveNewlinesSorted(node, s)
+			addRow(keyStr, valRaw, wrapValues)
 		}
 	}
 
-	return b.String()
+	return b.String(), spans
 }
 
 // RenderRows prints a two-column table (key, value) for precomputed rows.
@@ -516,7 +655,7 @@ func RenderRows(rows [][]string, noColor bool, keyColWidth, valueColWidth int) s
 			val = row[1]
 		}
 		keyStr := padRight(truncate(key, keyWidth), keyWidth)
-		renderMultilineRow(&b, keyStr, val, keyWidth, valueWidth, noColor, sep)
+		renderMultilineRow(&b, keyStr, val, keyWidth, valueWidth, noColor, sep, shouldWrap(key))
 	}
 
 	return b.String()
@@ -539,34 +678,58 @@ func naturalValueWidth(val string) int {
 	return best
 }
 
-// padRight pads a string to the specified width, right-aligned
+// padRight pads a string with spaces to the specified display width,
+// truncating it first if it is wider.
 func padRight(s string, width int) string {
-	if len(s) >= width {
-		return s[:width]
-	}
-	return s + strings.Repeat(" ", width-len(s))
+	return textwidth.PadRight(s, width)
 }
 
 // renderMultilineRow writes a key-value row to b, splitting multi-line values
 // across multiple display rows. The first line appears next to the key; continuation
 // lines are indented to align under the value column with an empty key column.
-func renderMultilineRow(b *strings.Builder, keyStr, valRaw string, keyWidth, valueWidth int, noColor bool, sep string) {
+// With wrap set, lines wider than the value column continue on further rows
+// instead of being truncated. It returns the number of lines written.
+func renderMultilineRow(b *strings.Builder, keyStr, valRaw string, keyWidth, valueWidth int, noColor bool, sep string, wrap bool) int {
 	// When multi-line rendering is disabled (maxValueLines == 0), flatten to single line.
-	if maxValueLines == 0 {
-		valFlat := padRight(truncate(escapeScalarString(valRaw), valueWidth), valueWidth)
+	if maxValueLines == 0 && !wrap {
+		valFlat := padRight(truncate(Hyperlink(escapeScalarString(valRaw)), valueWidth), valueWidth)
 		k := keyStr
 		if !noColor {
 			k = keyStyle.Render(k)
 			valFlat = valueStyle.Render(valFlat)
 		}
 		b.WriteString(k + sep + valFlat + "\n")
-		return
+		return 1
 	}
 
-	lines := strings.Split(valRaw, "\n")
-	// Trim trailing empty line that YAML block scalars often leave
-	if len(lines) > 1 && lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+	var lines []string
+	if maxValueLines == 0 {
+		lines = []string{escapeScalarString(valRaw)}
+	} else {
+		lines = strings.Split(valRaw, "\n")
+		// Trim trailing empty line that YAML block scalars often leave
+		if len(lines) > 1 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+
+	// Links are only emitted for lines that are not split by wrapping, so a
+	// wrapped URL never becomes several broken links.
+	linked := make([]bool, 0, len(lines))
+	if wrap {
+		wrapped := make([]string, 0, len(lines))
+		for _, line := range lines {
+			parts := textwidth.Wrap(line, valueWidth)
+			wrapped = append(wrapped, parts...)
+			for range parts {
+				linked = append(linked, len(parts) == 1)
+			}
+		}
+		lines = wrapped
+	} else {
+		for range lines {
+			linked = append(linked, true)
+		}
 	}
 
 	// Cap visible lines when a positive limit is set.
@@ -583,6 +746,9 @@ func renderMultilineRow(b *strings.Builder, keyStr, valRaw string, keyWidth, val
 		} else {
 			k = padRight("", keyWidth)
 		}
+		if linked[i] {
+			line = Hyperlink(line)
+		}
 		v := padRight(truncate(line, valueWidth), valueWidth)
 		if !noColor {
 			k = keyStyle.Render(k)
@@ -594,11 +760,13 @@ func renderMultilineRow(b *strings.Builder, keyStr, valRaw string, keyWidth, val
 	// Show truncation indicator
 	if truncated {
 		k := padRight("", keyWidth)
-		v := padRight(truncate("...", valueWidth), valueWidth)
+		v := padRight(truncate(ellipsis, valueWidth), valueWidth)
 		if !noColor {
 			k = keyStyle.Render(k)
 			v = valueStyle.Render(v)
 		}
 		b.WriteString(k + sep + v + "\n")
+		return len(lines) + 1
 	}
+	return len(lines)
 }
@@ -1,11 +1,14 @@
 package formatter
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 func TestStringifyString(t *testing.T) {
@@ -79,6 +82,12 @@ func TestStringifyFloat(t *testing.T) {
 	}
 }
 
+func TestStringifyFloatMatchesSprint(t *testing.T) {
+	for _, f := range []float64{0.1, 3, -0.5, 1e6, 1e21, 1e-7, 123456789.125} {
+		assert.Equal(t, fmt.Sprint(f), Stringify(f))
+	}
+}
+
 func TestStringifyMap(t *testing.T) {
 	data := map[string]any{"key": "value", "num": 42}
 	result := Stringify(data)
@@ -288,7 +297,7 @@ func TestFormatYAMLLiteralBlock(t *testing.T) {
 		"notes": "line1\nline2",
 	}
 
-	out, err := FormatYAML(obj, YAMLFormatOptions{Indent: 2, LiteralBlockStrings: true})
+	out, err := FormatYAML(obj, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}, LiteralBlockStrings: true})
 	if err != nil {
 		t.Fatalf("format yaml: %v", err)
 	}
@@ -311,7 +320,7 @@ func TestFormatYAMLExpandEscapedNewlines(t *testing.T) {
 		"note": "line1\\nline2",
 	}
 
-	out, err := FormatYAML(obj, YAMLFormatOptions{Indent: 2, LiteralBlockStrings: true, ExpandEscapedNewlines: true})
+	out, err := FormatYAML(obj, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}, LiteralBlockStrings: true, ExpandEscapedNewlines: true})
 	if err != nil {
 		t.Fatalf("format yaml: %v", err)
 	}
@@ -357,6 +366,22 @@ func TestPadRightExactLength(t *testing.T) {
 	}
 }
 
+func TestPadRightWideCharacters(t *testing.T) {
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+	for _, tc := range []struct{ in, want string }{
+		{"東京タワー", "東京 "},
+		{family + "abc", family + "abc"},
+		{"cafe\u0301!", "cafe\u0301!"},
+	} {
+		if got := padRight(tc.in, 5); got != tc.want {
+			t.Fatalf("padRight(%q, 5) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+	if got := truncate("東京タワー", 7); got != "東京..." {
+		t.Fatalf("expected truncation on a character boundary, got %q", got)
+	}
+}
+
 func TestSetTableTheme(t *testing.T) {
 	// Should not panic with zero-value colors
 	SetTableTheme(TableColors{})
@@ -665,6 +690,81 @@ func TestRenderMultilineRow_TrailingEmptyLineTrimmed(t *testing.T) {
 	assert.Equal(t, 2, valueLines, "trailing empty line should be trimmed")
 }
 
+func TestRenderTable_WrapValues(t *testing.T) {
+	origLines, origWrap := MaxValueLines(), WrapValues()
+	t.Cleanup(func() { SetMaxValueLines(origLines); SetWrapValues(origWrap) })
+	SetMaxValueLines(0)
+	SetWrapValues(true)
+
+	node := map[string]any{"desc": "the quick brown fox jumps over the lazy dog again and again"}
+	out := RenderTable(node, true, 6, 20, nil)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")[2:]
+
+	assert.Greater(t, len(lines), 1, "long value should wrap onto continuation lines")
+	assert.True(t, strings.HasPrefix(lines[0], "desc"))
+	for _, l := range lines[1:] {
+		assert.True(t, strings.HasPrefix(l, strings.Repeat(" ", 8)), "continuation line has a blank key: %q", l)
+	}
+	assert.NotContains(t, out, "...")
+	assert.Contains(t, out, "again and again")
+}
+
+func TestRenderTableRowSpans(t *testing.T) {
+	origLines, origWrap := MaxValueLines(), WrapValues()
+	t.Cleanup(func() { SetMaxValueLines(origLines); SetWrapValues(origWrap) })
+	SetMaxValueLines(0)
+	SetWrapValues(true)
+
+	// The "" key leaves the key column blank and the wide key is cut
+	// mid-rune by byte slicing, so rows cannot be told apart from the text.
+	node := map[string]any{
+		"":      "empty key",
+		"desc":  "the quick brown fox jumps over the lazy dog again and again",
+		"日本語キー": "wide",
+	}
+	out, spans := RenderTableRowSpans(node, true, 6, 20, nil)
+	body := strings.Split(strings.TrimRight(out, "\n"), "\n")[2:]
+
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+	assert.Equal(t, [2]int{0, 1}, spans[0])
+	assert.Equal(t, 1, spans[1][0])
+	assert.Greater(t, spans[1][1], 2, "the wrapped value spans several lines")
+	assert.Equal(t, [2]int{spans[1][1], spans[1][1] + 1}, spans[2])
+	assert.Equal(t, len(body), spans[2][1])
+	assert.Equal(t, RenderTable(node, true, 6, 20, nil), out)
+}
+
+func TestRenderTable_WrapKeys(t *testing.T) {
+	origLines, origKeys := MaxValueLines(), WrapKeys()
+	t.Cleanup(func() { SetMaxValueLines(origLines); SetWrapKeys(origKeys) })
+	SetMaxValueLines(0)
+	SetWrapKeys([]string{"wrapped"})
+	assert.Equal(t, []string{"wrapped"}, WrapKeys())
+
+	long := "the quick brown fox jumps over the lazy dog"
+	node := map[string]any{"wrapped": long, "plain": long}
+	out := RenderTable(node, true, 8, 20, nil)
+
+	assert.Contains(t, out, "jumps over the lazy", "the wrapped key shows its whole value")
+	assert.Equal(t, 1, strings.Count(out, "..."), "the other key is still truncated")
+}
+
+func TestRenderRows_WrapRespectsMaxValueLines(t *testing.T) {
+	origLines, origWrap := MaxValueLines(), WrapValues()
+	t.Cleanup(func() { SetMaxValueLines(origLines); SetWrapValues(origWrap) })
+	SetMaxValueLines(2)
+	SetWrapValues(true)
+
+	rows := [][]string{{"k", "one two three four five six seven eight nine ten eleven twelve"}}
+	out := RenderRows(rows, true, 4, 20)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")[2:]
+
+	assert.Len(t, lines, 3, "two wrapped lines plus the truncation indicator")
+	assert.Equal(t, "...", strings.TrimSpace(lines[2]))
+}
+
 func TestRenderRows_ColorBranches(t *testing.T) {
 	orig := MaxValueLines()
 	SetMaxValueLines(10)
@@ -729,7 +829,7 @@ func TestOrderedMapKeys(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := orderedMapKeys(m, tt.columnOrder)
+			got := orderedMapKeys(m, tt.columnOrder, keyorder.Active())
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -750,3 +850,17 @@ func TestRenderTable_MapWithColumnOrder(t *testing.T) {
 	assert.Contains(t, lines[4], "banana")
 	assert.Contains(t, lines[5], "zebra")
 }
+
+func TestSetEllipsis(t *testing.T) {
+	t.Cleanup(func() { SetEllipsis("") })
+
+	SetEllipsis("…")
+	assert.Equal(t, "hello w…", TruncateCell("hello world", 8))
+	assert.Equal(t, "hello w…", truncate("hello world", 8))
+	assert.Equal(t, "hello", TruncateCell("hello", 8))
+
+	SetEllipsis("")
+	assert.Equal(t, DefaultEllipsis, Ellipsis(), "an empty ellipsis restores the default")
+	assert.Equal(t, "hello...", TruncateCell("hello world", 8))
+	assert.Equal(t, "he", TruncateCell("hello world", 2), "too narrow for the ellipsis")
+}
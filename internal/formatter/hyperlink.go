@@ -0,0 +1,41 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// hyperlinks controls whether URL values are wrapped in OSC 8 terminal
+// hyperlinks. Off by default so output written to files and pipes stays
+// plain text.
+var hyperlinks bool
+
+// SetHyperlinks turns OSC 8 hyperlinks for URL values on or off.
+func SetHyperlinks(on bool) {
+	hyperlinks = on
+}
+
+// Hyperlinks reports whether URL values are rendered as hyperlinks.
+func Hyperlinks() bool {
+	return hyperlinks
+}
+
+// IsURL reports whether s is a single http or https URL.
+func IsURL(s string) bool {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return false
+	}
+	return !strings.ContainsAny(s, " \t\r\n")
+}
+
+// Hyperlink returns s as a clickable OSC 8 hyperlink to itself when
+// hyperlinks are enabled and s is a URL, and s unchanged otherwise. The
+// escape sequences take no display width, so the result can be truncated
+// and padded like plain text while still linking to the full URL.
+func Hyperlink(s string) string {
+	if !hyperlinks || !IsURL(s) {
+		return s
+	}
+	return ansi.SetHyperlink(s) + s + ansi.ResetHyperlink()
+}
@@ -0,0 +1,41 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+	"github.com/stretchr/testify/assert"
+)
+
+func withHyperlinks(t *testing.T) {
+	t.Helper()
+	orig := Hyperlinks()
+	SetHyperlinks(true)
+	t.Cleanup(func() { SetHyperlinks(orig) })
+}
+
+func TestIsURL(t *testing.T) {
+	assert.True(t, IsURL("https://example.com/a?b=c"))
+	assert.True(t, IsURL("http://localhost:8080"))
+	assert.False(t, IsURL("ftp://example.com"))
+	assert.False(t, IsURL("see https://example.com"))
+	assert.False(t, IsURL("https://example.com and more"))
+}
+
+func TestHyperlink(t *testing.T) {
+	assert.Equal(t, "https://example.com", Hyperlink("https://example.com"), "off by default")
+
+	withHyperlinks(t)
+	assert.Equal(t, "\x1b]8;;https://example.com\x07https://example.com\x1b]8;;\x07", Hyperlink("https://example.com"))
+	assert.Equal(t, "plain", Hyperlink("plain"))
+}
+
+func TestTruncateKeepsHyperlinkTarget(t *testing.T) {
+	withHyperlinks(t)
+	url := "https://example.com/a/very/long/path"
+	got := truncate(Hyperlink(url), 12)
+	assert.Equal(t, 12, textwidth.Width(got))
+	assert.True(t, strings.HasPrefix(got, "\x1b]8;;"+url+"\x07"), "the link still targets the full URL")
+	assert.Contains(t, got, "\x1b]8;;\x07", "the link is closed")
+}
@@ -0,0 +1,114 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// FormatJSON renders v as JSON laid out by opts. Object keys follow the
+// active key order unless opts.SortKeys is set; encoding/json always sorts
+// them, so other orders are written through an order-preserving wrapper.
+// Binary values are written as base64 strings.
+func FormatJSON(v interface{}, opts SerializeOptions) (string, error) {
+	defer opts.useKeyOrder()()
+	b, err := marshalJSON(jsonValue(v), "", opts)
+	if err != nil {
+		return "", err
+	}
+	return opts.finish(string(b) + "\n"), nil
+}
+
+// marshalJSON encodes v on one line when opts.Compact is set and indented
+// below prefix otherwise.
+func marshalJSON(v interface{}, prefix string, opts SerializeOptions) ([]byte, error) {
+	if opts.Compact {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, prefix, opts.jsonIndent())
+}
+
+// marshalOrdered is json.Marshal with object keys in the order of s.
+func marshalOrdered(v interface{}, s keyorder.Sorter) ([]byte, error) {
+	return json.Marshal(sortedJSONValue(v, s))
+}
+
+// jsonValue prepares v for encoding/json: keys in the active key order and
+// binary values as base64.
+func jsonValue(v interface{}) interface{} {
+	return sortedJSONValue(v, keyorder.Active())
+}
+
+// sortedJSONValue is jsonValue with keys in the order of s.
+func sortedJSONValue(v interface{}, s keyorder.Sorter) interface{} {
+	if customOrder(s.Mode) {
+		return orderedJSONValue(v, s)
+	}
+	return encodeBinary(v)
+}
+
+// customKeyOrder reports whether the active key order differs from the
+// alphabetical order encoding/json produces.
+func customKeyOrder() bool {
+	return customOrder(keyorder.CurrentMode())
+}
+
+// customOrder reports whether mode differs from the alphabetical order
+// encoding/json produces.
+func customOrder(mode keyorder.Mode) bool {
+	return mode != keyorder.Ascending && mode != keyorder.None
+}
+
+// orderedObject marshals a map with its keys in a fixed order.
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func orderedJSONValue(v interface{}, s keyorder.Sorter) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := s.Keys(t)
+		values := make(map[string]interface{}, len(t))
+		for _, k := range keys {
+			values[k] = orderedJSONValue(t[k], s)
+		}
+		return orderedObject{keys: keys, values: values}
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = orderedJSONValue(e, s)
+		}
+		return out
+	default:
+		if b, ok := BinaryBytes(v); ok {
+			return base64.StdEncoding.EncodeToString(b)
+		}
+		return v
+	}
+}
@@ -0,0 +1,73 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatJSON_DefaultSorted(t *testing.T) {
+	out, err := FormatJSON(map[string]any{"b": 1, "a": []any{map[string]any{"y": true, "x": nil}}}, SerializeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": [\n    {\n      \"x\": null,\n      \"y\": true\n    }\n  ],\n  \"b\": 1\n}\n", out)
+}
+
+func TestFormatJSON_InsertionOrder(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Insertion)
+	defer keyorder.SetMode(prev)
+	defer keyorder.SetDocument(nil)
+
+	order := keyorder.NewOrder()
+	inner := map[string]any{"y": true, "x": "<a>"}
+	order.Record(inner, []string{"y", "x"})
+	root := map[string]any{"b": 1, "a": []any{inner}}
+	order.Record(root, []string{"b", "a"})
+	keyorder.SetDocument(order)
+
+	out, err := FormatJSON(root, SerializeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"b\": 1,\n  \"a\": [\n    {\n      \"y\": true,\n      \"x\": \"\\u003ca\\u003e\"\n    }\n  ]\n}\n", out)
+}
+
+func TestFormatJSON_Options(t *testing.T) {
+	v := map[string]any{"b": 1, "a": []any{true}}
+
+	out, err := FormatJSON(v, SerializeOptions{Indent: 4})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n    \"a\": [\n        true\n    ],\n    \"b\": 1\n}\n", out)
+
+	out, err = FormatJSON(v, SerializeOptions{Compact: true})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":[true],\"b\":1}\n", out)
+
+	out, err = FormatJSON(v, SerializeOptions{Compact: true, NoTrailingNewline: true})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":[true],\"b\":1}", out)
+}
+
+func TestFormatJSON_SortKeys(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Descending)
+	defer keyorder.SetMode(prev)
+	defer keyorder.SetDocument(nil)
+
+	root := map[string]any{"b": 1, "c": 2, "a": 3}
+	order := keyorder.NewOrder()
+	order.Record(root, []string{"c", "a", "b"})
+	keyorder.SetDocument(order)
+
+	sorted, unsorted := true, false
+	out, err := FormatJSON(root, SerializeOptions{Compact: true})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"c\":2,\"b\":1,\"a\":3}\n", out, "active key order")
+
+	out, err = FormatJSON(root, SerializeOptions{Compact: true, SortKeys: &sorted})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":3,\"b\":1,\"c\":2}\n", out)
+
+	out, err = FormatJSON(root, SerializeOptions{Compact: true, SortKeys: &unsorted})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"c\":2,\"a\":3,\"b\":1}\n", out, "document order")
+	assert.Equal(t, keyorder.Descending, keyorder.CurrentMode(), "the active order is restored")
+}
@@ -0,0 +1,168 @@
+package formatter
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+)
+
+// KeyValueOptions controls FormatKeyValue output.
+type KeyValueOptions struct {
+	NoColor bool // disable color output
+
+	// Width caps each output line at this many display cells; longer values
+	// are truncated, or wrapped when their hint has Wrap. 0 leaves lines
+	// unbounded.
+	Width int
+
+	// Order lists the fields shown first. The other fields follow by hint
+	// Priority (highest first), then in the active key order.
+	Order []string
+
+	// Hints rename (DisplayName), hide (Hidden), cap (MaxWidth), wrap
+	// (Wrap), and check (Enum) fields, keyed by the field name.
+	Hints map[string]ColumnHint
+
+	// Formatters turn the values of fields into their display text, keyed by
+	// the field name. Other values are shown like list output.
+	Formatters map[string]func(any) string
+}
+
+// FormatKeyValue renders a single record vertically, one "label: value" line
+// per field with the values aligned, like a describe command:
+//
+//	Name:    web-1
+//	Status:  running
+//
+// Structs are rendered by their JSON fields. Values with line breaks continue
+// on lines indented to the value column. Records that are not objects render
+// as "value: <v>", like FormatAsList.
+func FormatKeyValue(record any, opts KeyValueOptions) string {
+	m, ok := keyValueObject(record)
+	if !ok {
+		return FormatAsList(record, ListOptions{NoColor: opts.NoColor})
+	}
+
+	keys := keyValueKeys(m, opts)
+	if len(keys) == 0 {
+		return ""
+	}
+	labels := make([]string, len(keys))
+	labelWidth := 0
+	for i, k := range keys {
+		labels[i] = k + ":"
+		if name := opts.Hints[k].DisplayName; name != "" {
+			labels[i] = name + ":"
+		}
+		labelWidth = max(labelWidth, textwidth.Width(labels[i]))
+	}
+	labelWidth++ // one space before the values
+
+	var b strings.Builder
+	indent := strings.Repeat(" ", labelWidth)
+	for i, k := range keys {
+		hint := opts.Hints[k]
+		lines := keyValueLines(m[k], opts.Formatters[k], hint, keyValueWidth(opts.Width, labelWidth, hint.MaxWidth))
+		style := valueStyle
+		if !hint.Allows(m[k]) {
+			style = warningStyle
+		}
+		for j, line := range lines {
+			label := indent
+			if j == 0 {
+				label = textwidth.PadRight(labels[i], labelWidth)
+				if !opts.NoColor {
+					label = keyStyle.Render(labels[i]) + strings.Repeat(" ", labelWidth-textwidth.Width(labels[i]))
+				}
+			}
+			if !opts.NoColor && line != "" {
+				line = style.Render(line)
+			}
+			b.WriteString(strings.TrimRight(label+line, " "))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// keyValueObject returns record as an object, converting structs and typed
+// maps through their JSON form.
+func keyValueObject(record any) (map[string]any, bool) {
+	switch v := record.(type) {
+	case map[string]any:
+		return v, true
+	case nil, string, bool, int, int64, float64, []any:
+		return nil, false
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// keyValueKeys returns the visible fields of m in display order.
+func keyValueKeys(m map[string]any, opts KeyValueOptions) []string {
+	keys := orderedMapKeys(m, opts.Order, keyorder.Active())
+	// orderedMapKeys puts the fields of Order first.
+	first := 0
+	for first < len(keys) && slices.Contains(opts.Order, keys[first]) {
+		first++
+	}
+	rest := keys[first:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return opts.Hints[rest[i]].Priority > opts.Hints[rest[j]].Priority
+	})
+
+	visible := keys[:0]
+	for _, k := range keys {
+		if !opts.Hints[k].Hidden {
+			visible = append(visible, k)
+		}
+	}
+	return visible
+}
+
+// keyValueWidth returns the width available to a value, or 0 when it is
+// unbounded.
+func keyValueWidth(total, labelWidth, maxWidth int) int {
+	w := 0
+	if total > 0 {
+		w = max(total-labelWidth, 1)
+	}
+	if maxWidth > 0 && (w == 0 || maxWidth < w) {
+		w = maxWidth
+	}
+	return w
+}
+
+// keyValueLines returns the display lines of a value, fitted to width.
+func keyValueLines(v any, format func(any) string, hint ColumnHint, width int) []string {
+	var s string
+	if format != nil {
+		s = format(v)
+	} else {
+		s = StringifyPreserveNewlines(v)
+	}
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if width <= 0 {
+		return lines
+	}
+	fitted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if hint.Wrap {
+			fitted = append(fitted, textwidth.Wrap(line, width)...)
+		} else {
+			fitted = append(fitted, TruncateCell(line, width))
+		}
+	}
+	return fitted
+}
@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatKeyValue(t *testing.T) {
+	record := map[string]any{
+		"name":    "web-1",
+		"status":  "running",
+		"created": "2026-01-02T03:04:05Z",
+		"secret":  "hunter2",
+		"zone":    "eu",
+	}
+	out := FormatKeyValue(record, KeyValueOptions{
+		NoColor: true,
+		Order:   []string{"name"},
+		Hints: map[string]ColumnHint{
+			"name":    {DisplayName: "Name"},
+			"status":  {DisplayName: "Status", Priority: 5},
+			"created": {DisplayName: "Created"},
+			"secret":  {Hidden: true},
+		},
+		Formatters: map[string]func(any) string{
+			"created": func(v any) string {
+				ts, _ := time.Parse(time.RFC3339, v.(string))
+				return ts.Format("Jan 2, 2006")
+			},
+		},
+	})
+	want := "Name:    web-1\n" +
+		"Status:  running\n" +
+		"Created: Jan 2, 2026\n" +
+		"zone:    eu\n"
+	if out != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, out)
+	}
+}
+
+func TestFormatKeyValueWidth(t *testing.T) {
+	record := map[string]any{
+		"id":   "abcdefghijklmnop",
+		"note": "one two three four",
+		"text": "line1\nline2",
+	}
+	out := FormatKeyValue(record, KeyValueOptions{
+		NoColor: true,
+		Width:   15,
+		Hints:   map[string]ColumnHint{"note": {Wrap: true}},
+	})
+	want := "id:   abcdef...\n" +
+		"note: one two\n" +
+		"      three\n" +
+		"      four\n" +
+		"text: line1\n" +
+		"      line2\n"
+	if out != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, out)
+	}
+}
+
+func TestFormatKeyValueStructAndScalar(t *testing.T) {
+	type pod struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	}
+	out := FormatKeyValue(pod{Name: "web", Labels: map[string]string{"app": "web"}}, KeyValueOptions{NoColor: true})
+	want := "labels: {\"app\":\"web\"}\nname:   web\n"
+	if out != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	if out := FormatKeyValue("hello", KeyValueOptions{NoColor: true}); out != "value: hello\n" {
+		t.Fatalf("expected scalar fallback, got %q", out)
+	}
+	if out := FormatKeyValue(map[string]any{"a": 1}, KeyValueOptions{Hints: map[string]ColumnHint{"a": {Hidden: true}}}); out != "" {
+		t.Fatalf("expected no output when every field is hidden, got %q", out)
+	}
+}
+
+func TestFormatKeyValueEnumWarning(t *testing.T) {
+	SetTableTheme(TableColors{})
+	hints := map[string]ColumnHint{"status": {Enum: []any{"running"}}}
+	ok := FormatKeyValue(map[string]any{"status": "running"}, KeyValueOptions{Hints: hints})
+	bad := FormatKeyValue(map[string]any{"status": "crashed"}, KeyValueOptions{Hints: hints})
+	if !strings.Contains(ok, valueStyle.Render("running")) {
+		t.Fatalf("expected allowed value in the value color, got %q", ok)
+	}
+	if !strings.Contains(bad, warningStyle.Render("crashed")) {
+		t.Fatalf("expected value outside the enum in the warning color, got %q", bad)
+	}
+}
@@ -1,8 +1,9 @@
 package formatter
 
 import (
-	"slices"
 	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 // ListOptions controls list output formatting.
@@ -11,112 +12,121 @@ type ListOptions struct {
 	ArrayStyle    string   // array index style: index, numbered, bullet, none
 	HiddenColumns []string // columns to exclude from output
 	ColumnOrder   []string // preferred key display order; unlisted keys appended alphabetically
+
+	// Guides expands nested maps and arrays below their key, drawing
+	// tree-style indentation guides (├─, └─, │), instead of printing them
+	// inline as JSON.
+	Guides bool
+	// Bullet is written before each element of a scalar array, e.g. "•" or
+	// "-". With ArrayStyle "bullet" it also replaces the "•" element header.
+	Bullet string
+	// MaxValueLen truncates each value preview to this many display cells,
+	// with line breaks escaped. 0 shows values in full.
+	MaxValueLen int
 }
 
+// List indentation guides, drawn in the table separator color.
+const (
+	listGuideBranch = "├─ "
+	listGuideLast   = "└─ "
+	listGuidePipe   = "│  "
+	listGuideSpace  = "   "
+)
+
 // FormatAsList renders data in a vertical list format.
 // Arrays of objects display each element with an index header and indented properties.
 // Maps display as key/value pairs with indentation.
 // Scalar values display as "value: <scalar>".
 func FormatAsList(node interface{}, opts ListOptions) string {
-	var b strings.Builder
+	w := listWriter{opts: opts}
 
 	switch v := node.(type) {
 	case []interface{}:
-		b.WriteString(formatArrayAsList(v, opts))
+		w.writeArray(v)
 	case map[string]interface{}:
-		b.WriteString(formatMapAsList(v, "", opts.NoColor, opts.ColumnOrder, opts.HiddenColumns))
+		w.writeMap(v, "")
 	default:
 		// Scalar values: display with "value:" label
-		labelStr := "value"
-		if !opts.NoColor {
-			labelStr = keyStyle.Render(labelStr)
-		}
-		valueStr := StringifyPreserveNewlines(v)
-		if !opts.NoColor {
-			valueStr = valueStyle.Render(valueStr)
-		}
-		b.WriteString(labelStr)
-		b.WriteString(": ")
-		b.WriteString(valueStr)
-		b.WriteString("\n")
+		w.b.WriteString(w.key("value"))
+		w.b.WriteString(": ")
+		w.b.WriteString(w.value(v))
+		w.b.WriteString("\n")
 	}
 
-	return b.String()
+	return w.b.String()
+}
+
+// listWriter accumulates list output for one FormatAsList call.
+type listWriter struct {
+	b    strings.Builder
+	opts ListOptions
 }
 
-func formatArrayAsList(arr []interface{}, opts ListOptions) string {
+func (w *listWriter) writeArray(arr []interface{}) {
 	if len(arr) == 0 {
-		return ""
+		return
 	}
 
-	var b strings.Builder
-
-	// Check if this is a homogeneous array of maps (objects)
-	isHomogeneousObjects := len(arr) > 0
-	firstIsMap := isObjectType(arr[0])
-
-	if firstIsMap {
-		for _, item := range arr {
-			if !isObjectType(item) {
-				isHomogeneousObjects = false
-				break
+	if !isHomogeneousObjects(arr) {
+		// Array of scalars or mixed types: print each on its own line (same as table)
+		for i, elem := range arr {
+			if w.opts.Guides && isNonEmptyContainer(elem) {
+				w.b.WriteString(w.key(w.elementLabel(i)))
+				w.b.WriteString("\n")
+				w.writeChildren(elem, "")
+				continue
+			}
+			if marker := w.opts.Bullet; marker != "" {
+				w.b.WriteString(w.guide(marker))
+				w.b.WriteString(" ")
 			}
+			w.b.WriteString(w.preview(elem))
+			w.b.WriteString("\n")
 		}
-	} else {
-		isHomogeneousObjects = false
+		return
 	}
 
-	if isHomogeneousObjects {
-		// Array of objects: show each with index header and indented properties
-		for i, elem := range arr {
-			if i > 0 {
-				b.WriteString("\n")
-			}
+	// Array of objects: show each with index header and indented properties
+	for i, elem := range arr {
+		if i > 0 {
+			w.b.WriteString("\n")
+		}
 
-			// Header for this element (skip if style is "none")
-			headerStr := FormatArrayIndex(i, opts.ArrayStyle)
-			if headerStr != "" {
-				if !opts.NoColor {
-					headerStr = headerStyle.Render(headerStr)
-				}
-				b.WriteString(headerStr)
-				b.WriteString("\n")
+		// Header for this element (skip if style is "none")
+		headerStr := w.arrayIndex(i)
+		if headerStr != "" {
+			if !w.opts.NoColor {
+				headerStr = headerStyle.Render(headerStr)
 			}
+			w.b.WriteString(headerStr)
+			w.b.WriteString("\n")
+		}
 
-			// Indented properties (only indent when there's a visible header)
-			indent := "  "
-			if headerStr == "" {
-				indent = ""
-			}
-			if m, ok := elem.(map[string]interface{}); ok {
-				b.WriteString(formatMapAsList(m, indent, opts.NoColor, opts.ColumnOrder, opts.HiddenColumns))
-			}
+		// Indented properties (only indent when there's a visible header)
+		indent := "  "
+		if headerStr == "" {
+			indent = ""
 		}
-	} else {
-		// Array of scalars or mixed types: print each on its own line (same as table)
-		for _, elem := range arr {
-			b.WriteString(StringifyPreserveNewlines(elem))
-			b.WriteString("\n")
+		if m, ok := elem.(map[string]interface{}); ok {
+			w.writeMap(m, indent)
 		}
 	}
-
-	return b.String()
 }
 
-func formatMapAsList(m map[string]interface{}, indent string, noColor bool, columnOrder []string, hiddenColumns ...[]string) string {
+// writeMap writes the top-level properties of an object, honoring
+// ColumnOrder and HiddenColumns.
+func (w *listWriter) writeMap(m map[string]interface{}, indent string) {
 	if len(m) == 0 {
-		return ""
+		return
 	}
 
-	var b strings.Builder
-
 	// Get keys ordered by columnOrder (falls back to alphabetical when empty)
-	keys := orderedMapKeys(m, columnOrder)
+	keys := orderedMapKeys(m, w.opts.ColumnOrder, keyorder.Active())
 
 	// Filter out hidden columns when provided
-	if len(hiddenColumns) > 0 && len(hiddenColumns[0]) > 0 {
-		hidden := make(map[string]bool, len(hiddenColumns[0]))
-		for _, col := range hiddenColumns[0] {
+	if len(w.opts.HiddenColumns) > 0 {
+		hidden := make(map[string]bool, len(w.opts.HiddenColumns))
+		for _, col := range w.opts.HiddenColumns {
 			hidden[col] = true
 		}
 		filtered := make([]string, 0, len(keys))
@@ -128,29 +138,137 @@ func formatMapAsList(m map[string]interface{}, indent string, noColor bool, colu
 		keys = filtered
 	}
 
-	for i, key := range keys {
-		if i > 0 {
-			b.WriteString("\n")
+	for _, key := range keys {
+		val := m[key]
+		w.b.WriteString(w.key(indent + key))
+		if w.opts.Guides && isNonEmptyContainer(val) {
+			w.b.WriteString("\n")
+			w.writeChildren(val, indent)
+			continue
 		}
+		w.b.WriteString(": ")
+		w.b.WriteString(w.value(val))
+		w.b.WriteString("\n")
+	}
+}
 
-		keyStr := indent + key
-		if !noColor {
-			keyStr = keyStyle.Render(keyStr)
+// writeChildren writes the entries of a nested map or array below its key,
+// each prefixed by prefix and a guide connecting it to its parent.
+func (w *listWriter) writeChildren(node interface{}, prefix string) {
+	type entry struct {
+		label  string
+		bullet bool
+		value  interface{}
+	}
+	var entries []entry
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for _, key := range getSortedKeys(v) {
+			entries = append(entries, entry{label: key, value: v[key]})
+		}
+	case []interface{}:
+		for i, elem := range v {
+			if isNonEmptyContainer(elem) {
+				entries = append(entries, entry{label: w.elementLabel(i), value: elem})
+			} else {
+				entries = append(entries, entry{label: w.opts.Bullet, bullet: true, value: elem})
+			}
 		}
+	}
 
-		val := m[key]
-		valStr := StringifyPreserveNewlines(val)
-		if !noColor {
-			valStr = valueStyle.Render(valStr)
+	for i, e := range entries {
+		connector, continuation := listGuideBranch, listGuidePipe
+		if i == len(entries)-1 {
+			connector, continuation = listGuideLast, listGuideSpace
 		}
+		w.b.WriteString(w.guide(prefix + connector))
+		switch {
+		case isNonEmptyContainer(e.value):
+			w.b.WriteString(w.key(e.label))
+			w.b.WriteString("\n")
+			w.writeChildren(e.value, prefix+continuation)
+			continue
+		case e.bullet && e.label != "":
+			w.b.WriteString(w.guide(e.label))
+			w.b.WriteString(" ")
+		case !e.bullet:
+			w.b.WriteString(w.key(e.label))
+			w.b.WriteString(": ")
+		}
+		w.b.WriteString(w.value(e.value))
+		w.b.WriteString("\n")
+	}
+}
 
-		b.WriteString(keyStr)
-		b.WriteString(": ")
-		b.WriteString(valStr)
+// arrayIndex returns the header of array element i, using Bullet as the
+// marker of the "bullet" style when set.
+func (w *listWriter) arrayIndex(i int) string {
+	if w.opts.ArrayStyle == "bullet" && w.opts.Bullet != "" {
+		return w.opts.Bullet
 	}
+	return FormatArrayIndex(i, w.opts.ArrayStyle)
+}
 
-	b.WriteString("\n")
-	return b.String()
+// elementLabel labels a nested container element; with no index style it
+// falls back to the tree's "(item)" placeholder.
+func (w *listWriter) elementLabel(i int) string {
+	return formatKeyOnly(w.arrayIndex(i))
+}
+
+// preview returns the display text of a value, truncated to MaxValueLen.
+func (w *listWriter) preview(v interface{}) string {
+	s := StringifyPreserveNewlines(v)
+	if w.opts.MaxValueLen > 0 {
+		s = truncate(escapeScalarString(s), w.opts.MaxValueLen)
+	}
+	return s
+}
+
+func (w *listWriter) key(s string) string {
+	if w.opts.NoColor {
+		return s
+	}
+	return keyStyle.Render(s)
+}
+
+func (w *listWriter) value(v interface{}) string {
+	s := w.preview(v)
+	if w.opts.NoColor {
+		return s
+	}
+	return valueStyle.Render(s)
+}
+
+func (w *listWriter) guide(s string) string {
+	if w.opts.NoColor {
+		return s
+	}
+	return separatorStyle.Render(s)
+}
+
+// isHomogeneousObjects reports whether arr is non-empty and holds only objects.
+func isHomogeneousObjects(arr []interface{}) bool {
+	if len(arr) == 0 {
+		return false
+	}
+	for _, item := range arr {
+		if !isObjectType(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNonEmptyContainer reports whether v is a map or array with entries.
+func isNonEmptyContainer(v interface{}) bool {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		return len(c) > 0
+	case []interface{}:
+		return len(c) > 0
+	default:
+		return false
+	}
 }
 
 // isObjectType returns true if v is a map[string]interface{} (representing JSON object)
@@ -159,23 +277,18 @@ func isObjectType(v interface{}) bool {
 	return ok
 }
 
-// getSortedKeys returns sorted keys from a map
+// getSortedKeys returns the keys of a map in the active key order
+// (alphabetical unless another order was selected with --sort).
 func getSortedKeys(m map[string]interface{}) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-
-	slices.Sort(keys)
-	return keys
+	return keyorder.Keys(m)
 }
 
 // orderedMapKeys returns map keys ordered by columnOrder first, then remaining
-// keys in alphabetical order. Keys in columnOrder that do not exist in the map
+// keys in the order of s. Keys in columnOrder that do not exist in the map
 // are skipped. When columnOrder is nil or empty, all keys are returned sorted.
-func orderedMapKeys(m map[string]any, columnOrder []string) []string {
+func orderedMapKeys(m map[string]any, columnOrder []string, s keyorder.Sorter) []string {
 	if len(columnOrder) == 0 {
-		return getSortedKeys(m)
+		return s.Keys(m)
 	}
 
 	result := make([]string, 0, len(m))
@@ -191,13 +304,11 @@ func orderedMapKeys(m map[string]any, columnOrder []string) []string {
 		}
 	}
 
-	remaining := make([]string, 0, len(m))
-	for k := range m {
+	for _, k := range s.Keys(m) {
 		if !used[k] {
-			remaining = append(remaining, k)
+			result = append(result, k)
 		}
 	}
-	slices.Sort(remaining)
 
-	return append(result, remaining...)
+	return result
 }
@@ -277,3 +277,81 @@ func TestFormatAsListArrayOfObjectsWithColumnOrder(t *testing.T) {
 		t.Fatalf("expected third property to be 'age' (remaining alphabetical), got %q", firstObjLines[2])
 	}
 }
+
+func TestFormatAsListGuides(t *testing.T) {
+	arr := []interface{}{
+		map[string]interface{}{
+			"name":  "web",
+			"ports": []interface{}{80, 443},
+			"meta": map[string]interface{}{
+				"team":   "core",
+				"owners": []interface{}{map[string]interface{}{"id": "a"}},
+			},
+		},
+		map[string]interface{}{"name": "db", "ports": []interface{}{}},
+	}
+	result := FormatAsList(arr, ListOptions{NoColor: true, ArrayStyle: "index", Guides: true})
+	expected := "[0]\n" +
+		"  meta\n" +
+		"  ├─ owners\n" +
+		"  │  └─ [0]\n" +
+		"  │     └─ id: a\n" +
+		"  └─ team: core\n" +
+		"  name: web\n" +
+		"  ports\n" +
+		"  ├─ 80\n" +
+		"  └─ 443\n" +
+		"\n" +
+		"[1]\n" +
+		"  name: db\n" +
+		"  ports: []\n"
+	if result != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestFormatAsListGuidesNoIndexStyle(t *testing.T) {
+	m := map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": 1}}}
+	result := FormatAsList(m, ListOptions{NoColor: true, ArrayStyle: "none", Guides: true})
+	expected := "items\n└─ (item)\n   └─ id: 1\n"
+	if result != expected {
+		t.Fatalf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestFormatAsListBullet(t *testing.T) {
+	result := FormatAsList([]interface{}{"a", "b"}, ListOptions{NoColor: true, Bullet: "-"})
+	if result != "- a\n- b\n" {
+		t.Fatalf("expected bulleted scalars, got %q", result)
+	}
+
+	arr := []interface{}{map[string]interface{}{"name": "Alice"}}
+	result = FormatAsList(arr, ListOptions{NoColor: true, ArrayStyle: "bullet", Bullet: "*"})
+	if result != "*\n  name: Alice\n" {
+		t.Fatalf("expected Bullet to replace the bullet header, got %q", result)
+	}
+}
+
+func TestFormatAsListMaxValueLen(t *testing.T) {
+	m := map[string]interface{}{
+		"desc":  "a long description",
+		"notes": "line one\nline two",
+		"short": "ok",
+	}
+	result := FormatAsList(m, ListOptions{NoColor: true, MaxValueLen: 10})
+	expected := "desc: a long ...\nnotes: line on...\nshort: ok\n"
+	if result != expected {
+		t.Fatalf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestFormatAsListGuidesColor(t *testing.T) {
+	m := map[string]interface{}{"meta": map[string]interface{}{"team": "core"}}
+	result := FormatAsList(m, ListOptions{Guides: true})
+	if !strings.Contains(result, separatorStyle.Render("└─ ")) {
+		t.Fatalf("expected guides in the separator color, got %q", result)
+	}
+	if !strings.Contains(result, keyStyle.Render("team")) {
+		t.Fatalf("expected nested keys in the key color, got %q", result)
+	}
+}
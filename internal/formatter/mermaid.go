@@ -2,8 +2,11 @@ package formatter
 
 import (
 	"fmt"
+	"hash/fnv"
 	"regexp"
 	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // MermaidOptions controls Mermaid diagram output formatting.
@@ -31,14 +34,16 @@ type MermaidOptions struct {
 
 // mermaidBuilder tracks state during diagram generation.
 type mermaidBuilder struct {
-	lines  []string
-	nodeID int
-	opts   MermaidOptions
+	lines []string
+	ids   map[string]bool
+	opts  MermaidOptions
 }
 
 // FormatAsMermaid renders data as a Mermaid flowchart diagram.
 // Maps become nodes with edges to child nodes, arrays show indexed children,
-// and scalar values are displayed as node labels.
+// and scalar values are displayed as node labels. Node IDs are derived from
+// each node's path (see MermaidNodeID), so a node keeps its ID when other
+// parts of the data change and diagrams diff cleanly between runs.
 func FormatAsMermaid(node interface{}, opts MermaidOptions) string {
 	if opts.Direction == "" {
 		opts.Direction = "TD"
@@ -49,20 +54,36 @@ func FormatAsMermaid(node interface{}, opts MermaidOptions) string {
 
 	b := &mermaidBuilder{
 		lines: []string{fmt.Sprintf("graph %s", opts.Direction)},
+		ids:   make(map[string]bool),
 		opts:  opts,
 	}
 
-	rootID := b.nextID()
+	rootID := b.nodeID("_")
 	b.addNode(rootID, "root", "")
-	b.buildMermaid(rootID, node, 0)
+	b.buildMermaid(rootID, "_", node, 0)
 
 	return strings.Join(b.lines, "\n") + "\n"
 }
 
-// nextID generates a unique node identifier.
-func (b *mermaidBuilder) nextID() string {
-	id := fmt.Sprintf("n%d", b.nodeID)
-	b.nodeID++
+// MermaidNodeID returns the Mermaid node ID FormatAsMermaid uses for the
+// node at path, e.g. "_", "_.spec.replicas", "_.items[0]", or
+// `_.labels["app-name"]`: "n" followed by the 32-bit FNV-1a hash of the path
+// in hex. Paths are written like TreeNode.Path, with keys that are not
+// identifiers in quoted brackets.
+func MermaidNodeID(path string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(path))
+	return fmt.Sprintf("n%08x", h.Sum32())
+}
+
+// nodeID returns the ID of the node at path, adding a numeric suffix in the
+// unlikely case of a hash collision within the diagram.
+func (b *mermaidBuilder) nodeID(path string) string {
+	id := MermaidNodeID(path)
+	for i := 2; b.ids[id]; i++ {
+		id = fmt.Sprintf("%s_%d", MermaidNodeID(path), i)
+	}
+	b.ids[id] = true
 	return id
 }
 
@@ -103,10 +124,10 @@ func (b *mermaidBuilder) escapeLabel(key, value string) string {
 }
 
 // buildMermaid recursively builds the diagram structure.
-func (b *mermaidBuilder) buildMermaid(parentID string, node interface{}, depth int) {
+func (b *mermaidBuilder) buildMermaid(parentID, path string, node interface{}, depth int) {
 	// Check depth limit
 	if b.opts.MaxDepth > 0 && depth >= b.opts.MaxDepth {
-		ellipsisID := b.nextID()
+		ellipsisID := b.nodeID(path + "/...")
 		b.addNode(ellipsisID, "...", "")
 		b.addEdge(parentID, ellipsisID)
 		return
@@ -114,14 +135,14 @@ func (b *mermaidBuilder) buildMermaid(parentID string, node interface{}, depth i
 
 	switch v := node.(type) {
 	case map[string]interface{}:
-		b.buildMapMermaid(parentID, v, depth)
+		b.buildMapMermaid(parentID, path, v, depth)
 	case []interface{}:
-		b.buildArrayMermaid(parentID, v, depth)
+		b.buildArrayMermaid(parentID, path, v, depth)
 	default:
 		// Scalar value - add as child node with the value
 		// Always show scalar roots even with NoValues since there's nothing else to display
 		if node != nil {
-			childID := b.nextID()
+			childID := b.nodeID(path + "/value")
 			valStr := formatScalarSimple(node)
 			b.addNode(childID, valStr, "")
 			b.addEdge(parentID, childID)
@@ -130,7 +151,7 @@ func (b *mermaidBuilder) buildMermaid(parentID string, node interface{}, depth i
 }
 
 // buildMapMermaid adds map entries as child nodes.
-func (b *mermaidBuilder) buildMapMermaid(parentID string, m map[string]interface{}, depth int) {
+func (b *mermaidBuilder) buildMapMermaid(parentID, path string, m map[string]interface{}, depth int) {
 	if len(m) == 0 {
 		return
 	}
@@ -139,12 +160,12 @@ func (b *mermaidBuilder) buildMapMermaid(parentID string, m map[string]interface
 
 	for _, key := range keys {
 		val := m[key]
-		b.addNodeForValue(parentID, key, val, depth)
+		b.addNodeForValue(parentID, childPath(path, key), key, val, depth)
 	}
 }
 
 // buildArrayMermaid adds array elements as child nodes.
-func (b *mermaidBuilder) buildArrayMermaid(parentID string, arr []interface{}, depth int) {
+func (b *mermaidBuilder) buildArrayMermaid(parentID, path string, arr []interface{}, depth int) {
 	if len(arr) == 0 {
 		return
 	}
@@ -153,13 +174,13 @@ func (b *mermaidBuilder) buildArrayMermaid(parentID string, arr []interface{}, d
 	if isScalarArray(arr) && !b.opts.ExpandArrays {
 		if len(arr) <= b.opts.MaxArrayInline {
 			// Show inline
-			childID := b.nextID()
+			childID := b.nodeID(path + "/value")
 			inlineVal := formatInlineArray(arr)
 			b.addNode(childID, inlineVal, "")
 			b.addEdge(parentID, childID)
 		} else {
 			// Show summary
-			childID := b.nextID()
+			childID := b.nodeID(path + "/value")
 			b.addNode(childID, fmt.Sprintf("[%d items]", len(arr)), "")
 			b.addEdge(parentID, childID)
 		}
@@ -169,15 +190,15 @@ func (b *mermaidBuilder) buildArrayMermaid(parentID string, arr []interface{}, d
 	// Expand array elements
 	for i, elem := range arr {
 		indexLabel := FormatArrayIndex(i, b.opts.ArrayStyle)
-		b.addNodeForValue(parentID, indexLabel, elem, depth)
+		b.addNodeForValue(parentID, indexPath(path, i), indexLabel, elem, depth)
 	}
 }
 
 // addNodeForValue adds a node for a key-value pair.
-func (b *mermaidBuilder) addNodeForValue(parentID, key string, val interface{}, depth int) {
+func (b *mermaidBuilder) addNodeForValue(parentID, path, key string, val interface{}, depth int) {
 	// Check depth limit before adding complex children
 	if b.opts.MaxDepth > 0 && depth >= b.opts.MaxDepth {
-		ellipsisID := b.nextID()
+		ellipsisID := b.nodeID(path)
 		b.addNode(ellipsisID, "...", "")
 		b.addEdge(parentID, ellipsisID)
 		return
@@ -185,20 +206,20 @@ func (b *mermaidBuilder) addNodeForValue(parentID, key string, val interface{},
 
 	switch v := val.(type) {
 	case map[string]interface{}:
-		childID := b.nextID()
+		childID := b.nodeID(path)
 		b.addNode(childID, key, "")
 		b.addEdge(parentID, childID)
-		b.buildMapMermaid(childID, v, depth+1)
+		b.buildMapMermaid(childID, path, v, depth+1)
 
 	case []interface{}:
-		childID := b.nextID()
+		childID := b.nodeID(path)
 		b.addNode(childID, key, "")
 		b.addEdge(parentID, childID)
-		b.buildArrayMermaid(childID, v, depth+1)
+		b.buildArrayMermaid(childID, path, v, depth+1)
 
 	default:
 		// Scalar value
-		childID := b.nextID()
+		childID := b.nodeID(path)
 		scalarVal := b.formatScalar(key, v)
 		b.addNode(childID, key, scalarVal)
 		b.addEdge(parentID, childID)
@@ -226,11 +247,11 @@ func (b *mermaidBuilder) formatScalar(key string, v interface{}) string {
 		return s
 	}
 
-	if len(s) > maxLen {
+	if textwidth.Width(s) > maxLen {
 		if maxLen <= 3 {
 			return "..."
 		}
-		return s[:maxLen-3] + "..."
+		return textwidth.Truncate(s, maxLen, "...")
 	}
 	return s
 }
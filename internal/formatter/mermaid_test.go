@@ -251,3 +251,52 @@ func TestSanitizeMermaidID(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatAsMermaid_StableNodeIDs(t *testing.T) {
+	before := map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 3},
+	}
+	after := map[string]interface{}{
+		"apiVersion": "v1",
+		"spec":       map[string]interface{}{"replicas": 5},
+	}
+
+	first := FormatAsMermaid(before, MermaidOptions{})
+	assert.Equal(t, first, FormatAsMermaid(before, MermaidOptions{}))
+
+	second := FormatAsMermaid(after, MermaidOptions{})
+	for _, path := range []string{"_", "_.spec", "_.spec.replicas"} {
+		id := MermaidNodeID(path)
+		assert.Contains(t, first, "    "+id+"[")
+		assert.Contains(t, second, "    "+id+"[")
+	}
+	assert.Contains(t, second, MermaidNodeID("_.spec")+` --> `+MermaidNodeID("_.spec.replicas"))
+	assert.Contains(t, second, MermaidNodeID("_.spec.replicas")+`["replicas: 5"]`)
+}
+
+func TestMermaidNodeID(t *testing.T) {
+	id := MermaidNodeID("_.items[0]")
+	assert.Regexp(t, `^n[0-9a-f]{8}$`, id)
+	assert.Equal(t, id, MermaidNodeID("_.items[0]"))
+	assert.NotEqual(t, id, MermaidNodeID("_.items[1]"))
+}
+
+func TestFormatAsMermaid_NodeIDsMatchTreePaths(t *testing.T) {
+	data := map[string]interface{}{
+		"metadata": map[string]interface{}{"app-name": "web"},
+		"items":    []interface{}{map[string]interface{}{"name": "a"}},
+	}
+	var paths []string
+	FormatAsTree(data, TreeOptions{
+		Filter: func(n TreeNode) bool {
+			paths = append(paths, n.Path)
+			return true
+		},
+	})
+	assert.Contains(t, paths, `_.metadata["app-name"]`)
+
+	out := FormatAsMermaid(data, MermaidOptions{})
+	for _, path := range paths {
+		assert.Contains(t, out, "    "+MermaidNodeID(path)+"[", "no Mermaid node for %s", path)
+	}
+}
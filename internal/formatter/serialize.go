@@ -0,0 +1,59 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// SerializeOptions control the layout of JSON and YAML output shared by
+// -o json and -o yaml (--indent, --sort-keys, --compact,
+// --no-trailing-newline). The zero value writes indented output with a
+// trailing newline and keys in the active key order.
+type SerializeOptions struct {
+	// Indent is the number of spaces per nesting level (default 2).
+	Indent int
+	// SortKeys overrides the active key order when set: true sorts object
+	// keys alphabetically, false keeps the order they had in the input
+	// (maps without a recorded order are sorted).
+	SortKeys *bool
+	// Compact writes JSON on a single line. YAML output is not affected.
+	Compact bool
+	// NoTrailingNewline leaves out the newline that ends the output.
+	NoTrailingNewline bool
+}
+
+// indent returns the indentation of one nesting level.
+func (o SerializeOptions) indent() int {
+	if o.Indent <= 0 {
+		return 2
+	}
+	return o.Indent
+}
+
+// jsonIndent returns the indent string for encoding/json.
+func (o SerializeOptions) jsonIndent() string {
+	return strings.Repeat(" ", o.indent())
+}
+
+// useKeyOrder applies SortKeys to the active key order and returns a
+// function restoring the previous order.
+func (o SerializeOptions) useKeyOrder() func() {
+	if o.SortKeys == nil {
+		return func() {}
+	}
+	s := keyorder.Active()
+	s.Mode = keyorder.Insertion
+	if *o.SortKeys {
+		s.Mode = keyorder.Ascending
+	}
+	return keyorder.Use(s)
+}
+
+// finish applies NoTrailingNewline to rendered output ending in a newline.
+func (o SerializeOptions) finish(s string) string {
+	if o.NoTrailingNewline {
+		return strings.TrimSuffix(s, "\n")
+	}
+	return s
+}
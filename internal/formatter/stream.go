@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"io"
+)
+
+// WriteJSON writes v to w exactly as FormatJSON renders it. The elements of
+// a top-level array are encoded and written one at a time, so the first
+// bytes of a large result are written early and the whole document is never
+// held in memory as a single string.
+func WriteJSON(w io.Writer, v interface{}, opts SerializeOptions) error {
+	arr, ok := v.([]interface{})
+	if !ok || len(arr) == 0 {
+		s, err := FormatJSON(v, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	}
+	defer opts.useKeyOrder()()
+	open, sep, end, indent := "[\n", ",\n", "\n", opts.jsonIndent()
+	if opts.Compact {
+		open, sep, end, indent = "[", ",", "", ""
+	}
+	if _, err := io.WriteString(w, open); err != nil {
+		return err
+	}
+	for i, e := range arr {
+		b, err := marshalJSON(jsonValue(e), indent, opts)
+		if err != nil {
+			return err
+		}
+		next := sep
+		if i == len(arr)-1 {
+			next = end
+		}
+		if _, err := io.WriteString(w, indent+string(b)+next); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, opts.finish("]\n"))
+	return err
+}
+
+// WriteYAML writes v to w exactly as FormatYAML renders it, streaming the
+// items of a top-level sequence one at a time like WriteJSON.
+func WriteYAML(w io.Writer, v interface{}, opts YAMLFormatOptions) error {
+	arr, ok := v.([]interface{})
+	streamed := ok && len(arr) > 0 && !(opts.PreserveSource && len(opts.Source) > 0)
+	if !streamed {
+		s, err := FormatYAML(v, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	}
+	defer opts.useKeyOrder()()
+	item := opts
+	item.SortKeys = nil // already applied
+	for i, e := range arr {
+		// A one-item sequence renders as the "- item" lines the item gets
+		// in the full sequence.
+		item.NoTrailingNewline = opts.NoTrailingNewline && i == len(arr)-1
+		s, err := FormatYAML([]interface{}{e}, item)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
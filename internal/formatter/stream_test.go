@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var streamSamples = []any{
+	[]any{},
+	[]any{1, "two", nil},
+	[]any{
+		map[string]any{"b": "x\ny", "a": []any{1, map[string]any{"z": true}}},
+		[]any{[]any{}, map[string]any{}},
+		"<tag>",
+		[]byte{0x89, 0x50},
+	},
+	map[string]any{"k": []any{1, 2}},
+	"scalar",
+}
+
+func TestWriteJSON_MatchesFormatJSON(t *testing.T) {
+	for _, opts := range []SerializeOptions{{}, {Indent: 4}, {Compact: true}, {NoTrailingNewline: true}, {Compact: true, NoTrailingNewline: true}} {
+		for _, v := range streamSamples {
+			want, err := FormatJSON(v, opts)
+			require.NoError(t, err)
+			var got strings.Builder
+			require.NoError(t, WriteJSON(&got, v, opts))
+			assert.Equal(t, want, got.String())
+		}
+	}
+}
+
+func TestWriteJSON_InsertionOrder(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Insertion)
+	defer keyorder.SetMode(prev)
+	defer keyorder.SetDocument(nil)
+
+	item := map[string]any{"y": 1, "x": 2}
+	order := keyorder.NewOrder()
+	order.Record(item, []string{"y", "x"})
+	keyorder.SetDocument(order)
+	var got strings.Builder
+	require.NoError(t, WriteJSON(&got, []any{item}, SerializeOptions{}))
+	assert.Equal(t, "[\n  {\n    \"y\": 1,\n    \"x\": 2\n  }\n]\n", got.String())
+}
+
+func TestWriteYAML_MatchesFormatYAML(t *testing.T) {
+	for _, opts := range []YAMLFormatOptions{
+		{},
+		{SerializeOptions: SerializeOptions{Indent: 4}, LiteralBlockStrings: true},
+		{SerializeOptions: SerializeOptions{NoTrailingNewline: true}},
+	} {
+		for _, v := range streamSamples {
+			want, err := FormatYAML(v, opts)
+			require.NoError(t, err)
+			var got strings.Builder
+			require.NoError(t, WriteYAML(&got, v, opts))
+			assert.Equal(t, want, got.String())
+		}
+	}
+}
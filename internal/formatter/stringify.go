@@ -0,0 +1,168 @@
+package formatter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// StringifyOptions adjusts StringifyWith. The zero value renders values
+// exactly like Stringify.
+type StringifyOptions struct {
+	// MaxDepth limits how many levels of nested maps and lists are written
+	// out; deeper ones are summarized as "{N keys}" and "[N items]".
+	// 0 writes every level.
+	MaxDepth int
+
+	// MaxLen truncates the result to this many display cells, ending it
+	// with the ellipsis. 0 leaves it whole.
+	MaxLen int
+
+	// FloatFormat is the fmt format of floating-point numbers, e.g. "%.2f".
+	// Empty uses the shortest representation, e.g. 1.5 or 1e+06.
+	FloatFormat string
+
+	// NullText is the text of a null value. Null values inside maps and
+	// lists stay null.
+	NullText string
+}
+
+// StringifyWith returns the display text of a value like Stringify, adjusted
+// by opts: scalars as text, maps and lists as compact JSON.
+func StringifyWith(v any, opts StringifyOptions) string {
+	var s string
+	switch {
+	case v == nil:
+		s = opts.NullText
+	case opts.MaxDepth <= 0 && opts.FloatFormat == "":
+		s = Stringify(v)
+	default:
+		s = stringifyValue(v, opts)
+	}
+	if opts.MaxLen > 0 {
+		s = TruncateCell(s, opts.MaxLen)
+	}
+	return s
+}
+
+// stringifyValue renders v with the depth and float options of opts.
+func stringifyValue(v any, opts StringifyOptions) string {
+	if _, ok := BinaryBytes(v); ok {
+		return Stringify(v)
+	}
+	switch t := v.(type) {
+	case string:
+		return escapeScalarString(t)
+	case float64, float32:
+		return formatFloat(t, opts.FloatFormat)
+	case map[string]any, []any:
+		var b strings.Builder
+		writeCompact(&b, t, 1, opts)
+		return b.String()
+	}
+	if plain, ok := plainValue(v); ok {
+		return stringifyValue(plain, opts)
+	}
+	return Stringify(v)
+}
+
+// writeCompact writes v as compact JSON, summarizing maps and lists nested
+// deeper than MaxDepth and formatting floats with FloatFormat.
+func writeCompact(b *strings.Builder, v any, depth int, opts StringifyOptions) {
+	if data, ok := BinaryBytes(v); ok {
+		writeJSONString(b, base64.StdEncoding.EncodeToString(data))
+		return
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			fmt.Fprintf(b, "{%d keys}", len(t))
+			return
+		}
+		keys := slices.Sorted(maps.Keys(t))
+		if customKeyOrder() {
+			keys = keyorder.Keys(t)
+		}
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, k)
+			b.WriteByte(':')
+			writeCompact(b, t[k], depth+1, opts)
+		}
+		b.WriteByte('}')
+	case []any:
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			fmt.Fprintf(b, "[%d items]", len(t))
+			return
+		}
+		b.WriteByte('[')
+		for i, e := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCompact(b, e, depth+1, opts)
+		}
+		b.WriteByte(']')
+	case float64, float32:
+		b.WriteString(formatFloat(t, opts.FloatFormat))
+	default:
+		if plain, ok := plainValue(v); ok {
+			writeCompact(b, plain, depth, opts)
+			return
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			data, _ = json.Marshal(fmt.Sprint(v))
+		}
+		b.Write(data)
+	}
+}
+
+func writeJSONString(b *strings.Builder, s string) {
+	data, _ := json.Marshal(s)
+	b.Write(data)
+}
+
+// formatFloat formats f with format, or like Stringify when it is empty.
+func formatFloat(f any, format string) string {
+	if format == "" {
+		return fmt.Sprint(f)
+	}
+	return fmt.Sprintf(format, f)
+}
+
+// plainValue converts structs, typed maps, and typed slices to the maps and
+// lists of decoded JSON, so the options apply to their fields too.
+func plainValue(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() { //nolint:exhaustive // only containers are converted
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		return nil, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var plain any
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return nil, false
+	}
+	switch plain.(type) {
+	case map[string]any, []any:
+		return plain, true
+	}
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Built-in column aggregates for table summary rows. Any other aggregate is
+// treated as an expression and handed to the SummaryEvaluator.
+const (
+	SummaryCount = "count" // number of non-empty cells
+	SummarySum   = "sum"   // sum of the numeric cells
+	SummaryAvg   = "avg"   // mean of the numeric cells, rounded to two decimals
+	SummaryMin   = "min"   // smallest numeric cell
+	SummaryMax   = "max"   // largest numeric cell
+)
+
+// SummaryLabel marks the summary row in the row number column.
+const SummaryLabel = "Σ"
+
+// SummaryEvaluator evaluates a custom aggregate expression against the data
+// being rendered, e.g. a CEL expression over '_'.
+type SummaryEvaluator func(expr string, data interface{}) (interface{}, error)
+
+// IsBuiltinSummary reports whether agg is one of the built-in aggregates.
+func IsBuiltinSummary(agg string) bool {
+	switch agg {
+	case SummaryCount, SummarySum, SummaryAvg, SummaryMin, SummaryMax:
+		return true
+	default:
+		return false
+	}
+}
+
+// SummaryRow computes a table footer with one cell per column. spec maps
+// column names to an aggregate; columns without one get an empty cell.
+// Built-in aggregates read the rendered cells, so numbers of any type are
+// summed alike and non-numeric cells are skipped. Other aggregates are
+// evaluated with eval against data; when eval is nil or fails the cell shows
+// "error" and the failures are returned joined.
+func SummaryRow(columns []string, rows [][]string, data interface{}, spec map[string]string, eval SummaryEvaluator) ([]string, error) {
+	if len(spec) == 0 {
+		return nil, nil
+	}
+	cells := make([]string, len(columns))
+	var errs []error
+	for i, col := range columns {
+		agg, ok := spec[col]
+		if !ok {
+			continue
+		}
+		agg = strings.TrimSpace(agg)
+		if IsBuiltinSummary(strings.ToLower(agg)) {
+			cells[i] = aggregateColumn(strings.ToLower(agg), rows, i)
+			continue
+		}
+		if eval == nil {
+			cells[i] = "error"
+			errs = append(errs, fmt.Errorf("summary %s: unknown aggregate %q", col, agg))
+			continue
+		}
+		v, err := eval(agg, data)
+		if err != nil {
+			cells[i] = "error"
+			errs = append(errs, fmt.Errorf("summary %s: %w", col, err))
+			continue
+		}
+		cells[i] = Stringify(v)
+	}
+	return cells, errors.Join(errs...)
+}
+
+// aggregateColumn applies a built-in aggregate to column i of rows.
+func aggregateColumn(agg string, rows [][]string, i int) string {
+	count, n := 0, 0
+	var sum, lo, hi float64
+	for _, row := range rows {
+		if i >= len(row) || strings.TrimSpace(row[i]) == "" {
+			continue
+		}
+		count++
+		f, err := strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+		if err != nil || math.IsNaN(f) {
+			continue
+		}
+		if n == 0 || f < lo {
+			lo = f
+		}
+		if n == 0 || f > hi {
+			hi = f
+		}
+		sum += f
+		n++
+	}
+
+	if agg == SummaryCount {
+		return strconv.Itoa(count)
+	}
+	if n == 0 {
+		return ""
+	}
+	switch agg {
+	case SummarySum:
+		return formatSummaryNumber(sum)
+	case SummaryAvg:
+		return formatSummaryNumber(math.Round(sum/float64(n)*100) / 100)
+	case SummaryMin:
+		return formatSummaryNumber(lo)
+	default:
+		return formatSummaryNumber(hi)
+	}
+}
+
+// formatSummaryNumber prints f without an exponent, rounding away float
+// noise such as 0.1+0.2 = 0.30000000000000004.
+func formatSummaryNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e9)/1e9, 'f', -1, 64)
+}
@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryRow_Builtins(t *testing.T) {
+	columns := []string{"name", "price", "qty", "note"}
+	rows := [][]string{
+		{"a", "0.1", "2", ""},
+		{"b", "0.2", "3", "x"},
+		{"c", "n/a", "4", ""},
+	}
+	spec := map[string]string{"name": "count", "price": "sum", "qty": "AVG", "note": "max"}
+
+	cells, err := SummaryRow(columns, rows, nil, spec, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "0.3", "3", ""}, cells)
+
+	cells, err = SummaryRow(columns, rows, nil, map[string]string{"qty": "min", "price": "max"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "0.2", "2", ""}, cells)
+}
+
+func TestSummaryRow_NoSpec(t *testing.T) {
+	cells, err := SummaryRow([]string{"a"}, [][]string{{"1"}}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cells)
+}
+
+func TestSummaryRow_Expressions(t *testing.T) {
+	data := []interface{}{"x", "y"}
+	eval := func(expr string, d interface{}) (interface{}, error) {
+		if expr == "size(_)" {
+			return len(d.([]interface{})), nil //nolint:forcetypeassert
+		}
+		return nil, errors.New("bad expression")
+	}
+
+	cells, err := SummaryRow([]string{"a", "b"}, [][]string{{"1", "2"}}, data, map[string]string{"a": "size(_)", "b": "oops("}, eval)
+	assert.Equal(t, []string{"2", "error"}, cells)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summary b: bad expression")
+
+	_, err = SummaryRow([]string{"a"}, [][]string{{"1"}}, data, map[string]string{"a": "median"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown aggregate "median"`)
+}
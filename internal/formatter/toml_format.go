@@ -1,7 +1,10 @@
 package formatter
 
 import (
+	"encoding/json"
+	"maps"
 	"reflect"
+	"slices"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -20,9 +23,9 @@ func FormatTOML(v any) (string, error) {
 		return "", nil
 	}
 
-	data := v
-	if isSliceOrArrayKind(v) {
-		data = map[string]any{"items": deref(v)}
+	data, _ := tomlIntegers(encodeBinary(v))
+	if isSliceOrArrayKind(data) {
+		data = map[string]any{"items": deref(data)}
 	}
 
 	b, err := toml.Marshal(data)
@@ -33,6 +36,46 @@ func FormatTOML(v any) (string, error) {
 	return string(b), nil
 }
 
+// tomlIntegers replaces json.Number integers that fit int64 with int64 so
+// they are written as TOML integers. Other json.Number values have no exact
+// TOML form and are written as strings. Maps and slices are copied only when
+// something in them is replaced.
+func tomlIntegers(v any) (any, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, true
+		}
+	case map[string]any:
+		var out map[string]any
+		for k, e := range t {
+			if c, changed := tomlIntegers(e); changed {
+				if out == nil {
+					out = maps.Clone(t)
+				}
+				out[k] = c
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case []any:
+		var out []any
+		for i, e := range t {
+			if c, changed := tomlIntegers(e); changed {
+				if out == nil {
+					out = slices.Clone(t)
+				}
+				out[i] = c
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	}
+	return v, false
+}
+
 // isSliceOrArrayKind reports whether v is a slice or array type.
 // It unwraps pointers and interfaces before checking the final kind.
 func isSliceOrArrayKind(v any) bool {
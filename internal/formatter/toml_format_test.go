@@ -1,6 +1,7 @@
 package formatter
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -67,3 +68,17 @@ func TestFormatTOML_PointerToSlice(t *testing.T) {
 	assert.Contains(t, out, "[[items]]")
 	assert.Regexp(t, `(?m)^name\s*=\s*["']alice["']\s*$`, out)
 }
+
+func TestFormatTOML_JSONNumbers(t *testing.T) {
+	data := map[string]any{
+		"id":    json.Number("9007199254740993"),
+		"price": json.Number("12345678901234567.891"),
+		"ids":   []any{json.Number("9007199254740995")},
+	}
+	out, err := FormatTOML(data)
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^id\s*=\s*9007199254740993$`, out)
+	assert.Regexp(t, `(?m)^ids\s*=\s*\[9007199254740995\]$`, out)
+	assert.Regexp(t, `(?m)^price\s*=\s*'12345678901234567.891'$`, out)
+	assert.Equal(t, json.Number("9007199254740993"), data["id"], "input is not modified")
+}
@@ -2,8 +2,11 @@ package formatter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 	"github.com/xlab/treeprint"
 )
 
@@ -31,6 +34,31 @@ type TreeOptions struct {
 	// ArrayStyle controls how array indices are displayed:
 	// "index" = [0], [1]; "numbered" = 1, 2; "bullet" = •; "none" = skip index.
 	ArrayStyle string
+	// Filter, when set, is called for every map entry and array element
+	// before it is added; returning false hides the node and its children.
+	Filter func(node TreeNode) bool
+	// Label, when set, returns the label shown for a node in place of its
+	// key or array index. Return node.Label to keep the default.
+	Label func(node TreeNode) string
+}
+
+// TreeNode describes a map entry or array element passed to the
+// TreeOptions callbacks.
+type TreeNode struct {
+	// Path locates the node from the root "_", e.g. "_.items[0].name" or
+	// `_.metadata["app-name"]`, as the navigator writes paths. It is the
+	// path MermaidNodeID takes.
+	Path string
+	// Key is the map key, or "" for array elements.
+	Key string
+	// Index is the array index, or -1 for map entries.
+	Index int
+	// Label is the default label: the key or the formatted array index.
+	Label string
+	// Value is the node's value.
+	Value interface{}
+	// Depth is 0 for children of the root.
+	Depth int
 }
 
 // ValidArrayStyles contains all valid array style values.
@@ -105,9 +133,9 @@ func buildTree(branch treeprint.Tree, node interface{}, opts TreeOptions, depth
 
 	switch v := node.(type) {
 	case map[string]interface{}:
-		buildMapTree(branch, v, opts, depth)
+		buildMapTree(branch, v, "_", opts, depth)
 	case []interface{}:
-		buildArrayTree(branch, v, opts, depth)
+		buildArrayTree(branch, v, "_", opts, depth)
 	default:
 		// Scalar at root level (unusual but handle it)
 		branch.AddNode(formatScalarValue(v, opts))
@@ -115,7 +143,7 @@ func buildTree(branch treeprint.Tree, node interface{}, opts TreeOptions, depth
 }
 
 // buildMapTree adds map entries as tree branches/nodes.
-func buildMapTree(branch treeprint.Tree, m map[string]interface{}, opts TreeOptions, depth int) {
+func buildMapTree(branch treeprint.Tree, m map[string]interface{}, path string, opts TreeOptions, depth int) {
 	if len(m) == 0 {
 		return
 	}
@@ -123,13 +151,13 @@ func buildMapTree(branch treeprint.Tree, m map[string]interface{}, opts TreeOpti
 	keys := getSortedKeys(m)
 
 	for _, key := range keys {
-		val := m[key]
-		addNodeForValue(branch, key, val, opts, depth)
+		node := TreeNode{Path: childPath(path, key), Key: key, Index: -1, Label: key, Value: m[key], Depth: depth}
+		addTreeNode(branch, node, opts)
 	}
 }
 
 // buildArrayTree adds array elements as indexed children.
-func buildArrayTree(branch treeprint.Tree, arr []interface{}, opts TreeOptions, depth int) {
+func buildArrayTree(branch treeprint.Tree, arr []interface{}, path string, opts TreeOptions, depth int) {
 	if len(arr) == 0 {
 		return
 	}
@@ -142,12 +170,52 @@ func buildArrayTree(branch treeprint.Tree, arr []interface{}, opts TreeOptions,
 
 	for i, elem := range arr {
 		indexKey := FormatArrayIndex(i, opts.ArrayStyle)
-		addNodeForValue(branch, indexKey, elem, opts, depth)
+		node := TreeNode{Path: indexPath(path, i), Index: i, Label: indexKey, Value: elem, Depth: depth}
+		addTreeNode(branch, node, opts)
+	}
+}
+
+// addTreeNode applies the Filter and Label callbacks to node and adds it.
+func addTreeNode(branch treeprint.Tree, node TreeNode, opts TreeOptions) {
+	if opts.Filter != nil && !opts.Filter(node) {
+		return
+	}
+	label := node.Label
+	if opts.Label != nil {
+		label = opts.Label(node)
+	}
+	addNodeForValue(branch, label, node.Value, node.Path, opts, node.Depth)
+}
+
+// childPath returns the path of the entry key of the map at path, written
+// the way the navigator writes it: "_.spec" for keys that are identifiers
+// and `_["bad-key"]` for any other key.
+func childPath(path, key string) string {
+	if isIdentifier(key) {
+		return path + "." + key
 	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// indexPath returns the path of element i of the array at path.
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
 }
 
 // addNodeForValue adds a node or branch for a key-value pair.
-func addNodeForValue(branch treeprint.Tree, key string, val interface{}, opts TreeOptions, depth int) {
+func addNodeForValue(branch treeprint.Tree, key string, val interface{}, path string, opts TreeOptions, depth int) {
 	// Check depth limit before recursing
 	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
 		branch.AddNode(formatKeyValue(key, "..."))
@@ -164,11 +232,11 @@ func addNodeForValue(branch treeprint.Tree, key string, val interface{}, opts Tr
 			}
 		} else {
 			child := branch.AddBranch(formatKeyOnly(key))
-			buildMapTree(child, v, opts, depth+1)
+			buildMapTree(child, v, path, opts, depth+1)
 		}
 
 	case []interface{}:
-		addArrayNode(branch, key, v, opts, depth)
+		addArrayNode(branch, key, v, path, opts, depth)
 
 	default:
 		// Scalar value
@@ -181,7 +249,7 @@ func addNodeForValue(branch treeprint.Tree, key string, val interface{}, opts Tr
 }
 
 // addArrayNode handles array nodes with appropriate inline/summary/expand logic.
-func addArrayNode(branch treeprint.Tree, key string, v []interface{}, opts TreeOptions, depth int) {
+func addArrayNode(branch treeprint.Tree, key string, v []interface{}, path string, opts TreeOptions, depth int) {
 	switch {
 	case len(v) == 0:
 		if opts.NoValues {
@@ -206,7 +274,7 @@ func addArrayNode(branch treeprint.Tree, key string, v []interface{}, opts TreeO
 	default:
 		// Complex array or expand mode - create branch with indexed children
 		child := branch.AddBranch(formatKeyOnly(key))
-		buildArrayTree(child, v, opts, depth+1)
+		buildArrayTree(child, v, path, opts, depth+1)
 	}
 }
 
@@ -253,11 +321,11 @@ func formatScalarValueWithKey(key string, v interface{}, opts TreeOptions) strin
 		return s
 	}
 
-	if len(s) > maxLen {
+	if textwidth.Width(s) > maxLen {
 		if maxLen <= 3 {
 			return "..."
 		}
-		return s[:maxLen-3] + "..."
+		return textwidth.Truncate(s, maxLen, "...")
 	}
 	return s
 }
@@ -399,3 +399,85 @@ func TestFormatArrayIndex_AllStyles(t *testing.T) {
 	assert.Equal(t, "", FormatArrayIndex(0, "none"))
 	assert.Equal(t, "[0]", FormatArrayIndex(0, ""))
 }
+
+func TestFormatAsTree_FilterHidesSubtree(t *testing.T) {
+	data := map[string]interface{}{
+		"keep": "yes",
+		"drop": map[string]interface{}{"inner": "x"},
+		"items": []interface{}{
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"name": "b"},
+		},
+	}
+	var paths []string
+	result := FormatAsTree(data, TreeOptions{
+		Filter: func(n TreeNode) bool {
+			paths = append(paths, n.Path)
+			return n.Key != "drop" && n.Path != "_.items[1]"
+		},
+	})
+
+	assert.Contains(t, result, "keep: yes")
+	assert.NotContains(t, result, "drop")
+	assert.NotContains(t, result, "inner")
+	assert.Contains(t, result, "name: a")
+	assert.NotContains(t, result, "name: b")
+	assert.Contains(t, paths, "_.items[0].name")
+	assert.NotContains(t, paths, "_.drop.inner")
+}
+
+func TestFormatAsTree_LabelCallback(t *testing.T) {
+	data := map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 3},
+		"tags": []interface{}{map[string]interface{}{"k": "v"}},
+	}
+	var nodes []TreeNode
+	result := FormatAsTree(data, TreeOptions{
+		Label: func(n TreeNode) string {
+			nodes = append(nodes, n)
+			if n.Path == "_.spec" {
+				return "specification"
+			}
+			if n.Index >= 0 {
+				return "tag #" + n.Label
+			}
+			return n.Label
+		},
+	})
+
+	assert.Contains(t, result, "specification")
+	assert.Contains(t, result, "replicas: 3")
+	assert.Contains(t, result, "tag #[0]")
+	for _, n := range nodes {
+		switch n.Path {
+		case "_.spec.replicas":
+			assert.Equal(t, 1, n.Depth)
+			assert.Equal(t, "replicas", n.Key)
+			assert.Equal(t, -1, n.Index)
+		case "_.tags[0]":
+			assert.Equal(t, 0, n.Index)
+			assert.Equal(t, "", n.Key)
+		}
+	}
+}
+
+func TestFormatAsTree_PathsQuoteKeys(t *testing.T) {
+	data := map[string]interface{}{
+		"labels": map[string]interface{}{"app-name": "web", "": "empty", "9lives": 1, "ok_1": true},
+	}
+	var paths []string
+	FormatAsTree(data, TreeOptions{
+		Filter: func(n TreeNode) bool {
+			paths = append(paths, n.Path)
+			return true
+		},
+	})
+
+	assert.ElementsMatch(t, []string{
+		"_.labels",
+		`_.labels[""]`,
+		`_.labels["9lives"]`,
+		`_.labels["app-name"]`,
+		"_.labels.ok_1",
+	}, paths)
+}
@@ -0,0 +1,106 @@
+package formatter
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Semantic states of a value (see ValueStates). The state picks the color
+// a value is drawn in and the icon shown before it in badges.
+const (
+	StateOK      = "ok"
+	StateInfo    = "info"
+	StateWarning = "warning"
+	StateError   = "error"
+)
+
+// ValidState reports whether s is one of the semantic states.
+func ValidState(s string) bool {
+	switch s {
+	case StateOK, StateInfo, StateWarning, StateError:
+		return true
+	}
+	return false
+}
+
+// StateThreshold maps the numbers below a bound to a state.
+type StateThreshold struct {
+	Below float64 `json:"below"`
+	State string  `json:"state"`
+}
+
+// ValueStates maps the values of a field to semantic states: numbers by
+// thresholds, such as latency below 100 ok, below 300 warning, else error,
+// and text by exact values, such as "failed" error.
+type ValueStates struct {
+	// Thresholds are tried in order; a number takes the state of the first
+	// whose Below it is under, or Else.
+	Thresholds []StateThreshold `json:"thresholds,omitempty"`
+
+	// Else is the state of numbers not below any threshold.
+	Else string `json:"else,omitempty"`
+
+	// Values maps text values to states, matched ignoring case.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// State returns the state of a value's displayed text, or "" when the
+// mapping gives it none.
+func (s *ValueStates) State(text string) string {
+	if s == nil || text == "" {
+		return ""
+	}
+	if state, ok := s.Values[text]; ok {
+		return state
+	}
+	for v, state := range s.Values {
+		if strings.EqualFold(v, text) {
+			return state
+		}
+	}
+	if len(s.Thresholds) == 0 {
+		return ""
+	}
+	n, ok := parseFloatCell(text)
+	if !ok {
+		return ""
+	}
+	for _, t := range s.Thresholds {
+		if n < t.Below {
+			return t.State
+		}
+	}
+	return s.Else
+}
+
+// StateIcon returns the icon shown before badges in state, or "".
+func StateIcon(state string) string {
+	switch state {
+	case StateOK:
+		return "✓"
+	case StateInfo:
+		return "ℹ"
+	case StateWarning:
+		return "⚠"
+	case StateError:
+		return "✗"
+	}
+	return ""
+}
+
+// StateStyle returns the style of values in state, colored by the table
+// theme (see TableColors), and whether state is known.
+func StateStyle(state string) (lipgloss.Style, bool) {
+	switch state {
+	case StateOK:
+		return okStyle, true
+	case StateInfo:
+		return keyStyle, true
+	case StateWarning:
+		return warningStyle, true
+	case StateError:
+		return errorStyle, true
+	}
+	return valueStyle, false
+}
@@ -0,0 +1,40 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueStates(t *testing.T) {
+	latency := &ValueStates{
+		Thresholds: []StateThreshold{{Below: 100, State: StateOK}, {Below: 300, State: StateWarning}},
+		Else:       StateError,
+	}
+	assert.Equal(t, StateOK, latency.State("42"))
+	assert.Equal(t, StateWarning, latency.State("100"))
+	assert.Equal(t, StateError, latency.State("1200"))
+	assert.Empty(t, latency.State("n/a"))
+	assert.Empty(t, latency.State(""))
+
+	status := &ValueStates{Values: map[string]string{"running": StateOK, "Failed": StateError}}
+	assert.Equal(t, StateOK, status.State("running"))
+	assert.Equal(t, StateError, status.State("FAILED"))
+	assert.Empty(t, status.State("pending"))
+	assert.Empty(t, status.State("12"), "no thresholds")
+
+	assert.Empty(t, (*ValueStates)(nil).State("running"))
+}
+
+func TestStateIconAndStyle(t *testing.T) {
+	assert.Equal(t, "✓", StateIcon(StateOK))
+	assert.Equal(t, "✗", StateIcon(StateError))
+	assert.Empty(t, StateIcon("bogus"))
+	assert.True(t, ValidState(StateWarning))
+	assert.False(t, ValidState("bogus"))
+
+	_, ok := StateStyle(StateInfo)
+	assert.True(t, ok)
+	_, ok = StateStyle("")
+	assert.False(t, ok)
+}
@@ -2,26 +2,49 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"strings"
 
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/oakwood-commons/kvx/internal/yamlsource"
 	"gopkg.in/yaml.v3"
 )
 
-// YAMLFormatOptions control YAML rendering.
+// YAMLFormatOptions control YAML rendering. SerializeOptions.Compact does
+// not apply to YAML.
 type YAMLFormatOptions struct {
-	Indent                int
+	SerializeOptions
 	LiteralBlockStrings   bool
 	ExpandEscapedNewlines bool
+	// PreserveSource emits values that come unchanged from the loaded YAML
+	// source with their original comments, anchors, key order, and quoting.
+	// Other values are rendered normally.
+	PreserveSource bool
+	// Source holds the nodes the value was read from (see
+	// yamlsource.Source.Lookup). With PreserveSource they are written in
+	// place of the value; without nodes the value is rendered normally.
+	Source []*yaml.Node
 }
 
 // FormatYAML renders an object to YAML using the provided options. Multi-line
 // strings can be emitted as literal blocks ("|") to preserve newlines.
 func FormatYAML(v interface{}, opts YAMLFormatOptions) (string, error) {
+	if opts.PreserveSource && len(opts.Source) > 0 {
+		s, err := encodeSourceNodes(opts.Source, opts.indent())
+		return opts.finish(s), err
+	}
+	defer opts.useKeyOrder()()
+
 	var node yaml.Node
 	if err := node.Encode(v); err != nil {
 		return "", err
 	}
 
+	retagScalars(&node, v)
+	if customKeyOrder() {
+		orderYAMLKeys(&node, v)
+	}
 	if opts.ExpandEscapedNewlines {
 		expandEscapedNewlines(&node)
 	}
@@ -31,12 +54,25 @@ func FormatYAML(v interface{}, opts YAMLFormatOptions) (string, error) {
 
 	var buf bytes.Buffer
 	enc := yaml.NewEncoder(&buf)
-	indent := opts.Indent
-	if indent <= 0 {
-		indent = 2
+	enc.SetIndent(opts.indent())
+	if err := enc.Encode(&node); err != nil {
+		return "", err
 	}
+	return opts.finish(buf.String()), nil
+}
+
+// encodeSourceNodes writes retained source nodes as-is; several nodes are
+// written as separate documents.
+func encodeSourceNodes(nodes []*yaml.Node, indent int) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
 	enc.SetIndent(indent)
-	if err := enc.Encode(&node); err != nil {
+	for _, n := range nodes {
+		if err := enc.Encode(yamlsource.Detach(n)); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Close(); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
@@ -65,3 +101,85 @@ func expandEscapedNewlines(n *yaml.Node) {
 		expandEscapedNewlines(c)
 	}
 }
+
+// retagScalars fixes scalars the encoder gets wrong for kvx values:
+// json.Number, written as a quoted string, becomes a plain number again, and
+// binary data becomes a !!binary base64 scalar.
+func retagScalars(n *yaml.Node, v interface{}) {
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) > 0 {
+			retagScalars(n.Content[0], v)
+		}
+		return
+	}
+	if b, ok := BinaryBytes(v); ok {
+		n.Kind = yaml.ScalarNode
+		n.Tag = "!!binary"
+		n.Value = base64.StdEncoding.EncodeToString(b)
+		n.Style = 0
+		n.Content = nil
+		return
+	}
+	switch t := v.(type) {
+	case json.Number:
+		if n.Kind == yaml.ScalarNode {
+			n.Tag = ""
+			n.Style = 0
+		}
+	case map[string]interface{}:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			retagScalars(n.Content[i+1], t[n.Content[i].Value])
+		}
+	case []interface{}:
+		for i, c := range n.Content {
+			if i < len(t) {
+				retagScalars(c, t[i])
+			}
+		}
+	}
+}
+
+// orderYAMLKeys rearranges mapping pairs, which the encoder emits sorted, into
+// the active key order. v is the value n was encoded from.
+func orderYAMLKeys(n *yaml.Node, v interface{}) {
+	if n == nil {
+		return
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			orderYAMLKeys(n.Content[0], v)
+		}
+	case yaml.MappingNode:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		pairs := make(map[string][2]*yaml.Node, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs[n.Content[i].Value] = [2]*yaml.Node{n.Content[i], n.Content[i+1]}
+		}
+		content := make([]*yaml.Node, 0, len(n.Content))
+		for _, k := range keyorder.Keys(m) {
+			pair, ok := pairs[k]
+			if !ok {
+				return
+			}
+			orderYAMLKeys(pair[1], m[k])
+			content = append(content, pair[0], pair[1])
+		}
+		if len(content) == len(n.Content) {
+			n.Content = content
+		}
+	case yaml.SequenceNode:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		for i, c := range n.Content {
+			if i < len(arr) {
+				orderYAMLKeys(c, arr[i])
+			}
+		}
+	}
+}
@@ -1,15 +1,18 @@
 package formatter
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestFormatYAML_SimpleMap(t *testing.T) {
 	data := map[string]any{"name": "test", "count": 42}
-	result, err := FormatYAML(data, YAMLFormatOptions{Indent: 2})
+	result, err := FormatYAML(data, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}})
 	require.NoError(t, err)
 	assert.Contains(t, result, "name: test")
 	assert.Contains(t, result, "count: 42")
@@ -72,7 +75,7 @@ func TestFormatYAML_Nested(t *testing.T) {
 			map[string]any{"name": "bob"},
 		},
 	}
-	result, err := FormatYAML(data, YAMLFormatOptions{Indent: 4})
+	result, err := FormatYAML(data, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 4}})
 	require.NoError(t, err)
 	assert.Contains(t, result, "users:")
 	assert.Contains(t, result, "name: alice")
@@ -81,7 +84,7 @@ func TestFormatYAML_Nested(t *testing.T) {
 
 func TestFormatYAML_CustomIndent(t *testing.T) {
 	data := map[string]any{"a": map[string]any{"b": "c"}}
-	result, err := FormatYAML(data, YAMLFormatOptions{Indent: 4})
+	result, err := FormatYAML(data, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 4}})
 	require.NoError(t, err)
 	assert.Contains(t, result, "    b: c")
 }
@@ -107,3 +110,66 @@ func TestFormatYAML_BooleanValues(t *testing.T) {
 	assert.Contains(t, result, "active: true")
 	assert.Contains(t, result, "deleted: false")
 }
+
+func TestFormatYAML_InsertionOrder(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Insertion)
+	defer keyorder.SetMode(prev)
+	defer keyorder.SetDocument(nil)
+
+	order := keyorder.NewOrder()
+	inner := map[string]any{"zeta": 1, "alpha": 2}
+	order.Record(inner, []string{"zeta", "alpha"})
+	root := map[string]any{"spec": inner, "kind": "Pod"}
+	order.Record(root, []string{"spec", "kind"})
+	keyorder.SetDocument(order)
+
+	result, err := FormatYAML(root, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "spec:\n  zeta: 1\n  alpha: 2\nkind: Pod\n", result)
+}
+
+func TestFormatYAML_Descending(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Descending)
+	defer keyorder.SetMode(prev)
+
+	result, err := FormatYAML(map[string]any{"a": 1, "c": 3, "b": 2}, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "c: 3\nb: 2\na: 1\n", result)
+}
+
+func TestFormatYAML_SortKeysAndNewline(t *testing.T) {
+	prev := keyorder.SetMode(keyorder.Descending)
+	defer keyorder.SetMode(prev)
+
+	sorted := true
+	result, err := FormatYAML(map[string]any{"a": 1, "c": 3, "b": 2}, YAMLFormatOptions{SerializeOptions: SerializeOptions{SortKeys: &sorted, NoTrailingNewline: true}})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1\nb: 2\nc: 3", result)
+}
+
+func TestFormatYAML_PreserveSource(t *testing.T) {
+	src := "# top\nname: \"demo\" # quoted\nitems: [1, 2]\n"
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	var v any
+	require.NoError(t, node.Decode(&v))
+
+	result, err := FormatYAML(v, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}, PreserveSource: true, Source: []*yaml.Node{&node}})
+	require.NoError(t, err)
+	assert.Equal(t, src, result)
+
+	result, err = FormatYAML(v, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}, PreserveSource: true})
+	require.NoError(t, err)
+	assert.Equal(t, "items:\n  - 1\n  - 2\nname: demo\n", result)
+
+	result, err = FormatYAML(v, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "items:\n  - 1\n  - 2\nname: demo\n", result)
+}
+
+func TestFormatYAML_JSONNumberUnquoted(t *testing.T) {
+	data := map[string]any{"price": json.Number("12345678901234567.891"), "list": []any{json.Number("1e400")}}
+	result, err := FormatYAML(data, YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "list:\n  - 1e400\nprice: 12345678901234567.891\n", result)
+}
@@ -0,0 +1,213 @@
+// Package jq runs jq programs against loaded data with gojq, for
+// --query-lang jq, core.EvaluateJQ, and the jq mode of the TUI expression
+// bar.
+package jq
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/itchyny/gojq"
+)
+
+// SyntaxError reports a program that does not parse.
+type SyntaxError struct {
+	Pos int    // Rune offset of the offending token in the program
+	Msg string // Parser message, e.g. `unexpected token "]"`
+	Err error  // Underlying gojq error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// Compile parses and compiles a jq program. Parse failures are returned as
+// *SyntaxError; compile failures, such as a call to an undefined function,
+// as gojq reports them.
+func Compile(expr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, syntaxError(expr, err)
+	}
+	return gojq.Compile(query)
+}
+
+// Check reports the first syntax error in expr, or nil when it parses.
+func Check(expr string) error {
+	if _, err := gojq.Parse(expr); err != nil {
+		return syntaxError(expr, err)
+	}
+	return nil
+}
+
+// syntaxError locates a gojq parse error at the start of the token it
+// stopped on, or at the end of expr when the program ended early.
+func syntaxError(expr string, err error) error {
+	var perr *gojq.ParseError
+	if !errors.As(err, &perr) {
+		return err
+	}
+	offset := min(max(perr.Offset-len(perr.Token), 0), len(expr))
+	return &SyntaxError{Pos: utf8.RuneCountInString(expr[:offset]), Msg: perr.Error(), Err: err}
+}
+
+// Run runs expr against root and returns every value it outputs, in order.
+// It stops at the first error the program raises, or when ctx is done.
+func Run(ctx context.Context, expr string, root any) ([]any, error) {
+	code, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	outputs := []any{}
+	iter := code.RunWithContext(ctx, Normalize(root))
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return outputs, nil
+		}
+		if err, isErr := v.(error); isErr {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				return outputs, nil
+			}
+			return nil, err
+		}
+		outputs = append(outputs, v)
+	}
+}
+
+// ErrorValue returns the value a program raised with error/1 or halt_error,
+// when err is such an error.
+func ErrorValue(err error) (any, bool) {
+	var ve gojq.ValueError
+	if !errors.As(err, &ve) {
+		return nil, false
+	}
+	return ve.Value(), true
+}
+
+// Evaluate runs expr against root like Run and returns its result: the
+// value itself when the program outputs exactly one, otherwise the list of
+// its outputs, so ".items[]" and "[.items[]]" show the same table.
+func Evaluate(expr string, root any) (any, error) {
+	outputs, err := Run(context.Background(), expr, root)
+	if err != nil {
+		return nil, err
+	}
+	return Collapse(outputs), nil
+}
+
+// Collapse returns the only value of outputs, or outputs itself when there
+// are none or several.
+func Collapse(outputs []any) any {
+	if len(outputs) == 1 {
+		return outputs[0]
+	}
+	return outputs
+}
+
+// Normalize converts decoded data to the types jq programs work on: maps
+// with string keys, lists, strings, booleans, nil, and numbers as int,
+// float64, or *big.Int. Times become RFC 3339 strings and binary data base64
+// text, as in JSON output; structs and other types go through their JSON
+// form. Containers are copied only when something inside them changes.
+func Normalize(v any) any {
+	n, _ := normalize(v)
+	return n
+}
+
+// normalize is Normalize, also reporting whether v changed.
+func normalize(v any) (any, bool) {
+	switch t := v.(type) {
+	case nil, bool, string, int, float64, *big.Int, json.Number:
+		return v, false
+	case map[string]any:
+		var out map[string]any
+		for k, e := range t {
+			n, changed := normalize(e)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = maps.Clone(t)
+			}
+			out[k] = n
+		}
+		if out == nil {
+			return t, false
+		}
+		return out, true
+	case []any:
+		var out []any
+		for i, e := range t {
+			n, changed := normalize(e)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = slices.Clone(t)
+			}
+			out[i] = n
+		}
+		if out == nil {
+			return t, false
+		}
+		return out, true
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[fmt.Sprint(k)] = Normalize(e)
+		}
+		return out, true
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint, uint64:
+		return normalizeInteger(t), true
+	case float32:
+		return float64(t), true
+	case time.Time:
+		return t.Format(time.RFC3339Nano), true
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t), true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v), true
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var plain any
+	if err := decoder.Decode(&plain); err != nil {
+		return fmt.Sprint(v), true
+	}
+	return Normalize(plain), true
+}
+
+// normalizeInteger returns an integer of any size as an int, or a *big.Int
+// when it does not fit.
+func normalizeInteger(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.CanInt() {
+		i := rv.Int()
+		if i < math.MinInt || i > math.MaxInt {
+			return big.NewInt(i)
+		}
+		return int(i)
+	}
+	u := rv.Uint()
+	if u > math.MaxInt {
+		return new(big.Int).SetUint64(u)
+	}
+	return int(u)
+}
@@ -0,0 +1,143 @@
+package jq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sample() map[string]any {
+	return map[string]any{
+		"items": []any{
+			map[string]any{"name": "a", "n": 1},
+			map[string]any{"name": "b", "n": 2},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	got, err := Evaluate(".items[0].name", sample())
+	require.NoError(t, err)
+	assert.Equal(t, "a", got)
+
+	got, err = Evaluate(".items[] | .name", sample())
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", "b"}, got, "several outputs are returned as a list")
+
+	got, err = Evaluate(".items[] | select(.n > 5)", sample())
+	require.NoError(t, err)
+	assert.Equal(t, []any{}, got, "no output is an empty list")
+
+	got, err = Evaluate("[.items[].n] | add", sample())
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+
+	got, err = Evaluate("1, halt, 2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got, "halt ends the outputs")
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	_, err := Evaluate(".items[", sample())
+	var se *SyntaxError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, 7, se.Pos, "an early end points past the program")
+	assert.Equal(t, "unexpected EOF", se.Msg)
+
+	_, err = Evaluate(".a ] .b", nil)
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, 3, se.Pos, "the offending token is pointed at")
+
+	_, err = Evaluate("nosuchfn", nil)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &se))
+	assert.Contains(t, err.Error(), "function not defined")
+
+	_, err = Evaluate(`error("boom")`, nil)
+	assert.EqualError(t, err, "error: boom")
+
+	assert.NoError(t, Check(".items | length"))
+	assert.Error(t, Check(".items |"))
+}
+
+func TestRunStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Run(ctx, "repeat(1)", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNormalize(t *testing.T) {
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	type point struct {
+		X int `json:"x"`
+	}
+	in := map[string]any{
+		"i64":   int64(7),
+		"u64":   uint64(1 << 63),
+		"f32":   float32(1.5),
+		"when":  when,
+		"bin":   []byte("hi"),
+		"keys":  map[any]any{1: "one"},
+		"point": point{X: 3},
+	}
+	got := Normalize(in).(map[string]any)
+	assert.Equal(t, 7, got["i64"])
+	assert.Equal(t, new(big.Int).SetUint64(1<<63), got["u64"])
+	assert.Equal(t, 1.5, got["f32"])
+	assert.Equal(t, "2024-05-01T12:00:00Z", got["when"])
+	assert.Equal(t, "aGk=", got["bin"])
+	assert.Equal(t, map[string]any{"1": "one"}, got["keys"])
+	assert.Equal(t, map[string]any{"x": json.Number("3")}, got["point"])
+	assert.Equal(t, int64(7), in["i64"], "the input is not modified")
+
+	plain := sample()
+	assert.Equal(t, plain, Normalize(plain))
+	items := Normalize(plain).(map[string]any)["items"].([]any)
+	assert.Same(t, &plain["items"].([]any)[0], &items[0], "unchanged lists are not copied")
+
+	got2, err := Evaluate(".when | fromdate", in)
+	require.NoError(t, err)
+	assert.Equal(t, float64(when.Unix()), got2)
+}
+
+func TestSplitPath(t *testing.T) {
+	steps, ok := SplitPath(`.items[0]."bad-key".["x y"].name`)
+	require.True(t, ok)
+	assert.Equal(t, []PathStep{
+		{Key: "items"}, {Index: 0, IsIndex: true}, {Key: "bad-key"}, {Key: "x y"}, {Key: "name"},
+	}, steps)
+	assert.Equal(t, `.items[0]."bad-key"."x y".name`, FormatPath(steps))
+
+	steps, ok = SplitPath(" . ")
+	require.True(t, ok)
+	assert.Empty(t, steps)
+	assert.Equal(t, ".", FormatPath(steps))
+
+	steps, ok = SplitPath(".[2]")
+	require.True(t, ok)
+	assert.Equal(t, ".[2]", FormatPath(steps))
+
+	for _, expr := range []string{"", "items", ".items[]", ".items[-1]", ".a?", ".a | .b", `."\(.x)"`, ".[1:2]", ".."} {
+		_, ok := SplitPath(expr)
+		assert.False(t, ok, expr)
+	}
+
+	assert.Equal(t, `."if"`, FormatPath([]PathStep{{Key: "if"}}), "keywords are quoted")
+}
+
+func TestErrorValue(t *testing.T) {
+	_, err := Evaluate(`error({"a": 1})`, nil)
+	v, ok := ErrorValue(err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"a": 1}, v)
+
+	_, ok = ErrorValue(errors.New("plain"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,179 @@
+package jq
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// PathStep is one step of a plain jq path: a field name or an array index.
+type PathStep struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// SplitPath splits a jq path made only of field and index steps, such as
+// `.items[0].name`, `."bad-key"`, or `.["bad-key"]`, into its steps; "." is
+// the empty path. ok is false for anything else, including iterators
+// (".[]"), slices, negative indexes, and optional steps ("?"), whose results
+// are not a single value at a path.
+func SplitPath(expr string) (steps []PathStep, ok bool) {
+	s := strings.TrimSpace(expr)
+	if s == "." {
+		return []PathStep{}, true
+	}
+	if !strings.HasPrefix(s, ".") {
+		return nil, false
+	}
+	steps = []PathStep{}
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".["), strings.HasPrefix(s, "["):
+			s = strings.TrimPrefix(s, ".")
+			end := closingBracket(s)
+			if end < 0 {
+				return nil, false
+			}
+			step, valid := bracketStep(strings.TrimSpace(s[1:end]))
+			if !valid {
+				return nil, false
+			}
+			steps = append(steps, step)
+			s = s[end+1:]
+		case strings.HasPrefix(s, `."`):
+			end := stringEnd(s[1:])
+			if end < 0 {
+				return nil, false
+			}
+			key, valid := unquote(s[1 : end+2])
+			if !valid {
+				return nil, false
+			}
+			steps = append(steps, PathStep{Key: key})
+			s = s[end+2:]
+		case strings.HasPrefix(s, "."):
+			n := 1
+			for n < len(s) && isIdentByte(s[n], n == 1) {
+				n++
+			}
+			if n == 1 {
+				return nil, false
+			}
+			steps = append(steps, PathStep{Key: s[1:n]})
+			s = s[n:]
+		default:
+			return nil, false
+		}
+	}
+	return steps, true
+}
+
+// bracketStep parses the inside of a bracket step: an index or a string key.
+func bracketStep(inner string) (PathStep, bool) {
+	if i, err := strconv.Atoi(inner); err == nil && i >= 0 {
+		return PathStep{Index: i, IsIndex: true}, true
+	}
+	if strings.HasPrefix(inner, `"`) && stringEnd(inner) == len(inner)-1 {
+		if key, ok := unquote(inner); ok {
+			return PathStep{Key: key}, true
+		}
+	}
+	return PathStep{}, false
+}
+
+// closingBracket returns the index of the ']' closing the '[' s starts with,
+// skipping string literals, or -1.
+func closingBracket(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			end := stringEnd(s[i:])
+			if end < 0 {
+				return -1
+			}
+			i += end
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// stringEnd returns the index of the quote closing the string literal s
+// starts with, or -1.
+func stringEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote decodes a jq string literal without interpolation.
+func unquote(lit string) (string, bool) {
+	if strings.Contains(lit, `\(`) {
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal([]byte(lit), &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+func isIdentByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// FormatPath writes steps as a jq path, e.g. `.items[0]."bad-key"`; no
+// steps is ".".
+func FormatPath(steps []PathStep) string {
+	if len(steps) == 0 {
+		return "."
+	}
+	var b strings.Builder
+	for _, step := range steps {
+		switch {
+		case step.IsIndex:
+			if b.Len() == 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString("[" + strconv.Itoa(step.Index) + "]")
+		case IsIdentifier(step.Key):
+			b.WriteString("." + step.Key)
+		default:
+			data, _ := json.Marshal(step.Key)
+			b.WriteString("." + string(data))
+		}
+	}
+	return b.String()
+}
+
+// IsIdentifier reports whether key can follow a dot in a jq path unquoted.
+func IsIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if !isIdentByte(key[i], i == 0) {
+			return false
+		}
+	}
+	return !isKeyword(key)
+}
+
+// isKeyword reports whether word is reserved by jq syntax. gojq accepts
+// keywords after a dot, but jq does not, so they are quoted.
+func isKeyword(word string) bool {
+	switch word {
+	case "and", "or", "not", "if", "then", "elif", "else", "end", "as", "def",
+		"reduce", "foreach", "try", "catch", "label", "import", "include", "__loc__":
+		return true
+	}
+	return false
+}
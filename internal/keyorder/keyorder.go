@@ -0,0 +1,296 @@
+// Package keyorder decides the order in which map keys are displayed and
+// serialized. Go maps do not remember insertion order, so the loader records
+// the original order of each decoded object in an Order that belongs to the
+// loaded document, and renderers ask a Sorter for the keys of a map.
+package keyorder
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Mode selects how map keys are ordered.
+type Mode string
+
+const (
+	// Ascending sorts keys alphabetically (default).
+	Ascending Mode = "ascending"
+	// Descending sorts keys in reverse alphabetical order.
+	Descending Mode = "descending"
+	// None leaves table rows in map iteration order; renderers that need a
+	// stable order (tree, list, serialized output) sort alphabetically.
+	None Mode = "none"
+	// Insertion keeps the order keys appeared in the source document.
+	// Maps without a recorded order (e.g. built by an expression) fall back
+	// to alphabetical.
+	Insertion Mode = "insertion"
+	// Schema puts keys listed in the schema/column order first, in that
+	// order, followed by the remaining keys alphabetically.
+	Schema Mode = "schema"
+	// Natural sorts keys alphabetically with runs of digits compared by
+	// value, so item2 comes before item10.
+	Natural Mode = "natural"
+)
+
+// Order holds the key order of the objects of one loaded document. It keeps
+// a reference to every map it describes, so an entry cannot be mistaken for
+// another map that reuses the address, and it is freed with the document.
+// A nil *Order records nothing and knows no order.
+type Order struct {
+	mu      sync.RWMutex
+	entries map[uintptr]entry
+}
+
+type entry struct {
+	m    map[string]interface{}
+	keys []string
+}
+
+// NewOrder returns an empty Order.
+func NewOrder() *Order {
+	return &Order{entries: make(map[uintptr]entry)}
+}
+
+// Record remembers keys as the original order of m.
+func (o *Order) Record(m map[string]interface{}, keys []string) {
+	if o == nil || len(m) == 0 || len(keys) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries[mapID(m)] = entry{m: m, keys: keys}
+}
+
+// Keys returns the recorded order of m. The order is only returned while it
+// still describes m exactly, so maps modified after loading fall back to the
+// mode's order.
+func (o *Order) Keys(m map[string]interface{}) ([]string, bool) {
+	if o == nil || len(m) == 0 {
+		return nil, false
+	}
+	o.mu.RLock()
+	e, ok := o.entries[mapID(m)]
+	o.mu.RUnlock()
+	if !ok || len(e.keys) != len(m) {
+		return nil, false
+	}
+	for _, k := range e.keys {
+		if _, exists := m[k]; !exists {
+			return nil, false
+		}
+	}
+	return slices.Clone(e.keys), true
+}
+
+// Documents holds the key orders of several loaded documents, each under the
+// root it was loaded as, so that loading one document does not replace the
+// order of another. The zero value is empty and ready to use; a nil
+// *Documents knows no order.
+type Documents struct {
+	mu    sync.RWMutex
+	roots map[uintptr]document
+}
+
+type document struct {
+	root  interface{}
+	order *Order
+}
+
+// Add records o as the key order of the document loaded as root. Only maps
+// and non-empty lists have keys to order; other roots are not recorded.
+func (d *Documents) Add(root interface{}, o *Order) {
+	id, ok := rootID(root)
+	if d == nil || o == nil || !ok {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.roots == nil {
+		d.roots = make(map[uintptr]document)
+	}
+	d.roots[id] = document{root: root, order: o}
+}
+
+// Remove forgets the key order of the document loaded as root.
+func (d *Documents) Remove(root interface{}) {
+	id, ok := rootID(root)
+	if d == nil || !ok {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.roots, id)
+}
+
+// Keys returns the recorded order of m from the document it belongs to.
+func (d *Documents) Keys(m map[string]interface{}) ([]string, bool) {
+	if d == nil || len(m) == 0 {
+		return nil, false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, doc := range d.roots {
+		if keys, ok := doc.order.Keys(m); ok {
+			return keys, true
+		}
+	}
+	return nil, false
+}
+
+// Sorter orders map keys. Doc and Docs supply the document orders used by
+// Insertion and Schema the preferred keys used by the Schema mode.
+type Sorter struct {
+	Mode   Mode
+	Schema []string
+	Doc    *Order
+	Docs   *Documents
+}
+
+// Keys returns the keys of m in the sorter's order.
+func (s Sorter) Keys(m map[string]interface{}) []string {
+	if s.Mode == Insertion {
+		if keys, ok := s.Doc.Keys(m); ok {
+			return keys
+		}
+		if keys, ok := s.Docs.Keys(m); ok {
+			return keys
+		}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	s.Sort(keys)
+	return keys
+}
+
+// Sort orders keys in place, for maps without a recorded document order.
+func (s Sorter) Sort(keys []string) {
+	switch s.Mode {
+	case Descending:
+		slices.Sort(keys)
+		slices.Reverse(keys)
+	case Natural:
+		slices.SortFunc(keys, CompareNatural)
+	case Schema:
+		rank := make(map[string]int, len(s.Schema))
+		for i, k := range s.Schema {
+			if _, dup := rank[k]; !dup {
+				rank[k] = i
+			}
+		}
+		slices.SortStableFunc(keys, func(a, b string) int {
+			ra, aok := rank[a]
+			rb, bok := rank[b]
+			switch {
+			case aok && bok:
+				return ra - rb
+			case aok:
+				return -1
+			case bok:
+				return 1
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			default:
+				return 0
+			}
+		})
+	default:
+		slices.Sort(keys)
+	}
+}
+
+var (
+	mu     sync.RWMutex
+	active = Sorter{Mode: Ascending}
+)
+
+// SetMode sets the mode of the active sorter and returns the previous one.
+// Unknown modes are treated as None.
+func SetMode(m Mode) Mode {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := active.Mode
+	switch m {
+	case Ascending, Descending, None, Insertion, Schema, Natural:
+		active.Mode = m
+	default:
+		active.Mode = None
+	}
+	return prev
+}
+
+// CurrentMode returns the mode of the active sorter.
+func CurrentMode() Mode {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active.Mode
+}
+
+// SetSchemaOrder sets the preferred key order used by the Schema mode.
+func SetSchemaOrder(keys []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	active.Schema = slices.Clone(keys)
+}
+
+// SetDocument sets the document order used by the Insertion mode, replacing
+// the previous document's. nil forgets it.
+func SetDocument(o *Order) {
+	mu.Lock()
+	defer mu.Unlock()
+	active.Doc = o
+}
+
+// Active returns the sorter used by Keys and Sort.
+func Active() Sorter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active
+}
+
+// Use makes s the active sorter and returns a function that restores the
+// previous one.
+func Use(s Sorter) func() {
+	mu.Lock()
+	prev := active
+	active = s
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		active = prev
+		mu.Unlock()
+	}
+}
+
+// Keys returns the keys of m ordered by the active sorter.
+func Keys(m map[string]interface{}) []string {
+	return Active().Keys(m)
+}
+
+// Sort orders keys in place with the active sorter, for maps without a
+// recorded document order.
+func Sort(keys []string) {
+	Active().Sort(keys)
+}
+
+func mapID(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+// rootID identifies a document by its root map or the backing array of its
+// root list.
+func rootID(root interface{}) (uintptr, bool) {
+	switch t := root.(type) {
+	case map[string]interface{}:
+		return mapID(t), true
+	case []interface{}:
+		if len(t) > 0 {
+			return reflect.ValueOf(t).Pointer(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,93 @@
+package keyorder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSorter_Modes(t *testing.T) {
+	m := map[string]interface{}{"b": 1, "a": 2, "c": 3}
+
+	assert.Equal(t, []string{"a", "b", "c"}, Sorter{Mode: Ascending}.Keys(m))
+	assert.Equal(t, []string{"c", "b", "a"}, Sorter{Mode: Descending}.Keys(m))
+	assert.Equal(t, []string{"a", "b", "c"}, Sorter{Mode: None}.Keys(m))
+	assert.Equal(t, []string{"c", "b", "a"}, Sorter{Mode: Schema, Schema: []string{"c", "missing", "b"}}.Keys(m))
+}
+
+func TestOrder_RecordYAML(t *testing.T) {
+	src := "zeta: 1\nalpha:\n  y: 1\n  x: 2\nmid: [{q: 1, p: 2}]\n"
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	var data interface{}
+	require.NoError(t, node.Decode(&data))
+	order := NewOrder()
+	order.RecordYAML(&node, data)
+
+	s := Sorter{Mode: Insertion, Doc: order}
+	root := data.(map[string]interface{})
+	assert.Equal(t, []string{"zeta", "alpha", "mid"}, s.Keys(root))
+	assert.Equal(t, []string{"y", "x"}, s.Keys(root["alpha"].(map[string]interface{})))
+	item := root["mid"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []string{"q", "p"}, s.Keys(item))
+}
+
+func TestOrder_FallsBackWhenModified(t *testing.T) {
+	order := NewOrder()
+	m := map[string]interface{}{"b": 1, "a": 2}
+	order.Record(m, []string{"b", "a"})
+	s := Sorter{Mode: Insertion, Doc: order}
+	assert.Equal(t, []string{"b", "a"}, s.Keys(m))
+
+	m["c"] = 3
+	assert.Equal(t, []string{"a", "b", "c"}, s.Keys(m))
+
+	unrecorded := map[string]interface{}{"y": 1, "x": 2}
+	assert.Equal(t, []string{"x", "y"}, s.Keys(unrecorded))
+}
+
+func TestOrder_PerDocument(t *testing.T) {
+	m := map[string]interface{}{"b": 1, "a": 2}
+	first := NewOrder()
+	first.Record(m, []string{"b", "a"})
+
+	assert.Equal(t, []string{"b", "a"}, Sorter{Mode: Insertion, Doc: first}.Keys(m))
+	assert.Equal(t, []string{"a", "b"}, Sorter{Mode: Insertion, Doc: NewOrder()}.Keys(m))
+	assert.Equal(t, []string{"a", "b"}, Sorter{Mode: Insertion}.Keys(m))
+}
+
+func TestDocuments(t *testing.T) {
+	a := map[string]interface{}{"z": 1, "a": 2}
+	orderA := NewOrder()
+	orderA.Record(a, []string{"z", "a"})
+	b := []interface{}{map[string]interface{}{"y": 1, "x": 2}}
+	orderB := NewOrder()
+	orderB.Record(b[0].(map[string]interface{}), []string{"y", "x"})
+
+	var docs Documents
+	docs.Add(a, orderA)
+	docs.Add(b, orderB)
+	docs.Add("scalar", NewOrder())
+	s := Sorter{Mode: Insertion, Docs: &docs}
+	assert.Equal(t, []string{"z", "a"}, s.Keys(a))
+	assert.Equal(t, []string{"y", "x"}, s.Keys(b[0].(map[string]interface{})))
+
+	docs.Remove(a)
+	assert.Equal(t, []string{"a", "z"}, s.Keys(a))
+	assert.Equal(t, []string{"y", "x"}, s.Keys(b[0].(map[string]interface{})))
+	assert.Equal(t, []string{"a", "z"}, Sorter{Mode: Insertion}.Keys(a), "a nil Documents knows no order")
+}
+
+func TestUse(t *testing.T) {
+	order := NewOrder()
+	m := map[string]interface{}{"b": 1, "a": 2}
+	order.Record(m, []string{"b", "a"})
+
+	restore := Use(Sorter{Mode: Insertion, Doc: order})
+	assert.Equal(t, []string{"b", "a"}, Keys(m))
+	restore()
+	assert.Equal(t, Ascending, CurrentMode())
+	assert.Equal(t, []string{"a", "b"}, Keys(m))
+}
@@ -0,0 +1,23 @@
+package keyorder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareNatural(t *testing.T) {
+	assert.Negative(t, CompareNatural("item2", "item10"))
+	assert.Positive(t, CompareNatural("item10", "item2"))
+	assert.Negative(t, CompareNatural("a", "b"))
+	assert.Negative(t, CompareNatural("item", "item1"))
+	assert.Negative(t, CompareNatural("v1.2", "v1.10"))
+	assert.NotZero(t, CompareNatural("07", "7"), "zero padding keeps values distinct")
+	assert.Zero(t, CompareNatural("x10", "x10"))
+}
+
+func TestSorter_Natural(t *testing.T) {
+	m := map[string]interface{}{"item10": 1, "item2": 2, "item1": 3, "alpha": 4}
+	assert.Equal(t, []string{"alpha", "item1", "item2", "item10"}, Sorter{Mode: Natural}.Keys(m))
+	assert.Equal(t, []string{"alpha", "item1", "item10", "item2"}, Sorter{Mode: Ascending}.Keys(m))
+}
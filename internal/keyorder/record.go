@@ -0,0 +1,55 @@
+package keyorder
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// RecordYAML records the key order of every mapping in n onto the
+// corresponding map in v, the value n was decoded into.
+func (o *Order) RecordYAML(n *yaml.Node, v interface{}) {
+	if o == nil || n == nil {
+		return
+	}
+	o.recordYAML(n, v)
+}
+
+func (o *Order) recordYAML(n *yaml.Node, v interface{}) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			o.recordYAML(n.Content[0], v)
+		}
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			o.recordYAML(n.Alias, v)
+		}
+	case yaml.MappingNode:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if n.Content[i].Tag == "!!merge" {
+				// Merged keys have no position of their own; leave the map
+				// unrecorded so it falls back to alphabetical order.
+				return
+			}
+			keys = append(keys, key)
+			o.recordYAML(n.Content[i+1], m[key])
+		}
+		o.Record(m, keys)
+	case yaml.SequenceNode:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		for i, c := range n.Content {
+			if i < len(arr) {
+				o.recordYAML(c, arr[i])
+			}
+		}
+	case yaml.ScalarNode:
+	}
+}
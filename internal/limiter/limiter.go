@@ -2,21 +2,25 @@ package limiter
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"sort"
 )
 
 // Config holds the record-limiting parameters.
 type Config struct {
-	Limit  int // Show only this many records (0 = unlimited)
-	Offset int // Skip the first N records (0 = no skip)
-	Tail   int // Show only the last N records (0 = disabled); mutually exclusive with Limit
+	Limit  int    // Show only this many records (0 = unlimited)
+	Offset int    // Skip the first N records (0 = no skip)
+	Tail   int    // Show only the last N records (0 = disabled); mutually exclusive with Limit
+	Sample int    // Show N records picked at random, in their original order (0 = disabled)
+	Seed   uint64 // Seed of the Sample picks; the same seed picks the same records
 }
 
 // Validate checks for conflicting flag combinations and returns an error if invalid.
 // Rules:
 // - Limit and Tail are mutually exclusive
 // - If Tail is set, Offset is ignored
+// - Sample picks from the records the other settings keep
 // - All numeric values must be non-negative
 func (c Config) Validate() error {
 	if c.Limit < 0 {
@@ -28,10 +32,13 @@ func (c Config) Validate() error {
 	if c.Tail < 0 {
 		return fmt.Errorf("--tail must be non-negative, got %d", c.Tail)
 	}
+	if c.Sample < 0 {
+		return fmt.Errorf("--sample must be non-negative, got %d", c.Sample)
+	}
 
 	// Check for mutually exclusive flags
 	if c.Limit > 0 && c.Tail > 0 {
-		return fmt.Errorf("--limit and --tail are mutually exclusive")
+		return fmt.Errorf("--limit (--head) and --tail are mutually exclusive")
 	}
 
 	return nil
@@ -39,7 +46,7 @@ func (c Config) Validate() error {
 
 // IsActive returns true if any limiting is configured.
 func (c Config) IsActive() bool {
-	return c.Limit > 0 || c.Offset > 0 || c.Tail > 0
+	return c.Limit > 0 || c.Offset > 0 || c.Tail > 0 || c.Sample > 0
 }
 
 // Apply applies the limiting configuration to the given data.
@@ -63,6 +70,19 @@ func (c Config) Apply(data interface{}) interface{} {
 
 // applyToArray applies limiting to an array.
 func (c Config) applyToArray(arr []interface{}) interface{} {
+	arr = c.sliceArray(arr)
+	if c.Sample <= 0 || c.Sample >= len(arr) {
+		return arr
+	}
+	out := make([]interface{}, 0, c.Sample)
+	for _, i := range c.sampleIndexes(len(arr)) {
+		out = append(out, arr[i])
+	}
+	return out
+}
+
+// sliceArray applies --tail, or --offset and --limit, to an array.
+func (c Config) sliceArray(arr []interface{}) []interface{} {
 	length := len(arr)
 
 	// Handle --tail (show last N records)
@@ -138,6 +158,14 @@ func (c Config) applyToMap(m map[string]interface{}) interface{} {
 		keys = keys[start:end]
 	}
 
+	if c.Sample > 0 && c.Sample < len(keys) {
+		picked := make([]string, 0, c.Sample)
+		for _, i := range c.sampleIndexes(len(keys)) {
+			picked = append(picked, keys[i])
+		}
+		keys = picked
+	}
+
 	// Reconstruct map with limited keys
 	result := make(map[string]interface{})
 	for _, k := range keys {
@@ -198,5 +226,28 @@ func (c Config) ApplyToGenericSlice(data interface{}) interface{} {
 	}
 
 	// Use reflection to slice
-	return val.Slice(start, end).Interface()
+	val = val.Slice(start, end)
+	if c.Sample <= 0 || c.Sample >= val.Len() {
+		return val.Interface()
+	}
+	out := reflect.MakeSlice(val.Type(), 0, c.Sample)
+	for _, i := range c.sampleIndexes(val.Len()) {
+		out = reflect.Append(out, val.Index(i))
+	}
+	return out.Interface()
+}
+
+// sampleIndexes returns Sample indexes picked at random from [0, n), in
+// increasing order. It makes one pass, choosing each index with the
+// probability that leaves every set of Sample indexes equally likely
+// (Knuth's selection sampling), so the picks keep the records' order.
+func (c Config) sampleIndexes(n int) []int {
+	r := rand.New(rand.NewPCG(c.Seed, c.Seed)) //nolint:gosec // sampling, not security
+	picked := make([]int, 0, c.Sample)
+	for i := 0; i < n && len(picked) < c.Sample; i++ {
+		if r.IntN(n-i) < c.Sample-len(picked) {
+			picked = append(picked, i)
+		}
+	}
+	return picked
 }
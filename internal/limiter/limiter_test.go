@@ -402,3 +402,59 @@ func TestApplyToGenericSlice(t *testing.T) {
 		assert.Equal(t, data, got)
 	})
 }
+
+func TestApplySample(t *testing.T) {
+	arr := make([]interface{}, 100)
+	for i := range arr {
+		arr[i] = i
+	}
+
+	t.Run("picks N records in order", func(t *testing.T) {
+		got := Config{Sample: 10, Seed: 7}.Apply(arr).([]interface{})
+		require.Len(t, got, 10)
+		for i := 1; i < len(got); i++ {
+			assert.Less(t, got[i-1].(int), got[i].(int))
+		}
+	})
+
+	t.Run("same seed same picks", func(t *testing.T) {
+		a := Config{Sample: 10, Seed: 7}.Apply(arr)
+		b := Config{Sample: 10, Seed: 7}.Apply(arr)
+		c := Config{Sample: 10, Seed: 8}.Apply(arr)
+		assert.Equal(t, a, b)
+		assert.NotEqual(t, a, c)
+	})
+
+	t.Run("sample of the limited records", func(t *testing.T) {
+		got := Config{Limit: 20, Offset: 50, Sample: 5, Seed: 1}.Apply(arr).([]interface{})
+		require.Len(t, got, 5)
+		for _, v := range got {
+			assert.GreaterOrEqual(t, v.(int), 50)
+			assert.Less(t, v.(int), 70)
+		}
+	})
+
+	t.Run("sample larger than the array", func(t *testing.T) {
+		got := Config{Sample: 500}.Apply(arr)
+		assert.Equal(t, arr, got)
+	})
+
+	t.Run("map keys", func(t *testing.T) {
+		m := map[string]interface{}{"a": 1, "b": 2, "c": 3, "d": 4}
+		got := Config{Sample: 2, Seed: 3}.Apply(m).(map[string]interface{})
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("generic slice", func(t *testing.T) {
+		data := []string{"a", "b", "c", "d", "e"}
+		got := Config{Sample: 3, Seed: 3}.ApplyToGenericSlice(data).([]string)
+		assert.Len(t, got, 3)
+		assert.IsIncreasing(t, got)
+	})
+
+	t.Run("negative sample invalid", func(t *testing.T) {
+		err := Config{Sample: -1}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--sample")
+	})
+}
@@ -1,15 +1,17 @@
 package navigator
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
-	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/oakwood-commons/kvx/internal/cel"
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 // SortOrder defines how map keys are ordered when rendered.
@@ -19,6 +21,13 @@ const (
 	SortNone       SortOrder = "none"
 	SortAscending  SortOrder = "ascending"
 	SortDescending SortOrder = "descending"
+	// SortInsertion keeps the key order of the source document (JSON/YAML).
+	SortInsertion SortOrder = "insertion"
+	// SortSchema orders keys by the configured column/schema order first.
+	SortSchema SortOrder = "schema"
+	// SortNatural sorts keys with numbers in them in human order (item2
+	// before item10).
+	SortNatural SortOrder = "natural"
 )
 
 // ScalarValueKey is the display key used for scalar (non-map, non-array) values
@@ -27,18 +36,55 @@ const (
 // a scalar value that might contain decodable serialized data.
 const ScalarValueKey = "(value)"
 
-var currentSortOrder = SortAscending
-
 // SetSortOrder updates the global sort order for map key rendering and returns the previous value.
+// The order also applies to tree, list, and serialized (JSON/YAML) output.
+// Unknown orders are treated as SortNone.
 func SetSortOrder(order SortOrder) SortOrder {
-	prev := currentSortOrder
-	switch order {
-	case SortAscending, SortDescending, SortNone:
-		currentSortOrder = order
-	default:
-		currentSortOrder = SortNone
+	return SortOrder(keyorder.SetMode(keyorder.Mode(order)))
+}
+
+// CurrentSortOrder returns the global sort order.
+func CurrentSortOrder() SortOrder {
+	return SortOrder(keyorder.CurrentMode())
+}
+
+// SetSchemaOrder sets the preferred key order used by SortSchema, typically
+// the configured column order or the property order of a display schema.
+func SetSchemaOrder(keys []string) {
+	keyorder.SetSchemaOrder(keys)
+}
+
+// SetDocumentOrder sets the recorded key order of the loaded document, used
+// by SortInsertion. It replaces the order of any previously loaded document.
+func SetDocumentOrder(order *keyorder.Order) {
+	keyorder.SetDocument(order)
+}
+
+// OrderedKeys returns the keys of m in the current sort order. With SortNone
+// keys are returned alphabetically so callers get a stable order.
+func OrderedKeys(m map[string]interface{}) []string {
+	return keyorder.Keys(m)
+}
+
+// orderedRowKeys returns the keys of m for row rendering in the order of s.
+// SortNone keeps map iteration order, as rows have always done.
+func orderedRowKeys(m map[string]interface{}, s keyorder.Sorter) []string {
+	if s.Mode == keyorder.None {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+	return s.Keys(m)
+}
+
+// sortRowKeys orders keys collected from a reflected map for row rendering.
+func sortRowKeys(keys []string, s keyorder.Sorter) {
+	if s.Mode == keyorder.None {
+		return
 	}
-	return prev
+	s.Sort(keys)
 }
 
 // Debug controls whether navigator prints troubleshooting logs.
@@ -89,6 +135,15 @@ func NodeAtPath(root interface{}, path string) (interface{}, error) {
 		return root, nil
 	}
 
+	// Recursive descent: "..name" or "items..name" lists every "name" value
+	if base, key, ok := SplitDescent(trimmed); ok {
+		node, err := NodeAtPath(root, base)
+		if err != nil {
+			return nil, err
+		}
+		return cel.FindKey(node, key), nil
+	}
+
 	// Try simple path navigation first (dotted paths and bracket notation)
 	if !isComplexCEL(path) {
 		if Debug {
@@ -121,6 +176,31 @@ func NodeAtPath(root interface{}, path string) (interface{}, error) {
 	return result, nil
 }
 
+// SplitDescent splits a recursive-descent path such as "..name" or
+// "_.items..name" into the base path ("" or "_.items") and the key to
+// collect ("name"). It reports false for anything else, including paths
+// that continue after the key.
+func SplitDescent(path string) (base, key string, ok bool) {
+	idx := strings.LastIndex(path, "..")
+	if idx < 0 {
+		return "", "", false
+	}
+	key = path[idx+2:]
+	if key == "" {
+		return "", "", false
+	}
+	for _, ch := range key {
+		if !(ch == '_' || ch == '-' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9') {
+			return "", "", false
+		}
+	}
+	base = strings.TrimSpace(path[:idx])
+	if strings.HasSuffix(base, ".") {
+		return "", "", false
+	}
+	return base, key, true
+}
+
 // isComplexCEL checks if a path requires full CEL evaluation (not just simple navigation)
 func isComplexCEL(path string) bool {
 	// Treat quoted string literals as CEL (e.g., "hi")
@@ -162,7 +242,8 @@ func isComplexCEL(path string) bool {
 		return true
 	}
 	// Check for comparisons outside of brackets
-	comparisons := []string{"==", "!=", "<=", ">=", "<", ">", "&&", "||"}
+	// The single '|' also chains expressions into a pipeline
+	comparisons := []string{"==", "!=", "<=", ">=", "<", ">", "&&", "|"}
 	for _, op := range comparisons {
 		if strings.Contains(path, op) {
 			return true
@@ -179,15 +260,41 @@ func simpleNavigate(root interface{}, path string) (interface{}, error) {
 	//           "items[0]" -> ["items", "0"]
 	//           "items[0].tags" -> ["items", "0", "tags"]
 	parts := parsePath(path)
+	offset := 0
 	for _, p := range parts {
+		start := offset
+		if i := strings.Index(path[offset:], p); i >= 0 {
+			start = offset + i
+			offset = start + len(p)
+		}
 		cur = navigateStep(cur, p)
 		if errResult, ok := cur.(error); ok {
+			var missing *cel.ExprError
+			if errors.As(errResult, &missing) {
+				locateMissingKey(missing, path, start, p)
+			}
 			return nil, errResult
 		}
 	}
 	return cur, nil
 }
 
+// locateMissingKey fills in where in path the step starting at byte offset
+// start was looked up: the rune offset of its key and the path before it.
+func locateMissingKey(missing *cel.ExprError, path string, start int, step string) {
+	keyStart := start
+	if strings.HasPrefix(step, `"`) {
+		keyStart++
+	}
+	missing.Pos = utf8.RuneCountInString(path[:keyStart])
+	missing.Parent = strings.TrimRight(path[:start], ".[")
+}
+
+// keyNotFound reports a map lookup of a missing key.
+func keyNotFound(key string) error {
+	return &cel.ExprError{Kind: cel.KindNoSuchKey, Key: key, Pos: -1, Err: fmt.Errorf("key '%s' not found", key)}
+}
+
 // parsePath splits a path into navigation steps, handling both dot and bracket notation
 // Examples: "items.0" -> ["items", "0"]
 //
@@ -241,7 +348,7 @@ func navigateStep(cur interface{}, step string) interface{} {
 	case map[string]interface{}:
 		v, ok := t[key]
 		if !ok {
-			return fmt.Errorf("key '%s' not found", key)
+			return keyNotFound(key)
 		}
 		return v
 	case []interface{}:
@@ -275,7 +382,7 @@ func navigateStep(cur interface{}, step string) interface{} {
 			mapKey := reflect.ValueOf(key).Convert(rv.Type().Key())
 			value := rv.MapIndex(mapKey)
 			if !value.IsValid() {
-				return fmt.Errorf("key '%s' not found", key)
+				return keyNotFound(key)
 			}
 			return value.Interface()
 		case reflect.Slice, reflect.Array:
@@ -291,7 +398,7 @@ func navigateStep(cur interface{}, step string) interface{} {
 			if field, ok := structFieldValue(rv, key); ok {
 				return field
 			}
-			return fmt.Errorf("key '%s' not found", key)
+			return keyNotFound(key)
 		default:
 			return fmt.Errorf("cannot descend into %T at '%s'", cur, step)
 		}
@@ -319,77 +426,14 @@ func structFieldValue(rv reflect.Value, key string) (interface{}, bool) {
 
 // NodeToRows converts a node into rows of [key, value] pairs for table display
 func NodeToRows(node interface{}) [][]string {
-	var rows [][]string
-	switch t := node.(type) {
-	case map[string]interface{}:
-		// Treat empty maps as scalar values
-		if len(t) == 0 {
-			rows = append(rows, []string{ScalarValueKey, formatter.Stringify(node)})
-			return rows
-		}
-		keys := make([]string, 0, len(t))
-		for k := range t {
-			keys = append(keys, k)
-		}
-		switch currentSortOrder {
-		case SortAscending:
-			sort.Strings(keys)
-		case SortDescending:
-			sort.Strings(keys)
-			reverseStrings(keys)
-		case SortNone:
-			// Preserve natural/insertion order where possible (maps may be random)
-		}
-		for _, k := range keys {
-			v := t[k]
-			rows = append(rows, []string{k, formatter.Stringify(v)})
-		}
-	case []interface{}:
-		// Treat empty arrays as scalar values
-		if len(t) == 0 {
-			rows = append(rows, []string{ScalarValueKey, formatter.Stringify(node)})
-			return rows
-		}
-		for i, v := range t {
-			rows = append(rows, []string{fmt.Sprintf("[%d]", i), formatter.Stringify(v)})
-		}
-	default:
-		// Check if it's a map or slice type (could be []map, []string, typed maps, etc.)
-		rv := reflect.ValueOf(node)
-		if rv.Kind() == reflect.Map && rv.IsValid() && rv.Type().Key().Kind() == reflect.String {
-			if rv.Len() == 0 {
-				rows = append(rows, []string{ScalarValueKey, formatter.Stringify(node)})
-				return rows
-			}
-			keys := make([]string, 0, rv.Len())
-			for _, k := range rv.MapKeys() {
-				keys = append(keys, k.String())
-			}
-			switch currentSortOrder {
-			case SortAscending:
-				sort.Strings(keys)
-			case SortDescending:
-				sort.Strings(keys)
-				reverseStrings(keys)
-			case SortNone:
-			}
-			for _, k := range keys {
-				value := rv.MapIndex(reflect.ValueOf(k)).Interface()
-				rows = append(rows, []string{k, formatter.Stringify(value)})
-			}
-			return rows
-		}
+	return nodeToRows(node, ArrayStyleIndex, formatter.Stringify, keyorder.Active())
+}
 
-		if rv.Kind() == reflect.Slice {
-			for i := 0; i < rv.Len(); i++ {
-				v := rv.Index(i).Interface()
-				rows = append(rows, []string{fmt.Sprintf("[%d]", i), formatter.Stringify(v)})
-			}
-		} else {
-			rows = append(rows, []string{ScalarValueKey, formatter.Stringify(node)})
-		}
-	}
-	return rows
+// NodeToRowsSorted is NodeToRows with map keys, including those of nested
+// values, in the order of s instead of the global sort order.
+func NodeToRowsSorted(node interface{}, s keyorder.Sorter) [][]string {
+	stringify := func(v any) string { return formatter.StringifySorted(v, s) }
+	return nodeToRows(node, ArrayStyleIndex, stringify, s)
 }
 
 // ArrayStyle constants control how array indices are displayed.
@@ -417,95 +461,102 @@ func DefaultRowOptions() RowOptions {
 // NodeToRowsWithOptions converts a node into rows of [key, value] pairs for table display.
 // Uses the provided options to customize output format.
 func NodeToRowsWithOptions(node interface{}, opts RowOptions) [][]string {
-	var rows [][]string
+	return nodeToRows(node, opts.ArrayStyle, formatter.StringifyPreserveNewlines, keyorder.Active())
+}
+
+// rowBuilder hands out [key, value] rows cut from one backing array, so
+// building the rows of a large node takes two allocations instead of one
+// per row.
+type rowBuilder struct {
+	rows  [][]string
+	cells []string
+}
+
+func newRowBuilder(n int) *rowBuilder {
+	return &rowBuilder{rows: make([][]string, 0, n), cells: make([]string, 2*n)}
+}
+
+func (b *rowBuilder) add(key, value string) {
+	i := 2 * len(b.rows)
+	row := b.cells[i : i+2 : i+2]
+	row[0], row[1] = key, value
+	b.rows = append(b.rows, row)
+}
+
+// nodeToRows converts node into rows, showing values with stringify, keys in
+// the order of s and array indices in arrayStyle. Empty maps and arrays are
+// scalar values.
+func nodeToRows(node interface{}, arrayStyle string, stringify func(any) string, s keyorder.Sorter) [][]string {
+	scalar := func() [][]string {
+		return [][]string{{ScalarValueKey, stringify(node)}}
+	}
 	switch t := node.(type) {
 	case map[string]interface{}:
-		// Treat empty maps as scalar values
 		if len(t) == 0 {
-			rows = append(rows, []string{ScalarValueKey, formatter.StringifyPreserveNewlines(node)})
-			return rows
-		}
-		keys := make([]string, 0, len(t))
-		for k := range t {
-			keys = append(keys, k)
-		}
-		switch currentSortOrder {
-		case SortAscending:
-			sort.Strings(keys)
-		case SortDescending:
-			sort.Strings(keys)
-			reverseStrings(keys)
-		case SortNone:
+			return scalar()
 		}
+		keys := orderedRowKeys(t, s)
+		b := newRowBuilder(len(keys))
 		for _, k := range keys {
-			v := t[k]
-			rows = append(rows, []string{k, formatter.StringifyPreserveNewlines(v)})
+			b.add(k, stringify(t[k]))
 		}
+		return b.rows
+	case []byte:
+		// Binary data is a single value, not a list of bytes
+		return scalar()
 	case []interface{}:
-		// Treat empty arrays as scalar values
 		if len(t) == 0 {
-			rows = append(rows, []string{ScalarValueKey, formatter.StringifyPreserveNewlines(node)})
-			return rows
+			return scalar()
 		}
+		b := newRowBuilder(len(t))
 		for i, v := range t {
-			key := formatArrayIndex(i, opts.ArrayStyle)
-			rows = append(rows, []string{key, formatter.StringifyPreserveNewlines(v)})
-		}
-	default:
-		rv := reflect.ValueOf(node)
-		if rv.Kind() == reflect.Map && rv.IsValid() && rv.Type().Key().Kind() == reflect.String {
-			if rv.Len() == 0 {
-				rows = append(rows, []string{ScalarValueKey, formatter.StringifyPreserveNewlines(node)})
-				return rows
-			}
-			keys := make([]string, 0, rv.Len())
-			for _, k := range rv.MapKeys() {
-				keys = append(keys, k.String())
-			}
-			switch currentSortOrder {
-			case SortAscending:
-				sort.Strings(keys)
-			case SortDescending:
-				sort.Strings(keys)
-				reverseStrings(keys)
-			case SortNone:
-			}
-			for _, k := range keys {
-				value := rv.MapIndex(reflect.ValueOf(k)).Interface()
-				rows = append(rows, []string{k, formatter.StringifyPreserveNewlines(value)})
-			}
-			return rows
+			b.add(formatArrayIndex(i, arrayStyle), stringify(v))
 		}
+		return b.rows
+	}
 
-		if rv.Kind() == reflect.Slice {
-			for i := 0; i < rv.Len(); i++ {
-				v := rv.Index(i).Interface()
-				key := formatArrayIndex(i, opts.ArrayStyle)
-				rows = append(rows, []string{key, formatter.StringifyPreserveNewlines(v)})
-			}
-		} else {
-			rows = append(rows, []string{ScalarValueKey, formatter.StringifyPreserveNewlines(node)})
+	// Typed maps and slices, e.g. []map[string]string or map[string]int
+	rv := reflect.ValueOf(node)
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		if rv.Len() == 0 {
+			return scalar()
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sortRowKeys(keys, s)
+		b := newRowBuilder(len(keys))
+		for _, k := range keys {
+			b.add(k, stringify(rv.MapIndex(reflect.ValueOf(k)).Interface()))
 		}
+		return b.rows
+	case rv.Kind() == reflect.Slice:
+		if rv.Len() == 0 {
+			return nil
+		}
+		b := newRowBuilder(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			b.add(formatArrayIndex(i, arrayStyle), stringify(rv.Index(i).Interface()))
+		}
+		return b.rows
 	}
-	return rows
+	return scalar()
 }
 
 // formatArrayIndex formats an array index according to the specified style.
 func formatArrayIndex(index int, style string) string {
 	switch style {
 	case ArrayStyleNumbered:
-		return fmt.Sprintf("%d", index+1)
+		return strconv.Itoa(index + 1)
 	case ArrayStyleBullet:
 		return "•"
 	case ArrayStyleNone:
 		return ""
 	default: // ArrayStyleIndex
-		return fmt.Sprintf("[%d]", index)
-	}
-}
-
-func reverseStrings(values []string) {
-	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
-		values[i], values[j] = values[j], values[i]
+		var buf [24]byte
+		b := append(strconv.AppendInt(append(buf[:0], '['), int64(index), 10), ']')
+		return string(b)
 	}
 }
@@ -1,8 +1,11 @@
 package navigator
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -170,6 +173,43 @@ func TestNodeToRowsMap(t *testing.T) {
 	}
 }
 
+func TestNodeToRowsBytes(t *testing.T) {
+	rows := NodeToRows([]byte{0x89, 'P', 'N', 'G'})
+	require.Len(t, rows, 1)
+	assert.Equal(t, ScalarValueKey, rows[0][0])
+	assert.Equal(t, "<binary 4 bytes: 89 50 4e 47>", rows[0][1])
+}
+
+func TestNodeToRowsInsertionOrder(t *testing.T) {
+	prev := SetSortOrder(SortInsertion)
+	defer SetSortOrder(prev)
+	defer SetDocumentOrder(nil)
+
+	node := map[string]interface{}{"zeta": 1, "alpha": 2, "mid": 3}
+	order := keyorder.NewOrder()
+	order.Record(node, []string{"zeta", "alpha", "mid"})
+	SetDocumentOrder(order)
+
+	rows := NodeToRows(node)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"zeta", "alpha", "mid"}, []string{rows[0][0], rows[1][0], rows[2][0]})
+
+	// Maps without a recorded order fall back to alphabetical.
+	rows = NodeToRows(map[string]interface{}{"b": 1, "a": 2})
+	assert.Equal(t, "a", rows[0][0])
+}
+
+func TestNodeToRowsSchemaOrder(t *testing.T) {
+	prev := SetSortOrder(SortSchema)
+	defer SetSortOrder(prev)
+	SetSchemaOrder([]string{"name", "id"})
+	defer SetSchemaOrder(nil)
+
+	rows := NodeToRows(map[string]interface{}{"age": 1, "id": 2, "name": 3, "city": 4})
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"name", "id", "age", "city"}, []string{rows[0][0], rows[1][0], rows[2][0], rows[3][0]})
+}
+
 func TestNodeToRowsMapDescending(t *testing.T) {
 	prev := SetSortOrder(SortDescending)
 	defer SetSortOrder(prev)
@@ -864,3 +904,89 @@ func TestNodeToRowsWithOptions_ReflectMapNone(t *testing.T) {
 	rows := NodeToRowsWithOptions(node, DefaultRowOptions())
 	require.Len(t, rows, 2)
 }
+
+func TestSplitDescent(t *testing.T) {
+	tests := []struct {
+		path, base, key string
+		ok              bool
+	}{
+		{"..name", "", "name", true},
+		{"_..name", "_", "name", true},
+		{"_.items..id", "_.items", "id", true},
+		{"_.items", "", "", false},
+		{"_.items..", "", "", false},
+		{"..name.first", "", "", false},
+		{"_...name", "", "", false},
+	}
+	for _, tt := range tests {
+		base, key, ok := SplitDescent(tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.base, base, tt.path)
+		assert.Equal(t, tt.key, key, tt.path)
+	}
+}
+
+func TestNodeAtPathRecursiveDescent(t *testing.T) {
+	root := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"nested": map[string]interface{}{"name": "b"}},
+		},
+		"name": "top",
+	}
+	got, err := NodeAtPath(root, "..name")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", "top"}, got)
+
+	got, err = NodeAtPath(root, "_.items..name")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, got)
+
+	_, err = NodeAtPath(root, "_.missing..name")
+	require.Error(t, err)
+}
+
+// benchNodes returns a 50k-key map and a 50k-element array of mixed scalars.
+func benchNodes() (map[string]interface{}, []interface{}) {
+	const n = 50000
+	m := make(map[string]interface{}, n)
+	arr := make([]interface{}, n)
+	for i := range n {
+		var v interface{}
+		switch i % 4 {
+		case 0:
+			v = "value-" + strings.Repeat("x", i%20)
+		case 1:
+			v = int64(i)
+		case 2:
+			v = float64(i) / 3
+		default:
+			v = i%2 == 0
+		}
+		m[fmt.Sprintf("key-%05d", i)] = v
+		arr[i] = v
+	}
+	return m, arr
+}
+
+func BenchmarkNodeToRows(b *testing.B) {
+	m, arr := benchNodes()
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			NodeToRows(m)
+		}
+	})
+	b.Run("array", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			NodeToRows(arr)
+		}
+	})
+	b.Run("array with options", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			NodeToRowsWithOptions(arr, RowOptions{ArrayStyle: ArrayStyleNumbered})
+		}
+	})
+}
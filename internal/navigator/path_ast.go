@@ -120,3 +120,80 @@ func ReconstructPath(nodes []Node) string {
 	}
 	return b.String()
 }
+
+// PathSteps splits an expression that only navigates from the root, such as
+// `_.items[0]["bad-key"]`, into its keys and indexes. It reports false for
+// any other expression, whose result is computed rather than read from the
+// input. An empty expression or "_" is the root and has no steps.
+func PathSteps(expr string) ([]string, bool) {
+	p := strings.TrimSpace(expr)
+	if p == "" || p == "_" {
+		return nil, true
+	}
+	if !strings.HasPrefix(p, "_.") && !strings.HasPrefix(p, "_[") {
+		return nil, false
+	}
+	steps := []string{}
+	for i := 1; i < len(p); {
+		switch p[i] {
+		case '.':
+			j := i + 1
+			for j < len(p) && isIdentByte(p[j], j == i+1) {
+				j++
+			}
+			if j == i+1 {
+				return nil, false
+			}
+			steps = append(steps, p[i+1:j])
+			i = j
+		case '[':
+			step, n, ok := bracketStep(p[i+1:])
+			if !ok {
+				return nil, false
+			}
+			steps = append(steps, step)
+			i += n + 1
+		default:
+			return nil, false
+		}
+	}
+	return steps, true
+}
+
+// bracketStep parses the inside of a bracket step up to and including the
+// closing ']': a non-negative index or a quoted key. It returns the step and
+// the number of bytes consumed.
+func bracketStep(s string) (string, int, bool) {
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil || !strings.HasPrefix(s[len(quoted):], "]") {
+			return "", 0, false
+		}
+		key, err := strconv.Unquote(quoted)
+		if err != nil {
+			return "", 0, false
+		}
+		return key, len(quoted) + 1, true
+	}
+	if strings.HasPrefix(s, "'") {
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 || strings.ContainsRune(s[1:end+1], '\\') || !strings.HasPrefix(s[end+2:], "]") {
+			return "", 0, false
+		}
+		return s[1 : end+1], end + 3, true
+	}
+	end := strings.IndexByte(s, ']')
+	if end <= 0 {
+		return "", 0, false
+	}
+	for _, ch := range s[:end] {
+		if ch < '0' || ch > '9' {
+			return "", 0, false
+		}
+	}
+	return s[:end], end + 1, true
+}
+
+func isIdentByte(ch byte, first bool) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || !first && ch >= '0' && ch <= '9'
+}
@@ -126,3 +126,31 @@ func TestReconstructPath_Roundtrip(t *testing.T) {
 		})
 	}
 }
+
+func TestPathSteps(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+		ok   bool
+	}{
+		{"", nil, true},
+		{"_", nil, true},
+		{"_.b", []string{"b"}, true},
+		{` _.items[0].name `, []string{"items", "0", "name"}, true},
+		{`_.metadata["bad-key"]['a.b']`, []string{"metadata", "bad-key", "a.b"}, true},
+		{`_["x\"]"][1]`, []string{`x"]`, "1"}, true},
+		{"_[1].a", []string{"1", "a"}, true},
+		{"_.items.filter(x, x.ok)", nil, false},
+		{"_.a + 1", nil, false},
+		{"_.items[_.i]", nil, false},
+		{"_.items[-1]", nil, false},
+		{"_.1a", nil, false},
+		{"items.name", nil, false},
+		{"_.a.", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := PathSteps(tt.expr)
+		assert.Equal(t, tt.ok, ok, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+}
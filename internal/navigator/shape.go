@@ -2,9 +2,9 @@ package navigator
 
 import (
 	"reflect"
-	"sort"
 
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 )
 
 // ShapeKind describes the general structure of data.
@@ -113,8 +113,7 @@ func isHomogeneousInterfaceArray(arr []any) (bool, []string) {
 		}
 	}
 
-	sort.Strings(baseKeys)
-	return true, baseKeys
+	return true, keyorder.Keys(firstMap)
 }
 
 func isHomogeneousReflectArray(rv reflect.Value) (bool, []string) {
@@ -157,8 +156,7 @@ func isHomogeneousReflectArray(rv reflect.Value) (bool, []string) {
 		}
 	}
 
-	sort.Strings(baseKeys)
-	return true, baseKeys
+	return true, keyorder.Keys(firstMap)
 }
 
 // toStringKeyMap attempts to convert a value to a map with string keys.
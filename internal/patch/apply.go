@@ -0,0 +1,309 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseDocument reads a JSON Patch document, a list of operation objects,
+// into operations.
+func ParseDocument(v interface{}) ([]Operation, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("a JSON Patch is a list of operations")
+	}
+	ops := make([]Operation, len(list))
+	for i, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("operation %d is not an object", i)
+		}
+		op, _ := m["op"].(string)
+		path, ok := m["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("operation %d has no path", i)
+		}
+		ops[i] = Operation{Op: op, Path: path, Value: m["value"]}
+		switch op {
+		case "add", "replace", "test":
+			if _, ok := m["value"]; !ok {
+				return nil, fmt.Errorf("%s operation %d has no value", op, i)
+			}
+		case "move", "copy":
+			if ops[i].From, ok = m["from"].(string); !ok {
+				return nil, fmt.Errorf("%s operation %d has no from", op, i)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("operation %d: unknown op %q", i, op)
+		}
+	}
+	return ops, nil
+}
+
+// IsDocument reports whether v looks like a JSON Patch document rather than
+// a merge patch: a list whose elements are objects with an op member.
+func IsDocument(v interface{}) bool {
+	list, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["op"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns doc with ops applied in order. doc is not modified. The
+// first operation that fails, including a failed test, stops the patch.
+func Apply(doc interface{}, ops []Operation) (interface{}, error) {
+	doc = deepCopy(doc)
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOp(doc, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyOp(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return add(doc, path, deepCopy(op.Value))
+	case "remove":
+		return remove(doc, path)
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return deepCopy(op.Value), nil
+		}
+		return update(doc, path, func(parent interface{}, token string) (interface{}, error) {
+			return setChild(parent, token, deepCopy(op.Value))
+		})
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, deepCopy(value))
+		}
+		if op.Path == op.From {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("cannot move a value into itself")
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "test":
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(value, op.Value) {
+			return nil, errors.New("test failed: the value differs")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			i := len(p)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(p)+1); err != nil {
+					return nil, err
+				}
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %s", token, kind(parent))
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return update(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[token]; !ok {
+				return nil, fmt.Errorf("no member %q", token)
+			}
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(p))
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from a %s", token, kind(parent))
+	})
+}
+
+// update calls fn with the container of the value at path and its last
+// reference token, and returns doc with the container fn returns in place.
+// Arrays change length, so each container is stored back in its parent.
+func update(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	next, err := child(doc, path[0])
+	if err != nil {
+		return nil, err
+	}
+	if next, err = update(next, path[1:], fn); err != nil {
+		return nil, err
+	}
+	return setChild(doc, path[0], next)
+}
+
+// get returns the value at path.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		var err error
+		if doc, err = child(doc, token); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func child(parent interface{}, token string) (interface{}, error) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		v, ok := p[token]
+		if !ok {
+			return nil, fmt.Errorf("no member %q", token)
+		}
+		return v, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(p))
+		if err != nil {
+			return nil, err
+		}
+		return p[i], nil
+	}
+	return nil, fmt.Errorf("cannot look up %q in a %s", token, kind(parent))
+}
+
+// setChild replaces an existing member or element of parent.
+func setChild(parent interface{}, token string, value interface{}) (interface{}, error) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[token]; !ok {
+			return nil, fmt.Errorf("no member %q", token)
+		}
+		p[token] = value
+		return p, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(p))
+		if err != nil {
+			return nil, err
+		}
+		p[i] = value
+		return p, nil
+	}
+	return nil, fmt.Errorf("cannot set %q in a %s", token, kind(parent))
+}
+
+// arrayIndex parses an array reference token, which must be below limit.
+func arrayIndex(token string, limit int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens; ""
+// refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func kind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	if _, ok := number(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// deepCopy copies the maps and slices of v, so patching never changes the
+// caller's document.
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = deepCopy(e)
+		}
+		return out
+	}
+	return v
+}
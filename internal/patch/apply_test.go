@@ -0,0 +1,127 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	doc := map[string]interface{}{
+		"name": "web",
+		"tags": []interface{}{"a", "c"},
+		"spec": map[string]interface{}{"replicas": 1, "a/b": true},
+	}
+	ops, err := ParseDocument([]interface{}{
+		map[string]interface{}{"op": "test", "path": "/name", "value": "web"},
+		map[string]interface{}{"op": "add", "path": "/tags/1", "value": "b"},
+		map[string]interface{}{"op": "add", "path": "/tags/-", "value": "d"},
+		map[string]interface{}{"op": "replace", "path": "/spec/replicas", "value": 3},
+		map[string]interface{}{"op": "remove", "path": "/spec/a~1b"},
+		map[string]interface{}{"op": "copy", "from": "/name", "path": "/spec/name"},
+		map[string]interface{}{"op": "move", "from": "/tags/0", "path": "/first"},
+	})
+	require.NoError(t, err)
+
+	got, err := Apply(doc, ops)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":  "web",
+		"first": "a",
+		"tags":  []interface{}{"b", "c", "d"},
+		"spec":  map[string]interface{}{"replicas": 3, "name": "web"},
+	}, got)
+	assert.Equal(t, []interface{}{"a", "c"}, doc["tags"], "the input is not modified")
+	assert.Len(t, doc["spec"], 2)
+}
+
+func TestApply_Errors(t *testing.T) {
+	doc := map[string]interface{}{"a": []interface{}{1}}
+	for _, op := range []Operation{
+		{Op: "test", Path: "/a/0", Value: 2},
+		{Op: "remove", Path: "/b"},
+		{Op: "replace", Path: "/a/1", Value: 1},
+		{Op: "add", Path: "/a/01", Value: 1},
+		{Op: "add", Path: "/a/0/x", Value: 1},
+		{Op: "move", From: "/a", Path: "/a/0"},
+		{Op: "remove", Path: ""},
+		{Op: "add", Path: "a", Value: 1},
+	} {
+		_, err := Apply(doc, []Operation{op})
+		assert.Error(t, err, "%+v", op)
+	}
+}
+
+func TestApply_RoundTripsDiff(t *testing.T) {
+	from := map[string]interface{}{"a": 1, "b": []interface{}{1, 2, 3}, "c": map[string]interface{}{"d": "x"}}
+	to := map[string]interface{}{"a": 2, "b": []interface{}{1}, "c": map[string]interface{}{"e": nil}, "f": "new"}
+
+	got, err := Apply(from, Diff(from, to))
+	require.NoError(t, err)
+	assert.True(t, Equal(to, got))
+
+	ops, err := ParseDocument(Document(Diff(from, to)))
+	require.NoError(t, err)
+	assert.Equal(t, Diff(from, to), ops)
+}
+
+func TestApply_RoundTripsKeyedDiff(t *testing.T) {
+	obj := func(id, v int) map[string]interface{} {
+		return map[string]interface{}{"id": id, "v": v}
+	}
+	from := []interface{}{obj(1, 1), obj(2, 2), obj(3, 3), obj(4, 4), obj(5, 5)}
+	to := []interface{}{obj(5, 5), obj(6, 6), obj(3, 30), obj(1, 1), obj(2, 2)}
+
+	got, err := Apply(from, DiffWithOptions(from, to, Options{ArrayKeys: []string{"id"}}))
+	require.NoError(t, err)
+	assert.True(t, Equal(to, got))
+}
+
+func TestParseDocument_Errors(t *testing.T) {
+	for _, doc := range []interface{}{
+		map[string]interface{}{"op": "add"},
+		[]interface{}{"add"},
+		[]interface{}{map[string]interface{}{"op": "add", "path": "/a"}},
+		[]interface{}{map[string]interface{}{"op": "move", "path": "/a"}},
+		[]interface{}{map[string]interface{}{"op": "frobnicate", "path": "/a"}},
+	} {
+		_, err := ParseDocument(doc)
+		assert.Error(t, err, "%v", doc)
+	}
+}
+
+func TestIsDocument(t *testing.T) {
+	assert.True(t, IsDocument([]interface{}{map[string]interface{}{"op": "remove", "path": "/a"}}))
+	assert.True(t, IsDocument([]interface{}{}))
+	assert.False(t, IsDocument(map[string]interface{}{"op": "remove"}))
+	assert.False(t, IsDocument([]interface{}{1}))
+}
+
+func TestApplyMerge(t *testing.T) {
+	target := map[string]interface{}{
+		"title":  "Goodbye!",
+		"author": map[string]interface{}{"givenName": "John", "familyName": "Doe"},
+		"tags":   []interface{}{"example", "sample"},
+	}
+	patch := map[string]interface{}{
+		"title":       "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author":      map[string]interface{}{"familyName": nil},
+		"tags":        []interface{}{"example"},
+		"new":         map[string]interface{}{"x": nil, "y": 1},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"title":       "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author":      map[string]interface{}{"givenName": "John"},
+		"tags":        []interface{}{"example"},
+		"new":         map[string]interface{}{"y": 1},
+	}, ApplyMerge(target, patch))
+	assert.Equal(t, "Doe", target["author"].(map[string]interface{})["familyName"], "the input is not modified")
+
+	assert.Equal(t, []interface{}{1}, ApplyMerge(target, []interface{}{1}))
+	merge, err := MergePatch(map[string]interface{}{"title": "x"}, target)
+	require.NoError(t, err)
+	assert.True(t, Equal(target, ApplyMerge(map[string]interface{}{"title": "x"}, merge)))
+}
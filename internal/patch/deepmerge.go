@@ -0,0 +1,150 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Strategy decides how DeepMerge combines two values that are not both
+// objects, such as two arrays or two scalars.
+type Strategy string
+
+const (
+	// Override keeps the overlay's value.
+	Override Strategy = "override"
+	// Append concatenates arrays; other values are overridden.
+	Append Strategy = "append"
+	// Unique concatenates arrays without repeating equal elements; other
+	// values are overridden.
+	Unique Strategy = "unique"
+	// ErrorOnConflict fails when the values differ.
+	ErrorOnConflict Strategy = "error"
+)
+
+// Strategies lists the merge strategies in the order they are documented.
+var Strategies = []Strategy{Override, Append, Unique, ErrorOnConflict}
+
+// ParseStrategy returns the strategy named s.
+func ParseStrategy(s string) (Strategy, error) {
+	for _, st := range Strategies {
+		if string(st) == strings.ToLower(strings.TrimSpace(s)) {
+			return st, nil
+		}
+	}
+	return "", fmt.Errorf("unknown merge strategy %q (expected override, append, unique, or error)", s)
+}
+
+// MergeOptions control DeepMerge. The embedded Options name the fields that
+// identify array elements: keyed arrays are merged element by element, with
+// elements only the overlay has appended, whatever the strategy.
+type MergeOptions struct {
+	Options
+	// Strategy applies where Paths has no entry (default Override).
+	Strategy Strategy
+	// Paths maps JSON Pointers to the strategy for that value and
+	// everything below it.
+	Paths map[string]Strategy
+}
+
+// DeepMerge returns overlay merged into base: members of objects on both
+// sides are merged recursively, members of only one side are kept, and
+// other values are combined by the strategy in effect at their path. base
+// and overlay are not modified.
+func DeepMerge(base, overlay interface{}, opts MergeOptions) (interface{}, error) {
+	s := opts.Strategy
+	if s == "" {
+		s = Override
+	}
+	return opts.merge("", base, overlay, s)
+}
+
+func (o MergeOptions) merge(path string, base, overlay interface{}, s Strategy) (interface{}, error) {
+	if ps, ok := o.Paths[path]; ok {
+		s = ps
+	}
+	switch b := base.(type) {
+	case map[string]interface{}:
+		if ov, ok := overlay.(map[string]interface{}); ok {
+			out := make(map[string]interface{}, len(b)+len(ov))
+			for k, v := range b {
+				out[k] = v
+			}
+			for _, k := range sortedKeys(ov) {
+				bv, ok := out[k]
+				if !ok {
+					out[k] = ov[k]
+					continue
+				}
+				v, err := o.merge(path+"/"+EscapePointer(k), bv, ov[k], s)
+				if err != nil {
+					return nil, err
+				}
+				out[k] = v
+			}
+			return out, nil
+		}
+	case []interface{}:
+		if ov, ok := overlay.([]interface{}); ok {
+			if key := o.arrayKey(b, ov); key != "" {
+				return o.mergeKeyed(path, key, b, ov, s)
+			}
+			switch s {
+			case Append:
+				return append(append([]interface{}{}, b...), ov...), nil
+			case Unique:
+				var out []interface{}
+				for _, e := range append(append([]interface{}{}, b...), ov...) {
+					if !containsEqual(out, e) {
+						out = append(out, e)
+					}
+				}
+				return out, nil
+			}
+		}
+	}
+	if s == ErrorOnConflict && !Equal(base, overlay) {
+		return nil, fmt.Errorf("conflicting values at %s", describePointer(path))
+	}
+	return overlay, nil
+}
+
+// mergeKeyed merges the elements of keyed arrays that share a key and
+// appends the overlay's other elements.
+func (o MergeOptions) mergeKeyed(path, key string, base, overlay []interface{}, s Strategy) (interface{}, error) {
+	out := append([]interface{}{}, base...)
+	index := make(map[string]int, len(base))
+	for i, e := range base {
+		index[elementID(e, key)] = i
+	}
+	for _, e := range overlay {
+		i, ok := index[elementID(e, key)]
+		if !ok {
+			out = append(out, e)
+			continue
+		}
+		v, err := o.merge(path+"/"+strconv.Itoa(i), out[i], e, s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func containsEqual(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if Equal(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// describePointer names a JSON Pointer in error messages.
+func describePointer(path string) string {
+	if path == "" {
+		return "the document root"
+	}
+	return path
+}
@@ -0,0 +1,104 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepMerge_Strategies(t *testing.T) {
+	base := map[string]interface{}{
+		"name": "web",
+		"tags": []interface{}{"a", "b"},
+		"spec": map[string]interface{}{"replicas": 1, "ports": []interface{}{80}},
+	}
+	overlay := map[string]interface{}{
+		"tags": []interface{}{"b", "c"},
+		"spec": map[string]interface{}{"replicas": 3},
+		"env":  "prod",
+	}
+	tests := []struct {
+		strategy Strategy
+		tags     []interface{}
+	}{
+		{"", []interface{}{"b", "c"}},
+		{Override, []interface{}{"b", "c"}},
+		{Append, []interface{}{"a", "b", "b", "c"}},
+		{Unique, []interface{}{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			got, err := DeepMerge(base, overlay, MergeOptions{Strategy: tt.strategy})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{
+				"name": "web",
+				"tags": tt.tags,
+				"spec": map[string]interface{}{"replicas": 3, "ports": []interface{}{80}},
+				"env":  "prod",
+			}, got)
+		})
+	}
+	assert.Equal(t, []interface{}{"a", "b"}, base["tags"], "base is not modified")
+}
+
+func TestDeepMerge_ErrorOnConflict(t *testing.T) {
+	base := map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "x"}}
+
+	got, err := DeepMerge(base, map[string]interface{}{"a": 1.0, "d": 2}, MergeOptions{Strategy: ErrorOnConflict})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1.0, "b": map[string]interface{}{"c": "x"}, "d": 2}, got)
+
+	_, err = DeepMerge(base, map[string]interface{}{"b": map[string]interface{}{"c": "y"}}, MergeOptions{Strategy: ErrorOnConflict})
+	assert.EqualError(t, err, "conflicting values at /b/c")
+	_, err = DeepMerge(1, 2, MergeOptions{Strategy: ErrorOnConflict})
+	assert.EqualError(t, err, "conflicting values at the document root")
+}
+
+func TestDeepMerge_Paths(t *testing.T) {
+	base := map[string]interface{}{
+		"tags":   []interface{}{"a"},
+		"locked": map[string]interface{}{"v": 1, "list": []interface{}{1}},
+	}
+	overlay := map[string]interface{}{
+		"tags":   []interface{}{"b"},
+		"locked": map[string]interface{}{"list": []interface{}{2}},
+	}
+	opts := MergeOptions{Paths: map[string]Strategy{"/tags": Append, "/locked": ErrorOnConflict}}
+	_, err := DeepMerge(base, overlay, opts)
+	assert.EqualError(t, err, "conflicting values at /locked/list")
+
+	opts.Paths["/locked/list"] = Unique
+	got, err := DeepMerge(base, overlay, opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"tags":   []interface{}{"a", "b"},
+		"locked": map[string]interface{}{"v": 1, "list": []interface{}{1, 2}},
+	}, got)
+}
+
+func TestDeepMerge_ArrayKeys(t *testing.T) {
+	base := []interface{}{
+		map[string]interface{}{"name": "app", "image": "app:1", "port": 80},
+		map[string]interface{}{"name": "sidecar", "image": "proxy:1"},
+	}
+	overlay := []interface{}{
+		map[string]interface{}{"name": "app", "image": "app:2"},
+		map[string]interface{}{"name": "metrics", "image": "exporter:1"},
+	}
+	got, err := DeepMerge(base, overlay, MergeOptions{Options: Options{ArrayKeys: []string{"name"}}, Strategy: Append})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "app", "image": "app:2", "port": 80},
+		map[string]interface{}{"name": "sidecar", "image": "proxy:1"},
+		map[string]interface{}{"name": "metrics", "image": "exporter:1"},
+	}, got)
+}
+
+func TestParseStrategy(t *testing.T) {
+	s, err := ParseStrategy(" Unique ")
+	require.NoError(t, err)
+	assert.Equal(t, Unique, s)
+	_, err = ParseStrategy("deep")
+	assert.ErrorContains(t, err, "unknown merge strategy")
+}
@@ -0,0 +1,85 @@
+package patch
+
+import "fmt"
+
+// MergePatch returns the RFC 7386 merge patch that turns from into to:
+// changed object members hold their new value and removed ones null. A
+// value that is not an object on both sides, arrays included, is replaced
+// as a whole. A null member of to that is new or changed cannot be
+// expressed, since null in a merge patch removes the member, so it is an
+// error; use Diff for such documents.
+func MergePatch(from, to interface{}) (interface{}, error) {
+	return mergePatch("", from, to)
+}
+
+func mergePatch(path string, from, to interface{}) (interface{}, error) {
+	f, fok := from.(map[string]interface{})
+	t, tok := to.(map[string]interface{})
+	if !fok || !tok {
+		if err := checkMergeValue(path, to); err != nil {
+			return nil, err
+		}
+		return to, nil
+	}
+	patch := map[string]interface{}{}
+	for k := range f {
+		if _, ok := t[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for _, k := range sortedKeys(t) {
+		tv := t[k]
+		fv, ok := f[k]
+		if ok && Equal(fv, tv) {
+			continue
+		}
+		p, err := mergePatch(path+"/"+EscapePointer(k), fv, tv)
+		if err != nil {
+			return nil, err
+		}
+		patch[k] = p
+	}
+	return patch, nil
+}
+
+// checkMergeValue reports a null member of v, which a merge patch would
+// apply as a removal. Nulls inside arrays are kept, as arrays are replaced
+// whole.
+func checkMergeValue(path string, v interface{}) error {
+	if path != "" && v == nil {
+		return fmt.Errorf("%s is null, which a merge patch cannot express because null removes a member", path)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, k := range sortedKeys(m) {
+		if err := checkMergeValue(path+"/"+EscapePointer(k), m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyMerge returns target with an RFC 7386 merge patch applied: members
+// of an object patch are merged recursively and null members remove the
+// member; any other patch replaces target. target is not modified.
+func ApplyMerge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, _ := target.(map[string]interface{})
+	out := make(map[string]interface{}, len(t)+len(p))
+	for k, v := range t {
+		out[k] = v
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(out, k)
+		} else {
+			out[k] = ApplyMerge(out[k], v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,282 @@
+// Package patch computes the changes between two documents as an RFC 6902
+// JSON Patch or an RFC 7386 JSON Merge Patch, applies such patches, and
+// deep-merges layered documents.
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation is one RFC 6902 JSON Patch operation.
+type Operation struct {
+	Op    string      // "add", "remove", "replace", "move", "copy", or "test"
+	Path  string      // JSON Pointer (RFC 6901) of the changed value
+	From  string      // Source pointer of move and copy operations
+	Value interface{} // Value of add, replace, and test operations
+}
+
+// Options control how Diff and DeepMerge match array elements.
+type Options struct {
+	// ArrayKeys are fields that identify the elements of arrays of objects,
+	// such as "name" or "id". An array is matched by the first key every
+	// element of both sides has, with distinct scalar values; other arrays
+	// are compared by index.
+	ArrayKeys []string
+}
+
+// Diff returns the operations that turn from into to. Object members are
+// visited in key order. Array elements are compared by index: elements
+// past the end of the shorter array are added, or removed from the last
+// one down so earlier indexes stay valid.
+func Diff(from, to interface{}) []Operation {
+	return DiffWithOptions(from, to, Options{})
+}
+
+// DiffWithOptions is Diff with elements of keyed arrays matched by key, so
+// a reordered list of named objects yields move operations rather than a
+// replace of every element.
+func DiffWithOptions(from, to interface{}, opts Options) []Operation {
+	var ops []Operation
+	opts.diff("", from, to, &ops)
+	return ops
+}
+
+func (o Options) diff(path string, from, to interface{}, ops *[]Operation) {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			for _, k := range sortedKeys(f) {
+				if tv, ok := t[k]; ok {
+					o.diff(path+"/"+EscapePointer(k), f[k], tv, ops)
+				} else {
+					*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + EscapePointer(k)})
+				}
+			}
+			for _, k := range sortedKeys(t) {
+				if _, ok := f[k]; !ok {
+					*ops = append(*ops, Operation{Op: "add", Path: path + "/" + EscapePointer(k), Value: t[k]})
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			if key := o.arrayKey(f, t); key != "" {
+				o.diffKeyed(path, key, f, t, ops)
+				return
+			}
+			common := min(len(f), len(t))
+			for i := 0; i < common; i++ {
+				o.diff(path+"/"+strconv.Itoa(i), f[i], t[i], ops)
+			}
+			for i := common; i < len(t); i++ {
+				*ops = append(*ops, Operation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: t[i]})
+			}
+			for i := len(f) - 1; i >= common; i-- {
+				*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			}
+			return
+		}
+	}
+	if !Equal(from, to) {
+		*ops = append(*ops, Operation{Op: "replace", Path: path, Value: to})
+	}
+}
+
+// diffKeyed diffs arrays whose elements are identified by key: elements
+// missing from to are removed, then to is walked in order, moving each
+// remaining element into place or adding it, and diffing the elements
+// found on both sides.
+func (o Options) diffKeyed(path, key string, from, to []interface{}, ops *[]Operation) {
+	toIDs := make(map[string]bool, len(to))
+	for _, e := range to {
+		toIDs[elementID(e, key)] = true
+	}
+	byID := make(map[string]interface{}, len(from))
+	var current []string // IDs of the elements in patch order
+	for _, e := range from {
+		id := elementID(e, key)
+		byID[id] = e
+		current = append(current, id)
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		if !toIDs[current[i]] {
+			*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			current = append(current[:i], current[i+1:]...)
+		}
+	}
+	for i, e := range to {
+		id := elementID(e, key)
+		elemPath := path + "/" + strconv.Itoa(i)
+		old, ok := byID[id]
+		if !ok {
+			*ops = append(*ops, Operation{Op: "add", Path: elemPath, Value: e})
+			current = append(current[:i], append([]string{id}, current[i:]...)...)
+			continue
+		}
+		if j := indexOf(current, id); j != i {
+			*ops = append(*ops, Operation{Op: "move", From: path + "/" + strconv.Itoa(j), Path: elemPath})
+			current = append(current[:j], current[j+1:]...)
+			current = append(current[:i], append([]string{id}, current[i:]...)...)
+		}
+		o.diff(elemPath, old, e, ops)
+	}
+}
+
+// arrayKey returns the first of ArrayKeys that identifies the elements of
+// both arrays, or "".
+func (o Options) arrayKey(from, to []interface{}) string {
+	for _, key := range o.ArrayKeys {
+		if identifies(from, key) && identifies(to, key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// identifies reports whether every element of list is an object with a
+// distinct scalar value for key.
+func identifies(list []interface{}, key string) bool {
+	seen := make(map[string]bool, len(list))
+	for _, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch m[key].(type) {
+		case map[string]interface{}, []interface{}, nil:
+			return false
+		}
+		id := elementID(e, key)
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// elementID is the key value of an element, as text so 1 and 1.0 match.
+func elementID(e interface{}, key string) string {
+	m, _ := e.(map[string]interface{})
+	if r, ok := number(m[key]); ok {
+		return r.RatString()
+	}
+	return fmt.Sprint(m[key])
+}
+
+func indexOf(ids []string, id string) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Document returns ops as a JSON Patch document: a list of objects with
+// op and path members, plus from or value where the operation takes one.
+func Document(ops []Operation) []interface{} {
+	doc := make([]interface{}, len(ops))
+	for i, op := range ops {
+		m := map[string]interface{}{"op": op.Op, "path": op.Path}
+		switch op.Op {
+		case "move", "copy":
+			m["from"] = op.From
+		case "add", "replace", "test":
+			m["value"] = op.Value
+		}
+		doc[i] = m
+	}
+	return doc
+}
+
+// EscapePointer escapes a key for use as a JSON Pointer reference token.
+func EscapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// Equal reports whether a and b are the same JSON value. Numbers are
+// compared by value, so 1 read from YAML equals 1.0 read from JSON.
+func Equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !Equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !Equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x.Cmp(y) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// number returns v as an exact rational when it is a finite number.
+func number(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	case float32:
+		r := new(big.Rat).SetFloat64(float64(n))
+		return r, r != nil
+	case float64:
+		r := new(big.Rat).SetFloat64(n)
+		return r, r != nil
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
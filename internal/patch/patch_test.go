@@ -0,0 +1,156 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	from := map[string]interface{}{
+		"name":   "web",
+		"port":   80,
+		"tags":   []interface{}{"a", "b", "c"},
+		"old":    true,
+		"a/b~c":  1,
+		"nested": map[string]interface{}{"x": 1, "y": 2},
+	}
+	to := map[string]interface{}{
+		"name":   "web",
+		"port":   8080,
+		"tags":   []interface{}{"a", "x"},
+		"new":    nil,
+		"a/b~c":  2.0,
+		"nested": map[string]interface{}{"x": 1.0, "z": []interface{}{}},
+	}
+	assert.Equal(t, []Operation{
+		{Op: "replace", Path: "/a~1b~0c", Value: 2.0},
+		{Op: "remove", Path: "/nested/y"},
+		{Op: "add", Path: "/nested/z", Value: []interface{}{}},
+		{Op: "remove", Path: "/old"},
+		{Op: "replace", Path: "/port", Value: 8080},
+		{Op: "replace", Path: "/tags/1", Value: "x"},
+		{Op: "remove", Path: "/tags/2"},
+		{Op: "add", Path: "/new", Value: nil},
+	}, Diff(from, to))
+}
+
+func TestDiff_Arrays(t *testing.T) {
+	assert.Equal(t, []Operation{
+		{Op: "add", Path: "/2", Value: 3},
+		{Op: "add", Path: "/3", Value: 4},
+	}, Diff([]interface{}{1, 2}, []interface{}{1, 2, 3, 4}))
+	assert.Equal(t, []Operation{
+		{Op: "remove", Path: "/3"},
+		{Op: "remove", Path: "/2"},
+	}, Diff([]interface{}{1, 2, 3, 4}, []interface{}{1, 2}))
+}
+
+func TestDiffWithOptions_ArrayKeys(t *testing.T) {
+	obj := func(name string, port int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "port": port}
+	}
+	from := []interface{}{obj("a", 1), obj("b", 2), obj("c", 3)}
+	to := []interface{}{obj("c", 3), obj("a", 10), obj("d", 4)}
+	opts := Options{ArrayKeys: []string{"id", "name"}}
+
+	assert.Equal(t, []Operation{
+		{Op: "remove", Path: "/1"},
+		{Op: "move", From: "/1", Path: "/0"},
+		{Op: "replace", Path: "/1/port", Value: 10},
+		{Op: "add", Path: "/2", Value: obj("d", 4)},
+	}, DiffWithOptions(from, to, opts))
+
+	// Duplicate or missing keys fall back to comparing by index.
+	dup := []interface{}{obj("a", 1), obj("a", 2)}
+	assert.Equal(t, Diff(from, dup), DiffWithOptions(from, dup, opts))
+	assert.Equal(t, Diff(from, []interface{}{"a"}), DiffWithOptions(from, []interface{}{"a"}, opts))
+}
+
+func TestDiff_Root(t *testing.T) {
+	assert.Empty(t, Diff(map[string]interface{}{"a": 1}, map[string]interface{}{"a": json.Number("1")}))
+	assert.Equal(t, []Operation{{Op: "replace", Path: "", Value: "b"}}, Diff([]interface{}{"a"}, "b"))
+}
+
+func TestDocument(t *testing.T) {
+	doc := Document([]Operation{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "remove", Path: "/b"},
+	})
+	out, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"}]`, string(out))
+}
+
+func TestMergePatch(t *testing.T) {
+	from := map[string]interface{}{
+		"title":   "Goodbye!",
+		"author":  map[string]interface{}{"givenName": "John", "familyName": "Doe"},
+		"tags":    []interface{}{"example", "sample"},
+		"content": "This will be unchanged",
+	}
+	to := map[string]interface{}{
+		"title":       "Hello!",
+		"author":      map[string]interface{}{"givenName": "John"},
+		"tags":        []interface{}{"example"},
+		"content":     "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890",
+	}
+	merge, err := MergePatch(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"title":       "Hello!",
+		"author":      map[string]interface{}{"familyName": nil},
+		"tags":        []interface{}{"example"},
+		"phoneNumber": "+01-123-456-7890",
+	}, merge)
+
+	merge, err = MergePatch(from, from)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, merge)
+	merge, err = MergePatch(from, []interface{}{1})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1}, merge)
+}
+
+func TestMergePatch_RoundTrip(t *testing.T) {
+	from := map[string]interface{}{
+		"a":    map[string]interface{}{"b": 1, "c": nil},
+		"list": []interface{}{1, nil},
+		"gone": true,
+	}
+	to := map[string]interface{}{
+		"a":    map[string]interface{}{"b": 2, "c": nil},
+		"list": []interface{}{nil, 2},
+		"new":  map[string]interface{}{"x": 1},
+	}
+	merge, err := MergePatch(from, to)
+	require.NoError(t, err)
+	assert.True(t, Equal(to, ApplyMerge(from, merge)), "unchanged nulls and nulls in arrays survive")
+
+	for name, target := range map[string]interface{}{
+		"/new":      map[string]interface{}{"a": from["a"], "list": from["list"], "gone": true, "new": nil},
+		"/gone":     map[string]interface{}{"a": from["a"], "list": from["list"], "gone": nil},
+		"/a/b":      map[string]interface{}{"a": map[string]interface{}{"b": nil, "c": nil}, "list": from["list"], "gone": true},
+		"/obj/k~1v": map[string]interface{}{"a": from["a"], "list": from["list"], "gone": true, "obj": map[string]interface{}{"k/v": nil}},
+	} {
+		_, err := MergePatch(from, target)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), name+" is null", name)
+		// A JSON Patch keeps the null.
+		applied, err := Apply(from, Diff(from, target))
+		require.NoError(t, err, name)
+		assert.True(t, Equal(target, applied), name)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, Equal(1, 1.0))
+	assert.True(t, Equal(json.Number("9007199254740993"), json.Number("9007199254740993")))
+	assert.False(t, Equal(json.Number("9007199254740993"), 9007199254740992.0))
+	assert.False(t, Equal(1, "1"))
+	assert.True(t, Equal([]interface{}{map[string]interface{}{"a": uint8(2)}}, []interface{}{map[string]interface{}{"a": int64(2)}}))
+	assert.False(t, Equal(nil, map[string]interface{}{}))
+}
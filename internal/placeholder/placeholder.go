@@ -0,0 +1,189 @@
+// Package placeholder finds {{name}} placeholders in CEL expressions and
+// replaces them with CEL literals, so a saved expression can be reused with
+// values asked for or passed in on each run.
+//
+// A placeholder is written {{name}}, {{name=default}}, or {{name in EXPR}},
+// where EXPR is a CEL expression evaluated against the input whose result
+// lists the values to choose from. A name may appear more than once; every
+// occurrence takes the same value.
+package placeholder
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholder is one named value an expression asks for.
+type Placeholder struct {
+	Name string
+	// Default is the value used when none is given, "" for none.
+	Default string
+	// HasDefault reports whether Default was set, so {{name=}} defaults to "".
+	HasDefault bool
+	// Choices is a CEL expression listing the values to choose from, or "".
+	Choices string
+}
+
+var (
+	placeholderRE = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	nameRE        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// Parse returns the placeholders of expr in order of first appearance. A
+// default or choice list given on any occurrence of a name applies to all
+// of them; giving two different ones is an error.
+func Parse(expr string) ([]Placeholder, error) {
+	var out []Placeholder
+	index := map[string]int{}
+	for _, m := range placeholderRE.FindAllStringSubmatch(expr, -1) {
+		p, err := parseSpec(m[1])
+		if err != nil {
+			return nil, err
+		}
+		i, seen := index[p.Name]
+		if !seen {
+			index[p.Name] = len(out)
+			out = append(out, p)
+			continue
+		}
+		prev := &out[i]
+		if p.HasDefault {
+			if prev.HasDefault && prev.Default != p.Default {
+				return nil, fmt.Errorf("placeholder %s has two defaults: %q and %q", p.Name, prev.Default, p.Default)
+			}
+			prev.Default, prev.HasDefault = p.Default, true
+		}
+		if p.Choices != "" {
+			if prev.Choices != "" && prev.Choices != p.Choices {
+				return nil, fmt.Errorf("placeholder %s has two choice lists", p.Name)
+			}
+			prev.Choices = p.Choices
+		}
+	}
+	return out, nil
+}
+
+// parseSpec parses the text between {{ and }}.
+func parseSpec(spec string) (Placeholder, error) {
+	s := strings.TrimSpace(spec)
+	name := nameRE.FindString(s)
+	if name == "" {
+		return Placeholder{}, fmt.Errorf("invalid placeholder {{%s}}: expected a name such as {{env}}", spec)
+	}
+	p := Placeholder{Name: name}
+	rest := strings.TrimSpace(s[len(name):])
+	switch {
+	case rest == "":
+	case strings.HasPrefix(rest, "="):
+		p.Default, p.HasDefault = strings.TrimSpace(rest[1:]), true
+	case strings.HasPrefix(rest, "in ") || strings.HasPrefix(rest, "in\t"):
+		p.Choices = strings.TrimSpace(rest[2:])
+	default:
+		return Placeholder{}, fmt.Errorf("invalid placeholder {{%s}}: expected {{%s}}, {{%s=default}}, or {{%s in EXPR}}", spec, name, name, name)
+	}
+	return p, nil
+}
+
+// Expand replaces every placeholder of expr with the CEL literal of its
+// value in values. A placeholder without a value is an error.
+func Expand(expr string, values map[string]interface{}) (string, error) {
+	var missing string
+	out := placeholderRE.ReplaceAllStringFunc(expr, func(m string) string {
+		name := nameRE.FindString(strings.TrimSpace(m[2 : len(m)-2]))
+		v, ok := values[name]
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return m
+		}
+		return Literal(v)
+	})
+	if missing != "" {
+		return "", fmt.Errorf("no value for placeholder {{%s}}", missing)
+	}
+	return out, nil
+}
+
+// Raw is CEL source inserted by Expand as written, such as dyn(null) to
+// stand in for a value of any type.
+type Raw string
+
+// Literal returns v written as a CEL literal. Values other than strings,
+// numbers, booleans, null, and Raw are written as their quoted text.
+func Literal(v interface{}) string {
+	switch t := v.(type) {
+	case Raw:
+		return string(t)
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case int:
+		return strconv.Itoa(t)
+	case int32:
+		return strconv.FormatInt(int64(t), 10)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case uint:
+		return strconv.FormatUint(uint64(t), 10) + "u"
+	case uint32:
+		return strconv.FormatUint(uint64(t), 10) + "u"
+	case uint64:
+		return strconv.FormatUint(t, 10) + "u"
+	case float32:
+		return floatLiteral(float64(t))
+	case float64:
+		return floatLiteral(t)
+	}
+	return strconv.Quote(fmt.Sprint(v))
+}
+
+func floatLiteral(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return `double("NaN")`
+	case math.IsInf(f, 1):
+		return `double("Infinity")`
+	case math.IsInf(f, -1):
+		return `double("-Infinity")`
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// Value converts text typed for a placeholder to the value it stands for:
+// an integer, a double, true, false, or null when it reads as one, and a
+// string otherwise. Text in double quotes is always a string, so "42"
+// stays text.
+func Value(text string) interface{} {
+	s := strings.TrimSpace(text)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package placeholder
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse(`_.items.filter(i, i.env == {{env in _.items.map(i, i.env)}} && i.stock > {{min=10}}) + [{{env}}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Placeholder{
+		{Name: "env", Choices: "_.items.map(i, i.env)"},
+		{Name: "min", Default: "10", HasDefault: true},
+	}, got)
+
+	got, err = Parse(`_.name == {{ name = }}`)
+	require.NoError(t, err)
+	assert.Equal(t, []Placeholder{{Name: "name", HasDefault: true}}, got)
+
+	got, err = Parse(`_.items`)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{
+		`_.a == {{}}`,
+		`_.a == {{1x}}`,
+		`_.a == {{env: prod}}`,
+		`{{env=a}} + {{env=b}}`,
+		`{{env in _.a}} + {{env in _.b}}`,
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	got, err := Expand(`_.items.filter(i, i.env == {{env in _.envs}} && i.stock > {{ min=10 }})`,
+		map[string]interface{}{"env": `pr"od`, "min": int64(5)})
+	require.NoError(t, err)
+	assert.Equal(t, `_.items.filter(i, i.env == "pr\"od" && i.stock > 5)`, got)
+
+	_, err = Expand(`_.a == {{a}} && _.b == {{b}}`, map[string]interface{}{"a": 1})
+	assert.EqualError(t, err, "no value for placeholder {{b}}")
+}
+
+func TestLiteral(t *testing.T) {
+	for _, tc := range []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, "null"},
+		{"a\nb", `"a\nb"`},
+		{true, "true"},
+		{42, "42"},
+		{int64(-3), "-3"},
+		{uint64(7), "7u"},
+		{2.0, "2.0"},
+		{1.5, "1.5"},
+		{1e21, "1e+21"},
+		{math.Inf(-1), `double("-Infinity")`},
+		{Raw("dyn(null)"), "dyn(null)"},
+	} {
+		assert.Equal(t, tc.want, Literal(tc.in), "%v", tc.in)
+	}
+}
+
+func TestValue(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want interface{}
+	}{
+		{"prod", "prod"},
+		{" prod ", "prod"},
+		{"42", int64(42)},
+		{"1.5", 1.5},
+		{"true", true},
+		{"null", nil},
+		{`"42"`, "42"},
+		{"inf", "inf"},
+	} {
+		assert.Equal(t, tc.want, Value(tc.in), tc.in)
+	}
+}
@@ -0,0 +1,268 @@
+package qr
+
+// qrBuilder lays out the modules of a code being encoded.
+type qrBuilder struct {
+	version    int
+	size       int
+	modules    [][]bool // Dark modules, indexed [y][x]
+	isFunction [][]bool // Modules of finder, timing, alignment, format, and version patterns
+}
+
+func (q *qrBuilder) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing, alignment, and version
+// patterns, and reserves the format information modules.
+func (q *qrBuilder) drawFunctionPatterns() {
+	for i := range q.size {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	pos := q.alignmentPositions()
+	n := len(pos)
+	for i := range n {
+		for j := range n {
+			// Skip the three corners taken by finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			q.drawAlignment(pos[i], pos[j])
+		}
+	}
+
+	q.drawFormatBits(0) // Reserved; drawn again once the mask is chosen
+	q.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on x, y.
+func (q *qrBuilder) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= q.size || yy >= q.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			q.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centred on x, y.
+func (q *qrBuilder) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the row and column centres of the alignment
+// patterns, in ascending order.
+func (q *qrBuilder) alignmentPositions() []int {
+	if q.version == 1 {
+		return nil
+	}
+	numAlign := q.version/7 + 2
+	step := 26
+	if q.version != 32 {
+		step = (q.version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	}
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, q.size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// drawFormatBits draws both copies of the format information for level L
+// and mask, and the dark module.
+func (q *qrBuilder) drawFormatBits(mask int) {
+	bits := formatInfo(mask)
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(bits, i))
+	}
+	q.setFunction(8, 7, bit(bits, 6))
+	q.setFunction(8, 8, bit(bits, 7))
+	q.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := range 8 {
+		q.setFunction(q.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(bits, i))
+	}
+	q.setFunction(8, q.size-8, true)
+}
+
+// formatInfo returns the 15 format information bits of level L and mask:
+// the level and mask, their BCH error correction, and the format mask.
+func formatInfo(mask int) int {
+	data := formatBitsL<<3 | mask
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionInfo returns the 18 version information bits of a version: the
+// version and its BCH error correction.
+func versionInfo(version int) int {
+	rem := version
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+// drawVersion draws both copies of the version information, from version 7.
+func (q *qrBuilder) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	bits := versionInfo(q.version)
+	for i := range 18 {
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, bit(bits, i))
+		q.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords fills the data modules in the zigzag order, two columns
+// at a time from the bottom right.
+func (q *qrBuilder) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := range q.size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert // Upward
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = bit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask.
+func (q *qrBuilder) applyMask(mask int) {
+	for y := range q.size {
+		for x := range q.size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the layout by the standard rules, lower being easier to
+// scan: runs of one color, 2x2 blocks, finder-like patterns, and imbalance
+// of dark and light.
+func (q *qrBuilder) penalty() int {
+	score := 0
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+	finderLike := [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+	for _, transpose := range []bool{false, true} {
+		for y := range q.size {
+			run := 1
+			for x := 1; x <= q.size; x++ {
+				if x < q.size && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			for x := 0; x+11 <= q.size; x++ {
+				forward, backward := true, true
+				for k := range 11 {
+					m := at(x+k, y, transpose)
+					forward = forward && m == finderLike[k]
+					backward = backward && m == finderLike[10-k]
+				}
+				if forward {
+					score += 40
+				}
+				if backward {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := range q.size {
+		for x := range q.size {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				m := q.modules[y][x]
+				if m == q.modules[y][x+1] && m == q.modules[y+1][x] && m == q.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := q.size * q.size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+func bit(x, i int) bool {
+	return x>>i&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
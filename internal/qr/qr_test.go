@@ -0,0 +1,171 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at version 1-M, from the worked example of the standard.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	assert.Equal(t, want, reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestFormatAndVersionInfo(t *testing.T) {
+	want := []int{
+		0b111011111000100, 0b111001011110011, 0b111110110101010, 0b111100010011101,
+		0b110011000101111, 0b110001100011000, 0b110110001000001, 0b110100101110110,
+	}
+	for mask, bits := range want {
+		assert.Equal(t, bits, formatInfo(mask), "mask %d", mask)
+	}
+	assert.Equal(t, 0x07C94, versionInfo(7))
+	assert.Equal(t, 0x28C69, versionInfo(40))
+}
+
+func TestEncodeVersion(t *testing.T) {
+	for _, tc := range []struct {
+		n, size int
+	}{
+		{0, 21}, {17, 21}, {18, 25}, {32, 25}, {78, 33}, {271, 57}, {2953, 177},
+	} {
+		c, err := Encode(strings.Repeat("a", tc.n))
+		require.NoError(t, err, tc.n)
+		assert.Equal(t, tc.size, c.Size, "%d bytes", tc.n)
+	}
+	_, err := Encode(strings.Repeat("a", 2954))
+	assert.ErrorIs(t, err, ErrTooLong)
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"https://microsoft.com/devicelogin",
+		"EH5HFPGJJ",
+		strings.Repeat("https://example.com/verify?code=ABCD-1234&", 10),
+		strings.Repeat("0123456789", 150),
+	} {
+		c, err := Encode(text)
+		require.NoError(t, err)
+		assert.Equal(t, text, decode(t, c), "version %d", (c.Size-17)/4)
+	}
+}
+
+func TestHalfBlocks(t *testing.T) {
+	c, err := Encode("kvx")
+	require.NoError(t, err)
+	lines := c.HalfBlocks(2)
+	require.Len(t, lines, 13) // (21 + 4) / 2 rounded up
+	assert.Equal(t, strings.Repeat(" ", 25), lines[0])
+	// The second line holds the finder pattern's top edge over its hollow row.
+	assert.True(t, strings.HasPrefix(lines[1], "  █▀▀▀▀▀█ "))
+	for _, line := range lines {
+		assert.Equal(t, 25, len([]rune(line)))
+	}
+}
+
+// decode reads text back from a code: the format information, the
+// unmasked codewords in placement order, each block checked against its
+// error correction, and the byte mode segment.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	version := (c.Size - 17) / 4
+	q := &qrBuilder{version: version, size: c.Size, modules: makeGrid(c.Size), isFunction: makeGrid(c.Size)}
+	q.drawFunctionPatterns()
+
+	format := 0
+	for i := 0; i <= 5; i++ {
+		format |= btoi(c.Dark(8, i)) << i
+	}
+	format |= btoi(c.Dark(8, 7))<<6 | btoi(c.Dark(8, 8))<<7 | btoi(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		format |= btoi(c.Dark(14-i, 8)) << i
+	}
+	mask := -1
+	for m := range 8 {
+		if formatInfo(m) == format {
+			mask = m
+		}
+	}
+	require.GreaterOrEqual(t, mask, 0, "format information")
+
+	for y := range c.Size {
+		for x := range c.Size {
+			q.modules[y][x] = c.Dark(x, y)
+		}
+	}
+	q.applyMask(mask)
+
+	var raw []byte
+	var cur, n int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if q.isFunction[y][x] {
+					continue
+				}
+				cur = cur<<1 | btoi(q.modules[y][x])
+				if n++; n%8 == 0 {
+					raw = append(raw, byte(cur))
+					cur = 0
+				}
+			}
+		}
+	}
+
+	numBlocks := numErrorCorrectionBlocks[version]
+	eccLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	require.GreaterOrEqual(t, len(raw), rawCodewords)
+	numShort := numBlocks - rawCodewords%numBlocks
+	shortLen := rawCodewords / numBlocks
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for _, b := range blocks {
+		dat, ecc := b[:len(b)-eccLen], b[len(b)-eccLen:]
+		require.Equal(t, ecc, reedSolomonRemainder(dat, reedSolomonDivisor(eccLen)))
+		data = append(data, dat...)
+	}
+
+	pos := 0
+	read := func(bits int) int {
+		v := 0
+		for range bits {
+			v = v<<1 | int(data[pos>>3]>>(7-pos&7)&1)
+			pos++
+		}
+		return v
+	}
+	require.Equal(t, 0x4, read(4), "byte mode")
+	out := make([]byte, read(charCountBits(version)))
+	for i := range out {
+		out[i] = byte(read(8))
+	}
+	return string(out)
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,164 @@
+// Package sampledata generates synthetic documents of a few familiar shapes
+// for demos, bug reports, and benchmarks, so no real data has to be shared.
+// The same shape, row count, and seed always generate the same document.
+package sampledata
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// Shapes lists the shapes Generate knows.
+var Shapes = []string{"users", "k8s", "metrics"}
+
+// epoch is the time the generated timestamps count from, so they do not
+// depend on when the document is generated.
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Generate returns a document of shape with rows records, drawn from seed:
+//
+//	users    an array of user accounts
+//	k8s      a Kubernetes List of Pods
+//	metrics  an array of per-minute service metrics
+func Generate(shape string, rows int, seed uint64) (interface{}, error) {
+	if rows < 0 {
+		return nil, fmt.Errorf("--rows must be non-negative, got %d", rows)
+	}
+	r := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // sample data, not security
+	switch shape {
+	case "users":
+		return users(r, rows), nil
+	case "k8s":
+		return pods(r, rows), nil
+	case "metrics":
+		return metrics(r, rows), nil
+	default:
+		return nil, fmt.Errorf("unknown sample shape %q (expected %s)", shape, strings.Join(Shapes, ", "))
+	}
+}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Rob", "Sophie", "Tim", "Yukihiro"}
+	lastNames  = []string{"Allen", "Hopper", "Kernighan", "Knuth", "Lamport", "Liskov", "Lovelace", "Perlman", "Pike", "Ritchie", "Stroustrup", "Thompson", "Torvalds", "Turing", "Wilson", "Wirth"}
+	cities     = [][2]string{{"Amsterdam", "NL"}, {"Austin", "US"}, {"Berlin", "DE"}, {"Lisbon", "PT"}, {"Nairobi", "KE"}, {"Osaka", "JP"}, {"São Paulo", "BR"}, {"Toronto", "CA"}}
+	roles      = []string{"admin", "editor", "viewer", "viewer", "viewer"}
+	userTags   = []string{"beta", "billing", "early-adopter", "mfa", "newsletter", "support"}
+	services   = []string{"api", "auth", "billing", "search", "worker"}
+	namespaces = []string{"default", "payments", "platform", "search"}
+	phases     = []string{"Running", "Running", "Running", "Running", "Pending", "Failed", "Succeeded"}
+	hosts      = []string{"node-a", "node-b", "node-c"}
+)
+
+func pick[T any](r *rand.Rand, from []T) T {
+	return from[r.IntN(len(from))]
+}
+
+// suffix returns a random lowercase alphanumeric string of n characters, as
+// in the generated names of Kubernetes objects.
+func suffix(r *rand.Rand, n int) string {
+	const chars = "bcdfghjklmnpqrstvwxz2456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = chars[r.IntN(len(chars))]
+	}
+	return string(b)
+}
+
+// round rounds f to two decimals.
+func round(f float64) float64 {
+	return float64(int64(f*100+0.5)) / 100
+}
+
+func users(r *rand.Rand, rows int) []interface{} {
+	out := make([]interface{}, rows)
+	for i := range out {
+		first, last := pick(r, firstNames), pick(r, lastNames)
+		city := pick(r, cities)
+		tags := []interface{}{}
+		for _, tag := range userTags {
+			if r.IntN(4) == 0 {
+				tags = append(tags, tag)
+			}
+		}
+		out[i] = map[string]interface{}{
+			"id":         i + 1,
+			"name":       first + " " + last,
+			"email":      fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1),
+			"age":        18 + r.IntN(60),
+			"active":     r.IntN(5) != 0,
+			"role":       pick(r, roles),
+			"created_at": epoch.Add(time.Duration(r.IntN(365*24)) * time.Hour).Format(time.RFC3339),
+			"address":    map[string]interface{}{"city": city[0], "country": city[1]},
+			"tags":       tags,
+		}
+	}
+	return out
+}
+
+func pods(r *rand.Rand, rows int) map[string]interface{} {
+	items := make([]interface{}, rows)
+	for i := range items {
+		app := pick(r, services)
+		tier := "backend"
+		if app == "api" {
+			tier = "frontend"
+		}
+		phase := pick(r, phases)
+		restarts := 0
+		if phase != "Pending" && r.IntN(4) == 0 {
+			restarts = 1 + r.IntN(12)
+		}
+		items[i] = map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":              fmt.Sprintf("%s-%s-%s", app, suffix(r, 10), suffix(r, 5)),
+				"namespace":         pick(r, namespaces),
+				"labels":            map[string]interface{}{"app": app, "tier": tier},
+				"creationTimestamp": epoch.Add(time.Duration(r.IntN(30*24*60)) * time.Minute).Format(time.RFC3339),
+			},
+			"spec": map[string]interface{}{
+				"nodeName": pick(r, hosts),
+				"containers": []interface{}{map[string]interface{}{
+					"name":  app,
+					"image": fmt.Sprintf("registry.example.com/%s:1.%d.%d", app, r.IntN(10), r.IntN(20)),
+					"ports": []interface{}{map[string]interface{}{"containerPort": 8080, "protocol": "TCP"}},
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": fmt.Sprintf("%dm", 100*(1+r.IntN(5))), "memory": fmt.Sprintf("%dMi", 128*(1+r.IntN(8)))},
+					},
+				}},
+			},
+			"status": map[string]interface{}{
+				"phase":        phase,
+				"podIP":        fmt.Sprintf("10.%d.%d.%d", r.IntN(4), r.IntN(256), 1+r.IntN(254)),
+				"restartCount": restarts,
+			},
+		}
+	}
+	return map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items}
+}
+
+func metrics(r *rand.Rand, rows int) []interface{} {
+	out := make([]interface{}, rows)
+	for i := range out {
+		p50 := 5 + r.Float64()*45
+		requests := 100 + r.IntN(4900)
+		out[i] = map[string]interface{}{
+			"timestamp":   epoch.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+			"host":        pick(r, hosts),
+			"service":     pick(r, services),
+			"cpu_percent": round(r.Float64() * 100),
+			"memory_mb":   256 + r.IntN(3840),
+			"requests":    requests,
+			"errors":      r.IntN(requests/50 + 1),
+			"latency_ms": map[string]interface{}{
+				"p50": round(p50),
+				"p95": round(p50 * (2 + r.Float64())),
+				"p99": round(p50 * (4 + 2*r.Float64())),
+			},
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package sampledata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateShapes(t *testing.T) {
+	for _, shape := range Shapes {
+		doc, err := Generate(shape, 25, 7)
+		require.NoError(t, err, shape)
+
+		again, err := Generate(shape, 25, 7)
+		require.NoError(t, err, shape)
+		assert.Equal(t, doc, again, "%s: the same seed generates the same document", shape)
+		other, err := Generate(shape, 25, 8)
+		require.NoError(t, err, shape)
+		assert.NotEqual(t, doc, other, "%s: another seed generates another document", shape)
+
+		records, ok := doc.([]interface{})
+		if shape == "k8s" {
+			list := doc.(map[string]interface{})
+			assert.Equal(t, "List", list["kind"])
+			records, ok = list["items"].([]interface{})
+		}
+		require.True(t, ok, shape)
+		assert.Len(t, records, 25, shape)
+	}
+}
+
+func TestGenerateRecords(t *testing.T) {
+	doc, err := Generate("users", 3, 1)
+	require.NoError(t, err)
+	user := doc.([]interface{})[2].(map[string]interface{})
+	assert.Equal(t, 3, user["id"])
+	assert.Regexp(t, `^[a-z]+\.[a-z]+3@example\.com$`, user["email"])
+	assert.Contains(t, user["address"], "city")
+
+	doc, err = Generate("k8s", 1, 1)
+	require.NoError(t, err)
+	pod := doc.(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Pod", pod["kind"])
+	meta := pod["metadata"].(map[string]interface{})
+	assert.Regexp(t, `^[a-z]+-[a-z0-9]{10}-[a-z0-9]{5}$`, meta["name"])
+
+	doc, err = Generate("metrics", 2, 1)
+	require.NoError(t, err)
+	points := doc.([]interface{})
+	assert.Equal(t, "2024-01-01T00:00:00Z", points[0].(map[string]interface{})["timestamp"])
+	assert.Equal(t, "2024-01-01T00:01:00Z", points[1].(map[string]interface{})["timestamp"])
+	latency := points[0].(map[string]interface{})["latency_ms"].(map[string]interface{})
+	assert.Less(t, latency["p50"], latency["p95"])
+	assert.Less(t, latency["p95"], latency["p99"])
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate("orders", 1, 1)
+	assert.EqualError(t, err, `unknown sample shape "orders" (expected users, k8s, metrics)`)
+	_, err = Generate("users", -1, 1)
+	assert.EqualError(t, err, "--rows must be non-negative, got -1")
+
+	doc, err := Generate("users", 0, 1)
+	require.NoError(t, err)
+	assert.Empty(t, doc)
+}
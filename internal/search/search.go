@@ -0,0 +1,181 @@
+// Package search finds the keys and values of a document that match a
+// query, for the TUI search (F3), --search, and core.Search.
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+)
+
+// Result is a key/value pair that matched.
+type Result struct {
+	Path  string // Path relative to the searched node, e.g. "items[0].name" or `["bad-key"]`
+	Key   string // Local key, e.g. "name", "[0]", or `["bad-key"]`
+	Value string // Stringified value for display
+	Node  any    // The value itself
+}
+
+// Matcher reports whether the text of a key or value matches a query.
+type Matcher func(text string) bool
+
+// Substring returns a Matcher of the texts containing query, ignoring case.
+func Substring(query string) Matcher {
+	q := strings.ToLower(query)
+	return func(text string) bool {
+		return strings.Contains(strings.ToLower(text), q)
+	}
+}
+
+// Walk collects the key/value pairs below node whose key or stringified
+// value matches, depth first with map keys in sorted order. Array elements
+// have no key, so only their values are matched. If limit > 0 it stops after
+// that many results and reports limited.
+func Walk(node any, match Matcher, limit int) (results []Result, limited bool) {
+	results = []Result{}
+	full := func() bool { return limit > 0 && len(results) >= limit }
+
+	var walk func(node any, currentPath string) bool
+	walk = func(node any, currentPath string) bool {
+		if full() {
+			return true
+		}
+		switch t := node.(type) {
+		case map[string]any:
+			for _, k := range sortedKeys(t) {
+				if full() {
+					return true
+				}
+				v := t[k]
+				childPath := ChildPath(currentPath, k)
+				valueStr := formatter.Stringify(v)
+				if match(k) || match(valueStr) {
+					results = append(results, Result{Path: childPath, Key: displayKey(k), Value: valueStr, Node: v})
+					if full() {
+						return true
+					}
+				}
+				if isContainer(v) && walk(v, childPath) {
+					return true
+				}
+			}
+		case []any:
+			for i, v := range t {
+				if full() {
+					return true
+				}
+				childPath := fmt.Sprintf("%s[%d]", currentPath, i)
+				valueStr := formatter.Stringify(v)
+				if match(valueStr) {
+					results = append(results, Result{Path: childPath, Key: fmt.Sprintf("[%d]", i), Value: valueStr, Node: v})
+					if full() {
+						return true
+					}
+				}
+				if isContainer(v) && walk(v, childPath) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	limited = walk(node, "")
+	return results, limited
+}
+
+// Descent collects every value stored under key at any depth below node,
+// the search-result form of the "..key" recursive descent path. Paths walk
+// maps in sorted key order.
+func Descent(node any, key string, limit int) (results []Result, limited bool) {
+	results = []Result{}
+	var walk func(node any, currentPath string) bool
+	walk = func(node any, currentPath string) bool {
+		switch t := node.(type) {
+		case map[string]any:
+			for _, k := range sortedKeys(t) {
+				childPath := ChildPath(currentPath, k)
+				if k == key {
+					if limit > 0 && len(results) >= limit {
+						return true
+					}
+					results = append(results, Result{Path: childPath, Key: displayKey(k), Value: formatter.Stringify(t[k]), Node: t[k]})
+				}
+				if walk(t[k], childPath) {
+					return true
+				}
+			}
+		case []any:
+			for i, v := range t {
+				if walk(v, fmt.Sprintf("%s[%d]", currentPath, i)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	limited = walk(node, "")
+	return results, limited
+}
+
+// ChildPath appends map key k to a search-relative path, quoting keys that
+// are not identifiers in brackets at any depth, e.g. `["bad-key"]`.
+func ChildPath(currentPath, k string) string {
+	switch {
+	case needsBracketNotation(k):
+		return currentPath + `["` + k + `"]`
+	case currentPath == "":
+		return k
+	default:
+		return currentPath + "." + k
+	}
+}
+
+// RootedPath returns a search-relative path as a _-rooted path, e.g.
+// "_.items[0].name" or `_["bad-key"]`.
+func RootedPath(path string) string {
+	switch {
+	case path == "":
+		return "_"
+	case strings.HasPrefix(path, "["):
+		return "_" + path
+	default:
+		return "_." + path
+	}
+}
+
+func displayKey(k string) string {
+	if needsBracketNotation(k) {
+		return `["` + k + `"]`
+	}
+	return k
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// needsBracketNotation reports whether a key has characters other than
+// letters, digits, and underscores, so paths must quote it in brackets.
+func needsBracketNotation(key string) bool {
+	for _, ch := range key {
+		if (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') && ch != '_' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkLimit(t *testing.T) {
+	node := map[string]any{"a": "x", "b": "x", "c": "x"}
+	results, limited := Walk(node, Substring("X"), 2)
+	require.True(t, limited)
+	require.Len(t, results, 2)
+	require.Equal(t, "a", results[0].Path)
+	require.Equal(t, "b", results[1].Path)
+
+	results, limited = Walk(node, Substring("x"), 0)
+	require.False(t, limited)
+	require.Len(t, results, 3)
+}
+
+func TestWalkArrayMatchesValuesOnly(t *testing.T) {
+	node := map[string]any{"list": []any{"zero", "one"}}
+	results, _ := Walk(node, Substring("0"), 0)
+	require.Empty(t, results)
+
+	results, _ = Walk(node, Substring("one"), 0)
+	require.Len(t, results, 2) // the list, whose JSON contains "one", and the element
+	require.Equal(t, "list[1]", results[1].Path)
+	require.Equal(t, "[1]", results[1].Key)
+}
+
+func TestDescent(t *testing.T) {
+	node := map[string]any{
+		"id":    1,
+		"items": []any{map[string]any{"id": 2}, map[string]any{"name": "n"}},
+	}
+	results, _ := Descent(node, "id", 0)
+	require.Len(t, results, 2)
+	require.Equal(t, "id", results[0].Path)
+	require.Equal(t, "items[0].id", results[1].Path)
+}
+
+func TestRootedPath(t *testing.T) {
+	require.Equal(t, "_", RootedPath(""))
+	require.Equal(t, "_.a.b", RootedPath("a.b"))
+	require.Equal(t, "_[0]", RootedPath("[0]"))
+	require.Equal(t, `_["bad-key"]`, RootedPath(ChildPath("", "bad-key")))
+}
@@ -0,0 +1,145 @@
+package sourcepos
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RecordYAML records the position and comments of every mapping key and
+// sequence item in n onto the corresponding container in v, the value n was
+// decoded into.
+func (ix *Index) RecordYAML(n *yaml.Node, v interface{}) {
+	if ix == nil || n == nil {
+		return
+	}
+	ix.recordYAML(n, v)
+}
+
+func (ix *Index) recordYAML(n *yaml.Node, v interface{}) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			ix.recordYAML(n.Content[0], v)
+		}
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			ix.recordYAML(n.Alias, v)
+		}
+	case yaml.MappingNode:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		positions := make(map[string]Position, len(m))
+		var comments map[string]string
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Tag == "!!merge" {
+				// Merged keys point at their definition under the anchor;
+				// keys written in this mapping override them below.
+				mergedPositions(n.Content[i+1], positions)
+			}
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Tag == "!!merge" {
+				continue
+			}
+			positions[key.Value] = Position{Line: key.Line, Column: key.Column}
+			comments = addComment(comments, key.Value, yamlComment(key, n.Content[i+1]))
+			ix.recordYAML(n.Content[i+1], m[key.Value])
+		}
+		ix.Record(m, positions, comments)
+	case yaml.SequenceNode:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		positions := make(map[string]Position, len(arr))
+		var comments map[string]string
+		for i, c := range n.Content {
+			if i >= len(arr) {
+				break
+			}
+			positions[indexKey(i)] = Position{Line: c.Line, Column: c.Column}
+			comments = addComment(comments, indexKey(i), commentText(c.HeadComment, c.LineComment))
+			ix.recordYAML(c, arr[i])
+		}
+		ix.Record(arr, positions, comments)
+	case yaml.ScalarNode:
+	}
+}
+
+// yamlComment returns the comments attached to a mapping entry: those above
+// the key and the one at the end of its line, which the parser puts on the
+// key or, for scalar values, on the value.
+func yamlComment(key, value *yaml.Node) string {
+	line := key.LineComment
+	if line == "" && (value.Kind == yaml.ScalarNode || value.Kind == yaml.AliasNode) {
+		line = value.LineComment
+	}
+	return commentText(key.HeadComment, line)
+}
+
+// mergedPositions adds the key positions of a merge ("<<") value, a mapping
+// or a sequence of mappings, without overriding keys already present.
+func mergedPositions(n *yaml.Node, positions map[string]Position) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			mergedPositions(n.Alias, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if _, exists := positions[key.Value]; !exists && key.Tag != "!!merge" {
+				positions[key.Value] = Position{Line: key.Line, Column: key.Column}
+			}
+		}
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			mergedPositions(c, positions)
+		}
+	default:
+	}
+}
+
+// Lines locates JSON tokens by byte offset, for decoders that read the text
+// token by token and record positions as they go.
+type Lines struct {
+	data       []byte
+	firstLine  int
+	lineStarts []int // offsets of the first byte of every line after the first
+}
+
+// NewLines indexes data, which starts on line firstLine of the parsed text
+// (1 for a whole document).
+func NewLines(data []byte, firstLine int) *Lines {
+	l := &Lines{data: data, firstLine: firstLine}
+	for i, b := range data {
+		if b == '\n' {
+			l.lineStarts = append(l.lineStarts, i+1)
+		}
+	}
+	return l
+}
+
+// Next returns the position of the token following byte offset off (a
+// json.Decoder's InputOffset), skipping whitespace and separators.
+func (l *Lines) Next(off int64) Position {
+	i := int(off)
+	for i < len(l.data) {
+		switch l.data[i] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			i++
+			continue
+		}
+		break
+	}
+	line := sort.SearchInts(l.lineStarts, i+1)
+	col := i + 1
+	if line > 0 {
+		col = i - l.lineStarts[line-1] + 1
+	}
+	return Position{Line: l.firstLine + line, Column: col}
+}
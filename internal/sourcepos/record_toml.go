@@ -0,0 +1,165 @@
+package sourcepos
+
+import (
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// RecordTOML records the position and comments of every key, table header
+// and array-of-tables entry in data onto the corresponding container in v,
+// the value data was decoded into. Values inside inline tables and arrays
+// are not recorded.
+func (ix *Index) RecordTOML(data []byte, v interface{}) {
+	root, ok := v.(map[string]interface{})
+	if !ok || ix == nil {
+		return
+	}
+	r := &tomlRecorder{entries: make(map[uintptr]*tomlEntry), arrayIndex: make(map[uintptr]int)}
+	p := unstable.Parser{KeepComments: true}
+	p.Reset(data)
+
+	current := root
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Comment:
+			r.addHeadComment(p.Shape(expr.Raw).Start.Line, string(expr.Data))
+			continue
+		case unstable.Table, unstable.ArrayTable:
+			current = r.header(&p, root, expr)
+		case unstable.KeyValue:
+			r.keyValue(&p, current, expr)
+		default:
+		}
+		r.head = nil
+	}
+	if p.Error() != nil {
+		return
+	}
+	for _, e := range r.entries {
+		ix.Record(e.container, e.positions, e.comments)
+	}
+}
+
+type tomlEntry struct {
+	container interface{}
+	positions map[string]Position
+	comments  map[string]string
+}
+
+type tomlRecorder struct {
+	entries map[uintptr]*tomlEntry
+	// arrayIndex counts the [[header]] entries seen per array.
+	arrayIndex map[uintptr]int
+	// head holds the run of comment lines directly above the next expression.
+	head     []string
+	headLine int
+}
+
+func (r *tomlRecorder) addHeadComment(line int, text string) {
+	if len(r.head) > 0 && line != r.headLine+1 {
+		r.head = nil
+	}
+	r.head = append(r.head, text)
+	r.headLine = line
+}
+
+// comment returns the head comments directly above line plus the comment
+// trailing expr on its own line.
+func (r *tomlRecorder) comment(p *unstable.Parser, expr *unstable.Node, line int) string {
+	var blocks []string
+	if len(r.head) > 0 && r.headLine == line-1 {
+		blocks = append(blocks, r.head...)
+	}
+	if next := expr.Next(); next != nil && next.Kind == unstable.Comment {
+		blocks = append(blocks, string(next.Data))
+	}
+	return commentText(blocks...)
+}
+
+func (r *tomlRecorder) add(container interface{}, key string, pos Position, comment string) {
+	id, _, ok := identity(container)
+	if !ok {
+		return
+	}
+	e := r.entries[id]
+	if e == nil {
+		e = &tomlEntry{container: container, positions: make(map[string]Position)}
+		r.entries[id] = e
+	}
+	if _, seen := e.positions[key]; !seen {
+		e.positions[key] = pos
+	}
+	e.comments = addComment(e.comments, key, comment)
+}
+
+// header handles a [table] or [[array]] header and returns the table that
+// the following key/value lines belong to.
+func (r *tomlRecorder) header(p *unstable.Parser, root map[string]interface{}, expr *unstable.Node) map[string]interface{} {
+	keys := expr.Key()
+	parent := root
+	for keys.Next() {
+		k := keys.Node()
+		name := string(k.Data)
+		start := p.Shape(k.Raw).Start
+		pos := Position{Line: start.Line, Column: start.Column}
+		child := parent[name]
+		if keys.IsLast() {
+			comment := r.comment(p, expr, start.Line)
+			if arr, ok := child.([]interface{}); ok && expr.Kind == unstable.ArrayTable {
+				r.add(parent, name, pos, "")
+				id, _, _ := identity(arr)
+				i := r.arrayIndex[id]
+				r.arrayIndex[id] = i + 1
+				if i < len(arr) {
+					r.add(arr, indexKey(i), pos, comment)
+					child = arr[i]
+				}
+			} else {
+				r.add(parent, name, pos, comment)
+			}
+		}
+		next, ok := lastTable(child)
+		if !ok {
+			return map[string]interface{}{}
+		}
+		parent = next
+	}
+	return parent
+}
+
+// keyValue records a key = value line, following dotted keys into their
+// implicit tables.
+func (r *tomlRecorder) keyValue(p *unstable.Parser, table map[string]interface{}, expr *unstable.Node) {
+	keys := expr.Key()
+	for keys.Next() {
+		k := keys.Node()
+		name := string(k.Data)
+		start := p.Shape(k.Raw).Start
+		pos := Position{Line: start.Line, Column: start.Column}
+		if keys.IsLast() {
+			r.add(table, name, pos, r.comment(p, expr, start.Line))
+			return
+		}
+		r.add(table, name, pos, "")
+		next, ok := table[name].(map[string]interface{})
+		if !ok {
+			return
+		}
+		table = next
+	}
+}
+
+// lastTable returns v as a table, or the last table of an array of tables,
+// which is the one a header such as [a.b] continues.
+func lastTable(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case []interface{}:
+		if len(t) > 0 {
+			m, ok := t[len(t)-1].(map[string]interface{})
+			return m, ok
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+// Package sourcepos remembers where the values of a loaded document were
+// written in the source text, and the comments written alongside them, so the
+// UI can show "data.yaml:142 # why this is set" for the selected node and open
+// the file at that line. The loader records them in an Index that belongs to
+// the loaded document; like keyorder, positions are keyed by the identity of
+// the containing map or slice, plus the key or index of the value within it.
+package sourcepos
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Position is a location in the source text. Line and Column are 1-based.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String renders the position as "file:line", or "line N" when the input
+// did not come from a file.
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("line %d", p.Line)
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// Index holds the positions and comments recorded for one loaded document.
+// It keeps a reference to every container it describes, so an entry cannot
+// be mistaken for another container that reuses the address, and it is
+// freed with the document. A nil *Index records and finds nothing.
+type Index struct {
+	mu         sync.RWMutex
+	file       string
+	lineOffset int
+	entries    map[uintptr]entry
+}
+
+// entry holds the positions and comments of the children of one map or
+// slice. size is the container length at recording time, used to detect
+// stale entries.
+type entry struct {
+	container interface{}
+	size      int
+	positions map[string]Position
+	comments  map[string]string
+}
+
+// NewIndex returns an empty Index for text read from file ("" when the input
+// did not come from a file). lineOffset is the number of lines that precede
+// the text handed to the parsers (e.g. leading blank lines trimmed by the
+// loader) and is added to every recorded line.
+func NewIndex(file string, lineOffset int) *Index {
+	return &Index{file: file, lineOffset: lineOffset, entries: make(map[uintptr]entry)}
+}
+
+// Record stores the positions and comments of the children of container,
+// keyed by map key or decimal slice index. Line numbers are relative to the
+// parsed text.
+func (ix *Index) Record(container interface{}, positions map[string]Position, comments map[string]string) {
+	if ix == nil || len(positions) == 0 {
+		return
+	}
+	id, size, ok := identity(container)
+	if !ok {
+		return
+	}
+	for k, p := range positions {
+		p.File = ix.file
+		p.Line += ix.lineOffset
+		positions[k] = p
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.entries[id] = entry{container: container, size: size, positions: positions, comments: comments}
+}
+
+// Lookup returns the position of the value stored under key in container, a
+// map key or a decimal slice index. Containers that changed size since they
+// were recorded report no positions.
+func (ix *Index) Lookup(container interface{}, key string) (Position, bool) {
+	e, ok := ix.lookupEntry(container)
+	if !ok {
+		return Position{}, false
+	}
+	p, found := e.positions[key]
+	return p, found
+}
+
+// Comment returns the comment written above or beside the value stored under
+// key in container, with the comment markers removed. Multi-line comments
+// keep their line breaks.
+func (ix *Index) Comment(container interface{}, key string) (string, bool) {
+	e, ok := ix.lookupEntry(container)
+	if !ok {
+		return "", false
+	}
+	c, found := e.comments[key]
+	return c, found
+}
+
+func (ix *Index) lookupEntry(container interface{}) (entry, bool) {
+	if ix == nil {
+		return entry{}, false
+	}
+	id, size, ok := identity(container)
+	if !ok {
+		return entry{}, false
+	}
+	ix.mu.RLock()
+	e, found := ix.entries[id]
+	ix.mu.RUnlock()
+	if !found || e.size != size {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// identity returns the address and length of a non-empty map or slice.
+func identity(v interface{}) (uintptr, int, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return 0, 0, false
+		}
+		return reflect.ValueOf(t).Pointer(), len(t), true
+	case []interface{}:
+		if len(t) == 0 {
+			return 0, 0, false
+		}
+		return reflect.ValueOf(t).Pointer(), len(t), true
+	}
+	return 0, 0, false
+}
+
+// commentText joins comment blocks into plain text: "#" markers and the space
+// after them are removed and blank lines dropped.
+func commentText(blocks ...string) string {
+	var lines []string
+	for _, b := range blocks {
+		for _, l := range strings.Split(b, "\n") {
+			l = strings.TrimSpace(l)
+			l = strings.TrimSpace(strings.TrimLeft(l, "#"))
+			if l != "" {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addComment stores text under key, creating the map on first use.
+func addComment(comments map[string]string, key, text string) map[string]string {
+	if text == "" {
+		return comments
+	}
+	if comments == nil {
+		comments = make(map[string]string)
+	}
+	comments[key] = text
+	return comments
+}
+
+// indexKey is the key under which slice element i is recorded.
+func indexKey(i int) string {
+	return strconv.Itoa(i)
+}
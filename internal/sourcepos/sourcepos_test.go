@@ -0,0 +1,131 @@
+package sourcepos
+
+import (
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func lookup(t *testing.T, ix *Index, container interface{}, key string) Position {
+	t.Helper()
+	p, ok := ix.Lookup(container, key)
+	require.True(t, ok, "no position for %q", key)
+	return p
+}
+
+func TestRecordYAML(t *testing.T) {
+	ix := NewIndex("data.yaml", 0)
+
+	src := "# header\nname: demo\nbase: &base\n  owner: me\nitems:\n  - id: 1\n  - id: 2\nmerged:\n  <<: *base\n  extra: true\n"
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	var v map[string]interface{}
+	require.NoError(t, node.Decode(&v))
+	ix.RecordYAML(&node, v)
+
+	assert.Equal(t, Position{File: "data.yaml", Line: 2, Column: 1}, lookup(t, ix, v, "name"))
+	assert.Equal(t, "data.yaml:5", lookup(t, ix, v, "items").String())
+
+	items := v["items"].([]interface{})
+	assert.Equal(t, Position{File: "data.yaml", Line: 7, Column: 5}, lookup(t, ix, items, "1"))
+	assert.Equal(t, 6, lookup(t, ix, items[0], "id").Line)
+
+	merged := v["merged"].(map[string]interface{})
+	assert.Equal(t, 4, lookup(t, ix, merged, "owner").Line, "merged keys point at the anchor")
+	assert.Equal(t, 10, lookup(t, ix, merged, "extra").Line)
+}
+
+func TestLines_Next(t *testing.T) {
+	src := []byte("{\n  \"a\": {\"b\": [1,\n    {\"c\": 2}]}\n}")
+	lines := NewLines(src, 1)
+	assert.Equal(t, Position{Line: 2, Column: 3}, lines.Next(1))
+	assert.Equal(t, Position{Line: 2, Column: 14}, lines.Next(14))
+	assert.Equal(t, Position{Line: 3, Column: 5}, lines.Next(18))
+	assert.Equal(t, Position{Line: 4, Column: 1}, NewLines([]byte("x"), 4).Next(0))
+}
+
+func TestIndex_FileAndLineOffset(t *testing.T) {
+	ix := NewIndex("data.json", 2)
+	v := map[string]interface{}{"k": 1.0}
+	ix.Record(v, map[string]Position{"k": {Line: 4, Column: 2}}, nil)
+	assert.Equal(t, Position{File: "data.json", Line: 6, Column: 2}, lookup(t, ix, v, "k"))
+	assert.Equal(t, "data.json:6", lookup(t, ix, v, "k").String())
+}
+
+func TestIndex_Stale(t *testing.T) {
+	ix := NewIndex("", 0)
+	v := map[string]interface{}{"a": 1.0}
+	ix.Record(v, map[string]Position{"a": {Line: 1, Column: 2}}, nil)
+	v["b"] = 2
+	_, ok := ix.Lookup(v, "a")
+	assert.False(t, ok, "a container that changed size has no positions")
+}
+
+func TestIndex_PerDocument(t *testing.T) {
+	v := map[string]interface{}{"a": 1.0}
+	NewIndex("", 0).Record(v, map[string]Position{"a": {Line: 1, Column: 2}}, nil)
+	_, ok := NewIndex("", 0).Lookup(v, "a")
+	assert.False(t, ok, "another document's index knows nothing about v")
+
+	var none *Index
+	none.Record(v, map[string]Position{"a": {Line: 1}}, nil)
+	_, ok = none.Lookup(v, "a")
+	assert.False(t, ok)
+}
+
+func TestRecordYAML_Comments(t *testing.T) {
+	ix := NewIndex("", 0)
+
+	src := "name: demo # display name\n# How many times to retry\n# before giving up.\nretries: 3\nnested: # section\n  a: 1\nlist:\n  # first\n  - x\n  - y\n"
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	var v map[string]interface{}
+	require.NoError(t, node.Decode(&v))
+	ix.RecordYAML(&node, v)
+
+	c, _ := ix.Comment(v, "name")
+	assert.Equal(t, "display name", c)
+	c, _ = ix.Comment(v, "retries")
+	assert.Equal(t, "How many times to retry\nbefore giving up.", c)
+	c, _ = ix.Comment(v, "nested")
+	assert.Equal(t, "section", c)
+	c, _ = ix.Comment(v["list"], "0")
+	assert.Equal(t, "first", c)
+	_, ok := ix.Comment(v["list"], "1")
+	assert.False(t, ok)
+}
+
+func TestRecordTOML(t *testing.T) {
+	ix := NewIndex("", 0)
+
+	src := "title = \"x\" # the title\n\n# Owner block\n[owner]\n# who\nname = \"Tom\"\nsite.url = \"u\"\n\n[[fruits]]\nname = \"apple\" # red\n[[fruits]]\n# second\nname = \"banana\"\n"
+	v := map[string]interface{}{}
+	require.NoError(t, toml.Unmarshal([]byte(src), &v))
+	ix.RecordTOML([]byte(src), v)
+
+	assert.Equal(t, Position{Line: 1, Column: 1}, lookup(t, ix, v, "title"))
+	c, _ := ix.Comment(v, "title")
+	assert.Equal(t, "the title", c)
+	assert.Equal(t, 4, lookup(t, ix, v, "owner").Line)
+	c, _ = ix.Comment(v, "owner")
+	assert.Equal(t, "Owner block", c)
+
+	owner := v["owner"].(map[string]interface{})
+	assert.Equal(t, Position{Line: 6, Column: 1}, lookup(t, ix, owner, "name"))
+	c, _ = ix.Comment(owner, "name")
+	assert.Equal(t, "who", c)
+	site := owner["site"].(map[string]interface{})
+	assert.Equal(t, Position{Line: 7, Column: 6}, lookup(t, ix, site, "url"))
+
+	fruits := v["fruits"].([]interface{})
+	assert.Equal(t, 9, lookup(t, ix, fruits, "0").Line)
+	assert.Equal(t, 11, lookup(t, ix, fruits, "1").Line)
+	c, _ = ix.Comment(fruits[0], "name")
+	assert.Equal(t, "red", c)
+	assert.Equal(t, 13, lookup(t, ix, fruits[1], "name").Line)
+	c, _ = ix.Comment(fruits[1], "name")
+	assert.Equal(t, "second", c)
+}
@@ -0,0 +1,90 @@
+// Package suggest ranks "did you mean" candidates for a mistyped name.
+package suggest
+
+import (
+	"sort"
+	"strings"
+)
+
+// EditDistance returns the Levenshtein distance between a and b, counting a
+// swap of two adjacent characters as a single edit so that common typos such
+// as "nmae" for "name" stay close. Comparison is case-insensitive.
+func EditDistance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// Three rolling rows: two back (for transpositions), previous, current.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// Closest returns up to limit candidates that are plausible typo
+// corrections of name, closest first. Candidates further away than a
+// length-dependent threshold are dropped, so short names only match near
+// misses. Ties keep the candidates' original order.
+func Closest(name string, candidates []string, limit int) []string {
+	if name == "" || limit <= 0 {
+		return nil
+	}
+	threshold := suggestThreshold(len([]rune(name)))
+
+	type scored struct {
+		name string
+		dist int
+	}
+	var hits []scored
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		if d := EditDistance(name, c); d <= threshold {
+			hits = append(hits, scored{name: c, dist: d})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].dist < hits[j].dist })
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	out := make([]string, len(hits))
+	for i, h := range hits {
+		out[i] = h.name
+	}
+	return out
+}
+
+func suggestThreshold(n int) int {
+	switch {
+	case n <= 3:
+		return 1
+	case n <= 6:
+		return 2
+	default:
+		return 3
+	}
+}
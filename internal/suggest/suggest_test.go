@@ -0,0 +1,29 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, EditDistance("name", "name"))
+	assert.Equal(t, 0, EditDistance("Name", "name"))
+	assert.Equal(t, 1, EditDistance("nmae", "name"))
+	assert.Equal(t, 1, EditDistance("nam", "name"))
+	assert.Equal(t, 1, EditDistance("names", "name"))
+	assert.Equal(t, 3, EditDistance("kitten", "sitting"))
+	assert.Equal(t, 4, EditDistance("", "name"))
+	assert.Equal(t, 4, EditDistance("name", ""))
+}
+
+func TestClosest(t *testing.T) {
+	keys := []string{"name", "namespace", "labels", "status", "names"}
+
+	assert.Equal(t, []string{"name", "names"}, Closest("nmae", keys, 3))
+	assert.Equal(t, []string{"status"}, Closest("stauts", keys, 3))
+	assert.Equal(t, []string{"name"}, Closest("nmae", keys, 1))
+	assert.Empty(t, Closest("zzz", keys, 3))
+	assert.Empty(t, Closest("", keys, 3))
+	assert.Empty(t, Closest("name", []string{"name"}, 3), "exact match is not a suggestion")
+}
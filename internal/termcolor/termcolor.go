@@ -0,0 +1,150 @@
+// Package termcolor decides how kvx colors its output. The --color flag, the
+// NO_COLOR, FORCE_COLOR, CLICOLOR and CLICOLOR_FORCE conventions and terminal
+// detection are resolved once into a single color profile that every writer
+// of styled output uses.
+//
+// The profile carries two decisions:
+//   - [colorprofile.ASCII] means the user turned color off. kvx then renders
+//     without styles, as with --no-color.
+//   - [colorprofile.NoTTY] means the output is not a terminal. The layout is
+//     unchanged but escape sequences are stripped on the way out.
+//
+// Richer profiles downsample true colors to what the terminal supports.
+package termcolor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+// Mode is the value of the --color flag.
+type Mode string
+
+const (
+	// ModeAuto colors output written to a terminal and honors the environment.
+	ModeAuto Mode = "auto"
+	// ModeAlways colors output even when it is piped or redirected.
+	ModeAlways Mode = "always"
+	// ModeNever renders output without colors or other styles.
+	ModeNever Mode = "never"
+)
+
+// ParseMode parses a --color flag value. The empty string means auto.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ModeAuto:
+		return ModeAuto, nil
+	case ModeAlways:
+		return ModeAlways, nil
+	case ModeNever:
+		return ModeNever, nil
+	default:
+		return "", fmt.Errorf("invalid --color value %q (expected auto, always, or never)", s)
+	}
+}
+
+// Detect resolves the color profile for one output stream. tty says whether
+// the stream is a terminal able to display escape sequences. environ is a
+// list of KEY=value pairs such as os.Environ().
+//
+// The first rule that applies wins:
+//  1. --color=always or --color=never.
+//  2. FORCE_COLOR or CLICOLOR_FORCE set to anything but "", 0 or false forces
+//     color, even into pipes. FORCE_COLOR=2 and 3 ask for 256 and true colors.
+//     FORCE_COLOR=0 turns color off.
+//  3. NO_COLOR set to any non-empty value turns color off.
+//  4. CLICOLOR=0 turns color off.
+//  5. Output that is not a terminal, or TERM=dumb, gets no escape sequences.
+//  6. Otherwise the terminal's own capabilities decide.
+func Detect(mode Mode, tty bool, environ []string) colorprofile.Profile {
+	env := envMap(environ)
+	switch mode {
+	case ModeNever:
+		return colorprofile.ASCII
+	case ModeAlways:
+		return forced(env, colorprofile.ANSI)
+	case ModeAuto:
+	}
+
+	if v := env["FORCE_COLOR"]; v != "" {
+		switch strings.ToLower(v) {
+		case "0", "false":
+			return colorprofile.ASCII
+		case "2":
+			return forced(env, colorprofile.ANSI256)
+		case "3":
+			return forced(env, colorprofile.TrueColor)
+		default:
+			return forced(env, colorprofile.ANSI)
+		}
+	}
+	if v := env["CLICOLOR_FORCE"]; v != "" && v != "0" && !strings.EqualFold(v, "false") {
+		return forced(env, colorprofile.ANSI)
+	}
+	if env["NO_COLOR"] != "" {
+		return colorprofile.ASCII
+	}
+	if env["CLICOLOR"] == "0" {
+		return colorprofile.ASCII
+	}
+	if !tty || env["TERM"] == "dumb" {
+		return colorprofile.NoTTY
+	}
+	if p := colorprofile.Env(environ); p > colorprofile.ASCII {
+		return p
+	}
+	// Terminals that do not describe themselves (no TERM, as on Windows
+	// consoles) still understand the basic colors.
+	return colorprofile.ANSI
+}
+
+// forced returns the terminal's own profile, raised to at least floor.
+func forced(env map[string]string, floor colorprofile.Profile) colorprofile.Profile {
+	environ := make([]string, 0, len(env))
+	for k, v := range env {
+		if k == "NO_COLOR" {
+			continue
+		}
+		environ = append(environ, k+"="+v)
+	}
+	return max(colorprofile.Env(environ), floor)
+}
+
+// Plain reports whether p asks for unstyled output, the equivalent of
+// --no-color.
+func Plain(p colorprofile.Profile) bool {
+	return p == colorprofile.ASCII
+}
+
+// Writer returns a writer that converts the styled text written to it to p
+// before passing it on to w: colors are downsampled, and for NoTTY all escape
+// sequences are removed. TrueColor and Unknown leave the text unchanged.
+func Writer(w io.Writer, p colorprofile.Profile) io.Writer {
+	if p == colorprofile.TrueColor || p == colorprofile.Unknown {
+		return w
+	}
+	return &colorprofile.Writer{Forward: w, Profile: p}
+}
+
+// Convert returns s converted to p, as if written through [Writer].
+func Convert(s string, p colorprofile.Profile) string {
+	if p == colorprofile.TrueColor || p == colorprofile.Unknown {
+		return s
+	}
+	var b strings.Builder
+	_, _ = io.WriteString(Writer(&b, p), s)
+	return b.String()
+}
+
+func envMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
@@ -0,0 +1,79 @@
+package termcolor
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]Mode{"": ModeAuto, "auto": ModeAuto, "Always": ModeAlways, " never ": ModeNever} {
+		got, err := ParseMode(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseMode("sometimes")
+	assert.Error(t, err)
+}
+
+func TestDetect(t *testing.T) {
+	term := "TERM=xterm-256color"
+	tests := []struct {
+		name string
+		mode Mode
+		tty  bool
+		env  []string
+		want colorprofile.Profile
+	}{
+		{"terminal", ModeAuto, true, []string{term}, colorprofile.ANSI256},
+		{"true color terminal", ModeAuto, true, []string{term, "COLORTERM=truecolor"}, colorprofile.TrueColor},
+		{"piped", ModeAuto, false, []string{term}, colorprofile.NoTTY},
+		{"dumb terminal", ModeAuto, true, []string{"TERM=dumb"}, colorprofile.NoTTY},
+		{"NO_COLOR", ModeAuto, true, []string{term, "NO_COLOR=yes"}, colorprofile.ASCII},
+		{"empty NO_COLOR", ModeAuto, true, []string{term, "NO_COLOR="}, colorprofile.ANSI256},
+		{"CLICOLOR=0", ModeAuto, true, []string{term, "CLICOLOR=0"}, colorprofile.ASCII},
+		{"CLICOLOR_FORCE into pipe", ModeAuto, false, []string{term, "CLICOLOR_FORCE=1"}, colorprofile.ANSI256},
+		{"CLICOLOR_FORCE=0", ModeAuto, false, []string{term, "CLICOLOR_FORCE=0"}, colorprofile.NoTTY},
+		{"FORCE_COLOR into pipe", ModeAuto, false, []string{"TERM=xterm", "FORCE_COLOR=1"}, colorprofile.ANSI},
+		{"FORCE_COLOR=3", ModeAuto, false, []string{"TERM=xterm", "FORCE_COLOR=3"}, colorprofile.TrueColor},
+		{"FORCE_COLOR beats NO_COLOR", ModeAuto, true, []string{term, "NO_COLOR=1", "FORCE_COLOR=2"}, colorprofile.ANSI256},
+		{"FORCE_COLOR=0", ModeAuto, true, []string{term, "FORCE_COLOR=0"}, colorprofile.ASCII},
+		{"always", ModeAlways, false, []string{"TERM=xterm", "NO_COLOR=1"}, colorprofile.ANSI},
+		{"never", ModeNever, true, []string{term, "FORCE_COLOR=3"}, colorprofile.ASCII},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.mode, tt.tty, tt.env))
+		})
+	}
+}
+
+func TestDetect_NoTERM(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows consoles report their own capabilities without TERM")
+	}
+	assert.Equal(t, colorprofile.ANSI, Detect(ModeAuto, true, nil), "terminals without TERM get basic colors")
+}
+
+func TestPlain(t *testing.T) {
+	assert.True(t, Plain(colorprofile.ASCII))
+	assert.False(t, Plain(colorprofile.NoTTY), "piped output keeps its layout")
+	assert.False(t, Plain(colorprofile.ANSI))
+}
+
+func TestConvert(t *testing.T) {
+	styled := "\x1b[38;2;255;0;0mred\x1b[m │"
+	assert.Equal(t, styled, Convert(styled, colorprofile.TrueColor))
+	assert.Equal(t, styled, Convert(styled, colorprofile.Unknown))
+	assert.Equal(t, "red │", Convert(styled, colorprofile.NoTTY))
+	assert.True(t, strings.HasPrefix(Convert(styled, colorprofile.ANSI), "\x1b[91m"), Convert(styled, colorprofile.ANSI))
+
+	var b strings.Builder
+	_, err := Writer(&b, colorprofile.NoTTY).Write([]byte(styled))
+	require.NoError(t, err)
+	assert.Equal(t, "red │", b.String())
+}
@@ -0,0 +1,81 @@
+// Package textwidth measures and cuts text by terminal display width. Text is
+// handled one grapheme cluster at a time with the same width rules lipgloss
+// uses for layout, so CJK characters, emoji ZWJ sequences and combining
+// accents are never split or mis-measured and padded columns line up with
+// lipgloss-rendered borders. ANSI escape sequences take no width and are
+// kept intact.
+package textwidth
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Width returns the display width of s in terminal cells.
+func Width(s string) int {
+	if IsPrintableASCII(s) {
+		return len(s)
+	}
+	return ansi.StringWidth(s)
+}
+
+// IsPrintableASCII reports whether s holds only printable ASCII characters,
+// each one cell wide, so its width is its length. Most table cells are, and
+// checking is much cheaper than measuring grapheme clusters.
+func IsPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Truncate cuts s to at most w cells. When s is cut, tail is appended and
+// counted within w. s must be a single line.
+func Truncate(s string, w int, tail string) string {
+	if w <= 0 {
+		return ""
+	}
+	if IsPrintableASCII(s) {
+		if len(s) <= w {
+			return s
+		}
+		if tw := Width(tail); tw <= w {
+			return s[:w-tw] + tail
+		}
+	}
+	return ansi.Truncate(s, w, tail)
+}
+
+// TruncateLeft keeps the rightmost w cells of s. s must be a single line.
+func TruncateLeft(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	over := Width(s) - w
+	if over <= 0 {
+		return s
+	}
+	return ansi.TruncateLeft(s, over, "")
+}
+
+// PadRight cuts s to w cells and pads it with spaces to exactly w cells.
+// A wide character that does not fit is replaced by padding.
+func PadRight(s string, w int) string {
+	s = Truncate(s, w, "")
+	if pad := w - Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// Wrap breaks s into lines of at most w cells, breaking at spaces where
+// possible and inside words that are wider than w. s must be a single line.
+func Wrap(s string, w int) []string {
+	if w <= 0 || Width(s) <= w {
+		return []string{s}
+	}
+	return strings.Split(ansi.Wrap(s, w, ""), "\n")
+}
@@ -0,0 +1,62 @@
+package textwidth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	family   = "\U0001F468\u200d\U0001F469\u200d\U0001F467" // ZWJ sequence, one cluster
+	accented = "e\u0301tude"                                // "é" as e + combining acute
+	cjk      = "東京タワー"
+)
+
+func TestWidth(t *testing.T) {
+	assert.Equal(t, 2, Width(family))
+	assert.Equal(t, 5, Width(accented))
+	assert.Equal(t, 10, Width(cjk))
+	assert.Equal(t, 3, Width("\x1b[31mred\x1b[0m"))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, family, Truncate(family+"abc", 2, ""))
+	assert.Equal(t, "", Truncate(family+"abc", 1, ""), "a cluster is never split")
+	assert.Equal(t, "e\u0301t…", Truncate(accented, 3, "…"))
+	assert.Equal(t, "東京…", Truncate(cjk, 6, "…"))
+	assert.Equal(t, "東…", Truncate(cjk, 4, "…"), "a wide char that would overflow is dropped")
+	assert.Equal(t, cjk, Truncate(cjk, 10, "…"))
+	assert.Equal(t, "\x1b[31m東\x1b[0m", Truncate("\x1b[31m東京\x1b[0m", 3, ""))
+	assert.Equal(t, "", Truncate(cjk, 0, ""))
+}
+
+func TestTruncateASCII(t *testing.T) {
+	assert.Equal(t, "abc", Truncate("abc", 3, "…"))
+	assert.Equal(t, "ab…", Truncate("abcd", 3, "…"))
+	assert.Equal(t, "abc", Truncate("abcd", 3, ""))
+	assert.Equal(t, "", Truncate("abcd", 1, "..."), "a tail wider than the width leaves nothing")
+	assert.True(t, IsPrintableASCII("a b~"))
+	assert.False(t, IsPrintableASCII("a\tb"))
+	assert.False(t, IsPrintableASCII(cjk))
+}
+
+func TestTruncateLeft(t *testing.T) {
+	assert.Equal(t, "ワー", TruncateLeft(cjk, 4))
+	assert.Equal(t, "abc"+family, TruncateLeft("xyzabc"+family, 5))
+	assert.Equal(t, "tude", TruncateLeft(accented, 4))
+	assert.Equal(t, cjk, TruncateLeft(cjk, 20))
+}
+
+func TestPadRight(t *testing.T) {
+	assert.Equal(t, "東京  ", PadRight("東京", 6))
+	assert.Equal(t, family+" ", PadRight(family, 3))
+	assert.Equal(t, "東京 ", PadRight(cjk, 5))
+	assert.Equal(t, "e\u0301t", PadRight(accented, 2))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"short"}, Wrap("short", 10))
+	assert.Equal(t, []string{"the quick", "brown fox"}, Wrap("the quick brown fox", 9))
+	assert.Equal(t, []string{"東京", "ワー"}, Wrap("東京ワー", 4))
+	assert.Equal(t, []string{"abcdef"}, Wrap("abcdef", 0))
+}
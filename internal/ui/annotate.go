@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// Annotation is a note or flag put on a row with the annotate key (a),
+// identified by the expression that reads the row from the root.
+type Annotation struct {
+	Path string `json:"path"`
+	Note string `json:"note,omitempty"`
+}
+
+// annotationMarker marks annotated rows in the marker column.
+const annotationMarker = "✎"
+
+// markerColumnWidth is the width of the marker column: the marker and a space.
+const markerColumnWidth = 2
+
+// vimAnnotate opens the note prompt of the selected row (a).
+func (m *Model) vimAnnotate() (tea.Model, tea.Cmd) {
+	return m, menuActionAnnotate(m)
+}
+
+// menuActionAnnotate opens the note prompt of the selected row, holding
+// its note when the row is already annotated.
+func menuActionAnnotate(m *Model) tea.Cmd {
+	if m.kioskRefuses("Annotating") {
+		return nil
+	}
+	path := formatPathForDisplay(m.selectedPickPath())
+	m.AnnotationPath = path
+	m.AnnotationText = ""
+	if i := m.annotationIndex(path); i >= 0 {
+		m.AnnotationText = m.Annotations[i].Note
+	}
+	m.AnnotationInput = true
+	m.clearErrorUnlessSticky()
+	return nil
+}
+
+// handleAnnotationKey edits the note prompt while it is open: typing goes
+// to the note, enter saves it (an empty note flags the row), ctrl+x
+// removes the row's annotation, and esc closes the prompt unchanged.
+func (m *Model) handleAnnotationKey(keyStr string) bool {
+	if !m.AnnotationInput {
+		return false
+	}
+	switch keyStr {
+	case "ctrl+c":
+		return false
+	case "enter":
+		m.AnnotationInput = false
+		m.setAnnotation(Annotation{Path: m.AnnotationPath, Note: strings.TrimSpace(m.AnnotationText)})
+		m.ErrMsg = fmt.Sprintf("Annotated %s (%d annotated)", m.AnnotationPath, len(m.Annotations))
+		m.StatusType = "success"
+	case "ctrl+x":
+		m.AnnotationInput = false
+		if i := m.annotationIndex(m.AnnotationPath); i >= 0 {
+			m.Annotations = append(m.Annotations[:i:i], m.Annotations[i+1:]...)
+			m.ErrMsg = fmt.Sprintf("Removed the annotation of %s", m.AnnotationPath)
+			m.StatusType = "success"
+		}
+	case "esc":
+		m.AnnotationInput = false
+	case "backspace", "ctrl+h":
+		_, size := utf8.DecodeLastRuneInString(m.AnnotationText)
+		m.AnnotationText = m.AnnotationText[:len(m.AnnotationText)-size]
+	case "ctrl+u":
+		m.AnnotationText = ""
+	case "space":
+		m.AnnotationText += " "
+	default:
+		if r, size := utf8.DecodeRuneInString(keyStr); size == len(keyStr) && unicode.IsPrint(r) {
+			m.AnnotationText += keyStr
+		}
+	}
+	return true
+}
+
+// setAnnotation adds a, or replaces the note of the row it annotates.
+func (m *Model) setAnnotation(a Annotation) {
+	if i := m.annotationIndex(a.Path); i >= 0 {
+		m.Annotations[i] = a
+		return
+	}
+	m.Annotations = append(m.Annotations, a)
+}
+
+// annotationIndex returns the index of the annotation of path, or -1.
+func (m *Model) annotationIndex(path string) int {
+	for i, a := range m.Annotations {
+		if a.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// annotationPrompt is the status bar line of the open note prompt.
+func (m *Model) annotationPrompt() string {
+	return fmt.Sprintf("Note for %s: %s█  (enter saves, empty flags the row, ctrl+x removes, esc cancels)", m.AnnotationPath, m.AnnotationText)
+}
+
+// annotationChip is the status bar note of the selected row when it is
+// annotated.
+func (m *Model) annotationChip() string {
+	if len(m.Annotations) == 0 {
+		return ""
+	}
+	i := m.annotationIndex(formatPathForDisplay(m.selectedPickPath()))
+	if i < 0 {
+		return ""
+	}
+	if note := m.Annotations[i].Note; note != "" {
+		return annotationMarker + " " + note
+	}
+	return annotationMarker + " flagged"
+}
+
+// annotationRowMarks reports which rows of the KEY/VALUE or columnar table
+// are annotated. It returns nil, leaving out the marker column, when
+// nothing is annotated or the panel shows something else.
+func (m *Model) annotationRowMarks(displayNode interface{}, columnar *ColumnarPanel) []bool {
+	if len(m.Annotations) == 0 {
+		return nil
+	}
+	return m.rowMarks(displayNode, columnar, func(path string) bool {
+		return m.annotationIndex(path) >= 0
+	})
+}
+
+// rowMarks reports marked(path) for the display path of each row of the
+// KEY/VALUE or columnar table, or returns nil when the panel shows
+// something else.
+func (m *Model) rowMarks(displayNode interface{}, columnar *ColumnarPanel, marked func(path string) bool) []bool {
+	var keys []string
+	switch {
+	case columnar != nil:
+		keys = columnar.Keys
+	case isCompositeNode(displayNode):
+		keys = extractRowKeys(navigator.NodeToRows(displayNode))
+	default:
+		return nil
+	}
+	marks := make([]bool, len(keys))
+	for i, k := range keys {
+		marks[i] = marked(formatPathForDisplay(buildPathWithKey(m.Path, k)))
+	}
+	return marks
+}
+
+// markTableRows adds the marker column to a rendered table: the marker on
+// the body lines of marked rows and blanks on every other line. spans holds
+// the body lines of each row when rows may span several; nil means one
+// line per row.
+func markTableRows(table string, marks []bool, spans [][2]int) string {
+	trailing := strings.HasSuffix(table, "\n")
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+	marked := make([]bool, len(lines))
+	for row, on := range marks {
+		if !on {
+			continue
+		}
+		start, end := row, row+1
+		if spans != nil {
+			if row >= len(spans) {
+				continue
+			}
+			start, end = spans[row][0], spans[row][1]
+		}
+		// Body lines start below the header and separator.
+		for l := start + 2; l < end+2 && l < len(lines); l++ {
+			marked[l] = true
+		}
+	}
+	for i, line := range lines {
+		if marked[i] {
+			lines[i] = annotationMarker + " " + line
+		} else {
+			lines[i] = strings.Repeat(" ", markerColumnWidth) + line
+		}
+	}
+	out := strings.Join(lines, "\n")
+	if trailing {
+		out += "\n"
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateRow(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'a', Text: "a"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'a', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: 't', Mod: tea.ModCtrl}},
+	} {
+		m := testColumnarModel(tc.mode)
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		m.Update(tc.key)
+		assert.True(t, m.AnnotationInput, tc.mode)
+		typeKeys(m, "needs review")
+		assert.Contains(t, stripANSI(m.View().Content), "Note for _[1]: needs review", tc.mode)
+		m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		assert.False(t, m.AnnotationInput, tc.mode)
+		assert.Equal(t, []Annotation{{Path: "_[1]", Note: "needs review"}}, m.Annotations, tc.mode)
+		assert.Equal(t, "✎ needs review", m.annotationChip(), tc.mode)
+	}
+}
+
+func TestAnnotateFlagEditAndRemove(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	a := tea.KeyPressMsg{Code: 'a', Text: "a"}
+
+	m.Update(a)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, []Annotation{{Path: "_[0]"}}, m.Annotations)
+	assert.Equal(t, "✎ flagged", m.annotationChip())
+
+	// Reopening holds the note; esc leaves it unchanged.
+	m.Update(a)
+	typeKeys(m, "x")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Equal(t, []Annotation{{Path: "_[0]"}}, m.Annotations)
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(a)
+	typeKeys(m, "dup")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(a)
+	assert.Empty(t, m.AnnotationText)
+	m.Update(tea.KeyPressMsg{Code: 'x', Mod: tea.ModCtrl})
+	assert.Equal(t, []Annotation{{Path: "_[1]", Note: "dup"}}, m.Annotations)
+	assert.Empty(t, m.annotationChip())
+}
+
+func TestAnnotationMarkerColumn(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	assert.NotContains(t, m.View().Content, annotationMarker)
+
+	m.Annotations = []Annotation{{Path: "_[2]"}}
+	view := stripANSI(m.View().Content)
+	var marked []string
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, annotationMarker) {
+			marked = append(marked, line)
+		}
+	}
+	if assert.Len(t, marked, 1) {
+		assert.Contains(t, marked[0], "[2]")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	assert.Contains(t, stripANSI(m.View().Content), annotationMarker+" [2]")
+}
+
+func TestMarkTableRows(t *testing.T) {
+	table := "KEY  VALUE\n---  -----\na    1\nb    2\n     2b\n"
+	assert.Equal(t, "  KEY  VALUE\n  ---  -----\n  a    1\n✎ b    2\n✎      2b\n",
+		markTableRows(table, []bool{false, true}, [][2]int{{0, 1}, {1, 3}}))
+	assert.Equal(t, "  KEY  VALUE\n  ---  -----\n✎ a    1\n  b    2\n       2b",
+		markTableRows(strings.TrimSuffix(table, "\n"), []bool{true}, nil))
+}
+
+func TestCarrySessionKeepsAnnotations(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.Annotations = []Annotation{{Path: "_[0]", Note: "n"}}
+	nm := InitialModel(m.Root)
+	m.carrySession(&nm)
+	assert.Equal(t, m.Annotations, nm.Annotations)
+}
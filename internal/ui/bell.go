@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// BellMode says which events ring the terminal bell.
+type BellMode string
+
+const (
+	// BellNever never rings the bell.
+	BellNever BellMode = "never"
+	// BellError rings on failures: a status screen ending in error or an
+	// expression that fails to evaluate.
+	BellError BellMode = "error"
+	// BellAlways also rings when a status screen succeeds and when a deep
+	// search completes.
+	BellAlways BellMode = "always"
+)
+
+// ValidBellModes lists all valid bell modes for validation.
+var ValidBellModes = []BellMode{BellNever, BellError, BellAlways}
+
+// IsValidBellMode checks if a bell mode string is valid.
+func IsValidBellMode(mode string) bool {
+	return slices.Contains(ValidBellModes, BellMode(mode))
+}
+
+// visualBellDuration is how long the visual bell keeps the screen reversed.
+const visualBellDuration = 100 * time.Millisecond
+
+// ringBell queues the bell for an event, when the bell mode covers it. The
+// bell is sent after the message being handled (see Update).
+func (m *Model) ringBell(failed bool) {
+	switch m.Bell {
+	case BellAlways:
+		m.bellPending = true
+	case BellError:
+		m.bellPending = m.bellPending || failed
+	}
+}
+
+// takeBell returns a tea.Cmd ringing the queued bell, if any, and clears it.
+// The audible bell is BEL; the visual bell flashes the screen in reverse
+// video (DECSCNM), as terminals do for their own visual bell.
+func (m *Model) takeBell() tea.Cmd {
+	if !m.bellPending {
+		return nil
+	}
+	m.bellPending = false
+	if !m.VisualBell {
+		return tea.Raw("\a")
+	}
+	return tea.Batch(
+		tea.Raw("\x1b[?5h"),
+		tea.Tick(visualBellDuration, func(time.Time) tea.Msg { return tea.RawMsg{Msg: "\x1b[?5l"} }),
+	)
+}
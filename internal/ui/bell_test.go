@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidBellMode(t *testing.T) {
+	for _, mode := range []string{"never", "error", "always"} {
+		assert.True(t, IsValidBellMode(mode), mode)
+	}
+	assert.False(t, IsValidBellMode("sometimes"))
+	assert.False(t, IsValidBellMode(""))
+}
+
+func TestRingBell(t *testing.T) {
+	for _, tc := range []struct {
+		mode            BellMode
+		onError, onDone bool
+	}{
+		{"", false, false},
+		{BellNever, false, false},
+		{BellError, true, false},
+		{BellAlways, true, true},
+	} {
+		m := &Model{Bell: tc.mode}
+		m.ringBell(true)
+		assert.Equal(t, tc.onError, m.takeBell() != nil, "%q on error", tc.mode)
+		m.ringBell(false)
+		assert.Equal(t, tc.onDone, m.takeBell() != nil, "%q on success", tc.mode)
+		assert.Nil(t, m.takeBell(), "the bell rings once")
+	}
+}
+
+// TestBell_StatusError verifies that a status screen ending in error rings
+// the bell after the message is handled.
+func TestBell_StatusError(t *testing.T) {
+	schema := testStatusSchema()
+	schema.Status.DoneBehavior = DoneBehaviorWaitForKey
+	m := InitialModel(testStatusData())
+	m.ViewMode = "status"
+	m.StatusViewState = buildStatusViewModel(testStatusData(), schema, KeyModeVim, true, nil, 80, 24)
+	m.Bell = BellError
+
+	_, cmd := m.Update(statusDoneMsg{Err: errors.New("denied")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.RawMsg{Msg: "\a"}, cmd())
+}
+
+func TestBell_Visual(t *testing.T) {
+	m := &Model{Bell: BellAlways, VisualBell: true}
+	m.ringBell(false)
+	cmd := m.takeBell()
+	require.NotNil(t, cmd)
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	require.Len(t, batch, 2)
+	assert.Equal(t, tea.RawMsg{Msg: "\x1b[?5h"}, batch[0]())
+}
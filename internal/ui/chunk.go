@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+)
+
+// chunkNode returns the part of node shown at path: the loaded items of an
+// array longer than ChunkThreshold, or node whole. While part of an array
+// is shown, ChunkFull holds all of it.
+func (m *Model) chunkNode(node interface{}, path string) interface{} {
+	m.ChunkFull = nil
+	if m.ChunkSize <= 0 {
+		return node
+	}
+	size := max(m.ChunkSize, m.ChunkLoaded[cursorPathKey(path)])
+	shown, more := formatter.ChunkArray(node, size, m.ChunkThreshold)
+	if more > 0 {
+		m.ChunkFull, _ = node.([]interface{})
+	}
+	return shown
+}
+
+// chunkMoreRows is the sentinel row under an array shown in chunks, such
+// as "… 9,500 more (press L to load next 500)", or under a root the record
+// limits cut, or "" when it is shown whole.
+func (m *Model) chunkMoreRows() string {
+	shown, ok := m.Node.([]interface{})
+	if m.ChunkFull == nil || !ok || len(shown) >= len(m.ChunkFull) {
+		return m.limitedMoreRows()
+	}
+	more := len(m.ChunkFull) - len(shown)
+	return fmt.Sprintf("%s (press L to load next %s)", formatter.MoreRowsLine(more), formatter.GroupDigits(min(more, m.ChunkSize)))
+}
+
+// handleChunkKey loads the next chunk of the array shown in chunks with L,
+// or the whole document once the records the limits kept are all shown.
+// While text is being typed, L stays a letter.
+func (m *Model) handleChunkKey(keyStr string) bool {
+	if keyStr != "L" || m.chunkMoreRows() == "" || m.InputFocused || m.MapFilterActive || m.AdvancedSearchActive || m.FilterActive {
+		return false
+	}
+	if m.ChunkFull != nil {
+		m.loadNextChunk()
+	} else {
+		m.loadAllRecords()
+	}
+	return true
+}
+
+// loadNextChunk shows the next ChunkSize items of the current array,
+// keeping the selected row. Filters typed for the shorter list are cleared.
+func (m *Model) loadNextChunk() {
+	shown, _ := m.Node.([]interface{})
+	total := len(m.ChunkFull)
+	loaded := min(len(shown)+m.ChunkSize, total)
+	if m.ChunkLoaded == nil {
+		m.ChunkLoaded = map[string]int{}
+	}
+	m.ChunkLoaded[cursorPathKey(m.Path)] = loaded
+	cursor := m.Tbl.Cursor()
+	m.NavigateTo(m.ChunkFull, m.Path)
+	m.Tbl.SetCursor(cursor)
+	m.SyncTableState()
+	m.syncPathInputWithCursor()
+	m.ErrMsg = fmt.Sprintf("Loaded %s of %s items", formatter.GroupDigits(loaded), formatter.GroupDigits(total))
+	m.StatusType = "success"
+}
+
+// chunkRoot shows the starting node in chunks when it is a large array,
+// once configure has set the chunk size.
+func (m *Model) chunkRoot() {
+	if _, more := formatter.ChunkArray(m.Node, m.ChunkSize, m.ChunkThreshold); more > 0 {
+		m.NavigateTo(m.Node, m.Path)
+	}
+}
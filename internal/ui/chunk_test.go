@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testChunkedModel(items int) *Model {
+	node := make([]interface{}, items)
+	for i := range node {
+		node[i] = map[string]interface{}{"id": i}
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = KeyModeVim
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.ChunkSize = 10
+	m.ChunkThreshold = 20
+	m.Tbl.Focus()
+	m.chunkRoot()
+	m.applyLayout(true)
+	return &m
+}
+
+func TestChunkedArrayLoadsOnL(t *testing.T) {
+	m := testChunkedModel(25)
+	l := tea.KeyPressMsg{Code: 'L', Text: "L"}
+
+	assert.Len(t, m.Tbl.Rows(), 10)
+	assert.Equal(t, "… 15 more (press L to load next 10)", m.chunkMoreRows())
+	assert.Contains(t, stripANSI(m.View().Content), "… 15 more (press L to load next 10)")
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(l)
+	assert.Len(t, m.Tbl.Rows(), 20)
+	assert.Equal(t, 1, m.Tbl.Cursor(), "loading keeps the selected row")
+	assert.Equal(t, "Loaded 20 of 25 items", m.ErrMsg)
+	assert.Equal(t, "… 5 more (press L to load next 5)", m.chunkMoreRows())
+
+	m.Update(l)
+	assert.Len(t, m.Tbl.Rows(), 25)
+	assert.Empty(t, m.chunkMoreRows())
+	assert.NotContains(t, stripANSI(m.View().Content), "more (press L")
+}
+
+func TestChunkedArrayKeepsLoadedItems(t *testing.T) {
+	m := testChunkedModel(25)
+	m.Update(tea.KeyPressMsg{Code: 'L', Text: "L"})
+	assert.Len(t, m.Tbl.Rows(), 20)
+
+	// Leaving the array and coming back keeps what was loaded.
+	m.NavigateTo(m.Root.([]interface{})[0], "_[0]") //nolint:forcetypeassert
+	assert.Nil(t, m.ChunkFull)
+	m.NavigateTo(m.Root, "")
+	assert.Len(t, m.Tbl.Rows(), 20)
+}
+
+func TestChunkedArrayBelowThreshold(t *testing.T) {
+	m := testChunkedModel(20)
+	assert.Len(t, m.Tbl.Rows(), 20)
+	assert.Empty(t, m.chunkMoreRows())
+	assert.False(t, m.handleChunkKey("L"))
+}
+
+func TestChunkKeyWhileTyping(t *testing.T) {
+	m := testChunkedModel(25)
+	m.FilterActive = true
+	assert.False(t, m.handleChunkKey("L"), "L is a letter while a filter is typed")
+	m.FilterActive = false
+	m.InputFocused = true
+	assert.False(t, m.handleChunkKey("L"))
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// ColumnarPanel is the data panel content of the columnar view: one row
+// per visible element of a list of objects, one column per field.
+type ColumnarPanel struct {
+	Columns     []string   // Field names, in display order
+	Keys        []string   // Row keys ("[0]", "[3]", ...) shown in the first column
+	Rows        [][]string // Cell text, aligned with Columns
+	Filters     []string   // Filter text per column; nil when the filter row is hidden
+	FilterFocus int        // Index in Columns of the filter being edited, or -1
+	HeaderFocus string     // Column whose header has the cursor, or ""
+
+	Hints map[string]formatter.ColumnHint // Semantic state colors of columns (see stateHints)
+}
+
+// columnarFields returns the visible columns of the current node when the
+// columnar view applies to it: a list whose elements are objects with the
+// same keys. Hidden columns are left out unless every column is hidden.
+func (m *Model) columnarFields() []string {
+	all := m.columnarAllFields()
+	fields := make([]string, 0, len(all))
+	for _, f := range all {
+		if !slices.Contains(m.HiddenColumns, f) {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return all
+	}
+	return fields
+}
+
+// columnarAllFields returns every field of the current node's elements in
+// ColumnOrder, followed by the fields ColumnOrder does not list; nil when
+// the columnar view does not apply.
+func (m *Model) columnarAllFields() []string {
+	if !m.ColumnarView || m.AdvancedSearchActive || m.activeCustomView() != nil {
+		return nil
+	}
+	if _, ok := m.Node.([]interface{}); !ok {
+		return nil
+	}
+	ok, fields := navigator.IsHomogeneousArray(m.Node)
+	if !ok {
+		return nil
+	}
+	ordered := make([]string, 0, len(fields))
+	for _, f := range m.ColumnOrder {
+		if slices.Contains(fields, f) && !slices.Contains(ordered, f) {
+			ordered = append(ordered, f)
+		}
+	}
+	for _, f := range fields {
+		if !slices.Contains(ordered, f) {
+			ordered = append(ordered, f)
+		}
+	}
+	return ordered
+}
+
+// columnarPanel builds the columnar view of the rows currently listed in
+// AllRowKeys, so the map and column filters apply to it.
+func (m *Model) columnarPanel(fields []string) *ColumnarPanel {
+	elems, _ := m.Node.([]interface{})
+	p := &ColumnarPanel{Columns: fields, FilterFocus: -1, HeaderFocus: m.focusedHeader(fields), Hints: stateHints(m.DisplaySchema)}
+	for _, key := range m.AllRowKeys {
+		i := parseArrayIndex(key)
+		if i < 0 || i >= len(elems) {
+			continue
+		}
+		obj, _ := elems[i].(map[string]interface{})
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			row[j] = formatter.Stringify(obj[f])
+		}
+		p.Keys = append(p.Keys, key)
+		p.Rows = append(p.Rows, row)
+	}
+	if m.ColumnFilterActive || len(m.activeColumnFilters(fields)) > 0 {
+		p.Filters = make([]string, len(fields))
+		for j, f := range fields {
+			p.Filters[j] = m.ColumnFilters[f]
+		}
+		if m.ColumnFilterActive {
+			p.FilterFocus = m.ColumnFilterCol
+		}
+	}
+	return p
+}
+
+// renderColumnarPanel renders p with the row keys as the first column. The
+// filter row, when shown, takes the place of the separator line, so the
+// table keeps the header/separator/rows shape windowTable expects.
+func renderColumnarPanel(p ColumnarPanel, width int, noColor bool) string {
+	columns := append([]string{"#"}, p.Columns...)
+	rows := make([][]string, len(p.Rows))
+	for i, row := range p.Rows {
+		rows[i] = append([]string{p.Keys[i]}, row...)
+	}
+	opts := formatter.ColumnarOptions{
+		NoColor:        noColor,
+		TotalWidth:     width,
+		RowNumberStyle: "none",
+		FilterFocus:    -1,
+		FocusedHeader:  p.HeaderFocus,
+		ColumnHints:    p.Hints,
+	}
+	if p.Filters != nil {
+		opts.Filters = append([]string{""}, p.Filters...)
+		if p.FilterFocus >= 0 {
+			opts.FilterFocus = p.FilterFocus + 1
+		}
+	}
+	return formatter.RenderColumnarTable(columns, rows, opts)
+}
+
+// columnFilter is the filter text typed under one column.
+type columnFilter struct {
+	field string
+	text  string
+}
+
+// activeColumnFilters returns the non-blank column filters in column order.
+func (m *Model) activeColumnFilters(fields []string) []columnFilter {
+	var filters []columnFilter
+	for _, f := range fields {
+		if text := m.ColumnFilters[f]; strings.TrimSpace(text) != "" {
+			filters = append(filters, columnFilter{field: f, text: text})
+		}
+	}
+	return filters
+}
+
+// matchColumnFilters reports whether every filter's text occurs in the
+// element's value for that column, ignoring case.
+func matchColumnFilters(filters []columnFilter, elem interface{}) bool {
+	obj, _ := elem.(map[string]interface{})
+	for _, f := range filters {
+		cell := strings.ToLower(formatter.Stringify(obj[f.field]))
+		if !strings.Contains(cell, strings.ToLower(f.text)) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyColumnFilters lists the elements of the current node that match the
+// column filters, like applyMapFilter does for map keys.
+func (m *Model) applyColumnFilters() {
+	keyW := m.KeyColWidth
+	if keyW <= 0 {
+		keyW = 30
+	}
+	valueW := m.ValueColWidth
+	if valueW <= 0 {
+		valueW = 60
+	}
+	stringRows := navigator.NodeToRows(m.Node)
+	if filters := m.activeColumnFilters(m.columnarFields()); len(filters) > 0 {
+		elems, _ := m.Node.([]interface{})
+		kept := make([][]string, 0, len(stringRows))
+		for i, row := range stringRows {
+			if i < len(elems) && matchColumnFilters(filters, elems[i]) {
+				kept = append(kept, row)
+			}
+		}
+		stringRows = kept
+	}
+	m.AllRows = styleRowsWithWidths(stringRows, keyW, valueW)
+	m.AllRowKeys = extractRowKeys(stringRows)
+	m.SyncTableState(true)
+	m.syncPathInputWithCursor()
+}
+
+// clearColumnFilters closes the filter row and lists every element again.
+func (m *Model) clearColumnFilters() {
+	hadFilters := len(m.ColumnFilters) > 0
+	m.ColumnFilters = nil
+	m.ColumnFilterActive = false
+	m.ColumnFilterCol = 0
+	if hadFilters {
+		m.applyColumnFilters()
+	}
+}
+
+// columnFilterExpr returns the CEL expression equivalent to the column
+// filters, such as
+//
+//	_.items.filter(x, toString(x.status).lowerAscii().contains("act"))
+//
+// or "" when no filter is set.
+func (m *Model) columnFilterExpr() string {
+	filters := m.activeColumnFilters(m.columnarFields())
+	if len(filters) == 0 {
+		return ""
+	}
+	conds := make([]string, len(filters))
+	for i, f := range filters {
+		field := "x." + f.field
+		if needsBracketNotation(f.field) {
+			field = "x[" + strconv.Quote(f.field) + "]"
+		}
+		conds[i] = fmt.Sprintf("toString(%s).lowerAscii().contains(%s)", field, strconv.Quote(strings.ToLower(f.text)))
+	}
+	return fmt.Sprintf("%s.filter(x, %s)", formatPathForDisplay(m.Path), strings.Join(conds, " && "))
+}
+
+// handleColumnFilterKey edits the filter row while it is open: typing goes
+// to the focused column's filter, tab/shift+tab and left/right move between
+// columns, enter closes the row keeping the filters, and esc clears them.
+// Other keys (up/down, function keys, the toggle binding) are not handled.
+func (m *Model) handleColumnFilterKey(keyStr string) bool {
+	if !m.ColumnFilterActive || m.InputFocused || m.MapFilterActive {
+		return false
+	}
+	fields := m.columnarFields()
+	if fields == nil {
+		m.ColumnFilterActive = false
+		return false
+	}
+	if m.ColumnFilterCol < 0 || m.ColumnFilterCol >= len(fields) {
+		m.ColumnFilterCol = 0
+	}
+	field := fields[m.ColumnFilterCol]
+	switch keyStr {
+	case "tab", "right":
+		m.ColumnFilterCol = (m.ColumnFilterCol + 1) % len(fields)
+		return true
+	case "shift+tab", "left":
+		m.ColumnFilterCol = (m.ColumnFilterCol + len(fields) - 1) % len(fields)
+		return true
+	case "enter":
+		m.ColumnFilterActive = false
+		return true
+	case "esc":
+		m.clearColumnFilters()
+		m.logEvent("cancel-column-filter:esc")
+		return true
+	case "backspace", "ctrl+h":
+		text := m.ColumnFilters[field]
+		if text == "" {
+			return true
+		}
+		_, size := utf8.DecodeLastRuneInString(text)
+		m.setColumnFilter(field, text[:len(text)-size])
+		return true
+	case "space":
+		keyStr = " "
+	}
+	r, size := utf8.DecodeRuneInString(keyStr)
+	if size != len(keyStr) || !unicode.IsPrint(r) {
+		return false
+	}
+	m.setColumnFilter(field, m.ColumnFilters[field]+keyStr)
+	return true
+}
+
+func (m *Model) setColumnFilter(field, text string) {
+	if m.ColumnFilters == nil {
+		m.ColumnFilters = map[string]string{}
+	}
+	if text == "" {
+		delete(m.ColumnFilters, field)
+	} else {
+		m.ColumnFilters[field] = text
+	}
+	m.applyColumnFilters()
+}
+
+// vimToggleColumns toggles the columnar view (same as F11).
+func (m *Model) vimToggleColumns() (tea.Model, tea.Cmd) {
+	return m, menuActionColumns(m)
+}
+
+// menuActionColumns switches lists of objects between the KEY/VALUE table
+// and a table with one column per field.
+func menuActionColumns(m *Model) tea.Cmd {
+	m.ColumnarView = !m.ColumnarView
+	m.StatusType = "success"
+	switch {
+	case !m.ColumnarView:
+		m.clearColumnFilters()
+		m.ErrMsg = "Key/value view"
+	case m.columnarFields() == nil:
+		m.ErrMsg = "Columnar view (shown for lists of objects with the same keys)"
+	default:
+		m.ErrMsg = "Columnar view"
+	}
+	return nil
+}
+
+// vimToggleColumnFilter opens or closes the column filter row (same as ctrl+f).
+func (m *Model) vimToggleColumnFilter() (tea.Model, tea.Cmd) {
+	return m, menuActionColumnFilter(m)
+}
+
+// menuActionColumnFilter opens or closes the filter row under the columnar
+// view's header. Closing it keeps the filters applied.
+func menuActionColumnFilter(m *Model) tea.Cmd {
+	if m.columnarFields() == nil {
+		m.ErrMsg = "Column filters need the columnar view of a list of objects"
+		m.StatusType = "error"
+		return nil
+	}
+	m.ColumnFilterActive = !m.ColumnFilterActive
+	m.clearErrorUnlessSticky()
+	return nil
+}
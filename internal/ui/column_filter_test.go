@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testColumnarModel(mode KeyMode) *Model {
+	node := []interface{}{
+		map[string]interface{}{"name": "alpha", "status": "active", "tier": "gold"},
+		map[string]interface{}{"name": "beta", "status": "archived", "tier": "silver"},
+		map[string]interface{}{"name": "gamma", "status": "Active", "tier": "silver"},
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = mode
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.Tbl.Focus()
+	m.applyLayout(true)
+	return &m
+}
+
+func typeKeys(m *Model, text string) {
+	for _, r := range text {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+func TestColumnarViewToggle(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'v', Text: "v"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'v', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: tea.KeyF11}},
+	} {
+		m := testColumnarModel(tc.mode)
+		require.Nil(t, panelLayoutStateFromModel(m, PanelLayoutModelOptions{}).Columnar, tc.mode)
+		m.Update(tc.key)
+		assert.True(t, m.ColumnarView, tc.mode)
+
+		view := m.View().Content
+		assert.Contains(t, view, "name", tc.mode)
+		assert.Contains(t, view, "status", tc.mode)
+		assert.Contains(t, view, "silver", tc.mode)
+
+		m.Update(tc.key)
+		assert.False(t, m.ColumnarView, tc.mode)
+	}
+}
+
+func TestColumnFilterRow(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: 'f', Mod: tea.ModCtrl})
+	assert.False(t, m.ColumnFilterActive, "filter row needs the columnar view")
+	assert.Equal(t, "error", m.StatusType)
+
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	m.Update(tea.KeyPressMsg{Code: 'f', Mod: tea.ModCtrl})
+	require.True(t, m.ColumnFilterActive)
+
+	// Typing filters the focused column; tab moves to the next column and
+	// filters combine with AND.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	typeKeys(m, "ACT")
+	assert.Equal(t, []string{"[0]", "[2]"}, m.AllRowKeys, "case-insensitive substring")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	typeKeys(m, "sil")
+	assert.Equal(t, []string{"[2]"}, m.AllRowKeys)
+	assert.Equal(t, map[string]string{"status": "ACT", "tier": "sil"}, m.ColumnFilters)
+
+	// The expression bar shows the equivalent CEL expression.
+	want := `_.filter(x, toString(x.status).lowerAscii().contains("act") && toString(x.tier).lowerAscii().contains("sil"))`
+	assert.Equal(t, want, m.PathInput.Value())
+	assert.Contains(t, m.View().Content, "toString(x.status)")
+	result, err := m.evaluateExpression(want, m.Root)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{m.Root.([]interface{})[2]}, result)
+
+	// Backspace edits the focused filter.
+	for range 3 {
+		m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	assert.Equal(t, []string{"[0]", "[2]"}, m.AllRowKeys)
+
+	// Enter closes the row and keeps the filters; esc then clears them.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.False(t, m.ColumnFilterActive)
+	assert.Equal(t, []string{"[0]", "[2]"}, m.AllRowKeys)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Empty(t, m.ColumnFilters)
+	assert.Equal(t, []string{"[0]", "[1]", "[2]"}, m.AllRowKeys)
+	assert.Equal(t, "_[0]", m.PathInput.Value())
+}
+
+func TestColumnFilterRowRendering(t *testing.T) {
+	m := testColumnarModel(KeyModeFunction)
+	m.NoColor = true
+	m.Update(tea.KeyPressMsg{Code: tea.KeyF11})
+	m.Update(tea.KeyPressMsg{Code: 'f', Mod: tea.ModCtrl})
+	typeKeys(m, "zz")
+
+	lines := strings.Split(m.View().Content, "\n")
+	require.Greater(t, len(lines), 3)
+	assert.Contains(t, lines[1], "#")
+	assert.Contains(t, lines[1], "name")
+	assert.Contains(t, lines[2], "›zz", "focused filter cell under its column")
+	assert.NotContains(t, m.View().Content, "alpha", "no rows match")
+}
+
+func TestColumnFilterExpr_BracketNotation(t *testing.T) {
+	node := []interface{}{
+		map[string]interface{}{"display-name": "a"},
+	}
+	m := InitialModel(node)
+	m.Node = node
+	m.Path = "items"
+	m.ColumnarView = true
+	m.ColumnFilters = map[string]string{"display-name": `Say "hi"`}
+	assert.Equal(t, `_.items.filter(x, toString(x["display-name"]).lowerAscii().contains("say \"hi\""))`, m.columnFilterExpr())
+}
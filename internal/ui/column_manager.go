@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// columnSchema is the JSON Schema the column manager exports: the column
+// order as the x-kvx-columnOrder extension and hidden columns as deprecated
+// properties, which --schema and tui.ParseSchema read back.
+type columnSchema struct {
+	Type  string            `json:"type"`
+	Items columnSchemaItems `json:"items"`
+}
+
+type columnSchemaItems struct {
+	Type        string                     `json:"type"`
+	ColumnOrder []string                   `json:"x-kvx-columnOrder"`
+	Properties  map[string]map[string]bool `json:"properties,omitempty"`
+}
+
+// columnLayoutSchema returns the JSON Schema snippet for the column order
+// and visibility of the columnar view.
+func (m *Model) columnLayoutSchema() string {
+	all := m.columnarAllFields()
+	schema := columnSchema{Type: "array", Items: columnSchemaItems{Type: "object", ColumnOrder: all}}
+	for _, f := range all {
+		if slices.Contains(m.HiddenColumns, f) {
+			if schema.Items.Properties == nil {
+				schema.Items.Properties = map[string]map[string]bool{}
+			}
+			schema.Items.Properties[f] = map[string]bool{"deprecated": true}
+		}
+	}
+	out, _ := json.MarshalIndent(schema, "", "  ")
+	return string(out)
+}
+
+// handleColumnManagerKey handles keys while the column manager is open:
+// up/down select a column, space shows or hides it, shift+up/down (K/J)
+// move it, y copies the layout as a JSON Schema snippet, and esc, enter,
+// or the column manager binding close the overlay. Other keys are ignored
+// so they do not reach the table underneath; ctrl+c still quits.
+func (m *Model) handleColumnManagerKey(keyStr string) bool {
+	if !m.ColumnManagerOpen {
+		return false
+	}
+	all := m.columnarAllFields()
+	if all == nil {
+		m.ColumnManagerOpen = false
+		return false
+	}
+	m.ColumnManagerIndex = max(0, min(m.ColumnManagerIndex, len(all)-1))
+	switch keyStr {
+	case "ctrl+c":
+		return false
+	case "up", "k", "ctrl+p":
+		m.ColumnManagerIndex = max(0, m.ColumnManagerIndex-1)
+	case "down", "j", "ctrl+n":
+		m.ColumnManagerIndex = min(len(all)-1, m.ColumnManagerIndex+1)
+	case "space", "x":
+		m.toggleColumnHidden(all[m.ColumnManagerIndex])
+	case "shift+up", "alt+up", "K":
+		m.moveColumn(all, -1)
+	case "shift+down", "alt+down", "J":
+		m.moveColumn(all, 1)
+	case "y", "alt+w":
+		if m.kioskRefuses("Copy") {
+			break
+		}
+		snippet := m.columnLayoutSchema()
+		if err := copyToClipboard(snippet); err != nil {
+			m.ErrMsg = fmt.Sprintf("Clipboard unavailable: %v", err)
+			m.StatusType = "error"
+		} else {
+			m.ErrMsg = "Copied column schema (use with --schema)"
+			m.StatusType = "success"
+		}
+	case "esc", "enter", "q":
+		m.ColumnManagerOpen = false
+	default:
+		if item, ok := CurrentMenuConfig().Items["column_manager"]; ok && item.Keys.forMode(m.KeyMode) == keyStr {
+			m.ColumnManagerOpen = false
+		}
+	}
+	return true
+}
+
+// toggleColumnHidden shows or hides field, keeping at least one column.
+func (m *Model) toggleColumnHidden(field string) {
+	if i := slices.Index(m.HiddenColumns, field); i >= 0 {
+		m.HiddenColumns = slices.Delete(slices.Clone(m.HiddenColumns), i, i+1)
+	} else {
+		if len(m.columnarFields()) <= 1 {
+			m.ErrMsg = "At least one column stays visible"
+			m.StatusType = "error"
+			return
+		}
+		m.HiddenColumns = append(slices.Clone(m.HiddenColumns), field)
+	}
+	m.ColumnFilterCol = 0
+	if len(m.ColumnFilters) > 0 {
+		m.applyColumnFilters()
+	}
+}
+
+// moveColumn moves the selected column of all by delta places and records
+// the result as the column order.
+func (m *Model) moveColumn(all []string, delta int) {
+	i, j := m.ColumnManagerIndex, m.ColumnManagerIndex+delta
+	if j < 0 || j >= len(all) {
+		return
+	}
+	order := slices.Clone(all)
+	order[i], order[j] = order[j], order[i]
+	m.ColumnOrder = order
+	m.ColumnManagerIndex = j
+	m.ColumnFilterCol = 0
+}
+
+// forMode returns the binding for mode.
+func (k MenuKeyBindings) forMode(mode KeyMode) string {
+	switch mode {
+	case KeyModeEmacs:
+		return k.Emacs
+	case KeyModeFunction:
+		return k.Function
+	default:
+		return k.Vim
+	}
+}
+
+// columnManagerContent renders the column manager overlay, shown in place
+// of the help panel like the function palette.
+func columnManagerContent(m *Model) string {
+	if m == nil || !m.ColumnManagerOpen {
+		return ""
+	}
+	all := m.columnarAllFields()
+	if all == nil {
+		return ""
+	}
+	width := m.WinWidth
+	if width <= 0 {
+		width = 80
+	}
+	// Same height budget as the function palette: ~40% of the window.
+	inner := min(max(m.WinHeight*2/5, 6), 18)
+	visible := inner - 1 // the last line is the key hint
+	start := 0
+	if m.ColumnManagerIndex >= visible {
+		start = m.ColumnManagerIndex - visible + 1
+	}
+	end := min(len(all), start+visible)
+
+	selected := lipgloss.NewStyle()
+	muted := lipgloss.NewStyle()
+	if !m.NoColor {
+		selected = selected.Bold(true).Foreground(lipgloss.Color("6"))
+		muted = muted.Foreground(lipgloss.Color("243"))
+	}
+	lines := make([]string, 0, inner)
+	for i := start; i < end; i++ {
+		box := "[x]"
+		if slices.Contains(m.HiddenColumns, all[i]) {
+			box = "[ ]"
+		}
+		line := fmt.Sprintf("  %s %s", box, all[i])
+		if i == m.ColumnManagerIndex {
+			line = selected.Render(fmt.Sprintf("▸ %s %s", box, all[i]))
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < visible {
+		lines = append(lines, "")
+	}
+	lines = append(lines, muted.Render("↑↓ select  space show/hide  K/J move  y copy schema  esc close"))
+
+	rendered := panelWithTitle("Columns", strings.Join(lines, "\n"), width, inner+2, borderForTheme(CurrentTheme()), m.NoColor)
+	return strings.TrimRight(rendered, "\n") + "\n"
+}
+
+// vimToggleColumnManager opens or closes the column manager (same as ctrl+o).
+func (m *Model) vimToggleColumnManager() (tea.Model, tea.Cmd) {
+	return m, menuActionColumnManager(m)
+}
+
+// menuActionColumnManager opens the column manager overlay of the columnar
+// view, which shows, hides, and reorders its columns.
+func menuActionColumnManager(m *Model) tea.Cmd {
+	if m.columnarAllFields() == nil {
+		m.ErrMsg = "The column manager needs the columnar view of a list of objects"
+		m.StatusType = "error"
+		return nil
+	}
+	m.ColumnManagerOpen = !m.ColumnManagerOpen
+	m.ColumnFilterActive = false
+	m.clearErrorUnlessSticky()
+	return nil
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnManagerOpens(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		view tea.KeyPressMsg
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'v', Text: "v"}, tea.KeyPressMsg{Code: 'c', Text: "c"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'v', Mod: tea.ModAlt}, tea.KeyPressMsg{Code: 'c', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: tea.KeyF11}, tea.KeyPressMsg{Code: 'o', Mod: tea.ModCtrl}},
+	} {
+		m := testColumnarModel(tc.mode)
+		m.Update(tc.key)
+		assert.False(t, m.ColumnManagerOpen, "%s: needs the columnar view", tc.mode)
+		assert.Equal(t, "error", m.StatusType, tc.mode)
+
+		m.Update(tc.view)
+		m.Update(tc.key)
+		require.True(t, m.ColumnManagerOpen, tc.mode)
+		assert.Contains(t, m.View().Content, "[x] status", tc.mode)
+
+		// The binding closes the overlay again.
+		m.Update(tc.key)
+		assert.False(t, m.ColumnManagerOpen, tc.mode)
+	}
+}
+
+func TestColumnManagerHideAndMove(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.NoColor = true
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	require.True(t, m.ColumnManagerOpen)
+	assert.Equal(t, []string{"name", "status", "tier"}, m.columnarAllFields())
+
+	// Space hides the selected column; keys do not reach the table.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	assert.Equal(t, []string{"status"}, m.HiddenColumns)
+	assert.Equal(t, []string{"name", "tier"}, m.columnarFields())
+	assert.Contains(t, m.View().Content, "▸ [ ] status")
+
+	// K/J move the selected column.
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m.Update(tea.KeyPressMsg{Code: 'K', Text: "K"})
+	assert.Equal(t, []string{"name", "tier", "status"}, m.ColumnOrder)
+	assert.Equal(t, 1, m.ColumnManagerIndex)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp, Mod: tea.ModShift})
+	assert.Equal(t, []string{"tier", "name", "status"}, m.ColumnOrder)
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.False(t, m.ColumnManagerOpen)
+	panel := panelLayoutStateFromModel(m, PanelLayoutModelOptions{}).Columnar
+	require.NotNil(t, panel)
+	assert.Equal(t, []string{"tier", "name"}, panel.Columns)
+	assert.NotContains(t, m.View().Content, "archived")
+}
+
+func TestColumnManagerKeepsOneColumn(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.ColumnarView = true
+	m.HiddenColumns = []string{"name", "status"}
+	m.ColumnManagerOpen = true
+	m.ColumnManagerIndex = 2
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	assert.Equal(t, []string{"name", "status"}, m.HiddenColumns)
+	assert.Equal(t, "error", m.StatusType)
+}
+
+func TestColumnManagerCopySchema(t *testing.T) {
+	var copied string
+	orig := copyToClipboardFn
+	copyToClipboardFn = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { copyToClipboardFn = orig }()
+
+	m := testColumnarModel(KeyModeVim)
+	m.ColumnarView = true
+	m.ColumnOrder = []string{"tier"}
+	m.HiddenColumns = []string{"status"}
+	m.ColumnManagerOpen = true
+	m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	assert.Equal(t, "success", m.StatusType)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(copied), &schema))
+	assert.Equal(t, map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":              "object",
+			"x-kvx-columnOrder": []interface{}{"tier", "name", "status"},
+			"properties": map[string]interface{}{
+				"status": map[string]interface{}{"deprecated": true},
+			},
+		},
+	}, schema)
+}
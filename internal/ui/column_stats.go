@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+)
+
+// columnStatsValueWidth caps the min and max values shown in the status bar.
+const columnStatsValueWidth = 24
+
+// handleHeaderKey moves the cursor between the columnar view's headers.
+// Up on the first row focuses the header row; left/right (and tab) then
+// move between columns, and down, enter, or esc return to the rows. Other
+// keys leave the header row and are handled as usual.
+func (m *Model) handleHeaderKey(keyStr string) bool {
+	if m.InputFocused || m.MapFilterActive || m.FilterActive || m.AdvancedSearchActive || m.ColumnFilterActive {
+		return false
+	}
+	fields := m.columnarFields()
+	if fields == nil {
+		m.HeaderFocused = false
+		return false
+	}
+	up, down, left, right := "k", "j", "h", "l"
+	switch m.KeyMode {
+	case KeyModeEmacs:
+		up, down, left, right = "ctrl+p", "ctrl+n", "ctrl+b", "ctrl+f"
+	case KeyModeFunction:
+		up, down, left, right = "up", "down", "left", "right"
+	}
+	if !m.HeaderFocused {
+		if (keyStr != "up" && keyStr != up) || m.Tbl.Cursor() != 0 {
+			return false
+		}
+		m.HeaderFocused = true
+		m.HeaderCol = min(m.HeaderCol, len(fields)-1)
+		m.clearErrorUnlessSticky()
+		return true
+	}
+	switch keyStr {
+	case "right", "tab", right:
+		m.HeaderCol = (m.HeaderCol + 1) % len(fields)
+	case "left", "shift+tab", left:
+		m.HeaderCol = (m.HeaderCol + len(fields) - 1) % len(fields)
+	case "up", up:
+	case "down", "enter", "esc", down:
+		m.HeaderFocused = false
+	default:
+		m.HeaderFocused = false
+		return false
+	}
+	return true
+}
+
+// focusedHeader returns the field whose header has the cursor, or "".
+func (m *Model) focusedHeader(fields []string) string {
+	if !m.HeaderFocused || m.HeaderCol < 0 || m.HeaderCol >= len(fields) {
+		return ""
+	}
+	return fields[m.HeaderCol]
+}
+
+// columnStatsStatus returns the status bar text for the focused header,
+// such as "status: string · 4 distinct · 1 null · min active · max pending",
+// or "". Stats are computed over the loaded elements of the list the first
+// time a column is focused and kept until the list changes; the schema's
+// description of the field follows them.
+func (m *Model) columnStatsStatus() string {
+	field := m.focusedHeader(m.columnarFields())
+	if field == "" {
+		return ""
+	}
+	text, ok := m.ColumnStatsCache[field]
+	if !ok {
+		text = m.computeColumnStats(field)
+		if m.ColumnStatsCache == nil {
+			m.ColumnStatsCache = map[string]string{}
+		}
+		m.ColumnStatsCache[field] = text
+	}
+	if desc := m.schemaDescriptionForColumn(field); desc != "" {
+		text += " · " + desc
+	}
+	return text
+}
+
+// computeColumnStats formats the stats of field over the loaded elements.
+func (m *Model) computeColumnStats(field string) string {
+	elems, _ := m.Node.([]interface{})
+	cells := make([]string, len(elems))
+	for i, e := range elems {
+		obj, _ := e.(map[string]interface{})
+		cells[i] = formatter.Stringify(obj[field])
+	}
+	s := formatter.ComputeColumnStats(cells)
+	text := fmt.Sprintf("%s: %s · %s distinct · %s null", field, s.Type,
+		formatter.GroupDigits(s.Distinct), formatter.GroupDigits(s.Nulls))
+	if s.Distinct > 0 {
+		text += fmt.Sprintf(" · min %s · max %s",
+			formatter.TruncateCell(s.Min, columnStatsValueWidth), formatter.TruncateCell(s.Max, columnStatsValueWidth))
+	}
+	return text
+}
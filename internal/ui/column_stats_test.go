@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderFocusShowsColumnStats(t *testing.T) {
+	for _, tc := range []struct {
+		mode         KeyMode
+		up, right    tea.KeyPressMsg
+		down, right2 tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'k', Text: "k"}, tea.KeyPressMsg{Code: 'l', Text: "l"}, tea.KeyPressMsg{Code: 'j', Text: "j"}, tea.KeyPressMsg{Code: tea.KeyRight}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl}, tea.KeyPressMsg{Code: 'f', Mod: tea.ModCtrl}, tea.KeyPressMsg{Code: 'n', Mod: tea.ModCtrl}, tea.KeyPressMsg{Code: tea.KeyTab}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: tea.KeyUp}, tea.KeyPressMsg{Code: tea.KeyRight}, tea.KeyPressMsg{Code: tea.KeyDown}, tea.KeyPressMsg{Code: tea.KeyTab}},
+	} {
+		m := testColumnarModel(tc.mode)
+		m.ColumnarView = true
+		m.Update(tc.up)
+		require.True(t, m.HeaderFocused, tc.mode)
+		assert.Equal(t, "name: string · 3 distinct · 0 null · min alpha · max gamma", m.columnStatsStatus(), tc.mode)
+		assert.Contains(t, stripANSI(m.View().Content), "›name", tc.mode)
+
+		m.Update(tc.right)
+		m.Update(tc.right2)
+		assert.Equal(t, "tier", m.focusedHeader(m.columnarFields()), tc.mode)
+		state := panelLayoutStateFromModel(m, PanelLayoutModelOptions{})
+		assert.Equal(t, "tier: string · 2 distinct · 0 null · min gold · max silver", state.InfoMessage, tc.mode)
+
+		m.Update(tc.down)
+		assert.False(t, m.HeaderFocused, tc.mode)
+		assert.Equal(t, 0, m.Tbl.Cursor(), tc.mode)
+		assert.Empty(t, m.columnStatsStatus(), tc.mode)
+	}
+}
+
+func TestHeaderFocusOnlyFromFirstRow(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.ColumnarView = true
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	assert.False(t, m.HeaderFocused, "k moved back to the first row")
+	assert.Equal(t, 0, m.Tbl.Cursor())
+
+	m.ColumnarView = false
+	assert.False(t, m.handleHeaderKey("up"), "the key/value table has no column headers")
+}
+
+func TestColumnStatsCached(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.ColumnarView = true
+	m.handleHeaderKey("up")
+	first := m.columnStatsStatus()
+	require.NotEmpty(t, first)
+	m.ColumnStatsCache["name"] = "cached"
+	assert.Equal(t, "cached", m.columnStatsStatus())
+
+	m.NavigateTo(m.Root, "")
+	assert.False(t, m.HeaderFocused)
+	assert.Nil(t, m.ColumnStatsCache, "stats are computed again for the list navigated to")
+}
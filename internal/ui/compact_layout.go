@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+)
+
+// useCompactLayout reports whether a window is too small for the bordered
+// panel layout.
+func useCompactLayout(width, height int) bool {
+	return width < CompactLayoutWidth || height < CompactLayoutHeight
+}
+
+// renderCompactLayout renders the minimal layout used in small terminals: one
+// "key: value" column without borders, an optional status and input line,
+// and a footer with only the help and quit keys. Every line is cut to the
+// window width and the output never exceeds the window height.
+func renderCompactLayout(state PanelLayoutState) string {
+	width, height := state.WinWidth, state.WinHeight
+	if width < TooSmallWidth || height < TooSmallHeight {
+		return renderTooSmall(width, height)
+	}
+	th := CurrentTheme()
+
+	// Status and input lines sit above the footer. When the window is too
+	// short for both, the input line wins.
+	var tail []string
+	if msg := strings.TrimSpace(state.InfoMessage); msg != "" {
+		line := strings.Join(strings.Fields(msg), " ")
+		if state.InfoError && !state.NoColor {
+			line = lipgloss.NewStyle().Foreground(th.StatusError).Render(line)
+		}
+		tail = append(tail, line)
+	}
+	if state.InputVisible && state.Input != nil {
+		prompt := "❯ "
+		if state.SearchActive {
+			prompt = searchPrompt(state.NoColor)
+		}
+		state.Input.SetWidth(max(width-ansiVisibleWidth(prompt), 1))
+		tail = append(tail, prompt+state.Input.View())
+	}
+	var footer []string
+	if !state.HideFooter {
+		footer = []string{renderCompactFooter(state.NoColor, state.KeyMode)}
+	}
+	avail := height - len(footer)
+	if len(tail) > avail {
+		tail = tail[len(tail)-avail:]
+	}
+	avail -= len(tail)
+
+	// The path replaces the panel title when there is room to spare.
+	var lines []string
+	if label := strings.TrimSpace(state.PathLabel); label != "" && avail > 3 {
+		if !state.NoColor {
+			label = lipgloss.NewStyle().Foreground(th.HeaderFG).Render(label)
+		}
+		lines = append(lines, label)
+		avail--
+	}
+	body := compactBody(state, width, avail)
+	for len(body) < avail {
+		body = append(body, "")
+	}
+	lines = append(append(append(lines, body...), tail...), footer...)
+	out := clampANSITextWidth(strings.Join(lines, "\n"), width)
+	return clampANSITextHeight(out, height)
+}
+
+// compactBody returns the data lines of the compact layout: the help text,
+// a custom view, a scalar value, or the rows of the current node with the
+// selected row highlighted and kept in view.
+func compactBody(state PanelLayoutState, width, height int) []string {
+	if height <= 0 {
+		return nil
+	}
+	var text string
+	switch {
+	case state.HelpVisible:
+		text = state.HelpText
+	case state.CustomContent != "":
+		text = state.CustomContent
+	case !state.SearchActive && !isCompositeNode(state.DisplayNode):
+		text = scalarDisplayText(state.DisplayNode)
+	}
+	if text != "" {
+		lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+		if state.HelpVisible {
+			lines, _, _ = scrollHelpLines(lines, state.HelpQuery, state.HelpScroll, height)
+		}
+		if len(lines) > height {
+			lines = lines[:height]
+		}
+		return lines
+	}
+
+	var rows [][]string
+	if state.SearchActive {
+		for _, hit := range state.SearchResults {
+			key := hit.Key
+			if hit.FullPath != "" {
+				key = hit.FullPath
+			}
+			rows = append(rows, []string{key, hit.Value})
+		}
+	} else {
+		rows = navigator.NodeToRows(state.DisplayNode)
+	}
+	if len(rows) == 0 {
+		return []string{"(empty)"}
+	}
+
+	selected := min(max(state.SelectedRow, 0), len(rows)-1)
+	start := max(selected-height+1, 0)
+	end := min(start+height, len(rows))
+
+	highlight := lipgloss.NewStyle()
+	keyStyle := lipgloss.NewStyle()
+	if state.NoColor {
+		highlight = highlight.Reverse(true)
+	} else {
+		th := CurrentTheme()
+		highlight = highlight.Background(th.SelectedBG).Foreground(th.SelectedFG)
+		keyStyle = keyStyle.Foreground(th.KeyColor)
+	}
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		value := strings.Join(strings.Fields(rows[i][1]), " ")
+		if i == selected {
+			line := clampANSITextWidth(rows[i][0]+": "+value, width)
+			lines = append(lines, highlight.Render(padANSIToWidth(line, width)))
+			continue
+		}
+		lines = append(lines, keyStyle.Render(rows[i][0]+":")+" "+value)
+	}
+	return lines
+}
+
+// renderCompactFooter returns the short footer of the compact layout, with
+// the keys for help and quit in the current key mode.
+func renderCompactFooter(noColor bool, keyMode KeyMode) string {
+	keyStyle := lipgloss.NewStyle()
+	if !noColor {
+		th := CurrentTheme()
+		keyStyle = keyStyle.Foreground(th.FooterFG).Background(th.FooterBG).Bold(true)
+	}
+	var parts []string
+	for _, menu := range []MenuConfig{CurrentMenuConfig(), DefaultMenuConfig()} {
+		for _, name := range []string{"help", "quit"} {
+			item, ok := menu.Items[name]
+			if !ok || !item.Enabled {
+				continue
+			}
+			var key string
+			switch keyMode {
+			case KeyModeVim:
+				key = item.Keys.Vim
+			case KeyModeEmacs:
+				key = formatEmacsKey(item.Keys.Emacs)
+			case KeyModeFunction:
+				key = strings.ToUpper(item.Keys.Function)
+			}
+			if key != "" {
+				parts = append(parts, keyStyle.Render(key)+" "+name)
+			}
+		}
+		if len(parts) > 0 {
+			break
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// renderTooSmall returns the notice shown when the window cannot fit even
+// the compact layout: the longest wording that fits, centered.
+func renderTooSmall(width, height int) string {
+	width, height = max(width, 1), max(height, 1)
+	var lines []string
+	for _, msg := range []string{"Terminal too small", "Too small", "Small", "!"} {
+		if textwidth.Width(msg) <= width {
+			lines = append(lines, msg)
+			break
+		}
+	}
+	need := fmt.Sprintf("need %dx%d", TooSmallWidth, TooSmallHeight)
+	if height > 1 && textwidth.Width(need) <= width {
+		lines = append(lines, need)
+	}
+	for i, line := range lines {
+		lines[i] = lipgloss.PlaceHorizontal(width, lipgloss.Center, line)
+	}
+	top := (height - len(lines)) / 2
+	return strings.Repeat("\n", top) + strings.Join(lines, "\n")
+}
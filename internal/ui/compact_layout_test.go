@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertFitsWindow(t *testing.T, out string, width, height int) {
+	t.Helper()
+	lines := strings.Split(out, "\n")
+	assert.LessOrEqual(t, len(lines), height, "too many lines for %dx%d:\n%s", width, height, out)
+	for _, line := range lines {
+		assert.LessOrEqual(t, visibleWidth(line), width, "line too wide for %dx%d: %q", width, height, line)
+	}
+}
+
+func TestView_FitsSmallWindows(t *testing.T) {
+	data := map[string]interface{}{
+		"alpha": "one",
+		"beta":  map[string]interface{}{"x": 1},
+		"gamma": strings.Repeat("long value ", 10),
+	}
+	setups := map[string]func(m *Model){
+		"table": func(*Model) {},
+		"help":  func(m *Model) { m.HelpVisible = true },
+		"input": func(m *Model) { m.InputFocused = true },
+	}
+	for name, setup := range setups {
+		for width := 1; width <= CompactLayoutWidth+2; width += 3 {
+			for height := 1; height <= CompactLayoutHeight+2; height++ {
+				m := InitialModel(data)
+				m.NoColor = true
+				setup(&m)
+				require.NotPanics(t, func() {
+					m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+				}, name)
+				var out string
+				require.NotPanics(t, func() { out = m.View().Content }, name)
+				assertFitsWindow(t, out, width, height)
+			}
+		}
+	}
+}
+
+func TestView_CompactLayout(t *testing.T) {
+	m := InitialModel(map[string]interface{}{"alpha": "one", "beta": "two"})
+	m.NoColor = true
+	m.Update(tea.WindowSizeMsg{Width: 30, Height: 8})
+
+	out := m.View().Content
+	assertFitsWindow(t, out, 30, 8)
+	assert.NotContains(t, out, "─", "compact layout has no borders")
+	assert.Contains(t, out, "alpha: one")
+	assert.Contains(t, out, "beta: two")
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "? help  q quit", strings.TrimSpace(lines[len(lines)-1]))
+}
+
+func TestView_CompactLayoutKeepsSelectionVisible(t *testing.T) {
+	data := map[string]interface{}{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		data[k] = k + k
+	}
+	m := InitialModel(data)
+	m.NoColor = true
+	m.Update(tea.WindowSizeMsg{Width: 30, Height: 5})
+	m.Tbl.SetCursor(7)
+
+	out := m.View().Content
+	assertFitsWindow(t, out, 30, 5)
+	assert.Contains(t, stripANSI(out), "h: hh")
+	assert.Contains(t, out, "\x1b[7m", "the selected row stays highlighted")
+}
+
+func TestView_TerminalTooSmall(t *testing.T) {
+	m := InitialModel(map[string]interface{}{"alpha": "one"})
+	m.NoColor = true
+	m.Update(tea.WindowSizeMsg{Width: 12, Height: 2})
+
+	out := m.View().Content
+	assertFitsWindow(t, out, 12, 2)
+	assert.Contains(t, out, "Too small")
+	assert.NotContains(t, out, "alpha")
+}
+
+func TestView_PanelLayoutAboveCompactSize(t *testing.T) {
+	m := InitialModel(map[string]interface{}{"alpha": "one"})
+	m.NoColor = true
+	m.Update(tea.WindowSizeMsg{Width: CompactLayoutWidth, Height: CompactLayoutHeight})
+
+	out := m.View().Content
+	assertFitsWindow(t, out, CompactLayoutWidth, CompactLayoutHeight)
+	assert.Contains(t, out, "─")
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/completion"
 )
 
 func TestFooterModel_Update(t *testing.T) {
@@ -257,3 +259,54 @@ func (t *testExpressionProvider) DiscoverSuggestions() []string {
 func (t *testExpressionProvider) IsExpression(expr string) bool {
 	return expr == "test"
 }
+
+func TestStatusModel_SignatureHint(t *testing.T) {
+	m := NewStatusModel()
+	m.Width = 80
+	m.InputFocused = true
+	m.FunctionHelpText = "filter help that should be superseded"
+	m.Signature = &completion.SignatureHelp{
+		Function: completion.FunctionMetadata{Name: "filter", Description: "Filter array elements"},
+		Params:   []string{"x", "condition"},
+		ArgIndex: 1,
+	}
+
+	m.NoColor = true
+	view := m.View()
+	if !strings.Contains(view, "filter(x, ‹condition›) — Filter array elements") {
+		t.Errorf("expected signature hint with active parameter marked, got %q", view)
+	}
+
+	m.NoColor = false
+	plain := stripANSI(m.View())
+	if !strings.Contains(plain, "filter(x, condition)") {
+		t.Errorf("expected signature hint in colored status, got %q", plain)
+	}
+	if strings.ContainsRune(plain, sigParamStart) || strings.ContainsRune(plain, sigParamEnd) {
+		t.Errorf("sentinels must not leak into rendered status: %q", plain)
+	}
+}
+
+func TestTruncateSignatureAware_NarrowWidths(t *testing.T) {
+	msg := "filter(x, " + string(sigParamStart) + "condition" + string(sigParamEnd) + ") — Filter array elements"
+	for _, noColor := range []bool{false, true} {
+		for width := 0; width <= 3; width++ {
+			got := truncateSignatureAware(msg, width, noColor)
+			if w := signatureWidth(got, noColor); w > width {
+				t.Errorf("noColor=%v width=%d: %q is %d cells wide", noColor, width, got, w)
+			}
+		}
+		if got := truncateSignatureAware(msg, -1, noColor); got != "" {
+			t.Errorf("negative width should give an empty string, got %q", got)
+		}
+	}
+
+	// Wide characters are cut by cells, and an opened sentinel is closed.
+	got := truncateSignatureAware("日本語"+string(sigParamStart)+"パラメータ"+string(sigParamEnd), 12, true)
+	if w := signatureWidth(got, true); w > 12 {
+		t.Errorf("%q is %d cells wide, want at most 12", got, w)
+	}
+	if strings.Count(got, string(sigParamStart)) != strings.Count(got, string(sigParamEnd)) {
+		t.Errorf("unbalanced sentinels in %q", got)
+	}
+}
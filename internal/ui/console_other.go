@@ -0,0 +1,11 @@
+//go:build !windows
+
+package ui
+
+import "os"
+
+// enableVirtualTerminal is a no-op outside Windows, where terminals interpret
+// escape sequences natively.
+func enableVirtualTerminal(_ *os.File) bool {
+	return true
+}
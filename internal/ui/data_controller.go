@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// dataRetryInterval is how soon data pushed while the user is busy (see
+// refreshBlocked) is tried again.
+const dataRetryInterval = 250 * time.Millisecond
+
+// DataController pushes new data into a running viewer from other
+// goroutines, for embedding apps with their own change detection. The view
+// keeps its path, selected row, and column filters where they still apply,
+// and highlights the rows that changed, as with --refresh. Its methods are
+// safe for concurrent use; only the latest data is shown.
+type DataController struct {
+	mu      sync.Mutex
+	root    any
+	pending bool
+	changed chan struct{} // Wakes the viewer; holds at most one pending signal
+}
+
+// dataChangedMsg is sent when a DataController has new data.
+type dataChangedMsg struct{}
+
+// NewDataController returns a controller for the viewer's data, to pass in
+// Config.DataController.
+func NewDataController() *DataController {
+	return &DataController{changed: make(chan struct{}, 1)}
+}
+
+// SetData replaces the document shown with root. Data set before the
+// viewer starts shows once it does.
+func (c *DataController) SetData(root any) {
+	c.mu.Lock()
+	c.root = root
+	c.pending = true
+	c.mu.Unlock()
+	select {
+	case c.changed <- struct{}{}:
+	default: // A signal is already pending; the viewer takes the latest data
+	}
+}
+
+// take returns the data set since the last take, if any.
+func (c *DataController) take() (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.pending {
+		return nil, false
+	}
+	root := c.root
+	c.root, c.pending = nil, false
+	return root, true
+}
+
+// waitForDataChange returns a tea.Cmd that blocks until c has new data.
+func waitForDataChange(c *DataController) tea.Cmd {
+	return func() tea.Msg {
+		<-c.changed
+		return dataChangedMsg{}
+	}
+}
+
+// handleDataChanged shows the data pushed through DataController and waits
+// for more. While a refresh is blocked, the data stays with the controller
+// and is tried again shortly.
+func (m *Model) handleDataChanged() tea.Cmd {
+	c := m.DataController
+	if c == nil {
+		return nil
+	}
+	if m.refreshBlocked() {
+		return tea.Tick(dataRetryInterval, func(time.Time) tea.Msg { return dataChangedMsg{} })
+	}
+	root, ok := c.take()
+	if !ok {
+		return waitForDataChange(c)
+	}
+	return tea.Batch(m.applyRefresh(root), waitForDataChange(c))
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataController_SetData(t *testing.T) {
+	root := map[string]interface{}{"items": []interface{}{"a", "b"}, "name": "kvx"}
+	m := InitialModel(root)
+	m.Root = root
+	m.DataController = NewDataController()
+	m.NavigateTo(root["items"], "_.items")
+	m.Tbl.SetCursor(1)
+
+	m.DataController.SetData(map[string]interface{}{"items": []interface{}{"a", "b", "c"}, "name": "kvx"})
+	msg := waitForDataChange(m.DataController)()
+	require.IsType(t, dataChangedMsg{}, msg)
+	_, cmd := m.Update(msg)
+	assert.NotNil(t, cmd, "waits for more data")
+	assert.Equal(t, "_.items", m.Path)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, m.Node)
+	assert.Equal(t, 1, m.Tbl.Cursor())
+	assert.Contains(t, m.ChangedRows, "_.items[2]")
+
+	_, ok := m.DataController.take()
+	assert.False(t, ok, "the data is taken once")
+}
+
+func TestDataController_Blocked(t *testing.T) {
+	m := InitialModel(map[string]interface{}{"n": 1})
+	m.DataController = NewDataController()
+	m.DataController.SetData(map[string]interface{}{"n": 2})
+	m.HelpVisible = true
+	assert.NotNil(t, m.handleDataChanged(), "tries again")
+	assert.Equal(t, map[string]interface{}{"n": 1}, m.Node)
+
+	m.HelpVisible = false
+	m.handleDataChanged()
+	assert.Equal(t, map[string]interface{}{"n": 2}, m.Node, "the data waited for the overlay to close")
+}
+
+func TestDataController_ConcurrentSetData(t *testing.T) {
+	c := NewDataController()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() { c.SetData(i) })
+	}
+	wg.Wait()
+	root, ok := c.take()
+	require.True(t, ok)
+	assert.IsType(t, 0, root, "the latest data wins")
+}
@@ -2,10 +2,11 @@ package ui
 
 import (
 	"fmt"
-	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // DebugModel represents the debug bar component
@@ -73,13 +74,7 @@ func (m *DebugModel) UpdateDebugInfo(stateKey string, debugInfo DebugInfo) {
 		if m.Width > 0 {
 			target = m.Width
 		}
-		padded := message
-		if len(padded) > target {
-			padded = padded[:target-3] + "..."
-		}
-		if len(padded) < target {
-			padded += strings.Repeat(" ", target-len(padded))
-		}
+		padded := textwidth.PadRight(textwidth.Truncate(message, target, "..."), target)
 
 		m.LastDebugOutput = debugStyle.Render(padded) + "\n"
 		m.LastDebugValues = stateKey
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// deltaHighlightDuration is how long a row changed by a refresh stays
+// highlighted, like watch -d.
+const deltaHighlightDuration = 3 * time.Second
+
+// deltaFadeMsg is sent when highlighted changes may have faded.
+type deltaFadeMsg struct{}
+
+// rowValues returns the value shown in each row of node, by key.
+func rowValues(node interface{}) map[string]string {
+	rows := navigator.NodeToRows(node)
+	values := make(map[string]string, len(rows))
+	for _, r := range rows {
+		if len(r) > 1 {
+			values[r[0]] = r[1]
+		}
+	}
+	return values
+}
+
+// noteChanges highlights the rows of the current node whose value differs
+// from before, rows that were not there included, and returns the tea.Cmd
+// that fades them, or nil when nothing changed. Deterministic runs highlight
+// nothing.
+func (m *Model) noteChanges(before map[string]string) tea.Cmd {
+	if m.Deterministic {
+		return nil
+	}
+	now := time.Now()
+	changed := false
+	for key, value := range rowValues(m.Node) {
+		if old, ok := before[key]; ok && old == value {
+			continue
+		}
+		if m.ChangedRows == nil {
+			m.ChangedRows = map[string]time.Time{}
+		}
+		m.ChangedRows[formatPathForDisplay(buildPathWithKey(m.Path, key))] = now
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return tea.Tick(deltaHighlightDuration, func(time.Time) tea.Msg { return deltaFadeMsg{} })
+}
+
+// handleDeltaFade forgets the changes that have faded. Changes from a later
+// refresh fade on their own tick.
+func (m *Model) handleDeltaFade() {
+	for path, at := range m.ChangedRows {
+		if time.Since(at) >= deltaHighlightDuration {
+			delete(m.ChangedRows, path)
+		}
+	}
+}
+
+// changedRowMarks reports which rows of the table changed in a recent
+// refresh, or returns nil when none did.
+func (m *Model) changedRowMarks(displayNode interface{}, columnar *ColumnarPanel) []bool {
+	if len(m.ChangedRows) == 0 {
+		return nil
+	}
+	return m.rowMarks(displayNode, columnar, func(path string) bool {
+		at, ok := m.ChangedRows[path]
+		return ok && time.Since(at) < deltaHighlightDuration
+	})
+}
+
+// highlightChangedRows styles the body lines of the changed rows of a
+// rendered table. spans holds the body lines of each row when rows may span
+// several; nil means one line per row.
+func highlightChangedRows(table string, changed []bool, spans [][2]int, noColor bool) string {
+	if len(changed) == 0 {
+		return table
+	}
+	style := lipgloss.NewStyle().Bold(true)
+	if !noColor {
+		style = style.Foreground(CurrentTheme().StatusSuccess)
+	}
+	trailing := strings.HasSuffix(table, "\n")
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+	for row, on := range changed {
+		if !on {
+			continue
+		}
+		start, end := row, row+1
+		if spans != nil {
+			if row >= len(spans) {
+				continue
+			}
+			start, end = spans[row][0], spans[row][1]
+		}
+		// Body lines start below the header and separator.
+		for l := start + 2; l < end+2 && l < len(lines); l++ {
+			lines[l] = style.Render(ansiRegexp.ReplaceAllString(lines[l], ""))
+		}
+	}
+	out := strings.Join(lines, "\n")
+	if trailing {
+		out += "\n"
+	}
+	return out
+}
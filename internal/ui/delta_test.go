@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRefresh_HighlightsChanges(t *testing.T) {
+	m := refreshModel(map[string]interface{}{"status": "pending", "name": "job"})
+	cmd := m.applyRefresh(map[string]interface{}{"status": "done", "name": "job", "took": "3s"})
+	require.NotNil(t, cmd, "the highlight fades")
+	assert.Contains(t, m.ChangedRows, "_.status")
+	assert.Contains(t, m.ChangedRows, "_.took", "new rows count as changed")
+	assert.NotContains(t, m.ChangedRows, "_.name")
+	assert.Equal(t, []bool{false, true, true}, m.changedRowMarks(m.Node, nil), "rows are name, status, took")
+
+	assert.Nil(t, m.applyRefresh(map[string]interface{}{"status": "done", "name": "job", "took": "3s"}), "nothing changed")
+
+	m.ChangedRows["_.status"] = time.Now().Add(-deltaHighlightDuration)
+	m.handleDeltaFade()
+	assert.NotContains(t, m.ChangedRows, "_.status", "faded")
+	assert.Contains(t, m.ChangedRows, "_.took")
+}
+
+func TestHighlightChangedRows(t *testing.T) {
+	table := "KEY VALUE\n---------\na   1\nb   2\n"
+	assert.Equal(t, table, highlightChangedRows(table, nil, nil, true))
+
+	out := highlightChangedRows(table, []bool{false, true}, nil, true)
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "a   1", lines[2])
+	assert.NotEqual(t, "b   2", lines[3], "styled")
+	assert.Equal(t, "b   2", ansiRegexp.ReplaceAllString(lines[3], ""))
+	assert.True(t, strings.HasSuffix(out, "\n"))
+}
@@ -8,9 +8,9 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // DetailViewModel holds state for the sectioned detail rendering of a single object.
@@ -21,6 +21,7 @@ type DetailViewModel struct {
 	ScrollTop int                    // First visible line
 	Width     int                    // Available width
 	Height    int                    // Available height
+	Schema    *DisplaySchema         // Back-reference for reflowing on resize
 }
 
 // renderedSection is a pre-computed section of the detail view.
@@ -47,6 +48,7 @@ func buildDetailViewModel(node interface{}, schema *DisplaySchema, width, height
 		Object: obj,
 		Width:  width,
 		Height: height,
+		Schema: schema,
 	}
 
 	// Resolve title
@@ -79,7 +81,7 @@ func buildDetailViewModel(node interface{}, schema *DisplaySchema, width, height
 
 	// Render explicit sections
 	for _, s := range schema.Detail.Sections {
-		rs := renderDetailSection(obj, s, contentWidth, hiddenSet)
+		rs := renderDetailSection(obj, s, contentWidth, hiddenSet, schema.Detail)
 		if len(rs.Lines) > 0 {
 			dv.Sections = append(dv.Sections, rs)
 		}
@@ -98,7 +100,7 @@ func buildDetailViewModel(node interface{}, schema *DisplaySchema, width, height
 			Fields: otherFields,
 			Layout: DisplayLayoutTable,
 		}
-		rs := renderDetailSection(obj, other, contentWidth, hiddenSet)
+		rs := renderDetailSection(obj, other, contentWidth, hiddenSet, schema.Detail)
 		if len(rs.Lines) > 0 {
 			dv.Sections = append(dv.Sections, rs)
 		}
@@ -107,8 +109,9 @@ func buildDetailViewModel(node interface{}, schema *DisplaySchema, width, height
 	return dv
 }
 
-// renderDetailSection renders a single section of the detail view.
-func renderDetailSection(obj map[string]interface{}, section DetailSection, width int, hidden map[string]bool) renderedSection {
+// renderDetailSection renders a single section of the detail view, with
+// the labels and descriptions of detail in table sections.
+func renderDetailSection(obj map[string]interface{}, section DetailSection, width int, hidden map[string]bool, detail *DetailDisplayConfig) renderedSection {
 	rs := renderedSection{
 		Title:  section.Title,
 		Layout: section.Layout,
@@ -127,7 +130,7 @@ func renderDetailSection(obj map[string]interface{}, section DetailSection, widt
 	case DisplayLayoutTags:
 		rs.Lines = renderTagsSection(obj, section.Fields, width, hidden)
 	default: // table
-		rs.Lines = renderTableSection(obj, section.Fields, width, hidden, section.ColumnOrder)
+		rs.Lines = renderTableSection(obj, section.Fields, width, hidden, section.ColumnOrder, detail.Labels, detail.Descriptions)
 	}
 
 	return rs
@@ -154,8 +157,8 @@ func renderInlineSection(obj map[string]interface{}, fields []string, width int,
 		return nil
 	}
 	line := strings.Join(parts, " · ")
-	if runewidth.StringWidth(line) > width {
-		line = runewidth.Truncate(line, width-3, "...")
+	if textwidth.Width(line) > width {
+		line = textwidth.Truncate(line, width-3, formatter.Ellipsis())
 	}
 	return []string{line}
 }
@@ -230,7 +233,7 @@ func renderTagsSection(obj map[string]interface{}, fields []string, width int, h
 	currentLine := ""
 	currentWidth := 0
 	for _, badge := range badges {
-		bw := runewidth.StringWidth(stripANSI(badge))
+		bw := textwidth.Width(stripANSI(badge))
 		spaceNeeded := bw
 		if currentWidth > 0 {
 			spaceNeeded++ // space separator
@@ -254,13 +257,21 @@ func renderTagsSection(obj map[string]interface{}, fields []string, width int, h
 	return lines
 }
 
-// renderTableSection renders fields as KEY/VALUE rows.
+// renderTableSection renders fields as KEY/VALUE rows, keyed by their
+// labels when set, with their descriptions dimmed under them.
 // When a field contains []map[string]any, it renders an inline columnar table
 // instead of the placeholder "[N items]" text.
-func renderTableSection(obj map[string]interface{}, fields []string, width int, hidden map[string]bool, columnOrder []string) []string {
+func renderTableSection(obj map[string]interface{}, fields []string, width int, hidden map[string]bool, columnOrder []string, labels, descriptions map[string]string) []string {
 	th := CurrentTheme()
 	keyStyle := lipgloss.NewStyle().Foreground(th.KeyColor)
 	valStyle := lipgloss.NewStyle().Foreground(th.ValueColor)
+	descStyle := lipgloss.NewStyle().Faint(true)
+	label := func(f string) string {
+		if l := labels[f]; l != "" {
+			return l
+		}
+		return f
+	}
 
 	// Find longest key for alignment
 	maxKeyLen := 0
@@ -268,8 +279,8 @@ func renderTableSection(obj map[string]interface{}, fields []string, width int,
 		if hidden[f] {
 			continue
 		}
-		if len(f) > maxKeyLen {
-			maxKeyLen = len(f)
+		if w := textwidth.Width(label(f)); w > maxKeyLen {
+			maxKeyLen = w
 		}
 	}
 	if maxKeyLen > width/3 {
@@ -293,16 +304,22 @@ func renderTableSection(obj map[string]interface{}, fields []string, width int,
 			continue
 		}
 
-		key := f
-		if len(key) > maxKeyLen {
-			key = runewidth.Truncate(key, maxKeyLen, "...")
+		key := label(f)
+		if textwidth.Width(key) > maxKeyLen {
+			key = formatter.TruncateCell(key, maxKeyLen)
 		}
 		// Pad key to alignment width
-		key += strings.Repeat(" ", maxKeyLen-runewidth.StringWidth(key))
+		key += strings.Repeat(" ", maxKeyLen-textwidth.Width(key))
 
 		val := stringifyValue(v, width-maxKeyLen-3)
 		line := keyStyle.Render(key) + "  " + valStyle.Render(val)
 		lines = append(lines, line)
+		if desc := strings.TrimSpace(descriptions[f]); desc != "" {
+			indent := strings.Repeat(" ", maxKeyLen+2)
+			for _, l := range textwidth.Wrap(desc, max(width-maxKeyLen-2, 10)) {
+				lines = append(lines, indent+descStyle.Render(l))
+			}
+		}
 	}
 	return lines
 }
@@ -319,22 +336,51 @@ func stringifyValue(v interface{}, maxWidth int) string {
 			parts = append(parts, formatter.Stringify(elem))
 		}
 		s := "[" + strings.Join(parts, ", ") + "]"
-		if runewidth.StringWidth(s) > maxWidth {
-			s = runewidth.Truncate(s, maxWidth-3, "") + "..."
-		}
+		s = formatter.TruncateCell(s, maxWidth)
 		return s
 	case map[string]interface{}:
 		s := fmt.Sprintf("{%d keys}", len(val))
 		return s
 	default:
 		s := formatter.Stringify(v)
-		if runewidth.StringWidth(s) > maxWidth {
-			s = runewidth.Truncate(s, maxWidth-3, "") + "..."
-		}
+		s = formatter.TruncateCell(s, maxWidth)
 		return s
 	}
 }
 
+// lineCount returns the number of lines renderDetailView lays out before
+// scrolling: a blank line and an optional title per section, then its content.
+func (dv *DetailViewModel) lineCount() int {
+	n := 0
+	for _, sec := range dv.Sections {
+		n++
+		if sec.Title != "" {
+			n++
+		}
+		n += len(sec.Lines)
+	}
+	return n
+}
+
+// reflow rebuilds the sections for a new window size. Sections are wrapped
+// when they are built, so a resize has to lay them out again. ScrollTop keeps
+// its relative position in the content.
+func (dv *DetailViewModel) reflow(width, height int) {
+	rebuilt := buildDetailViewModel(dv.Object, dv.Schema, width, height)
+	if rebuilt == nil {
+		dv.Width = width
+		dv.Height = height
+		return
+	}
+	oldTotal := dv.lineCount()
+	dv.Sections = rebuilt.Sections
+	dv.Width = width
+	dv.Height = height
+	if oldTotal > 0 {
+		dv.ScrollTop = dv.ScrollTop * dv.lineCount() / oldTotal
+	}
+}
+
 // renderDetailView renders the full detail view for the panel layout.
 func renderDetailView(dv *DetailViewModel, _ *DisplaySchema, noColor bool) string {
 	if dv == nil {
@@ -490,7 +536,7 @@ func renderInlineColumnarTable(cols []string, rows []map[string]interface{}, wid
 	// Compute column widths from header + data.
 	colWidths := make([]int, len(cols))
 	for i, c := range cols {
-		colWidths[i] = runewidth.StringWidth(c)
+		colWidths[i] = textwidth.Width(c)
 	}
 	cellValues := make([][]string, len(rows))
 	for r, row := range rows {
@@ -501,7 +547,7 @@ func renderInlineColumnarTable(cols []string, rows []map[string]interface{}, wid
 				s = fmt.Sprintf("%v", row[col])
 			}
 			cellValues[r][c] = s
-			if w := runewidth.StringWidth(s); w > colWidths[c] {
+			if w := textwidth.Width(s); w > colWidths[c] {
 				colWidths[c] = w
 			}
 		}
@@ -535,8 +581,8 @@ func renderInlineColumnarTable(cols []string, rows []map[string]interface{}, wid
 	// Render header.
 	var headerParts []string
 	for i, col := range cols {
-		cell := runewidth.Truncate(col, colWidths[i], "...")
-		cell += strings.Repeat(" ", colWidths[i]-runewidth.StringWidth(cell))
+		cell := formatter.TruncateCell(col, colWidths[i])
+		cell += strings.Repeat(" ", colWidths[i]-textwidth.Width(cell))
 		headerParts = append(headerParts, headerStyle.Render(cell))
 	}
 	sep := strings.Repeat(" ", gap)
@@ -546,8 +592,8 @@ func renderInlineColumnarTable(cols []string, rows []map[string]interface{}, wid
 	for _, cells := range cellValues {
 		var parts []string
 		for i, val := range cells {
-			cell := runewidth.Truncate(val, colWidths[i], "...")
-			cell += strings.Repeat(" ", colWidths[i]-runewidth.StringWidth(cell))
+			cell := formatter.TruncateCell(val, colWidths[i])
+			cell += strings.Repeat(" ", colWidths[i]-textwidth.Width(cell))
 			parts = append(parts, cellStyle.Render(cell))
 		}
 		lines = append(lines, strings.Join(parts, sep))
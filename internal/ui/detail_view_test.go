@@ -462,7 +462,7 @@ func TestRenderTableSection_WithNestedTable(t *testing.T) {
 			map[string]interface{}{"name": "home", "status": "inactive"},
 		},
 	}
-	lines := renderTableSection(obj, []string{"profiles"}, 80, map[string]bool{}, []string{"name", "status"})
+	lines := renderTableSection(obj, []string{"profiles"}, 80, map[string]bool{}, []string{"name", "status"}, nil, nil)
 	require.NotEmpty(t, lines)
 	// Should have header + 2 data rows
 	assert.GreaterOrEqual(t, len(lines), 3)
@@ -477,7 +477,7 @@ func TestRenderTableSection_ScalarFields(t *testing.T) {
 		"name":   "test",
 		"status": "ok",
 	}
-	lines := renderTableSection(obj, []string{"name", "status"}, 80, map[string]bool{}, nil)
+	lines := renderTableSection(obj, []string{"name", "status"}, 80, map[string]bool{}, nil, nil, nil)
 	require.Len(t, lines, 2)
 	plain := stripANSI(strings.Join(lines, "\n"))
 	assert.Contains(t, plain, "name")
@@ -0,0 +1,30 @@
+package ui
+
+import "charm.land/bubbles/v2/textinput"
+
+// DeterministicWidth and DeterministicHeight are the terminal size of
+// deterministic runs (--deterministic), in place of the detected size.
+const (
+	DeterministicWidth  = 80
+	DeterministicHeight = 24
+)
+
+// SetDeterministic makes the TUI render the same frames on every run, for
+// snapshot comparisons in CI. The TUI runs at DeterministicWidth x
+// DeterministicHeight unless given a size, the input cursors do not blink,
+// the status spinner stands still, and rows changed by a refresh are not
+// highlighted, since the highlight fades with time.
+func (m *Model) SetDeterministic(on bool) {
+	m.Deterministic = on
+	if !on {
+		return
+	}
+	for _, in := range []*textinput.Model{&m.PathInput, &m.SearchInput, &m.MapFilterInput} {
+		s := in.Styles()
+		s.Cursor.Blink = false
+		in.SetStyles(s)
+	}
+	if m.StatusViewState != nil {
+		m.StatusViewState.Static = true
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDeterministic(t *testing.T) {
+	plain := InitialModel(map[string]interface{}{"a": 1})
+	require.NotNil(t, plain.Init(), "the cursor blinks")
+	plain.SetDeterministic(true)
+	assert.Nil(t, plain.Init(), "nothing ticks")
+
+	m := refreshModel(map[string]interface{}{"status": "pending"})
+	require.True(t, m.PathInput.Styles().Cursor.Blink)
+	m.SetDeterministic(true)
+	assert.False(t, m.PathInput.Styles().Cursor.Blink)
+	assert.False(t, m.SearchInput.Styles().Cursor.Blink)
+	assert.False(t, m.MapFilterInput.Styles().Cursor.Blink)
+
+	assert.Nil(t, m.applyRefresh(map[string]interface{}{"status": "done"}))
+	assert.Empty(t, m.ChangedRows, "changes are not highlighted")
+	assert.Equal(t, "done", m.Node.(map[string]interface{})["status"])
+
+	// Deterministic rendering carries over to the result of an expression.
+	next := InitialModel(m.Root)
+	m.carrySession(&next)
+	assert.True(t, next.Deterministic)
+	assert.False(t, next.PathInput.Styles().Cursor.Blink)
+}
@@ -1,7 +1,10 @@
 package ui
 
-// DisplaySchema controls how the interactive TUI renders arrays of objects.
-// When present, arrays matching the schema render as a scrollable card list
+import "github.com/oakwood-commons/kvx/internal/formatter"
+
+// DisplaySchema controls how the interactive TUI renders collections of
+// objects: arrays, and maps keyed by ID. When present, collections matching
+// the schema render as a scrollable card list
 // (title + subtitle + badges) instead of the default KEY/VALUE table, and
 // drilling into an item shows a sectioned detail view.
 type DisplaySchema struct {
@@ -15,7 +18,7 @@ type DisplaySchema struct {
 	// (e.g., "Providers", "Services").
 	CollectionTitle string `json:"collectionTitle,omitempty"`
 
-	// List configures how an array of objects is rendered as a card list.
+	// List configures how a collection of objects is rendered as a card list.
 	// When nil, the default KEY/VALUE table is used.
 	List *ListDisplayConfig `json:"list,omitempty"`
 
@@ -30,10 +33,13 @@ type DisplaySchema struct {
 	Status *StatusDisplayConfig `json:"status,omitempty"`
 }
 
-// ListDisplayConfig controls the card-list rendering for arrays of objects.
+// ListDisplayConfig controls the card-list rendering for collections of
+// objects. In a map-valued collection the pseudo-field ListKeyField ("$key")
+// names each element's map key in any of the fields.
 type ListDisplayConfig struct {
 	// TitleField is the object key whose value is shown as the card title (bold).
-	// Required for list view activation.
+	// Required for list view activation on arrays; the title of an element of
+	// a map defaults to its key.
 	TitleField string `json:"titleField"`
 
 	// SubtitleField is the object key whose value is shown below the title (dimmed, truncated).
@@ -46,6 +52,12 @@ type ListDisplayConfig struct {
 	// next to or below the title. Array values are expanded into individual badges.
 	BadgeFields []string `json:"badgeFields,omitempty"`
 
+	// BadgeStates maps fields to semantic states ("ok", "info", "warning",
+	// "error") by numeric thresholds or by value. A badge in a state is
+	// drawn in its theme color with its icon (e.g. "✓ running"), and
+	// columnar table cells of the field in its color.
+	BadgeStates map[string]*ValueStates `json:"badgeStates,omitempty"`
+
 	// SecondaryFields lists object keys shown as small metadata below the subtitle.
 	SecondaryFields []string `json:"secondaryFields,omitempty"`
 
@@ -54,6 +66,27 @@ type ListDisplayConfig struct {
 	ArrayStyle string `json:"arrayStyle,omitempty"`
 }
 
+// ValueStates maps the values of a field to semantic states, by numeric
+// thresholds (latency below 100 ok, below 300 warning, else error) or by
+// value ("failed" error).
+type ValueStates = formatter.ValueStates
+
+// StateThreshold maps the numbers below a bound to a state.
+type StateThreshold = formatter.StateThreshold
+
+// stateHints returns column hints carrying the BadgeStates of schema's list
+// config, or nil when it has none.
+func stateHints(schema *DisplaySchema) map[string]formatter.ColumnHint {
+	if schema == nil || schema.List == nil || len(schema.List.BadgeStates) == 0 {
+		return nil
+	}
+	hints := make(map[string]formatter.ColumnHint, len(schema.List.BadgeStates))
+	for field, states := range schema.List.BadgeStates {
+		hints[field] = formatter.ColumnHint{States: states}
+	}
+	return hints
+}
+
 // DetailDisplayConfig controls how a single object is rendered in detail view.
 type DetailDisplayConfig struct {
 	// TitleField is the object key whose value is shown as the detail header.
@@ -66,6 +99,14 @@ type DetailDisplayConfig struct {
 
 	// HiddenFields lists object keys that are excluded from the detail view entirely.
 	HiddenFields []string `json:"hiddenFields,omitempty"`
+
+	// Labels maps object keys to the labels shown in place of them in
+	// table sections, such as a JSON Schema property's title.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Descriptions maps object keys to text shown dimmed under their row
+	// in table sections, such as a JSON Schema property's description.
+	Descriptions map[string]string `json:"descriptions,omitempty"`
 }
 
 // DetailSection defines a group of fields rendered together with a specific layout.
@@ -121,13 +162,37 @@ type StatusDisplayConfig struct {
 
 	// Timeout is a duration string (e.g., "30s", "2m") after which the
 	// screen transitions to success and auto-exits. Ignored when a
-	// programmatic Done channel is provided via Config.Done.
+	// programmatic Done channel or StatusController is provided via
+	// Config.Done or Config.StatusController.
 	Timeout string `json:"timeout,omitempty"`
 
+	// DeadlineField is the data field holding when the wait expires, such as
+	// the TTL of a device code: an RFC 3339 time, or a number of seconds from
+	// when the screen opens (like OAuth's expires_in). While waiting, the
+	// screen shows a live countdown ("Expires in 4:32").
+	DeadlineField string `json:"deadlineField,omitempty"`
+
+	// DeadlineLabel is the text before the countdown (default: "Expires in").
+	DeadlineLabel string `json:"deadlineLabel,omitempty"`
+
+	// ExpiredMessage replaces the countdown once the deadline has passed
+	// (default: "Expired").
+	ExpiredMessage string `json:"expiredMessage,omitempty"`
+
+	// FailOnExpiry ends the wait with ExpiredMessage as an error when the
+	// deadline passes, instead of only marking the countdown expired.
+	FailOnExpiry bool `json:"failOnExpiry,omitempty"`
+
 	// DisplayFields lists data fields to show as labeled values on the status screen
 	// (e.g., a device code or URL the user needs to copy/visit).
 	DisplayFields []StatusFieldDisplay `json:"displayFields,omitempty"`
 
+	// QRField is a data field, such as a device-login URL, also shown as a
+	// QR code to scan with a phone. The code is drawn black on white; with
+	// NoColor, or when the screen is too small for it, the value is shown as
+	// text unless it is one of the DisplayFields.
+	QRField string `json:"qrField,omitempty"`
+
 	// Actions defines interactive hotkey actions available on the status screen.
 	Actions []StatusActionConfig `json:"actions,omitempty"`
 
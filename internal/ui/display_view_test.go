@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	tea "charm.land/bubbletea/v2"
+
 	"github.com/oakwood-commons/kvx/internal/formatter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -73,6 +76,78 @@ func TestBuildListViewModel_BadgeFromArray(t *testing.T) {
 	assert.Contains(t, vm.Items[0].Badges, "aws")
 }
 
+func TestBuildListViewModel_BadgeStates(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"name": "api", "status": "running", "ms": float64(42)},
+		map[string]interface{}{"name": "db", "status": "failed", "ms": float64(900)},
+	}
+	schema := &DisplaySchema{
+		List: &ListDisplayConfig{
+			TitleField:  "name",
+			BadgeFields: []string{"status", "ms", "name"},
+			BadgeStates: map[string]*ValueStates{
+				"status": {Values: map[string]string{"running": "ok", "failed": "error"}},
+				"ms": {
+					Thresholds: []StateThreshold{{Below: 100, State: "ok"}, {Below: 300, State: "warning"}},
+					Else:       "error",
+				},
+			},
+		},
+	}
+	vm := buildListViewModel(data, schema, 80, 24)
+	require.NotNil(t, vm)
+	assert.Equal(t, []string{"ok", "ok", ""}, vm.Items[0].States)
+	assert.Equal(t, []string{"error", "error", ""}, vm.Items[1].States)
+
+	content := renderListView(vm, schema, true)
+	assert.Contains(t, content, " ✓ running   ✓ 42   api ")
+	assert.Contains(t, content, " ✗ failed   ✗ 900   db ")
+}
+
+func TestBuildListViewModel_Map(t *testing.T) {
+	data := map[string]interface{}{
+		"db":  map[string]interface{}{"owner": "team-b", "status": "failed"},
+		"api": map[string]interface{}{"name": "Public API", "owner": "team-a"},
+	}
+	schema := &DisplaySchema{
+		List: &ListDisplayConfig{
+			BadgeFields:     []string{"status"},
+			SecondaryFields: []string{ListKeyField, "owner"},
+		},
+	}
+	vm := buildListViewModel(data, schema, 80, 24)
+	require.NotNil(t, vm)
+	require.Len(t, vm.Items, 2)
+	assert.Equal(t, "api", vm.Items[0].Title, "title defaults to the key")
+	assert.Equal(t, []string{"api", "team-a"}, vm.Items[0].Secondary)
+	assert.Equal(t, "db", vm.Items[1].Key)
+	assert.Equal(t, []string{"failed"}, vm.Items[1].Badges)
+	assert.Contains(t, vm.Items[1].SearchText, "db")
+
+	schema.List.TitleField = "name"
+	vm = buildListViewModel(data, schema, 80, 24)
+	assert.Equal(t, "Public API", vm.Items[0].Title)
+	assert.Equal(t, "db", vm.Items[1].Title, "falls back to the key")
+}
+
+func TestIsListCollection(t *testing.T) {
+	list := &ListDisplayConfig{SubtitleField: "desc"}
+	registry := map[string]interface{}{
+		"a": map[string]interface{}{"desc": "first"},
+		"b": map[string]interface{}{"desc": "second", "extra": 1},
+	}
+	assert.True(t, isListCollection(registry, list))
+	assert.False(t, isListCollection(map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 2},
+		"meta": map[string]interface{}{"desc": "x"},
+	}, list), "a value without any list field")
+	assert.False(t, isListCollection(map[string]interface{}{"a": "scalar"}, list))
+	assert.False(t, isListCollection(map[string]interface{}{}, list))
+	assert.False(t, isListCollection([]interface{}{map[string]interface{}{"desc": "x"}}, list), "arrays need a title field")
+	assert.True(t, isListCollection(registry, &ListDisplayConfig{}))
+	assert.False(t, isListCollection(registry, nil))
+}
+
 func TestBuildListViewModel_SecondaryFields(t *testing.T) {
 	data := []interface{}{
 		map[string]interface{}{
@@ -490,3 +565,90 @@ func TestListViewKey_FunctionMode_LettersFilter(t *testing.T) {
 	assert.True(t, handled)
 	assert.Equal(t, 1, m.ListViewState.Selected)
 }
+
+func TestListViewKey_DrillIntoMapElement(t *testing.T) {
+	data := map[string]interface{}{
+		"api": map[string]interface{}{"owner": "team-a"},
+		"db":  map[string]interface{}{"owner": "team-b"},
+	}
+	m := InitialModel(data)
+	m.Root = data
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.DisplaySchema = &DisplaySchema{List: &ListDisplayConfig{SecondaryFields: []string{"owner"}}}
+	m.updateViewMode(data)
+	require.Equal(t, "list", m.ViewMode)
+	assert.Equal(t, "_.api", m.selectedPickPath())
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	assert.Equal(t, "_.db", m.selectedPickPath())
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, "_.db", m.Path)
+	assert.Equal(t, data["db"], m.Node)
+}
+
+// ---------------------------------------------------------------------------
+// reflow on resize
+// ---------------------------------------------------------------------------
+
+func TestWindowResize_ReflowsDetailView(t *testing.T) {
+	obj := map[string]interface{}{
+		"name": "svc",
+		"desc": strings.Repeat("lorem ipsum dolor sit amet ", 20),
+	}
+	schema := &DisplaySchema{
+		Detail: &DetailDisplayConfig{
+			TitleField: "name",
+			Sections: []DetailSection{
+				{Title: "About", Fields: []string{"desc"}, Layout: "paragraph"},
+			},
+		},
+	}
+	m := InitialModel(obj)
+	m.DisplaySchema = schema
+	m.ViewMode = "detail"
+	m.WinWidth = 40
+	m.WinHeight = 10
+	m.updateViewMode(obj)
+	require.NotNil(t, m.DetailViewState)
+
+	narrow := m.DetailViewState.lineCount()
+	m.DetailViewState.ScrollTop = narrow / 2
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+
+	dv := m.DetailViewState
+	wide := dv.lineCount()
+	assert.Less(t, wide, narrow, "a wider window should rewrap the paragraph into fewer lines")
+	for _, line := range dv.Sections[0].Lines {
+		assert.LessOrEqual(t, visibleWidth(line), 120)
+	}
+	assert.Equal(t, (narrow/2)*wide/narrow, dv.ScrollTop, "scroll position should stay proportional")
+}
+
+func TestListViewReflow_KeepsSelectionOffsetProportional(t *testing.T) {
+	lv := &ListViewModel{Selected: 15, ScrollTop: 10}
+	lv.reflow(10, 20)
+	assert.Equal(t, 5, lv.ScrollTop)
+
+	lv = &ListViewModel{Selected: 15, ScrollTop: 5}
+	lv.reflow(20, 10)
+	assert.Equal(t, 10, lv.ScrollTop)
+}
+
+func TestRenderListView_FillsPanelAfterGrowing(t *testing.T) {
+	data := make([]interface{}, 6)
+	for i := range data {
+		data[i] = map[string]interface{}{"name": fmt.Sprintf("item-%d", i)}
+	}
+	schema := &DisplaySchema{List: &ListDisplayConfig{TitleField: "name"}}
+	lv := buildListViewModel(data, schema, 60, 40)
+	require.NotNil(t, lv)
+	lv.Selected = 5
+	lv.ScrollTop = 4
+
+	out := lv.Render(60, 40, true)
+	assert.Equal(t, 0, lv.ScrollTop, "all items fit, so none should stay scrolled off")
+	assert.Contains(t, out, "item-0")
+}
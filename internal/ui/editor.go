@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+)
+
+// editorFinishedMsg is sent when the editor launched by the edit action exits.
+type editorFinishedMsg struct {
+	err error
+}
+
+// selectedSourceKey returns the key under which positions and comments of
+// the selected row's value are recorded in m.Node.
+func (m *Model) selectedSourceKey() (string, bool) {
+	key, ok := m.selectedRowKey()
+	if !ok || key == navigator.ScalarValueKey {
+		return "", false
+	}
+	if _, isArr := m.Node.([]interface{}); isArr {
+		key = strings.TrimSuffix(strings.TrimPrefix(key, "["), "]")
+	}
+	return key, true
+}
+
+// selectedSourcePosition returns where the selected row's value was written
+// in the loaded input, when positions were recorded for it.
+func (m *Model) selectedSourcePosition() (sourcepos.Position, bool) {
+	key, ok := m.selectedSourceKey()
+	if !ok {
+		return sourcepos.Position{}, false
+	}
+	return m.Positions.Lookup(m.Node, key)
+}
+
+// sourceInfoForSelectedRow returns the status bar label for the selected
+// row: its source position and the comment written with it, e.g.
+// "data.yaml:142  # retries before giving up". Comments are flattened to one
+// line and cut to the window width. Returns "" when nothing is recorded.
+func (m *Model) sourceInfoForSelectedRow() string {
+	if m.InputFocused || m.AdvancedSearchActive || m.HelpVisible {
+		return ""
+	}
+	key, ok := m.selectedSourceKey()
+	if !ok {
+		return ""
+	}
+	var parts []string
+	if pos, ok := m.Positions.Lookup(m.Node, key); ok {
+		if pos.File != "" {
+			pos.File = filepath.Base(pos.File)
+		}
+		parts = append(parts, pos.String())
+	}
+	if c, ok := m.Positions.Comment(m.Node, key); ok {
+		parts = append(parts, "# "+strings.Join(strings.Fields(c), " "))
+	}
+	info := strings.Join(parts, "  ")
+	if m.WinWidth > 0 {
+		info = textwidth.Truncate(info, m.WinWidth, "…")
+	}
+	return info
+}
+
+// vimOpenInEditor opens the selected node in the editor (same as F2).
+func (m *Model) vimOpenInEditor() (tea.Model, tea.Cmd) {
+	return m, menuActionEdit(m)
+}
+
+// menuActionEdit opens the input file at the selected node's line in
+// $VISUAL or $EDITOR, suspending the UI until the editor exits.
+func menuActionEdit(m *Model) tea.Cmd {
+	if m.kioskRefuses("Editing") {
+		return nil
+	}
+	pos, ok := m.selectedSourcePosition()
+	if !ok || pos.File == "" {
+		m.ErrMsg = "No source file position for the selected node"
+		m.StatusType = "error"
+		return nil
+	}
+	cmd := editorCommand(preferredEditor(), pos)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// preferredEditor returns the user's editor command line, falling back to vi.
+func preferredEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
+
+// editorCommand builds the command that opens pos in editor, an editor
+// command line such as "vim" or "code --wait". Editors that take a
+// file:line:column argument get one; everything else gets the "+line file"
+// form understood by vi, emacs, nano and most terminal editors.
+func editorCommand(editor string, pos sourcepos.Position) *exec.Cmd {
+	fields := splitCommandLine(editor)
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+	line := strconv.Itoa(pos.Line)
+	col := strconv.Itoa(max(pos.Column, 1))
+	args := fields[1:]
+	switch strings.TrimSuffix(strings.ToLower(filepath.Base(fields[0])), ".exe") {
+	case "code", "code-insiders", "codium", "cursor":
+		args = append(args, "--goto", pos.File+":"+line+":"+col)
+	case "subl", "hx", "helix", "zed", "micro":
+		args = append(args, pos.File+":"+line+":"+col)
+	case "notepad++":
+		args = append(args, "-n"+line, "-c"+col, pos.File)
+	case "notepad":
+		// Notepad cannot jump to a line.
+		args = append(args, pos.File)
+	default:
+		args = append(args, "+"+line, pos.File)
+	}
+	return exec.Command(fields[0], args...) //nolint:gosec // the editor is chosen by the user
+}
+
+// splitCommandLine splits an editor command line into fields. Double or
+// single quotes group a field containing spaces, and a command line that
+// names an existing file is kept whole, so Windows paths such as
+// C:\Program Files\Notepad++\notepad++.exe work quoted or not. Backslashes
+// are not escape characters.
+func splitCommandLine(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if st, err := os.Stat(s); err == nil && !st.IsDir() {
+		return []string{s}
+	}
+	var fields []string
+	var cur strings.Builder
+	var quote rune
+	inField := false
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, cur.String())
+				cur.Reset()
+				inField = false
+			}
+		default:
+			cur.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
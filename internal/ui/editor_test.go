@@ -0,0 +1,127 @@
+//nolint:forcetypeassert
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEditorCommand(t *testing.T) {
+	pos := sourcepos.Position{File: "data.yaml", Line: 42, Column: 3}
+
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"vim", []string{"vim", "+42", "data.yaml"}},
+		{"emacs -nw", []string{"emacs", "-nw", "+42", "data.yaml"}},
+		{"code --wait", []string{"code", "--wait", "--goto", "data.yaml:42:3"}},
+		{"/usr/local/bin/hx", []string{"/usr/local/bin/hx", "data.yaml:42:3"}},
+		{`"/opt/my editors/hx"`, []string{"/opt/my editors/hx", "data.yaml:42:3"}},
+		{"notepad++.exe", []string{"notepad++.exe", "-n42", "-c3", "data.yaml"}},
+		{"NOTEPAD.EXE", []string{"NOTEPAD.EXE", "data.yaml"}},
+		{"", []string{"vi", "+42", "data.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.editor, func(t *testing.T) {
+			assert.Equal(t, tt.want, editorCommand(tt.editor, pos).Args)
+		})
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	assert.Equal(t, []string{`C:\Program Files\Notepad++\notepad++.exe`, "-multiInst"},
+		splitCommandLine(`"C:\Program Files\Notepad++\notepad++.exe" -multiInst`))
+	assert.Equal(t, []string{"emacs", "-nw"}, splitCommandLine("  emacs   -nw "))
+	assert.Equal(t, []string{"sh", "-c", "vim -u NONE"}, splitCommandLine(`sh -c 'vim -u NONE'`))
+	assert.Nil(t, splitCommandLine("   "))
+
+	dir := filepath.Join(t.TempDir(), "My Editor")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	exe := filepath.Join(dir, "edit")
+	require.NoError(t, os.WriteFile(exe, nil, 0o600))
+	assert.Equal(t, []string{exe}, splitCommandLine(exe), "an unquoted path with spaces is kept whole")
+}
+
+func TestPreferredEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	assert.Equal(t, "nano", preferredEditor())
+	t.Setenv("VISUAL", "code --wait")
+	assert.Equal(t, "code --wait", preferredEditor())
+}
+
+func sourcePosModel(t *testing.T) *Model {
+	t.Helper()
+	return sourcePosModelFrom(t, "name: demo\nitems:\n  - a\n  # the second\n  # item\n  - b\n")
+}
+
+func sourcePosModelFrom(t *testing.T, src string) *Model {
+	t.Helper()
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &node))
+	var root map[string]interface{}
+	require.NoError(t, node.Decode(&root))
+	positions := sourcepos.NewIndex("/tmp/configs/data.yaml", 0)
+	positions.RecordYAML(&node, root)
+
+	m := InitialModel(root)
+	m.Root = root
+	m.Positions = positions
+	m.InputFocused = false
+	m.KeyMode = KeyModeVim
+	m.WinWidth = 80
+	m.WinHeight = 20
+	m.applyLayout(true)
+	m.Tbl.Focus()
+	return &m
+}
+
+func TestSourceInfoForSelectedRow(t *testing.T) {
+	m := sourcePosModel(t)
+	moveCursorToKey(t, m, "name")
+	assert.Equal(t, "data.yaml:1", m.sourceInfoForSelectedRow())
+
+	moveCursorToKey(t, m, "items")
+	m = pressKeyRight(m)
+	m.Tbl.SetCursor(1)
+	assert.Equal(t, "data.yaml:6  # the second item", m.sourceInfoForSelectedRow())
+
+	m.WinWidth = 20
+	assert.Equal(t, "data.yaml:6  # the …", m.sourceInfoForSelectedRow())
+
+	m.InputFocused = true
+	assert.Equal(t, "", m.sourceInfoForSelectedRow(), "hidden while typing an expression")
+}
+
+func TestSourceInfoForSelectedRow_WideComment(t *testing.T) {
+	m := sourcePosModelFrom(t, "name: demo # 名前👩‍💻 note\n")
+	moveCursorToKey(t, m, "name")
+
+	// The emoji ZWJ sequence is one 2-cell cluster and is never split.
+	m.WinWidth = 22
+	assert.Equal(t, "data.yaml:1  # 名前👩‍💻…", m.sourceInfoForSelectedRow())
+	m.WinWidth = 21
+	assert.Equal(t, "data.yaml:1  # 名前…", m.sourceInfoForSelectedRow())
+	assert.LessOrEqual(t, textwidth.Width(m.sourceInfoForSelectedRow()), m.WinWidth)
+}
+
+func TestOpenInEditorWithoutFile(t *testing.T) {
+	m := sourcePosModel(t)
+	m.Node = map[string]interface{}{"other": 1}
+	m.applyLayout(true)
+
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	m = result.(*Model)
+	assert.Nil(t, cmd)
+	assert.Equal(t, "error", m.StatusType)
+	assert.Contains(t, m.ErrMsg, "No source file position")
+}
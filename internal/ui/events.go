@@ -0,0 +1,52 @@
+package ui
+
+import "time"
+
+// EventKind says what an Event reports.
+type EventKind string
+
+const (
+	// EventNavigate: the view moved to a path or expression result.
+	EventNavigate EventKind = "navigate"
+	// EventSearch: a deep search (F3, Enter) or "..key" lookup ran.
+	EventSearch EventKind = "search"
+	// EventEvaluate: an expression entered in the expression bar was evaluated.
+	EventEvaluate EventKind = "evaluate"
+)
+
+// Event reports something the user did in the viewer, for embedders that
+// mirror the session into an audit log or a secondary display (OnEvent).
+// Fields that do not apply to the Kind are left empty.
+type Event struct {
+	Kind     EventKind     `json:"kind"`
+	At       time.Time     `json:"at"`
+	Path     string        `json:"path,omitempty"`     // Navigate: where the view moved; Search: the path searched below
+	Expr     string        `json:"expr,omitempty"`     // Evaluate: the expression
+	Query    string        `json:"query,omitempty"`    // Search: the query
+	Results  int           `json:"results,omitempty"`  // Search: how many results were found
+	Limited  bool          `json:"limited,omitempty"`  // Search: the result limit cut the results short
+	Duration time.Duration `json:"duration,omitempty"` // Evaluate: how long the evaluation took
+	Error    string        `json:"error,omitempty"`    // Evaluate: why the expression failed
+}
+
+// emit sends e to OnEvent, stamped with the current time.
+func (m *Model) emit(e Event) {
+	if m.OnEvent == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	m.OnEvent(e)
+}
+
+// noteEval records an expression evaluated from the expression bar in the
+// session stats and reports it to OnEvent.
+func (m *Model) noteEval(expr string, took time.Duration, err error) {
+	m.Stats.noteEval(expr, took, err)
+	e := Event{Kind: EventEvaluate, Expr: expr, Duration: took}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	m.emit(e)
+}
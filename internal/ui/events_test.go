@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnEvent(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	var events []Event
+	m.OnEvent = func(e Event) { events = append(events, e) }
+
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyRight})
+	m = press(m, tea.KeyPressMsg{Code: ':', Text: ":"})
+	m.PathInput.SetValue("_.nope")
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.PathInput.SetValue(`_.b["y"]`)
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	m.AdvancedSearchActive = true
+	m.AdvancedSearchCommitted = true
+	m.AdvancedSearchBasePath = ""
+	m.AdvancedSearchQuery = "x"
+	m.applyAdvancedSearch()
+
+	var kinds []EventKind
+	for _, e := range events {
+		assert.False(t, e.At.IsZero(), "%s event has a time", e.Kind)
+		kinds = append(kinds, e.Kind)
+	}
+	require.Equal(t, []EventKind{EventNavigate, EventNavigate, EventEvaluate, EventEvaluate, EventNavigate, EventSearch}, kinds)
+
+	assert.Equal(t, "_", events[0].Path)
+	assert.Equal(t, "_.a", events[1].Path)
+	assert.Equal(t, "_.nope", events[2].Expr)
+	assert.NotEmpty(t, events[2].Error)
+	assert.Equal(t, `_.b["y"]`, events[3].Expr)
+	assert.Empty(t, events[3].Error)
+	assert.Equal(t, `_.b["y"]`, events[4].Path)
+	assert.Equal(t, Event{Kind: EventSearch, At: events[5].At, Path: "_", Query: "x", Results: 2}, events[5])
+}
+
+func TestEmitWithoutOnEvent(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	assert.NotPanics(t, func() { m.emit(Event{Kind: EventNavigate}) })
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	celhelper "github.com/oakwood-commons/kvx/internal/cel"
+	"github.com/oakwood-commons/kvx/internal/completion"
+	"github.com/oakwood-commons/kvx/internal/jq"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/suggest"
+)
+
+// maxKeySuggestions caps the "did you mean" list for unknown-field errors.
+const maxKeySuggestions = 3
+
+// setStickyExprError reports a failed expression with a friendlier message
+// than the raw evaluator error and remembers where in the input it points,
+// so the status bar can draw a caret under the offending character, and
+// rings the bell when configured. The input must already hold expr.
+func (m *Model) setStickyExprError(expr string, err error) {
+	msg, pos := m.describeExprError(expr, err)
+	m.setStickyError(msg)
+	m.ErrPos = pos
+	m.ringBell(true)
+}
+
+// describeExprError turns an evaluation error for expr into a status message
+// and the rune offset of the offending character (-1 when unknown). Syntax
+// errors point at the parser's error position; unknown fields point at the
+// field name and suggest the closest existing keys. Anything else keeps the
+// original error text, pointing at the failing call when it is known.
+func (m *Model) describeExprError(expr string, err error) (string, int) {
+	var jqSyntax *jq.SyntaxError
+	if errors.As(err, &jqSyntax) {
+		return "Syntax error: " + jqSyntax.Msg, jqSyntax.Pos
+	}
+
+	pos := -1
+	var exprErr *celhelper.ExprError
+	if errors.As(err, &exprErr) {
+		pos = exprErr.Pos
+		if exprErr.Kind == celhelper.KindParse && m.CompletionEngine != nil {
+			if synPos, msg, ok := m.CompletionEngine.SyntaxError(expr); ok {
+				return "Syntax error: " + strings.TrimPrefix(msg, "Syntax error: "), synPos
+			}
+		}
+	}
+
+	if missing, ok := completion.MissingKey(err); ok {
+		keyPos, parent := missing.Pos, missing.Parent
+		if keyPos < 0 {
+			keyPos, parent = completion.LocateKey(expr, missing.Key)
+		}
+		msg := fmt.Sprintf("Path error: unknown field '%s'", missing.Key)
+		if suggestions := suggest.Closest(missing.Key, m.keysForSuggestion(parent), maxKeySuggestions); len(suggestions) > 0 {
+			msg += "; did you mean " + quoteAlternatives(suggestions) + "?"
+		}
+		return msg, keyPos
+	}
+
+	if isJQProgram(expr) {
+		return fmt.Sprintf("jq error: %v", err), -1
+	}
+	return fmt.Sprintf("Path error: %v", err), pos
+}
+
+// keysForSuggestion returns the map keys of the node that parent evaluates
+// to, falling back to the root when parent is empty. Parents that cannot be
+// resolved on their own (e.g. lambda variables inside filter) yield nil.
+func (m *Model) keysForSuggestion(parent string) []string {
+	node := m.Root
+	if p := strings.TrimSpace(parent); p != "" && p != "_" {
+		if isJQProgramPath(p) {
+			p = jqModelPath(p)
+		}
+		resolved, err := navigator.Navigate(m.Root, p)
+		if err != nil {
+			return nil
+		}
+		node = resolved
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// quoteAlternatives formats names as "'a'", "'a' or 'b'", or "'a', 'b' or 'c'".
+func quoteAlternatives(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "'" + n + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+// errorCaretColumn returns the screen column, relative to the start of the
+// input line, under which the status bar should draw the error caret, or 0
+// when no caret applies (no positioned error, the input changed since the
+// error, or the text is too wide to be shown unscrolled).
+func (m *Model) errorCaretColumn(labelWidth int) int {
+	if !m.ErrSticky || m.ErrPos < 0 || !m.InputFocused {
+		return 0
+	}
+	value := m.PathInput.Value()
+	if value != m.ErrStickyInput {
+		return 0
+	}
+	runes := []rune(value)
+	if m.ErrPos > len(runes) || labelWidth+lipgloss.Width(value)+1 > m.WinWidth {
+		return 0
+	}
+	return labelWidth + lipgloss.Width(string(runes[:m.ErrPos]))
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+func TestExprError_UnknownFieldSuggestsKeys(t *testing.T) {
+	root := map[string]interface{}{
+		"name":   "demo",
+		"labels": map[string]interface{}{"app": "web", "tier": "front"},
+	}
+	m := focusedModelWithRoot(root)
+	m.WinWidth = 80
+	m.PathInput.SetValue("_.nmae")
+
+	newModel, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m2 := newModel.(*Model)
+
+	assert.Equal(t, "Path error: unknown field 'nmae'; did you mean 'name'?", m2.ErrMsg)
+	assert.Equal(t, 2, m2.ErrPos)
+	assert.True(t, m2.ErrSticky)
+}
+
+func TestExprError_NestedUnknownFieldUsesParentKeys(t *testing.T) {
+	root := map[string]interface{}{
+		"labels": map[string]interface{}{"app": "web", "tier": "front"},
+	}
+	m := focusedModelWithRoot(root)
+
+	for expr, wantPos := range map[string]int{`_.labels.tire`: 9, `_.labels["tire"]`: 10} {
+		_, err := navigator.Navigate(root, expr)
+		require.Error(t, err, expr)
+		msg, pos := m.describeExprError(expr, err)
+		assert.Equal(t, "Path error: unknown field 'tire'; did you mean 'tier'?", msg, expr)
+		assert.Equal(t, wantPos, pos, expr)
+	}
+
+	// Errors are classified by type, not by their text.
+	msg, pos := m.describeExprError(`_.labels.tire`, errors.New("no such key: tire"))
+	assert.Equal(t, "Path error: no such key: tire", msg)
+	assert.Equal(t, -1, pos)
+}
+
+func TestExprError_SyntaxError(t *testing.T) {
+	m := focusedModelWithRoot(map[string]interface{}{"a": 1})
+
+	_, err := navigator.Navigate(m.Root, "_.a +")
+	require.Error(t, err)
+	msg, pos := m.describeExprError("_.a +", err)
+	assert.True(t, strings.HasPrefix(msg, "Syntax error: "), msg)
+	assert.Equal(t, 5, pos)
+
+	msg, _ = m.describeExprError("_.a +", errors.New("Syntax error: mismatched input"))
+	assert.Equal(t, "Path error: Syntax error: mismatched input", msg)
+}
+
+func TestExprError_OtherErrorsKeepText(t *testing.T) {
+	m := focusedModelWithRoot(map[string]interface{}{"a": 1})
+
+	msg, pos := m.describeExprError("_.a / 0", errors.New("division by zero"))
+	assert.Equal(t, "Path error: division by zero", msg)
+	assert.Equal(t, -1, pos)
+}
+
+func TestExprError_CaretColumn(t *testing.T) {
+	m := focusedModelWithRoot(map[string]interface{}{"name": "demo"})
+	m.WinWidth = 80
+	m.PathInput.SetValue("_.nmae")
+	_, err := navigator.Navigate(m.Root, "_.nmae")
+	require.Error(t, err)
+	m.setStickyExprError("_.nmae", err)
+
+	assert.Equal(t, 4, m.errorCaretColumn(2))
+
+	// Editing the input invalidates the caret.
+	m.PathInput.SetValue("_.nma")
+	assert.Equal(t, 0, m.errorCaretColumn(2))
+}
+
+func TestWithErrorCaret(t *testing.T) {
+	assert.Equal(t, "    ^ bad", withErrorCaret("bad", 4, 20))
+	// Message too long to follow the caret: place it before.
+	assert.Equal(t, "bad      ^", withErrorCaret("bad", 9, 11))
+	// Neither fits: plain message.
+	assert.Equal(t, "a long message", withErrorCaret("a long message", 3, 10))
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/completion"
+)
+
+// exprRuneStyle captures everything that affects how one rune of the
+// expression input is drawn, so runs of identical runes render together.
+type exprRuneStyle struct {
+	kind    completion.TokenKind
+	match   bool // bracket under/next to the cursor, or its partner
+	errMark bool // parse error position or the token an evaluation error points at
+	cursor  bool
+}
+
+// renderHighlightedInput renders the focused expression input with syntax
+// colors, the bracket pair around the cursor highlighted, and the first parse
+// error (or the token a failed evaluation points at) underlined. ok is false
+// when the plain textinput view should be used instead: colors disabled,
+// empty input (placeholder), or text wider than avail.
+func (m *Model) renderHighlightedInput(avail int) (string, bool) {
+	if m.NoColor || !m.InputFocused || m.CompletionEngine == nil {
+		return "", false
+	}
+	value := m.PathInput.Value()
+	runes := []rune(value)
+	if len(runes) == 0 || lipgloss.Width(value)+1 > avail {
+		return "", false
+	}
+
+	pos := m.PathInput.Position()
+	if pos < 0 || pos > len(runes) {
+		pos = len(runes)
+	}
+
+	styles := make([]exprRuneStyle, len(runes)+1)
+	for _, tok := range m.CompletionEngine.Tokenize(value) {
+		for i := tok.Start; i < tok.End && i < len(runes); i++ {
+			styles[i].kind = tok.Kind
+		}
+	}
+	styles[len(runes)].kind = completion.TokenSpace
+
+	// Highlight the bracket at the cursor, or the one just before it.
+	for _, at := range []int{pos, pos - 1} {
+		if partner := completion.MatchingBracket(value, at); partner >= 0 {
+			styles[at].match = true
+			styles[partner].match = true
+			break
+		}
+	}
+
+	// Underline parse errors, but not the end-of-input errors that every
+	// partially typed expression produces.
+	if errPos, _, ok := m.CompletionEngine.SyntaxError(value); ok {
+		if errPos < len([]rune(strings.TrimRight(value, " "))) {
+			styles[errPos].errMark = true
+		}
+	}
+	// Underline the whole token an evaluation error points at (e.g. an unknown field).
+	if m.ErrSticky && m.ErrPos >= 0 && value == m.ErrStickyInput {
+		for _, tok := range m.CompletionEngine.Tokenize(value) {
+			if m.ErrPos >= tok.Start && m.ErrPos < tok.End {
+				for i := tok.Start; i < tok.End; i++ {
+					styles[i].errMark = true
+				}
+				break
+			}
+		}
+	}
+
+	styles[pos].cursor = true
+
+	th := CurrentTheme()
+	var b strings.Builder
+	runStart := 0
+	for i := 1; i <= len(styles); i++ {
+		if i < len(styles) && styles[i] == styles[runStart] {
+			continue
+		}
+		text := " "
+		if runStart < len(runes) {
+			end := i
+			if end > len(runes) {
+				end = len(runes)
+			}
+			text = string(runes[runStart:end])
+		}
+		b.WriteString(exprLipglossStyle(th, styles[runStart]).Render(text))
+		runStart = i
+	}
+	return b.String(), true
+}
+
+// exprLipglossStyle maps a rune style to theme colors.
+func exprLipglossStyle(th Theme, s exprRuneStyle) lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if fg := exprTokenColor(th, s.kind); fg != nil {
+		st = st.Foreground(fg)
+	}
+	switch s.kind {
+	case completion.TokenRoot, completion.TokenFunction:
+		st = st.Bold(true)
+	}
+	if s.match {
+		st = st.Bold(true)
+		if th.SelectedBG != nil {
+			st = st.Background(th.SelectedBG)
+		}
+		if th.SelectedFG != nil {
+			st = st.Foreground(th.SelectedFG)
+		}
+	}
+	if s.errMark {
+		st = st.Underline(true)
+		if th.StatusError != nil {
+			st = st.Foreground(th.StatusError)
+		}
+	}
+	if s.cursor {
+		st = st.Reverse(true)
+	}
+	return st
+}
+
+func exprTokenColor(th Theme, kind completion.TokenKind) color.Color {
+	switch kind {
+	case completion.TokenIdent, completion.TokenRoot:
+		return th.KeyColor
+	case completion.TokenFunction:
+		return th.HelpKey
+	case completion.TokenKeyword:
+		return th.HelpValue
+	case completion.TokenString, completion.TokenNumber:
+		return th.ValueColor
+	default:
+		return th.InputFG
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
 )
 
 // TestEnterWithUnderscorePreservesIt validates that pressing Enter with just "_" in expr mode
@@ -316,3 +318,82 @@ func TestUnderscorePrefixedKeyInTableModeSync(t *testing.T) {
 		t.Fatalf("expected PathInput to be '_.__metadata', got %q", val)
 	}
 }
+
+// TestEnterDescentPathOpensSearchResults validates that a "..key" path lists
+// every matching value with its full path and that results can be entered.
+func TestEnterDescentPathOpensSearchResults(t *testing.T) {
+	root := map[string]interface{}{
+		"name": "top",
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "tags": []interface{}{"x"}},
+			map[string]interface{}{"meta": map[string]interface{}{"name": "b"}},
+		},
+	}
+	m := focusedModelWithRoot(root)
+	m.PathInput.SetValue("_.items..name")
+	newModel, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m2 := newModel.(*Model)
+
+	if m2.InputFocused || !m2.AdvancedSearchActive {
+		t.Fatalf("expected committed search results, got input=%v search=%v", m2.InputFocused, m2.AdvancedSearchActive)
+	}
+	var paths []string
+	for _, r := range m2.AdvancedSearchResults {
+		paths = append(paths, r.FullPath)
+	}
+	if strings.Join(paths, ",") != "[0].name,[1].meta.name" {
+		t.Fatalf("unexpected result paths %v", paths)
+	}
+
+	m2.Tbl.SetCursor(1)
+	next, _ := m2.navigateForwardFromSearch()
+	m3 := next.(*Model)
+	if m3.Path != "_.items[1].meta.name" {
+		t.Fatalf("expected to navigate to the match, got %q", m3.Path)
+	}
+
+	if got := SearchPaths(root, "..name"); strings.Join(got, ",") != "_.items[0].name,_.items[1].meta.name,_.name" {
+		t.Fatalf("unexpected descent search paths %v", got)
+	}
+}
+
+func TestSearchPaths_QuotesKeys(t *testing.T) {
+	root := map[string]any{
+		"bad-key": "needle",
+		"nested":  map[string]any{"odd key": "needle"},
+		"list":    []any{"needle"},
+	}
+	want := `_["bad-key"],_.list,_.list[0],_.nested,_.nested["odd key"]`
+	if got := SearchPaths(root, "needle"); strings.Join(got, ",") != want {
+		t.Fatalf("unexpected search paths %v", got)
+	}
+	for _, p := range SearchPaths(root, "needle") {
+		if _, err := navigator.Resolve(root, p); err != nil {
+			t.Errorf("path %q does not resolve: %v", p, err)
+		}
+	}
+}
+
+// TestEnterPipelineShowsFinalStage validates that a '|' pipeline typed in the
+// input bar shows the result of its last stage.
+func TestEnterPipelineShowsFinalStage(t *testing.T) {
+	root := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "b", "ok": true},
+			map[string]interface{}{"name": "c", "ok": false},
+			map[string]interface{}{"name": "a", "ok": true},
+		},
+	}
+	m := focusedModelWithRoot(root)
+	m.PathInput.SetValue("_.items | filter(x, x.ok) | map(x, x.name) | sort()")
+	newModel, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m2 := newModel.(*Model)
+
+	if m2.ErrMsg != "" {
+		t.Fatalf("unexpected error %q", m2.ErrMsg)
+	}
+	got, ok := m2.Node.([]interface{})
+	if !ok || len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("expected [a b], got %#v", m2.Node)
+	}
+}
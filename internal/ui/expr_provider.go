@@ -4,6 +4,7 @@ import (
 	"github.com/google/cel-go/cel"
 
 	celhelper "github.com/oakwood-commons/kvx/internal/cel"
+	"github.com/oakwood-commons/kvx/internal/jq"
 	"github.com/oakwood-commons/kvx/internal/navigator"
 )
 
@@ -45,16 +46,22 @@ var exprProvider ExpressionProvider = celExpressionProvider{}
 func SetExpressionProvider(p ExpressionProvider) {
 	if p != nil {
 		exprProvider = p
-		// Also wire up the navigator to use the same evaluator
-		navigator.SetEvaluator(p.Evaluate)
+		// Also wire up the navigator to use the same evaluator; in jq mode
+		// it keeps sending jq programs to gojq
+		if queryLang == QueryLangJQ {
+			navigator.SetEvaluator(evaluateWithQueryLang)
+		} else {
+			navigator.SetEvaluator(p.Evaluate)
+		}
 	}
 }
 
-// ResetExpressionProvider restores the default CEL expression provider.
-// This also resets the navigator to use its default evaluator.
-// Useful for test cleanup.
+// ResetExpressionProvider restores the default CEL expression provider and
+// query language. This also resets the navigator to use its default
+// evaluator. Useful for test cleanup.
 func ResetExpressionProvider() {
 	exprProvider = celExpressionProvider{}
+	queryLang = QueryLangCEL
 	navigator.SetEvaluator(nil)
 }
 
@@ -75,6 +82,10 @@ func DiscoverExpressions() []string {
 
 // IsExpression delegates to the configured provider to detect expression syntax.
 func IsExpression(expr string) bool {
+	if isJQProgram(expr) {
+		_, isPath := jq.SplitPath(expr)
+		return !isPath
+	}
 	return exprProvider.IsExpression(expr)
 }
 
@@ -4,6 +4,7 @@
 package expression
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -446,7 +447,7 @@ func inferNodeType(node interface{}) string {
 		return "string"
 	case bool:
 		return "bool"
-	case float64:
+	case float64, json.Number:
 		return "double"
 	case int, int64:
 		return "int"
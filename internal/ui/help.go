@@ -50,13 +50,23 @@ func navigationHelpRows(keyMode KeyMode, descs map[string]string) [][]string {
 			{"j/k", descs["navigate_up_down"]},
 			{"h/l", descs["navigate_back_forward"]},
 			{"Enter/l", "decode serialized scalar"},
-			{"/", "search/filter"},
+			{"/ s", "search (s: under selection)"},
 			{"n/N", "next/prev match"},
 			{"gg/G", "go to top/bottom"},
 			{":", "expression mode"},
 			{"y", "copy path"},
+			{"e", "open source in $EDITOR"},
+			{"o", "open URL in browser"},
+			{"w", "wrap long values"},
 			{"?", "toggle help"},
 			{"q", descs["quit"]},
+			{"v C-f c", "columns (C-f: filter, c: manage)"},
+			{"k on row 1", "column stats (h/l: next column)"},
+			{"a", "annotate row"},
+			{"p", "peek at the whole value"},
+			{"d", "detail view of the element (--schema)"},
+			{"r", "recently visited paths"},
+			{"L", "load more of a large array or all records"},
 		}
 	case KeyModeEmacs:
 		// Show emacs-style keys only (no function key references)
@@ -64,14 +74,24 @@ func navigationHelpRows(keyMode KeyMode, descs map[string]string) [][]string {
 			{"C-n/C-p", descs["navigate_up_down"]},
 			{"C-b/C-f", descs["navigate_back_forward"]},
 			{"C-f/Enter", "decode serialized scalar"},
-			{"C-s", "search/filter"},
+			{"C-s M-s", "search (M-s: under selection)"},
 			{"C-r", "prev match"},
 			{"M-</M->", "go to top/bottom"},
 			{"M-x", "expression mode"},
 			{"M-w", "copy path"},
+			{"M-e", "open source in $EDITOR"},
+			{"M-o", "open URL in browser"},
+			{"M-t", "wrap long values"},
 			{"F1", "toggle help"},
 			{"C-g", "cancel/clear"},
 			{"C-q", descs["quit"]},
+			{"M-v M-f M-c", "columns (M-f: filter, M-c: manage)"},
+			{"C-p on row 1", "column stats (C-b/C-f: next column)"},
+			{"M-a", "annotate row"},
+			{"M-p", "peek at the whole value"},
+			{"M-d", "detail view of the element (--schema)"},
+			{"M-r", "recently visited paths"},
+			{"L", "load more of a large array or all records"},
 		}
 	case KeyModeFunction:
 		// Show arrow keys only - function keys are in the Keys section
@@ -80,11 +100,29 @@ func navigationHelpRows(keyMode KeyMode, descs map[string]string) [][]string {
 			{"←/→", descs["navigate_back_forward"]},
 			{"→/Enter", "decode serialized scalar"},
 			{"Home/End", "go to top/bottom"},
+			{"C-f C-o", "filter/manage columns (F11 view)"},
+			{"↑ on row 1", "column stats (←/→: next column)"},
+			{"C-t", "annotate row"},
+			{"C-v", "peek at the whole value"},
+			{"C-d", "detail view of the element (--schema)"},
+			{"C-r", "recently visited paths"},
+			{"L", "load more of a large array or all records"},
 		}
 	}
 	return rows
 }
 
+// filterHelpRows documents the map filter query syntax (see parseMapFilter).
+func filterHelpRows() [][]string {
+	return [][]string{
+		{"filter abc", "keys starting with abc"},
+		{"filter =abc", "values containing abc"},
+		{"filter :type", "values of a type (list, null...)"},
+		{"filter !term", "negate a term"},
+		{`filter "a b"`, `quote spaces or a leading = : !`},
+	}
+}
+
 // View renders the help overlay if visible
 func (m HelpModel) View() string {
 	if !m.Visible {
@@ -132,7 +170,7 @@ func (m HelpModel) View() string {
 		}
 	}
 	// Show keybindings based on current key mode
-	navRows := navigationHelpRows(m.KeyMode, descs)
+	navRows := append(navigationHelpRows(m.KeyMode, descs), filterHelpRows()...)
 	helpRows = append(helpRows, navRows...)
 
 	// Expression mode keybindings (separate section)
@@ -287,7 +325,7 @@ func GenerateHelpText(menu MenuConfig, allowEditInput bool, navDescs map[string]
 	}
 
 	// Add navigation keybindings based on key mode
-	navRows := navigationHelpRows(keyMode, descs)
+	navRows := append(navigationHelpRows(keyMode, descs), filterHelpRows()...)
 	helpRows = append(helpRows, navRows...)
 
 	// Expression mode keybindings (separate section)
@@ -332,6 +370,7 @@ func GenerateHelpText(menu MenuConfig, allowEditInput bool, navDescs map[string]
 	lines = append(lines, "")
 	hint := keyModeSwitchHint(keyMode)
 	lines = append(lines, valStyle.Render(hint))
+	lines = append(lines, valStyle.Render("In help: / search, other keys say what they do"))
 
 	return strings.Join(lines, "\n")
 }
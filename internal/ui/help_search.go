@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// keyDescriptions describes the keys that move around rather than run a
+// menu action, for the key lookup of the help overlay.
+var keyDescriptions = map[VimAction]string{
+	VimActionDown:        "move down",
+	VimActionUp:          "move up",
+	VimActionBack:        "go back to the parent",
+	VimActionForward:     "open the selected row",
+	VimActionNextMatch:   "next search match",
+	VimActionPrevMatch:   "previous search match",
+	VimActionTop:         "go to the top",
+	VimActionBottom:      "go to the bottom",
+	VimActionPendingG:    "go to the top (press twice: gg)",
+	VimActionClearSearch: "cancel or clear the search",
+	VimActionEnter:       "open the selected row or decode a serialized value",
+}
+
+// commonKeyDescriptions describes the keys that work in every key mode.
+var commonKeyDescriptions = map[string]string{
+	"up":     "move up",
+	"down":   "move down",
+	"left":   "go back to the parent",
+	"right":  "open the selected row",
+	"home":   "go to the top",
+	"end":    "go to the bottom",
+	"enter":  "open the selected row or decode a serialized value",
+	"esc":    "close the overlay or cancel",
+	"L":      "load more of a large array or all records",
+	"ctrl+c": "quit",
+}
+
+// closeHelp hides the help overlay and forgets its search and scrolling.
+func (m *Model) closeHelp() {
+	m.HelpVisible = false
+	m.HelpScroll = 0
+	m.HelpQuery = ""
+	m.HelpSearching = false
+	m.HelpKeyInfo = ""
+	m.applyLayout(true) // Recalculate layout when help closes
+}
+
+// handleHelpKey handles keys while the help overlay is open, which is
+// modal: up/down scroll, / searches the help text, esc or F1 close it, and
+// any other key shows what it does in the current key mode instead of
+// doing it; ctrl+c still quits.
+func (m *Model) handleHelpKey(keyStr string) (bool, tea.Cmd) {
+	if !m.HelpVisible {
+		return false, nil
+	}
+	if m.HelpSearching {
+		m.handleHelpSearchKey(keyStr)
+		return true, nil
+	}
+	switch keyStr {
+	case "ctrl+c":
+		return true, tea.Quit
+	case "f1":
+		m.closeHelp()
+	case "esc":
+		if m.HelpQuery != "" {
+			m.HelpQuery = ""
+			m.HelpScroll = 0
+			break
+		}
+		m.closeHelp()
+	case "/":
+		m.HelpSearching = true
+		m.HelpKeyInfo = ""
+	case "up":
+		m.HelpScroll = max(0, m.HelpScroll-1)
+	case "down":
+		m.HelpScroll++
+	case "pgup":
+		m.HelpScroll = max(0, m.HelpScroll-10)
+	case "pgdown":
+		m.HelpScroll += 10
+	case "home":
+		m.HelpScroll = 0
+	default:
+		switch {
+		case m.KeyMode == KeyModeVim && keyStr == "k", m.KeyMode == KeyModeEmacs && keyStr == "ctrl+p":
+			m.HelpScroll = max(0, m.HelpScroll-1)
+		case m.KeyMode == KeyModeVim && keyStr == "j", m.KeyMode == KeyModeEmacs && keyStr == "ctrl+n":
+			m.HelpScroll++
+		default:
+			m.HelpKeyInfo = m.keyDescription(keyStr)
+		}
+	}
+	return true, nil
+}
+
+// handleHelpSearchKey edits the help search query: enter keeps the lines
+// it matches, esc clears it.
+func (m *Model) handleHelpSearchKey(keyStr string) {
+	switch keyStr {
+	case "enter":
+		m.HelpSearching = false
+		return
+	case "esc":
+		m.HelpSearching = false
+		m.HelpQuery = ""
+	case "backspace", "ctrl+h":
+		_, size := utf8.DecodeLastRuneInString(m.HelpQuery)
+		m.HelpQuery = m.HelpQuery[:len(m.HelpQuery)-size]
+	case "ctrl+u":
+		m.HelpQuery = ""
+	case "space":
+		m.HelpQuery += " "
+	default:
+		if r, size := utf8.DecodeRuneInString(keyStr); size == len(keyStr) && unicode.IsPrint(r) {
+			m.HelpQuery += keyStr
+		}
+	}
+	m.HelpScroll = 0
+}
+
+// keyDescription says what keyStr does in the current key mode: the help
+// text of the menu item bound to it, or what the navigation key does.
+func (m *Model) keyDescription(keyStr string) string {
+	menu := CurrentMenuConfig()
+	for _, kv := range MenuItems(menu) {
+		if strings.EqualFold(kv.Key, keyStr) && kv.Item.Enabled {
+			return fmt.Sprintf("%s: %s", keyStr, menuItemDescription(kv.Item))
+		}
+	}
+	names := slices.Sorted(maps.Keys(menu.Items))
+	for _, name := range names {
+		if item := menu.Items[name]; item.Enabled && item.Keys.forMode(m.KeyMode) == keyStr {
+			return fmt.Sprintf("%s: %s", keyStr, menuItemDescription(item))
+		}
+	}
+	var bindings map[string]VimAction
+	switch m.KeyMode {
+	case KeyModeVim:
+		bindings = VimKeyBindings
+	case KeyModeEmacs:
+		bindings = EmacsKeyBindings
+	}
+	if action, ok := bindings[keyStr]; ok {
+		if desc, ok := keyDescriptions[action]; ok {
+			return fmt.Sprintf("%s: %s", keyStr, desc)
+		}
+		for _, name := range names {
+			if item := menu.Items[name]; item.Enabled && actionToVimAction[item.Action] == action {
+				return fmt.Sprintf("%s: %s", keyStr, menuItemDescription(item))
+			}
+		}
+	}
+	if desc, ok := commonKeyDescriptions[keyStr]; ok {
+		return fmt.Sprintf("%s: %s", keyStr, desc)
+	}
+	return fmt.Sprintf("%s does nothing in %s mode", keyStr, m.KeyMode)
+}
+
+// menuItemDescription is the help text of item, or its label.
+func menuItemDescription(item MenuItem) string {
+	if item.HelpText != "" {
+		return item.HelpText
+	}
+	return item.Label
+}
+
+// helpStatus is the status bar line while the help overlay is open: the
+// search prompt or what the last key pressed does, if anything.
+func (m *Model) helpStatus() string {
+	switch {
+	case m.HelpSearching:
+		return fmt.Sprintf("Search help: %s█  (enter keeps, esc clears)", m.HelpQuery)
+	case m.HelpKeyInfo != "":
+		return m.HelpKeyInfo
+	case m.HelpQuery != "":
+		return fmt.Sprintf("Help lines matching %q  (esc clears)", m.HelpQuery)
+	}
+	return ""
+}
+
+// scrollHelpLines returns the lines of the help overlay to show in height
+// lines: those matching query, case-insensitively, starting scroll lines
+// down. It also returns the index of the first line shown and how many
+// lines match.
+func scrollHelpLines(lines []string, query string, scroll, height int) ([]string, int, int) {
+	if query != "" {
+		q := strings.ToLower(query)
+		var matched []string
+		for _, l := range lines {
+			if strings.Contains(strings.ToLower(stripANSI(l)), q) {
+				matched = append(matched, l)
+			}
+		}
+		if len(matched) == 0 {
+			matched = []string{fmt.Sprintf("No help matches %q", query)}
+		}
+		lines = matched
+	}
+	total := len(lines)
+	if height <= 0 || total <= height {
+		return lines, 0, total
+	}
+	start := max(0, min(scroll, total-height))
+	return lines[start : start+height], start, total
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelpKeyLookup(t *testing.T) {
+	for _, tc := range []struct {
+		mode   KeyMode
+		open   tea.KeyPressMsg
+		key    tea.KeyPressMsg
+		want   string
+		scroll tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: '?', Text: "?"}, tea.KeyPressMsg{Code: 'r', Text: "r"}, "r: Jump back to a recently visited path", tea.KeyPressMsg{Code: 'j', Text: "j"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: tea.KeyF1}, tea.KeyPressMsg{Code: 'p', Mod: tea.ModAlt}, "alt+p: Show the selected value whole", tea.KeyPressMsg{Code: 'n', Mod: tea.ModCtrl}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: tea.KeyF1}, tea.KeyPressMsg{Code: tea.KeyF3}, "f3: Start search", tea.KeyPressMsg{Code: tea.KeyDown}},
+	} {
+		m := testRecentModel(tc.mode)
+		m.Update(tc.open)
+		require.True(t, m.HelpVisible, tc.mode)
+
+		// Keys pressed in help say what they do instead of doing it.
+		m.Update(tc.key)
+		assert.Equal(t, tc.want, m.HelpKeyInfo, tc.mode)
+		assert.False(t, m.RecentOpen, tc.mode)
+		assert.Nil(t, m.Peek, tc.mode)
+		assert.False(t, m.AdvancedSearchActive, tc.mode)
+		assert.Contains(t, stripANSI(m.View().Content), tc.want, tc.mode)
+
+		m.Update(tc.scroll)
+		assert.Equal(t, 1, m.HelpScroll, tc.mode)
+
+		m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+		assert.False(t, m.HelpVisible, tc.mode)
+		assert.Zero(t, m.HelpScroll, tc.mode)
+		assert.Empty(t, m.HelpKeyInfo, tc.mode)
+	}
+}
+
+func TestHelpKeyLookupNavigation(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	assert.Equal(t, "h: go back to the parent", m.keyDescription("h"))
+	assert.Equal(t, "G: go to the bottom", m.keyDescription("G"))
+	assert.Equal(t, "L: load more of a large array or all records", m.keyDescription("L"))
+	assert.Equal(t, "x does nothing in vim mode", m.keyDescription("x"))
+}
+
+func TestHelpSearch(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	m.WinHeight = 40
+	m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+	m.Update(tea.KeyPressMsg{Code: '/', Text: "/"})
+	require.True(t, m.HelpSearching)
+	typeKeys(m, "PEEK")
+	assert.Contains(t, stripANSI(m.View().Content), "Search help: PEEK█")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.False(t, m.HelpSearching)
+	assert.Equal(t, "PEEK", m.HelpQuery)
+
+	view := stripANSI(m.View().Content)
+	assert.Contains(t, view, "peek at the whole value")
+	assert.NotContains(t, view, "annotate row")
+
+	// esc clears the search first, then closes help.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.True(t, m.HelpVisible)
+	assert.Empty(t, m.HelpQuery)
+	assert.Contains(t, stripANSI(m.View().Content), "annotate row")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.False(t, m.HelpVisible)
+}
+
+func TestScrollHelpLines(t *testing.T) {
+	lines := []string{"a one", "b two", "c three", "d four"}
+
+	shown, start, total := scrollHelpLines(lines, "", 1, 2)
+	assert.Equal(t, []string{"b two", "c three"}, shown)
+	assert.Equal(t, 1, start)
+	assert.Equal(t, 4, total)
+
+	// Scrolling past the end keeps the last lines in view.
+	shown, start, _ = scrollHelpLines(lines, "", 10, 2)
+	assert.Equal(t, []string{"c three", "d four"}, shown)
+	assert.Equal(t, 2, start)
+
+	shown, _, total = scrollHelpLines(lines, "T", 0, 2)
+	assert.Equal(t, []string{"b two", "c three"}, shown)
+	assert.Equal(t, 2, total)
+
+	shown, _, _ = scrollHelpLines(lines, "zzz", 0, 2)
+	assert.Equal(t, []string{`No help matches "zzz"`}, shown)
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// selectedURL returns the selected row's value when it is an http or https
+// URL.
+func (m *Model) selectedURL() (string, bool) {
+	node, err := navigator.Resolve(m.Root, m.selectedRowPath())
+	if err != nil {
+		return "", false
+	}
+	s, ok := node.(string)
+	if !ok || !formatter.IsURL(s) {
+		return "", false
+	}
+	return s, true
+}
+
+// vimOpenURL opens the selected URL value (same as F8).
+func (m *Model) vimOpenURL() (tea.Model, tea.Cmd) {
+	return m, menuActionOpenURL(m)
+}
+
+// menuActionOpenURL opens the selected URL value in the default browser, the
+// same way the status screen's open-url action does.
+func menuActionOpenURL(m *Model) tea.Cmd {
+	if m.kioskRefuses("Opening URLs") {
+		return nil
+	}
+	url, ok := m.selectedURL()
+	if !ok {
+		m.ErrMsg = "Selected value is not a URL"
+		m.StatusType = "error"
+		return nil
+	}
+	if err := OpenURL(url); err != nil {
+		m.ErrMsg = fmt.Sprintf("Open URL failed: %v", err)
+		m.StatusType = "error"
+		return nil
+	}
+	m.ErrMsg = fmt.Sprintf("Opened: %s", url)
+	m.StatusType = "success"
+	return nil
+}
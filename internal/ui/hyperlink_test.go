@@ -0,0 +1,62 @@
+//nolint:forcetypeassert
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func urlModel(t *testing.T) *Model {
+	t.Helper()
+	root := map[string]interface{}{
+		"docs": "https://example.com/docs",
+		"name": "demo",
+	}
+	m := InitialModel(root)
+	m.Root = root
+	m.InputFocused = false
+	m.KeyMode = KeyModeVim
+	m.WinWidth = 80
+	m.WinHeight = 20
+	m.applyLayout(true)
+	m.Tbl.Focus()
+	return &m
+}
+
+func TestOpenURLAction(t *testing.T) {
+	var opened string
+	orig := openURLFn
+	openURLFn = func(url string) error {
+		opened = url
+		return nil
+	}
+	t.Cleanup(func() { openURLFn = orig })
+
+	m := urlModel(t)
+	moveCursorToKey(t, m, "docs")
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	m = result.(*Model)
+	assert.Equal(t, "https://example.com/docs", opened)
+	assert.Equal(t, "success", m.StatusType)
+
+	opened = ""
+	moveCursorToKey(t, m, "name")
+	result, _ = m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	m = result.(*Model)
+	assert.Empty(t, opened)
+	assert.Equal(t, "error", m.StatusType)
+	assert.Contains(t, m.ErrMsg, "not a URL")
+}
+
+func TestURLCellsRenderAsHyperlinks(t *testing.T) {
+	orig := formatter.Hyperlinks()
+	formatter.SetHyperlinks(true)
+	t.Cleanup(func() { formatter.SetHyperlinks(orig) })
+
+	rows := styleRowsWithWidths([][]string{{"docs", "https://example.com/docs"}}, 10, 12)
+	assert.Contains(t, rows[0][1], "\x1b]8;;https://example.com/docs\x07")
+	assert.Equal(t, 12, visibleWidth(rows[0][1]))
+}
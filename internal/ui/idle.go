@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// idleCheckMsg is sent when the idle timeout may have passed.
+type idleCheckMsg struct{}
+
+// idleCheck returns a tea.Cmd that sends idleCheckMsg after d.
+func idleCheck(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// noteInput records key presses, mouse events, and pastes, which restart
+// the idle timeout.
+func (m *Model) noteInput(msg tea.Msg) {
+	switch msg.(type) {
+	case tea.KeyPressMsg, tea.MouseMsg, tea.PasteMsg:
+		m.lastInput = time.Now()
+	}
+}
+
+// handleIdleCheck exits once IdleTimeout has passed without input, printing
+// the result of the current expression when IdlePrint is set, and otherwise
+// checks again when the timeout would next run out. A status screen still
+// waiting for its operation is never idle.
+func (m *Model) handleIdleCheck() tea.Cmd {
+	if m.IdleTimeout <= 0 {
+		return nil
+	}
+	if m.ViewMode == "status" && m.StatusViewState != nil && m.StatusViewState.Phase == statusPhaseWaiting {
+		m.lastInput = time.Now()
+	}
+	if left := m.IdleTimeout - time.Since(m.lastInput); left > 0 {
+		return idleCheck(left)
+	}
+	m.IdleExited = true
+	if !m.IdlePrint {
+		return tea.Quit
+	}
+	expr := strings.TrimSpace(m.PathInput.Value())
+	if expr == "" {
+		expr = formatPathForDisplay("")
+	}
+	return m.printCLIOutput(expr)
+}
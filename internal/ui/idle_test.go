@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleCheck(t *testing.T) {
+	m := InitialModel(map[string]any{"name": "kvx"})
+	m.IdleTimeout = time.Minute
+	m.Init()
+	assert.NotNil(t, m.handleIdleCheck(), "checks again before the timeout")
+	assert.False(t, m.IdleExited)
+
+	m.lastInput = time.Now().Add(-2 * time.Minute)
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	assert.WithinDuration(t, time.Now(), m.lastInput, time.Second, "input restarts the timeout")
+
+	m.lastInput = time.Now().Add(-2 * time.Minute)
+	_, cmd := m.Update(idleCheckMsg{})
+	require.NotNil(t, cmd)
+	assert.True(t, m.IdleExited)
+	assert.Empty(t, m.PendingCLIExpr)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestIdleCheck_Print(t *testing.T) {
+	m := InitialModel(map[string]any{"name": "kvx"})
+	m.IdleTimeout = time.Minute
+	m.IdlePrint = true
+	m.PathInput.SetValue("_.name")
+	m.lastInput = time.Now().Add(-2 * time.Minute)
+	cmd := m.handleIdleCheck()
+	require.NotNil(t, cmd)
+	assert.Equal(t, "_.name", m.PendingCLIExpr)
+}
+
+func TestIdleCheck_StatusWaiting(t *testing.T) {
+	m := InitialModel(testStatusData())
+	m.ViewMode = "status"
+	m.StatusViewState = buildStatusViewModel(testStatusData(), testStatusSchema(), KeyModeVim, true, nil, 80, 24)
+	m.IdleTimeout = time.Minute
+	m.lastInput = time.Now().Add(-2 * time.Minute)
+	assert.NotNil(t, m.handleIdleCheck())
+	assert.False(t, m.IdleExited, "a waiting status screen is not idle")
+
+	m.IdleTimeout = 0
+	assert.Nil(t, m.handleIdleCheck())
+}
@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/completion"
+	"github.com/oakwood-commons/kvx/internal/jq"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// Query languages the expression bar reads.
+const (
+	QueryLangCEL = "cel"
+	QueryLangJQ  = "jq"
+)
+
+// jqRunTimeout bounds a jq program run from the expression bar, so an
+// endless program such as "repeat(1)" reports an error instead of hanging.
+const jqRunTimeout = 10 * time.Second
+
+// queryLang is the language of the expression bar, set by SetQueryLang.
+var queryLang = QueryLangCEL
+
+// SetQueryLang selects the language the expression bar reads: QueryLangCEL
+// (the default) or QueryLangJQ. In jq mode, typed programs run with gojq and
+// completion, highlighting, and the function palette switch to jq, while
+// paths built by browsing are shown as jq paths such as .items[0].name.
+func SetQueryLang(lang string) error {
+	switch lang {
+	case "", QueryLangCEL:
+		queryLang = QueryLangCEL
+	case QueryLangJQ:
+		queryLang = QueryLangJQ
+		navigator.SetEvaluator(evaluateWithQueryLang)
+	default:
+		return fmt.Errorf("unknown query language %q: must be %s or %s", lang, QueryLangCEL, QueryLangJQ)
+	}
+	return nil
+}
+
+// QueryLang returns the language of the expression bar.
+func QueryLang() string {
+	return queryLang
+}
+
+// evaluateWithQueryLang is the navigator's evaluator in jq mode: jq programs
+// run with gojq and CEL paths with the expression provider.
+func evaluateWithQueryLang(expr string, root interface{}) (interface{}, error) {
+	if isJQProgram(expr) {
+		return jq.Evaluate(expr, root)
+	}
+	return exprProvider.Evaluate(expr, root)
+}
+
+// isJQProgram reports whether expr runs as jq: in jq mode, everything but
+// the CEL paths the TUI builds, which start with the root "_".
+func isJQProgram(expr string) bool {
+	return queryLang == QueryLangJQ && !strings.HasPrefix(strings.TrimSpace(expr), "_")
+}
+
+// isJQProgramPath reports whether a model path holds a jq program rather
+// than a CEL path. Typed jq paths start with "."; the result of a program is
+// kept as "(program)", or "[program]" when it outputs several values, so
+// drilling into it appends steps like "(program).name" that jq can run.
+func isJQProgramPath(path string) bool {
+	if queryLang != QueryLangJQ {
+		return false
+	}
+	p := strings.TrimSpace(path)
+	switch {
+	case strings.HasPrefix(p, "."), strings.HasPrefix(p, "("):
+		return true
+	case strings.HasPrefix(p, "[") && len(p) > 1:
+		// "[0]" and `["key"]` are CEL steps from the root
+		next := p[1]
+		return next != '"' && (next < '0' || next > '9')
+	}
+	return false
+}
+
+// jqModelPath normalizes a jq path or program for the model: plain jq paths
+// become the CEL path browsing would build, and programs are kept as typed.
+func jqModelPath(path string) string {
+	if steps, ok := jq.SplitPath(path); ok {
+		return celPathFromJQ(steps)
+	}
+	return strings.TrimSpace(path)
+}
+
+// celPathFromJQ builds the CEL path of the steps of a plain jq path; no
+// steps is the root, "".
+func celPathFromJQ(steps []jq.PathStep) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("_")
+	for _, step := range steps {
+		switch {
+		case step.IsIndex:
+			b.WriteString("[" + strconv.Itoa(step.Index) + "]")
+		case isValidCELIdentifier(step.Key):
+			b.WriteString("." + step.Key)
+		default:
+			b.WriteString(celBracketExpr(step.Key))
+		}
+	}
+	return b.String()
+}
+
+// jqDisplayPath formats a model path for the expression bar in jq mode:
+// "." for the root, CEL paths as jq paths, and programs as they are.
+func jqDisplayPath(path string) string {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" || trimmed == "_" {
+		return "."
+	}
+	if isJQProgramPath(trimmed) || strings.ContainsAny(trimmed, "()|") {
+		return trimmed
+	}
+	segments := splitPathSegments(trimmed)
+	steps := make([]jq.PathStep, 0, len(segments))
+	for _, seg := range segments {
+		if i, err := strconv.Atoi(seg); err == nil && i >= 0 {
+			steps = append(steps, jq.PathStep{Index: i, IsIndex: true})
+			continue
+		}
+		if key, err := strconv.Unquote(seg); err == nil && strings.HasPrefix(seg, `"`) {
+			seg = key
+		}
+		steps = append(steps, jq.PathStep{Key: seg})
+	}
+	return jq.FormatPath(steps)
+}
+
+// splitJQDrillPath splits a path into a program result, "(program)" or
+// "[program]", and the steps drilled into it, so going up stops at the
+// result and then returns to the root.
+func splitJQDrillPath(path string) (base, rest string, ok bool) {
+	if !isJQProgramPath(path) || (!strings.HasPrefix(path, "(") && !strings.HasPrefix(path, "[")) {
+		return "", "", false
+	}
+	end := completion.MatchingBracket(path, 0)
+	if end < 0 {
+		return "", "", false
+	}
+	runes := []rune(path)
+	return string(runes[:end+1]), string(runes[end+1:]), true
+}
+
+// enterJQ runs the expression bar's input as jq. Plain paths are browsed
+// like CEL paths; other programs show their result, a list when they output
+// none or several values.
+func (m *Model) enterJQ(value string) (tea.Model, tea.Cmd) {
+	m.ShowSuggestions = false
+	expr := strings.TrimSpace(value)
+	if expr == "" {
+		expr = "."
+	}
+	if steps, ok := jq.SplitPath(expr); ok {
+		path := celPathFromJQ(steps)
+		start := time.Now()
+		node, err := navigator.Resolve(m.Root, path)
+		m.noteEval(expr, time.Since(start), err)
+		if err != nil {
+			m.setStickyExprError(expr, err)
+			return m, nil
+		}
+		return m.jqResultModel(node, path, expr), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqRunTimeout)
+	defer cancel()
+	start := time.Now()
+	outputs, err := jq.Run(ctx, expr, m.Root)
+	m.noteEval(expr, time.Since(start), err)
+	if err != nil {
+		m.setStickyExprError(expr, err)
+		return m, nil
+	}
+	return m.jqResultModel(jq.Collapse(outputs), jqResultPath(expr, len(outputs)), expr), nil
+}
+
+// jqResultPath is the model path of the result of a program that output n
+// values: "(program)" for one, "[program]" for the list of none or several.
+func jqResultPath(expr string, n int) string {
+	if n == 1 {
+		return "(" + expr + ")"
+	}
+	return "[" + expr + "]"
+}
+
+// applyInitialJQ shows the result of the initial program in jq mode, like
+// Enter does for a typed one.
+func applyInitialJQ(m *Model, expr string) {
+	if steps, ok := jq.SplitPath(expr); ok {
+		path := celPathFromJQ(steps)
+		node, err := navigator.Resolve(m.Root, path)
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("explore expression error: %v", err)
+			m.StatusType = "error"
+			return
+		}
+		m.NavigateTo(node, path)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), jqRunTimeout)
+	defer cancel()
+	outputs, err := jq.Run(ctx, expr, m.Root)
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("explore expression error: %v", err)
+		m.StatusType = "error"
+		return
+	}
+	node := jq.Collapse(outputs)
+	m.NavigateTo(node, jqResultPath(expr, len(outputs)))
+	m.setExprResult(expr, node)
+	m.PathInput.SetValue(expr)
+	m.PathInput.SetCursor(len(expr))
+}
+
+// jqResultModel shows node at path with expr kept in the expression bar,
+// like the CEL branches of Enter.
+func (m *Model) jqResultModel(node interface{}, path, expr string) *Model {
+	newModel := InitialModel(node)
+	m.carrySession(&newModel)
+	newModel.Root = m.Root
+	newModel.DebugMode = m.DebugMode
+	newModel.NoColor = m.NoColor
+	newModel.WinWidth = m.WinWidth
+	newModel.WinHeight = m.WinHeight
+	newModel.ExprProvider = m.ExprProvider
+	newModel.Path = path
+	newModel.PathKeys = parsePathKeys(path)
+	newModel.ApplyColorScheme()
+	newModel.applyLayout(true)
+	newModel.InputFocused = true
+	newModel.setExprResult(expr, newModel.Node)
+	newModel.PathInput.SetValue(expr)
+	newModel.PathInput.SetCursor(len(expr))
+	newModel.PathInput.Focus()
+	newModel.Tbl.Blur()
+	return &newModel
+}
+
+// completeJQ handles Tab in jq mode. Each completion holds the whole input,
+// so Tab replaces the input with the first one and pressing it again cycles
+// through the rest.
+func (m *Model) completeJQ() {
+	value := m.PathInput.Value()
+	completions := m.Status.Completions
+	sel := m.Status.SelectedCompletion
+	cycling := m.LastTabPosition == len(value) && sel >= 0 && sel < len(completions) && completions[sel].Text == value
+	next := 0
+	if cycling {
+		next = (sel + 1) % len(completions)
+	} else {
+		m.filterSuggestions(true)
+		m.syncSuggestions()
+		completions = m.Status.Completions
+		if len(completions) == 0 {
+			return
+		}
+	}
+	text := completions[next].Text
+	m.Status.SelectedCompletion = next
+	m.SelectedSuggestion = next
+	m.PathInput.SetValue(text)
+	m.PathInput.SetCursor(len(text))
+	m.LastTabPosition = len(text)
+}
+
+// insertJQFunction inserts a builtin chosen in the function palette: on its
+// own when the input is empty or the root, otherwise as a new pipeline
+// stage.
+func (m *Model) insertJQFunction(fn *completion.FunctionMetadata) {
+	text := fn.Name
+	if len(fn.ParamTypes) > 0 {
+		text += "("
+	}
+	current := strings.TrimSpace(m.PathInput.Value())
+	if current != "" && current != "." {
+		text = current + " | " + text
+	}
+	m.PathInput.SetValue(text)
+	m.PathInput.SetCursor(len(text))
+}
@@ -0,0 +1,143 @@
+//nolint:forcetypeassert
+package ui
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// jqModel returns a focused model over sampleData in jq mode.
+func jqModel(t *testing.T) *Model {
+	t.Helper()
+	require.NoError(t, SetQueryLang(QueryLangJQ))
+	t.Cleanup(ResetExpressionProvider)
+	node := sampleData()
+	m := InitialModel(node)
+	m.Root = node
+	m.InputFocused = true
+	m.PathInput = textinput.New()
+	m.PathInput.Prompt = ""
+	m.PathInput.Focus()
+	return &m
+}
+
+func typeAndEnter(t *testing.T, m *Model, expr string) *Model {
+	t.Helper()
+	m.PathInput.SetValue(expr)
+	next, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	return next.(*Model)
+}
+
+func TestSetQueryLang(t *testing.T) {
+	t.Cleanup(ResetExpressionProvider)
+
+	require.NoError(t, SetQueryLang(QueryLangJQ))
+	assert.Equal(t, QueryLangJQ, QueryLang())
+	require.NoError(t, SetQueryLang(""))
+	assert.Equal(t, QueryLangCEL, QueryLang())
+
+	err := SetQueryLang("jsonpath")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be cel or jq")
+	assert.Equal(t, QueryLangCEL, QueryLang())
+}
+
+func TestJQPaths(t *testing.T) {
+	jqModel(t)
+
+	assert.Equal(t, ".", formatPathForDisplay(""))
+	assert.Equal(t, ".items[0].name", formatPathForDisplay("_.items[0].name"))
+	assert.Equal(t, `.regions."bad-key"`, formatPathForDisplay(`_.regions["bad-key"]`))
+	assert.Equal(t, "(.items | length)", formatPathForDisplay("(.items | length)"))
+
+	assert.Equal(t, "_.items[0].name", normalizePathForModel(".items[0].name"))
+	assert.Equal(t, `_["bad-key"]`, normalizePathForModel(`."bad-key"`))
+	assert.Equal(t, "", normalizePathForModel("."))
+	assert.Equal(t, "[.items[]]", normalizePathForModel("[.items[]]"))
+	assert.Equal(t, "_[0]", normalizePathForModel("_[0]"), "CEL paths are kept")
+
+	assert.Equal(t, "(.items | map(.name))", removeLastSegment("(.items | map(.name))[1]"))
+	assert.Equal(t, "", removeLastSegment("(.items | map(.name))"))
+	assert.Equal(t, "[.items[]][0]", removeLastSegment("[.items[]][0].name"))
+	assert.Equal(t, "_.items", removeLastSegment("_.items[0]"))
+}
+
+func TestJQEnter(t *testing.T) {
+	m := jqModel(t)
+
+	m2 := typeAndEnter(t, m, ".regions.asia")
+	assert.Equal(t, "_.regions.asia", m2.Path, "plain paths are browsed")
+	assert.Equal(t, map[string]interface{}{"count": 2}, m2.Node)
+	assert.Equal(t, ".regions.asia", m2.PathInput.Value())
+
+	m2 = typeAndEnter(t, m, ".items | map(.name)")
+	assert.Equal(t, "(.items | map(.name))", m2.Path)
+	assert.Equal(t, []interface{}{"a", "b"}, m2.Node)
+	assert.Equal(t, ".items | map(.name)", m2.PathInput.Value())
+	assert.Empty(t, m2.ErrMsg)
+
+	m2 = typeAndEnter(t, m, ".items[].name")
+	assert.Equal(t, "[.items[].name]", m2.Path, "several outputs are shown as a list")
+	assert.Equal(t, []interface{}{"a", "b"}, m2.Node)
+
+	// Drilling into a program's result appends jq steps
+	node, err := navigator.Resolve(m.Root, "[.items[].name][1]")
+	require.NoError(t, err)
+	assert.Equal(t, "b", node)
+
+	m2 = typeAndEnter(t, m, ".items ]")
+	assert.Contains(t, m2.ErrMsg, "Syntax error")
+	assert.Equal(t, 7, m2.ErrPos)
+
+	m2 = typeAndEnter(t, m, ".items | nope")
+	assert.Contains(t, m2.ErrMsg, "jq error")
+	assert.Empty(t, m2.Path)
+}
+
+func TestJQCompletionAndHighlighting(t *testing.T) {
+	m := jqModel(t)
+	require.NotNil(t, m.CompletionEngine)
+
+	m.PathInput.SetValue(".reg")
+	m.PathInput.SetCursor(4)
+	next, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m2 := next.(*Model)
+	assert.Equal(t, ".regions", m2.PathInput.Value())
+
+	m2.PathInput.SetValue(".regions.")
+	m2.PathInput.SetCursor(9)
+	next, _ = m2.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m3 := next.(*Model)
+	first := m3.PathInput.Value()
+	next, _ = m3.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m4 := next.(*Model)
+	assert.ElementsMatch(t, []string{".regions.asia", ".regions.europe"}, []string{first, m4.PathInput.Value()}, "Tab cycles")
+
+	assert.Equal(t, "items", m.CompletionEngine.Tokenize(".items")[1].Text)
+
+	m.PathInput.SetValue(".items")
+	for _, fn := range m.FunctionPalette.AllFunctions {
+		if fn.Name == "map" {
+			m.insertJQFunction(&fn)
+			break
+		}
+	}
+	assert.Equal(t, ".items | map(", m.PathInput.Value())
+}
+
+func TestJQInitialExpr(t *testing.T) {
+	m := jqModel(t)
+	applyInitialExpr(m, ".items | length")
+	assert.Equal(t, 2, m.Node)
+	assert.Equal(t, ".items | length", m.PathInput.Value())
+
+	m = jqModel(t)
+	applyInitialExpr(m, ".regions")
+	assert.Equal(t, "_.regions", m.Path)
+}
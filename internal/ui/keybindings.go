@@ -1,6 +1,11 @@
 package ui
 
 import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/oakwood-commons/kvx/internal/navigator"
@@ -38,24 +43,35 @@ func IsValidKeyMode(mode string) bool {
 type VimAction string
 
 const (
-	VimActionNone        VimAction = ""
-	VimActionDown        VimAction = "down"
-	VimActionUp          VimAction = "up"
-	VimActionBack        VimAction = "back"
-	VimActionForward     VimAction = "forward"
-	VimActionSearch      VimAction = "search"
-	VimActionNextMatch   VimAction = "next_match"
-	VimActionPrevMatch   VimAction = "prev_match"
-	VimActionTop         VimAction = "top"
-	VimActionBottom      VimAction = "bottom"
-	VimActionHelp        VimAction = "help"
-	VimActionCopy        VimAction = "copy"
-	VimActionExpr        VimAction = "expr"
-	VimActionQuit        VimAction = "quit"
-	VimActionPendingG    VimAction = "pending_g" // Waiting for second key in gg sequence
-	VimActionClearSearch VimAction = "clear_search"
-	VimActionEnter       VimAction = "enter"
-	VimActionFilter      VimAction = "filter" // Map filter mode ('f' key)
+	VimActionNone            VimAction = ""
+	VimActionDown            VimAction = "down"
+	VimActionUp              VimAction = "up"
+	VimActionBack            VimAction = "back"
+	VimActionForward         VimAction = "forward"
+	VimActionSearch          VimAction = "search"
+	VimActionNextMatch       VimAction = "next_match"
+	VimActionPrevMatch       VimAction = "prev_match"
+	VimActionTop             VimAction = "top"
+	VimActionBottom          VimAction = "bottom"
+	VimActionHelp            VimAction = "help"
+	VimActionCopy            VimAction = "copy"
+	VimActionExpr            VimAction = "expr"
+	VimActionQuit            VimAction = "quit"
+	VimActionPendingG        VimAction = "pending_g" // Waiting for second key in gg sequence
+	VimActionClearSearch     VimAction = "clear_search"
+	VimActionEnter           VimAction = "enter"
+	VimActionFilter          VimAction = "filter"           // Map filter mode ('f' key)
+	VimActionEdit            VimAction = "edit"             // Open the source file at the selected node
+	VimActionOpenURL         VimAction = "open_url"         // Open the selected URL value in the browser
+	VimActionWrap            VimAction = "wrap"             // Toggle wrapping of long values
+	VimActionSearchSelection VimAction = "search_selection" // Deep search within the selected row's subtree
+	VimActionColumns         VimAction = "columns"          // Toggle the columnar view of lists of objects
+	VimActionColumnFilter    VimAction = "column_filter"    // Toggle the per-column filter row
+	VimActionColumnManager   VimAction = "column_manager"   // Show, hide, and reorder columns
+	VimActionAnnotate        VimAction = "annotate"         // Put a note or flag on the selected row
+	VimActionPeek            VimAction = "peek"             // Show the selected value without truncation
+	VimActionDetail          VimAction = "detail"           // Open the schema detail view of the selected element
+	VimActionRecent          VimAction = "recent"           // List the paths visited this session to jump back to
 )
 
 // VimKeyBindings maps keys to actions for vim mode.
@@ -75,7 +91,19 @@ var VimKeyBindings = map[string]VimAction{
 	"y":     VimActionCopy,
 	":":     VimActionExpr,
 	"q":     VimActionQuit,
+	"e":     VimActionEdit,
+	"o":     VimActionOpenURL,
+	"w":     VimActionWrap,
+	"s":     VimActionSearchSelection,
+	"v":     VimActionColumns,
+	"c":     VimActionColumnManager,
+	"a":     VimActionAnnotate,
+	"p":     VimActionPeek,
+	"d":     VimActionDetail,
+	"r":     VimActionRecent,
 	"enter": VimActionEnter,
+
+	"ctrl+f": VimActionColumnFilter, // Filter row in the columnar view
 }
 
 // EmacsKeyBindings maps keys to actions for emacs mode.
@@ -93,20 +121,42 @@ var EmacsKeyBindings = map[string]VimAction{
 	"f1":     VimActionHelp, // Use F1 for help (ctrl+h is backspace in terminals)
 	"alt+w":  VimActionCopy,
 	"alt+x":  VimActionExpr,
-	"ctrl+g": VimActionClearSearch, // Cancel in emacs
-	"ctrl+q": VimActionQuit,        // Quit
+	"ctrl+g": VimActionClearSearch,     // Cancel in emacs
+	"ctrl+q": VimActionQuit,            // Quit
+	"alt+e":  VimActionEdit,            // Open source file in $EDITOR
+	"alt+o":  VimActionOpenURL,         // Open URL value in the browser
+	"alt+t":  VimActionWrap,            // Toggle value wrapping
+	"alt+s":  VimActionSearchSelection, // Search under the selected row
+	"alt+v":  VimActionColumns,         // Toggle the columnar view
+	"alt+f":  VimActionColumnFilter,    // Toggle the column filter row (ctrl+f moves forward)
+	"alt+c":  VimActionColumnManager,   // Show, hide, and reorder columns
+	"alt+a":  VimActionAnnotate,        // Annotate the selected row
+	"alt+p":  VimActionPeek,            // Show the selected value whole
+	"alt+d":  VimActionDetail,          // Open the detail view of the selected element
+	"alt+r":  VimActionRecent,          // Jump back to a recently visited path (ctrl+r is prev match)
 	"enter":  VimActionEnter,
 }
 
 // actionToVimAction maps config action names to VimAction constants.
 var actionToVimAction = map[string]VimAction{
-	"help":        VimActionHelp,
-	"search":      VimActionSearch,
-	"filter":      VimActionFilter,
-	"copy":        VimActionCopy,
-	"expr":        VimActionExpr,
-	"expr_toggle": VimActionExpr,
-	"quit":        VimActionQuit,
+	"help":             VimActionHelp,
+	"search":           VimActionSearch,
+	"filter":           VimActionFilter,
+	"copy":             VimActionCopy,
+	"expr":             VimActionExpr,
+	"expr_toggle":      VimActionExpr,
+	"quit":             VimActionQuit,
+	"edit":             VimActionEdit,
+	"open_url":         VimActionOpenURL,
+	"wrap":             VimActionWrap,
+	"search_selection": VimActionSearchSelection,
+	"columns":          VimActionColumns,
+	"column_filter":    VimActionColumnFilter,
+	"column_manager":   VimActionColumnManager,
+	"annotate":         VimActionAnnotate,
+	"peek":             VimActionPeek,
+	"detail":           VimActionDetail,
+	"recent":           VimActionRecent,
 }
 
 // UpdateKeyBindingsFromConfig rebuilds vim/emacs keybindings from menu config.
@@ -148,6 +198,85 @@ func UpdateKeyBindingsFromConfig(menu MenuConfig) {
 	}
 }
 
+// builtinKeyBindings holds the vim and emacs bindings of actions that have no
+// menu item, such as moving down, which the menu config cannot rebind. They
+// are copied before UpdateKeyBindingsFromConfig changes the maps.
+var builtinKeyBindings = map[KeyMode]map[string]VimAction{
+	KeyModeVim:   withoutMenuActions(VimKeyBindings),
+	KeyModeEmacs: withoutMenuActions(EmacsKeyBindings),
+}
+
+func withoutMenuActions(bindings map[string]VimAction) map[string]VimAction {
+	menuActions := slices.Collect(maps.Values(actionToVimAction))
+	out := make(map[string]VimAction)
+	for key, action := range bindings {
+		if !slices.Contains(menuActions, action) {
+			out[key] = action
+		}
+	}
+	return out
+}
+
+// KeyConflict is a key bound to more than one action in a key mode.
+type KeyConflict struct {
+	Mode    KeyMode
+	Key     string
+	Actions []string
+}
+
+// KeyConflicts returns the keys bound to more than one enabled menu item in
+// the same key mode, or to a menu item and a built-in navigation key of vim or
+// emacs mode, sorted by mode and key. Only one of the actions runs when such a
+// key is pressed. Menu items are named by their config key.
+func KeyConflicts(menu MenuConfig) []KeyConflict {
+	bound := map[KeyMode]map[string][]string{}
+	bind := func(mode KeyMode, key, action string) {
+		if key == "" {
+			return
+		}
+		if bound[mode] == nil {
+			bound[mode] = map[string][]string{}
+		}
+		if !slices.Contains(bound[mode][key], action) {
+			bound[mode][key] = append(bound[mode][key], action)
+		}
+	}
+	for mode, bindings := range builtinKeyBindings {
+		for key, action := range bindings {
+			name := string(action)
+			if action == VimActionPendingG {
+				name = string(VimActionTop)
+			}
+			bind(mode, key, name)
+		}
+	}
+	for name, item := range menu.Items {
+		if !item.Enabled {
+			continue
+		}
+		bind(KeyModeFunction, strings.ToLower(item.Keys.Function), name)
+		bind(KeyModeVim, item.Keys.Vim, name)
+		bind(KeyModeEmacs, item.Keys.Emacs, name)
+	}
+
+	var out []KeyConflict
+	for mode, keys := range bound {
+		for key, actions := range keys {
+			if len(actions) > 1 {
+				sort.Strings(actions)
+				out = append(out, KeyConflict{Mode: mode, Key: key, Actions: actions})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Mode != out[j].Mode {
+			return out[i].Mode < out[j].Mode
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
 // handleVimKey processes a key press in vim mode and returns the action to take.
 // Returns VimActionNone if the key is not a vim binding.
 func (m *Model) handleVimKey(keyStr string) VimAction {
@@ -232,6 +361,28 @@ func (m *Model) executeVimAction(action VimAction) (tea.Model, tea.Cmd) {
 		return m.vimQuit()
 	case VimActionClearSearch:
 		return m.vimClearSearch()
+	case VimActionEdit:
+		return m.vimOpenInEditor()
+	case VimActionOpenURL:
+		return m.vimOpenURL()
+	case VimActionWrap:
+		return m.vimToggleWrap()
+	case VimActionSearchSelection:
+		return m.vimSearchSelection()
+	case VimActionColumns:
+		return m.vimToggleColumns()
+	case VimActionColumnFilter:
+		return m.vimToggleColumnFilter()
+	case VimActionColumnManager:
+		return m.vimToggleColumnManager()
+	case VimActionAnnotate:
+		return m.vimAnnotate()
+	case VimActionPeek:
+		return m.vimPeek()
+	case VimActionDetail:
+		return m.vimDetail()
+	case VimActionRecent:
+		return m.vimRecent()
 	}
 	return m, nil
 }
@@ -340,6 +491,50 @@ func (m *Model) vimToggleHelp() (tea.Model, tea.Cmd) {
 	return m, menuActionHelp(m)
 }
 
+// vimSearchSelection searches under the selected row (same as F9).
+func (m *Model) vimSearchSelection() (tea.Model, tea.Cmd) {
+	return m, menuActionSearchSelection(m)
+}
+
+// menuActionSearchSelection starts a deep search scoped to the selected
+// row's subtree without navigating into it first. The scope is shown in the
+// search panel title.
+func menuActionSearchSelection(m *Model) tea.Cmd {
+	if m.InputFocused || m.AdvancedSearchActive {
+		return nil
+	}
+	path := m.selectedRowPath()
+	node, err := navigator.Resolve(m.Root, path)
+	if err != nil || path == m.Path || !isCompositeNode(node) {
+		m.ErrMsg = "Selected value has no children to search"
+		m.StatusType = "error"
+		return nil
+	}
+	cmd := menuActionSearch(m)
+	m.AdvancedSearchBasePath = path
+	m.AdvancedSearchScope = path
+	m.logEvent("enter-search:selection " + path)
+	return cmd
+}
+
+// vimToggleWrap toggles value wrapping (same as F7).
+func (m *Model) vimToggleWrap() (tea.Model, tea.Cmd) {
+	return m, menuActionWrap(m)
+}
+
+// menuActionWrap switches the KEY/VALUE table between truncating and
+// wrapping long values.
+func menuActionWrap(m *Model) tea.Cmd {
+	m.WrapValues = !m.WrapValues
+	if m.WrapValues {
+		m.ErrMsg = "Wrapping long values"
+	} else {
+		m.ErrMsg = "Truncating long values"
+	}
+	m.StatusType = "success"
+	return nil
+}
+
 // vimCopy copies current path/expression (same as F5).
 func (m *Model) vimCopy() (tea.Model, tea.Cmd) {
 	return m, menuActionCopy(m)
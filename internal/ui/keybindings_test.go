@@ -2,6 +2,7 @@
 package ui
 
 import (
+	"maps"
 	"strings"
 	"testing"
 
@@ -531,3 +532,145 @@ func TestKeyReleaseMsg_SwallowedByModel(t *testing.T) {
 		t.Errorf("KeyReleaseMsg must not set LastKey: got %q", m2.LastKey)
 	}
 }
+
+func TestWrapToggle(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'w', Text: "w"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 't', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: tea.KeyF7}},
+	} {
+		m := testKeyModeModel(tc.mode)
+		if m.WrapValues {
+			t.Fatalf("%s: wrap should start disabled", tc.mode)
+		}
+		result, _ := m.Update(tc.key)
+		m = result.(*Model)
+		if !m.WrapValues {
+			t.Errorf("%s: expected wrap to be enabled", tc.mode)
+		}
+		if !panelLayoutStateFromModel(m, PanelLayoutModelOptions{}).WrapValues {
+			t.Errorf("%s: expected layout state to carry wrap", tc.mode)
+		}
+		result, _ = m.Update(tc.key)
+		m = result.(*Model)
+		if m.WrapValues {
+			t.Errorf("%s: expected wrap to be disabled again", tc.mode)
+		}
+	}
+}
+
+func TestUpdateKeyBindingsFromConfig_RebindsViewActions(t *testing.T) {
+	vim, emacs := maps.Clone(VimKeyBindings), maps.Clone(EmacsKeyBindings)
+	t.Cleanup(func() { VimKeyBindings, EmacsKeyBindings = vim, emacs })
+
+	menu := MenuConfig{Items: map[string]MenuItem{}}
+	for i, action := range []string{"edit", "open_url", "wrap", "search_selection"} {
+		key := string(rune('1' + i))
+		menu.Items[action] = MenuItem{Action: action, Enabled: true, Keys: MenuKeyBindings{Vim: key, Emacs: "alt+" + key}}
+	}
+	UpdateKeyBindingsFromConfig(menu)
+
+	for key, want := range map[string]VimAction{"1": VimActionEdit, "2": VimActionOpenURL, "3": VimActionWrap, "4": VimActionSearchSelection} {
+		if got := VimKeyBindings[key]; got != want {
+			t.Errorf("vim %q: got %q, want %q", key, got, want)
+		}
+		if got := EmacsKeyBindings["alt+"+key]; got != want {
+			t.Errorf("emacs alt+%s: got %q, want %q", key, got, want)
+		}
+	}
+	for _, old := range []string{"e", "o", "w", "s"} {
+		if action, ok := VimKeyBindings[old]; ok {
+			t.Errorf("default vim key %q should be replaced, still bound to %q", old, action)
+		}
+	}
+}
+
+func TestSearchSelection(t *testing.T) {
+	node := map[string]any{
+		"config": map[string]any{"name": "tea", "nested": map[string]any{"name": "green tea"}},
+		"other":  map[string]any{"name": "tea"},
+		"plain":  1,
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = KeyModeVim
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.Tbl.Focus()
+	m.applyLayout(true)
+
+	// Scalars have nothing to search under.
+	moveCursorToKey(t, &m, "plain")
+	m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	if m.AdvancedSearchActive || m.StatusType != "error" {
+		t.Fatalf("expected an error for a scalar selection, got active=%v status=%q", m.AdvancedSearchActive, m.StatusType)
+	}
+
+	moveCursorToKey(t, &m, "config")
+	m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	if !m.AdvancedSearchActive {
+		t.Fatal("expected search to start")
+	}
+	if m.Path != "" && m.Path != "_" {
+		t.Errorf("search under selection must not navigate, path is %q", m.Path)
+	}
+	if got := m.customSearchTitle(); got != "Search under _.config" {
+		t.Errorf("unexpected search title %q", got)
+	}
+
+	m.AdvancedSearchQuery = "tea"
+	m.AdvancedSearchCommitted = true
+	m.applyAdvancedSearch()
+	if len(m.AdvancedSearchResults) == 0 {
+		t.Fatal("expected results under the selection")
+	}
+	for _, r := range m.AdvancedSearchResults {
+		if strings.HasPrefix(r.FullPath, "other") {
+			t.Errorf("result %q is outside the selected subtree", r.FullPath)
+		}
+	}
+	if p := m.selectedRowPath(); !strings.HasPrefix(p, "_.config") {
+		t.Errorf("result paths should be rooted at the selection, got %q", p)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.AdvancedSearchScope != "" {
+		t.Error("expected Esc to clear the search scope")
+	}
+}
+
+func TestKeyConflicts(t *testing.T) {
+	if got := KeyConflicts(DefaultMenuConfig()); len(got) != 0 {
+		t.Fatalf("default menu has conflicts: %v", got)
+	}
+
+	menu := DefaultMenuConfig()
+	menu.Items = maps.Clone(menu.Items)
+	peek := menu.Items["peek"]
+	peek.Enabled = true
+	peek.Keys = MenuKeyBindings{Function: "F5", Vim: "j", Emacs: "alt+p"}
+	menu.Items["peek"] = peek
+	got := KeyConflicts(menu)
+	want := []KeyConflict{
+		{Mode: KeyModeFunction, Key: "f5", Actions: []string{"copy", "peek"}},
+		{Mode: KeyModeVim, Key: "j", Actions: []string{"down", "peek"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Mode != want[i].Mode || got[i].Key != want[i].Key || strings.Join(got[i].Actions, ",") != strings.Join(want[i].Actions, ",") {
+			t.Errorf("conflict %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	peek.Enabled = false
+	menu.Items["peek"] = peek
+	if got := KeyConflicts(menu); len(got) != 0 {
+		t.Errorf("disabled items do not conflict, got %v", got)
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// KeyEvent is one input event of a key recording (--record-keys): a key
+// press, a paste, or a new terminal size, AtMs milliseconds after the
+// recording started. Key is the key's name, for people reading the log;
+// replaying uses the raw fields.
+type KeyEvent struct {
+	AtMs    int64  `json:"at_ms"`
+	Key     string `json:"key,omitempty"`
+	Code    rune   `json:"code,omitempty"`
+	Shifted rune   `json:"shifted,omitempty"`
+	Base    rune   `json:"base,omitempty"`
+	Mod     int    `json:"mod,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Paste   string `json:"paste,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+}
+
+// keyEventOf returns the event of msg, or false when msg is not input.
+func keyEventOf(msg tea.Msg) (KeyEvent, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		return KeyEvent{Key: msg.String(), Code: msg.Code, Shifted: msg.ShiftedCode, Base: msg.BaseCode, Mod: int(msg.Mod), Text: msg.Text}, true
+	case tea.PasteMsg:
+		return KeyEvent{Key: "paste", Paste: msg.Content}, true
+	case tea.WindowSizeMsg:
+		return KeyEvent{Key: "resize", Width: msg.Width, Height: msg.Height}, true
+	}
+	return KeyEvent{}, false
+}
+
+// Msg returns the message that replays e.
+func (e KeyEvent) Msg() tea.Msg {
+	switch {
+	case e.Width > 0 || e.Height > 0:
+		return tea.WindowSizeMsg{Width: e.Width, Height: e.Height}
+	case e.Paste != "":
+		return tea.PasteMsg{Content: e.Paste}
+	}
+	return tea.KeyPressMsg{Code: e.Code, ShiftedCode: e.Shifted, BaseCode: e.Base, Mod: tea.KeyMod(e.Mod), Text: e.Text}
+}
+
+// KeyRecorder writes the input events of a session to w as they happen, one
+// JSON KeyEvent per line, so a session that crashes is still recorded.
+type KeyRecorder struct {
+	w     io.Writer
+	start time.Time
+	err   error
+}
+
+// NewKeyRecorder returns a recorder writing to w, counting time from now.
+func NewKeyRecorder(w io.Writer) *KeyRecorder {
+	return &KeyRecorder{w: w, start: time.Now()}
+}
+
+// Err returns the first error writing the recording, if any; the recorder
+// stops at the first error.
+func (r *KeyRecorder) Err() error {
+	return r.err
+}
+
+func (r *KeyRecorder) record(msg tea.Msg) {
+	if r == nil || r.err != nil {
+		return
+	}
+	ev, ok := keyEventOf(msg)
+	if !ok {
+		return
+	}
+	ev.AtMs = time.Since(r.start).Milliseconds()
+	line, err := json.Marshal(ev)
+	if err != nil {
+		r.err = err
+		return
+	}
+	_, r.err = r.w.Write(append(line, '\n'))
+}
+
+// ReadKeyLog reads a key recording written by a KeyRecorder.
+func ReadKeyLog(r io.Reader) ([]KeyEvent, error) {
+	var events []KeyEvent
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var ev KeyEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		events = append(events, ev)
+	}
+	return events, sc.Err()
+}
+
+// maxReplayGap caps the pause between two replayed events, so replaying a
+// session does not wait through the time its user spent thinking. No key
+// handling depends on timing, so a shorter pause replays the same session.
+const maxReplayGap = time.Second
+
+// KeyReplay replays a key recording in a running TUI (--replay-keys), at the
+// pace it was recorded. Keys the user presses before it is done are ignored,
+// except ctrl+c, so the replay stays faithful.
+type KeyReplay struct {
+	Events  []KeyEvent
+	next    int
+	feeding bool
+}
+
+// NewKeyReplay returns a replay of events.
+func NewKeyReplay(events []KeyEvent) *KeyReplay {
+	return &KeyReplay{Events: events}
+}
+
+// replayMsg asks the model to replay the next event.
+type replayMsg struct{}
+
+func (r *KeyReplay) pending() bool {
+	return r != nil && r.next < len(r.Events)
+}
+
+// tick waits out the recorded pause before the next event.
+func (r *KeyReplay) tick() tea.Cmd {
+	if !r.pending() {
+		return nil
+	}
+	var prev int64
+	if r.next > 0 {
+		prev = r.Events[r.next-1].AtMs
+	}
+	gap := min(max(time.Duration(r.Events[r.next].AtMs-prev)*time.Millisecond, 0), maxReplayGap)
+	return tea.Tick(gap, func(time.Time) tea.Msg { return replayMsg{} })
+}
+
+// handleReplay replays the next event on a replayMsg and drops the user's
+// keys while the replay runs. It reports whether msg was handled.
+func (m *Model) handleReplay(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
+	r := m.Replay
+	if !r.pending() || r.feeding {
+		return m, nil, false
+	}
+	switch msg := msg.(type) {
+	case replayMsg:
+		ev := r.Events[r.next]
+		r.next++
+		r.feeding = true
+		next, cmd := m.Update(ev.Msg())
+		r.feeding = false
+		return next, tea.Batch(cmd, r.tick()), true
+	case tea.KeyPressMsg:
+		if msg.String() != "ctrl+c" {
+			return m, nil, true
+		}
+	case tea.PasteMsg:
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// ReplayKeyEvents applies events to m at once, like ApplyStartupKeys.
+func ReplayKeyEvents(m *Model, events []KeyEvent) {
+	for _, ev := range events {
+		if updated, _ := m.Update(ev.Msg()); updated != nil {
+			if um, ok := updated.(*Model); ok {
+				*m = *um
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRecordingReplays(t *testing.T) {
+	var log bytes.Buffer
+	m := testRecentModel(KeyModeVim)
+	m.KeyRecorder = NewKeyRecorder(&log)
+	m = press(m,
+		tea.KeyPressMsg{Code: 'j', Text: "j"},
+		tea.KeyPressMsg{Code: 'l', Text: "l"},
+		tea.KeyPressMsg{Code: 'r', Mod: tea.ModAlt},
+	)
+	m.Update(tea.WindowSizeMsg{Width: 90, Height: 30})
+	m.Update(tea.PasteMsg{Content: "pasted"})
+	m.Update(refreshTickMsg{})
+	require.NoError(t, m.KeyRecorder.Err())
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	require.Len(t, lines, 5, "only input is recorded")
+	assert.Contains(t, lines[0], `"key":"j"`)
+	assert.Contains(t, lines[2], `"key":"alt+r"`)
+	assert.Contains(t, lines[3], `"width":90`)
+
+	events, err := ReadKeyLog(&log)
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	assert.Equal(t, tea.KeyPressMsg{Code: 'r', Mod: tea.ModAlt}, events[2].Msg())
+	assert.Equal(t, tea.WindowSizeMsg{Width: 90, Height: 30}, events[3].Msg())
+	assert.Equal(t, tea.PasteMsg{Content: "pasted"}, events[4].Msg())
+
+	replayed := testRecentModel(KeyModeVim)
+	ReplayKeyEvents(replayed, events)
+	assert.Equal(t, m.Path, replayed.Path)
+	assert.Equal(t, m.Tbl.Cursor(), replayed.Tbl.Cursor())
+	assert.Equal(t, 90, replayed.WinWidth)
+}
+
+func TestKeyReplayInTUI(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	m.Replay = NewKeyReplay([]KeyEvent{
+		{AtMs: 10, Code: 'j', Text: "j"},
+		{AtMs: 5000, Code: 'l', Text: "l"},
+	})
+	require.NotNil(t, m.Init())
+
+	// The user's keys wait for the replay, except ctrl+c.
+	m.Update(tea.KeyPressMsg{Code: 'G', Text: "G"})
+	assert.Zero(t, m.Tbl.Cursor())
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl})
+	assert.NotNil(t, cmd)
+
+	next, cmd := m.Update(replayMsg{})
+	m = next.(*Model)
+	assert.Equal(t, 1, m.Tbl.Cursor())
+	assert.NotNil(t, cmd, "the next event is scheduled")
+	next, _ = m.Update(replayMsg{})
+	m = next.(*Model)
+	assert.Equal(t, "_.b", formatPathForDisplay(m.Path))
+	assert.False(t, m.Replay.pending())
+
+	m.Update(tea.KeyPressMsg{Code: 'h', Text: "h"})
+	assert.Empty(t, m.Path, "keys work again once the replay is done")
+}
+
+func TestKeyReplayGap(t *testing.T) {
+	r := NewKeyReplay([]KeyEvent{{AtMs: 0}, {AtMs: 60000}})
+	assert.NotNil(t, r.tick())
+	r.next = 2
+	assert.Nil(t, r.tick())
+	assert.Nil(t, (*KeyReplay)(nil).tick())
+}
+
+func TestReadKeyLogErrors(t *testing.T) {
+	_, err := ReadKeyLog(strings.NewReader("{\"at_ms\":1,\"key\":\"j\"}\n\nnot json\n"))
+	assert.ErrorContains(t, err, "line 3:")
+}
@@ -0,0 +1,63 @@
+package ui
+
+import "maps"
+
+// kioskDisabledActions are the menu actions kiosk mode turns off: everything
+// that edits, leaves the viewer, or hands data to another program. Quit stays,
+// without printing the current expression result.
+var kioskDisabledActions = map[string]bool{
+	"expr_toggle": true,
+	"copy":        true,
+	"edit":        true,
+	"open_url":    true,
+	"annotate":    true,
+}
+
+// SetKiosk turns kiosk mode on or off. Kiosk mode is read-only browsing for
+// restricted dashboards and demos: navigation and search only. It turns off
+// expression editing, quitting with output, the clipboard, and every shell-out
+// (editor, browser, desktop notifications). Call it after the other settings,
+// since it overrides them.
+func (m *Model) SetKiosk(on bool) {
+	m.Kiosk = on
+	if !on {
+		return
+	}
+	m.AllowEditInput = false
+	m.InputFocused = false
+	m.Notify = false
+	m.IdlePrint = false
+}
+
+// kioskRefuses reports whether kiosk mode rules out what, an action named
+// for the status bar, and says so there.
+func (m *Model) kioskRefuses(what string) bool {
+	if !m.Kiosk {
+		return false
+	}
+	m.ErrMsg = what + " is not available in kiosk mode"
+	m.StatusType = "error"
+	return true
+}
+
+// KioskMenu returns menu with the items kiosk mode turns off disabled, so the
+// footer and key bindings leave them out.
+func KioskMenu(menu MenuConfig) MenuConfig {
+	out := menu
+	out.Items = maps.Clone(menu.Items)
+	for name, item := range out.Items {
+		if kioskDisabledActions[item.Action] {
+			item.Enabled = false
+			out.Items[name] = item
+		}
+	}
+	for _, item := range []*MenuItem{
+		&out.F1, &out.F2, &out.F3, &out.F4, &out.F5, &out.F6,
+		&out.F7, &out.F8, &out.F9, &out.F10, &out.F11, &out.F12,
+	} {
+		if kioskDisabledActions[item.Action] {
+			item.Enabled = false
+		}
+	}
+	return out
+}
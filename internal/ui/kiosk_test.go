@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetKiosk(t *testing.T) {
+	m := InitialModel(map[string]any{"name": "kvx", "url": "https://example.com"})
+	m.Notify = true
+	m.IdlePrint = true
+	m.SetKiosk(true)
+	assert.False(t, m.AllowEditInput)
+	assert.False(t, m.Notify)
+	assert.False(t, m.IdlePrint)
+
+	for name, action := range map[string]MenuAction{
+		"copy": menuActionCopy, "edit": menuActionEdit, "open_url": menuActionOpenURL, "annotate": menuActionAnnotate,
+	} {
+		m.ErrMsg = ""
+		assert.Nil(t, action(&m), name)
+		assert.Contains(t, m.ErrMsg, "not available in kiosk mode", name)
+	}
+	assert.False(t, m.AnnotationInput)
+
+	m.PathInput.SetValue("_.name")
+	cmd := menuActionQuit(&m)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+	assert.Empty(t, m.PendingCLIExpr, "quitting prints nothing")
+}
+
+func TestKioskMenu(t *testing.T) {
+	orig := DefaultMenuConfig()
+	menu := KioskMenu(orig)
+	for name, item := range menu.Items {
+		want := orig.Items[name].Enabled && !kioskDisabledActions[item.Action]
+		assert.Equal(t, want, item.Enabled, name)
+	}
+	assert.True(t, menu.Items["search"].Enabled)
+	assert.True(t, menu.Items["quit"].Enabled)
+	assert.False(t, menu.Items["copy"].Enabled)
+	assert.True(t, DefaultMenuConfig().Items["copy"].Enabled, "the menu passed in is unchanged")
+}
+
+func TestKiosk_StatusActions(t *testing.T) {
+	var copied []string
+	orig := copyToClipboardFn
+	t.Cleanup(func() { copyToClipboardFn = orig })
+	copyToClipboardFn = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+
+	m := &Model{DisplaySchema: testStatusSchema(), Kiosk: true, WinWidth: 80, WinHeight: 24}
+	m.updateViewMode(testStatusData())
+	require.NotNil(t, m.StatusViewState)
+	sv, _ := m.StatusViewState.executeAction(m.StatusViewState.Config.Actions[0])
+	assert.Empty(t, copied)
+	assert.Contains(t, sv.FlashMsg, "not available in kiosk mode")
+}
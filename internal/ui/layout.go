@@ -37,6 +37,15 @@ const (
 	MinInputWidth          = 20
 	DefaultKeyColWidth     = 30
 	DefaultValueColWidth   = 60
+
+	// CompactLayoutWidth and CompactLayoutHeight are the smallest window the
+	// bordered panel layout fits in. Smaller windows get the compact layout.
+	CompactLayoutWidth  = 40
+	CompactLayoutHeight = 10
+	// TooSmallWidth and TooSmallHeight are the smallest window the compact
+	// layout can use; below them only a "terminal too small" notice is shown.
+	TooSmallWidth  = 16
+	TooSmallHeight = 3
 )
 
 // NewLayoutManager creates a new layout manager
@@ -1,11 +1,73 @@
 package ui
 
-import "github.com/oakwood-commons/kvx/internal/limiter"
+import (
+	"fmt"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/limiter"
+	"github.com/oakwood-commons/kvx/pkg/loader"
+)
 
-//nolint:unused // Variable is set by SetLimiterConfig but may be used in future versions.
 var activeLimiterConfig limiter.Config
 
 // SetLimiterConfig stores the limiter configuration for subsequent TUI sessions.
 func SetLimiterConfig(cfg limiter.Config) {
 	activeLimiterConfig = cfg
 }
+
+// limitRoot shows the records of the root that the limiter configuration
+// keeps (--head, --tail, --sample), holding the whole document in
+// LimitedFull until L loads it.
+func (m *Model) limitRoot(cfg limiter.Config) {
+	if !cfg.IsActive() {
+		return
+	}
+	total := recordCount(m.Root)
+	limited := cfg.Apply(m.Root)
+	if recordCount(limited) >= total {
+		return
+	}
+	m.LimitedFull = m.Root
+	m.Root = limited
+	m.Node = limited
+	m.NavigateTo(limited, "")
+}
+
+// limitedMoreRows is the sentinel row under a limited root, such as
+// "… 1,000 of 2,000,000 records shown (press L to load all)", or "".
+func (m *Model) limitedMoreRows() string {
+	if m.LimitedFull == nil || m.Path != "" {
+		return ""
+	}
+	return fmt.Sprintf("… %s of %s records shown (press L to load all)",
+		formatter.GroupDigits(recordCount(m.Root)), formatter.GroupDigits(recordCount(m.LimitedFull)))
+}
+
+// loadAllRecords replaces the limited root with the whole document. Row
+// positions differ between the two, so the cursor returns to the top. The
+// records left out were not decoded at startup, so eager decoding runs on
+// them now.
+func (m *Model) loadAllRecords() {
+	m.Root = m.LimitedFull
+	m.LimitedFull = nil
+	if m.AllowDecode && m.AutoDecode == "eager" {
+		m.Root = loader.RecursiveDecode(m.Root)
+	}
+	m.ChunkLoaded = nil
+	m.NavigateTo(m.Root, "")
+	m.syncPathInputWithCursor()
+	m.ErrMsg = fmt.Sprintf("Loaded all %s records", formatter.GroupDigits(recordCount(m.Root)))
+	m.StatusType = "success"
+}
+
+// recordCount returns the number of records the limits count in v: the
+// items of an array or the keys of a map.
+func recordCount(v interface{}) int {
+	switch node := v.(type) {
+	case []interface{}:
+		return len(node)
+	case map[string]interface{}:
+		return len(node)
+	}
+	return 0
+}
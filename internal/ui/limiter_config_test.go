@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/internal/limiter"
+)
+
+func testLimitedModel(items int, cfg limiter.Config) *Model {
+	node := make([]interface{}, items)
+	for i := range node {
+		node[i] = map[string]interface{}{"id": i}
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = KeyModeVim
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.Tbl.Focus()
+	m.limitRoot(cfg)
+	m.applyLayout(true)
+	return &m
+}
+
+func TestLimitedRootLoadsAllOnL(t *testing.T) {
+	m := testLimitedModel(50, limiter.Config{Sample: 5, Seed: 1})
+	require.NotNil(t, m.LimitedFull)
+	assert.Len(t, m.Tbl.Rows(), 5)
+	assert.Equal(t, "… 5 of 50 records shown (press L to load all)", m.chunkMoreRows())
+	assert.Contains(t, stripANSI(m.View().Content), "… 5 of 50 records shown (press L to load all)")
+
+	m.Update(tea.KeyPressMsg{Code: 'L', Text: "L"})
+	assert.Nil(t, m.LimitedFull)
+	assert.Len(t, m.Tbl.Rows(), 50)
+	assert.Equal(t, "Loaded all 50 records", m.ErrMsg)
+	assert.Empty(t, m.chunkMoreRows())
+}
+
+func TestLimitedRootChunksThenLoadsAll(t *testing.T) {
+	node := make([]interface{}, 40)
+	for i := range node {
+		node[i] = i
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = KeyModeVim
+	m.InputFocused = false
+	m.ChunkSize = 10
+	m.ChunkThreshold = 20
+	m.limitRoot(limiter.Config{Limit: 25})
+
+	l := tea.KeyPressMsg{Code: 'L', Text: "L"}
+	assert.Len(t, m.Tbl.Rows(), 10)
+	m.Update(l)
+	m.Update(l)
+	assert.Len(t, m.Tbl.Rows(), 25)
+	assert.Equal(t, "… 25 of 40 records shown (press L to load all)", m.chunkMoreRows())
+	m.Update(l)
+	assert.Nil(t, m.LimitedFull)
+	assert.Len(t, m.Tbl.Rows(), 10, "the whole array is shown in chunks again")
+}
+
+func TestLimitedRootOnlyAtRoot(t *testing.T) {
+	m := testLimitedModel(50, limiter.Config{Tail: 3})
+	m.NavigateTo(m.Root.([]interface{})[0], "_[0]") //nolint:forcetypeassert
+	assert.Empty(t, m.chunkMoreRows())
+	assert.False(t, m.handleChunkKey("L"))
+	assert.NotNil(t, m.LimitedFull)
+}
+
+func TestLimitRootKeepsShortRoot(t *testing.T) {
+	m := testLimitedModel(5, limiter.Config{Limit: 10})
+	assert.Nil(t, m.LimitedFull, "nothing was left out")
+	m = testLimitedModel(5, limiter.Config{})
+	assert.Nil(t, m.LimitedFull)
+}
@@ -2,14 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // ListViewModel holds state for the card-list rendering of an array of objects.
@@ -27,49 +29,94 @@ type ListViewModel struct {
 
 // ListViewItem is a pre-computed renderable card derived from an object.
 type ListViewItem struct {
-	Index      int      // Original array index
-	Title      string   // Title text (from TitleField)
+	Index      int      // Original array index, or position in a map collection
+	Key        string   // Map key of the element, for a map-valued collection
+	Keyed      bool     // Whether the item is an element of a map collection
+	Title      string   // Title text (from TitleField, or the map key)
 	Subtitle   string   // Subtitle text (from SubtitleField)
 	Badges     []string // Badge labels (from BadgeFields)
+	States     []string // State of each badge (from BadgeStates), or ""
 	Secondary  []string // Secondary field values (from SecondaryFields)
 	SearchText string   // Pre-computed concatenation of all field values for deep search
 }
 
-// buildListViewModel creates a ListViewModel from an array node using the display schema.
+// ListKeyField is the pseudo-field naming the map key of an element of a
+// map-valued collection, for use in any of the list fields.
+const ListKeyField = "$key"
+
+// field returns the value of a list field of obj, the element of item,
+// resolving ListKeyField to the map key.
+func (item ListViewItem) field(obj map[string]interface{}, name string) interface{} {
+	if name == ListKeyField && item.Keyed {
+		return item.Key
+	}
+	return obj[name]
+}
+
+// buildListViewModel creates a ListViewModel from an array node, or a map
+// node keyed by ID, using the display schema.
 func buildListViewModel(node interface{}, schema *DisplaySchema, width, height int) *ListViewModel {
-	arr, ok := node.([]interface{})
-	if !ok || schema == nil || schema.List == nil || schema.List.TitleField == "" {
+	if schema == nil || schema.List == nil {
+		return nil
+	}
+	var elems []ListViewItem
+	var objs []map[string]interface{}
+	switch n := node.(type) {
+	case []interface{}:
+		if schema.List.TitleField == "" {
+			return nil
+		}
+		for i, elem := range n {
+			if obj, ok := elem.(map[string]interface{}); ok {
+				elems = append(elems, ListViewItem{Index: i})
+				objs = append(objs, obj)
+			}
+		}
+	case map[string]interface{}:
+		for i, k := range navigator.OrderedKeys(n) {
+			if obj, ok := n[k].(map[string]interface{}); ok {
+				elems = append(elems, ListViewItem{Index: i, Key: k, Keyed: true})
+				objs = append(objs, obj)
+			}
+		}
+	default:
 		return nil
 	}
 
-	items := make([]ListViewItem, 0, len(arr))
-	for i, elem := range arr {
-		obj, ok := elem.(map[string]interface{})
-		if !ok {
-			continue
+	items := make([]ListViewItem, 0, len(elems))
+	for i, item := range elems {
+		obj := objs[i]
+		if schema.List.TitleField != "" {
+			item.Title = formatter.Stringify(item.field(obj, schema.List.TitleField))
+		}
+		if item.Title == "" && item.Keyed {
+			item.Title = item.Key
 		}
-		item := ListViewItem{Index: i}
-		item.Title = formatter.Stringify(obj[schema.List.TitleField])
 		if schema.List.SubtitleField != "" {
-			item.Subtitle = formatter.Stringify(obj[schema.List.SubtitleField])
+			item.Subtitle = formatter.Stringify(item.field(obj, schema.List.SubtitleField))
 		}
 		for _, bf := range schema.List.BadgeFields {
-			val := obj[bf]
+			states := schema.List.BadgeStates[bf]
+			addBadge := func(b string) {
+				item.Badges = append(item.Badges, b)
+				item.States = append(item.States, states.State(b))
+			}
+			val := item.field(obj, bf)
 			switch v := val.(type) {
 			case []interface{}:
 				for _, elem := range v {
-					item.Badges = append(item.Badges, formatter.Stringify(elem))
+					addBadge(formatter.Stringify(elem))
 				}
 			case string:
-				item.Badges = append(item.Badges, v)
+				addBadge(v)
 			default:
 				if val != nil {
-					item.Badges = append(item.Badges, formatter.Stringify(val))
+					addBadge(formatter.Stringify(val))
 				}
 			}
 		}
 		for _, sf := range schema.List.SecondaryFields {
-			val := obj[sf]
+			val := item.field(obj, sf)
 			if val != nil {
 				item.Secondary = append(item.Secondary, formatter.Stringify(val))
 			}
@@ -77,6 +124,9 @@ func buildListViewModel(node interface{}, schema *DisplaySchema, width, height i
 
 		// Build SearchText from all field values for deep search.
 		var parts []string
+		if item.Keyed {
+			parts = append(parts, item.Key)
+		}
 		keys := make([]string, 0, len(obj))
 		for k := range obj {
 			keys = append(keys, k)
@@ -230,6 +280,12 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 			}
 		}
 	}
+	// Pull earlier items back in while the tail still fits, so a taller panel
+	// (e.g. after a resize) does not leave blank space below the last item.
+	for lv.ScrollTop > 0 && lv.ScrollTop-1+countVisibleItems(items, lv.ScrollTop-1, availableHeight, subtitleLines, maxSubWidth, hasSecondary) >= len(items) {
+		lv.ScrollTop--
+		visibleCount = countVisibleItems(items, lv.ScrollTop, availableHeight, subtitleLines, maxSubWidth, hasSecondary)
+	}
 
 	// Styles
 	titleStyle := lipgloss.NewStyle().Bold(true)
@@ -278,11 +334,18 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 		badgeStr := ""
 		if len(item.Badges) > 0 {
 			badges := make([]string, 0, len(item.Badges))
-			for _, b := range item.Badges {
+			for j, b := range item.Badges {
+				style := badgeStyle
+				if state := item.States[j]; state != "" {
+					b = formatter.StateIcon(state) + " " + b
+					if st, ok := formatter.StateStyle(state); ok {
+						style = style.Foreground(st.GetForeground())
+					}
+				}
 				if noColor {
 					badges = append(badges, " "+b+" ")
 				} else {
-					badges = append(badges, badgeStyle.Render(" "+b+" "))
+					badges = append(badges, style.Render(" "+b+" "))
 				}
 			}
 			badgeStr = " " + strings.Join(badges, " ")
@@ -290,7 +353,7 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 
 		titleLine := marker + titleRendered + badgeStr
 		// Clamp to width
-		if runewidth.StringWidth(stripANSI(titleLine)) > contentWidth+2 {
+		if textwidth.Width(stripANSI(titleLine)) > contentWidth+2 {
 			titleLine = clampANSITextWidth(titleLine, contentWidth+2)
 		}
 		lines = append(lines, titleLine)
@@ -305,10 +368,11 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 				subLines = subLines[:subtitleLines]
 				// Add ellipsis to last line
 				last := subLines[len(subLines)-1]
-				if runewidth.StringWidth(last) > maxSubWidth-3 {
-					last = runewidth.Truncate(last, maxSubWidth-3, "") + "..."
+				ellipsis := formatter.Ellipsis()
+				if room := maxSubWidth - textwidth.Width(ellipsis); textwidth.Width(last) > room {
+					last = textwidth.Truncate(last, room, "") + ellipsis
 				} else {
-					last += "..."
+					last += ellipsis
 				}
 				subLines[len(subLines)-1] = last
 			}
@@ -321,7 +385,7 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 		// Secondary fields line
 		if len(item.Secondary) > 0 {
 			secondaryLine := "    " + subtitleStyle.Render(strings.Join(item.Secondary, " · "))
-			if runewidth.StringWidth(stripANSI(secondaryLine)) > contentWidth+2 {
+			if textwidth.Width(stripANSI(secondaryLine)) > contentWidth+2 {
 				secondaryLine = clampANSITextWidth(secondaryLine, contentWidth+2)
 			}
 			lines = append(lines, secondaryLine)
@@ -338,7 +402,7 @@ func renderListView(lv *ListViewModel, schema *DisplaySchema, noColor bool) stri
 
 // wrapAtWidth wraps text at the given width, breaking on word boundaries.
 func wrapAtWidth(text string, width int) string {
-	if width <= 0 || runewidth.StringWidth(text) <= width {
+	if width <= 0 || textwidth.Width(text) <= width {
 		return text
 	}
 
@@ -351,7 +415,7 @@ func wrapAtWidth(text string, width int) string {
 	current := words[0]
 	for _, word := range words[1:] {
 		test := current + " " + word
-		if runewidth.StringWidth(test) > width {
+		if textwidth.Width(test) > width {
 			lines = append(lines, current)
 			current = word
 		} else {
@@ -384,19 +448,70 @@ func isHomogeneousObjectArray(node interface{}) bool {
 	return true
 }
 
-// collectObjectKeys returns sorted keys from a map, excluding hidden fields.
+// isListCollection reports whether node renders as a card list under list:
+// a homogeneous array of objects when list has a TitleField, or a map of
+// objects keyed by ID. A map qualifies only when each value has one of the
+// list's fields, so plain nested objects keep the table view.
+func isListCollection(node interface{}, list *ListDisplayConfig) bool {
+	if list == nil {
+		return false
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return list.TitleField != "" && isHomogeneousObjectArray(node)
+	}
+	if len(obj) == 0 {
+		return false
+	}
+	fields := slices.Concat([]string{list.TitleField, list.SubtitleField}, list.BadgeFields, list.SecondaryFields)
+	fields = slices.DeleteFunc(fields, func(f string) bool { return f == "" || f == ListKeyField })
+	for _, v := range obj {
+		elem, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if len(fields) > 0 && !slices.ContainsFunc(fields, func(f string) bool {
+			_, has := elem[f]
+			return has
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// listItemChild returns the path below basePath and the value of item's
+// element in the collection node it was built from.
+func listItemChild(node interface{}, basePath string, item ListViewItem) (string, interface{}, bool) {
+	switch n := node.(type) {
+	case []interface{}:
+		if item.Keyed || item.Index >= len(n) {
+			return "", nil, false
+		}
+		return buildPathWithKey(basePath, fmt.Sprintf("[%d]", item.Index)), n[item.Index], true
+	case map[string]interface{}:
+		v, ok := n[item.Key]
+		if !item.Keyed || !ok {
+			return "", nil, false
+		}
+		return buildPathWithKey(basePath, item.Key), v, true
+	}
+	return "", nil, false
+}
+
+// collectObjectKeys returns the keys of a map in the active key order,
+// excluding hidden fields.
 func collectObjectKeys(obj map[string]interface{}, hidden []string) []string {
 	hiddenSet := make(map[string]bool, len(hidden))
 	for _, h := range hidden {
 		hiddenSet[h] = true
 	}
 	keys := make([]string, 0, len(obj))
-	for k := range obj {
+	for _, k := range navigator.OrderedKeys(obj) {
 		if !hiddenSet[k] {
 			keys = append(keys, k)
 		}
 	}
-	sort.Strings(keys)
 	return keys
 }
 
@@ -409,6 +524,17 @@ func (lv *ListViewModel) Init() tea.Cmd                { return nil }
 func (lv *ListViewModel) SearchTitle() string          { return "" } // caller sets based on ListPanelMode
 func (lv *ListViewModel) FlashMessage() (string, bool) { return "", false }
 
+// reflow adapts the list to a new window size. The selected item keeps its
+// relative position in the panel: its distance from the top is scaled by the
+// change in height, and renderListView settles the rest.
+func (lv *ListViewModel) reflow(oldHeight, newHeight int) {
+	if oldHeight <= 0 || newHeight <= 0 || lv.Selected < lv.ScrollTop {
+		return
+	}
+	offset := (lv.Selected - lv.ScrollTop) * newHeight / oldHeight
+	lv.ScrollTop = max(lv.Selected-offset, 0)
+}
+
 func (lv *ListViewModel) Render(width, height int, noColor bool) string {
 	lv.Width = width
 	lv.Height = height
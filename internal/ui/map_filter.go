@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+)
+
+// mapFilterTerm is one space-separated term of a map filter query.
+type mapFilterTerm struct {
+	negate bool
+	kind   byte // 0 = key prefix, '=' = value substring, ':' = type
+	text   string
+}
+
+// parseMapFilter splits a map filter query into terms. Every term must match
+// for a key to be kept:
+//
+//	abc     key starts with abc
+//	=abc    value contains abc
+//	:type   value has the type (string, int, double, number, bool, list, map, null, bytes)
+//	!term   negates any of the above
+//	"a b"   quoted text may hold spaces or start with =, : or !, e.g. "=x"
+//	        for keys starting with =x or !="a b" for values without a b;
+//	        inside the quotes \" is a quote and \\ a backslash
+//
+// Matching is case-insensitive. An unclosed quote runs to the end of the
+// query so the filter applies while the text is still being typed.
+func parseMapFilter(query string) []mapFilterTerm {
+	var terms []mapFilterTerm
+	rest := strings.ToLower(query)
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			return terms
+		}
+		var t mapFilterTerm
+		if rest[0] == '!' {
+			t.negate = true
+			rest = rest[1:]
+		}
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			t.kind = rest[0]
+			rest = rest[1:]
+		}
+		t.text, rest = mapFilterText(rest)
+		if t.text == "" {
+			// A bare "!", "=" or ":" while typing matches everything.
+			continue
+		}
+		terms = append(terms, t)
+	}
+}
+
+// mapFilterText reads the text of one term from the start of s and returns
+// it with the rest of the query: up to the next space, or the contents of a
+// double-quoted string.
+func mapFilterText(s string) (text, rest string) {
+	if !strings.HasPrefix(s, `"`) {
+		if i := strings.IndexAny(s, " \t"); i >= 0 {
+			return s[:i], s[i:]
+		}
+		return s, ""
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), ""
+}
+
+// matchMapFilter reports whether key and value satisfy every term.
+func matchMapFilter(terms []mapFilterTerm, key string, value interface{}) bool {
+	for _, t := range terms {
+		var ok bool
+		switch t.kind {
+		case '=':
+			ok = strings.Contains(strings.ToLower(formatter.Stringify(value)), t.text)
+		case ':':
+			ok = matchFilterType(t.text, value)
+		default:
+			ok = strings.HasPrefix(strings.ToLower(key), t.text)
+		}
+		if ok == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilterType reports whether value has the named type. Common aliases
+// (array, object, float, boolean, nil) are accepted, and number matches any
+// numeric type.
+func matchFilterType(name string, value interface{}) bool {
+	label := nodeTypeLabel(value)
+	if value == nil {
+		label = "null"
+	}
+	switch name {
+	case "array":
+		name = "list"
+	case "object":
+		name = "map"
+	case "float":
+		name = "double"
+	case "boolean":
+		name = "bool"
+	case "str":
+		name = "string"
+	case "nil":
+		name = "null"
+	case "number", "num":
+		return label == "int" || label == "uint" || label == "double"
+	}
+	return label == name
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchMapFilter(t *testing.T) {
+	node := map[string]interface{}{
+		"name":   "demo",
+		"test_a": 1,
+		"test_b": nil,
+		"tags":   []interface{}{"a"},
+		"status": "Error occurred",
+		"owner":  map[string]interface{}{"id": 1},
+		"ratio":  2.5,
+	}
+	match := func(query string) []string {
+		terms := parseMapFilter(query)
+		var keys []string
+		for _, k := range []string{"name", "owner", "ratio", "status", "tags", "test_a", "test_b"} {
+			if matchMapFilter(terms, k, node[k]) {
+				keys = append(keys, k)
+			}
+		}
+		return keys
+	}
+
+	assert.Equal(t, []string{"test_a", "test_b"}, match("TE"), "key prefix, case-insensitive")
+	assert.Equal(t, []string{"status"}, match("=error"))
+	assert.Equal(t, []string{"test_b"}, match(":null"))
+	assert.Equal(t, []string{"tags"}, match(":array"))
+	assert.Equal(t, []string{"owner"}, match(":map"))
+	assert.Equal(t, []string{"ratio", "test_a"}, match(":number"))
+	assert.Equal(t, []string{"name", "owner", "ratio", "status", "tags"}, match("!test"))
+	assert.Equal(t, []string{"test_a"}, match(":number !ratio"), "terms combine")
+	assert.Equal(t, []string{"name", "owner", "ratio", "status", "tags", "test_a", "test_b"}, match("! = :"), "incomplete terms match everything")
+}
+
+func TestMapFilterTypedQuery(t *testing.T) {
+	m := testKeyModeModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	for _, r := range "!alpha :int" {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	assert.Equal(t, "!alpha :int", m.MapFilterQuery)
+	assert.Equal(t, []string{"beta", "charlie", "delta"}, m.AllRowKeys)
+}
+
+func TestMatchMapFilter_QuotedTerms(t *testing.T) {
+	node := map[string]interface{}{
+		"my key":   "plain",
+		"my other": "error occurred",
+		"=weird":   1,
+		":type":    2,
+		"!bang":    3,
+		`a"b`:      4,
+	}
+	keys := []string{"!bang", ":type", "=weird", `a"b`, "my key", "my other"}
+	match := func(query string) []string {
+		terms := parseMapFilter(query)
+		var out []string
+		for _, k := range keys {
+			if matchMapFilter(terms, k, node[k]) {
+				out = append(out, k)
+			}
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"my key"}, match(`"my k"`), "quoted text keeps spaces")
+	assert.Equal(t, []string{"my key"}, match(`"my k`), "an unclosed quote runs to the end")
+	assert.Equal(t, []string{"=weird"}, match(`"=w"`))
+	assert.Equal(t, []string{":type"}, match(`":t"`))
+	assert.Equal(t, []string{"!bang"}, match(`"!b"`))
+	assert.Equal(t, []string{`a"b`}, match(`"a\"b"`))
+	assert.Equal(t, []string{"my other"}, match(`="error occ"`), "prefixes apply to quoted text")
+	assert.Equal(t, []string{"my key"}, match(`my !="error occ"`))
+	assert.Equal(t, keys, match(`""`), "empty quotes match everything")
+}
+
+func TestMapFilterTypedQuotes(t *testing.T) {
+	m := testKeyModeModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	for _, r := range `"al` {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	assert.Equal(t, `"al`, m.MapFilterQuery)
+	assert.Equal(t, []string{"alpha"}, m.AllRowKeys)
+}
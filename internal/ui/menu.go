@@ -130,27 +130,49 @@ func fallbackDefaultMenuConfig() MenuConfig {
 	copyItem := MenuItem{Label: "copy", Action: "copy", Enabled: true, HelpText: "Copy current expression/path", Keys: MenuKeyBindings{Function: "f5", Vim: "y", Emacs: "alt+w"}}
 	exprItem := MenuItem{Label: "expr", Action: "expr_toggle", Enabled: true, HelpText: "Toggle expression input", Keys: MenuKeyBindings{Function: "f6", Vim: ":", Emacs: "alt+x"}}
 	quitItem := MenuItem{Label: "quit", Action: "quit", Enabled: true, HelpText: "Quit", Keys: MenuKeyBindings{Function: "f10", Vim: "q", Emacs: "ctrl+q"}}
+	editItem := MenuItem{Label: "edit", Action: "edit", Enabled: true, HelpText: "Open source in $EDITOR", Keys: MenuKeyBindings{Function: "f2", Vim: "e", Emacs: "alt+e"}}
+	wrapItem := MenuItem{Label: "wrap", Action: "wrap", Enabled: true, HelpText: "Wrap long values", Keys: MenuKeyBindings{Function: "f7", Vim: "w", Emacs: "alt+t"}}
+	openURLItem := MenuItem{Label: "open", Action: "open_url", Enabled: true, HelpText: "Open URL in browser", Keys: MenuKeyBindings{Function: "f8", Vim: "o", Emacs: "alt+o"}}
+	searchSelItem := MenuItem{Label: "search sel", Action: "search_selection", Enabled: true, HelpText: "Search under selection", Keys: MenuKeyBindings{Function: "f9", Vim: "s", Emacs: "alt+s"}}
+	columnsItem := MenuItem{Label: "columns", Action: "columns", Enabled: true, HelpText: "Toggle columnar view", Keys: MenuKeyBindings{Function: "f11", Vim: "v", Emacs: "alt+v"}}
+	colFilterItem := MenuItem{Label: "col filter", Action: "column_filter", Enabled: true, HelpText: "Filter columns", Keys: MenuKeyBindings{Function: "ctrl+f", Vim: "ctrl+f", Emacs: "alt+f"}}
+	colManagerItem := MenuItem{Label: "col manager", Action: "column_manager", Enabled: true, HelpText: "Show, hide, and reorder columns", Keys: MenuKeyBindings{Function: "ctrl+o", Vim: "c", Emacs: "alt+c"}}
+	peekItem := MenuItem{Label: "peek", Action: "peek", Enabled: true, HelpText: "Show the selected value whole", Keys: MenuKeyBindings{Function: "ctrl+v", Vim: "p", Emacs: "alt+p"}}
+	recentItem := MenuItem{Label: "recent", Action: "recent", Enabled: true, HelpText: "Jump back to a recently visited path", Keys: MenuKeyBindings{Function: "ctrl+r", Vim: "r", Emacs: "alt+r"}}
+	detailItem := MenuItem{Label: "detail", Action: "detail", Enabled: true, HelpText: "Open the detail view of the selected element", Keys: MenuKeyBindings{Function: "ctrl+d", Vim: "d", Emacs: "alt+d"}}
+	annotateItem := MenuItem{Label: "annotate", Action: "annotate", Enabled: true, HelpText: "Annotate the selected row", Keys: MenuKeyBindings{Function: "ctrl+t", Vim: "a", Emacs: "alt+a"}}
 
 	menu := MenuConfig{
 		F1:  helpItem,
-		F2:  MenuItem{},
+		F2:  editItem,
 		F3:  searchItem,
 		F4:  filterItem,
 		F5:  copyItem,
 		F6:  exprItem,
-		F7:  MenuItem{},
-		F8:  MenuItem{},
-		F9:  MenuItem{},
+		F7:  wrapItem,
+		F8:  openURLItem,
+		F9:  searchSelItem,
 		F10: quitItem,
-		F11: MenuItem{},
+		F11: columnsItem,
 		F12: MenuItem{},
 		Items: map[string]MenuItem{
-			"help":   helpItem,
-			"search": searchItem,
-			"filter": filterItem,
-			"copy":   copyItem,
-			"expr":   exprItem,
-			"quit":   quitItem,
+			"help":             helpItem,
+			"search":           searchItem,
+			"filter":           filterItem,
+			"copy":             copyItem,
+			"expr":             exprItem,
+			"quit":             quitItem,
+			"edit":             editItem,
+			"wrap":             wrapItem,
+			"open_url":         openURLItem,
+			"search_selection": searchSelItem,
+			"columns":          columnsItem,
+			"column_filter":    colFilterItem,
+			"column_manager":   colManagerItem,
+			"annotate":         annotateItem,
+			"peek":             peekItem,
+			"detail":           detailItem,
+			"recent":           recentItem,
 		},
 	}
 	// Build key-action maps for fallback config
@@ -179,15 +201,26 @@ type MenuAction func(*Model) tea.Cmd
 // defaultMenuActions returns the built-in menu action handlers.
 func defaultMenuActions() map[string]MenuAction {
 	return map[string]MenuAction{
-		"help":        menuActionHelp,
-		"expr_toggle": menuActionExprToggle,
-		"search":      menuActionSearch,
-		"filter":      menuActionFilter,
-		"copy":        menuActionCopy,
-		"quit":        menuActionQuit,
-		"custom":      menuActionCustom,
-		"noop":        func(_ *Model) tea.Cmd { return nil },
-		"":            func(_ *Model) tea.Cmd { return nil },
+		"help":             menuActionHelp,
+		"expr_toggle":      menuActionExprToggle,
+		"search":           menuActionSearch,
+		"filter":           menuActionFilter,
+		"copy":             menuActionCopy,
+		"quit":             menuActionQuit,
+		"edit":             menuActionEdit,
+		"open_url":         menuActionOpenURL,
+		"wrap":             menuActionWrap,
+		"search_selection": menuActionSearchSelection,
+		"columns":          menuActionColumns,
+		"column_filter":    menuActionColumnFilter,
+		"column_manager":   menuActionColumnManager,
+		"annotate":         menuActionAnnotate,
+		"peek":             menuActionPeek,
+		"detail":           menuActionDetail,
+		"recent":           menuActionRecent,
+		"custom":           menuActionCustom,
+		"noop":             func(_ *Model) tea.Cmd { return nil },
+		"":                 func(_ *Model) tea.Cmd { return nil },
 	}
 }
 
@@ -290,6 +323,17 @@ func MenuFromConfig(cfg MenuConfigYAML, allowEditInput *bool) MenuConfig {
 		{"copy", cfg.Copy},
 		{"expr", cfg.Expr},
 		{"quit", cfg.Quit},
+		{"edit", cfg.Edit},
+		{"open_url", cfg.OpenURL},
+		{"wrap", cfg.Wrap},
+		{"search_selection", cfg.SearchSelection},
+		{"columns", cfg.Columns},
+		{"column_filter", cfg.ColumnFilter},
+		{"column_manager", cfg.ColumnManager},
+		{"annotate", cfg.Annotate},
+		{"peek", cfg.Peek},
+		{"detail", cfg.Detail},
+		{"recent", cfg.Recent},
 		{"custom", cfg.Custom},
 	}
 
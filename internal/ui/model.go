@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	rdebug "runtime/debug"
@@ -15,12 +16,15 @@ import (
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 
 	celhelper "github.com/oakwood-commons/kvx/internal/cel"
 	"github.com/oakwood-commons/kvx/internal/completion"
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/jq"
 	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/search"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 	"github.com/oakwood-commons/kvx/pkg/intellisense"
 	"github.com/oakwood-commons/kvx/pkg/loader"
 )
@@ -84,19 +88,16 @@ func (m *Model) buildTabCandidates(token string) []tabCandidate {
 	keys := make([]tabCandidate, 0, len(m.FilteredSuggestions))
 	funcs := make([]tabCandidate, 0, len(m.FilteredSuggestions))
 
+	mode := m.completionMatchMode()
 	for i, s := range m.FilteredSuggestions {
 		isFunction := strings.Contains(s, "(") || strings.Contains(s, " - ")
 		if token != "" {
+			name := matchKeyToken(s)
 			if isFunction {
-				name := extractFunctionName(s)
-				if !strings.HasPrefix(strings.ToLower(name), token) {
-					continue
-				}
-			} else {
-				cand := matchKeyToken(s)
-				if !strings.HasPrefix(strings.ToLower(cand), token) {
-					continue
-				}
+				name = extractFunctionName(s)
+			}
+			if _, _, ok := completion.Match(name, token, mode); !ok {
+				continue
 			}
 		}
 		if isFunction {
@@ -117,6 +118,29 @@ func (m *Model) buildTabCandidates(token string) []tabCandidate {
 	return candidates
 }
 
+// SetCompletionMatchMode configures how expression-mode completions match the
+// partial token: "fuzzy" (default) or "prefix" for strict prefix matching.
+// Unknown modes are rejected and leave the current mode unchanged.
+func (m *Model) SetCompletionMatchMode(mode string) error {
+	parsed, err := completion.ParseMatchMode(mode)
+	if err != nil {
+		return err
+	}
+	if m.CompletionEngine != nil {
+		m.CompletionEngine.SetMatchMode(parsed)
+	}
+	return nil
+}
+
+// completionMatchMode returns the match mode used by the completion engine,
+// falling back to strict prefix matching when no engine is configured.
+func (m *Model) completionMatchMode() completion.MatchMode {
+	if m.CompletionEngine == nil {
+		return completion.MatchPrefix
+	}
+	return m.CompletionEngine.MatchMode()
+}
+
 // Model represents the minimal root Bubble Tea UI model for kvx.
 // It delegates to RootModel for core navigation/expression/filtering logic.
 // Search (Task 7) and Help (Task 8) logic are deferred and kept here temporarily.
@@ -134,6 +158,7 @@ type Model struct {
 	Layout                     *LayoutManager   // Layout manager for consistent spacing
 	Node                       interface{}
 	Root                       interface{}
+	Positions                  *sourcepos.Index // Where the values of Root were written, when recorded by the loader
 	Path                       string
 	PathKeys                   []string
 	CursorByPath               map[string]int // Remember cursor positions per path for back navigation
@@ -141,6 +166,7 @@ type Model struct {
 	ErrMsg                     string
 	ErrSticky                  bool
 	ErrStickyInput             string
+	ErrPos                     int // Rune offset in ErrStickyInput of the character a sticky error points at (-1 if none)
 	LastKey                    string
 	InputFocused               bool
 	ExprDisplay                string                          // Last committed expression/path for data panel label in expr mode
@@ -185,6 +211,10 @@ type Model struct {
 	FunctionExamples           map[string]FunctionExampleValue // Optional function examples (normalized function names)
 	ShowPanelTitle             bool                            // Whether to render panel title/header
 	HelpVisible                bool                            // Whether inline help is shown (F1)
+	HelpScroll                 int                             // First line of the help overlay shown
+	HelpQuery                  string                          // Help overlay search (/): only lines containing it are shown
+	HelpSearching              bool                            // Whether the help search query is being typed
+	HelpKeyInfo                string                          // What the last key pressed in the help overlay does
 	LastTabPosition            int                             // Track cursor position on last tab to detect cycling
 	LastTabToken               string                          // Track initial token for tab cycling
 	LastTabTokenIsFunc         bool                            // Track whether tab cycling is for functions
@@ -198,6 +228,7 @@ type Model struct {
 	DesiredWinHeight           int                             // Forced height when provided via CLI flags
 	ForceWindowSize            bool                            // Whether to ignore terminal resize events and stick to desired size
 	KeyColWidth                int                             // Current width for the KEY column
+	WrapValues                 bool                            // Wrap long values onto continuation lines instead of truncating
 	ConfiguredKeyColWidth      int                             // Desired/capped key column width from config (<=0 means default)
 	ValueColWidth              int                             // Computed width for the VALUE column
 	ConfiguredValueColWidth    int                             // Configured value column width (0 = auto-calculate)
@@ -212,6 +243,7 @@ type Model struct {
 	AdvancedSearchQuery        string                          // Current advanced search query
 	AdvancedSearchResults      []SearchResult                  // Search results with full paths
 	AdvancedSearchBasePath     string                          // Base path where search was initiated (for combining with result paths)
+	AdvancedSearchScope        string                          // Subtree path when searching under the selection (shown in the search title)
 	AdvancedSearchCommitted    bool                            // Whether Enter was pressed to commit deep search (vs real-time filter)
 	PreviousNode               interface{}                     // Previous node before search (for restoring on Esc)
 	PreviousPath               string                          // Previous path before search (for restoring on Esc)
@@ -233,6 +265,40 @@ type Model struct {
 	MapFilterActive bool            // Whether map filter mode is active
 	MapFilterQuery  string          // Current map filter query
 	MapFilterInput  textinput.Model // Text input for map filter mode
+	PinnedFilter    string          // Map filter query kept applied across navigation (F4 while filtering)
+
+	// Columnar view (F11) of lists of objects, with a per-column filter row (ctrl+f)
+	ColumnarView       bool               // Whether lists of objects render one column per field
+	ColumnFilterActive bool               // Whether the filter row under the header is being edited
+	ColumnFilters      map[string]string  // Filter text by field; rows must contain every one
+	ColumnFilterCol    int                // Index of the column whose filter is being edited
+	ColumnOrder        []string           // Preferred column order (--column-order, schema); edited in the column manager
+	HiddenColumns      []string           // Columns left out of the columnar view
+	ColumnManagerOpen  bool               // Whether the column manager overlay (c) is shown
+	ColumnManagerIndex int                // Selected line of the column manager
+	HeaderFocused      bool               // Whether the cursor is on the header row (up from the first row)
+	HeaderCol          int                // Index of the focused header among the visible columns
+	ColumnStatsCache   map[string]string  // Status bar stats of the list's columns, by field, computed on focus
+	Peek               *Pick              // Value shown whole in the peek overlay (p), or nil when closed
+	PeekScroll         int                // First value line shown in the peek overlay
+	RecentOpen         bool               // Whether the recent panel (r) is shown
+	RecentIndex        int                // Selected line of the recent panel
+	OnExitViewState    func(ViewState)    // Receives the view layout when RunModel exits, to persist it
+	OnExitLayout       func(LayoutConfig) // Receives the expression and view layout when RunModel exits (--save-layout)
+
+	// Pick mode (--pick): enter picks the selected value and quits
+	PickMode    bool         // Whether enter picks values instead of navigating, and quitting prints nothing
+	PickMulti   bool         // Whether space marks rows to pick together (--multi)
+	PickMarks   []Pick       // Rows marked with space, in the order they were marked
+	Picks       []Pick       // Values picked before quitting
+	OnExitPicks func([]Pick) // Receives the picks (none when quit without picking) when RunModel exits
+
+	// Annotations (a): notes and flags on rows, handed over when RunModel exits
+	Annotations       []Annotation       // Annotated rows, in the order they were first annotated
+	AnnotationInput   bool               // Whether the note prompt is open
+	AnnotationPath    string             // Row the note prompt annotates
+	AnnotationText    string             // Note typed in the prompt
+	OnExitAnnotations func([]Annotation) // Receives the annotations when RunModel exits
 
 	// Performance settings
 	SearchDebounceID     int    // Counter for debounce message correlation
@@ -242,6 +308,15 @@ type Model struct {
 	SearchPendingQuery   string // Query pending debounce timer
 	VirtualScrolling     bool   // Whether virtual scrolling is enabled
 	ScrollBufferRows     int    // Extra rows to render above/below viewport
+	ChunkSize            int    // Items of a large array shown at first and loaded per L (0 = whole arrays)
+	ChunkThreshold       int    // Arrays longer than this are shown in chunks
+
+	// Chunked arrays (L): large arrays show their first items, more on demand
+	ChunkFull   []interface{}  // Whole array of the current node while only part of it is shown
+	ChunkLoaded map[string]int // Items loaded of the arrays L was pressed on, by path
+
+	// Limited root (--limit, --tail, --sample): L loads the whole document
+	LimitedFull interface{} // Whole document while Root holds only the records the limits kept
 
 	// Display schema for rich TUI rendering (list/detail views)
 	DisplaySchema    *DisplaySchema   // Optional schema for list/detail view modes
@@ -252,8 +327,81 @@ type Model struct {
 	DetailSourcePath string           // Path from which we drilled into detail view (to navigate back)
 	ListPanelMode    string           // ListPanelModeSearch or ListPanelModeFilter — determines search panel behaviour in list view
 
+	// Detail layout of a JSON Schema without x-kvx-detail, opened on an
+	// array element with the detail key (d)
+	SchemaDetail *DetailDisplayConfig
+	// Titles and descriptions of a JSON Schema, shown in the status bar for
+	// the selected key and in the help overlay for the current node
+	SchemaDocs *SchemaDoc
+
 	// Status screen async completion (set by library consumers via Config.Done)
 	DoneChan <-chan StatusResult // Optional channel signaling async operation completion
+	// Status screen updates from library consumers (Config.StatusController)
+	StatusController *StatusController
+	// Notify shows a desktop notification when the status screen finishes
+	// while the terminal is unfocused (features.notify, Config.Notify)
+	Notify bool
+	// Unfocused is set while the terminal reports it has lost focus
+	Unfocused bool
+	// Bell says which events ring the terminal bell (features.bell)
+	Bell BellMode
+	// VisualBell flashes the screen instead of sounding the bell
+	VisualBell  bool
+	bellPending bool
+	// IdleTimeout exits the TUI after this long without input (0: never)
+	IdleTimeout time.Duration
+	// IdlePrint prints the current expression result on an idle exit
+	IdlePrint bool
+	// Kiosk restricts the TUI to navigation and search (see SetKiosk)
+	Kiosk bool
+	// IdleExited is set when the TUI exited on its idle timeout
+	IdleExited bool
+	lastInput  time.Time
+	// Refresh re-reads the input every RefreshInterval (--refresh) and the
+	// view re-evaluates the current expression on the new data
+	Refresh         func() (interface{}, error)
+	RefreshInterval time.Duration
+	// DataController pushes new data from library consumers (Config.DataController)
+	DataController *DataController
+	// Stream appends the records read in the background to the root (--stream)
+	Stream *RecordStream
+	// WatchLoad reads the data again whenever the file at WatchPath changes
+	// (--watch), keeping the view's path and cursor like Refresh
+	WatchPath  string
+	WatchLoad  func() (interface{}, error)
+	watchStamp fileStamp
+	watcher    *fileWatcher
+	// RootExpr is the expression the node at the empty path was evaluated
+	// from when it is not a path (-e with a CEL expression), for Refresh
+	RootExpr string
+	// ChangedRows holds when the row at each display path last changed in
+	// a refresh; changed rows are highlighted for a few seconds
+	ChangedRows map[string]time.Time
+	// Visits logs the paths opened this session, oldest first; the recent
+	// panel (r) lists them and OnExitVisits receives them when RunModel exits
+	Visits       []Visit
+	OnExitVisits func([]Visit)
+	visitPath    string
+	// OnEvent receives the navigation, search, and evaluation events of the
+	// session as they happen, on the update loop
+	OnEvent func(Event)
+	// Stats counts the session for --session-summary; OnExitStats receives
+	// them when RunModel exits
+	Stats       *SessionStats
+	OnExitStats func(SessionStats)
+	// Tutorial walks the user through the TUI (kvx tutorial), shown in the
+	// info panel
+	Tutorial *Tutorial
+	// KeyRecorder logs the input events of the session (--record-keys);
+	// Replay replays a recorded session (--replay-keys)
+	KeyRecorder *KeyRecorder
+	Replay      *KeyReplay
+	// OnScriptError receives the failures of the startup keys (--press): unmet
+	// <expect:...> checks and invalid directives
+	OnScriptError func(error)
+	// Deterministic renders the same frames on every run (--deterministic);
+	// set it with SetDeterministic
+	Deterministic bool
 
 	// ExprProvider overrides CEL expression evaluation when set.
 	// It allows library consumers to inject custom expression handling.
@@ -299,6 +447,7 @@ func (m *Model) clearSearchState() {
 	m.AdvancedSearchQuery = ""
 	m.AdvancedSearchResults = []SearchResult{}
 	m.AdvancedSearchBasePath = ""
+	m.AdvancedSearchScope = ""
 	m.AdvancedSearchCommitted = false
 	m.SearchInput.SetValue("")
 	m.SearchInput.SetCursor(0)
@@ -372,6 +521,7 @@ func (m *Model) setStickyError(msg string) {
 	m.StatusType = "error"
 	m.ErrSticky = true
 	m.ErrStickyInput = m.PathInput.Value()
+	m.ErrPos = -1
 }
 
 func (m *Model) clearError() {
@@ -379,6 +529,7 @@ func (m *Model) clearError() {
 	m.StatusType = ""
 	m.ErrSticky = false
 	m.ErrStickyInput = ""
+	m.ErrPos = -1
 }
 
 func (m *Model) clearErrorUnlessSticky() {
@@ -649,35 +800,45 @@ func styleRows(stringRows [][]string) []table.Row {
 // styleRowsWithWidths truncates and styles rows to match column widths
 func styleRowsWithWidths(stringRows [][]string, keyWidth, valueWidth int) []table.Row {
 	rows := make([]table.Row, len(stringRows))
+	cells := make([]string, 2*len(stringRows))
 
 	// Truncate to the exact column width - the table will handle its own padding.
 	// Ensure that the widths are at least 1.
-	keyContentWidth := keyWidth
-	valueContentWidth := valueWidth
-	if keyContentWidth < 1 {
-		keyContentWidth = 1
-	}
-	if valueContentWidth < 1 {
-		valueContentWidth = 1
-	}
+	keyContentWidth := max(keyWidth, 1)
+	valueContentWidth := max(valueWidth, 1)
 
 	for i, sr := range stringRows {
-		row := make([]string, len(sr))
+		row := cells[2*i : 2*i : 2*i+2]
+		if len(sr) > 2 {
+			row = make([]string, 0, len(sr))
+		}
+		row = row[:len(sr)]
 		// Truncate and copy key column
 		if len(sr) > 0 {
-			keyCell := truncateString(sr[0], keyContentWidth)
-			row[0] = padToWidth(keyCell, keyContentWidth)
+			row[0] = fitCell(sr[0], keyContentWidth)
 		}
 		// Truncate value column (no styling - table cell style handles it)
 		if len(sr) > 1 {
-			valCell := truncateString(sr[1], valueContentWidth)
-			row[1] = padToWidth(valCell, valueContentWidth)
+			row[1] = fitCell(formatter.Hyperlink(sr[1]), valueContentWidth)
 		}
 		rows[i] = table.Row(row)
 	}
 	return rows
 }
 
+// fitCell truncates s to width with the ellipsis and pads it to width, like
+// padToWidth(truncateString(s, width), width), measuring plain ASCII cells,
+// the common case, only once.
+func fitCell(s string, width int) string {
+	if !textwidth.IsPrintableASCII(s) {
+		return padToWidth(truncateString(s, width), width)
+	}
+	if len(s) > width {
+		return formatter.TruncateCell(s, width)
+	}
+	return s + spaces(width-len(s))
+}
+
 func extractRowKeys(rows [][]string) []string {
 	keys := make([]string, len(rows))
 	for i, r := range rows {
@@ -697,7 +858,7 @@ func styleRowsNoTruncation(stringRows [][]string, keyWidth, valueWidth int) []ta
 			row[0] = padToWidth(truncateNoEllipsis(sr[0], keyWidth), keyWidth)
 		}
 		if len(sr) > 1 {
-			row[1] = padToWidth(truncateNoEllipsis(sr[1], valueWidth), valueWidth)
+			row[1] = padToWidth(truncateNoEllipsis(formatter.Hyperlink(sr[1]), valueWidth), valueWidth)
 		}
 		rows[i] = table.Row(row)
 	}
@@ -753,16 +914,11 @@ func truncateString(s string, maxLen int) string {
 		return s
 	}
 	// Measure display width (handles wide chars like CJK)
-	w := runewidth.StringWidth(s)
+	w := textwidth.Width(s)
 	if w <= maxLen {
 		return s
 	}
-	if maxLen < 3 {
-		// Too short for ellipsis, just truncate
-		return runewidth.Truncate(s, maxLen, "")
-	}
-	// Truncate to fit width, leaving room for ellipsis
-	return runewidth.Truncate(s, maxLen-3, "") + "..."
+	return formatter.TruncateCell(s, maxLen)
 }
 
 // padToWidth right-pads the string to the given display width using spaces.
@@ -774,18 +930,25 @@ func padToWidth(s string, width int) string {
 	if w >= width {
 		return s
 	}
-	return s + strings.Repeat(" ", width-w)
+	return s + spaces(width-w)
+}
+
+// padding backs spaces, so padding a cell allocates only the padded string.
+var padding = strings.Repeat(" ", 256)
+
+// spaces returns n spaces.
+func spaces(n int) string {
+	if n <= len(padding) {
+		return padding[:n]
+	}
+	return strings.Repeat(" ", n)
 }
 
 func truncateNoEllipsis(s string, maxLen int) string {
 	if maxLen <= 0 {
 		return s
 	}
-	runes := []rune(s)
-	if len(runes) <= maxLen {
-		return s
-	}
-	return string(runes[:maxLen])
+	return textwidth.Truncate(s, maxLen, "")
 }
 
 // adjustSuggestionNamespace avoids duplicating the namespace when inserting a suggestion.
@@ -892,8 +1055,8 @@ func InitialModel(node interface{}) Model {
 	ti.CharLimit = 500
 	ti.SetWidth(80) // Initial width, will be adjusted in applyLayout
 	ti.Prompt = ""
-	// At root, show '_' by default in the expr section
-	ti.SetValue("_")
+	// At root, show the root ('_', or '.' in jq mode) in the expr section
+	ti.SetValue(formatPathForDisplay(""))
 
 	si := textinput.New()
 	si.Placeholder = ""
@@ -935,11 +1098,15 @@ func InitialModel(node interface{}) Model {
 	}
 
 	// Initialize CEL function suggestions with usage hints; fall back to static list
-	suggestionsList := DiscoverExpressions()
-
+	var suggestionsList []string
 	var completionEngine *completion.CompletionEngine
-	if provider, err := intellisense.NewProvider(); err == nil {
-		completionEngine = completion.NewEngine(provider)
+	if queryLang == QueryLangJQ {
+		completionEngine = completion.NewEngine(intellisense.NewJQProvider())
+	} else {
+		suggestionsList = DiscoverExpressions()
+		if provider, err := intellisense.NewProvider(); err == nil {
+			completionEngine = completion.NewEngine(provider)
+		}
 	}
 
 	// Supplement the registry with any functions the ExpressionProvider discovered
@@ -1007,6 +1174,7 @@ func InitialModel(node interface{}) Model {
 		InputPlaceholder:           "Enter path (e.g. items[0] or items.filter(x, x.available))",
 		HelpNavigationDescriptions: nil,
 		TruncateTableCells:         true,
+		WrapValues:                 formatter.WrapValues(),
 		FunctionExamples:           functionExamples,
 		FunctionPalette:            palette,
 		KeyMode:                    KeyModeVim, // Default to vim-style keybindings
@@ -1015,6 +1183,8 @@ func InitialModel(node interface{}) Model {
 		SearchResultLimit: 500,  // Limit deep search to 500 results
 		ScrollBufferRows:  5,    // Pre-render 5 rows above/below viewport
 		VirtualScrolling:  true, // Enable virtual scrolling by default
+		ChunkSize:         500,  // Show large arrays 500 items at a time
+		ChunkThreshold:    1000, // once they hold more than 1000 items
 	}
 }
 
@@ -1075,6 +1245,9 @@ func normalizePathForModel(path string) string {
 	if cleaned == "" || cleaned == "_" {
 		return ""
 	}
+	if isJQProgramPath(cleaned) {
+		return jqModelPath(cleaned)
+	}
 	// Normalize segments and quote invalid identifiers (e.g., tasks.build-windows -> tasks["build-windows"]).
 	prefixed := cleaned
 	if !strings.HasPrefix(prefixed, "_") {
@@ -1163,9 +1336,13 @@ func (m *Model) logKeyEvent(keyStr string) {
 }
 
 // formatPathForDisplay formats a path for display in the expression bar.
-// It adds the "_." prefix if needed, or returns "_" for empty paths.
+// It adds the "_." prefix if needed, or returns "_" for empty paths; in jq
+// mode paths are shown as jq paths instead.
 // This centralizes the repeated path formatting logic throughout the codebase.
 func formatPathForDisplay(path string) string {
+	if queryLang == QueryLangJQ {
+		return jqDisplayPath(path)
+	}
 	trimmed := strings.TrimSpace(path)
 	if trimmed == "" {
 		return "_"
@@ -1319,7 +1496,7 @@ func formatExprDisplay(expr string) string {
 
 func completionRootForInput(root, current interface{}, input string) interface{} {
 	trimmed := strings.TrimSpace(input)
-	if trimmed == "" || strings.HasPrefix(trimmed, "_") {
+	if trimmed == "" || strings.HasPrefix(trimmed, "_") || isJQProgram(trimmed) {
 		return root
 	}
 	return current
@@ -1377,12 +1554,8 @@ func (m *Model) storeCursorForPathByKey(path string, selectedKey string) {
 	idx := 0
 	switch node := m.Node.(type) {
 	case map[string]interface{}:
-		// Get sorted keys (same order as NodeToRows)
-		keys := make([]string, 0, len(node))
-		for k := range node {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
+		// Same order as NodeToRows
+		keys := navigator.OrderedKeys(node)
 		for i, k := range keys {
 			if k == selectedKey {
 				idx = i
@@ -1553,6 +1726,8 @@ func (m *Model) NavigateTo(node interface{}, path string) *Model {
 
 	// Check if display schema should activate a custom view mode
 	m.updateViewMode(node)
+	// Large arrays show their loaded chunks only
+	node = m.chunkNode(node, normalizedPath)
 
 	// Preserve search context before updating (needed for left arrow navigation back to search)
 	searchContextActive := m.SearchContextActive
@@ -1594,9 +1769,23 @@ func (m *Model) NavigateTo(node interface{}, path string) *Model {
 	// Clear suggestion filter state
 	m.SuggestionFilterActive = false
 	m.FilteredSuggestionRows = nil
+	// Column filters belong to the list they were typed for
+	m.ColumnFilters = nil
+	m.ColumnFilterActive = false
+	m.ColumnFilterCol = 0
+	m.ColumnManagerOpen = false
+	m.HeaderFocused = false
+	m.ColumnStatsCache = nil
+	m.Peek = nil
+	m.RecentOpen = false
 
 	// Use SyncTableState() to update table rows and cursor consistently
 	m.SyncTableState(true)
+	// A pinned filter applies to every node navigated into.
+	if m.PinnedFilter != "" {
+		m.MapFilterQuery = m.PinnedFilter
+		m.applyMapFilter()
+	}
 
 	// Restore AdvancedSearchActive if it was set (it will be cleared by the caller if needed)
 	m.AdvancedSearchActive = wasInSearch
@@ -1629,12 +1818,15 @@ func (m *Model) NavigateTo(node interface{}, path string) *Model {
 
 // nodeTypeLabel maps a Go node to a simple CEL-like type label used in suggestion usage strings.
 func nodeTypeLabel(node interface{}) string {
-	switch node.(type) {
+	switch v := node.(type) {
 	case []interface{}:
 		return "list"
 	case map[string]interface{}:
 		return "map"
 	case string:
+		if _, binary := formatter.BinaryBytes(node); binary {
+			return "bytes"
+		}
 		return "string"
 	case bool:
 		return "bool"
@@ -1644,6 +1836,10 @@ func nodeTypeLabel(node interface{}) string {
 		return "uint"
 	case float32, float64:
 		return "double"
+	case json.Number:
+		return celhelper.NumberType(v)
+	case []byte:
+		return "bytes"
 	default:
 		return "any"
 	}
@@ -2271,6 +2467,10 @@ func (m *Model) filterWithCompletionEngine(allowDropdown bool) {
 		}
 	}
 	currentNodeForCtx := m.Node
+	if queryLang == QueryLangJQ {
+		// jq programs always run against the whole document
+		currentNodeForCtx = m.Root
+	}
 	if currentNodeForCtx == nil {
 		currentNodeForCtx = completionRootForInput(m.Root, m.Node, input)
 	}
@@ -2793,7 +2993,7 @@ func isAlphaNumOrUnderscore(ch rune) bool {
 		ch == '_'
 }
 
-// performAdvancedSearch recursively searches a node (and its children) for key-value pairs matching the query.
+// performAdvancedSearch searches a node (and its children) for key-value pairs matching the query.
 // Returns results with relative paths (relative to the search root). Searches both keys and values (case-insensitive substring match).
 // If limit > 0, stops after collecting that many results and returns limited=true.
 func performAdvancedSearch(node interface{}, query string, limit int) (results []SearchResult, limited bool) {
@@ -2801,160 +3001,17 @@ func performAdvancedSearch(node interface{}, query string, limit int) (results [
 		return []SearchResult{}, false
 	}
 
-	queryLower := strings.ToLower(query)
-	results = []SearchResult{}
-
-	// Recursive function to search a node at a given path
-	var searchRecursive func(node interface{}, currentPath string) bool
-	searchRecursive = func(node interface{}, currentPath string) bool {
-		// Check limit
-		if limit > 0 && len(results) >= limit {
-			return true // stop searching
-		}
-
-		switch t := node.(type) {
-		case map[string]interface{}:
-			// Sort keys for deterministic results
-			keys := make([]string, 0, len(t))
-			for k := range t {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-
-			for _, k := range keys {
-				// Check limit before processing each key
-				if limit > 0 && len(results) >= limit {
-					return true
-				}
-
-				v := t[k]
-				valueStr := formatter.Stringify(v)
-
-				// Check if key or value matches (case-insensitive substring)
-				keyMatches := strings.Contains(strings.ToLower(k), queryLower)
-				valueMatches := strings.Contains(strings.ToLower(valueStr), queryLower)
-
-				if keyMatches || valueMatches {
-					// Build full path
-					var fullPath string
-					if currentPath == "" {
-						fullPath = k
-					} else {
-						// Check if key needs bracket notation
-						if needsBracketNotation(k) {
-							fullPath = currentPath + `["` + k + `"]`
-						} else {
-							fullPath = currentPath + "." + k
-						}
-					}
-
-					// Display key with bracket notation if needed
-					displayKey := k
-					if needsBracketNotation(k) {
-						displayKey = `["` + k + `"]`
-					}
-
-					results = append(results, SearchResult{
-						FullPath: fullPath,
-						Key:      displayKey,
-						Value:    valueStr,
-						Node:     v,
-					})
-
-					// Check if we hit the limit after adding
-					if limit > 0 && len(results) >= limit {
-						return true
-					}
-				}
-
-				// Recursively search nested structures
-				if vMap, ok := v.(map[string]interface{}); ok {
-					var nextPath string
-					if currentPath == "" {
-						if needsBracketNotation(k) {
-							nextPath = `["` + k + `"]`
-						} else {
-							nextPath = k
-						}
-					} else {
-						if needsBracketNotation(k) {
-							nextPath = currentPath + `["` + k + `"]`
-						} else {
-							nextPath = currentPath + "." + k
-						}
-					}
-					if searchRecursive(vMap, nextPath) {
-						return true
-					}
-				} else if vArr, ok := v.([]interface{}); ok {
-					var basePath string
-					if currentPath == "" {
-						if needsBracketNotation(k) {
-							basePath = `["` + k + `"]`
-						} else {
-							basePath = k
-						}
-					} else {
-						if needsBracketNotation(k) {
-							basePath = currentPath + `["` + k + `"]`
-						} else {
-							basePath = currentPath + "." + k
-						}
-					}
-					if searchRecursive(vArr, basePath) {
-						return true
-					}
-				}
-			}
-
-		case []interface{}:
-			for i, v := range t {
-				// Check limit before processing each element
-				if limit > 0 && len(results) >= limit {
-					return true
-				}
-
-				valueStr := formatter.Stringify(v)
-				// Check if value matches (arrays don't have keys to match)
-				valueMatches := strings.Contains(strings.ToLower(valueStr), queryLower)
-
-				if valueMatches {
-					// Build full path with array index
-					fullPath := fmt.Sprintf("%s[%d]", currentPath, i)
-					displayKey := fmt.Sprintf("[%d]", i)
-
-					results = append(results, SearchResult{
-						FullPath: fullPath,
-						Key:      displayKey,
-						Value:    valueStr,
-						Node:     v,
-					})
-
-					// Check if we hit the limit after adding
-					if limit > 0 && len(results) >= limit {
-						return true
-					}
-				}
-
-				// Recursively search nested structures
-				if vMap, ok := v.(map[string]interface{}); ok {
-					nextPath := fmt.Sprintf("%s[%d]", currentPath, i)
-					if searchRecursive(vMap, nextPath) {
-						return true
-					}
-				} else if vArr, ok := v.([]interface{}); ok {
-					nextPath := fmt.Sprintf("%s[%d]", currentPath, i)
-					if searchRecursive(vArr, nextPath) {
-						return true
-					}
-				}
-			}
-		}
-		return false
+	// "..key" lists every value stored under key instead of matching text
+	var found []search.Result
+	if base, key, ok := navigator.SplitDescent(strings.TrimSpace(query)); ok && base == "" {
+		found, limited = search.Descent(node, key, limit)
+	} else {
+		found, limited = search.Walk(node, search.Substring(query), limit)
+	}
+	results = make([]SearchResult, len(found))
+	for i, r := range found {
+		results[i] = SearchResult{FullPath: r.Path, Key: r.Key, Value: r.Value, Node: r.Node}
 	}
-
-	// Start search from the provided node with empty path (paths will be relative to this node)
-	limited = searchRecursive(node, "")
 	return results, limited
 }
 
@@ -2980,9 +3037,27 @@ func SearchRows(node interface{}, query string) [][]string {
 	return rows
 }
 
+// SearchPaths returns the _-rooted path of every match SearchRows lists, in
+// the same order, e.g. "_.items[0].name" or `_["bad-key"]`.
+func SearchPaths(node interface{}, query string) []string {
+	q := strings.TrimSpace(query)
+	if q == "" {
+		return nil
+	}
+	results, _ := performAdvancedSearch(node, q, 0) // 0 = no limit
+	paths := make([]string, 0, len(results))
+	for _, res := range results {
+		paths = append(paths, search.RootedPath(res.FullPath))
+	}
+	return paths
+}
+
 // evaluateExpression uses the per-instance ExprProvider when set,
 // otherwise falls back to the package-level EvaluateExpression.
 func (m *Model) evaluateExpression(expr string, root interface{}) (interface{}, error) {
+	if isJQProgram(expr) {
+		return jq.Evaluate(expr, root)
+	}
 	if m.ExprProvider != nil {
 		return m.ExprProvider.Evaluate(expr, root)
 	}
@@ -2992,6 +3067,9 @@ func (m *Model) evaluateExpression(expr string, root interface{}) (interface{},
 // isExpression checks via the per-instance ExprProvider when set,
 // otherwise falls back to the package-level IsExpression.
 func (m *Model) isExpression(expr string) bool {
+	if isJQProgram(expr) {
+		return IsExpression(expr)
+	}
 	if m.ExprProvider != nil {
 		return m.ExprProvider.IsExpression(expr)
 	}
@@ -2999,10 +3077,32 @@ func (m *Model) isExpression(expr string) bool {
 }
 
 func (m *Model) Init() tea.Cmd {
-	cmds := []tea.Cmd{textinput.Blink}
+	var cmds []tea.Cmd
+	if !m.Deterministic {
+		cmds = append(cmds, textinput.Blink)
+	}
 	if cv := m.activeCustomView(); cv != nil {
 		cmds = append(cmds, cv.Init())
 	}
+	if m.IdleTimeout > 0 {
+		m.lastInput = time.Now()
+		cmds = append(cmds, idleCheck(m.IdleTimeout))
+	}
+	if m.RefreshInterval > 0 && m.Refresh != nil {
+		cmds = append(cmds, refreshTick(m.RefreshInterval))
+	}
+	if m.DataController != nil {
+		cmds = append(cmds, waitForDataChange(m.DataController))
+	}
+	if m.Stream != nil {
+		cmds = append(cmds, streamTick())
+	}
+	if cmd := m.startWatch(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if m.Replay.pending() {
+		cmds = append(cmds, m.Replay.tick())
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -3012,9 +3112,14 @@ func (m *Model) syncPathInputWithCursor() {
 	if m.InputFocused {
 		return
 	}
+	// Column filters show as their CEL expression, ready to copy
+	if expr := m.columnFilterExpr(); expr != "" {
+		m.PathInput.SetValue(expr)
+		return
+	}
 	path := strings.TrimSpace(m.selectedRowPath())
 	if path == "" {
-		m.PathInput.SetValue("_")
+		m.PathInput.SetValue(formatPathForDisplay(""))
 		return
 	}
 	// Update the path input to reflect the selected row
@@ -3073,7 +3178,7 @@ func (m *Model) navigateExprBackward() (tea.Model, tea.Cmd) {
 	if strings.Contains(m.Path, "filter(") || strings.Contains(m.Path, "map(") {
 		newModel := m.NavigateTo(m.Root, "")
 		newModel.InputFocused = true
-		newModel.PathInput.SetValue("_")
+		newModel.PathInput.SetValue(formatPathForDisplay(""))
 		newModel.PathInput.SetCursor(len(newModel.PathInput.Value()))
 		newModel.PathInput.Focus()
 		return newModel, nil
@@ -3170,6 +3275,12 @@ func (m *Model) applyLayout(forceRegenerate ...bool) {
 				if m.FilterActive && m.FilterBuffer != "" {
 					m.applyTypeAheadFilter()
 				}
+				if m.PinnedFilter != "" {
+					m.applyMapFilter()
+				}
+				if len(m.ColumnFilters) > 0 {
+					m.applyColumnFilters()
+				}
 			}
 			// Note: AllRows is preserved if widths didn't change
 		}
@@ -3245,8 +3356,9 @@ func (m *Model) SyncTableState(resetCursorToZero ...bool) {
 	case m.SuggestionFilterActive && len(m.FilteredSuggestionRows) > 0:
 		// Use filtered suggestion rows (from path input filtering)
 		rows = m.FilteredSuggestionRows
-	case len(m.AllRows) > 0:
-		// Use existing rows (preserves filtered state, etc.)
+	case len(m.AllRows) > 0 || len(m.ColumnFilters) > 0:
+		// Use existing rows (preserves filtered state, etc.), even when
+		// the column filters match nothing
 		rows = m.AllRows
 	case m.Node != nil:
 		// Generate rows from current node
@@ -3353,7 +3465,31 @@ func (m *Model) SyncTableState(resetCursorToZero ...bool) {
 	}
 }
 
+// Update records user input for the idle timeout and any key recording,
+// handles msg, then rings any bell queued while handling it.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if next, cmd, ok := m.handleReplay(msg); ok {
+		return next, cmd
+	}
+	m.KeyRecorder.record(msg)
+	m.noteInput(msg)
+	m.noteVisit()
+	next, cmd := m.update(msg)
+	bell := m.takeBell()
+	if nm, ok := next.(*Model); ok && nm != m {
+		bell = tea.Batch(bell, nm.takeBell())
+	}
+	if bell != nil {
+		cmd = tea.Batch(cmd, bell)
+	}
+	if nm, ok := next.(*Model); ok {
+		nm.noteVisit()
+		nm.advanceTutorial()
+	}
+	return next, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	defer m.clearStickyErrorIfInputChanged()
 
@@ -3363,18 +3499,65 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	// Route status view messages when in status mode
-	case spinner.TickMsg, statusDoneMsg, statusTimeoutMsg, statusDoneTimerMsg, statusFlashClearMsg:
+	case spinner.TickMsg, statusDoneMsg, statusChangedMsg, statusCountdownMsg, statusTimeoutMsg, statusDoneTimerMsg, statusFlashClearMsg:
 		if m.ViewMode == "status" && m.StatusViewState != nil {
 			var statusCmd tea.Cmd
 			var updated CustomView
+			waiting := m.StatusViewState.Phase == statusPhaseWaiting
 			updated, statusCmd = m.StatusViewState.Update(msg)
 			if sv, ok := updated.(*StatusViewModel); ok {
 				m.StatusViewState = sv
 			}
+			if waiting && m.StatusViewState.Phase != statusPhaseWaiting {
+				m.ringBell(m.StatusViewState.Phase == statusPhaseError)
+				if m.Notify && m.Unfocused {
+					statusCmd = tea.Batch(statusCmd, m.StatusViewState.notifyCmd(m.AppName))
+				}
+			}
 			return m, statusCmd
 		}
 		return m, nil
 
+	case tea.FocusMsg:
+		m.Unfocused = false
+		return m, nil
+
+	case idleCheckMsg:
+		return m, m.handleIdleCheck()
+
+	case refreshTickMsg:
+		return m, m.handleRefreshTick()
+
+	case refreshedMsg:
+		return m, m.handleRefreshed(msg)
+
+	case dataChangedMsg:
+		return m, m.handleDataChanged()
+
+	case streamTickMsg:
+		return m, m.handleStreamTick()
+
+	case watchEventMsg:
+		return m, m.handleWatchEvent(msg)
+
+	case watchedMsg:
+		return m, m.handleWatched(msg)
+
+	case deltaFadeMsg:
+		m.handleDeltaFade()
+		return m, nil
+
+	case tea.BlurMsg:
+		m.Unfocused = true
+		return m, nil
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.ErrMsg = fmt.Sprintf("Editor failed: %v", msg.err)
+			m.StatusType = "error"
+		}
+		return m, nil
+
 	case SearchDebounceMsg:
 		// Only execute search if this is the latest debounce request
 		if msg.ID == m.SearchDebounceID && msg.Query == m.SearchPendingQuery {
@@ -3401,10 +3584,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Capture terminal size and adjust table height to keep header visible
+		oldH := m.WinHeight
 		m.WinWidth = targetW
 		m.WinHeight = targetH
 		// Apply layout WITHOUT forcing row regeneration - let it decide based on width changes
 		m.applyLayout(false) // Don't force regenerate on every resize
+		m.reflowViews(oldH)
 		// Focus is handled by SyncTableState() below
 		return m, nil
 
@@ -3430,18 +3615,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// When help is visible, treat it as modal: allow closing with F1/esc or quitting with ctrl+c.
-		if m.HelpVisible {
-			switch keyStr {
-			case "f1", "esc":
-				m.HelpVisible = false
-				m.applyLayout(true) // Recalculate layout when help closes
-				return m, nil
-			case "ctrl+c":
-				return m, tea.Quit
-			default:
-				return m, nil
-			}
+		// When help is visible, treat it as modal: it scrolls, searches, and looks up keys.
+		if handled, cmd := m.handleHelpKey(keyStr); handled {
+			return m, cmd
 		}
 
 		// When function palette is visible, route keys to the palette.
@@ -3466,8 +3642,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "enter":
 				if fn := m.FunctionPalette.SelectedFunction(); fn != nil {
-					text := InsertText(fn)
-					m.insertPaletteFunction(text, fn.IsMethod)
+					if queryLang == QueryLangJQ {
+						m.insertJQFunction(fn)
+					} else {
+						text := InsertText(fn)
+						m.insertPaletteFunction(text, fn.IsMethod)
+					}
 				}
 				m.FunctionPalette.Close()
 				return m, nil
@@ -3478,10 +3658,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.handleAnnotationKey(keyStr) {
+			return m, nil
+		}
+
+		if m.handleColumnManagerKey(keyStr) {
+			return m, nil
+		}
+
+		if m.handlePeekKey(keyStr) {
+			return m, nil
+		}
+
+		if m.handleRecentKey(keyStr) {
+			return m, nil
+		}
+
+		if m.handleColumnFilterKey(keyStr) {
+			return m, nil
+		}
+
+		if m.handleHeaderKey(keyStr) {
+			return m, nil
+		}
+
 		if handled, cmd := m.handleMenuKey(keyStr); handled {
 			return m, cmd
 		}
 
+		if handled, cmd := m.handlePickKey(keyStr); handled {
+			return m, cmd
+		}
+
+		if m.handleChunkKey(keyStr) {
+			return m, nil
+		}
+
 		// Handle custom view modes (list/detail/status) before standard navigation
 		if m.ViewMode == "list" {
 			if handled, result, viewCmd := m.handleListViewKey(keyStr); handled {
@@ -3722,7 +3934,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if len(rows) > 0 {
 							m.syncPathInputWithCursor()
 						} else {
-							m.PathInput.SetValue("_")
+							m.PathInput.SetValue(formatPathForDisplay(""))
 						}
 
 						m.clearErrorUnlessSticky()
@@ -3914,6 +4126,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.SyncTableState()
 				return m, cmd
 			case "tab":
+				if queryLang == QueryLangJQ {
+					m.completeJQ()
+					return m, nil
+				}
 				// Tab: cycle suggestions; if a key is selected, complete it into input
 				// If input ends with '[', immediately insert the first index ([0]) unconditionally
 				if strings.HasSuffix(m.PathInput.Value(), "[") {
@@ -4511,6 +4727,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.FunctionPalette.NoColor = m.NoColor
 				if m.FunctionPalette.Visible {
 					m.FunctionPalette.Close()
+				} else if queryLang == QueryLangJQ {
+					// jq has no namespaces or methods to narrow the palette to
+					m.FunctionPalette.Toggle()
 				} else {
 					input := strings.TrimSpace(m.PathInput.Value())
 					if strings.HasSuffix(input, ".") {
@@ -4546,9 +4765,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "f5":
 				// Copy current expression with quoting
+				if m.kioskRefuses("Copy") {
+					return m, nil
+				}
 				p := strings.TrimSpace(m.PathInput.Value())
 				if p == "" {
-					p = "_"
+					p = formatPathForDisplay("")
 				}
 				cliSafe := makePathCLISafe(p)
 				if err := copyToClipboard(cliSafe); err != nil {
@@ -4558,15 +4780,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "f10":
+				if m.Kiosk {
+					return m, tea.Quit
+				}
 				// Validate eval then print and quit
 				evalExpr := strings.TrimSpace(m.PathInput.Value())
 				if evalExpr == "" {
-					evalExpr = "_"
+					evalExpr = formatPathForDisplay("")
 				}
 				// Use expression exactly as typed - no modifications
 				// Use the configured expression provider to respect custom CEL environments
 				if _, err := m.evaluateExpression(evalExpr, m.Root); err != nil {
 					m.ErrMsg = fmt.Sprintf("explore expression error: %v", err)
+					m.ringBell(true)
 					return m, nil
 				}
 				return m, m.printCLIOutput(evalExpr)
@@ -4575,12 +4801,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				// Enter in input mode: always run the current expression as-is (no auto-completion)
 				currentValue := m.PathInput.Value()
+				if queryLang == QueryLangJQ {
+					return m.enterJQ(currentValue)
+				}
 
 				// If the typed input resolves to a node, navigate to it directly
 				pathValue := strings.TrimSpace(currentValue)
 				if pathValue == "_" {
 					// Root navigation: keep expr text as-is but reset path state
 					newModel := InitialModel(m.Root)
+					m.carrySession(&newModel)
 					newModel.Root = m.Root
 					newModel.DebugMode = m.DebugMode
 					newModel.NoColor = m.NoColor
@@ -4601,12 +4831,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Try to evaluate the expression as-is, even if it ends with "." or "["
 				// This allows showing errors for invalid expressions like "_.", "_.items[", etc.
 				if pathValue != "" {
+					// "..key" paths open as search results so each match keeps its path
+					if base, key, ok := navigator.SplitDescent(pathValue); ok {
+						if err := m.openDescentResults(base, key); err != nil {
+							m.setStickyExprError(pathValue, err)
+						}
+						return m, nil
+					}
 					// First, try to navigate/evaluate the expression
+					evalStart := time.Now()
 					node, err := navigator.Navigate(m.Root, pathValue)
+					m.noteEval(pathValue, time.Since(evalStart), err)
 					if err == nil {
 						// For free-form CEL, avoid NavigateTo to preserve input exactly
 						if (strings.Contains(pathValue, "(") && strings.Contains(pathValue, ")")) || m.isExpression(pathValue) {
 							newModel := InitialModel(node)
+							m.carrySession(&newModel)
 							newModel.Root = m.Root
 							newModel.DebugMode = m.DebugMode
 							newModel.NoColor = m.NoColor
@@ -4628,6 +4868,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						// Create model without altering typed input
 						newModel := InitialModel(node)
+						m.carrySession(&newModel)
 						newModel.Root = m.Root
 						newModel.DebugMode = m.DebugMode
 						newModel.NoColor = m.NoColor
@@ -4648,9 +4889,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					// Navigation failed - show error message
 					// This handles cases like "_.", "_.items[", etc. that are invalid
-					m.setStickyError(fmt.Sprintf("Path error: %v", err))
 					// Keep the input value as-is so user can see what they typed
 					m.PathInput.SetValue(pathValue)
+					m.setStickyExprError(pathValue, err)
 					return m, nil
 				}
 				// Check if the current value exactly matches a root key - if so, navigate directly
@@ -4661,6 +4902,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							if err == nil {
 								// Build model and keep input exactly as typed
 								nm := InitialModel(newNode)
+								m.carrySession(&nm)
 								nm.Root = m.Root
 								nm.DebugMode = m.DebugMode
 								nm.NoColor = m.NoColor
@@ -4697,6 +4939,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							if err == nil {
 								// Build model and keep input exactly as typed
 								nm := InitialModel(newNode)
+								m.carrySession(&nm)
 								nm.Root = m.Root
 								nm.DebugMode = m.DebugMode
 								nm.NoColor = m.NoColor
@@ -4727,6 +4970,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if pathValue == "_" || pathValue == "_." || pathValue == "" {
 					// Go to root but preserve literal expr input
 					newModel := InitialModel(m.Root)
+					m.carrySession(&newModel)
 					newModel.Root = m.Root
 					newModel.DebugMode = m.DebugMode
 					newModel.NoColor = m.NoColor
@@ -4747,12 +4991,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Use unified Navigate interface that handles both dotted paths and CEL
 				newNode, err := navigator.Navigate(m.Root, pathValue)
 				if err != nil {
-					m.setStickyError(fmt.Sprintf("Path error: %v", err))
 					m.PathInput.SetValue(pathValue)
+					m.setStickyExprError(pathValue, err)
 					return m, nil
 				}
 
 				newModel := InitialModel(newNode)
+				m.carrySession(&newModel)
 				newModel.Root = m.Root
 				newModel.DebugMode = m.DebugMode
 				newModel.NoColor = m.NoColor
@@ -4774,8 +5019,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return &newModel, nil
 			case "esc":
 				if m.HelpVisible {
-					m.HelpVisible = false
-					m.applyLayout(true) // Recalculate layout when help closes
+					m.closeHelp()
 				}
 				m.ShowSuggestions = false
 				m.setShowInfoPopup(false)
@@ -4798,8 +5042,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "esc":
 			if m.HelpVisible {
-				m.HelpVisible = false
-				m.applyLayout(true) // Recalculate layout when help closes
+				m.closeHelp()
 				return m, nil
 			}
 			if m.ShowInfoPopup && !m.InfoPopupPermanent {
@@ -4819,7 +5062,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
-				m.Node = restoreNode
+				m.Node = m.chunkNode(restoreNode, restorePath)
 				m.Path = restorePath
 				m.PathInput.SetValue(formatPathForDisplay(restorePath))
 
@@ -4850,11 +5093,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.SyncTableState()
 				return m, nil
 			}
-			// Exit map filter mode if active
-			if m.MapFilterActive {
+			// Exit map filter mode if active; Esc also drops a pinned filter
+			if m.MapFilterActive || m.PinnedFilter != "" {
 				m.MapFilterActive = false
 				m.MapFilterQuery = ""
 				m.MapFilterInput.SetValue("")
+				m.PinnedFilter = ""
 				// Regenerate rows from current node
 				keyW := m.KeyColWidth
 				if keyW <= 0 {
@@ -4871,6 +5115,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.logEvent("cancel-map-filter:esc")
 				return m, nil
 			}
+			// Esc also drops column filters kept after closing the filter row
+			if len(m.ColumnFilters) > 0 {
+				m.clearColumnFilters()
+				m.logEvent("cancel-column-filter:esc")
+				return m, nil
+			}
 			return m, nil
 		case "backspace", "ctrl+h":
 			// Handle backspace in map filter mode
@@ -4922,6 +5172,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// First Enter: Commit the search and perform deep search
 					m.AdvancedSearchCommitted = true
 					m.applyAdvancedSearch()
+					m.ringBell(false)
 					return m, nil
 				}
 
@@ -5005,8 +5256,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.SearchContextBasePath = ""
 			}
 
-			// Clear map filter mode when navigating into a child
+			// Clear map filter mode when navigating into a child; an edited
+			// pinned filter keeps the new query
 			if m.MapFilterActive {
+				if m.PinnedFilter != "" {
+					m.PinnedFilter = m.MapFilterQuery
+				}
 				m.MapFilterActive = false
 				m.MapFilterQuery = ""
 				m.MapFilterInput.SetValue("")
@@ -5109,7 +5364,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m.handleVimForwardNavigation()
 				case VimActionDown, VimActionUp, VimActionSearch, VimActionFilter, VimActionNextMatch, VimActionPrevMatch,
 					VimActionTop, VimActionBottom, VimActionHelp, VimActionCopy, VimActionExpr,
-					VimActionQuit, VimActionClearSearch:
+					VimActionQuit, VimActionClearSearch, VimActionEdit, VimActionOpenURL, VimActionWrap, VimActionSearchSelection,
+					VimActionColumns, VimActionColumnFilter, VimActionColumnManager, VimActionAnnotate, VimActionPeek, VimActionDetail, VimActionRecent:
 					return m.executeVimAction(action)
 				}
 			}
@@ -5126,7 +5382,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m.handleVimForwardNavigation()
 				case VimActionDown, VimActionUp, VimActionSearch, VimActionNextMatch, VimActionPrevMatch,
 					VimActionTop, VimActionBottom, VimActionHelp, VimActionCopy, VimActionExpr,
-					VimActionQuit, VimActionClearSearch, VimActionFilter:
+					VimActionQuit, VimActionClearSearch, VimActionFilter, VimActionEdit, VimActionOpenURL, VimActionWrap, VimActionSearchSelection,
+					VimActionColumns, VimActionColumnFilter, VimActionColumnManager, VimActionAnnotate, VimActionPeek, VimActionDetail, VimActionRecent:
 					return m.executeVimAction(action)
 				}
 			}
@@ -5144,8 +5401,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			// Handle input in map filter mode
 			if m.MapFilterActive {
-				// Capture alphanumeric input and some special characters
-				if len(keyStr) == 1 && ((keyStr[0] >= 'a' && keyStr[0] <= 'z') || (keyStr[0] >= 'A' && keyStr[0] <= 'Z') || (keyStr[0] >= '0' && keyStr[0] <= '9') || keyStr[0] == '_' || keyStr[0] == '-' || keyStr[0] == ' ' || keyStr[0] == '.') {
+				// Space separates filter terms
+				if keyStr == "space" {
+					keyStr = " "
+				}
+				// Capture alphanumeric input, some special characters, the
+				// value (=), type (:) and negation (!) filter prefixes, and
+				// the quotes and backslash escapes of quoted terms
+				if len(keyStr) == 1 && ((keyStr[0] >= 'a' && keyStr[0] <= 'z') || (keyStr[0] >= 'A' && keyStr[0] <= 'Z') || (keyStr[0] >= '0' && keyStr[0] <= '9') || strings.ContainsRune(`_- .=:!"\`, rune(keyStr[0]))) {
 					m.MapFilterQuery += keyStr
 					m.MapFilterInput.SetValue(m.MapFilterQuery)
 					m.MapFilterInput.SetCursor(len(m.MapFilterQuery))
@@ -5263,7 +5526,7 @@ func (m *Model) applyAdvancedSearch() {
 	if m.AdvancedSearchQuery == "" {
 		m.AdvancedSearchResults = []SearchResult{}
 		m.AllRows = []table.Row{}
-		m.PathInput.SetValue("_")
+		m.PathInput.SetValue(formatPathForDisplay(""))
 		// Sync table state (will set empty rows and cursor)
 		m.SyncTableState()
 		return
@@ -5304,6 +5567,13 @@ func (m *Model) applyAdvancedSearch() {
 	results, limited := performAdvancedSearch(searchNode, m.AdvancedSearchQuery, limit)
 	m.SearchResultsLimited = limited
 	m.AdvancedSearchResults = results
+	m.emit(Event{
+		Kind:    EventSearch,
+		Path:    formatPathForDisplay(m.AdvancedSearchBasePath),
+		Query:   m.AdvancedSearchQuery,
+		Results: len(results),
+		Limited: limited,
+	})
 
 	// Note: AllRows will be set by SyncTableState() based on AdvancedSearchResults
 	// Sync table state (will generate rows from search results)
@@ -5318,7 +5588,7 @@ func (m *Model) applyAdvancedSearch() {
 	if len(m.AdvancedSearchResults) > 0 {
 		m.syncPathInputWithCursor()
 	} else {
-		m.PathInput.SetValue("_")
+		m.PathInput.SetValue(formatPathForDisplay(""))
 	}
 }
 
@@ -5352,8 +5622,8 @@ func (m *Model) applyMapFilter() {
 		return
 	}
 
-	// Filter map keys (case-insensitive substring match on keys and values)
-	queryLower := strings.ToLower(m.MapFilterQuery)
+	// Filter map keys by prefix, value, or type (see parseMapFilter)
+	terms := parseMapFilter(m.MapFilterQuery)
 	var filteredRows []table.Row
 	var filteredKeys []string
 
@@ -5368,22 +5638,15 @@ func (m *Model) applyMapFilter() {
 		v := mapNode[k]
 		valueStr := formatter.Stringify(v)
 
-		// Check if key starts with the query (prefix match)
-		keyMatches := strings.HasPrefix(strings.ToLower(k), queryLower)
-
-		if keyMatches {
+		if matchMapFilter(terms, k, v) {
 			// Display key with bracket notation if needed
 			displayKey := k
 			if needsBracketNotation(k) {
 				displayKey = `["` + k + `"]`
 			}
 			// Truncate key and value to fit column widths
-			if len(displayKey) > keyW {
-				displayKey = displayKey[:keyW-3] + "..."
-			}
-			if len(valueStr) > valueW {
-				valueStr = valueStr[:valueW-3] + "..."
-			}
+			displayKey = formatter.TruncateCell(displayKey, keyW)
+			valueStr = formatter.TruncateCell(valueStr, valueW)
 			filteredRows = append(filteredRows, table.Row{displayKey, valueStr})
 			filteredKeys = append(filteredKeys, k)
 		}
@@ -5395,6 +5658,29 @@ func (m *Model) applyMapFilter() {
 	m.syncPathInputWithCursor()
 }
 
+// togglePinnedFilter leaves map filter mode, pinning the typed query so it
+// keeps applying to every node navigated into. An empty query unpins.
+func (m *Model) togglePinnedFilter() {
+	m.PinnedFilter = strings.TrimSpace(m.MapFilterQuery)
+	m.MapFilterQuery = m.PinnedFilter
+	m.MapFilterActive = false
+	m.MapFilterInput.Blur()
+	m.applyMapFilter()
+	if m.PinnedFilter != "" {
+		m.logEvent("pin-map-filter:" + m.PinnedFilter)
+	} else {
+		m.logEvent("unpin-map-filter")
+	}
+}
+
+// pinnedFilterChip is the status bar label for a pinned filter.
+func pinnedFilterChip(query string) string {
+	if query == "" {
+		return ""
+	}
+	return fmt.Sprintf("FILTER: %s (pinned)", query)
+}
+
 // viewSnapshot holds the pre-rendered pieces of the TUI for pure rendering.
 type viewSnapshot struct {
 	Table        string
@@ -5490,6 +5776,12 @@ func isScalarNode(node interface{}) (bool, string) {
 	switch node.(type) {
 	case map[string]interface{}, []interface{}:
 		return false, ""
+	case []byte:
+		return true, "bytes"
+	case string:
+		if _, binary := formatter.BinaryBytes(node); binary {
+			return true, "bytes"
+		}
 	}
 	v := reflect.ValueOf(node)
 	if v.IsValid() {
@@ -5507,7 +5799,7 @@ func isScalarNode(node interface{}) (bool, string) {
 		}
 	}
 	typeLabel := "value"
-	switch node.(type) {
+	switch v := node.(type) {
 	case string:
 		typeLabel = "string"
 	case bool:
@@ -5518,6 +5810,11 @@ func isScalarNode(node interface{}) (bool, string) {
 		typeLabel = "uint"
 	case float32, float64:
 		typeLabel = "float"
+	case json.Number:
+		typeLabel = "float"
+		if t := celhelper.NumberType(v); t != "double" {
+			typeLabel = t
+		}
 	case time.Time:
 		typeLabel = "time"
 	}
@@ -5541,8 +5838,7 @@ func (m Model) renderScalarView() string {
 	if !m.NoColor {
 		valueStyle = valueStyle.Foreground(th.ValueColor)
 	}
-	// Preserve real line breaks in scalar view so users can read multiline content.
-	val := formatter.StringifyPreserveNewlines(m.Node)
+	val := scalarDisplayText(m.Node)
 	valueLine := valueStyle.MaxWidth(width).Render(val)
 	return valueLine
 }
@@ -5676,7 +5972,11 @@ func (m Model) buildViewSnapshot() viewSnapshot {
 			inputLabel = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true).Render(focusedPrompt)
 		}
 	}
-	inputContent := inputLabel + m.PathInput.View()
+	inputView := m.PathInput.View()
+	if highlighted, ok := m.renderHighlightedInput(m.WinWidth - lipgloss.Width(inputLabel)); ok {
+		inputView = highlighted
+	}
+	inputContent := inputLabel + inputView
 	if !m.NoColor {
 		if fg := CurrentTheme().InputFG; fg != nil {
 			inputContent = lipgloss.NewStyle().Foreground(fg).Render(inputContent)
@@ -5772,6 +6072,11 @@ func (m Model) buildViewSnapshot() viewSnapshot {
 			m.Status.CursorIndex = 1
 		}
 	}
+	// Point at the offending character when the status bar sits right under the input.
+	m.Status.ErrCaretCol = 0
+	if snap.Suggestions == "" {
+		m.Status.ErrCaretCol = m.errorCaretColumn(lipgloss.Width(inputLabel))
+	}
 	snap.Status = m.Status.View()
 	if strings.TrimSpace(stripANSI(snap.Status)) == "" {
 		page := ""
@@ -5804,7 +6109,7 @@ func (m *Model) View() tea.View {
 }
 
 func (m *Model) panelLayoutView() tea.View {
-	inputVisible := m.InputFocused || m.AdvancedSearchActive || m.MapFilterActive
+	inputVisible := m.InputFocused || m.AdvancedSearchActive || m.MapFilterActive || m.columnFilterExpr() != ""
 	helpTitle := strings.TrimSpace(m.HelpTitle)
 	if helpTitle == "" {
 		helpTitle = "Help"
@@ -5828,6 +6133,8 @@ func (m *Model) panelLayoutView() tea.View {
 	}
 	v := tea.NewView(view)
 	v.AltScreen = true
+	// Focus reports tell whether a finished status screen is being watched
+	v.ReportFocus = m.Notify
 	// Enable keyboard enhancements for proper modifier key detection (e.g., Shift+Tab)
 	v.KeyboardEnhancements.ReportEventTypes = true
 	return v
@@ -6132,6 +6439,7 @@ func (m *Model) syncStatus() {
 	m.Status.AdvancedSearchResults = m.AdvancedSearchResults
 	m.Status.FilterActive = m.FilterActive
 	m.Status.FilterBuffer = m.FilterBuffer
+	m.Status.PinnedFilter = m.PinnedFilter
 	m.Status.InputFocused = m.InputFocused
 	m.Status.FilteredSuggestions = m.FilteredSuggestions
 	m.Status.SelectedSuggestion = m.SelectedSuggestion
@@ -6140,6 +6448,7 @@ func (m *Model) syncStatus() {
 	m.Status.ShowSuggestionSummary = m.ShowSuggestionSummary
 	m.Status.HelpVisible = m.HelpVisible
 	m.Status.FunctionHelpText = m.detectFunctionHelp()
+	m.Status.Signature = m.activeSignature()
 	m.Status.InputValue = m.PathInput.Value() // Pass input value to status bar
 	m.Status.NoColor = m.NoColor
 	m.Status.SetWidth(m.WinWidth)
@@ -6181,6 +6490,10 @@ func (m *Model) syncStatus() {
 	// decodable string, show a contextual hint so users know they can press
 	// Enter/→ to expand it.
 	m.Status.DecodeHint = m.decodeHintForSelectedRow()
+	m.Status.SourceInfo = strings.TrimSpace(m.sourceInfoForSelectedRow() + "  " + m.schemaDescriptionForSelectedRow())
+	m.Status.PickChip = m.pickChip()
+	m.Status.StreamChip = m.streamChip()
+	m.Status.ColumnStats = m.columnStatsStatus()
 }
 
 // decodeHintForSelectedRow returns a short hint string (e.g. "↵ decode")
@@ -6302,6 +6615,24 @@ func (m *Model) detectFunctionHelp() string {
 	return ""
 }
 
+// activeSignature returns the parameter hint for the function call the cursor
+// is inside of in expression mode, or nil once the call's closing paren is typed.
+func (m *Model) activeSignature() *completion.SignatureHelp {
+	if !m.InputFocused || m.CompletionEngine == nil {
+		return nil
+	}
+	runes := []rune(m.PathInput.Value())
+	pos := m.PathInput.Position()
+	if pos < 0 || pos > len(runes) {
+		pos = len(runes)
+	}
+	sig, ok := m.CompletionEngine.SignatureHelp(string(runes[:pos]))
+	if !ok {
+		return nil
+	}
+	return &sig
+}
+
 // syncFooter updates the footer component with current model state
 func (m *Model) syncFooter() {
 	m.Footer.NoColor = m.NoColor
@@ -6373,6 +6704,15 @@ func (m *Model) handleMenuKey(keyStr string) (bool, tea.Cmd) {
 	}
 
 	item := getItem(keyStr)
+	if item == nil && m.KeyMode == KeyModeFunction {
+		// Function-mode bindings may also be modifier keys such as ctrl+f
+		for _, it := range menu.Items {
+			if it.Enabled && it.Keys.Function == keyStr {
+				item = &it
+				break
+			}
+		}
+	}
 	if item == nil || !item.Enabled {
 		return false, nil
 	}
@@ -6551,7 +6891,7 @@ func menuActionExprToggle(m *Model) tea.Cmd {
 		// Use the current PathInput value directly - it already has the user's expression
 		currentValue := strings.TrimSpace(m.PathInput.Value())
 		if currentValue == "" {
-			currentValue = "_"
+			currentValue = formatPathForDisplay("")
 		}
 		m.ExprDisplay = currentValue
 		m.PathInput.SetValue(currentValue)
@@ -6617,7 +6957,44 @@ func menuActionSearch(m *Model) tea.Cmd {
 	return m.SearchInput.Focus()
 }
 
+// openDescentResults leaves expression mode and lists every value stored
+// under key below base (a "..key" path) as committed search results, so
+// each match shows its path and can be navigated into.
+func (m *Model) openDescentResults(base, key string) error {
+	node, err := navigator.Resolve(m.Root, base)
+	if err != nil {
+		return err
+	}
+	if !isCompositeNode(node) {
+		if base == "" {
+			base = "_"
+		}
+		return fmt.Errorf("%s has no children to search", base)
+	}
+	menuActionExprToggle(m)
+	m.PreviousNode = m.Node
+	m.PreviousPath = m.Path
+	m.PreviousAllRows = append([]table.Row(nil), m.Tbl.Rows()...)
+	m.clearSearchState()
+	m.FilterActive = false
+	m.FilterBuffer = ""
+	m.AdvancedSearchActive = true
+	m.AdvancedSearchCommitted = true
+	m.AdvancedSearchBasePath = normalizePathForModel(base)
+	m.AdvancedSearchQuery = ".." + key
+	m.SearchInput.SetValue(m.AdvancedSearchQuery)
+	m.SearchInput.SetCursor(len(m.AdvancedSearchQuery))
+	m.logEvent("enter-search:descent " + m.AdvancedSearchQuery)
+	m.applyAdvancedSearch()
+	return nil
+}
+
 func menuActionFilter(m *Model) tea.Cmd {
+	// Pressed again while filtering: pin (or unpin) the query.
+	if m.MapFilterActive && !m.InputFocused && !m.AdvancedSearchActive {
+		m.togglePinnedFilter()
+		return nil
+	}
 	// Don't activate if already in input mode or another filter/search
 	if m.InputFocused || m.AdvancedSearchActive || m.MapFilterActive {
 		return nil
@@ -6634,11 +7011,11 @@ func menuActionFilter(m *Model) tea.Cmd {
 	currentRows := m.Tbl.Rows()
 	m.PreviousAllRows = append([]table.Row(nil), currentRows...)
 
-	// Activate map filter mode
+	// Activate map filter mode, starting from the pinned query so it can be edited
 	m.MapFilterActive = true
-	m.MapFilterQuery = ""
-	m.MapFilterInput.SetValue("")
-	m.MapFilterInput.SetCursor(0)
+	m.MapFilterQuery = m.PinnedFilter
+	m.MapFilterInput.SetValue(m.PinnedFilter)
+	m.MapFilterInput.SetCursor(len(m.PinnedFilter))
 	m.FilterActive = false // Clear type-ahead filter
 	m.FilterBuffer = ""
 
@@ -6647,10 +7024,13 @@ func menuActionFilter(m *Model) tea.Cmd {
 }
 
 func menuActionCopy(m *Model) tea.Cmd {
+	if m.kioskRefuses("Copy") {
+		return nil
+	}
 	// Always use PathInput value (works for both input mode and search mode)
 	expr := strings.TrimSpace(m.PathInput.Value())
 	if expr == "" {
-		expr = "_"
+		expr = formatPathForDisplay("")
 	}
 	cliSafe := makePathCLISafe(expr)
 	if err := copyToClipboard(cliSafe); err != nil {
@@ -6664,15 +7044,20 @@ func menuActionCopy(m *Model) tea.Cmd {
 }
 
 func menuActionQuit(m *Model) tea.Cmd {
+	// Kiosk mode quits without printing anything
+	if m.Kiosk {
+		return tea.Quit
+	}
 	// Both expr and table modes use the PathInput value
 	evalExpr := strings.TrimSpace(m.PathInput.Value())
 	if evalExpr == "" {
-		evalExpr = "_"
+		evalExpr = formatPathForDisplay("")
 	}
 	// Expressions are already properly formatted - don't modify them
 	if _, err := m.evaluateExpression(evalExpr, m.Root); err != nil {
 		m.ErrMsg = fmt.Sprintf("explore expression error: %v", err)
 		m.StatusType = "error"
+		m.ringBell(true)
 		return nil
 	}
 	return m.printCLIOutput(evalExpr)
@@ -6836,6 +7221,13 @@ func removeLastSegment(path string) string {
 	if path == "" {
 		return ""
 	}
+	// Above a jq program's result is the root
+	if base, rest, ok := splitJQDrillPath(path); ok {
+		if rest == "" {
+			return ""
+		}
+		return base + removeLastSegment(rest)
+	}
 
 	// Find the last segment boundary, ignoring dots inside ["..."] brackets
 	lastDotIdx := -1
@@ -2274,3 +2274,54 @@ func TestModel_IsExpression_PerInstance(t *testing.T) {
 		assert.False(t, m.isExpression("simple.path"))
 	})
 }
+
+// TestPinnedFilterPersistsAcrossNavigation validates that F4 pins the map
+// filter so it keeps applying to each node navigated into.
+func TestPinnedFilterPersistsAcrossNavigation(t *testing.T) {
+	node := map[string]interface{}{
+		"na":    map[string]interface{}{"name": "x", "id": 1},
+		"nb":    map[string]interface{}{"name": "z", "zone": "eu"},
+		"other": 3,
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.InputFocused = false
+	m.KeyMode = KeyModeFunction
+	m.WinWidth = 80
+	m.WinHeight = 20
+	m.applyLayout(true)
+	m.Tbl.Focus()
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyF4})
+	require.True(t, m.MapFilterActive)
+	m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyF4})
+
+	assert.False(t, m.MapFilterActive, "F4 again leaves the filter input")
+	assert.Equal(t, "n", m.PinnedFilter)
+	assert.Equal(t, []string{"na", "nb"}, m.AllRowKeys)
+
+	m.NavigateTo(node["nb"], "_.nb")
+	assert.Equal(t, []string{"name"}, m.AllRowKeys, "pinned filter applies to the child")
+	state := panelLayoutStateFromModel(&m, PanelLayoutModelOptions{})
+	assert.Contains(t, state.InfoMessage, "FILTER: n (pinned)")
+	assert.Len(t, state.DisplayNode, 1)
+
+	// Reopening the filter starts from the pinned query.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyF4})
+	assert.Equal(t, "n", m.MapFilterQuery)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Empty(t, m.PinnedFilter, "Esc unpins")
+	assert.Len(t, m.AllRowKeys, 2)
+}
+
+func BenchmarkStyleRows(b *testing.B) {
+	rows := make([][]string, 50000)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprintf("key-%05d", i), "value-" + strings.Repeat("x", i%80)}
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		styleRowsWithWidths(rows, 20, 40)
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"strings"
 
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 
 	"github.com/oakwood-commons/kvx/internal/completion"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // categoryOrder defines the display order for function categories in the palette.
@@ -492,8 +492,8 @@ func (m *FunctionPaletteModel) renderCategoryTabs(width int) string {
 		line += suffix
 	}
 	// Truncate the tab line to fit in no-color mode.
-	if m.NoColor && runewidth.StringWidth(line) > width {
-		line = runewidth.Truncate(line, width, "…")
+	if m.NoColor && textwidth.Width(line) > width {
+		line = textwidth.Truncate(line, width, "…")
 	}
 	return line
 }
@@ -510,10 +510,15 @@ func (m *FunctionPaletteModel) renderFunctionLine(fn completion.FunctionMetadata
 		tag = "method"
 	}
 	nameStr := fn.Name + "()"
+	if queryLang == QueryLangJQ {
+		// jq builtins are called without parentheses when they take no arguments
+		tag = "builtin"
+		nameStr = fn.Signature
+	}
 
 	// Build: "▸ name()  [tag]  description…"
 	fixedPart := fmt.Sprintf("%s%-16s [%s]", prefix, nameStr, tag)
-	fixedWidth := runewidth.StringWidth(fixedPart)
+	fixedWidth := textwidth.Width(fixedPart)
 
 	desc := fn.Description
 	descSpace := width - fixedWidth - 2 // 2 for spacing before desc
@@ -525,8 +530,8 @@ func (m *FunctionPaletteModel) renderFunctionLine(fn completion.FunctionMetadata
 	}
 
 	// Final truncation safety net.
-	if runewidth.StringWidth(fixedPart) > width {
-		fixedPart = runewidth.Truncate(fixedPart, width, "…")
+	if textwidth.Width(fixedPart) > width {
+		fixedPart = textwidth.Truncate(fixedPart, width, "…")
 	}
 
 	if !m.NoColor && selected {
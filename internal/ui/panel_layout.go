@@ -5,9 +5,9 @@ import (
 	"strings"
 
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // PanelLayoutInput is the minimal interface needed to render the input panel.
@@ -35,6 +35,8 @@ type PanelLayoutState struct {
 	HelpVisible bool
 	HelpTitle   string
 	HelpText    string
+	HelpScroll  int    // First help line shown when the help does not fit
+	HelpQuery   string // Only help lines containing it are shown
 
 	Title string
 
@@ -42,6 +44,7 @@ type PanelLayoutState struct {
 	InfoError   bool
 
 	InfoPopupText string
+	InfoTitle     string // Title of the info panel (default "Info")
 	HelpPopupText string
 
 	InputVisible    bool
@@ -56,6 +59,9 @@ type PanelLayoutState struct {
 	SearchResults   []searchHit
 	MapFilterActive bool   // 'f' key filter mode for maps
 	PaletteContent  string // Pre-rendered function palette overlay
+	ColumnsContent  string // Pre-rendered column manager overlay
+	PeekContent     string // Pre-rendered peek overlay
+	RecentContent   string // Pre-rendered recent panel
 
 	DisplayNode interface{}
 	Node        interface{}
@@ -63,6 +69,18 @@ type PanelLayoutState struct {
 	SelectedRow int
 	PathLabel   string
 	KeyColWidth int
+	WrapValues  bool // Wrap long values onto continuation lines within their row
+	// Columnar replaces the KEY/VALUE table with one column per field.
+	Columnar *ColumnarPanel
+	// RowMarks flags the annotated rows of the table; when set, a marker
+	// column is drawn in front of it.
+	RowMarks []bool
+	// ChangedRows flags the rows of the table changed by a recent refresh,
+	// which are highlighted.
+	ChangedRows []bool
+	// MoreRows is the sentinel row kept under a table that shows the first
+	// items of a longer array, such as "… 9,500 more (press L to load next 500)".
+	MoreRows string
 
 	// CustomContent overrides the default table rendering when set.
 	// Used by display schema list/detail views.
@@ -73,6 +91,21 @@ type PanelLayoutState struct {
 	CustomFooter string
 }
 
+// syncFormatterTableTheme keeps formatter table colors in sync with the
+// current theme for the main view.
+func syncFormatterTableTheme() {
+	th := CurrentTheme()
+	formatter.SetTableTheme(formatter.TableColors{
+		HeaderFG:       th.HeaderFG,
+		HeaderBG:       th.HeaderBG,
+		KeyColor:       th.KeyColor,
+		ValueColor:     th.ValueColor,
+		SeparatorColor: th.SeparatorColor,
+		OKColor:        th.StatusSuccess,
+		ErrorColor:     th.StatusError,
+	})
+}
+
 // RenderPanelLayout renders the panel layout using precomputed state.
 func RenderPanelLayout(state PanelLayoutState) string {
 	if state.WinWidth <= 0 {
@@ -81,6 +114,11 @@ func RenderPanelLayout(state PanelLayoutState) string {
 	if state.WinHeight <= 0 {
 		state.WinHeight = 24
 	}
+	// Interactive windows too small for the panels get the compact layout.
+	// Snapshots keep the panels and clamp to a minimal canvas instead.
+	if !state.SnapshotHeader && useCompactLayout(state.WinWidth, state.WinHeight) {
+		return renderCompactLayout(state)
+	}
 	// Clamp to a minimal canvas to avoid negative layouts
 	if state.WinHeight < 6 {
 		state.WinHeight = 6
@@ -130,6 +168,8 @@ func RenderPanelLayout(state PanelLayoutState) string {
 			helpWidthLimit = 1
 		}
 		helpContent = renderHelpMarkdown(helpContent, helpWidthLimit, state.NoColor)
+		matched, _, _ := scrollHelpLines(strings.Split(helpContent, "\n"), state.HelpQuery, 0, 0)
+		helpContent = strings.Join(matched, "\n")
 	}
 
 	infoContent := strings.TrimSpace(state.InfoPopupText)
@@ -214,6 +254,12 @@ func RenderPanelLayout(state PanelLayoutState) string {
 
 	var tableText string
 	displayNode := state.DisplayNode
+	// The sentinel row of a chunked array stays in view under the table.
+	tableHeight := dataPanelHeight - 2
+	moreRows := state.MoreRows != "" && state.CustomContent == "" && !state.SearchActive && isCompositeNode(displayNode)
+	if moreRows {
+		tableHeight--
+	}
 	switch {
 	case state.CustomContent != "":
 		// Display schema list/detail view overrides the default table rendering
@@ -230,34 +276,67 @@ func RenderPanelLayout(state PanelLayoutState) string {
 		}
 		// Clamp after highlighting to ensure ANSI codes don't trigger wrapping
 		tableText = clampANSITextWidth(tableText, innerPanelWidth+2)
+	case state.Columnar != nil:
+		syncFormatterTableTheme()
+		if state.RowMarks != nil {
+			tableText = markTableRows(renderColumnarPanel(*state.Columnar, innerPanelWidth-markerColumnWidth, state.NoColor), state.RowMarks, nil)
+		} else {
+			tableText = renderColumnarPanel(*state.Columnar, innerPanelWidth, state.NoColor)
+		}
+		tableText = highlightChangedRows(tableText, state.ChangedRows, nil, state.NoColor)
+		var windowSelected int
+		tableText, windowSelected = windowTable(tableText, selectedRow, tableHeight)
+		if highlightRows {
+			tableText = highlightTableRow(tableText, windowSelected, panelWidth-2, state.NoColor)
+		}
+		tableText = clampANSITextWidth(tableText, innerPanelWidth+2)
 	default:
-		// Keep formatter table colors in sync with the current theme for the main view.
-		th := CurrentTheme()
-		formatter.SetTableTheme(formatter.TableColors{
-			HeaderFG:       th.HeaderFG,
-			HeaderBG:       th.HeaderBG,
-			KeyColor:       th.KeyColor,
-			ValueColor:     th.ValueColor,
-			SeparatorColor: th.SeparatorColor,
-		})
+		syncFormatterTableTheme()
 		// Disable multi-line value rendering for the interactive table.
 		// The TUI cursor tracks logical rows (one per key), so multi-line
 		// expansion would break selection highlighting and navigation.
+		// Wrapping is the exception: the formatter reports which lines
+		// each row spans so the window and highlight cover whole rows.
 		prevLines := formatter.MaxValueLines()
 		formatter.SetMaxValueLines(0)
 		defer formatter.SetMaxValueLines(prevLines)
-		tableText = formatter.RenderTable(displayNode, state.NoColor, keyColWidth, availableForValues, nil)
+		prevWrap := formatter.WrapValues()
+		formatter.SetWrapValues(state.WrapValues)
+		defer formatter.SetWrapValues(prevWrap)
+		var rowSpans [][2]int
+		if state.RowMarks != nil {
+			tableText, rowSpans = formatter.RenderTableRowSpans(displayNode, state.NoColor, keyColWidth, availableForValues-markerColumnWidth, nil)
+			tableText = markTableRows(tableText, state.RowMarks, rowSpans)
+		} else {
+			tableText, rowSpans = formatter.RenderTableRowSpans(displayNode, state.NoColor, keyColWidth, availableForValues, nil)
+		}
+		tableText = highlightChangedRows(tableText, state.ChangedRows, rowSpans, state.NoColor)
 		// Clamp to the inner content width (panel width minus borders) to prevent wrapping.
 		// Clamp with +2 to preserve all three ellipsis dots that truncate() adds.
 		tableText = clampANSITextWidth(tableText, innerPanelWidth+2)
-		var windowSelected int
-		tableText, windowSelected = windowTable(tableText, selectedRow, dataPanelHeight-2)
-		if highlightRows {
-			tableText = highlightTableRow(tableText, windowSelected, panelWidth-2, state.NoColor)
+		if state.WrapValues || len(formatter.WrapKeys()) > 0 {
+			var first, count int
+			tableText, first, count = windowWrappedTable(tableText, rowSpans, selectedRow, tableHeight)
+			if highlightRows {
+				tableText = highlightTableLines(tableText, first, count, panelWidth-2, state.NoColor)
+			}
+		} else {
+			var windowSelected int
+			tableText, windowSelected = windowTable(tableText, selectedRow, tableHeight)
+			if highlightRows {
+				tableText = highlightTableRow(tableText, windowSelected, panelWidth-2, state.NoColor)
+			}
 		}
 		// Final clamp after highlighting so ANSI styling cannot cause wrapping
 		tableText = clampANSITextWidth(tableText, innerPanelWidth+2)
 	}
+	if moreRows {
+		more := clampANSITextWidth(state.MoreRows, innerPanelWidth)
+		if !state.NoColor {
+			more = lipgloss.NewStyle().Faint(true).Render(more)
+		}
+		tableText = strings.TrimRight(tableText, "\n") + "\n" + more
+	}
 	// Clamp to the panel content height so the layout stays within the requested window size
 	tableText = clampANSITextHeight(tableText, dataPanelHeight-2)
 	// Compute counts for label
@@ -317,11 +396,21 @@ func RenderPanelLayout(state PanelLayoutState) string {
 				helpWidth = candidate
 			}
 		}
+		// Help taller than its panel scrolls; the title says which lines show.
+		shown, start, total := scrollHelpLines(strings.Split(helpContent, "\n"), "", state.HelpScroll, helpPanelHeight-2)
+		if len(shown) < total {
+			title = fmt.Sprintf("%s (%d-%d of %d)", title, start+1, start+len(shown), total)
+			helpContent = strings.Join(shown, "\n")
+		}
 		helpPanel = strings.TrimRight(panelWithTitle(title, helpContent, helpWidth, helpPanelHeight, panelBorder, state.NoColor), "\n")
 	}
 	infoPanel := ""
 	if infoPanelHeight > 0 && infoContent != "" {
-		infoPanel = strings.TrimRight(panelWithTitle("Info", infoContent, panelWidth, infoPanelHeight, panelBorder, state.NoColor), "\n")
+		infoTitle := state.InfoTitle
+		if infoTitle == "" {
+			infoTitle = "Info"
+		}
+		infoPanel = strings.TrimRight(panelWithTitle(infoTitle, infoContent, panelWidth, infoPanelHeight, panelBorder, state.NoColor), "\n")
 	}
 
 	statusPanel := ""
@@ -367,7 +456,7 @@ func RenderPanelLayout(state PanelLayoutState) string {
 			styled := style.Render(line)
 			msgWidth := ansiVisibleWidth(styled)
 			if msgWidth > statusPanelWidth {
-				styled = runewidth.Truncate(styled, statusPanelWidth, "")
+				styled = textwidth.Truncate(styled, statusPanelWidth, "")
 				msgWidth = ansiVisibleWidth(styled)
 			}
 			padding := statusPanelWidth - msgWidth
@@ -429,7 +518,7 @@ func RenderPanelLayout(state PanelLayoutState) string {
 				inputView = ansiRegexp.ReplaceAllString(inputView, "")
 			}
 			if inputValue != "" {
-				desiredWidth := runewidth.StringWidth(inputValue)
+				desiredWidth := textwidth.Width(inputValue)
 				if state.ExprMode || state.SearchActive || state.MapFilterActive {
 					desiredWidth++
 				}
@@ -492,7 +581,7 @@ func RenderPanelLayout(state PanelLayoutState) string {
 			lines = lines[:target]
 		}
 		for len(lines) < target {
-			width := runewidth.StringWidth(lines[0])
+			width := textwidth.Width(lines[0])
 			if width < 2 {
 				width = 2
 			}
@@ -524,10 +613,19 @@ func RenderPanelLayout(state PanelLayoutState) string {
 	paletteLines := split(state.PaletteContent)
 	dataLines := split(dataPanel)
 	p3Lines := split(statusPanel)
-	// When the function palette is open, it replaces the help panel area.
+	columnsLines := split(state.ColumnsContent)
+	peekLines := split(state.PeekContent)
+	recentLines := split(state.RecentContent)
+	// When the function palette, column manager, peek overlay, or recent panel is open, it replaces the help panel area.
 	overlayLines := helpLines
 	if len(paletteLines) > 0 {
 		overlayLines = paletteLines
+	} else if len(columnsLines) > 0 {
+		overlayLines = columnsLines
+	} else if len(peekLines) > 0 {
+		overlayLines = peekLines
+	} else if len(recentLines) > 0 {
+		overlayLines = recentLines
 	}
 	mainLines := append(append(overlayLines, dataLines...), p3Lines...)
 	inputLines := split(inputPanel)
@@ -572,7 +670,7 @@ func RenderPanelLayout(state PanelLayoutState) string {
 		if ansiVisibleWidth(bottomLine) < bottomWidth {
 			bottomLine = padANSIToWidth(bottomLine, bottomWidth)
 		} else if ansiVisibleWidth(bottomLine) > bottomWidth {
-			bottomLine = runewidth.Truncate(bottomLine, bottomWidth, "")
+			bottomLine = textwidth.Truncate(bottomLine, bottomWidth, "")
 		}
 		footerStyle := lipgloss.NewStyle()
 		if !state.NoColor {
@@ -46,8 +46,8 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 			filteredCount = len(filtered)
 		}
 	}
-	// Handle 'f' key map filter mode
-	if !m.AdvancedSearchActive && m.MapFilterActive {
+	// Handle 'f' key map filter mode, live or pinned
+	if !m.AdvancedSearchActive && (m.MapFilterActive || m.PinnedFilter != "") {
 		if cur, ok := m.Node.(map[string]interface{}); ok {
 			if m.MapFilterQuery == "" {
 				// Empty query shows all keys
@@ -55,11 +55,11 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 				filteredCount = len(cur)
 			} else {
 				filtered := map[string]interface{}{}
-				queryLower := strings.ToLower(m.MapFilterQuery)
-				for k := range cur {
-					// Match key prefix (same logic as applyMapFilter)
-					if strings.HasPrefix(strings.ToLower(k), queryLower) {
-						filtered[k] = cur[k]
+				terms := parseMapFilter(m.MapFilterQuery)
+				for k, v := range cur {
+					// Same matching as applyMapFilter
+					if matchMapFilter(terms, k, v) {
+						filtered[k] = v
 					}
 				}
 				displayNode = filtered
@@ -121,6 +121,14 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 	if m.HelpVisible && popupText != "" {
 		helpText = ""
 	}
+	// The schema's docs for the current node lead the help overlay.
+	if schemaHelp := m.schemaHelpText(); m.HelpVisible && schemaHelp != "" {
+		if popupText != "" {
+			popupText = schemaHelp + "\n\n" + popupText
+		} else {
+			helpText = schemaHelp + "\n\n" + helpText
+		}
+	}
 
 	infoMessage := ""
 	infoError := false
@@ -139,10 +147,32 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 			if infoMessage == "" && m.ShowSuggestionSummary && m.SuggestionSummary != "" {
 				infoMessage = m.SuggestionSummary
 			}
+		} else if stats := m.columnStatsStatus(); stats != "" {
+			infoMessage = stats
 		} else if m.DecodedActive {
 			infoMessage = "✓ decoded"
-		} else if hint := m.decodeHintForSelectedRow(); hint != "" {
-			infoMessage = hint
+		} else {
+			// Where the selected value was written and its comment, its
+			// schema description, then the decode hint if any.
+			infoMessage = strings.TrimSpace(m.sourceInfoForSelectedRow() + "  " + m.schemaDescriptionForSelectedRow() + "  " + m.decodeHintForSelectedRow())
+		}
+		if chip := pinnedFilterChip(m.PinnedFilter); chip != "" && !m.MapFilterActive && !m.InputFocused {
+			infoMessage = strings.TrimSpace(chip + "  " + infoMessage)
+		}
+		if !m.InputFocused {
+			for _, chip := range []string{m.annotationChip(), m.pickChip()} {
+				if chip != "" {
+					infoMessage = strings.TrimSpace(chip + "  " + infoMessage)
+				}
+			}
+		}
+		if m.AnnotationInput {
+			infoMessage = m.annotationPrompt()
+			infoError = false
+		}
+		if status := m.helpStatus(); m.HelpVisible && status != "" {
+			infoMessage = status
+			infoError = false
 		}
 	}
 
@@ -159,6 +189,8 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 		HelpVisible:     m.HelpVisible,
 		HelpTitle:       opts.HelpTitle,
 		HelpText:        helpText,
+		HelpScroll:      m.HelpScroll,
+		HelpQuery:       m.HelpQuery,
 		Title:           title,
 		InfoMessage:     infoMessage,
 		InfoError:       infoError,
@@ -174,17 +206,32 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 		SearchResults:   searchResults,
 		MapFilterActive: m.MapFilterActive,
 		PaletteContent:  paletteContent(m),
+		ColumnsContent:  columnManagerContent(m),
+		PeekContent:     peekContent(m),
+		RecentContent:   recentContent(m),
 		DisplayNode:     displayNode,
 		Node:            m.Node,
 		RowCount:        rowCount,
 		SelectedRow:     selected,
 		PathLabel:       pathLabel,
 		KeyColWidth:     m.KeyColWidth,
+		WrapValues:      m.WrapValues,
+	}
+
+	if fields := m.columnarFields(); fields != nil {
+		state.Columnar = m.columnarPanel(fields)
+	}
+	if !m.AdvancedSearchActive {
+		state.RowMarks = m.annotationRowMarks(displayNode, state.Columnar)
+		state.ChangedRows = m.changedRowMarks(displayNode, state.Columnar)
+		state.MoreRows = m.chunkMoreRows()
 	}
 
 	// Apply custom view mode content (list/detail views)
 	if customContent, ok := m.renderCustomViewContent(); ok {
 		state.CustomContent = customContent
+		state.RowMarks = nil
+		state.ChangedRows = nil
 		// Title is in the top border; clear the bottom-left path label
 		// so it doesn't show a meaningless path like "_.code".
 		state.PathLabel = ""
@@ -202,6 +249,11 @@ func panelLayoutStateFromModel(m *Model, opts PanelLayoutModelOptions) PanelLayo
 		}
 	}
 
+	if m.Tutorial != nil {
+		state.InfoTitle = m.Tutorial.tutorialTitle()
+		state.InfoPopupText = m.Tutorial.tutorialText(m.KeyMode)
+	}
+
 	return state
 }
 
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+)
+
+// vimPeek shows the selected value whole (same as ctrl+v).
+func (m *Model) vimPeek() (tea.Model, tea.Cmd) {
+	return m, menuActionPeek(m)
+}
+
+// menuActionPeek opens the peek overlay, which shows the selected row's
+// value without truncation, or closes it.
+func menuActionPeek(m *Model) tea.Cmd {
+	if m.Peek != nil {
+		m.Peek = nil
+		return nil
+	}
+	pick, ok := m.selectedPick()
+	if !ok {
+		return nil
+	}
+	m.Peek = &pick
+	m.PeekScroll = 0
+	m.clearErrorUnlessSticky()
+	return nil
+}
+
+// handlePeekKey handles keys while the peek overlay is open: up/down
+// scroll a long value, y copies it, and esc, enter, or the peek binding
+// close the overlay. Other keys are ignored so they do not reach the
+// table underneath; ctrl+c still quits.
+func (m *Model) handlePeekKey(keyStr string) bool {
+	if m.Peek == nil {
+		return false
+	}
+	switch keyStr {
+	case "ctrl+c":
+		return false
+	case "up", "k", "ctrl+p":
+		m.PeekScroll = max(0, m.PeekScroll-1)
+	case "down", "j", "ctrl+n":
+		m.PeekScroll++
+	case "y", "alt+w":
+		if m.kioskRefuses("Copy") {
+			break
+		}
+		if err := copyToClipboard(formatter.StringifyPreserveNewlines(m.Peek.Value)); err != nil {
+			m.ErrMsg = fmt.Sprintf("Clipboard unavailable: %v", err)
+			m.StatusType = "error"
+		} else {
+			m.ErrMsg = fmt.Sprintf("Copied value of %s", m.Peek.Path)
+			m.StatusType = "success"
+		}
+	case "esc", "enter", "q":
+		m.Peek = nil
+	default:
+		if item, ok := CurrentMenuConfig().Items["peek"]; ok && item.Keys.forMode(m.KeyMode) == keyStr {
+			m.Peek = nil
+		}
+	}
+	return true
+}
+
+// peekLines returns the value of the peek overlay wrapped to width: one
+// "field: value" entry per field of an object, in column order in the
+// columnar view, or the value's text.
+func (m *Model) peekLines(width int) []string {
+	var text []string
+	if obj, ok := m.Peek.Value.(map[string]interface{}); ok {
+		fields := m.columnarAllFields()
+		if fields == nil {
+			fields = slices.Sorted(maps.Keys(obj))
+		}
+		for _, f := range fields {
+			text = append(text, f+": "+formatter.StringifyPreserveNewlines(obj[f]))
+		}
+	} else {
+		text = []string{formatter.StringifyPreserveNewlines(m.Peek.Value)}
+	}
+	var lines []string
+	for _, t := range text {
+		for l := range strings.SplitSeq(t, "\n") {
+			lines = append(lines, textwidth.Wrap(l, width)...)
+		}
+	}
+	return lines
+}
+
+// peekContent renders the peek overlay, shown in place of the help panel
+// like the column manager.
+func peekContent(m *Model) string {
+	if m == nil || m.Peek == nil {
+		return ""
+	}
+	width := m.WinWidth
+	if width <= 0 {
+		width = 80
+	}
+	// Same height budget as the column manager: ~40% of the window.
+	inner := min(max(m.WinHeight*2/5, 6), 18)
+	visible := inner - 2 // the first line is the path, the last the key hint
+	lines := m.peekLines(width - 2)
+	m.PeekScroll = max(0, min(m.PeekScroll, len(lines)-visible))
+	end := min(len(lines), m.PeekScroll+visible)
+
+	bold := lipgloss.NewStyle()
+	muted := lipgloss.NewStyle()
+	if !m.NoColor {
+		bold = bold.Bold(true)
+		muted = muted.Foreground(lipgloss.Color("243"))
+	}
+	out := make([]string, 0, inner)
+	out = append(out, bold.Render(formatter.TruncateCell(m.Peek.Path, width-2)))
+	out = append(out, lines[m.PeekScroll:end]...)
+	for len(out) < inner-1 {
+		out = append(out, "")
+	}
+	hint := "y copy  esc close"
+	if len(lines) > visible {
+		hint = fmt.Sprintf("↑↓ scroll (%d-%d of %d lines)  %s", m.PeekScroll+1, end, len(lines), hint)
+	}
+	out = append(out, muted.Render(hint))
+
+	rendered := panelWithTitle("Peek", strings.Join(out, "\n"), width, inner+2, borderForTheme(CurrentTheme()), m.NoColor)
+	return strings.TrimRight(rendered, "\n") + "\n"
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekOpens(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'p', Text: "p"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'p', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: 'v', Mod: tea.ModCtrl}},
+	} {
+		m := testColumnarModel(tc.mode)
+		m.Update(tc.key)
+		require.NotNil(t, m.Peek, tc.mode)
+		assert.Equal(t, "_[0]", m.Peek.Path, tc.mode)
+
+		// Keys for the table do not move the row under the overlay.
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		assert.Equal(t, 0, m.Tbl.Cursor(), tc.mode)
+
+		// The binding closes the overlay again.
+		m.Update(tc.key)
+		assert.Nil(t, m.Peek, tc.mode)
+	}
+}
+
+func TestPeekShowsWholeValue(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+	node := map[string]interface{}{"note": long, "id": 1}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = KeyModeVim
+	m.InputFocused = false
+	m.NoColor = true
+	m.WinWidth = 60
+	m.WinHeight = 24
+	m.Tbl.Focus()
+	m.applyLayout(true)
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	require.NotNil(t, m.Peek)
+	assert.Equal(t, "_.note", m.Peek.Path)
+
+	content := stripANSI(peekContent(&m))
+	assert.Contains(t, content, "Peek")
+	assert.Equal(t, 40, strings.Count(content, "word"), "every word of the value is shown")
+	for line := range strings.SplitSeq(strings.TrimRight(content, "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), 60)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Nil(t, m.Peek)
+}
+
+func TestPeekListsFieldsOfRow(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.ColumnOrder = []string{"tier", "status", "name"}
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	require.NotNil(t, m.Peek)
+	assert.Equal(t, []string{"tier: gold", "status: active", "name: alpha"}, m.peekLines(40), "fields follow the column order")
+}
+
+func TestPeekScrollsLongValue(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.NoColor = true
+	m.Peek = &Pick{Path: "_.text", Value: strings.Repeat("line\n", 30)}
+	for range 40 {
+		m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	}
+	content := stripANSI(peekContent(m))
+	assert.Contains(t, content, "of 31 lines")
+	assert.Equal(t, 31-7, m.PeekScroll, "scrolling stops at the last line")
+}
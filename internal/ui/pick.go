@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// Pick is a value picked in pick mode and the expression that reads it
+// from the root, such as _.items[2].name.
+type Pick struct {
+	Path  string
+	Value interface{}
+}
+
+// pickHint is shown in the status bar when pick mode starts.
+func (m *Model) pickHint() string {
+	if m.PickMulti {
+		return "Pick mode: space marks rows, enter picks the marked rows (or the selected one), quitting picks nothing"
+	}
+	return "Pick mode: enter picks the selected value, quitting picks nothing"
+}
+
+// handlePickKey picks the selected row with enter in pick mode and quits.
+// With PickMulti, space marks and unmarks the selected row and enter
+// picks the marked rows, or the selected row when none is marked. Both
+// keys keep their usual meaning while text is being typed: in the
+// expression bar, a search that is not committed yet, or a filter.
+func (m *Model) handlePickKey(keyStr string) (bool, tea.Cmd) {
+	if !m.PickMode || m.InputFocused || m.MapFilterActive {
+		return false, nil
+	}
+	if m.AdvancedSearchActive && (!m.AdvancedSearchCommitted || m.ListPanelMode != "") {
+		return false, nil
+	}
+	switch {
+	case keyStr == "enter":
+		if len(m.PickMarks) > 0 {
+			m.Picks = append([]Pick(nil), m.PickMarks...)
+			return true, tea.Quit
+		}
+		p, ok := m.selectedPick()
+		if !ok {
+			return true, nil
+		}
+		m.Picks = []Pick{p}
+		return true, tea.Quit
+	case keyStr == "space" && m.PickMulti:
+		p, ok := m.selectedPick()
+		if !ok {
+			return true, nil
+		}
+		if i := m.pickMarkIndex(p.Path); i >= 0 {
+			m.PickMarks = append(m.PickMarks[:i], m.PickMarks[i+1:]...)
+		} else {
+			m.PickMarks = append(m.PickMarks, p)
+		}
+		m.clearErrorUnlessSticky()
+		return true, nil
+	}
+	return false, nil
+}
+
+// selectedPick evaluates the selected row, reporting an error in the
+// status bar when it cannot be read.
+func (m *Model) selectedPick() (Pick, bool) {
+	path := formatPathForDisplay(m.selectedPickPath())
+	value, err := m.evaluateExpression(path, m.Root)
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Cannot pick %s: %v", path, err)
+		m.StatusType = "error"
+		return Pick{}, false
+	}
+	return Pick{Path: path, Value: value}, true
+}
+
+// pickMarkIndex returns the index of the mark for path, or -1.
+func (m *Model) pickMarkIndex(path string) int {
+	for i, p := range m.PickMarks {
+		if p.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// pickChip is the status bar note of the marked rows: their count, after
+// a check mark when the selected row is one of them.
+func (m *Model) pickChip() string {
+	if len(m.PickMarks) == 0 {
+		return ""
+	}
+	chip := fmt.Sprintf("%d marked", len(m.PickMarks))
+	if m.pickMarkIndex(formatPathForDisplay(m.selectedPickPath())) >= 0 {
+		chip = "✓ " + chip
+	}
+	return chip
+}
+
+// selectedPickPath returns the path of the highlighted list item or row.
+func (m *Model) selectedPickPath() string {
+	if m.ViewMode == "list" && m.ListViewState != nil {
+		items := filterListItems(m.ListViewState)
+		if sel := m.ListViewState.Selected; sel >= 0 && sel < len(items) {
+			if path, _, ok := listItemChild(m.Node, m.Path, items[sel]); ok {
+				return path
+			}
+		}
+	}
+	return m.selectedRowPath()
+}
+
+// carrySession keeps pick mode, the annotations, kiosk mode, the
+// notification, idle, and refresh settings, the data controller, the record
+// stream, --save-layout, the visit log, the session stats, the tutorial, the
+// key recording and replay, the startup key script's error hook, and
+// deterministic rendering on a model that replaces m, such as the result of
+// an expression entered in the expression bar.
+func (m *Model) carrySession(to *Model) {
+	to.PickMode = m.PickMode
+	to.PickMulti = m.PickMulti
+	to.PickMarks = m.PickMarks
+	to.OnExitPicks = m.OnExitPicks
+	to.Annotations = m.Annotations
+	to.OnExitAnnotations = m.OnExitAnnotations
+	to.Notify = m.Notify
+	to.Unfocused = m.Unfocused
+	to.Bell = m.Bell
+	to.VisualBell = m.VisualBell
+	to.IdleTimeout = m.IdleTimeout
+	to.IdlePrint = m.IdlePrint
+	to.lastInput = m.lastInput
+	to.Kiosk = m.Kiosk
+	to.OnExitLayout = m.OnExitLayout
+	to.Refresh = m.Refresh
+	to.RefreshInterval = m.RefreshInterval
+	to.DataController = m.DataController
+	to.Stream = m.Stream
+	to.WatchPath = m.WatchPath
+	to.WatchLoad = m.WatchLoad
+	to.watchStamp = m.watchStamp
+	to.Visits = m.Visits
+	to.OnExitVisits = m.OnExitVisits
+	to.OnEvent = m.OnEvent
+	to.Stats = m.Stats
+	to.OnExitStats = m.OnExitStats
+	to.visitPath = m.visitPath
+	to.Tutorial = m.Tutorial
+	to.KeyRecorder = m.KeyRecorder
+	to.Replay = m.Replay
+	to.OnScriptError = m.OnScriptError
+	to.SetDeterministic(m.Deterministic)
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickModeEnterPicksSelectedRow(t *testing.T) {
+	for _, mode := range []KeyMode{KeyModeVim, KeyModeEmacs, KeyModeFunction} {
+		m := testColumnarModel(mode)
+		m.PickMode = true
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		require.NotNil(t, cmd, mode)
+		assert.IsType(t, tea.QuitMsg{}, cmd(), mode)
+		assert.Equal(t, []Pick{{
+			Path:  "_[1]",
+			Value: map[string]interface{}{"name": "beta", "status": "archived", "tier": "silver"},
+		}}, m.Picks, mode)
+	}
+}
+
+func TestPickModeRightStillNavigates(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.PickMode = true
+	m.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	assert.Empty(t, m.Picks)
+	assert.Equal(t, "_[0]", formatPathForDisplay(m.Path))
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, []Pick{{Path: "_[0].name", Value: "alpha"}}, m.Picks)
+}
+
+func TestPickModeOffEnterNavigates(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Empty(t, m.Picks)
+	assert.Equal(t, "_[0]", formatPathForDisplay(m.Path))
+}
+
+func TestCarrySession(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.PickMode = true
+	var picked []Pick
+	m.OnExitPicks = func(p []Pick) { picked = p }
+
+	nm := InitialModel(m.Root)
+	m.carrySession(&nm)
+	assert.True(t, nm.PickMode)
+	nm.OnExitPicks([]Pick{{Path: "_"}})
+	assert.Len(t, picked, 1)
+}
+
+func TestPickMultiMarksRows(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.PickMode = true
+	m.PickMulti = true
+	space := tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(space)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(space)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(space)
+	m.Update(space) // unmark again
+	m.syncStatus()
+	assert.Equal(t, "2 marked", m.Status.PickChip)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.syncStatus()
+	assert.Equal(t, "✓ 2 marked", m.Status.PickChip)
+	assert.Contains(t, stripANSI(m.View().Content), "✓ 2 marked")
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	var paths []string
+	for _, p := range m.Picks {
+		paths = append(paths, p.Path)
+	}
+	assert.Equal(t, []string{"_[2]", "_[0]"}, paths)
+}
+
+func TestPickMultiEnterWithoutMarksPicksSelected(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.PickMode = true
+	m.PickMulti = true
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Len(t, m.Picks, 1)
+	assert.Equal(t, "_[0]", m.Picks[0].Path)
+}
@@ -2,18 +2,24 @@ package ui
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
+	"unicode/utf16"
 )
 
-// copyToClipboardFn and openURLFn are the active implementations for clipboard
-// and browser operations. Tests replace them with no-ops via
-// stubPlatformActions() to prevent side effects.
+// copyToClipboardFn, openURLFn, and notifyFn are the active implementations
+// for clipboard, browser, and notification operations. Tests replace them
+// with no-ops via stubPlatformActions() to prevent side effects.
 var (
 	copyToClipboardFn = copyToClipboardImpl
 	openURLFn         = openURLImpl
+	notifyFn          = notifyImpl
 )
 
 // CopyToClipboard copies text to the system clipboard.
@@ -22,46 +28,111 @@ func CopyToClipboard(text string) error { return copyToClipboardFn(text) }
 // OpenURL opens a URL in the default browser.
 func OpenURL(url string) error { return openURLFn(url) }
 
-// StubPlatformActions replaces clipboard and browser functions with no-ops
-// and returns a restore function. Use in tests to prevent side effects.
+// Notify shows a desktop notification.
+func Notify(title, body string) error { return notifyFn(title, body) }
+
+// StubPlatformActions replaces clipboard, browser, and notification functions
+// with no-ops and returns a restore function. Use in tests to prevent side
+// effects.
 func StubPlatformActions() (restore func()) {
 	origCopy := copyToClipboardFn
 	origOpen := openURLFn
+	origNotify := notifyFn
 	copyToClipboardFn = func(string) error { return nil }
 	openURLFn = func(string) error { return nil }
+	notifyFn = func(string, string) error { return nil }
 	return func() {
 		copyToClipboardFn = origCopy
 		openURLFn = origOpen
+		notifyFn = origNotify
 	}
 }
 
-// copyToClipboardImpl is the real clipboard implementation.
-func copyToClipboardImpl(text string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+// lookPath finds clipboard commands on PATH. Tests replace it to simulate
+// other platforms.
+var lookPath = exec.LookPath
 
-	var cmd *exec.Cmd
+// clipboardCommand is one way of writing text to the system clipboard.
+type clipboardCommand struct {
+	name string
+	args []string
+	// utf16 marks commands that read UTF-16LE with a byte order mark. clip.exe
+	// reads anything else in the console's OEM code page, mangling non-ASCII text.
+	utf16 bool
+}
 
-	switch runtime.GOOS {
+// clipboardCommands returns the clipboard commands for goos in order of
+// preference.
+func clipboardCommands(goos string) []clipboardCommand {
+	switch goos {
 	case "darwin":
-		cmd = exec.CommandContext(ctx, "pbcopy")
+		return []clipboardCommand{{name: "pbcopy"}}
 	case "linux":
-		// Try xclip first, then xsel, then wl-copy (Wayland)
-		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.CommandContext(ctx, "xclip", "-selection", "clipboard")
-		} else if _, err := exec.LookPath("xsel"); err == nil {
-			cmd = exec.CommandContext(ctx, "xsel", "--clipboard", "--input")
-		} else if _, err := exec.LookPath("wl-copy"); err == nil {
-			cmd = exec.CommandContext(ctx, "wl-copy")
-		} else {
-			return fmt.Errorf("no clipboard command found (install xclip, xsel, or wl-clipboard)")
+		return []clipboardCommand{
+			{name: "xclip", args: []string{"-selection", "clipboard"}},
+			{name: "xsel", args: []string{"--clipboard", "--input"}},
+			{name: "wl-copy"},
+			// Under WSL the Windows clipboard is reachable through clip.exe.
+			{name: "clip.exe", utf16: true},
 		}
 	case "windows":
-		cmd = exec.CommandContext(ctx, "clip")
+		return []clipboardCommand{{name: "clip.exe", utf16: true}}
 	default:
+		return nil
+	}
+}
+
+// encodeUTF16LE encodes text as UTF-16LE with a byte order mark.
+func encodeUTF16LE(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 2, 2+2*len(units))
+	out[0], out[1] = 0xFF, 0xFE
+	for _, u := range units {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	return out
+}
+
+// copyToClipboardImpl is the real clipboard implementation.
+func copyToClipboardImpl(text string) error {
+	candidates := clipboardCommands(runtime.GOOS)
+	if candidates == nil {
 		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
+	for _, c := range candidates {
+		path, err := lookPath(c.name)
+		if err != nil {
+			continue
+		}
+		data := []byte(text)
+		if c.utf16 {
+			data = encodeUTF16LE(text)
+		}
+		return runClipboardCommand(path, c.args, data)
+	}
+	if runtime.GOOS == "linux" {
+		return fmt.Errorf("no clipboard command found (install xclip, xsel, or wl-clipboard)")
+	}
+	return fmt.Errorf("no clipboard command found (%s)", candidates[0].name)
+}
 
+// ClipboardCommand returns the name of the command the copy action would use,
+// or "" when none is installed.
+func ClipboardCommand() string {
+	for _, c := range clipboardCommands(runtime.GOOS) {
+		if _, err := lookPath(c.name); err == nil {
+			return c.name
+		}
+	}
+	return ""
+}
+
+// runClipboardCommand writes data to the stdin of the clipboard command.
+func runClipboardCommand(path string, args []string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, args...)
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return err
@@ -71,7 +142,7 @@ func copyToClipboardImpl(text string) error {
 		return err
 	}
 
-	_, _ = stdin.Write([]byte(text))
+	_, _ = stdin.Write(data)
 	_ = stdin.Close()
 
 	return cmd.Wait()
@@ -101,3 +172,97 @@ func openURLImpl(url string) error {
 
 	return cmd.Start()
 }
+
+// notifyCommand returns the command showing a desktop notification on goos:
+// osascript on macOS, notify-send on Linux, and a PowerShell balloon tip on
+// Windows. ok is false on other platforms.
+func notifyCommand(goos, title, body string) (name string, args []string, ok bool) {
+	switch goos {
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, quote(body), quote(title))
+		return "osascript", []string{"-e", script}, true
+	case "linux":
+		return "notify-send", []string{title, body}, true
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := "Add-Type -AssemblyName System.Windows.Forms; " +
+			"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+			"$n.Icon = [System.Drawing.SystemIcons]::Information; " +
+			"$n.Visible = $true; " +
+			"$n.ShowBalloonTip(5000, " + quote(title) + ", " + quote(body) + ", 'Info'); " +
+			"Start-Sleep -Seconds 6; $n.Dispose()"
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// notifyImpl is the real notification implementation. The command is not
+// waited for: the PowerShell balloon stays up for a few seconds.
+func notifyImpl(title, body string) error {
+	name, args, ok := notifyCommand(runtime.GOOS, title, body)
+	if !ok {
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+	path, err := lookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found", name)
+	}
+	return exec.CommandContext(context.Background(), path, args...).Start()
+}
+
+// ColorSupported reports whether ANSI colors and box drawing written to f
+// display correctly. On Windows this first enables virtual terminal
+// processing for the console; legacy consoles that lack it print escape
+// sequences literally and should get plain output instead.
+func ColorSupported(f *os.File) bool {
+	return enableVirtualTerminal(f)
+}
+
+// ConfigFileCandidates returns the locations checked for the user config
+// file, in order of preference.
+func ConfigFileCandidates() []string {
+	home, _ := os.UserHomeDir()
+	return configFileCandidates(runtime.GOOS, os.Getenv, home)
+}
+
+// configFileCandidates returns $XDG_CONFIG_HOME/kvx/config.yaml when
+// XDG_CONFIG_HOME is set. Otherwise it returns ~/.config/kvx/config.yaml and,
+// on Windows, %APPDATA%\kvx\config.yaml.
+func configFileCandidates(goos string, getenv func(string) string, home string) []string {
+	if xdg := getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return []string{filepath.Join(xdg, "kvx", "config.yaml")}
+	}
+	var out []string
+	if home != "" {
+		out = append(out, filepath.Join(home, ".config", "kvx", "config.yaml"))
+	}
+	if goos == "windows" {
+		if appData := getenv("APPDATA"); appData != "" {
+			out = append(out, filepath.Join(appData, "kvx", "config.yaml"))
+		}
+	}
+	return out
+}
+
+// ExpandHome replaces a leading "~" in path with the user's home directory.
+// Shells on Unix do this before kvx sees the argument; PowerShell and cmd.exe
+// do not.
+func ExpandHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return expandHome(path, home)
+}
+
+func expandHome(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestMain stubs platform actions (clipboard, browser) so that no test in the
@@ -13,3 +19,101 @@ func TestMain(m *testing.M) {
 	restore()
 	os.Exit(code)
 }
+
+func TestClipboardCommands(t *testing.T) {
+	names := func(goos string) []string {
+		var out []string
+		for _, c := range clipboardCommands(goos) {
+			out = append(out, c.name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"pbcopy"}, names("darwin"))
+	assert.Equal(t, []string{"xclip", "xsel", "wl-copy", "clip.exe"}, names("linux"))
+	assert.Equal(t, []string{"clip.exe"}, names("windows"))
+	assert.Nil(t, clipboardCommands("plan9"))
+
+	for _, c := range clipboardCommands("windows") {
+		assert.True(t, c.utf16, "clip.exe needs UTF-16 input")
+	}
+}
+
+func TestClipboardCommand(t *testing.T) {
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	assert.Empty(t, ClipboardCommand())
+
+	cmds := clipboardCommands(runtime.GOOS)
+	if len(cmds) == 0 {
+		t.Skip("no clipboard commands on " + runtime.GOOS)
+	}
+	want := cmds[len(cmds)-1].name
+	lookPath = func(name string) (string, error) {
+		if name == want {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	assert.Equal(t, want, ClipboardCommand())
+}
+
+func TestNotifyCommand(t *testing.T) {
+	name, args, ok := notifyCommand("darwin", `Sign "in"`, `C:\done`)
+	require.True(t, ok)
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "C:\\done" with title "Sign \"in\""`}, args)
+
+	name, args, ok = notifyCommand("linux", "kvx", "Done")
+	require.True(t, ok)
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"kvx", "Done"}, args)
+
+	name, args, ok = notifyCommand("windows", "kvx", "It's done")
+	require.True(t, ok)
+	assert.Equal(t, "powershell", name)
+	assert.Contains(t, args[len(args)-1], `ShowBalloonTip(5000, 'kvx', 'It''s done', 'Info')`)
+
+	_, _, ok = notifyCommand("plan9", "kvx", "Done")
+	assert.False(t, ok)
+}
+
+func TestEncodeUTF16LE(t *testing.T) {
+	assert.Equal(t, []byte{0xFF, 0xFE, 'a', 0, 0xE9, 0}, encodeUTF16LE("aé"))
+	// Characters outside the BMP become a surrogate pair.
+	assert.Equal(t, []byte{0xFF, 0xFE, 0x3D, 0xD8, 0x00, 0xDE}, encodeUTF16LE("😀"))
+}
+
+func TestConfigFileCandidates(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	home := filepath.Join("home", "u")
+
+	assert.Equal(t,
+		[]string{filepath.Join("xdg", "kvx", "config.yaml")},
+		configFileCandidates("windows", env(map[string]string{"XDG_CONFIG_HOME": "xdg", "APPDATA": "roaming"}), home))
+	assert.Equal(t,
+		[]string{filepath.Join(home, ".config", "kvx", "config.yaml")},
+		configFileCandidates("linux", env(map[string]string{"APPDATA": "roaming"}), home))
+	assert.Equal(t,
+		[]string{filepath.Join(home, ".config", "kvx", "config.yaml"), filepath.Join("roaming", "kvx", "config.yaml")},
+		configFileCandidates("windows", env(map[string]string{"APPDATA": "roaming"}), home))
+}
+
+func TestExpandHome(t *testing.T) {
+	home := filepath.Join("home", "u")
+	assert.Equal(t, home, expandHome("~", home))
+	assert.Equal(t, filepath.Join(home, "kvx.yaml"), expandHome("~/kvx.yaml", home))
+	assert.Equal(t, filepath.Join(home, "kvx.yaml"), expandHome(`~\kvx.yaml`, home))
+	assert.Equal(t, "~user/kvx.yaml", expandHome("~user/kvx.yaml", home))
+	assert.Equal(t, "data/kvx.yaml", expandHome("data/kvx.yaml", home))
+}
+
+func TestColorSupportedForRedirectedOutput(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	assert.True(t, ColorSupported(f), "files are not consoles and are left alone")
+}
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// recentLimit is how many paths the recent panel lists.
+const recentLimit = 20
+
+// Visit records that the user opened a path, or an expression, during the
+// session.
+type Visit struct {
+	Path string    `json:"path"` // Path or expression as shown in the path input, e.g. _.items[0]
+	At   time.Time `json:"at"`   // When the view moved there
+}
+
+// recentPath is a line of the recent panel: a path and how often it was
+// visited this session.
+type recentPath struct {
+	Path   string
+	Visits int
+}
+
+// noteVisit adds the current path to the visit log, and reports it to
+// OnEvent, when the view has moved since the last visit.
+func (m *Model) noteVisit() {
+	if len(m.Visits) > 0 && m.Path == m.visitPath {
+		return
+	}
+	m.visitPath = m.Path
+	path := formatPathForDisplay(m.Path)
+	if n := len(m.Visits); n > 0 && m.Visits[n-1].Path == path {
+		return
+	}
+	at := time.Now()
+	m.Visits = append(m.Visits, Visit{Path: path, At: at})
+	m.Stats.noteVisit(m.Path)
+	m.emit(Event{Kind: EventNavigate, At: at, Path: path})
+}
+
+// recentPaths returns the paths visited this session, most recent first,
+// leaving out the current path.
+func (m *Model) recentPaths() []recentPath {
+	current := formatPathForDisplay(m.Path)
+	counts := map[string]int{}
+	for _, v := range m.Visits {
+		counts[v.Path]++
+	}
+	var out []recentPath
+	seen := map[string]bool{current: true}
+	for i := len(m.Visits) - 1; i >= 0 && len(out) < recentLimit; i-- {
+		path := m.Visits[i].Path
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		out = append(out, recentPath{Path: path, Visits: counts[path]})
+	}
+	return out
+}
+
+// vimRecent opens or closes the recent panel (same as ctrl+r).
+func (m *Model) vimRecent() (tea.Model, tea.Cmd) {
+	return m, menuActionRecent(m)
+}
+
+// menuActionRecent opens the recent panel, which lists the paths visited
+// this session to jump back to, or closes it.
+func menuActionRecent(m *Model) tea.Cmd {
+	if m.RecentOpen {
+		m.RecentOpen = false
+		return nil
+	}
+	if len(m.recentPaths()) == 0 {
+		m.ErrMsg = "No other paths visited yet"
+		m.StatusType = "error"
+		return nil
+	}
+	m.RecentOpen = true
+	m.RecentIndex = 0
+	m.clearErrorUnlessSticky()
+	return nil
+}
+
+// handleRecentKey handles keys while the recent panel is open: up/down
+// select a path, enter jumps to it, and esc or the recent binding close
+// the panel. Other keys are ignored so they do not reach the table
+// underneath; ctrl+c still quits.
+func (m *Model) handleRecentKey(keyStr string) bool {
+	if !m.RecentOpen {
+		return false
+	}
+	recent := m.recentPaths()
+	if len(recent) == 0 {
+		m.RecentOpen = false
+		return false
+	}
+	m.RecentIndex = max(0, min(m.RecentIndex, len(recent)-1))
+	switch keyStr {
+	case "ctrl+c":
+		return false
+	case "up", "k", "ctrl+p":
+		m.RecentIndex = max(0, m.RecentIndex-1)
+	case "down", "j", "ctrl+n":
+		m.RecentIndex = min(len(recent)-1, m.RecentIndex+1)
+	case "enter":
+		m.RecentOpen = false
+		m.jumpTo(recent[m.RecentIndex].Path)
+	case "esc", "q":
+		m.RecentOpen = false
+	default:
+		if item, ok := CurrentMenuConfig().Items["recent"]; ok && item.Keys.forMode(m.KeyMode) == keyStr {
+			m.RecentOpen = false
+		}
+	}
+	return true
+}
+
+// jumpTo shows path, evaluated on the data the session started from.
+func (m *Model) jumpTo(path string) {
+	base := m.Root
+	if m.RootExpr != "" {
+		node, err := m.evaluateExpression(m.RootExpr, m.Root)
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("%s: %v", path, err)
+			m.StatusType = "error"
+			return
+		}
+		base = node
+	}
+	node, err := navigator.Navigate(base, path)
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("%s: %v", path, err)
+		m.StatusType = "error"
+		return
+	}
+	m.NavigateTo(node, path)
+	m.syncPathInputWithCursor()
+}
+
+// recentContent renders the recent panel, shown in place of the help panel
+// like the column manager.
+func recentContent(m *Model) string {
+	if m == nil || !m.RecentOpen {
+		return ""
+	}
+	recent := m.recentPaths()
+	if len(recent) == 0 {
+		return ""
+	}
+	width := m.WinWidth
+	if width <= 0 {
+		width = 80
+	}
+	// Same height budget as the column manager: ~40% of the window.
+	inner := min(max(m.WinHeight*2/5, 6), 18)
+	visible := inner - 1 // the last line is the key hint
+	start := 0
+	if m.RecentIndex >= visible {
+		start = m.RecentIndex - visible + 1
+	}
+	end := min(len(recent), start+visible)
+
+	selected := lipgloss.NewStyle()
+	muted := lipgloss.NewStyle()
+	if !m.NoColor {
+		selected = selected.Bold(true).Foreground(lipgloss.Color("6"))
+		muted = muted.Foreground(lipgloss.Color("243"))
+	}
+	lines := make([]string, 0, inner)
+	for i := start; i < end; i++ {
+		count := ""
+		if recent[i].Visits > 1 {
+			count = fmt.Sprintf("  (%d×)", recent[i].Visits)
+		}
+		path := formatter.TruncateCell(recent[i].Path, width-6-len([]rune(count)))
+		if i == m.RecentIndex {
+			lines = append(lines, selected.Render("▸ "+path)+muted.Render(count))
+		} else {
+			lines = append(lines, "  "+path+muted.Render(count))
+		}
+	}
+	for len(lines) < visible {
+		lines = append(lines, "")
+	}
+	lines = append(lines, muted.Render("↑↓ select  enter jump  esc close"))
+
+	rendered := panelWithTitle("Recent", strings.Join(lines, "\n"), width, inner+2, borderForTheme(CurrentTheme()), m.NoColor)
+	return strings.TrimRight(rendered, "\n") + "\n"
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecentModel(mode KeyMode) *Model {
+	node := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1},
+		"b": map[string]interface{}{"y": 2},
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = mode
+	m.InputFocused = false
+	m.NoColor = true
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.Tbl.Focus()
+	m.applyLayout(true)
+	return &m
+}
+
+// visitAB opens _.a, goes back to the root, and opens _.b.
+func visitAB(m *Model) {
+	m.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyLeft})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+}
+
+func TestVisitLog(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	visitAB(m)
+	// Keys that do not move the view add nothing.
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+
+	var paths []string
+	for _, v := range m.Visits {
+		paths = append(paths, v.Path)
+		assert.False(t, v.At.IsZero())
+	}
+	assert.Equal(t, []string{"_", "_.a", "_", "_.b"}, paths)
+	assert.Equal(t, []recentPath{{Path: "_", Visits: 2}, {Path: "_.a", Visits: 1}}, m.recentPaths())
+}
+
+func TestRecentJumpsBack(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'r', Text: "r"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'r', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}},
+	} {
+		m := testRecentModel(tc.mode)
+		visitAB(m)
+		require.Equal(t, "_.b", m.Path, tc.mode)
+
+		m.Update(tc.key)
+		require.True(t, m.RecentOpen, tc.mode)
+		content := stripANSI(recentContent(m))
+		assert.Contains(t, content, "Recent", tc.mode)
+		assert.Contains(t, content, "▸ _  (2×)", tc.mode)
+		assert.Contains(t, content, "_.a", tc.mode)
+
+		// Keys for the table move the selection in the panel instead.
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		assert.Equal(t, 1, m.RecentIndex, tc.mode)
+		m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		assert.False(t, m.RecentOpen, tc.mode)
+		assert.Equal(t, "_.a", m.Path, tc.mode)
+		assert.Equal(t, "_.a", m.Visits[len(m.Visits)-1].Path, tc.mode)
+
+		// The binding closes the panel again.
+		m.Update(tc.key)
+		require.True(t, m.RecentOpen, tc.mode)
+		m.Update(tc.key)
+		assert.False(t, m.RecentOpen, tc.mode)
+	}
+}
+
+func TestRecentNeedsAnotherPath(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	assert.False(t, m.RecentOpen)
+	assert.Equal(t, "No other paths visited yet", m.ErrMsg)
+}
+
+func TestVisitsCarriedAcrossExpressions(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	visitAB(m)
+	nm := InitialModel(m.Root)
+	m.carrySession(&nm)
+	nm.Path = "_.a.x"
+	nm.noteVisit()
+	require.Len(t, nm.Visits, 5)
+	assert.Equal(t, "_.a.x", nm.Visits[4].Path)
+}
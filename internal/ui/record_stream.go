@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// streamInterval is how often the view takes the records a RecordStream
+// read since the last look.
+const streamInterval = 250 * time.Millisecond
+
+// RecordStream feeds records read in the background into a running viewer
+// (--stream), whose root is the array of the records read so far. The view
+// keeps its path, selected row, and column filters as records arrive, as
+// with --refresh, and the status bar counts them until the stream ends. Its
+// methods are safe for concurrent use.
+type RecordStream struct {
+	mu      sync.Mutex
+	records []any
+	shown   int // Records the view has taken
+	done    bool
+	err     error
+}
+
+// NewRecordStream returns an empty stream.
+func NewRecordStream() *RecordStream {
+	return &RecordStream{}
+}
+
+// Append adds records to the stream.
+func (s *RecordStream) Append(records ...any) {
+	s.mu.Lock()
+	s.records = append(s.records, records...)
+	s.mu.Unlock()
+}
+
+// Close ends the stream; err, when not nil, is why it ended early and is
+// shown in the status bar.
+func (s *RecordStream) Close(err error) {
+	s.mu.Lock()
+	s.done, s.err = true, err
+	s.mu.Unlock()
+}
+
+// Records returns the records read so far. The slice is shared with the
+// stream; it must not be modified.
+func (s *RecordStream) Records() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records[:len(s.records):len(s.records)]
+}
+
+// Len returns the number of records read so far.
+func (s *RecordStream) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// take returns the records read so far, whether any arrived since the last
+// take, and whether the stream has ended and why.
+func (s *RecordStream) take() (records []any, changed, done bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.records)
+	changed = n != s.shown
+	s.shown = n
+	return s.records[:n:n], changed, s.done, s.err
+}
+
+// streamTickMsg is sent when the view is due to take new records.
+type streamTickMsg struct{}
+
+func streamTick() tea.Cmd {
+	return tea.Tick(streamInterval, func(time.Time) tea.Msg { return streamTickMsg{} })
+}
+
+// handleStreamTick shows the records that arrived since the last tick. While
+// a refresh is blocked they stay with the stream until the next tick. Ticks
+// stop once the stream has ended and its last records are shown.
+func (m *Model) handleStreamTick() tea.Cmd {
+	s := m.Stream
+	if s == nil {
+		return nil
+	}
+	if m.refreshBlocked() {
+		return streamTick()
+	}
+	records, changed, done, err := s.take()
+	var cmd tea.Cmd
+	if changed {
+		cmd = m.applyRefresh(records)
+	}
+	if !done {
+		return tea.Batch(cmd, streamTick())
+	}
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("stream: %v", err)
+		m.StatusType = "error"
+	} else {
+		m.ErrMsg = fmt.Sprintf("%d records loaded", len(records))
+		m.StatusType = "success"
+	}
+	return cmd
+}
+
+// streamChip is the status bar note of a stream still reading.
+func (m *Model) streamChip() string {
+	s := m.Stream
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return ""
+	}
+	return fmt.Sprintf("%d records loaded (streaming)", len(s.records))
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordStream(t *testing.T) {
+	s := NewRecordStream()
+	s.Append(map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2})
+	m := InitialModel(s.Records())
+	m.Stream = s
+	require.NotNil(t, m.Init(), "ticks while streaming")
+	m.Tbl.SetCursor(1)
+
+	s.Append(map[string]interface{}{"id": 3})
+	m.syncStatus()
+	assert.Equal(t, "3 records loaded (streaming)", m.Status.StreamChip)
+	_, cmd := m.Update(streamTickMsg{})
+	assert.NotNil(t, cmd, "ticks again until the stream ends")
+	assert.Len(t, m.Node, 3)
+	assert.Equal(t, 1, m.Tbl.Cursor(), "the selected row is kept")
+	assert.Contains(t, m.ChangedRows, "_[2]")
+
+	s.Close(nil)
+	m.syncStatus()
+	assert.Empty(t, m.Status.StreamChip)
+	_, cmd = m.Update(streamTickMsg{})
+	assert.Nil(t, cmd, "stops ticking once the stream has ended")
+	assert.Equal(t, "3 records loaded", m.ErrMsg)
+	assert.Equal(t, "success", m.StatusType)
+}
+
+func TestRecordStream_BlockedAndError(t *testing.T) {
+	s := NewRecordStream()
+	m := InitialModel(s.Records())
+	m.Stream = s
+	s.Append("a")
+	m.HelpVisible = true
+	assert.NotNil(t, m.handleStreamTick(), "tries again")
+	assert.Empty(t, m.Node, "records wait for the overlay to close")
+
+	m.HelpVisible = false
+	s.Close(errors.New("unexpected EOF"))
+	m.handleStreamTick()
+	assert.Equal(t, []interface{}{"a"}, m.Node)
+	assert.Equal(t, "stream: unexpected EOF", m.ErrMsg)
+	assert.Equal(t, "error", m.StatusType)
+}
+
+func TestRecordStream_ConcurrentAppend(t *testing.T) {
+	s := NewRecordStream()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() { s.Append(i) })
+	}
+	wg.Wait()
+	records, changed, done, err := s.take()
+	assert.Len(t, records, 10)
+	assert.True(t, changed)
+	assert.False(t, done)
+	assert.NoError(t, err)
+	_, changed, _, _ = s.take()
+	assert.False(t, changed, "records are taken once")
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// refreshTickMsg is sent when the input is due to be read again.
+type refreshTickMsg struct{}
+
+// refreshedMsg carries the input read again by Refresh.
+type refreshedMsg struct {
+	root interface{}
+	err  error
+}
+
+// refreshTick returns a tea.Cmd that sends refreshTickMsg after d.
+func refreshTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return refreshTickMsg{} })
+}
+
+// refreshBlocked reports whether the user is in the middle of something a
+// refresh would throw away: typing an expression, searching, filtering,
+// or reading an overlay. Refreshes wait until they are done.
+func (m *Model) refreshBlocked() bool {
+	return m.InputFocused || m.AdvancedSearchActive || m.SearchContextActive ||
+		m.FilterActive || m.MapFilterActive || m.ColumnFilterActive ||
+		m.ColumnManagerOpen || m.Peek != nil || m.RecentOpen || m.HelpVisible || m.ViewMode != ""
+}
+
+// handleRefreshTick reads the input again in the background, or waits
+// another interval while a refresh is blocked.
+func (m *Model) handleRefreshTick() tea.Cmd {
+	if m.RefreshInterval <= 0 || m.Refresh == nil {
+		return nil
+	}
+	if m.refreshBlocked() {
+		return refreshTick(m.RefreshInterval)
+	}
+	refresh := m.Refresh
+	return func() tea.Msg {
+		root, err := refresh()
+		return refreshedMsg{root: root, err: err}
+	}
+}
+
+// handleRefreshed shows the input read again and schedules the next
+// refresh. A failed read keeps the data shown and says why in the status bar.
+func (m *Model) handleRefreshed(msg refreshedMsg) tea.Cmd {
+	if m.RefreshInterval <= 0 || m.Refresh == nil {
+		return nil
+	}
+	switch {
+	case msg.err != nil:
+		m.ErrMsg = fmt.Sprintf("refresh: %v", msg.err)
+		m.StatusType = "error"
+	case !m.refreshBlocked():
+		return tea.Batch(m.applyRefresh(msg.root), refreshTick(m.RefreshInterval))
+	}
+	return refreshTick(m.RefreshInterval)
+}
+
+// applyRefresh replaces the data with root and shows the current path or
+// expression evaluated on it again, keeping the selected row and the column
+// filters. When the path no longer exists, the view goes back to the top.
+// It returns the tea.Cmd fading the highlight of the rows that changed.
+func (m *Model) applyRefresh(root interface{}) tea.Cmd {
+	path := m.Path
+	cursor := m.Tbl.Cursor()
+	filters := m.ColumnFilters
+	before := rowValues(m.Node)
+
+	m.Root = root
+	m.LimitedFull = nil
+	m.limitRoot(activeLimiterConfig)
+	base := m.Root
+	if m.RootExpr != "" {
+		node, err := m.evaluateExpression(m.RootExpr, m.Root)
+		if err != nil {
+			m.NavigateTo(m.Root, "")
+			m.ErrMsg = fmt.Sprintf("refresh: %v", err)
+			m.StatusType = "error"
+			return nil
+		}
+		base = node
+	}
+	node := base
+	if path != "" {
+		var err error
+		if node, err = navigator.Navigate(base, path); err != nil {
+			m.NavigateTo(base, "")
+			m.ErrMsg = fmt.Sprintf("refresh: %s is gone", formatPathForDisplay(path))
+			m.StatusType = "error"
+			return nil
+		}
+	}
+	m.NavigateTo(node, path)
+	if len(filters) > 0 {
+		m.ColumnFilters = filters
+		m.applyColumnFilters()
+	}
+	m.Tbl.SetCursor(min(cursor, max(len(m.Tbl.Rows())-1, 0)))
+	m.SyncTableState()
+	m.syncPathInputWithCursor()
+	return m.noteChanges(before)
+}
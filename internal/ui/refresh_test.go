@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func refreshModel(root interface{}) *Model {
+	m := InitialModel(root)
+	m.Root = root
+	m.Refresh = func() (interface{}, error) { return nil, errors.New("unused") }
+	m.RefreshInterval = time.Millisecond
+	return &m
+}
+
+func TestHandleRefreshTick(t *testing.T) {
+	m := refreshModel(map[string]interface{}{"n": 1})
+	m.Refresh = func() (interface{}, error) { return map[string]interface{}{"n": 2}, nil }
+	cmd := m.handleRefreshTick()
+	require.NotNil(t, cmd)
+	assert.Equal(t, refreshedMsg{root: map[string]interface{}{"n": 2}}, cmd())
+
+	m.InputFocused = true
+	cmd = m.handleRefreshTick()
+	require.NotNil(t, cmd, "waits for the next interval")
+	_, blocked := cmd().(refreshedMsg)
+	assert.False(t, blocked, "does not read while an expression is being typed")
+
+	m.RefreshInterval = 0
+	assert.Nil(t, m.handleRefreshTick())
+}
+
+func TestApplyRefresh_KeepsPathAndCursor(t *testing.T) {
+	m := refreshModel(map[string]interface{}{"items": []interface{}{"a", "b", "c"}})
+	m.NavigateTo(m.Root.(map[string]interface{})["items"], "_.items")
+	m.Tbl.SetCursor(2)
+
+	assert.NotNil(t, m.handleRefreshed(refreshedMsg{root: map[string]interface{}{"items": []interface{}{"a", "b", "c", "d"}}}))
+	assert.Equal(t, "_.items", m.Path)
+	assert.Equal(t, []interface{}{"a", "b", "c", "d"}, m.Node)
+	assert.Equal(t, 2, m.Tbl.Cursor())
+
+	m.applyRefresh(map[string]interface{}{"other": 1})
+	assert.Empty(t, m.Path, "a path that is gone goes back to the top")
+	assert.Equal(t, "refresh: _.items is gone", m.ErrMsg)
+}
+
+func TestApplyRefresh_RootExpr(t *testing.T) {
+	m := refreshModel(map[string]interface{}{"items": []interface{}{1, 2, 3}})
+	applyInitialExpr(m, "_.items.filter(x, x > 1)")
+	require.Equal(t, "_.items.filter(x, x > 1)", m.RootExpr)
+
+	m.applyRefresh(map[string]interface{}{"items": []interface{}{1, 2, 3, 4}})
+	assert.Len(t, m.Node, 3, "the -e expression is evaluated on the new data")
+}
+
+func TestHandleRefreshed_Error(t *testing.T) {
+	m := refreshModel(map[string]interface{}{"n": 1})
+	assert.NotNil(t, m.handleRefreshed(refreshedMsg{err: errors.New("file vanished")}))
+	assert.Equal(t, "refresh: file vanished", m.ErrMsg)
+	assert.Equal(t, map[string]interface{}{"n": 1}, m.Node, "the data shown is kept")
+}
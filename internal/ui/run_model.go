@@ -38,8 +38,10 @@ func RunModel(appName string, root interface{}, helpTitle, helpText string, debu
 	if configure != nil {
 		configure(&m)
 	}
+	m.limitRoot(activeLimiterConfig)
 	// Trigger custom view mode detection (list/detail) now that DisplaySchema may be set.
-	m.updateViewMode(root)
+	m.updateViewMode(m.Root)
+	m.chunkRoot()
 
 	// Eager auto-decode: recursively decode all serialized scalars at load time
 	if m.AllowDecode && m.AutoDecode == "eager" {
@@ -52,6 +54,14 @@ func RunModel(appName string, root interface{}, helpTitle, helpText string, debu
 
 	applyInitialExpr(&m, initialExpr)
 
+	if m.Deterministic {
+		if width <= 0 {
+			width = DeterministicWidth
+		}
+		if height <= 0 {
+			height = DeterministicHeight
+		}
+	}
 	if width > 0 || height > 0 {
 		runW := width
 		runH := height
@@ -86,6 +96,11 @@ func RunModel(appName string, root interface{}, helpTitle, helpText string, debu
 		m.HelpVisible = true
 	}
 
+	if m.PickMode && m.ErrMsg == "" {
+		m.ErrMsg = m.pickHint()
+		m.StatusType = "success"
+	}
+
 	m.ApplyColorScheme()
 	m.applyLayout(true)
 	m.syncAllComponents()
@@ -94,8 +109,32 @@ func RunModel(appName string, root interface{}, helpTitle, helpText string, debu
 	finalModel, err := prog.Run()
 	if finalModel != nil {
 		if fm, ok := finalModel.(*Model); ok && fm != nil {
+			fm.stopWatch()
 			flushDebugEvents(fm, debugSink)
-			printPendingCLIExpr(fm)
+			if fm.IdleExited {
+				fmt.Fprintf(os.Stderr, "exited after %s without input\n", fm.IdleTimeout)
+			}
+			if !fm.PickMode {
+				printPendingCLIExpr(fm)
+			}
+			if fm.OnExitViewState != nil {
+				fm.OnExitViewState(fm.CurrentViewState())
+			}
+			if fm.OnExitLayout != nil {
+				fm.OnExitLayout(fm.CurrentLayout())
+			}
+			if fm.OnExitPicks != nil {
+				fm.OnExitPicks(fm.Picks)
+			}
+			if fm.OnExitAnnotations != nil {
+				fm.OnExitAnnotations(fm.Annotations)
+			}
+			if fm.OnExitVisits != nil {
+				fm.OnExitVisits(fm.Visits)
+			}
+			if fm.OnExitStats != nil && fm.Stats != nil {
+				fm.OnExitStats(fm.Stats.finish())
+			}
 		}
 	}
 	return err
@@ -109,6 +148,10 @@ func applyInitialExpr(m *Model, expr string) {
 	if trimmed == "" {
 		return
 	}
+	if isJQProgram(trimmed) {
+		applyInitialJQ(m, trimmed)
+		return
+	}
 	node, err := navigator.Navigate(m.Root, trimmed)
 	if err != nil {
 		m.ErrMsg = fmt.Sprintf("explore expression error: %v", err)
@@ -125,6 +168,7 @@ func applyInitialExpr(m *Model, expr string) {
 		} else {
 			// Non-path expression: keep behavior of landing at root path.
 			m.NavigateTo(node, "")
+			m.RootExpr = trimmed
 		}
 		m.setExprResult(trimmed, node)
 		m.PathInput.SetValue(trimmed)
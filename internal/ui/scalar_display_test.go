@@ -42,3 +42,17 @@ func TestScalarDisplayFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestScalarDisplayTextBinary(t *testing.T) {
+	got := scalarDisplayText("\x89PNG\r\n\x1a\n")
+	lines := strings.Split(got, "\n")
+	if lines[0] != "<binary 8 bytes: 89 50 4e 47 0d 0a 1a 0a>" {
+		t.Fatalf("unexpected placeholder line: %q", lines[0])
+	}
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "00000000  89 50 4e 47") {
+		t.Fatalf("expected hexdump after a blank line, got %q", got)
+	}
+	if ok, label := isScalarNode("\x89PNG"); !ok || label != "bytes" {
+		t.Fatalf("expected bytes scalar, got %v %q", ok, label)
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/oakwood-commons/kvx/internal/navigator"
+)
+
+// detailSchema returns the display schema the detail view renders with: the
+// DisplaySchema when it has a detail layout, else one holding SchemaDetail,
+// the layout derived from a plain JSON Schema, or nil when there is neither.
+func (m *Model) detailSchema() *DisplaySchema {
+	if m.DisplaySchema != nil && m.DisplaySchema.Detail != nil {
+		return m.DisplaySchema
+	}
+	if m.SchemaDetail != nil {
+		return &DisplaySchema{Version: "v1", Detail: m.SchemaDetail}
+	}
+	return nil
+}
+
+// vimDetail opens the detail view of the selected element (same as ctrl+d).
+func (m *Model) vimDetail() (tea.Model, tea.Cmd) {
+	return m, menuActionDetail(m)
+}
+
+// menuActionDetail opens the sectioned detail view of the selected element
+// of an array of objects, labelled by the schema's property titles, or goes
+// back to the array from it.
+func menuActionDetail(m *Model) tea.Cmd {
+	if m.ViewMode == "detail" {
+		_, cmd := m.navigateBack()
+		return cmd
+	}
+	if _, ok := m.Node.([]interface{}); !ok {
+		return nil
+	}
+	if m.detailSchema() == nil {
+		m.ErrMsg = "The detail view needs a --schema describing the records"
+		m.StatusType = "error"
+		return nil
+	}
+	key, ok := m.selectedRowKey()
+	if !ok {
+		return nil
+	}
+	path := buildPathWithKey(m.Path, key)
+	node, err := navigator.Resolve(m.Root, path)
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Error: %v", err)
+		m.StatusType = "error"
+		return nil
+	}
+	if _, ok := node.(map[string]interface{}); !ok {
+		m.ErrMsg = fmt.Sprintf("%s is not an object", formatPathForDisplay(path))
+		m.StatusType = "error"
+		return nil
+	}
+
+	m.storeCursorForPath(m.Path)
+	m.DetailSourcePath = m.Path
+	m.ViewMode = "detail"
+	m.NavigateTo(node, normalizePathForModel(path))
+	m.PathKeys = parsePathKeys(m.Path)
+	m.applyLayout(true)
+	return nil
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchemaDetailModel(mode KeyMode) *Model {
+	node := []interface{}{
+		map[string]interface{}{"user_id": "u001", "full_name": "Alice Smith"},
+		map[string]interface{}{"user_id": "u002", "full_name": "Bob Jones"},
+	}
+	m := InitialModel(node)
+	m.Root = node
+	m.KeyMode = mode
+	m.InputFocused = false
+	m.WinWidth = 80
+	m.WinHeight = 24
+	m.SchemaDetail = &DetailDisplayConfig{
+		Labels:       map[string]string{"user_id": "ID", "full_name": "Name"},
+		Descriptions: map[string]string{"full_name": "User's full name"},
+	}
+	m.Tbl.Focus()
+	m.applyLayout(true)
+	return &m
+}
+
+func TestDetailKeyOpensSchemaDetailView(t *testing.T) {
+	for _, tc := range []struct {
+		mode KeyMode
+		key  tea.KeyPressMsg
+	}{
+		{KeyModeVim, tea.KeyPressMsg{Code: 'd', Text: "d"}},
+		{KeyModeEmacs, tea.KeyPressMsg{Code: 'd', Mod: tea.ModAlt}},
+		{KeyModeFunction, tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl}},
+	} {
+		m := testSchemaDetailModel(tc.mode)
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		m.Update(tc.key)
+		require.Equal(t, "detail", m.ViewMode, tc.mode)
+		require.NotNil(t, m.DetailViewState, tc.mode)
+		content := stripANSI(m.View().Content)
+		assert.Contains(t, content, "Name  Bob Jones", tc.mode)
+		assert.Contains(t, content, "User's full name", tc.mode)
+		assert.Contains(t, content, "ID    u002", tc.mode)
+
+		// The detail key goes back to the array, on the same row.
+		m.Update(tc.key)
+		assert.Empty(t, m.ViewMode, tc.mode)
+		assert.Equal(t, "", m.Path, tc.mode)
+		assert.Equal(t, 1, m.Tbl.Cursor(), tc.mode)
+	}
+}
+
+func TestDetailKeyEscGoesBack(t *testing.T) {
+	m := testSchemaDetailModel(KeyModeVim)
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	require.Equal(t, "detail", m.ViewMode)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Empty(t, m.ViewMode)
+	assert.Nil(t, m.DetailViewState)
+}
+
+func TestDetailKeyWithoutSchema(t *testing.T) {
+	m := testSchemaDetailModel(KeyModeVim)
+	m.SchemaDetail = nil
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	assert.Empty(t, m.ViewMode)
+	assert.Equal(t, "The detail view needs a --schema describing the records", m.ErrMsg)
+}
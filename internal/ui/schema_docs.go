@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/textwidth"
+)
+
+// SchemaDoc holds the titles and descriptions of a JSON Schema, shaped like
+// the documents it describes: one node per object, property, and array
+// item schema.
+type SchemaDoc struct {
+	Title       string
+	Description string
+	Properties  map[string]*SchemaDoc // Documented properties of an object
+	Items       *SchemaDoc            // Elements of an array
+	Additional  *SchemaDoc            // Values of a map (additionalProperties)
+}
+
+// Child returns the documentation of the value at key inside a value d
+// describes: a property, an array element for an index, or a map value.
+// Returns nil when the schema does not describe it.
+func (d *SchemaDoc) Child(key string) *SchemaDoc {
+	if d == nil {
+		return nil
+	}
+	key = strings.Trim(key, `"'`)
+	if c, ok := d.Properties[key]; ok {
+		return c
+	}
+	if _, err := strconv.Atoi(key); err == nil && d.Items != nil {
+		return d.Items
+	}
+	return d.Additional
+}
+
+// schemaDocAt returns the documentation of the value at path, or nil.
+func (m *Model) schemaDocAt(path string) *SchemaDoc {
+	doc := m.SchemaDocs
+	keys := parsePathKeys(path)
+	if len(keys) > 0 && keys[0] == "_" {
+		keys = keys[1:]
+	}
+	for _, key := range keys {
+		if doc = doc.Child(key); doc == nil {
+			return nil
+		}
+	}
+	return doc
+}
+
+// schemaDescriptionForSelectedRow returns the schema description of the
+// selected row's key, flattened to one line and cut to the window width,
+// or "".
+func (m *Model) schemaDescriptionForSelectedRow() string {
+	if m.SchemaDocs == nil || m.InputFocused || m.AdvancedSearchActive || m.HelpVisible {
+		return ""
+	}
+	key, ok := m.selectedSourceKey()
+	if !ok {
+		return ""
+	}
+	return m.schemaDescription(m.schemaDocAt(m.Path).Child(key))
+}
+
+// schemaDescriptionForColumn returns the schema description of a field of
+// the elements of the current array, for the focused columnar header.
+func (m *Model) schemaDescriptionForColumn(field string) string {
+	if m.SchemaDocs == nil {
+		return ""
+	}
+	return m.schemaDescription(m.schemaDocAt(m.Path).Child("0").Child(field))
+}
+
+func (m *Model) schemaDescription(doc *SchemaDoc) string {
+	if doc == nil {
+		return ""
+	}
+	desc := strings.Join(strings.Fields(doc.Description), " ")
+	if m.WinWidth > 0 {
+		desc = textwidth.Truncate(desc, m.WinWidth, "…")
+	}
+	return desc
+}
+
+// schemaHelpText returns the help overlay section documenting the current
+// node from the schema: its title and description, then the description of
+// each documented field (of the elements, for an array), or "".
+func (m *Model) schemaHelpText() string {
+	doc := m.schemaDocAt(m.Path)
+	if doc == nil {
+		return ""
+	}
+	fields := doc
+	if _, isArr := m.Node.([]interface{}); isArr && doc.Items != nil {
+		fields = doc.Items
+	}
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(fields.Properties)) {
+		p := fields.Properties[name]
+		if desc := strings.Join(strings.Fields(p.Description), " "); desc != "" {
+			lines = append(lines, "- **"+name+"**: "+desc)
+		}
+	}
+	desc := strings.Join(strings.Fields(doc.Description), " ")
+	if desc == "" && len(lines) == 0 {
+		return ""
+	}
+	heading := "# Schema"
+	if doc.Title != "" {
+		heading += ": " + doc.Title
+	}
+	out := []string{heading}
+	if desc != "" {
+		out = append(out, desc)
+	}
+	out = append(out, lines...)
+	return strings.Join(out, "\n")
+}
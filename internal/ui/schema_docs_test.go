@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchemaDocs() *SchemaDoc {
+	return &SchemaDoc{
+		Title:       "Services",
+		Description: "Deployed services",
+		Items: &SchemaDoc{Properties: map[string]*SchemaDoc{
+			"name":   {Description: "Service name"},
+			"status": {Description: "Rollout   state,\nupdated hourly"},
+		}},
+	}
+}
+
+func TestSchemaDocChild(t *testing.T) {
+	doc := &SchemaDoc{Properties: map[string]*SchemaDoc{
+		"labels": {Additional: &SchemaDoc{Description: "A label value"}},
+		"hosts":  {Items: &SchemaDoc{Description: "A host"}},
+	}}
+	assert.Equal(t, "A label value", doc.Child("labels").Child("team").Description)
+	assert.Equal(t, "A host", doc.Child(`"hosts"`).Child("2").Description)
+	assert.Nil(t, doc.Child("hosts").Child("name"))
+	assert.Nil(t, doc.Child("missing"))
+	assert.Nil(t, (*SchemaDoc)(nil).Child("x"))
+}
+
+func TestSchemaDescriptionInStatusBar(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.SchemaDocs = testSchemaDocs()
+
+	m.Update(tea.KeyPressMsg{Code: 'l', Text: "l"})
+	require.Equal(t, "_[0]", m.Path)
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	assert.Equal(t, "Rollout state, updated hourly", m.schemaDescriptionForSelectedRow())
+	state := panelLayoutStateFromModel(m, PanelLayoutModelOptions{})
+	assert.Equal(t, "Rollout state, updated hourly", state.InfoMessage)
+
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	assert.Empty(t, m.schemaDescriptionForSelectedRow(), "tier is not documented")
+}
+
+func TestSchemaDescriptionWithColumnStats(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.SchemaDocs = testSchemaDocs()
+	m.ColumnarView = true
+	m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	require.True(t, m.HeaderFocused)
+	assert.Equal(t, "name: string · 3 distinct · 0 null · min alpha · max gamma · Service name", m.columnStatsStatus())
+}
+
+func TestSchemaHelpText(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	assert.Empty(t, m.schemaHelpText())
+
+	m.SchemaDocs = testSchemaDocs()
+	assert.Equal(t, "# Schema: Services\nDeployed services\n- **name**: Service name\n- **status**: Rollout state, updated hourly", m.schemaHelpText())
+
+	m.HelpVisible = true
+	m.HelpPopupText = ""
+	state := panelLayoutStateFromModel(m, PanelLayoutModelOptions{HelpText: "# Navigation"})
+	assert.Equal(t, m.schemaHelpText()+"\n\n# Navigation", state.HelpText)
+
+	m.HelpPopupText = "# Keys"
+	state = panelLayoutStateFromModel(m, PanelLayoutModelOptions{HelpText: "# Navigation"})
+	assert.Equal(t, m.schemaHelpText()+"\n\n# Keys", state.HelpPopupText)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// SessionStats counts what happened in a TUI session, for the summary of
+// --session-summary: how long it ran, the expressions run, the deepest path
+// visited, and the slowest evaluation. They never leave the machine.
+type SessionStats struct {
+	Start        time.Time
+	Duration     time.Duration // Set when RunModel exits
+	Expressions  int           // Expressions entered in the expression bar
+	Failed       int           // Expressions that returned an error
+	Visits       int           // Paths the view moved to
+	DeepestPath  string        // Deepest path visited, as shown in the path input
+	DeepestDepth int
+	SlowestExpr  string
+	SlowestEval  time.Duration
+}
+
+// NewSessionStats returns stats of a session starting now.
+func NewSessionStats() *SessionStats {
+	return &SessionStats{Start: time.Now()}
+}
+
+// noteEval counts an expression entered in the expression bar, which took
+// took to evaluate.
+func (s *SessionStats) noteEval(expr string, took time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.Expressions++
+	if err != nil {
+		s.Failed++
+	}
+	if took > s.SlowestEval {
+		s.SlowestExpr, s.SlowestEval = expr, took
+	}
+}
+
+// noteVisit counts a move to path. Expression results have no depth.
+func (s *SessionStats) noteVisit(path string) {
+	if s == nil {
+		return
+	}
+	s.Visits++
+	if strings.Contains(path, "(") || IsExpression(path) {
+		return
+	}
+	keys := parsePathKeys(normalizePathForModel(path))
+	if len(keys) > 0 && keys[0] == "_" {
+		keys = keys[1:]
+	}
+	if len(keys) > s.DeepestDepth {
+		s.DeepestPath, s.DeepestDepth = formatPathForDisplay(path), len(keys)
+	}
+}
+
+// finish returns the stats of the session ending now.
+func (s *SessionStats) finish() SessionStats {
+	out := *s
+	out.Duration = time.Since(s.Start)
+	return out
+}
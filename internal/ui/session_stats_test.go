@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStats(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	stats := NewSessionStats()
+	m.Stats = stats
+
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyRight}, tea.KeyPressMsg{Code: tea.KeyRight})
+	assert.Equal(t, "_.a.x", stats.DeepestPath)
+	assert.Equal(t, 2, stats.DeepestDepth)
+
+	m = press(m, tea.KeyPressMsg{Code: ':', Text: ":"})
+	m.PathInput.SetValue(`_.b["y"]`)
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.PathInput.SetValue(`_.a.map(k, k + "!")`)
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.PathInput.SetValue("_.nope")
+	m = press(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Same(t, stats, m.Stats, "the stats carry over to the expression's model")
+
+	assert.Equal(t, 3, stats.Expressions)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, "_.a.x", stats.DeepestPath, "the first of the deepest paths")
+	assert.Equal(t, 2, stats.DeepestDepth)
+	assert.NotEmpty(t, stats.SlowestExpr)
+	assert.Positive(t, stats.Visits)
+
+	stats.Start = time.Now().Add(-time.Minute)
+	done := stats.finish()
+	assert.GreaterOrEqual(t, done.Duration, time.Minute)
+	require.Zero(t, stats.Duration, "finish returns a copy")
+}
+
+func TestSessionStatsNil(t *testing.T) {
+	var stats *SessionStats
+	assert.NotPanics(t, func() {
+		stats.noteEval("_", time.Millisecond, nil)
+		stats.noteVisit("a")
+	})
+}
@@ -14,6 +14,7 @@ type ModelSnapshotConfig struct {
 	HelpVisible bool
 	HideFooter  bool // Hide the footer bar (for non-interactive display)
 	StartKeys   []string
+	Replay      []KeyEvent // Recorded input replayed after StartKeys (--replay-keys)
 	InitialExpr string
 	Configure   func(*Model)
 	Root        interface{}
@@ -52,6 +53,7 @@ func renderModelLayoutSnapshot(node interface{}, cfg ModelSnapshotConfig) string
 	}
 	// Trigger custom view mode detection (list/detail) now that DisplaySchema may be set.
 	m.updateViewMode(node)
+	m.chunkRoot()
 	// Eager auto-decode: recursively decode all serialized scalars at load time
 	if m.AllowDecode && m.AutoDecode == "eager" {
 		m.Root = loader.RecursiveDecode(m.Root)
@@ -65,6 +67,7 @@ func renderModelLayoutSnapshot(node interface{}, cfg ModelSnapshotConfig) string
 	if containsF1(cfg.StartKeys) {
 		m.HelpVisible = true
 	}
+	ReplayKeyEvents(&m, cfg.Replay)
 	if strings.TrimSpace(cfg.HelpText) != "" {
 		// When an explicit help text is provided (e.g., CLI popup text), avoid rendering an additional popup.
 		m.HelpPopupText = ""
@@ -1,13 +1,26 @@
 package ui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 )
 
 // ApplyStartupKeys simulates startup keypresses (Vim-like tokens and literal text).
 // It mutates the provided model in place.
+//
+// Besides keys, a token may hold directives for scripted runs:
+//
+//	<sleep:200ms>       wait before the next key
+//	<type:_.items>      type the text as is, even if it holds < or >
+//	<expect:"3 rows">   check that the screen shows the text
+//
+// An argument may be double-quoted, with Go escapes, to hold >. A failed
+// expectation or an invalid directive is shown in the status bar and passed
+// to OnScriptError; the remaining keys still run.
 func ApplyStartupKeys(m *Model, keys []string) {
 	if len(keys) == 0 || m == nil {
 		return
@@ -20,15 +33,7 @@ func ApplyStartupKeys(m *Model, keys []string) {
 		}
 		// Leading backslash forces literal text (e.g., "\\<f12>").
 		if strings.HasPrefix(token, `\`) {
-			token = strings.TrimPrefix(token, `\`)
-			for _, r := range token {
-				msg := tea.KeyPressMsg{Code: r, Text: string(r)}
-				if updated, _ := m.Update(msg); updated != nil {
-					if um, ok := updated.(*Model); ok {
-						*m = *um
-					}
-				}
-			}
+			m.typeStartupText(strings.TrimPrefix(token, `\`))
 			continue
 		}
 
@@ -36,30 +41,21 @@ func ApplyStartupKeys(m *Model, keys []string) {
 		// Split into vim-style tokens and literal text segments
 		segments := parseTokenSegments(token)
 		for _, segment := range segments {
-			if segment.isVimKey {
+			switch {
+			case segment.directive != "":
+				m.runStartupDirective(segment)
+			case segment.isVimKey:
 				// Process as vim-style key
 				if msgs, ok := keyMsgsFromToken(segment.text); ok {
 					for _, msg := range msgs {
 						if msg.Code == tea.KeyEscape {
 							sawEsc = true
 						}
-						if updated, _ := m.Update(msg); updated != nil {
-							if um, ok := updated.(*Model); ok {
-								*m = *um
-							}
-						}
-					}
-				}
-			} else {
-				// Process as literal text
-				for _, r := range segment.text {
-					msg := tea.KeyPressMsg{Code: r, Text: string(r)}
-					if updated, _ := m.Update(msg); updated != nil {
-						if um, ok := updated.(*Model); ok {
-							*m = *um
-						}
+						m.pressStartupKey(msg)
 					}
 				}
+			default:
+				m.typeStartupText(segment.text)
 			}
 		}
 	}
@@ -72,13 +68,88 @@ func ApplyStartupKeys(m *Model, keys []string) {
 	}
 }
 
+func (m *Model) pressStartupKey(msg tea.KeyPressMsg) {
+	if updated, _ := m.Update(msg); updated != nil {
+		if um, ok := updated.(*Model); ok {
+			*m = *um
+		}
+	}
+}
+
+// typeStartupText types text one character at a time.
+func (m *Model) typeStartupText(text string) {
+	for _, r := range text {
+		m.pressStartupKey(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+// runStartupDirective runs a <sleep:...>, <type:...>, or <expect:...>
+// directive of the startup keys.
+func (m *Model) runStartupDirective(segment tokenSegment) {
+	switch segment.directive {
+	case "sleep":
+		d, err := time.ParseDuration(segment.text)
+		if err != nil || d < 0 {
+			m.scriptError(fmt.Errorf("invalid <sleep:%s>: expected a duration such as 200ms", segment.text))
+			return
+		}
+		time.Sleep(d)
+	case "type":
+		m.typeStartupText(segment.text)
+	case "expect":
+		m.applyLayout(true)
+		if !strings.Contains(stripANSI(m.View().Content), segment.text) {
+			m.scriptError(fmt.Errorf("expected the screen to show %q", segment.text))
+		}
+	}
+}
+
+// scriptError reports a failure of the startup keys.
+func (m *Model) scriptError(err error) {
+	m.ErrMsg = err.Error()
+	m.StatusType = "error"
+	if m.OnScriptError != nil {
+		m.OnScriptError(err)
+	}
+}
+
+// startupDirectives are the <name:argument> directives of the startup keys.
+var startupDirectives = []string{"sleep", "type", "expect"}
+
 // tokenSegment represents a parsed segment of a token (either a vim-style key or literal text)
 type tokenSegment struct {
-	text     string
-	isVimKey bool
+	text      string
+	isVimKey  bool
+	directive string // "sleep", "type", or "expect", with its argument in text
 }
 
-// parseTokenSegments splits a token into segments of vim-style keys and literal text.
+// parseDirective parses a <name:argument> directive at the start of s and
+// returns it with the length it takes up.
+func parseDirective(s string) (tokenSegment, int, bool) {
+	for _, name := range startupDirectives {
+		prefix := "<" + name + ":"
+		if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+			continue
+		}
+		rest := s[len(prefix):]
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil || !strings.HasPrefix(rest[len(quoted):], ">") {
+				return tokenSegment{}, 0, false
+			}
+			arg, _ := strconv.Unquote(quoted)
+			return tokenSegment{text: arg, directive: name}, len(prefix) + len(quoted) + 1, true
+		}
+		end := strings.Index(rest, ">")
+		if end == -1 {
+			return tokenSegment{}, 0, false
+		}
+		return tokenSegment{text: rest[:end], directive: name}, len(prefix) + end + 1, true
+	}
+	return tokenSegment{}, 0, false
+}
+
+// parseTokenSegments splits a token into segments of vim-style keys, directives, and literal text.
 // Example: "<F1>rwo" -> [segment{text: "<F1>", isVimKey: true}, segment{text: "rwo", isVimKey: false}]
 func parseTokenSegments(token string) []tokenSegment {
 	var segments []tokenSegment
@@ -100,11 +171,17 @@ func parseTokenSegments(token string) []tokenSegment {
 			segments = append(segments, tokenSegment{text: remaining[:startIdx], isVimKey: false})
 		}
 
+		if segment, n, ok := parseDirective(remaining[startIdx:]); ok {
+			segments = append(segments, segment)
+			remaining = remaining[startIdx+n:]
+			continue
+		}
+
 		// Find the closing >
 		endIdx := strings.Index(remaining[startIdx:], ">")
 		if endIdx == -1 {
 			// No closing >, treat rest as literal text
-			segments = append(segments, tokenSegment{text: remaining, isVimKey: false})
+			segments = append(segments, tokenSegment{text: remaining[startIdx:], isVimKey: false})
 			break
 		}
 
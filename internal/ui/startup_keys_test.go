@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenSegments(t *testing.T) {
+	assert.Equal(t, []tokenSegment{
+		{text: "<F1>", isVimKey: true},
+		{text: "rwo"},
+	}, parseTokenSegments("<F1>rwo"))
+	assert.Equal(t, []tokenSegment{
+		{text: ":"},
+		{text: "_.items.filter(x, x.n > 2)", directive: "type"},
+		{text: "<Enter>", isVimKey: true},
+		{text: "200ms", directive: "sleep"},
+		{text: `3 "rows"`, directive: "expect"},
+	}, parseTokenSegments(`:<type:"_.items.filter(x, x.n > 2)"><Enter><sleep:200ms><expect:"3 \"rows\"">`))
+	assert.Equal(t, []tokenSegment{{text: "a"}, {text: "<b"}}, parseTokenSegments("a<b"), "an unclosed < is text")
+}
+
+func TestStartupKeyScript(t *testing.T) {
+	m := testRecentModel(KeyModeVim)
+	var failures []error
+	m.OnScriptError = func(err error) { failures = append(failures, err) }
+
+	start := time.Now()
+	ApplyStartupKeys(m, []string{`<type:j><sleep:20ms><expect:"_.b"><Right><expect:y>`})
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, "_.b", formatPathForDisplay(m.Path))
+	assert.Empty(t, failures)
+
+	ApplyStartupKeys(m, []string{`<expect:"nope"><sleep:soon><Left>`})
+	assert.Equal(t, []error{
+		errors.New(`expected the screen to show "nope"`),
+		errors.New("invalid <sleep:soon>: expected a duration such as 200ms"),
+	}, failures)
+	assert.Empty(t, m.Path, "the keys after a failure still run")
+
+	m.OnScriptError = nil
+	ApplyStartupKeys(m, []string{`<expect:"nope">`})
+	assert.Equal(t, `expected the screen to show "nope"`, m.ErrMsg)
+	assert.Equal(t, "error", m.StatusType)
+}
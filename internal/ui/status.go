@@ -8,32 +8,40 @@ import (
 	"charm.land/lipgloss/v2"
 
 	"github.com/oakwood-commons/kvx/internal/completion"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 // StatusModel represents the status bar component
 type StatusModel struct {
 	ErrMsg                string
+	ErrCaretCol           int    // Column under the input bar to mark with a caret for ErrMsg (0 = none)
 	StatusType            string // "error", "success", or ""
 	AdvancedSearchActive  bool
 	AdvancedSearchQuery   string
 	AdvancedSearchResults []SearchResult
 	FilterActive          bool
 	FilterBuffer          string
-	CursorIndex           int                     // Current cursor position (1-based)
-	TotalRows             int                     // Total number of rows
-	InputFocused          bool                    // Whether in expression mode
-	FilteredSuggestions   []string                // Available suggestions (legacy)
-	SelectedSuggestion    int                     // Currently selected suggestion index (legacy)
-	ShowSuggestions       bool                    // Whether suggestions are available (legacy)
-	Completions           []completion.Completion // New completion engine results
-	SelectedCompletion    int                     // Currently selected completion index
-	ShowCompletions       bool                    // Whether to show completions
-	HelpVisible           bool                    // Whether help overlay is visible
-	FunctionHelpText      string                  // Help text for the current function being typed or at cursor
-	SuggestionSummary     string                  // One-shot summary of functions after typing a trailing dot
-	ShowSuggestionSummary bool                    // Whether to render the trailing-dot summary in the status bar
-	InputValue            string                  // Current input value to check if it ends with "."
-	DecodeHint            string                  // Contextual hint shown when the selected value is decodable
+	PinnedFilter          string                    // Map filter query pinned across navigation
+	CursorIndex           int                       // Current cursor position (1-based)
+	TotalRows             int                       // Total number of rows
+	InputFocused          bool                      // Whether in expression mode
+	FilteredSuggestions   []string                  // Available suggestions (legacy)
+	SelectedSuggestion    int                       // Currently selected suggestion index (legacy)
+	ShowSuggestions       bool                      // Whether suggestions are available (legacy)
+	Completions           []completion.Completion   // New completion engine results
+	SelectedCompletion    int                       // Currently selected completion index
+	ShowCompletions       bool                      // Whether to show completions
+	HelpVisible           bool                      // Whether help overlay is visible
+	FunctionHelpText      string                    // Help text for the current function being typed or at cursor
+	Signature             *completion.SignatureHelp // Parameter hint for the function call under the cursor
+	SuggestionSummary     string                    // One-shot summary of functions after typing a trailing dot
+	ShowSuggestionSummary bool                      // Whether to render the trailing-dot summary in the status bar
+	InputValue            string                    // Current input value to check if it ends with "."
+	DecodeHint            string                    // Contextual hint shown when the selected value is decodable
+	SourceInfo            string                    // Source location and comment of the selected value (e.g. "data.yaml:142  # note")
+	PickChip              string                    // Rows marked in pick mode (e.g. "✓ 2 marked")
+	StreamChip            string                    // Records read by a stream still reading (e.g. "120 records loaded (streaming)")
+	ColumnStats           string                    // Stats of the focused column header (e.g. "status: string · 4 distinct · 1 null · min active · max pending")
 	NoColor               bool
 	Width                 int
 }
@@ -76,6 +84,9 @@ func (m StatusModel) View() string {
 	case m.ErrMsg != "":
 		statusStyle = statusStyle.Foreground(CurrentTheme().StatusError)
 		message = m.ErrMsg
+		if m.InputFocused && m.ErrCaretCol > 0 {
+			message = withErrorCaret(m.ErrMsg, m.ErrCaretCol, m.Width)
+		}
 	case m.HelpVisible:
 		// Show help text left-justified, no counter
 		statusStyle = statusStyle.Foreground(CurrentTheme().StatusColor)
@@ -98,9 +109,12 @@ func (m StatusModel) View() string {
 			message = fmt.Sprintf("Filter: '%s'", m.FilterBuffer)
 		}
 	case m.InputFocused:
-		// In expression mode - prioritize function help text, then suggestions, then default message
+		// In expression mode - prioritize the signature hint for an open call,
+		// then function help text, then suggestions, then default message
 		statusStyle = statusStyle.Foreground(CurrentTheme().StatusColor)
 		switch {
+		case m.Signature != nil:
+			message = formatStatusSignature(*m.Signature)
 		case m.FunctionHelpText != "":
 			// Show function help text (left-justified)
 			message = m.FunctionHelpText
@@ -143,6 +157,10 @@ func (m StatusModel) View() string {
 		default:
 			message = "Type . to see CEL suggestions"
 		}
+	case m.ColumnStats != "":
+		// The cursor is on a header, not a row
+		statusStyle = statusStyle.Foreground(CurrentTheme().StatusColor)
+		message = m.ColumnStats
 	default:
 		statusStyle = statusStyle.Foreground(CurrentTheme().StatusColor)
 		switch {
@@ -153,6 +171,18 @@ func (m StatusModel) View() string {
 		case m.DecodeHint != "":
 			message = m.DecodeHint
 		}
+		if m.SourceInfo != "" {
+			message = strings.TrimSpace(m.SourceInfo + "  " + message)
+		}
+		if chip := pinnedFilterChip(m.PinnedFilter); chip != "" {
+			message = strings.TrimSpace(chip + "  " + message)
+		}
+		if m.PickChip != "" {
+			message = strings.TrimSpace(m.PickChip + "  " + message)
+		}
+		if m.StreamChip != "" {
+			message = strings.TrimSpace(m.StreamChip + "  " + message)
+		}
 	}
 
 	// Pad the status bar to the window width (fallback to 92 if unknown)
@@ -162,12 +192,12 @@ func (m StatusModel) View() string {
 	}
 
 	// Only truncate non-function-help messages to preserve full examples in function help
-	if m.FunctionHelpText == "" && len(message) > target {
-		message = message[:target-3] + "..."
+	if (m.FunctionHelpText == "" || m.Signature != nil) && signatureWidth(message, m.NoColor) > target {
+		message = truncateSignatureAware(message, target, m.NoColor)
 	}
 
 	// Left-justify help text, function help, and expression mode suggestions; right-justify everything else
-	msgLen := len(message)
+	msgLen := textwidth.Width(message)
 	var padded string
 	if m.HelpVisible || m.FunctionHelpText != "" || m.InputFocused {
 		// Left-justify: pad on the right
@@ -186,9 +216,108 @@ func (m StatusModel) View() string {
 		}
 	}
 
+	if m.Signature != nil && strings.ContainsRune(message, sigParamStart) {
+		return m.renderSignatureLine(message, statusStyle, target) + "\n"
+	}
+
 	return statusStyle.Width(target).Render(padded) + "\n"
 }
 
+// withErrorCaret places a "^" at column col so it sits under the offending
+// character of the input bar directly above. The message follows the caret
+// when it fits, otherwise it precedes it; if neither fits the message is
+// returned unchanged.
+func withErrorCaret(msg string, col, width int) string {
+	if width <= 0 {
+		width = 92
+	}
+	msgWidth := lipgloss.Width(msg)
+	switch {
+	case col+2+msgWidth <= width:
+		return strings.Repeat(" ", col) + "^ " + msg
+	case msgWidth+1 <= col && col < width:
+		return msg + strings.Repeat(" ", col-msgWidth) + "^"
+	default:
+		return msg
+	}
+}
+
+// Sentinels marking the active parameter inside a signature hint. They are
+// replaced with styling (or plain markers when colors are disabled) at render time.
+const (
+	sigParamStart = '\uE000'
+	sigParamEnd   = '\uE001'
+)
+
+// formatStatusSignature renders a signature hint such as
+// "filter(x, condition) — Filter array elements" with the active parameter
+// wrapped in sentinels.
+func formatStatusSignature(sig completion.SignatureHelp) string {
+	hint := completion.FormatSignatureHelp(sig, func(p string) string {
+		return string(sigParamStart) + p + string(sigParamEnd)
+	})
+	if desc := strings.TrimSpace(sig.Function.Description); desc != "" {
+		hint += " — " + desc
+	}
+	return hint
+}
+
+// truncateSignatureAware shortens message to at most width display cells,
+// closing any signature sentinel that the cut would leave open. Widths are
+// measured as rendered, so the ‹› markers added without colors count too.
+func truncateSignatureAware(message string, width int, noColor bool) string {
+	if width <= 0 {
+		return ""
+	}
+	if signatureWidth(message, noColor) <= width {
+		return message
+	}
+	tail := "..."
+	if width <= len(tail) {
+		tail = ""
+	}
+	for w := width - len(tail); w > 0; w-- {
+		cut := textwidth.Truncate(message, w, "")
+		if strings.ContainsRune(cut, sigParamStart) && !strings.ContainsRune(cut, sigParamEnd) {
+			cut += string(sigParamEnd)
+		}
+		if signatureWidth(cut+tail, noColor) <= width {
+			return cut + tail
+		}
+	}
+	return ""
+}
+
+// signatureWidth returns the display width of message once its signature
+// sentinels are rendered: invisible with colors, ‹› without.
+func signatureWidth(message string, noColor bool) int {
+	n := 0
+	if noColor {
+		n = strings.Count(message, string(sigParamStart)) + strings.Count(message, string(sigParamEnd))
+	}
+	plain := strings.NewReplacer(string(sigParamStart), "", string(sigParamEnd), "").Replace(message)
+	return textwidth.Width(plain) + n
+}
+
+// renderSignatureLine styles a status line containing a signature hint,
+// emphasizing the active parameter and padding to width.
+func (m StatusModel) renderSignatureLine(message string, base lipgloss.Style, width int) string {
+	before, rest, _ := strings.Cut(message, string(sigParamStart))
+	active, after, _ := strings.Cut(rest, string(sigParamEnd))
+	if m.NoColor {
+		// Without colors, mark the active parameter with guillemets.
+		active = "‹" + active + "›"
+	}
+	if pad := width - lipgloss.Width(before+active+after); pad > 0 {
+		after += strings.Repeat(" ", pad)
+	}
+	if m.NoColor {
+		return base.Render(before + active + after)
+	}
+	activeStyle := base.Foreground(CurrentTheme().HelpKey).Bold(true).Underline(true)
+	return base.Render(before) + activeStyle.Render(active) + base.Render(after)
+}
+
 // SetWidth sets the width of the status bar
 func (m *StatusModel) SetWidth(width int) {
 	m.Width = width
@@ -215,16 +344,29 @@ func (m StatusModel) RenderCompletions(maxLines int) string {
 			prefix = "❯ "
 		}
 
+		// Highlight selected item
+		lineStyle := lipgloss.NewStyle()
+		if i == m.SelectedCompletion {
+			lineStyle = lineStyle.Foreground(th.StatusSuccess).Bold(true)
+		}
+
 		// Format: name - detail
-		line := fmt.Sprintf("%s%s", prefix, c.Display)
+		line := prefix + c.Display
 		if c.Detail != "" {
 			line += " - " + c.Detail
 		}
 
-		// Highlight selected item
-		if !m.NoColor && i == m.SelectedCompletion {
-			style := lipgloss.NewStyle().Foreground(th.StatusSuccess).Bold(true)
-			line = style.Render(line)
+		if !m.NoColor {
+			// Mark the characters matched by the partial token so fuzzy
+			// and substring hits are easy to spot.
+			matchStyle := lineStyle.Foreground(th.HelpKey).Bold(true).Underline(true)
+			offsets := make([]int, len(c.Matches))
+			for j, pos := range c.Matches {
+				offsets[j] = pos + len([]rune(prefix))
+			}
+			line = completion.HighlightMatches(line, offsets,
+				func(s string) string { return matchStyle.Render(s) },
+				func(s string) string { return lineStyle.Render(s) })
 		}
 
 		lines = append(lines, line)
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// StatusController drives the status screen from other goroutines: an
+// embedding app updates the wait message, log lines, data fields, and
+// progress as its operation runs, then finishes it, instead of computing
+// the screen up front. Its methods are safe for concurrent use; updates
+// made before the screen starts show once it does.
+type StatusController struct {
+	mu      sync.Mutex
+	state   statusControlState
+	changed chan struct{} // Wakes the status view; holds at most one pending signal
+}
+
+// statusControlState is the state a StatusController has accumulated.
+type statusControlState struct {
+	Message     string         // Replaces the schema's WaitMessage when set
+	Lines       []string       // Lines added below the messages
+	Fields      map[string]any // Data fields set over the loaded data
+	Progress    float64        // Fraction done, from 0 to 1
+	HasProgress bool           // Whether a progress bar is shown
+	Result      *StatusResult  // Outcome, once finished
+}
+
+// statusChangedMsg is sent when a StatusController has been updated.
+type statusChangedMsg struct{}
+
+// NewStatusController returns a controller for the status screen, to pass
+// in Config.StatusController.
+func NewStatusController() *StatusController {
+	return &StatusController{changed: make(chan struct{}, 1)}
+}
+
+// SetMessage replaces the message shown next to the spinner.
+func (c *StatusController) SetMessage(msg string) {
+	c.update(func(s *statusControlState) { s.Message = msg })
+}
+
+// AddLine adds a line below the status messages, such as a step done.
+func (c *StatusController) AddLine(line string) {
+	c.update(func(s *statusControlState) { s.Lines = append(s.Lines, line) })
+}
+
+// SetFields sets data fields, updating the title, messages, display fields,
+// and action values that read them.
+func (c *StatusController) SetFields(fields map[string]any) {
+	c.update(func(s *statusControlState) {
+		if s.Fields == nil {
+			s.Fields = make(map[string]any, len(fields))
+		}
+		maps.Copy(s.Fields, fields)
+	})
+}
+
+// SetProgress shows a progress bar filled to fraction, clamped to 0..1.
+// A negative fraction hides the bar.
+func (c *StatusController) SetProgress(fraction float64) {
+	c.update(func(s *statusControlState) {
+		s.HasProgress = fraction >= 0
+		s.Progress = min(max(fraction, 0), 1)
+	})
+}
+
+// Finish ends the operation with result, as a send on Config.Done would.
+// Calls after the first are ignored.
+func (c *StatusController) Finish(result StatusResult) {
+	c.update(func(s *statusControlState) {
+		if s.Result == nil {
+			s.Result = &result
+		}
+	})
+}
+
+func (c *StatusController) update(fn func(*statusControlState)) {
+	c.mu.Lock()
+	fn(&c.state)
+	c.mu.Unlock()
+	select {
+	case c.changed <- struct{}{}:
+	default: // A signal is already pending; the view reads the latest state
+	}
+}
+
+// snapshot returns a copy of the controller's state.
+func (c *StatusController) snapshot() statusControlState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.state
+	s.Lines = slices.Clone(s.Lines)
+	s.Fields = maps.Clone(s.Fields)
+	return s
+}
+
+// waitForStatusChange returns a tea.Cmd that blocks until c is updated.
+func waitForStatusChange(c *StatusController) tea.Cmd {
+	return func() tea.Msg {
+		<-c.changed
+		return statusChangedMsg{}
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusController_UpdatesView(t *testing.T) {
+	data := testStatusData()
+	ctrl := NewStatusController()
+	ctrl.SetMessage("Polling for the token...")
+	ctrl.AddLine("Opened the browser")
+	ctrl.SetFields(map[string]any{"code": "NEWCODE"})
+	ctrl.SetProgress(0.5)
+
+	sv := buildStatusViewModel(data, testStatusSchema(), KeyModeVim, true, nil, 60, 24)
+	require.NotNil(t, sv)
+	sv.attachController(ctrl)
+	assert.True(t, sv.HasDone)
+	assert.Equal(t, "EH5HFPGJJ", data["code"], "the loaded data is not modified")
+
+	view := stripANSI(sv.View())
+	assert.Contains(t, view, "Polling for the token...")
+	assert.NotContains(t, view, "Waiting for authentication...")
+	assert.Contains(t, view, "Opened the browser")
+	assert.Contains(t, view, "Code: NEWCODE")
+	assert.Contains(t, view, "████████████████████░░░░░░░░░░░░░░░░░░░░  50%")
+
+	ctrl.SetProgress(-1)
+	ctrl.AddLine("Token received")
+	_, cmd := sv.Update(statusChangedMsg{})
+	assert.NotNil(t, cmd, "waits for the next change")
+	view = stripANSI(sv.View())
+	assert.Contains(t, view, "Token received")
+	assert.NotContains(t, view, "50%")
+}
+
+func TestStatusController_Finish(t *testing.T) {
+	ctrl := NewStatusController()
+	sv := buildStatusViewModel(testStatusData(), testStatusSchema(), KeyModeVim, true, nil, 80, 24)
+	sv.attachController(ctrl)
+
+	ctrl.Finish(StatusResult{Err: errors.New("denied")})
+	ctrl.Finish(StatusResult{Message: "ignored"})
+	sv.Update(statusChangedMsg{})
+	assert.Equal(t, statusPhaseError, sv.Phase)
+	assert.Equal(t, "denied", sv.ResultMsg)
+	assert.Contains(t, sv.View(), "✗ denied")
+}
+
+func TestStatusController_ConcurrentUpdates(t *testing.T) {
+	ctrl := NewStatusController()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			ctrl.AddLine("line")
+			ctrl.SetFields(map[string]any{"n": i})
+			ctrl.SetProgress(float64(i) / 10)
+		})
+	}
+	wg.Wait()
+	state := ctrl.snapshot()
+	assert.Len(t, state.Lines, 10)
+	assert.Contains(t, state.Fields, "n")
+
+	// Bursts of updates leave one pending signal.
+	assert.Len(t, ctrl.changed, 1)
+}
+
+func TestUpdateViewMode_StatusController(t *testing.T) {
+	ctrl := NewStatusController()
+	ctrl.AddLine("Started")
+	m := &Model{DisplaySchema: testStatusSchema(), StatusController: ctrl, WinWidth: 80, WinHeight: 24}
+	m.updateViewMode(testStatusData())
+	require.NotNil(t, m.StatusViewState)
+	assert.Same(t, ctrl, m.StatusViewState.Controller)
+	assert.Equal(t, []string{"Started"}, m.StatusViewState.control.Lines)
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// statusCountdownMsg is sent every second while a status screen counts
+// down to its deadline.
+type statusCountdownMsg struct{}
+
+// countdownTick returns a tea.Cmd that sends statusCountdownMsg after a second.
+func countdownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return statusCountdownMsg{} })
+}
+
+// parseDeadline returns the deadline a DeadlineField value names: a time,
+// an RFC 3339 string, or a number of seconds after now.
+func parseDeadline(v any, now time.Time) (time.Time, bool) {
+	var secs float64
+	switch v := v.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		secs = n
+	case float64:
+		secs = v
+	case int:
+		secs = float64(v)
+	case int64:
+		secs = float64(v)
+	default:
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(secs * float64(time.Second))), true
+}
+
+// updateDeadline resolves the deadline from the data when the value of the
+// DeadlineField has changed, so seconds count from when they were set.
+func (sv *StatusViewModel) updateDeadline() {
+	if sv.Config.DeadlineField == "" {
+		return
+	}
+	data, _ := sv.Data.(map[string]any)
+	v := data[sv.Config.DeadlineField]
+	if sv.deadlineSet && reflect.DeepEqual(v, sv.deadlineValue) {
+		return
+	}
+	sv.deadlineValue = v
+	sv.deadlineSet = true
+	sv.Deadline, _ = parseDeadline(v, sv.now())
+}
+
+// expired reports whether the deadline has passed.
+func (sv *StatusViewModel) expired() bool {
+	return !sv.Deadline.IsZero() && !sv.now().Before(sv.Deadline)
+}
+
+// countdownText returns the countdown line, such as "Expires in 4:32", or
+// the expired message once the deadline has passed.
+func (sv *StatusViewModel) countdownText() string {
+	if sv.expired() {
+		return sv.expiredMessage()
+	}
+	label := sv.Config.DeadlineLabel
+	if label == "" {
+		label = "Expires in"
+	}
+	return label + " " + formatCountdown(sv.Deadline.Sub(sv.now()))
+}
+
+func (sv *StatusViewModel) expiredMessage() string {
+	if sv.Config.ExpiredMessage != "" {
+		return sv.Config.ExpiredMessage
+	}
+	return "Expired"
+}
+
+// handleCountdown ends the wait when the deadline has passed and the schema
+// asks to fail on expiry, and otherwise ticks again.
+func (sv *StatusViewModel) handleCountdown() (CustomView, tea.Cmd) {
+	if sv.Phase != statusPhaseWaiting {
+		return sv, nil
+	}
+	if sv.Config.FailOnExpiry && sv.expired() {
+		return sv.Update(statusDoneMsg{Err: errors.New(sv.expiredMessage())})
+	}
+	return sv, countdownTick()
+}
+
+// formatCountdown formats a remaining duration as m:ss, or h:mm:ss from an
+// hour, rounding partial seconds up.
+func formatCountdown(d time.Duration) string {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
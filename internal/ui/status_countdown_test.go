@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countdownStatusView(t *testing.T, deadline any, clock *time.Time) *StatusViewModel {
+	t.Helper()
+	data := testStatusData()
+	data["expires_in"] = deadline
+	schema := testStatusSchema()
+	schema.Status.DeadlineField = "expires_in"
+	sv := buildStatusViewModel(data, schema, KeyModeVim, true, make(chan StatusResult), 80, 24)
+	require.NotNil(t, sv)
+	sv.now = func() time.Time { return *clock }
+	sv.deadlineSet = false
+	sv.updateDeadline()
+	return sv
+}
+
+func TestParseDeadline(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tc := range []struct {
+		value any
+		want  time.Time
+		ok    bool
+	}{
+		{float64(90), now.Add(90 * time.Second), true},
+		{int64(5), now.Add(5 * time.Second), true},
+		{"30", now.Add(30 * time.Second), true},
+		{"2026-01-02T03:10:00Z", time.Date(2026, 1, 2, 3, 10, 0, 0, time.UTC), true},
+		{now, now, true},
+		{"soon", time.Time{}, false},
+		{nil, time.Time{}, false},
+	} {
+		got, ok := parseDeadline(tc.value, now)
+		assert.Equal(t, tc.ok, ok, "%v", tc.value)
+		assert.True(t, tc.want.Equal(got), "%v: got %v", tc.value, got)
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "4:32", formatCountdown(4*time.Minute+32*time.Second))
+	assert.Equal(t, "0:01", formatCountdown(200*time.Millisecond))
+	assert.Equal(t, "1:02:03", formatCountdown(time.Hour+2*time.Minute+3*time.Second))
+}
+
+func TestStatusCountdown(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sv := countdownStatusView(t, float64(272), &clock)
+	assert.Contains(t, stripANSI(sv.View()), "Expires in 4:32")
+
+	clock = clock.Add(272 * time.Second)
+	_, cmd := sv.Update(statusCountdownMsg{})
+	assert.NotNil(t, cmd, "keeps ticking when not failing on expiry")
+	assert.Equal(t, statusPhaseWaiting, sv.Phase)
+	assert.Contains(t, stripANSI(sv.View()), "✗ Expired")
+	assert.NotContains(t, stripANSI(sv.View()), "Expires in")
+}
+
+func TestStatusCountdown_FailOnExpiry(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sv := countdownStatusView(t, "2026-01-02T03:05:00Z", &clock)
+	sv.Config.FailOnExpiry = true
+	sv.Config.ExpiredMessage = "The code has expired"
+	sv.Update(statusCountdownMsg{})
+	assert.Equal(t, statusPhaseWaiting, sv.Phase)
+
+	clock = clock.Add(time.Minute)
+	sv.Update(statusCountdownMsg{})
+	assert.Equal(t, statusPhaseError, sv.Phase)
+	assert.Equal(t, "The code has expired", sv.ResultMsg)
+}
+
+func TestStatusCountdown_ControllerRenewsDeadline(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sv := countdownStatusView(t, float64(10), &clock)
+	ctrl := NewStatusController()
+	sv.attachController(ctrl)
+
+	clock = clock.Add(5 * time.Second)
+	ctrl.SetFields(map[string]any{"code": "NEWCODE"})
+	sv.Update(statusChangedMsg{})
+	assert.Contains(t, stripANSI(sv.View()), "Expires in 0:05", "an unchanged deadline keeps counting")
+
+	ctrl.SetFields(map[string]any{"expires_in": float64(600)})
+	sv.Update(statusChangedMsg{})
+	assert.Contains(t, stripANSI(sv.View()), "Expires in 10:00")
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"slices"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/oakwood-commons/kvx/internal/qr"
+)
+
+// qrQuietZone is the light margin around status screen QR codes, in
+// modules. The standard asks for four; phones read two fine.
+const qrQuietZone = 2
+
+// qrSection returns the status screen lines showing the QRField value as a
+// QR code within room lines, followed by a blank line. Falls back to the
+// value as text with NoColor or when the code does not fit, or to nothing
+// when a display field already shows the value.
+func (sv *StatusViewModel) qrSection(room int) []string {
+	val := sv.getFieldValue(sv.Config.QRField)
+	if val == "" {
+		return nil
+	}
+	if !sv.NoColor {
+		if code, err := qr.Encode(val); err == nil {
+			lines := code.HalfBlocks(qrQuietZone)
+			width := code.Size + 2*qrQuietZone
+			if (sv.Width <= 0 || width+2 <= sv.Width) && len(lines)+1 <= room {
+				style := lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("#ffffff"))
+				out := make([]string, 0, len(lines)+1)
+				for _, line := range lines {
+					out = append(out, "  "+style.Render(line))
+				}
+				return append(out, "")
+			}
+		}
+	}
+	if slices.ContainsFunc(sv.Config.DisplayFields, func(df StatusFieldDisplay) bool {
+		return df.Field == sv.Config.QRField
+	}) {
+		return nil
+	}
+	display := val
+	if isURL(val) {
+		display = ansi.SetHyperlink(val) + val + ansi.ResetHyperlink()
+	}
+	return []string{"  " + display, ""}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusQRSection(t *testing.T) {
+	schema := testStatusSchema()
+	schema.Status.QRField = "url"
+	sv := buildStatusViewModel(testStatusData(), schema, KeyModeVim, false, nil, 80, 40)
+	require.NotNil(t, sv)
+
+	// "https://microsoft.com/devicelogin" is a version 3 code: 29 modules
+	// and a quiet zone of 2 on each side, two rows per line.
+	lines := sv.qrSection(40)
+	require.Len(t, lines, 18)
+	assert.Contains(t, stripANSI(lines[1]), "█▀▀▀▀▀█")
+	assert.Empty(t, lines[17])
+	assert.Contains(t, stripANSI(sv.View()), "█▀▀▀▀▀█")
+
+	// Too few lines or columns: the URL is already a display field.
+	assert.Nil(t, sv.qrSection(10))
+	sv.Width = 30
+	assert.Nil(t, sv.qrSection(40))
+
+	// With NoColor the value is shown as text unless a display field has it.
+	sv.Width = 80
+	sv.NoColor = true
+	schema.Status.DisplayFields = schema.Status.DisplayFields[1:]
+	lines = sv.qrSection(40)
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "https://microsoft.com/devicelogin")
+	assert.False(t, strings.Contains(sv.View(), "█▀▀▀▀▀█"))
+}
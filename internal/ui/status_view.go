@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +30,7 @@ type StatusViewModel struct {
 	Data    any // The loaded data node (map[string]any or similar)
 	KeyMode KeyMode
 	NoColor bool
+	Kiosk   bool // Actions are refused (see Model.SetKiosk)
 
 	// Async completion
 	DoneChan  <-chan StatusResult // From Config.Done (programmatic)
@@ -33,8 +38,19 @@ type StatusViewModel struct {
 	Phase     statusPhase
 	ResultMsg string // Message from StatusResult or timeout
 
+	// Programmatic updates
+	Controller *StatusController // From Config.StatusController
+	control    statusControlState
+
+	// Countdown to the schema's DeadlineField
+	Deadline      time.Time
+	deadlineValue any  // DeadlineField value Deadline was resolved from
+	deadlineSet   bool // Whether deadlineValue has been read
+	now           func() time.Time
+
 	// Spinner
 	Spinner spinner.Model
+	Static  bool // The spinner stands still (deterministic rendering)
 
 	// Action flash messages
 	FlashMsg   string
@@ -78,7 +94,7 @@ func buildStatusViewModel(data any, schema *DisplaySchema, keyMode KeyMode, noCo
 		}
 	}
 
-	return &StatusViewModel{
+	sv := &StatusViewModel{
 		Config:   schema.Status,
 		Data:     data,
 		KeyMode:  keyMode,
@@ -89,17 +105,29 @@ func buildStatusViewModel(data any, schema *DisplaySchema, keyMode KeyMode, noCo
 		Spinner:  s,
 		Width:    width,
 		Height:   height,
+		now:      time.Now,
 	}
+	sv.updateDeadline()
+	return sv
 }
 
 // Init returns the initial commands for the status view (spinner tick + completion source).
 func (sv *StatusViewModel) Init() tea.Cmd {
-	cmds := []tea.Cmd{sv.Spinner.Tick}
+	var cmds []tea.Cmd
+	if !sv.Static {
+		cmds = append(cmds, sv.Spinner.Tick)
+	}
 
+	if sv.Controller != nil {
+		cmds = append(cmds, waitForStatusChange(sv.Controller))
+	}
+	if sv.Config.DeadlineField != "" {
+		cmds = append(cmds, countdownTick())
+	}
 	if sv.DoneChan != nil {
 		// Listen on the programmatic Done channel
 		cmds = append(cmds, waitForDone(sv.DoneChan))
-	} else if sv.Config.Timeout != "" {
+	} else if sv.Controller == nil && sv.Config.Timeout != "" {
 		// Start the schema-defined timeout
 		if d, err := time.ParseDuration(sv.Config.Timeout); err == nil {
 			cmds = append(cmds, startTimeout(d))
@@ -137,6 +165,21 @@ func (sv *StatusViewModel) Update(msg tea.Msg) (CustomView, tea.Cmd) {
 		}
 		return sv, sv.doneDelayCmd()
 
+	case statusChangedMsg:
+		if sv.Controller == nil {
+			return sv, nil
+		}
+		if result := sv.applyControl(); result != nil {
+			if sv.Phase != statusPhaseWaiting {
+				return sv, nil
+			}
+			return sv.Update(statusDoneMsg(*result))
+		}
+		return sv, waitForStatusChange(sv.Controller)
+
+	case statusCountdownMsg:
+		return sv.handleCountdown()
+
 	case statusTimeoutMsg:
 		sv.Phase = statusPhaseSuccess
 		if sv.Config.SuccessMessage != "" {
@@ -202,10 +245,12 @@ func (sv *StatusViewModel) executeAction(action StatusActionConfig) (*StatusView
 	}
 
 	var err error
-	switch action.Type {
-	case "copy-value":
+	switch {
+	case sv.Kiosk:
+		err = errors.New("not available in kiosk mode")
+	case action.Type == "copy-value":
 		err = CopyToClipboard(fieldValue)
-	case "open-url":
+	case action.Type == "open-url":
 		err = OpenURL(fieldValue)
 	}
 
@@ -235,8 +280,8 @@ func (sv *StatusViewModel) View() string {
 	// Title is rendered in the panel border (set by panelLayoutStateFromModel),
 	// so we skip it here to avoid duplication.
 
-	// Messages
-	messages := sv.getMessages()
+	// Messages, then the lines added by the controller
+	messages := append(sv.getMessages(), sv.control.Lines...)
 	for _, msg := range messages {
 		msgStyle := lipgloss.NewStyle()
 		if !sv.NoColor && th.StatusColor != nil {
@@ -267,17 +312,39 @@ func (sv *StatusViewModel) View() string {
 	if len(sv.Config.DisplayFields) > 0 {
 		sections = append(sections, "")
 	}
+	qrAt := len(sections)
 
 	// Phase-specific content
 	switch sv.Phase {
 	case statusPhaseWaiting:
-		if sv.HasDone && sv.Config.WaitMessage != "" {
+		waitMessage := sv.Config.WaitMessage
+		if sv.control.Message != "" {
+			waitMessage = sv.control.Message
+		}
+		waitStyle := lipgloss.NewStyle()
+		if !sv.NoColor && th.StatusColor != nil {
+			waitStyle = waitStyle.Foreground(th.StatusColor)
+		}
+		if sv.HasDone && waitMessage != "" {
 			spinnerView := sv.Spinner.View()
-			waitStyle := lipgloss.NewStyle()
-			if !sv.NoColor && th.StatusColor != nil {
-				waitStyle = waitStyle.Foreground(th.StatusColor)
+			sections = append(sections, "  "+spinnerView+" "+waitStyle.Render(waitMessage))
+			sections = append(sections, "")
+		}
+		if sv.control.HasProgress {
+			sections = append(sections, "  "+waitStyle.Render(renderProgressBar(sv.control.Progress, sv.Width-4)))
+			sections = append(sections, "")
+		}
+		if !sv.Deadline.IsZero() {
+			countdownStyle := waitStyle
+			countdown := sv.countdownText()
+			if sv.expired() {
+				countdownStyle = lipgloss.NewStyle().Bold(true)
+				if !sv.NoColor && th.StatusError != nil {
+					countdownStyle = countdownStyle.Foreground(th.StatusError)
+				}
+				countdown = "✗ " + countdown
 			}
-			sections = append(sections, "  "+spinnerView+" "+waitStyle.Render(sv.Config.WaitMessage))
+			sections = append(sections, "  "+countdownStyle.Render(countdown))
 			sections = append(sections, "")
 		}
 	case statusPhaseSuccess:
@@ -304,6 +371,14 @@ func (sv *StatusViewModel) View() string {
 		}
 	}
 
+	if sv.Config.QRField != "" {
+		room := math.MaxInt
+		if sv.Height > 0 {
+			room = sv.Height - len(sections)
+		}
+		sections = slices.Insert(sections, qrAt, sv.qrSection(room)...)
+	}
+
 	content := strings.Join(sections, "\n")
 
 	return content
@@ -347,6 +422,24 @@ func (sv *StatusViewModel) getTitleText() string {
 	return sv.getFieldValue(sv.Config.TitleField)
 }
 
+// notifyCmd returns a tea.Cmd showing the result as a desktop notification,
+// titled with the status title or else appName. Failures are ignored: the
+// notification is a courtesy, and the result stays on screen.
+func (sv *StatusViewModel) notifyCmd(appName string) tea.Cmd {
+	title := sv.getTitleText()
+	if title == "" {
+		title = appName
+	}
+	body := "✓ " + sv.ResultMsg
+	if sv.Phase == statusPhaseError {
+		body = "✗ " + sv.ResultMsg
+	}
+	return func() tea.Msg {
+		_ = Notify(title, body)
+		return nil
+	}
+}
+
 // getMessages extracts messages from the data using the schema's MessageField.
 func (sv *StatusViewModel) getMessages() []string {
 	if sv.Config.MessageField == "" {
@@ -473,6 +566,41 @@ func (sv *StatusViewModel) doneDelayCmd() tea.Cmd {
 	}
 }
 
+// attachController makes the status view show the updates of c and finish
+// when c does.
+func (sv *StatusViewModel) attachController(c *StatusController) {
+	sv.Controller = c
+	sv.HasDone = true
+	if data, ok := sv.Data.(map[string]any); ok {
+		sv.Data = maps.Clone(data)
+	}
+	sv.applyControl()
+}
+
+// applyControl takes the latest state of the controller, setting its fields
+// over the data. Returns the result once the controller has finished.
+func (sv *StatusViewModel) applyControl() *StatusResult {
+	sv.control = sv.Controller.snapshot()
+	if len(sv.control.Fields) > 0 {
+		data, ok := sv.Data.(map[string]any)
+		if !ok {
+			data = make(map[string]any, len(sv.control.Fields))
+		}
+		maps.Copy(data, sv.control.Fields)
+		sv.Data = data
+		sv.updateDeadline()
+	}
+	return sv.control.Result
+}
+
+// renderProgressBar renders a bar filled to fraction followed by the
+// percentage, at most width cells wide.
+func renderProgressBar(fraction float64, width int) string {
+	barWidth := min(max(width-5, 10), 40)
+	filled := int(fraction * float64(barWidth))
+	return strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled) + fmt.Sprintf(" %3.0f%%", fraction*100)
+}
+
 // waitForDone returns a tea.Cmd that blocks on the done channel and sends statusDoneMsg.
 func waitForDone(ch <-chan StatusResult) tea.Cmd {
 	return func() tea.Msg {
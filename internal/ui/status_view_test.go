@@ -669,3 +669,41 @@ func TestStatusViewKey_RoutedThroughModelUpdate(t *testing.T) {
 	assert.Equal(t, "status", m2.ViewMode)
 	assert.NotNil(t, m2.StatusViewState)
 }
+
+// TestStatusNotify verifies that a finished status screen sends a desktop
+// notification only when enabled and the terminal is unfocused.
+func TestStatusNotify(t *testing.T) {
+	var got []string
+	orig := notifyFn
+	t.Cleanup(func() { notifyFn = orig })
+	notifyFn = func(title, body string) error {
+		got = append(got, title+": "+body)
+		return nil
+	}
+
+	finish := func(notify bool, focus ...tea.Msg) {
+		schema := testStatusSchema()
+		schema.Status.DoneBehavior = DoneBehaviorWaitForKey
+		m := InitialModel(testStatusData())
+		m.AppName = "kvx"
+		m.ViewMode = "status"
+		m.StatusViewState = buildStatusViewModel(testStatusData(), schema, KeyModeVim, true, nil, 80, 24)
+		m.Notify = notify
+		for _, msg := range focus {
+			m.Update(msg)
+		}
+		_, cmd := m.Update(statusDoneMsg{Message: "Signed in"})
+		if cmd != nil {
+			cmd()
+		}
+	}
+
+	finish(true)
+	finish(true, tea.BlurMsg{}, tea.FocusMsg{})
+	finish(false, tea.BlurMsg{})
+	assert.Empty(t, got, "focused, or notify off")
+
+	finish(true, tea.BlurMsg{})
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], ": ✓ Signed in")
+}
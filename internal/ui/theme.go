@@ -124,6 +124,8 @@ func SetTheme(t Theme) {
 		KeyColor:       t.KeyColor,
 		ValueColor:     t.ValueColor,
 		SeparatorColor: t.SeparatorColor,
+		OKColor:        t.StatusSuccess,
+		ErrorColor:     t.StatusError,
 	})
 }
 
@@ -278,6 +280,9 @@ type CLIConfig struct {
 	HelpHeaderTemplate string `yaml:"help_header_template,omitempty" yamlcomment:"Template for CLI --help header (supports Go templates)"`
 	HelpDescription    string `yaml:"help_description,omitempty" yamlcomment:"Description paragraph for CLI --help (supports Go templates)"`
 	HelpUsage          string `yaml:"help_usage,omitempty" yamlcomment:"Usage instructions for CLI --help (supports Go templates)"`
+	// Aliases maps a name to the arguments `kvx @name` expands to, e.g.
+	// pods: "-e '_.items.map(i, i.metadata.name)' -o table".
+	Aliases map[string]string `yaml:"aliases,omitempty" yamlcomment:"Argument shortcuts run as 'kvx @name [args...]'"`
 }
 
 // HelpMenuConfig holds the dynamically generated help menu text.
@@ -304,18 +309,34 @@ type FeaturesConfig struct {
 	AllowSuggestions  *bool   `yaml:"allow_suggestions,omitempty" yamlcomment:"Show CEL/intellisense suggestions"`
 	AllowIntellisense *bool   `yaml:"allow_intellisense,omitempty" yamlcomment:"Show CEL/intellisense dropdown hints"`
 	KeyMode           *string `yaml:"key_mode,omitempty" yamlcomment:"Keybinding mode: vim (default), emacs, or function"`
+	Hyperlinks        *bool   `yaml:"hyperlinks,omitempty" yamlcomment:"Render URL values as clickable terminal hyperlinks (OSC 8)"`
+	Notify            *bool   `yaml:"notify,omitempty" yamlcomment:"Desktop notification when a status screen finishes while the terminal is unfocused"`
+	Bell              *string `yaml:"bell,omitempty" yamlcomment:"Ring the terminal bell on: never (default), error, or always (also on success and search completion)"`
+	VisualBell        *bool   `yaml:"visual_bell,omitempty" yamlcomment:"Flash the screen instead of sounding the bell"`
 }
 
 // DisplayConfig holds display and layout settings.
 type DisplayConfig struct {
 	KeyColWidth   *int    `yaml:"key_col_width,omitempty" yamlcomment:"Width of the KEY column (default: 30)"`
 	ValueColWidth *int    `yaml:"value_col_width,omitempty" yamlcomment:"Width of the VALUE column (default: auto)"`
-	Sort          *string `yaml:"sort,omitempty" yamlcomment:"Sort order for map keys: none|ascending|descending"`
+	Sort          *string `yaml:"sort,omitempty" yamlcomment:"Sort order for map keys: none|ascending|descending|natural|insertion|schema"`
 }
 
 // BehaviorConfig holds user behavior and interaction settings.
 type BehaviorConfig struct {
-	// Future fields will be added here
+	IdleTimeout *string `yaml:"idle_timeout,omitempty" yamlcomment:"Exit the interactive TUI after this long without input, e.g. 30m (empty or 0: never)"`
+	IdlePrint   *bool   `yaml:"idle_print,omitempty" yamlcomment:"Print the current expression result when exiting on the idle timeout"`
+}
+
+// LayoutConfig is a named layout, restored with --layout NAME and saved with
+// --save-layout NAME: the expression to open and how the view is arranged.
+type LayoutConfig struct {
+	Expression    string   `yaml:"expression,omitempty" yamlcomment:"CEL expression to open, as with -e"`
+	ViewMode      string   `yaml:"view_mode,omitempty" yamlcomment:"View mode: table or columns"`
+	ColumnOrder   []string `yaml:"column_order,omitempty" yamlcomment:"Column order of the columnar view"`
+	HiddenColumns []string `yaml:"hidden_columns,omitempty" yamlcomment:"Columns hidden in the columnar view"`
+	Sort          string   `yaml:"sort,omitempty" yamlcomment:"Sort order, as with --sort"`
+	KeyColWidth   *int     `yaml:"key_col_width,omitempty" yamlcomment:"Width of the key column"`
 }
 
 // PerformanceConfig holds performance and optimization settings.
@@ -339,6 +360,15 @@ type PerformanceConfig struct {
 	// Improves performance for large datasets.
 	// Default: true
 	VirtualScrolling *bool `yaml:"virtual_scrolling,omitempty" yamlcomment:"Enable virtual scrolling for large datasets"`
+
+	// ArrayChunkSize is how many items of a large array are shown at first,
+	// and how many more each press of L loads in the TUI. 0 shows arrays whole.
+	// Default: 500
+	ArrayChunkSize *int `yaml:"array_chunk_size,omitempty" yamlcomment:"Items of a large array shown at first and loaded per L (0 = whole arrays)"`
+
+	// ArrayChunkThreshold is the length above which arrays are shown in chunks.
+	// Default: 1000
+	ArrayChunkThreshold *int `yaml:"array_chunk_threshold,omitempty" yamlcomment:"Arrays longer than this are shown in chunks"`
 }
 
 // SearchConfig holds search and filtering settings.
@@ -370,11 +400,27 @@ type TableFormattingConfig struct {
 	// HiddenColumns specifies columns to omit from columnar tables.
 	HiddenColumns []string `yaml:"hidden_columns,omitempty" yamlcomment:"Columns to hide in columnar display"`
 
+	// Summary maps columns to an aggregate shown in a footer row under
+	// columnar tables: count, sum, avg, min, max, or a CEL expression.
+	Summary map[string]string `yaml:"summary,omitempty" yamlcomment:"Footer aggregates per column: count, sum, avg, min, max, or a CEL expression"`
+
+	// WidthPercentile sizes columns to this percentile of their value widths
+	// instead of the longest value, truncating outliers. 0 = longest value.
+	WidthPercentile *int `yaml:"width_percentile,omitempty" yamlcomment:"Size columns to this percentile of value widths, e.g. 90 (default: 0 = longest value)"`
+
 	// MaxValueLines caps how many lines a multi-line value renders in the
 	// key-value table view. 0 disables multi-line (escapes newlines).
 	// Negative means unlimited. Default: 10.
 	MaxValueLines *int `yaml:"max_value_lines,omitempty" yamlcomment:"Max lines for multi-line values (0=disable, -1=unlimited, default: 10)"`
 
+	// WrapValues wraps long values in KEY/VALUE tables onto continuation
+	// lines instead of truncating them. The interactive TUI toggles it
+	// with the wrap key.
+	WrapValues *bool `yaml:"wrap_values,omitempty" yamlcomment:"Wrap long values in KEY/VALUE tables instead of truncating (default: false)"`
+
+	// Ellipsis marks where a value was cut to fit its column, such as "…".
+	Ellipsis *string `yaml:"ellipsis,omitempty" yamlcomment:"Marker for values cut to fit a column (default: ...)"`
+
 	// SchemaFile is a path to a JSON Schema file used to derive column display hints.
 	SchemaFile *string `yaml:"schema_file,omitempty" yamlcomment:"JSON Schema file for column display hints"`
 
@@ -388,6 +434,7 @@ type YAMLFormattingConfig struct {
 	Indent                *int  `yaml:"indent,omitempty" yamlcomment:"Indentation size in spaces (default: 2)"`
 	LiteralBlockStrings   *bool `yaml:"literal_block_strings,omitempty" yamlcomment:"Render multiline strings with | literal blocks"`
 	ExpandEscapedNewlines *bool `yaml:"expand_escaped_newlines,omitempty" yamlcomment:"Convert literal \n sequences to real newlines in YAML output"`
+	Fidelity              *bool `yaml:"fidelity,omitempty" yamlcomment:"Keep comments, anchors, key order, and quoting of YAML input for the input or a plain path into it"`
 }
 
 // TreeFormattingConfig controls tree output formatting.
@@ -433,7 +480,8 @@ type MermaidFormattingConfig struct {
 
 // IntellisenseConfig holds intellisense/completion configuration.
 type IntellisenseConfig struct {
-	MaxSuggestions *int `yaml:"max_suggestions,omitempty" yamlcomment:"Maximum number of suggestions to show"`
+	MaxSuggestions *int    `yaml:"max_suggestions,omitempty" yamlcomment:"Maximum number of suggestions to show"`
+	MatchMode      *string `yaml:"match_mode,omitempty" yamlcomment:"Completion matching: fuzzy (prefix, substring, subsequence) or prefix"`
 }
 
 // FunctionExample holds a function's description and usage examples
@@ -520,19 +568,20 @@ type AppConfig struct {
 
 // Config holds UI-specific configuration.
 type Config struct {
-	Theme        ThemeSelectionConfig   `yaml:"theme" yamlcomment:"Theme selection and configuration"`
-	Features     FeaturesConfig         `yaml:"features" yamlcomment:"Feature flags - enable/disable UI features"`
-	Intellisense IntellisenseConfig     `yaml:"intellisense,omitempty" yamlcomment:"Intellisense and completion settings"`
-	Help         HelpConfig             `yaml:"help,omitempty" yamlcomment:"Help text and function examples"`
-	Display      DisplayConfig          `yaml:"display" yamlcomment:"Display and layout settings"`
-	Behavior     BehaviorConfig         `yaml:"behavior,omitempty" yamlcomment:"User behavior and interaction settings"`
-	Performance  PerformanceConfig      `yaml:"performance,omitempty" yamlcomment:"Performance and optimization settings"`
-	Search       SearchConfig           `yaml:"search,omitempty" yamlcomment:"Search and filtering settings"`
-	Formatting   FormattingConfig       `yaml:"formatting,omitempty" yamlcomment:"Data formatting settings"`
-	Popup        PopupConfig            `yaml:"popup" yamlcomment:"Popup and modal settings"`
-	Themes       map[string]ThemeConfig `yaml:"themes" yamlcomment:"Theme definitions"`
-	LegacyTheme  ThemeConfig            `yaml:",inline,omitempty"` // backward compatibility for single-theme files
-	Menu         MenuConfigYAML         `yaml:"menu" yamlcomment:"Function key labels/actions"`
+	Theme        ThemeSelectionConfig    `yaml:"theme" yamlcomment:"Theme selection and configuration"`
+	Features     FeaturesConfig          `yaml:"features" yamlcomment:"Feature flags - enable/disable UI features"`
+	Intellisense IntellisenseConfig      `yaml:"intellisense,omitempty" yamlcomment:"Intellisense and completion settings"`
+	Help         HelpConfig              `yaml:"help,omitempty" yamlcomment:"Help text and function examples"`
+	Display      DisplayConfig           `yaml:"display" yamlcomment:"Display and layout settings"`
+	Behavior     BehaviorConfig          `yaml:"behavior,omitempty" yamlcomment:"User behavior and interaction settings"`
+	Performance  PerformanceConfig       `yaml:"performance,omitempty" yamlcomment:"Performance and optimization settings"`
+	Search       SearchConfig            `yaml:"search,omitempty" yamlcomment:"Search and filtering settings"`
+	Formatting   FormattingConfig        `yaml:"formatting,omitempty" yamlcomment:"Data formatting settings"`
+	Popup        PopupConfig             `yaml:"popup" yamlcomment:"Popup and modal settings"`
+	Themes       map[string]ThemeConfig  `yaml:"themes" yamlcomment:"Theme definitions"`
+	Layouts      map[string]LayoutConfig `yaml:"layouts,omitempty" yamlcomment:"Named layouts restored with --layout NAME"`
+	LegacyTheme  ThemeConfig             `yaml:",inline,omitempty"` // backward compatibility for single-theme files
+	Menu         MenuConfigYAML          `yaml:"menu" yamlcomment:"Function key labels/actions"`
 }
 
 // ThemeConfigFile holds the complete configuration (app + ui).
@@ -544,19 +593,20 @@ type ThemeConfigFile struct {
 	Debug    DebugConfig    `yaml:"debug" yamlcomment:"Debug and logging settings"`
 	HelpMenu HelpMenuConfig `yaml:"help_menu,omitempty" yamlcomment:"Help menu information (populated dynamically from menu config)"`
 	// UI-specific settings
-	Theme        ThemeSelectionConfig   `yaml:"theme" yamlcomment:"Theme selection and configuration"`
-	Features     FeaturesConfig         `yaml:"features" yamlcomment:"Feature flags - enable/disable UI features"`
-	Intellisense IntellisenseConfig     `yaml:"intellisense,omitempty" yamlcomment:"Intellisense and completion settings"`
-	Help         HelpConfig             `yaml:"help,omitempty" yamlcomment:"Help text and function examples"`
-	Display      DisplayConfig          `yaml:"display" yamlcomment:"Display and layout settings"`
-	Behavior     BehaviorConfig         `yaml:"behavior,omitempty" yamlcomment:"User behavior and interaction settings"`
-	Performance  PerformanceConfig      `yaml:"performance,omitempty" yamlcomment:"Performance and optimization settings"`
-	Search       SearchConfig           `yaml:"search,omitempty" yamlcomment:"Search and filtering settings"`
-	Formatting   FormattingConfig       `yaml:"formatting,omitempty" yamlcomment:"Data formatting settings"`
-	Popup        PopupConfig            `yaml:"popup" yamlcomment:"Popup and modal settings"`
-	Themes       map[string]ThemeConfig `yaml:"themes" yamlcomment:"Theme definitions"`
-	LegacyTheme  ThemeConfig            `yaml:",inline,omitempty"` // backward compatibility for single-theme files
-	Menu         MenuConfigYAML         `yaml:"menu" yamlcomment:"Function key labels/actions"`
+	Theme        ThemeSelectionConfig    `yaml:"theme" yamlcomment:"Theme selection and configuration"`
+	Features     FeaturesConfig          `yaml:"features" yamlcomment:"Feature flags - enable/disable UI features"`
+	Intellisense IntellisenseConfig      `yaml:"intellisense,omitempty" yamlcomment:"Intellisense and completion settings"`
+	Help         HelpConfig              `yaml:"help,omitempty" yamlcomment:"Help text and function examples"`
+	Display      DisplayConfig           `yaml:"display" yamlcomment:"Display and layout settings"`
+	Behavior     BehaviorConfig          `yaml:"behavior,omitempty" yamlcomment:"User behavior and interaction settings"`
+	Performance  PerformanceConfig       `yaml:"performance,omitempty" yamlcomment:"Performance and optimization settings"`
+	Search       SearchConfig            `yaml:"search,omitempty" yamlcomment:"Search and filtering settings"`
+	Formatting   FormattingConfig        `yaml:"formatting,omitempty" yamlcomment:"Data formatting settings"`
+	Popup        PopupConfig             `yaml:"popup" yamlcomment:"Popup and modal settings"`
+	Themes       map[string]ThemeConfig  `yaml:"themes" yamlcomment:"Theme definitions"`
+	Layouts      map[string]LayoutConfig `yaml:"layouts,omitempty" yamlcomment:"Named layouts restored with --layout NAME"`
+	LegacyTheme  ThemeConfig             `yaml:",inline,omitempty"` // backward compatibility for single-theme files
+	Menu         MenuConfigYAML          `yaml:"menu" yamlcomment:"Function key labels/actions"`
 	// Legacy fields for backward compatibility (populated from new structure)
 	DefaultTheme   string          `yaml:"default_theme,omitempty" yamlcomment:"[DEPRECATED] Use ui.theme.default instead"`
 	AllowEditInput *bool           `yaml:"allow_edit_input,omitempty" yamlcomment:"[DEPRECATED] Use ui.features.allow_edit_input instead"`
@@ -573,13 +623,24 @@ type ThemeConfigFile struct {
 // Uses action-based keys (help, search, filter, etc.) with mode-specific key bindings.
 type MenuConfigYAML struct {
 	// Action-based menu items
-	Help   MenuItemConfig `yaml:"help,omitempty" yamlcomment:"Help action"`
-	Search MenuItemConfig `yaml:"search,omitempty" yamlcomment:"Search action"`
-	Filter MenuItemConfig `yaml:"filter,omitempty" yamlcomment:"Filter action"`
-	Copy   MenuItemConfig `yaml:"copy,omitempty" yamlcomment:"Copy action"`
-	Expr   MenuItemConfig `yaml:"expr,omitempty" yamlcomment:"Expression toggle action"`
-	Quit   MenuItemConfig `yaml:"quit,omitempty" yamlcomment:"Quit action"`
-	Custom MenuItemConfig `yaml:"custom,omitempty" yamlcomment:"Custom action"`
+	Help            MenuItemConfig `yaml:"help,omitempty" yamlcomment:"Help action"`
+	Search          MenuItemConfig `yaml:"search,omitempty" yamlcomment:"Search action"`
+	Filter          MenuItemConfig `yaml:"filter,omitempty" yamlcomment:"Filter action"`
+	Copy            MenuItemConfig `yaml:"copy,omitempty" yamlcomment:"Copy action"`
+	Expr            MenuItemConfig `yaml:"expr,omitempty" yamlcomment:"Expression toggle action"`
+	Quit            MenuItemConfig `yaml:"quit,omitempty" yamlcomment:"Quit action"`
+	Edit            MenuItemConfig `yaml:"edit,omitempty" yamlcomment:"Open in editor action"`
+	OpenURL         MenuItemConfig `yaml:"open_url,omitempty" yamlcomment:"Open URL action"`
+	Wrap            MenuItemConfig `yaml:"wrap,omitempty" yamlcomment:"Wrap toggle action"`
+	SearchSelection MenuItemConfig `yaml:"search_selection,omitempty" yamlcomment:"Search under selection action"`
+	Columns         MenuItemConfig `yaml:"columns,omitempty" yamlcomment:"Columnar view toggle action"`
+	ColumnFilter    MenuItemConfig `yaml:"column_filter,omitempty" yamlcomment:"Column filter row action"`
+	ColumnManager   MenuItemConfig `yaml:"column_manager,omitempty" yamlcomment:"Column manager overlay action"`
+	Annotate        MenuItemConfig `yaml:"annotate,omitempty" yamlcomment:"Row annotation action"`
+	Peek            MenuItemConfig `yaml:"peek,omitempty" yamlcomment:"Full value overlay action"`
+	Detail          MenuItemConfig `yaml:"detail,omitempty" yamlcomment:"Schema detail view action"`
+	Recent          MenuItemConfig `yaml:"recent,omitempty" yamlcomment:"Recently visited paths action"`
+	Custom          MenuItemConfig `yaml:"custom,omitempty" yamlcomment:"Custom action"`
 
 	// Legacy F-key based items (for backwards compatibility)
 	F1  MenuItemConfig `yaml:"f1,omitempty" yamlcomment:"F1 menu item (legacy)"`
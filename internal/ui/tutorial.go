@@ -0,0 +1,137 @@
+package ui
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed tutorial.yaml
+var tutorialDocument []byte
+
+// TutorialDocument returns the sample document the tutorial walks through.
+func TutorialDocument() []byte {
+	return append([]byte(nil), tutorialDocument...)
+}
+
+// TutorialStep is one step of the tutorial: what to do, and how to tell it
+// was done.
+type TutorialStep struct {
+	Title  string
+	Prompt string
+	Done   func(m *Model) bool
+}
+
+// Tutorial walks the user through the TUI step by step, advancing when the
+// current step's action was done (kvx tutorial).
+type Tutorial struct {
+	Steps []TutorialStep
+	Step  int // Index of the current step; len(Steps) once finished
+}
+
+// NewTutorial returns the steps of the tutorial over TutorialDocument, with
+// the keys of mode.
+func NewTutorial(mode KeyMode) *Tutorial {
+	key := func(vim, emacs, function string) string {
+		return MenuKeyBindings{Vim: vim, Emacs: emacs, Function: function}.forMode(mode)
+	}
+	down, forward, back := key("j", "C-n", "↓"), key("l", "C-f", "→"), key("h", "C-b", "←")
+	return &Tutorial{Steps: []TutorialStep{
+		{
+			Title:  "Move around",
+			Prompt: fmt.Sprintf("The table shows the keys and values of the document. Move down to the services row with %s.", down),
+			Done: func(m *Model) bool {
+				return m.Path == "" && formatPathForDisplay(m.selectedRowPath()) == "_.services"
+			},
+		},
+		{
+			Title:  "Open a value",
+			Prompt: fmt.Sprintf("Lists and maps open like folders: press %s to open services.", forward),
+			Done: func(m *Model) bool {
+				return formatPathForDisplay(m.Path) == "_.services"
+			},
+		},
+		{
+			Title:  "Go back up",
+			Prompt: fmt.Sprintf("Open the billing service ([2]), then go back to the list with %s. The path of the open value shows under the table.", back),
+			Done: func(m *Model) bool {
+				return formatPathForDisplay(m.Path) == "_.services" && slices.ContainsFunc(m.Visits, func(v Visit) bool {
+					return v.Path == "_.services[2]"
+				})
+			},
+		},
+		{
+			Title:  "Search",
+			Prompt: fmt.Sprintf("Search finds keys and values anywhere below the open value. Press %s and type degraded.", key("/", "C-s", "F3")),
+			Done: func(m *Model) bool {
+				return m.AdvancedSearchActive && strings.Contains(strings.ToLower(m.AdvancedSearchQuery), "degraded")
+			},
+		},
+		{
+			Title: "Filter",
+			Prompt: fmt.Sprintf("Press esc to leave the search, go back to the top with %s, and open config. Then press %s and type ret to keep only the keys starting with ret.",
+				back, key("f", "C-l", "F4")),
+			Done: func(m *Model) bool {
+				return formatPathForDisplay(m.Path) == "_.config" && strings.HasPrefix(strings.ToLower(m.MapFilterQuery), "ret")
+			},
+		},
+		{
+			Title: "Expressions",
+			Prompt: fmt.Sprintf(`CEL expressions compute new views of the data. Press esc, then %s, enter _.services.filter(s, s.status != "healthy") and press Enter to list the services that need attention.`,
+				key(":", "M-x", "F6")),
+			Done: tutorialUnhealthyServices,
+		},
+	}}
+}
+
+// tutorialUnhealthyServices reports whether the current view lists some
+// services of the tutorial document, none of them healthy.
+func tutorialUnhealthyServices(m *Model) bool {
+	items, ok := m.Node.([]interface{})
+	if !ok || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		svc, ok := item.(map[string]interface{})
+		if !ok || svc["name"] == nil || svc["status"] == "healthy" {
+			return false
+		}
+	}
+	return true
+}
+
+// advanceTutorial moves the tutorial past the steps that are done.
+func (m *Model) advanceTutorial() {
+	t := m.Tutorial
+	if t == nil || t.Step >= len(t.Steps) {
+		return
+	}
+	done := t.Step
+	for t.Step < len(t.Steps) && t.Steps[t.Step].Done(m) {
+		t.Step++
+	}
+	if t.Step == done {
+		return
+	}
+	m.ErrMsg = fmt.Sprintf("✓ %s", t.Steps[t.Step-1].Title)
+	m.StatusType = "success"
+}
+
+// tutorialTitle is the title of the tutorial panel.
+func (t *Tutorial) tutorialTitle() string {
+	if t.Step >= len(t.Steps) {
+		return "Tutorial: done"
+	}
+	return fmt.Sprintf("Tutorial %d/%d: %s", t.Step+1, len(t.Steps), t.Steps[t.Step].Title)
+}
+
+// tutorialText is the text of the tutorial panel: what to do next.
+func (t *Tutorial) tutorialText(mode KeyMode) string {
+	if t.Step >= len(t.Steps) {
+		help := MenuKeyBindings{Vim: "?", Emacs: "F1", Function: "F1"}.forMode(mode)
+		quit := MenuKeyBindings{Vim: "q", Emacs: "C-q", Function: "F10"}.forMode(mode)
+		return fmt.Sprintf("You finished the tutorial! Keep exploring, press %s for every key, or esc and then %s to quit.", help, quit)
+	}
+	return t.Steps[t.Step].Prompt
+}
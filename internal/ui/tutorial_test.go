@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testTutorialModel(t *testing.T, mode KeyMode) *Model {
+	t.Helper()
+	var root map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(TutorialDocument(), &root))
+	m := InitialModel(root)
+	m.Root = root
+	m.KeyMode = mode
+	m.InputFocused = false
+	m.NoColor = true
+	m.WinWidth = 100
+	m.WinHeight = 40
+	m.Tbl.Focus()
+	m.applyLayout(true)
+	m.Tutorial = NewTutorial(mode)
+	return &m
+}
+
+func press(m *Model, keys ...tea.KeyPressMsg) *Model {
+	for _, k := range keys {
+		next, _ := m.Update(k)
+		m = next.(*Model)
+	}
+	return m
+}
+
+func TestTutorialWalkThrough(t *testing.T) {
+	var (
+		down  = tea.KeyPressMsg{Code: 'j', Text: "j"}
+		right = tea.KeyPressMsg{Code: 'l', Text: "l"}
+		left  = tea.KeyPressMsg{Code: 'h', Text: "h"}
+		esc   = tea.KeyPressMsg{Code: tea.KeyEscape}
+		enter = tea.KeyPressMsg{Code: tea.KeyEnter}
+	)
+	m := testTutorialModel(t, KeyModeVim)
+	tut := m.Tutorial
+	view := stripANSI(m.View().Content)
+	assert.Contains(t, view, "Tutorial 1/6: Move around")
+	assert.Contains(t, view, "Move down to the services row with j.")
+
+	m = press(m, down)
+	assert.Equal(t, 1, tut.Step)
+	assert.Equal(t, "✓ Move around", m.ErrMsg)
+
+	m = press(m, right)
+	assert.Equal(t, 2, tut.Step)
+
+	// Going back without opening billing first does not count.
+	m = press(m, down, down, right)
+	require.Equal(t, "_.services[2]", formatPathForDisplay(m.Path))
+	assert.Equal(t, 2, tut.Step)
+	m = press(m, left)
+	assert.Equal(t, 3, tut.Step)
+
+	m = press(m, tea.KeyPressMsg{Code: '/', Text: "/"})
+	typeKeys(m, "degraded")
+	assert.Equal(t, 4, tut.Step)
+
+	m = press(m, esc, left)
+	require.Equal(t, "", m.Path)
+	m.Tbl.SetCursor(0)
+	m = press(m, right, tea.KeyPressMsg{Code: 'f', Text: "f"})
+	typeKeys(m, "ret")
+	assert.Equal(t, 5, tut.Step)
+
+	m = press(m, esc, esc, tea.KeyPressMsg{Code: ':', Text: ":"})
+	require.True(t, m.InputFocused)
+	m.PathInput.SetValue(`_.services.filter(s, s.status != "healthy")`)
+	m = press(m, enter)
+	assert.Equal(t, 6, tut.Step)
+	assert.Same(t, tut, m.Tutorial, "the tutorial carries over to the expression's model")
+	assert.Contains(t, stripANSI(m.View().Content), "Tutorial: done")
+}
+
+func TestTutorialKeysFollowMode(t *testing.T) {
+	assert.Contains(t, NewTutorial(KeyModeEmacs).Steps[0].Prompt, "with C-n.")
+	assert.Contains(t, NewTutorial(KeyModeFunction).Steps[3].Prompt, "Press F3")
+	assert.Contains(t, (&Tutorial{}).tutorialText(KeyModeFunction), "F10 to quit")
+}
+
+func TestTutorialUnhealthyServices(t *testing.T) {
+	m := testTutorialModel(t, KeyModeVim)
+	assert.False(t, tutorialUnhealthyServices(m), "the root is a map")
+	m.Node = []interface{}{map[string]interface{}{"name": "billing", "status": "degraded"}}
+	assert.True(t, tutorialUnhealthyServices(m))
+	m.Node = []interface{}{map[string]interface{}{"name": "api", "status": "healthy"}}
+	assert.False(t, tutorialUnhealthyServices(m))
+	m.Node = []interface{}{}
+	assert.False(t, tutorialUnhealthyServices(m))
+}
@@ -7,11 +7,11 @@ import (
 
 	"charm.land/bubbles/v2/table"
 	"charm.land/lipgloss/v2"
-	runewidth "github.com/mattn/go-runewidth"
 	"golang.org/x/term"
 
 	"github.com/oakwood-commons/kvx/internal/formatter"
 	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/textwidth"
 )
 
 var (
@@ -38,12 +38,12 @@ func repeatToWidth(fill string, width int) string {
 		fill = " "
 	}
 	var b strings.Builder
-	for runewidth.StringWidth(b.String()) < width {
+	for textwidth.Width(b.String()) < width {
 		b.WriteString(fill)
 	}
 	result := b.String()
-	if w := runewidth.StringWidth(result); w > width {
-		result = runewidth.Truncate(result, width, "")
+	if w := textwidth.Width(result); w > width {
+		result = textwidth.Truncate(result, width, "")
 	}
 	return result
 }
@@ -228,92 +228,20 @@ func padANSIToWidth(s string, targetWidth int) string {
 // ansiVisibleWidth calculates the visible width of a string with ANSI escape sequences.
 func ansiVisibleWidth(s string) int {
 	plain := ansiRegexp.ReplaceAllString(s, "")
-	return runewidth.StringWidth(plain)
+	return textwidth.Width(plain)
 }
 
 // clampANSITextWidth trims each line to the provided max display width while
-// preserving ANSI escape sequences.
+// preserving ANSI escape sequences. Grapheme clusters are never split.
 func clampANSITextWidth(s string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return ""
 	}
-
-	var out strings.Builder
-	width := 0
-
-	// State machine for ANSI escape sequences.
-	// Handles both CSI (ESC [ ... letter) and OSC (ESC ] ... ST/BEL).
-	const (
-		stNormal = iota
-		stEsc    // just saw ESC, next char determines sequence type
-		stCSI    // inside CSI sequence, waiting for terminating letter
-		stOSC    // inside OSC sequence, waiting for ST (ESC \) or BEL
-		stOSCEsc // inside OSC, just saw ESC (looking for \\ to end)
-	)
-	state := stNormal
-
-	for _, r := range s {
-		if r == '\n' {
-			out.WriteRune(r)
-			width = 0
-			state = stNormal
-			continue
-		}
-
-		switch state {
-		case stNormal:
-			if r == 0x1b { // ESC
-				state = stEsc
-				out.WriteRune(r)
-				continue
-			}
-			w := runewidth.RuneWidth(r)
-			if width+w > maxWidth {
-				continue
-			}
-			out.WriteRune(r)
-			width += w
-
-		case stEsc:
-			out.WriteRune(r)
-			switch r {
-			case '[':
-				state = stCSI
-			case ']':
-				state = stOSC
-			default:
-				// Single-char escape (e.g. ESC c) — done.
-				state = stNormal
-			}
-
-		case stCSI:
-			out.WriteRune(r)
-			// CSI sequences end with a letter.
-			if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') {
-				state = stNormal
-			}
-
-		case stOSC:
-			out.WriteRune(r)
-			switch r {
-			case 0x1b:
-				state = stOSCEsc
-			case 0x07: // BEL terminates OSC
-				state = stNormal
-			}
-
-		case stOSCEsc:
-			out.WriteRune(r)
-			// ESC \ (ST) terminates OSC; anything else stays in OSC.
-			if r == '\\' {
-				state = stNormal
-			} else {
-				state = stOSC
-			}
-		}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = textwidth.Truncate(line, maxWidth, "")
 	}
-
-	return out.String()
+	return strings.Join(lines, "\n")
 }
 
 // newStyle creates a lipgloss style.
@@ -420,8 +348,90 @@ func windowTable(table string, selected int, maxLines int) (string, int) {
 	return strings.Join(outLines, "\n") + "\n", newSelected
 }
 
+// windowWrappedTable is windowTable for tables whose rows may span several
+// lines. spans holds the body line range of each row, as returned by
+// formatter.RenderTableRowSpans, and selected is a row; the window keeps as
+// much of it visible as fits and never starts on a continuation line unless
+// the row itself is taller than the window. It returns the windowed table
+// and the body line range (first, count) of the selected row within it.
+func windowWrappedTable(table string, spans [][2]int, selected int, maxLines int) (string, int, int) {
+	if maxLines <= 0 {
+		return "", 0, 0
+	}
+
+	lines := strings.Split(strings.TrimRight(table, "\n"), "\n")
+	if len(lines) < 3 {
+		return clampANSITextHeight(table, maxLines), 0, 0
+	}
+	header, separator, body := lines[0], lines[1], lines[2:]
+	if len(spans) == 0 {
+		windowed, _ := windowTable(table, 0, maxLines)
+		return windowed, 0, 0
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	if selected >= len(spans) {
+		selected = len(spans) - 1
+	}
+	sel := spans[selected]
+
+	if len(lines) <= maxLines {
+		return table, sel[0], sel[1] - sel[0]
+	}
+	bodyLines := maxLines - 2
+	if bodyLines <= 0 {
+		out := []string{header}
+		if maxLines > 1 {
+			out = append(out, separator)
+		}
+		return strings.Join(out, "\n") + "\n", 0, 0
+	}
+
+	start := 0
+	if sel[1] > bodyLines {
+		start = sel[1] - bodyLines
+	}
+	if start > sel[0] {
+		// The selected row is taller than the window: show its top.
+		start = sel[0]
+	}
+	if maxStart := len(body) - bodyLines; start > maxStart {
+		start = maxStart
+	}
+	// Snap forward to a row boundary so the window doesn't open mid-row.
+	for _, sp := range spans {
+		if sp[0] >= start {
+			start = sp[0]
+			break
+		}
+	}
+	end := start + bodyLines
+	if end > len(body) {
+		end = len(body)
+	}
+
+	count := sel[1] - sel[0]
+	if sel[0]+count > end {
+		count = end - sel[0]
+	}
+	outLines := append([]string{header, separator}, body[start:end]...)
+	return strings.Join(outLines, "\n") + "\n", sel[0] - start, count
+}
+
 // highlightTableRow highlights the selected row in a table.
 func highlightTableRow(table string, selected int, targetWidth int, noColor bool) string {
+	lines := strings.Split(strings.TrimRight(table, "\n"), "\n")
+	rowCount := len(lines) - 2 // header + separator
+	if selected >= rowCount {
+		selected = rowCount - 1
+	}
+	return highlightTableLines(table, selected, 1, targetWidth, noColor)
+}
+
+// highlightTableLines highlights count body lines of a table starting at
+// first, i.e. one logical row when its value is wrapped.
+func highlightTableLines(table string, first, count int, targetWidth int, noColor bool) string {
 	lines := strings.Split(strings.TrimRight(table, "\n"), "\n")
 	if len(lines) < 3 {
 		return table
@@ -431,9 +441,6 @@ func highlightTableRow(table string, selected int, targetWidth int, noColor bool
 	if rowCount <= 0 {
 		return table
 	}
-	if selected >= rowCount {
-		selected = rowCount - 1
-	}
 	if targetWidth < 0 {
 		targetWidth = 0
 	}
@@ -448,7 +455,7 @@ func highlightTableRow(table string, selected int, targetWidth int, noColor bool
 	}
 	for i := 0; i < rowCount; i++ {
 		lineIdx := i + 2
-		if i == selected {
+		if i >= first && i < first+count {
 			// Strip existing ANSI so the highlight background isn't reset mid-row.
 			plain := ansiRegexp.ReplaceAllString(lines[lineIdx], "")
 			padded := padANSIToWidth(plain, targetWidth)
@@ -472,28 +479,11 @@ func renderSearchTable(hits []searchHit, keyWidth, valueWidth int, noColor bool)
 		if width <= 0 {
 			return ""
 		}
-		if runewidth.StringWidth(s) <= width {
-			return s
-		}
-		if width <= 3 {
-			return runewidth.Truncate(s, width, "")
-		}
-		target := width - 3
-		var out strings.Builder
-		w := 0
-		for _, r := range s {
-			rw := runewidth.RuneWidth(r)
-			if w+rw > target {
-				break
-			}
-			out.WriteRune(r)
-			w += rw
-		}
-		return out.String() + "..."
+		return formatter.TruncateCell(s, width)
 	}
 	pad := func(s string, width int) string {
 		s = truncateWithEllipsis(s, width)
-		w := runewidth.StringWidth(s)
+		w := textwidth.Width(s)
 		if w >= width {
 			return s
 		}
@@ -546,8 +536,7 @@ func renderScalarBlock(node interface{}, width int, noColor bool) string {
 		headerStyle = headerStyle.Foreground(th.HeaderFG).Background(th.HeaderBG)
 		valueStyle = valueStyle.Foreground(th.ValueColor)
 	}
-	// Preserve real line breaks for scalar strings so snapshot/CLI views show multiline content.
-	val := formatter.StringifyPreserveNewlines(node)
+	val := scalarDisplayText(node)
 	lines := strings.Split(val, "\n")
 	var b strings.Builder
 	// Single header line to satisfy tests expecting a header before values.
@@ -590,100 +579,12 @@ func isSimpleScalarArray(arr []interface{}) bool {
 
 // leftTruncate keeps the rightmost visible width of a plain (non-ANSI) string.
 func leftTruncate(s string, maxWidth int) string {
-	if maxWidth <= 0 {
-		return ""
-	}
-	if ansiVisibleWidth(s) <= maxWidth {
-		return s
-	}
-	runes := []rune(s)
-	total := 0
-	out := []rune{}
-	// Walk from the end to preserve the tail.
-	for i := len(runes) - 1; i >= 0; i-- {
-		w := runewidth.RuneWidth(runes[i])
-		if total+w > maxWidth {
-			break
-		}
-		out = append(out, runes[i])
-		total += w
-	}
-	// Reverse to restore order.
-	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
-		out[i], out[j] = out[j], out[i]
-	}
-	return string(out)
+	return textwidth.TruncateLeft(s, maxWidth)
 }
 
 // leftTruncateANSI keeps the rightmost visible width of a string while preserving ANSI sequences.
 func leftTruncateANSI(s string, maxWidth int) string {
-	if maxWidth <= 0 {
-		return ""
-	}
-	if ansiVisibleWidth(s) <= maxWidth {
-		return s
-	}
-
-	type token struct {
-		text  string
-		width int
-	}
-
-	tokens := make([]token, 0, len(s))
-	inEscape := false
-	var esc strings.Builder
-	for _, r := range s {
-		if inEscape {
-			esc.WriteRune(r)
-			if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') {
-				tokens = append(tokens, token{text: esc.String(), width: 0})
-				esc.Reset()
-				inEscape = false
-			}
-			continue
-		}
-		if r == 0x1b { // ESC
-			inEscape = true
-			esc.WriteRune(r)
-			continue
-		}
-		tokens = append(tokens, token{text: string(r), width: runewidth.RuneWidth(r)})
-	}
-	if inEscape && esc.Len() > 0 {
-		tokens = append(tokens, token{text: esc.String(), width: 0})
-	}
-
-	visible := 0
-	var pendingZeros []token
-	var out []token
-	for i := len(tokens) - 1; i >= 0; i-- {
-		t := tokens[i]
-		if t.width == 0 {
-			pendingZeros = append(pendingZeros, t)
-			continue
-		}
-		if visible+t.width > maxWidth {
-			continue
-		}
-		if len(pendingZeros) > 0 {
-			out = append(out, pendingZeros...)
-			pendingZeros = nil
-		}
-		out = append(out, t)
-		visible += t.width
-	}
-	if visible > 0 && len(pendingZeros) > 0 {
-		out = append(out, pendingZeros...)
-	}
-	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
-		out[i], out[j] = out[j], out[i]
-	}
-
-	var b strings.Builder
-	for _, t := range out {
-		b.WriteString(t.text)
-	}
-	return b.String()
+	return textwidth.TruncateLeft(s, maxWidth)
 }
 
 // addBottomLabel injects a left-justified path and right-aligned label into the bottom border of a bordered panel.
@@ -712,7 +613,7 @@ func addBottomLabel(panel string, leftText string, label string, targetWidth int
 		return panel
 	}
 
-	width := runewidth.StringWidth(plainBottom)
+	width := textwidth.Width(plainBottom)
 	if targetWidth > 0 {
 		width = targetWidth
 	}
@@ -720,7 +621,7 @@ func addBottomLabel(panel string, leftText string, label string, targetWidth int
 		return panel
 	}
 
-	inner := width - runewidth.StringWidth(leftCorner) - runewidth.StringWidth(rightCorner)
+	inner := width - textwidth.Width(leftCorner) - textwidth.Width(rightCorner)
 	left := strings.TrimSpace(leftText)
 	if left != "" {
 		left = " " + left + " "
@@ -730,8 +631,8 @@ func addBottomLabel(panel string, leftText string, label string, targetWidth int
 		right = " " + right + " "
 	}
 
-	leftW := runewidth.StringWidth(left)
-	rightW := runewidth.StringWidth(right)
+	leftW := textwidth.Width(left)
+	rightW := textwidth.Width(right)
 	if leftW+rightW > inner {
 		avail := inner - rightW
 		if avail < 0 {
@@ -739,7 +640,7 @@ func addBottomLabel(panel string, leftText string, label string, targetWidth int
 		}
 		if avail > 0 {
 			left = leftTruncate(left, avail)
-			leftW = runewidth.StringWidth(left)
+			leftW = textwidth.Width(left)
 		} else {
 			left = ""
 			leftW = 0
@@ -820,27 +721,18 @@ func panelWithTitle(title string, content string, width int, height int, border
 	// Build new top border with a centered title: "┌─ Title ─────┐"
 	titleWithSpace := " " + title + " "
 
-	plainTopWidth := runewidth.StringWidth(plainTop)
-	leftWidth := runewidth.StringWidth(topLeft)
-	rightWidth := runewidth.StringWidth(topRight)
+	plainTopWidth := textwidth.Width(plainTop)
+	leftWidth := textwidth.Width(topLeft)
+	rightWidth := textwidth.Width(topRight)
 	titleInnerWidth := plainTopWidth - leftWidth - rightWidth
 	if titleInnerWidth < 1 {
 		return bordered
 	}
 
-	// Trim title to available width (display-width aware).
-	// Use lipgloss.Width for the final measurement so multi-rune
-	// sequences like emoji+VS16 (e.g. ⚙️) are counted correctly.
-	titleRunes := []rune(titleWithSpace)
-	trimmed := make([]rune, 0, len(titleRunes))
-	for _, r := range titleRunes {
-		trimmed = append(trimmed, r)
-		if lipgloss.Width(string(trimmed)) > titleInnerWidth {
-			trimmed = trimmed[:len(trimmed)-1]
-			break
-		}
-	}
-	titleWidth := lipgloss.Width(string(trimmed))
+	// Trim title to available width without splitting grapheme clusters
+	// such as emoji+VS16 (e.g. ⚙️).
+	trimmed := textwidth.Truncate(titleWithSpace, titleInnerWidth, "")
+	titleWidth := lipgloss.Width(trimmed)
 
 	// Center the title by padding with box-drawing characters, then clamp to titleInnerWidth.
 	leftPad := 0
@@ -858,7 +750,7 @@ func panelWithTitle(title string, content string, width int, height int, border
 	borderColor := th.SeparatorColor
 	borderPaint := lipgloss.NewStyle().Foreground(borderColor).Render
 	titlePaint := lipgloss.NewStyle().Foreground(th.HeaderFG).Bold(true).Render
-	newTopBorder := borderPaint(topLeft) + borderPaint(repeatToWidth(border.Top, leftPad)) + titlePaint(string(trimmed)) + borderPaint(repeatToWidth(border.Top, rightPad)) + borderPaint(topRight)
+	newTopBorder := borderPaint(topLeft) + borderPaint(repeatToWidth(border.Top, leftPad)) + titlePaint(trimmed) + borderPaint(repeatToWidth(border.Top, rightPad)) + borderPaint(topRight)
 
 	// Reconstruct the panel with new top border
 	lines[0] = newTopBorder
@@ -995,3 +887,13 @@ func RenderNodeTable(node interface{}, noColor bool, keyColWidth, valueColWidth
 	}
 	return output
 }
+
+// scalarDisplayText returns the text shown when a scalar is opened on its
+// own: binary data as a size line plus hexdump, other values with real line
+// breaks preserved so multiline content is readable.
+func scalarDisplayText(node interface{}) string {
+	if b, ok := formatter.BinaryBytes(node); ok {
+		return formatter.BinaryPlaceholder(b) + "\n\n" + strings.TrimRight(formatter.Hexdump(b), "\n")
+	}
+	return formatter.StringifyPreserveNewlines(node)
+}
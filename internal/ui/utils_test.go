@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
 	"charm.land/lipgloss/v2"
@@ -146,6 +147,30 @@ func TestLeftTruncateANSI(t *testing.T) {
 	assert.Equal(t, 3, ansiVisibleWidth(result))
 }
 
+func TestTruncationKeepsGraphemeClusters(t *testing.T) {
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+	accented := "e\u0301"
+
+	assert.Equal(t, family, clampANSITextWidth(family+"abc", 2))
+	assert.Equal(t, "a"+accented, clampANSITextWidth("a"+accented+"bc", 2))
+	assert.Equal(t, "東京\nabcde", clampANSITextWidth("東京タワー\nabcdef", 5))
+	assert.Equal(t, "c"+family, leftTruncate("abc"+family, 3))
+	assert.Equal(t, "東京...", truncateString("東京タワー", 7))
+	assert.Equal(t, "東", truncateNoEllipsis("東京", 3))
+}
+
+func TestStyleRowsWithWidths_WideCharacters(t *testing.T) {
+	rows := styleRowsWithWidths([][]string{
+		{"名前", "東京タワー東京タワー"},
+		{"family", "\U0001F468\u200d\U0001F469\u200d\U0001F467 x"},
+		{"cafe\u0301", "plain"},
+	}, 6, 9)
+	for _, r := range rows {
+		assert.Equal(t, 6, lipgloss.Width(r[0]), "key %q", r[0])
+		assert.Equal(t, 9, lipgloss.Width(r[1]), "value %q", r[1])
+	}
+}
+
 func TestWindowTable(t *testing.T) {
 	// Simple table with header, separator, and rows
 	table := "HEADER\n------\nrow1\nrow2\nrow3\nrow4\nrow5\n"
@@ -208,6 +233,70 @@ func TestHighlightTableRow(t *testing.T) {
 	})
 }
 
+func TestWindowWrappedTable(t *testing.T) {
+	// "b" wraps onto two continuation lines.
+	table := "KEY   VALUE\n-----\na     one\nb     two\n      two-2\n      two-3\nc     three\nd     four\n"
+	spans := [][2]int{{0, 1}, {1, 4}, {4, 5}, {5, 6}}
+
+	t.Run("fits", func(t *testing.T) {
+		result, first, count := windowWrappedTable(table, spans, 1, 20)
+		assert.Equal(t, table, result)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("scrolls to whole row", func(t *testing.T) {
+		result, first, count := windowWrappedTable(table, spans, 1, 5)
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		assert.Equal(t, []string{"b     two", "      two-2", "      two-3"}, lines[2:])
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("never opens mid-row", func(t *testing.T) {
+		result, first, count := windowWrappedTable(table, spans, 2, 4)
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		assert.Equal(t, []string{"c     three", "d     four"}, lines[2:])
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("row taller than window", func(t *testing.T) {
+		result, first, count := windowWrappedTable(table, spans, 1, 4)
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		assert.Equal(t, []string{"b     two", "      two-2"}, lines[2:])
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("zero maxLines", func(t *testing.T) {
+		result, _, _ := windowWrappedTable(table, spans, 0, 0)
+		assert.Equal(t, "", result)
+	})
+
+	t.Run("blank key starts its own row", func(t *testing.T) {
+		// The "" key renders with a blank key column, like a continuation line.
+		blank := "KEY   VALUE\n-----\na     one\n      empty-key\nc     three\n"
+		result, first, count := windowWrappedTable(blank, [][2]int{{0, 1}, {1, 2}, {2, 3}}, 1, 3)
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		assert.Equal(t, []string{"      empty-key"}, lines[2:])
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestHighlightTableLines(t *testing.T) {
+	table := "KEY   VALUE\n-----\na     one\nb     two\n      two-2\nc     three\n"
+	result := highlightTableLines(table, 1, 2, 20, true)
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	assert.Equal(t, "a     one", lines[2])
+	assert.NotEqual(t, "b     two", lines[3])
+	assert.Contains(t, lines[3], "b     two")
+	assert.Contains(t, lines[4], "two-2")
+	assert.NotEqual(t, "      two-2", lines[4])
+	assert.Equal(t, "c     three", lines[5])
+}
+
 func TestRenderScalarBlock(t *testing.T) {
 	result := renderScalarBlock("hello world", 40, true)
 	assert.Contains(t, result, "VALUE")
@@ -1,49 +1,53 @@
 package ui
 
-import (
-	"fmt"
-
-	tea "charm.land/bubbletea/v2"
-)
+import tea "charm.land/bubbletea/v2"
 
 // updateViewMode determines whether the current node should be rendered as a
 // list view, detail view, or the default table view based on the DisplaySchema.
 func (m *Model) updateViewMode(node interface{}) {
-	if m.DisplaySchema == nil {
-		m.ViewMode = ""
-		m.ListViewState = nil
-		m.DetailViewState = nil
-		m.StatusViewState = nil
-		return
-	}
-
 	// Status view takes priority — it's a top-level screen, not a drill-down view.
-	if m.DisplaySchema.Status != nil && m.DisplaySchema.Status.TitleField != "" {
+	if m.DisplaySchema != nil && m.DisplaySchema.Status != nil && m.DisplaySchema.Status.TitleField != "" {
 		m.ViewMode = "status"
 		m.StatusViewState = buildStatusViewModel(
 			node, m.DisplaySchema, m.KeyMode, m.NoColor, m.DoneChan,
 			m.WinWidth, m.WinHeight,
 		)
+		if m.StatusViewState != nil {
+			m.StatusViewState.Kiosk = m.Kiosk
+			m.StatusViewState.Static = m.Deterministic
+			if m.StatusController != nil {
+				m.StatusViewState.attachController(m.StatusController)
+			}
+		}
 		m.ListViewState = nil
 		m.DetailViewState = nil
 		return
 	}
 
-	// If we're coming from a list view drill-in, check for detail view
+	// If we're coming from a list view drill-in, or the detail key opened an
+	// array element, check for detail view
 	if m.ViewMode == "list" || m.ViewMode == "detail" {
 		// If the node is a single object and we have detail config, show detail
 		if _, isObj := node.(map[string]interface{}); isObj {
-			if m.DisplaySchema.Detail != nil {
+			if schema := m.detailSchema(); schema != nil {
 				m.ViewMode = "detail"
-				m.DetailViewState = buildDetailViewModel(node, m.DisplaySchema, m.WinWidth, m.WinHeight)
+				m.DetailViewState = buildDetailViewModel(node, schema, m.WinWidth, m.WinHeight)
 				m.ListViewState = nil
 				return
 			}
 		}
 	}
 
-	// Check if node is a homogeneous array of objects for list view
-	if m.DisplaySchema.List != nil && m.DisplaySchema.List.TitleField != "" && isHomogeneousObjectArray(node) {
+	if m.DisplaySchema == nil {
+		m.ViewMode = ""
+		m.ListViewState = nil
+		m.DetailViewState = nil
+		m.StatusViewState = nil
+		return
+	}
+
+	// Check if node is a collection of objects for list view
+	if isListCollection(node, m.DisplaySchema.List) {
 		m.ViewMode = "list"
 		m.ListViewState = buildListViewModel(node, m.DisplaySchema, m.WinWidth, m.WinHeight)
 		m.DetailViewState = nil
@@ -56,6 +60,23 @@ func (m *Model) updateViewMode(node interface{}) {
 	m.DetailViewState = nil
 }
 
+// reflowViews lays the list and detail views out again after the window
+// changed size, keeping their scroll positions proportional. oldHeight is the
+// window height before the resize. The table regenerates its rows in
+// applyLayout and the status view lays itself out on every render.
+func (m *Model) reflowViews(oldHeight int) {
+	switch m.ViewMode {
+	case "list":
+		if m.ListViewState != nil {
+			m.ListViewState.reflow(oldHeight, m.WinHeight)
+		}
+	case "detail":
+		if m.DetailViewState != nil {
+			m.DetailViewState.reflow(m.WinWidth, m.WinHeight)
+		}
+	}
+}
+
 // activeCustomView returns the active CustomView for the current ViewMode,
 // or nil when the default table view is active.
 func (m *Model) activeCustomView() CustomView {
@@ -189,16 +210,14 @@ func (m *Model) handleListViewKey(keyStr string) (bool, tea.Model, tea.Cmd) {
 			if len(items) > 0 && lv.Selected < len(items) {
 				m.AdvancedSearchActive = false
 				m.SearchInput.Blur()
-				item := items[lv.Selected]
-				m.DetailSourcePath = m.Path
-				m.storeCursorForPath(m.Path)
-				newPath := buildPathWithKey(m.Path, fmt.Sprintf("[%d]", item.Index))
-				arr, ok := m.Node.([]interface{})
-				if !ok || item.Index >= len(arr) {
+				newPath, childNode, ok := listItemChild(m.Node, m.Path, items[lv.Selected])
+				if !ok {
 					return true, m, nil
 				}
+				m.DetailSourcePath = m.Path
+				m.storeCursorForPath(m.Path)
 				m.ViewMode = "detail"
-				newModel := m.NavigateTo(arr[item.Index], normalizePathForModel(newPath))
+				newModel := m.NavigateTo(childNode, normalizePathForModel(newPath))
 				newModel.PathKeys = parsePathKeys(newModel.Path)
 				newModel.applyLayout(true)
 				return true, newModel, nil
@@ -283,16 +302,12 @@ func (m *Model) handleListViewKey(keyStr string) (bool, tea.Model, tea.Cmd) {
 	case VimActionForward, VimActionEnter:
 		// Drill into the selected item
 		if lv.Selected < len(items) {
-			item := items[lv.Selected]
-			m.DetailSourcePath = m.Path
-			m.storeCursorForPath(m.Path)
-
-			newPath := buildPathWithKey(m.Path, fmt.Sprintf("[%d]", item.Index))
-			arr, ok := m.Node.([]interface{})
-			if !ok || item.Index >= len(arr) {
+			newPath, childNode, ok := listItemChild(m.Node, m.Path, items[lv.Selected])
+			if !ok {
 				return true, m, nil
 			}
-			childNode := arr[item.Index]
+			m.DetailSourcePath = m.Path
+			m.storeCursorForPath(m.Path)
 
 			// Force detail view mode before NavigateTo
 			m.ViewMode = "detail"
@@ -341,7 +356,7 @@ func (m *Model) handleListViewKey(keyStr string) (bool, tea.Model, tea.Cmd) {
 		m.SearchInput.SetCursor(len(lv.Filter))
 		return true, m, m.SearchInput.Focus()
 
-	case VimActionCopy, VimActionNextMatch, VimActionPrevMatch, VimActionClearSearch:
+	case VimActionCopy, VimActionNextMatch, VimActionPrevMatch, VimActionClearSearch, VimActionEdit, VimActionOpenURL, VimActionWrap, VimActionSearchSelection:
 		// No meaningful use in list view; consume to prevent fallthrough.
 		return true, m, nil
 
@@ -426,12 +441,12 @@ func (m *Model) handleDetailViewKey(keyStr string) (bool, tea.Model, tea.Cmd) {
 		dv.ScrollTop = 0
 		return true, m, nil
 
-	case VimActionSearch, VimActionFilter, VimActionNextMatch, VimActionPrevMatch, VimActionClearSearch:
+	case VimActionSearch, VimActionFilter, VimActionNextMatch, VimActionPrevMatch, VimActionClearSearch, VimActionSearchSelection:
 		// Not applicable in detail view; consume to prevent fallthrough.
 		return true, m, nil
 
-	case VimActionCopy:
-		// Copy not applicable in display schema detail view; consume.
+	case VimActionCopy, VimActionEdit, VimActionOpenURL, VimActionWrap:
+		// Copy, edit, open and wrap not applicable in display schema detail view; consume.
 		return true, m, nil
 
 	case VimActionQuit:
@@ -465,6 +480,9 @@ func (m *Model) renderCustomViewContent() (string, bool) {
 
 	width := m.WinWidth
 	height := m.WinHeight - 6 // account for borders, footer, status
+	if useCompactLayout(m.WinWidth, m.WinHeight) {
+		height = m.WinHeight - 2 // path and footer lines only
+	}
 	content := cv.Render(width, height, m.NoColor)
 	return content, true
 }
@@ -497,6 +515,9 @@ func (m *Model) syncCustomViewFilter() {
 func (m *Model) customSearchTitle() string {
 	cv := m.activeCustomView()
 	if cv == nil {
+		if m.AdvancedSearchScope != "" {
+			return "Search under " + formatPathForDisplay(m.AdvancedSearchScope)
+		}
 		return ""
 	}
 	// The view itself may provide a title override.
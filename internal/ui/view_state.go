@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ViewState is the view layout kvx remembers per dataset, such as every
+// file named package.json or every input shown with one schema.
+type ViewState struct {
+	ViewMode      string   `json:"viewMode,omitempty"`      // "columns" or "table"
+	ColumnOrder   []string `json:"columnOrder,omitempty"`   // Column order of the columnar view
+	HiddenColumns []string `json:"hiddenColumns,omitempty"` // Columns hidden in the columnar view
+	Sort          string   `json:"sort,omitempty"`          // --sort order last given for the dataset
+}
+
+// View modes recorded in ViewState.
+const (
+	ViewModeTable   = "table"
+	ViewModeColumns = "columns"
+)
+
+// viewStateFile is the file in StateDir holding the ViewState of every dataset.
+const viewStateFile = "views.json"
+
+// StateDir returns the directory kvx keeps state in, or "" when no home
+// directory is known.
+func StateDir() string {
+	home, _ := os.UserHomeDir()
+	return stateDir(runtime.GOOS, os.Getenv, home)
+}
+
+// stateDir returns $XDG_STATE_HOME/kvx when XDG_STATE_HOME is set.
+// Otherwise it returns %LOCALAPPDATA%\kvx on Windows and
+// ~/.local/state/kvx elsewhere.
+func stateDir(goos string, getenv func(string) string, home string) string {
+	if xdg := getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "kvx")
+	}
+	if goos == "windows" {
+		if local := getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "kvx")
+		}
+	}
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "kvx")
+}
+
+// LoadViewState returns the view state saved for key, if any.
+func LoadViewState(key string) (ViewState, bool) {
+	states, err := readViewStates(StateDir())
+	if err != nil {
+		return ViewState{}, false
+	}
+	vs, ok := states[key]
+	return vs, ok
+}
+
+// SaveViewState records vs as the view state of key.
+func SaveViewState(key string, vs ViewState) error {
+	dir := StateDir()
+	if dir == "" {
+		return errors.New("no state directory")
+	}
+	states, err := readViewStates(dir)
+	if err != nil {
+		// Start over rather than keep failing on a damaged file.
+		states = map[string]ViewState{}
+	}
+	states[key] = vs
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("save view state: %w", err)
+	}
+	// Write a temporary file and rename it so a crash never leaves half a file.
+	tmp, err := os.CreateTemp(dir, viewStateFile+".*")
+	if err != nil {
+		return fmt.Errorf("save view state: %w", err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, viewStateFile))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("save view state: %w", err)
+	}
+	return nil
+}
+
+func readViewStates(dir string) (map[string]ViewState, error) {
+	states := map[string]ViewState{}
+	if dir == "" {
+		return states, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, viewStateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// CurrentViewState returns the view layout of the model. Sort is left to
+// the caller, since the TUI does not change it.
+func (m *Model) CurrentViewState() ViewState {
+	vs := ViewState{
+		ViewMode:      ViewModeTable,
+		ColumnOrder:   slices.Clone(m.ColumnOrder),
+		HiddenColumns: slices.Clone(m.HiddenColumns),
+	}
+	if m.ColumnarView {
+		vs.ViewMode = ViewModeColumns
+	}
+	return vs
+}
+
+// ApplyViewState restores a saved view layout. Empty fields keep the
+// model's settings.
+func (m *Model) ApplyViewState(vs ViewState) {
+	switch vs.ViewMode {
+	case ViewModeColumns:
+		m.ColumnarView = true
+	case ViewModeTable:
+		m.ColumnarView = false
+	}
+	if len(vs.ColumnOrder) > 0 {
+		m.ColumnOrder = slices.Clone(vs.ColumnOrder)
+	}
+	if len(vs.HiddenColumns) > 0 {
+		m.HiddenColumns = slices.Clone(vs.HiddenColumns)
+	}
+}
+
+// ViewState returns the view layout part of l.
+func (l LayoutConfig) ViewState() ViewState {
+	return ViewState{
+		ViewMode:      l.ViewMode,
+		ColumnOrder:   l.ColumnOrder,
+		HiddenColumns: l.HiddenColumns,
+		Sort:          l.Sort,
+	}
+}
+
+// CurrentLayout returns the current expression and view layout as a named
+// layout. The expression is left empty at the root; Sort and KeyColWidth are
+// left to the caller, which chose them.
+func (m *Model) CurrentLayout() LayoutConfig {
+	vs := m.CurrentViewState()
+	expr := strings.TrimSpace(m.PathInput.Value())
+	if expr == formatPathForDisplay("") {
+		expr = ""
+	}
+	return LayoutConfig{
+		Expression:    expr,
+		ViewMode:      vs.ViewMode,
+		ColumnOrder:   vs.ColumnOrder,
+		HiddenColumns: vs.HiddenColumns,
+	}
+}
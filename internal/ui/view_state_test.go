@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDir(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	home := filepath.Join("home", "u")
+
+	assert.Equal(t, filepath.Join("xdg", "kvx"),
+		stateDir("windows", env(map[string]string{"XDG_STATE_HOME": "xdg", "LOCALAPPDATA": "local"}), home))
+	assert.Equal(t, filepath.Join("local", "kvx"),
+		stateDir("windows", env(map[string]string{"LOCALAPPDATA": "local"}), home))
+	assert.Equal(t, filepath.Join(home, ".local", "state", "kvx"),
+		stateDir("linux", env(map[string]string{"LOCALAPPDATA": "local"}), home))
+	assert.Empty(t, stateDir("linux", env(nil), ""))
+}
+
+func TestSaveAndLoadViewState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	_, ok := LoadViewState("file:package.json")
+	assert.False(t, ok)
+
+	want := ViewState{ViewMode: ViewModeColumns, ColumnOrder: []string{"name", "tier"}, HiddenColumns: []string{"id"}, Sort: "descending"}
+	require.NoError(t, SaveViewState("file:package.json", want))
+	require.NoError(t, SaveViewState("schema:https://example.com/pods", ViewState{ViewMode: ViewModeTable}))
+
+	got, ok := LoadViewState("file:package.json")
+	require.True(t, ok)
+	assert.Equal(t, want, got)
+	got, ok = LoadViewState("schema:https://example.com/pods")
+	require.True(t, ok)
+	assert.Equal(t, ViewModeTable, got.ViewMode)
+
+	// A damaged file is replaced on the next save.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kvx", viewStateFile), []byte("{"), 0o600))
+	_, ok = LoadViewState("file:package.json")
+	assert.False(t, ok)
+	require.NoError(t, SaveViewState("file:a.yaml", ViewState{ViewMode: ViewModeColumns}))
+	_, ok = LoadViewState("file:a.yaml")
+	assert.True(t, ok)
+}
+
+func TestModelViewState(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	assert.Equal(t, ViewState{ViewMode: ViewModeTable}, m.CurrentViewState())
+
+	m.ApplyViewState(ViewState{ViewMode: ViewModeColumns, ColumnOrder: []string{"tier"}, HiddenColumns: []string{"status"}})
+	assert.True(t, m.ColumnarView)
+	assert.Equal(t, []string{"tier", "name"}, m.columnarFields())
+	assert.Equal(t, ViewState{ViewMode: ViewModeColumns, ColumnOrder: []string{"tier"}, HiddenColumns: []string{"status"}}, m.CurrentViewState())
+
+	// Empty fields keep the model's settings.
+	m.ApplyViewState(ViewState{})
+	assert.True(t, m.ColumnarView)
+	assert.Equal(t, []string{"tier"}, m.ColumnOrder)
+}
+
+func TestModelCurrentLayout(t *testing.T) {
+	m := testColumnarModel(KeyModeVim)
+	m.ApplyViewState(LayoutConfig{ViewMode: ViewModeColumns, HiddenColumns: []string{"status"}, Sort: "ascending"}.ViewState())
+	m.PathInput.SetValue("_")
+	assert.Equal(t, LayoutConfig{ViewMode: ViewModeColumns, HiddenColumns: []string{"status"}}, m.CurrentLayout(), "the root has no expression")
+
+	m.PathInput.SetValue(" _.items ")
+	assert.Equal(t, "_.items", m.CurrentLayout().Expression)
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchRetryInterval is how soon a change of the watched file is tried
+// again while a refresh is blocked.
+const watchRetryInterval = 500 * time.Millisecond
+
+// fileStamp identifies a version of a file by its modification time and
+// size; a missing file has the zero stamp.
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func statStamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{mod: info.ModTime(), size: info.Size()}
+}
+
+// fileWatcher reports changes of one file through fsnotify. It watches the
+// file's directory rather than the file, so a file that an editor replaces
+// by renaming a new one over it is still watched afterwards.
+type fileWatcher struct {
+	path    string
+	w       *fsnotify.Watcher
+	changed chan struct{} // Holds at most one pending signal; closed with the watcher
+}
+
+func newFileWatcher(path string) (*fileWatcher, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(filepath.Dir(abs)); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	fw := &fileWatcher{path: abs, w: w, changed: make(chan struct{}, 1)}
+	go fw.run()
+	return fw, nil
+}
+
+// run turns the events of the file into change signals until the watcher
+// is closed. Watcher errors, such as a dropped event, count as a change, as
+// the stamp check that follows ignores versions already shown.
+func (fw *fileWatcher) run() {
+	defer close(fw.changed)
+	for {
+		select {
+		case ev, ok := <-fw.w.Events:
+			if !ok {
+				return
+			}
+			if ev.Name != fw.path || ev.Op == fsnotify.Chmod {
+				continue
+			}
+		case _, ok := <-fw.w.Errors:
+			if !ok {
+				return
+			}
+		}
+		select {
+		case fw.changed <- struct{}{}:
+		default: // A signal is already pending
+		}
+	}
+}
+
+// Close stops watching.
+func (fw *fileWatcher) Close() error {
+	return fw.w.Close()
+}
+
+// watchEventMsg carries the stamp of the watched file after it changed.
+type watchEventMsg struct {
+	stamp fileStamp
+}
+
+// watchedMsg carries the data read again after the watched file changed.
+type watchedMsg struct {
+	root  interface{}
+	stamp fileStamp
+	err   error
+}
+
+// waitForWatch returns a tea.Cmd that blocks until the watched file
+// changes. Stat runs off the update loop, like a refresh read.
+func waitForWatch(fw *fileWatcher) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-fw.changed; !ok {
+			return nil
+		}
+		return watchEventMsg{stamp: statStamp(fw.path)}
+	}
+}
+
+// startWatch records the version of the watched file the view shows and
+// starts watching it. A file that cannot be watched is reported in the
+// status bar and the view stays as it is.
+func (m *Model) startWatch() tea.Cmd {
+	if m.WatchPath == "" || m.WatchLoad == nil {
+		return nil
+	}
+	m.watchStamp = statStamp(m.WatchPath)
+	fw, err := newFileWatcher(m.WatchPath)
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("watch: %v", err)
+		m.StatusType = "error"
+		return nil
+	}
+	m.watcher = fw
+	return waitForWatch(fw)
+}
+
+// stopWatch stops watching the file, once the viewer exits.
+func (m *Model) stopWatch() {
+	if m.watcher != nil {
+		_ = m.watcher.Close()
+		m.watcher = nil
+	}
+}
+
+// handleWatchEvent reads the data again when the watched file changed since
+// the view last read it. While the file is missing (e.g. an editor replacing
+// it) it waits for the next change; while a refresh is blocked it tries
+// again shortly.
+func (m *Model) handleWatchEvent(msg watchEventMsg) tea.Cmd {
+	if m.watcher == nil || m.WatchLoad == nil {
+		return nil
+	}
+	if msg.stamp == m.watchStamp || msg.stamp == (fileStamp{}) {
+		return waitForWatch(m.watcher)
+	}
+	if m.refreshBlocked() {
+		path := m.watcher.path
+		return tea.Tick(watchRetryInterval, func(time.Time) tea.Msg {
+			return watchEventMsg{stamp: statStamp(path)}
+		})
+	}
+	load := m.WatchLoad
+	return func() tea.Msg {
+		root, err := load()
+		return watchedMsg{root: root, stamp: msg.stamp, err: err}
+	}
+}
+
+// handleWatched shows the data read after the watched file changed, keeping
+// the path and selected row like a refresh, and resumes watching. A failed
+// read, such as a half-saved file, keeps the data shown and says why in the
+// status bar; the next save is read again.
+func (m *Model) handleWatched(msg watchedMsg) tea.Cmd {
+	if m.watcher == nil || m.WatchLoad == nil {
+		return nil
+	}
+	switch {
+	case msg.err != nil:
+		m.watchStamp = msg.stamp
+		m.ErrMsg = fmt.Sprintf("watch: %v", msg.err)
+		m.StatusType = "error"
+		return waitForWatch(m.watcher)
+	case m.refreshBlocked():
+		// Blocked since the read started: try again once unblocked.
+		return m.handleWatchEvent(watchEventMsg{stamp: msg.stamp})
+	}
+	m.watchStamp = msg.stamp
+	return tea.Batch(m.applyRefresh(msg.root), waitForWatch(m.watcher))
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func watchModel(t *testing.T, root interface{}) (*Model, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	m := InitialModel(root)
+	m.Root = root
+	m.WatchPath = path
+	m.WatchLoad = func() (interface{}, error) { return nil, errors.New("unused") }
+	require.NotNil(t, m.startWatch())
+	t.Cleanup(m.stopWatch)
+	return &m, path
+}
+
+// runWithin runs cmd and returns its message, failing when it takes longer
+// than a few seconds.
+func runWithin(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+		return nil
+	}
+}
+
+func TestFileWatcher(t *testing.T) {
+	m, path := watchModel(t, map[string]interface{}{"n": 1})
+
+	// A save may be reported as several events; wait for the one that
+	// shows the whole file.
+	waitForSize := func(size int64) {
+		t.Helper()
+		for {
+			ev, ok := runWithin(t, waitForWatch(m.watcher)).(watchEventMsg)
+			require.True(t, ok)
+			if ev.stamp.size == size {
+				return
+			}
+		}
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"n": 2}`), 0o600))
+	waitForSize(8)
+
+	// Editors often save by renaming a new file over the old one.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(`{"n": 33}`), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+	waitForSize(9)
+
+	m.stopWatch()
+	assert.Nil(t, m.handleWatchEvent(watchEventMsg{stamp: statStamp(path)}))
+}
+
+func TestHandleWatchEvent(t *testing.T) {
+	m, path := watchModel(t, map[string]interface{}{"n": 1})
+	m.WatchLoad = func() (interface{}, error) { return map[string]interface{}{"n": 2}, nil }
+
+	cmd := m.handleWatchEvent(watchEventMsg{stamp: m.watchStamp})
+	require.NotNil(t, cmd, "an unchanged file is waited on again")
+
+	changed := fileStamp{mod: m.watchStamp.mod.Add(time.Second), size: 2}
+	m.InputFocused = true
+	cmd = m.handleWatchEvent(watchEventMsg{stamp: changed})
+	require.NotNil(t, cmd, "tries again shortly")
+	_, read := cmd().(watchedMsg)
+	assert.False(t, read, "does not read while an expression is being typed")
+	m.InputFocused = false
+
+	cmd = m.handleWatchEvent(watchEventMsg{stamp: changed})
+	require.NotNil(t, cmd)
+	assert.Equal(t, watchedMsg{root: map[string]interface{}{"n": 2}, stamp: changed}, cmd())
+
+	assert.Equal(t, statStamp(path), m.watchStamp, "the stamp moves only once the data is shown")
+}
+
+func TestHandleWatchEvent_MissingFile(t *testing.T) {
+	m, _ := watchModel(t, map[string]interface{}{"n": 1})
+	m.WatchLoad = func() (interface{}, error) {
+		t.Fatal("a missing file is not read")
+		return nil, nil
+	}
+	require.NotNil(t, m.handleWatchEvent(watchEventMsg{}))
+}
+
+func TestStartWatch_Unwatchable(t *testing.T) {
+	m := InitialModel(map[string]interface{}{})
+	m.WatchPath = filepath.Join(t.TempDir(), "missing-dir", "data.json")
+	m.WatchLoad = func() (interface{}, error) { return nil, nil }
+	assert.Nil(t, m.startWatch())
+	assert.Contains(t, m.ErrMsg, "watch: ")
+}
+
+func TestHandleWatched_KeepsPathAndCursor(t *testing.T) {
+	m, _ := watchModel(t, map[string]interface{}{"items": []interface{}{"a", "b", "c"}})
+	m.NavigateTo(m.Root.(map[string]interface{})["items"], "_.items")
+	m.Tbl.SetCursor(2)
+
+	stamp := fileStamp{mod: time.Unix(1, 0), size: 1}
+	assert.NotNil(t, m.handleWatched(watchedMsg{root: map[string]interface{}{"items": []interface{}{"a", "b", "c", "d"}}, stamp: stamp}))
+	assert.Equal(t, "_.items", m.Path)
+	assert.Equal(t, []interface{}{"a", "b", "c", "d"}, m.Node)
+	assert.Equal(t, 2, m.Tbl.Cursor())
+	assert.Equal(t, stamp, m.watchStamp)
+}
+
+func TestHandleWatched_Error(t *testing.T) {
+	m, _ := watchModel(t, map[string]interface{}{"n": 1})
+	stamp := fileStamp{mod: time.Unix(1, 0), size: 1}
+	require.NotNil(t, m.handleWatched(watchedMsg{stamp: stamp, err: errors.New("unexpected end of JSON input")}))
+	assert.Equal(t, "watch: unexpected end of JSON input", m.ErrMsg)
+	assert.Equal(t, map[string]interface{}{"n": 1}, m.Root, "the data shown is kept")
+	assert.Equal(t, stamp, m.watchStamp, "the broken version is not read again")
+}
+
+func TestStatStamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	assert.Equal(t, fileStamp{}, statStamp(path))
+	require.NoError(t, os.WriteFile(path, []byte("abc"), 0o600))
+	assert.Equal(t, int64(3), statStamp(path).size)
+}
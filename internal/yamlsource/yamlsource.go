@@ -0,0 +1,192 @@
+// Package yamlsource keeps the parsed yaml.Node tree of YAML input so that a
+// value taken from it can be written back out with its original comments,
+// anchors, key order, and quoting style. A value is found in the source by
+// the navigation path it was read from; values an expression computes have
+// no path and so no node.
+package yamlsource
+
+import (
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source holds the document nodes of one loaded YAML input. A nil Source
+// holds nothing.
+type Source struct {
+	docs []*yaml.Node
+}
+
+// NewSource returns an empty Source.
+func NewSource() *Source {
+	return &Source{}
+}
+
+// Add appends a parsed document node.
+func (s *Source) Add(n *yaml.Node) {
+	if s == nil || n == nil {
+		return
+	}
+	s.docs = append(s.docs, n)
+}
+
+// Lookup returns the source nodes at path, given as the steps a navigation
+// path is made of: mapping keys, and decimal indexes into sequences. An empty
+// path yields every document. For multi-document input the first step
+// selects the document, as it indexes the slice the documents load into.
+// The returned nodes are shared and must not be modified; see Detach.
+func (s *Source) Lookup(path []string) ([]*yaml.Node, bool) {
+	if s == nil || len(s.docs) == 0 {
+		return nil, false
+	}
+	if len(path) == 0 {
+		return s.docs, true
+	}
+	cur := s.docs[0]
+	if len(s.docs) > 1 {
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= len(s.docs) {
+			return nil, false
+		}
+		cur, path = s.docs[i], path[1:]
+	}
+	for _, step := range path {
+		if cur = child(cur, step); cur == nil {
+			return nil, false
+		}
+	}
+	return []*yaml.Node{cur}, true
+}
+
+// child returns the node step leads to from n, or nil.
+func child(n *yaml.Node, step string) *yaml.Node {
+	n = resolve(n)
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		return mappingValue(n, step)
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(step)
+		if err != nil || i < 0 || i >= len(n.Content) {
+			return nil
+		}
+		return n.Content[i]
+	}
+	return nil
+}
+
+// mappingValue returns the value of key in mapping n. Keys written in n take
+// precedence over merged ones, and earlier merge sources over later ones, as
+// when decoding.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if k.Tag == "!!merge" {
+			merges = append(merges, n.Content[i+1])
+			continue
+		}
+		if k.Value == key {
+			return n.Content[i+1]
+		}
+	}
+	for _, m := range merges {
+		m = resolve(m)
+		if m == nil {
+			continue
+		}
+		sources := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			sources = m.Content
+		}
+		for _, src := range sources {
+			if src = resolve(src); src != nil && src.Kind == yaml.MappingNode {
+				if v := mappingValue(src, key); v != nil {
+					return v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows document nodes and aliases to the node holding the value.
+func resolve(n *yaml.Node) *yaml.Node {
+	for n != nil {
+		switch {
+		case n.Kind == yaml.DocumentNode && len(n.Content) > 0:
+			n = n.Content[0]
+		case n.Kind == yaml.AliasNode:
+			n = n.Alias
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Detach returns a deep copy of n that can be encoded on its own. Aliases
+// whose anchor is defined outside n are replaced by a copy of the anchored
+// value, since the anchor would not be part of the output, and anchors that
+// nothing in n refers to are dropped.
+func Detach(n *yaml.Node) *yaml.Node {
+	defined := make(map[*yaml.Node]bool)
+	collectAnchors(n, defined)
+	used := make(map[*yaml.Node]bool)
+	collectAliases(n, defined, used)
+	return clone(n, used)
+}
+
+func collectAnchors(n *yaml.Node, defined map[*yaml.Node]bool) {
+	if n == nil || n.Kind == yaml.AliasNode {
+		return
+	}
+	if n.Anchor != "" {
+		defined[n] = true
+	}
+	for _, c := range n.Content {
+		collectAnchors(c, defined)
+	}
+}
+
+func collectAliases(n *yaml.Node, defined, used map[*yaml.Node]bool) {
+	if n == nil {
+		return
+	}
+	if n.Kind == yaml.AliasNode && defined[n.Alias] {
+		used[n.Alias] = true
+	}
+	for _, c := range n.Content {
+		collectAliases(c, defined, used)
+	}
+}
+
+// clone copies n, keeping only the anchors in keep. Aliases to any other
+// anchor are replaced by a copy of the anchored value.
+func clone(n *yaml.Node, keep map[*yaml.Node]bool) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yaml.AliasNode && n.Alias != nil && !keep[n.Alias] {
+		inlined := clone(n.Alias, nil)
+		inlined.LineComment = n.LineComment
+		return inlined
+	}
+	cp := *n
+	if !keep[n] {
+		cp.Anchor = ""
+	}
+	if n.Tag == "!!merge" {
+		// The encoder writes an explicit "!!merge" tag unless it is implied.
+		cp.Tag = ""
+	}
+	if len(n.Content) > 0 {
+		cp.Content = make([]*yaml.Node, len(n.Content))
+		for i, c := range n.Content {
+			cp.Content[i] = clone(c, keep)
+		}
+	}
+	return &cp
+}
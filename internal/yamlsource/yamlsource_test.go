@@ -0,0 +1,103 @@
+package yamlsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const sample = `# config
+defaults: &defaults
+  timeout: 30 # seconds
+services:
+  api:
+    <<: *defaults
+    image: 'nginx'
+  worker:
+    settings: *defaults
+`
+
+func load(t *testing.T, docs ...string) *Source {
+	t.Helper()
+	src := NewSource()
+	for _, d := range docs {
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(d), &node))
+		src.Add(&node)
+	}
+	return src
+}
+
+func encode(t *testing.T, n *yaml.Node) string {
+	t.Helper()
+	out, err := yaml.Marshal(Detach(n))
+	require.NoError(t, err)
+	return string(out)
+}
+
+func lookup(t *testing.T, src *Source, path ...string) *yaml.Node {
+	t.Helper()
+	nodes, ok := src.Lookup(path)
+	require.True(t, ok)
+	require.Len(t, nodes, 1)
+	return nodes[0]
+}
+
+func TestLookup_Document(t *testing.T) {
+	n := lookup(t, load(t, sample))
+	assert.Equal(t, yaml.DocumentNode, n.Kind)
+	assert.Contains(t, encode(t, n), "# config")
+}
+
+func TestLookup_SubtreeInlinesOutsideAliases(t *testing.T) {
+	n := lookup(t, load(t, sample), "services", "worker")
+	assert.Equal(t, "settings:\n    timeout: 30 # seconds\n", encode(t, n))
+}
+
+func TestLookup_MergeKeysAndQuoting(t *testing.T) {
+	src := load(t, sample)
+	out := encode(t, lookup(t, src, "services", "api"))
+	assert.Contains(t, out, "<<:\n")
+	assert.NotContains(t, out, "!!merge")
+	assert.Contains(t, out, "image: 'nginx'")
+
+	// Merged keys are found through the alias they come from.
+	assert.Equal(t, "30", lookup(t, src, "services", "api", "timeout").Value)
+}
+
+func TestLookup_DuplicateSubtrees(t *testing.T) {
+	src := load(t, "a: {x: 1} # A\nb: {x: 1} # B\n")
+	assert.Equal(t, "# B", lookup(t, src, "b").LineComment)
+	assert.Equal(t, "# A", lookup(t, src, "a").LineComment)
+}
+
+func TestLookup_Sequences(t *testing.T) {
+	src := load(t, "items:\n  - name: one\n  - name: two # second\n")
+	assert.Equal(t, "# second", lookup(t, src, "items", "1", "name").LineComment)
+
+	for _, path := range [][]string{{"items", "2"}, {"items", "x"}, {"missing"}, {"items", "0", "name", "deeper"}} {
+		_, ok := src.Lookup(path)
+		assert.False(t, ok, "%v", path)
+	}
+}
+
+func TestLookup_MultiDocument(t *testing.T) {
+	src := load(t, "a: 1 # one\n", "b: 2\n")
+
+	nodes, ok := src.Lookup(nil)
+	require.True(t, ok)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, "# one", lookup(t, src, "0", "a").LineComment)
+	assert.Equal(t, "2", lookup(t, src, "1", "b").Value)
+	_, ok = src.Lookup([]string{"a"})
+	assert.False(t, ok, "the first step selects the document")
+}
+
+func TestLookup_NilSource(t *testing.T) {
+	var src *Source
+	src.Add(&yaml.Node{})
+	_, ok := src.Lookup(nil)
+	assert.False(t, ok)
+}
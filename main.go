@@ -8,7 +8,16 @@ import (
 	"github.com/oakwood-commons/kvx/pkg/logger"
 )
 
+// Set at release time by goreleaser through -ldflags -X.
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
 func main() {
+	cmd.SetBuildInfo(Version, Commit, BuildTime)
+
 	exitCode := 0
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
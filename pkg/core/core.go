@@ -2,10 +2,12 @@ package core
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/oakwood-commons/kvx/internal/cel"
 	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
 	"github.com/oakwood-commons/kvx/internal/navigator"
 	"github.com/oakwood-commons/kvx/pkg/loader"
 )
@@ -41,14 +43,33 @@ const (
 	SortNone       SortOrder = "none"
 	SortAscending  SortOrder = "ascending"
 	SortDescending SortOrder = "descending"
+	// SortInsertion keeps the key order of the source document. Documents
+	// must be loaded with the Engine's LoadRoot, LoadRootBytes, or LoadFile.
+	SortInsertion SortOrder = "insertion"
+	// SortSchema orders keys listed with WithSchemaOrder first, then the
+	// remaining keys alphabetically.
+	SortSchema SortOrder = "schema"
+	// SortNatural sorts keys alphabetically with runs of digits compared
+	// by value, so item2 comes before item10.
+	SortNatural SortOrder = "natural"
 )
 
 // Engine provides a minimal shared API for loading, evaluating, and rendering data.
+//
+// An Engine is safe for concurrent use by multiple goroutines once New
+// returns, as long as its fields are not changed afterwards; injected
+// Evaluators, Navigators, and Formatters must be safe for concurrent use too.
+// Engines share one CEL environment, so creating one per request is cheap,
+// but a server can equally share a single Engine, e.g. Default.
 type Engine struct {
-	Evaluator Evaluator
-	Navigator Navigator
-	Formatter Formatter
-	SortOrder SortOrder
+	Evaluator   Evaluator
+	Navigator   Navigator
+	Formatter   Formatter
+	SortOrder   SortOrder
+	SchemaOrder []string
+
+	// docs holds the key order of each document loaded by the Engine.
+	docs keyorder.Documents
 }
 
 // Option configures the Engine.
@@ -75,13 +96,20 @@ func WithFormatter(f Formatter) Option {
 	}
 }
 
-// WithSortOrder sets the row sort order.
+// WithSortOrder sets the key order used for rows and rendering.
 func WithSortOrder(order SortOrder) Option {
 	return func(c *Engine) {
 		c.SortOrder = order
 	}
 }
 
+// WithSchemaOrder sets the key order used by SortSchema.
+func WithSchemaOrder(keys []string) Option {
+	return func(c *Engine) {
+		c.SchemaOrder = append([]string(nil), keys...)
+	}
+}
+
 // New creates an Engine with defaults.
 func New(opts ...Option) (*Engine, error) {
 	engine := &Engine{
@@ -106,6 +134,20 @@ func New(opts ...Option) (*Engine, error) {
 	return engine, nil
 }
 
+// defaultEngine is the Engine returned by Default, created on first use.
+var defaultEngine = sync.OnceValues(func() (*Engine, error) { return New() })
+
+// Default returns a shared Engine with the default settings, created with New
+// on first use. It suits servers and tools that evaluate expressions from many
+// goroutines without configuring an Engine of their own; the same Engine and
+// error are returned on every call.
+//
+//	engine, err := core.Default()
+//	out, err := engine.Evaluate("_.items.size()", root)
+func Default() (*Engine, error) {
+	return defaultEngine()
+}
+
 // LoadRoot parses input into a single root node; multi-doc inputs return a slice.
 func LoadRoot(input string) (interface{}, error) {
 	return loader.LoadRoot(input)
@@ -139,12 +181,65 @@ func LoadObject(value interface{}) (interface{}, error) {
 	return loader.LoadObject(value)
 }
 
+// LoadRoot parses input like the package-level LoadRoot. With SortInsertion
+// the Engine also keeps the key order of the document, under the returned
+// root, until Forget is called with it.
+func (e *Engine) LoadRoot(input string) (interface{}, error) {
+	return e.loadDocument(loader.LoadDocument(input, e.documentOptions()))
+}
+
+// LoadRootBytes is like LoadRoot for input bytes.
+func (e *Engine) LoadRootBytes(data []byte) (interface{}, error) {
+	return e.LoadRoot(string(data))
+}
+
+// LoadFile reads and parses a file like the package-level LoadFile, keeping
+// its key order like LoadRoot.
+func (e *Engine) LoadFile(path string) (interface{}, error) {
+	return e.loadDocument(loader.LoadFileDocument(path, e.documentOptions()))
+}
+
+func (e *Engine) documentOptions() loader.DocumentOptions {
+	return loader.DocumentOptions{KeyOrder: e.SortOrder == SortInsertion}
+}
+
+func (e *Engine) loadDocument(doc *loader.Document, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	e.docs.Add(doc.Root, doc.Order)
+	return doc.Root, nil
+}
+
+// Forget drops the key order the Engine keeps for a root it loaded. A
+// long-lived Engine that loads many documents should call it once a document
+// is no longer rendered.
+func (e *Engine) Forget(root interface{}) {
+	e.docs.Remove(root)
+}
+
+// sorter returns the key order of the Engine's Rows, RenderTable, and
+// Stringify calls.
+func (e *Engine) sorter() keyorder.Sorter {
+	return keyorder.Sorter{
+		Mode:   keyorder.Mode(toNavigatorSort(e.SortOrder)),
+		Schema: e.SchemaOrder,
+		Docs:   &e.docs,
+	}
+}
+
 // Evaluate runs the evaluator against the provided root node.
+// Recognized failures are returned as *ErrParse, *ErrNoSuchKey, or
+// *ErrTypeMismatch (use errors.As); their Error text is the evaluator's own.
 func (e *Engine) Evaluate(expr string, root interface{}) (interface{}, error) {
 	if e == nil || e.Evaluator == nil {
 		return nil, fmt.Errorf("evaluator is not configured")
 	}
-	return e.Evaluator.Evaluate(expr, root)
+	result, err := e.Evaluator.Evaluate(expr, root)
+	if err != nil {
+		return nil, e.classifyError(expr, root, err)
+	}
+	return result, nil
 }
 
 // EvaluateWhere filters list data by applying a per-item boolean expression.
@@ -157,45 +252,65 @@ func (e *Engine) EvaluateWhere(expr string, root interface{}) ([]interface{}, er
 	if !ok {
 		return nil, fmt.Errorf("evaluator does not support where filtering")
 	}
-	return weval.EvaluateWhere(expr, root)
+	result, err := weval.EvaluateWhere(expr, root)
+	if err != nil {
+		return nil, e.classifyError(expr, root, err)
+	}
+	return result, nil
 }
 
 // NodeAtPath navigates a path into the root using navigator rules.
 func (e *Engine) NodeAtPath(root interface{}, path string) (interface{}, error) {
-	e.ensureNavigator()
-	if e == nil || e.Navigator == nil {
+	nav := e.navigator()
+	if nav == nil {
 		return nil, fmt.Errorf("navigator is not configured")
 	}
-	return e.Navigator.NodeAtPath(root, path)
+	node, err := nav.NodeAtPath(root, path)
+	if err != nil {
+		return nil, e.classifyError(path, root, err)
+	}
+	return node, nil
 }
 
-// Rows converts a node into table rows, honoring the Engine sort order.
+// Rows converts a node into table rows, honoring the Engine sort order. An
+// injected Navigator is given the order through SetSortOrder.
 func (e *Engine) Rows(node interface{}) [][]string {
-	e.ensureNavigator()
-	if e == nil || e.Navigator == nil {
+	nav := e.navigator()
+	if nav == nil {
 		return nil
 	}
-	prev := e.Navigator.SetSortOrder(e.SortOrder)
-	defer e.Navigator.SetSortOrder(prev)
-	return e.Navigator.NodeToRows(node)
+	if _, ok := nav.(defaultNavigator); ok {
+		return navigator.NodeToRowsSorted(node, e.sorter())
+	}
+	prev := nav.SetSortOrder(e.SortOrder)
+	defer nav.SetSortOrder(prev)
+	return nav.NodeToRows(node)
 }
 
-// RenderTable renders a two-column table for the node.
+// RenderTable renders a two-column table for the node, honoring the Engine
+// sort order.
 func (e *Engine) RenderTable(node interface{}, noColor bool, keyColWidth, valueColWidth int, columnOrder []string) string {
-	e.ensureFormatter()
-	if e == nil || e.Formatter == nil {
+	f := e.formatter()
+	if f == nil {
 		return ""
 	}
-	return e.Formatter.RenderTable(node, noColor, keyColWidth, valueColWidth, columnOrder)
+	if _, ok := f.(defaultFormatter); ok {
+		return formatter.RenderTableSorted(node, noColor, keyColWidth, valueColWidth, columnOrder, e.sorter())
+	}
+	return f.RenderTable(node, noColor, keyColWidth, valueColWidth, columnOrder)
 }
 
-// Stringify renders a node into a display string.
+// Stringify renders a node into a display string, honoring the Engine sort
+// order.
 func (e *Engine) Stringify(node interface{}) string {
-	e.ensureFormatter()
-	if e == nil || e.Formatter == nil {
+	f := e.formatter()
+	if f == nil {
 		return ""
 	}
-	return e.Formatter.Stringify(node)
+	if _, ok := f.(defaultFormatter); ok {
+		return formatter.StringifySorted(node, e.sorter())
+	}
+	return f.Stringify(node)
 }
 
 func toNavigatorSort(order SortOrder) navigator.SortOrder {
@@ -204,6 +319,12 @@ func toNavigatorSort(order SortOrder) navigator.SortOrder {
 		return navigator.SortAscending
 	case SortDescending:
 		return navigator.SortDescending
+	case SortInsertion:
+		return navigator.SortInsertion
+	case SortSchema:
+		return navigator.SortSchema
+	case SortNatural:
+		return navigator.SortNatural
 	case SortNone:
 		return navigator.SortNone
 	default:
@@ -217,6 +338,12 @@ func fromNavigatorSort(order navigator.SortOrder) SortOrder {
 		return SortAscending
 	case navigator.SortDescending:
 		return SortDescending
+	case navigator.SortInsertion:
+		return SortInsertion
+	case navigator.SortSchema:
+		return SortSchema
+	case navigator.SortNatural:
+		return SortNatural
 	case navigator.SortNone:
 		return SortNone
 	default:
@@ -249,20 +376,26 @@ func (defaultFormatter) Stringify(node interface{}) string {
 	return formatter.Stringify(node)
 }
 
-func (e *Engine) ensureNavigator() {
-	if e == nil {
-		return
-	}
-	if e.Navigator == nil {
-		e.Navigator = defaultNavigator{}
+// navigator returns the Engine's Navigator, or the default one for an Engine
+// not created by New. It never modifies the Engine, so that calls stay safe
+// for concurrent use.
+func (e *Engine) navigator() Navigator {
+	switch {
+	case e == nil:
+		return nil
+	case e.Navigator == nil:
+		return defaultNavigator{}
 	}
+	return e.Navigator
 }
 
-func (e *Engine) ensureFormatter() {
-	if e == nil {
-		return
-	}
-	if e.Formatter == nil {
-		e.Formatter = defaultFormatter{}
+// formatter returns the Engine's Formatter like navigator.
+func (e *Engine) formatter() Formatter {
+	switch {
+	case e == nil:
+		return nil
+	case e.Formatter == nil:
+		return defaultFormatter{}
 	}
+	return e.Formatter
 }
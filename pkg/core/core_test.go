@@ -2,9 +2,12 @@ package core
 
 import (
 	"os"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/go-logr/logr"
+	"github.com/oakwood-commons/kvx/internal/navigator"
 )
 
 func TestEngineEvaluate(t *testing.T) {
@@ -170,6 +173,9 @@ func TestToNavigatorSort(t *testing.T) {
 	}{
 		{SortAscending},
 		{SortDescending},
+		{SortInsertion},
+		{SortSchema},
+		{SortNatural},
 		{SortNone},
 		{SortOrder("invalid")},
 	}
@@ -178,7 +184,7 @@ func TestToNavigatorSort(t *testing.T) {
 			// Roundtrip: toNavigatorSort then fromNavigatorSort should preserve known values
 			nav := toNavigatorSort(tt.input)
 			back := fromNavigatorSort(nav)
-			if tt.input == SortAscending || tt.input == SortDescending || tt.input == SortNone {
+			if tt.input != SortOrder("invalid") {
 				if back != tt.input {
 					t.Fatalf("roundtrip failed: %q -> %q", tt.input, back)
 				}
@@ -187,28 +193,79 @@ func TestToNavigatorSort(t *testing.T) {
 	}
 }
 
+func TestEngineRowsInsertionOrder(t *testing.T) {
+	engine, err := New(WithSortOrder(SortInsertion))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root, err := engine.LoadRoot("zeta: 1\nalpha: 2\nmid: 3\n")
+	if err != nil {
+		t.Fatalf("LoadRoot() error: %v", err)
+	}
+	rows := engine.Rows(root)
+	if len(rows) != 3 || rows[0][0] != "zeta" || rows[1][0] != "alpha" || rows[2][0] != "mid" {
+		t.Fatalf("expected document order [zeta alpha mid], got %v", rows)
+	}
+}
+
+func TestEnginesKeepTheirOwnKeyOrder(t *testing.T) {
+	insertion, err := New(WithSortOrder(SortInsertion))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	schema, err := New(WithSortOrder(SortSchema), WithSchemaOrder([]string{"mid"}))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root, err := insertion.LoadRoot(`{"zeta": 1, "alpha": 2, "mid": 3}`)
+	if err != nil {
+		t.Fatalf("LoadRoot() error: %v", err)
+	}
+
+	firstKeys := func(rows [][]string) []string {
+		keys := make([]string, len(rows))
+		for i, r := range rows {
+			keys[i] = r[0]
+		}
+		return keys
+	}
+	if got := firstKeys(schema.Rows(root)); !reflect.DeepEqual(got, []string{"mid", "alpha", "zeta"}) {
+		t.Fatalf("schema engine rows = %v", got)
+	}
+	if got := firstKeys(insertion.Rows(root)); !reflect.DeepEqual(got, []string{"zeta", "alpha", "mid"}) {
+		t.Fatalf("insertion engine rows = %v", got)
+	}
+	if got := insertion.Stringify(root); got != `{"zeta":1,"alpha":2,"mid":3}` {
+		t.Fatalf("insertion engine Stringify = %s", got)
+	}
+	// Neither engine changes the order used outside of its own calls.
+	if got := navigator.OrderedKeys(root.(map[string]interface{})); !reflect.DeepEqual(got, []string{"alpha", "mid", "zeta"}) {
+		t.Fatalf("global order changed: %v", got)
+	}
+}
+
 func TestEngineRowsNilNavigator(t *testing.T) {
 	engine := &Engine{Navigator: nil}
 	rows := engine.Rows(map[string]interface{}{"a": 1})
-	// ensureNavigator fills it in, so we should get rows
+	// The default navigator stands in, so we should get rows
 	if rows == nil {
-		t.Fatal("expected non-nil rows after ensureNavigator")
+		t.Fatal("expected non-nil rows from the default navigator")
 	}
 }
 
 func TestEngineRenderTableNil(t *testing.T) {
 	engine := &Engine{}
 	result := engine.RenderTable(map[string]interface{}{"a": 1}, true, 20, 40, nil)
-	// ensureFormatter fills it in
+	// The default formatter stands in
 	if result == "" {
-		t.Fatal("expected non-empty after ensureFormatter")
+		t.Fatal("expected non-empty from the default formatter")
 	}
 }
 
 func TestEngineStringifyNil(t *testing.T) {
 	engine := &Engine{}
 	result := engine.Stringify("test")
-	// ensureFormatter fills it in
+	// The default formatter stands in
 	if result != "test" {
 		t.Fatalf("Stringify = %q, want test", result)
 	}
@@ -250,3 +307,132 @@ func TestLoadRootBytesWithLogger(t *testing.T) {
 func testLogger() logr.Logger {
 	return logr.Discard()
 }
+
+func TestEngineKeepsKeyOrderPerDocument(t *testing.T) {
+	engine, err := New(WithSortOrder(SortInsertion))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	a, err := engine.LoadRoot(`{"z": 1, "a": 2}`)
+	if err != nil {
+		t.Fatalf("LoadRoot error: %v", err)
+	}
+	b, err := engine.LoadRoot(`{"a": 1, "z": 2}`)
+	if err != nil {
+		t.Fatalf("LoadRoot error: %v", err)
+	}
+	if got := engine.Rows(a); !reflect.DeepEqual(got, [][]string{{"z", "1"}, {"a", "2"}}) {
+		t.Fatalf("Rows(a) = %v after loading b", got)
+	}
+	if got := engine.Rows(b); !reflect.DeepEqual(got, [][]string{{"a", "1"}, {"z", "2"}}) {
+		t.Fatalf("Rows(b) = %v", got)
+	}
+
+	engine.Forget(a)
+	if got := engine.Stringify(a); got != `{"a":2,"z":1}` {
+		t.Fatalf("Stringify(a) after Forget = %s, want alphabetical", got)
+	}
+	if got := engine.Stringify(b); got != `{"a":1,"z":2}` {
+		t.Fatalf("Stringify(b) = %s", got)
+	}
+}
+
+func TestEngineConcurrentUse(t *testing.T) {
+	engine, err := New(WithSortOrder(SortInsertion))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	// Two documents with opposite key orders, loaded and rendered in
+	// parallel, must each keep their own order.
+	docs := []struct {
+		input string
+		keys  []string
+		json  string
+	}{
+		{`{"z":1,"items":[{"name":"a","id":1}]}`, []string{"z", "items"}, `{"z":1,"items":[{"name":"a","id":1}]}`},
+		{`{"items":[{"id":1,"name":"a"}],"z":1}`, []string{"items", "z"}, `{"items":[{"id":1,"name":"a"}],"z":1}`},
+	}
+	var wg sync.WaitGroup
+	for i := range 16 {
+		doc := docs[i%len(docs)]
+		wg.Go(func() {
+			root, err := engine.LoadRoot(doc.input)
+			if err != nil {
+				t.Errorf("LoadRoot error: %v", err)
+				return
+			}
+			defer engine.Forget(root)
+			if out, err := engine.Evaluate("_.items[0].name", root); err != nil || out != "a" {
+				t.Errorf("Evaluate = %v, %v; want a", out, err)
+			}
+			if _, err := engine.Evaluate("_.nmae", root); err == nil {
+				t.Error("Evaluate of a missing key: expected error")
+			}
+			for range 10 {
+				rows := engine.Rows(root)
+				keys := make([]string, len(rows))
+				for j, r := range rows {
+					keys[j] = r[0]
+				}
+				if !reflect.DeepEqual(keys, doc.keys) {
+					t.Errorf("Rows keys = %v, want %v", keys, doc.keys)
+				}
+				if got := engine.Stringify(root); got != doc.json {
+					t.Errorf("Stringify = %s, want %s", got, doc.json)
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestDefault(t *testing.T) {
+	first, err := Default()
+	if err != nil {
+		t.Fatalf("Default error: %v", err)
+	}
+	second, _ := Default()
+	if first != second {
+		t.Fatal("Default returned a different Engine")
+	}
+	out, err := first.Evaluate("_.a + 1", map[string]interface{}{"a": 1})
+	if err != nil || out != int64(2) {
+		t.Fatalf("Evaluate = %v, %v; want 2", out, err)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for b.Loop() {
+		if _, err := New(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEvaluateNewEngine(b *testing.B) {
+	root := map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "a"}}}
+	for b.Loop() {
+		engine, err := New()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := engine.Evaluate("_.items[0].name", root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEvaluateSharedEngine(b *testing.B) {
+	engine, err := Default()
+	if err != nil {
+		b.Fatal(err)
+	}
+	root := map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "a"}}}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := engine.Evaluate("_.items[0].name", root); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
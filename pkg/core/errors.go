@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/cel"
+	"github.com/oakwood-commons/kvx/internal/suggest"
+)
+
+// maxKeyCandidates caps the closest-key suggestions attached to ErrNoSuchKey.
+const maxKeyCandidates = 3
+
+// ErrParse reports an expression that failed to compile: a syntax error or a
+// check-time error such as an undeclared reference.
+type ErrParse struct {
+	Expr string // Expression as given to the evaluator
+	Pos  int    // Rune offset of the first error in Expr, or -1 if unknown
+	Msg  string // First error message without location or source snippet
+	Err  error  // Underlying evaluator error
+}
+
+func (e *ErrParse) Error() string { return e.Err.Error() }
+func (e *ErrParse) Unwrap() error { return e.Err }
+
+// ErrNoSuchKey reports a lookup of a map key that does not exist.
+type ErrNoSuchKey struct {
+	Path       string   // Expression for the object the key was looked up on ("" for the root)
+	Key        string   // Missing key
+	Pos        int      // Rune offset of Key in the evaluated expression, or -1 if unknown
+	Candidates []string // Closest existing keys on that object, best first
+	Err        error    // Underlying evaluator or navigator error
+}
+
+func (e *ErrNoSuchKey) Error() string { return e.Err.Error() }
+func (e *ErrNoSuchKey) Unwrap() error { return e.Err }
+
+// ErrTypeMismatch reports an operation applied to values of the wrong type.
+// Want is empty when the evaluator only knows that no overload matched; Got
+// then lists the argument types.
+type ErrTypeMismatch struct {
+	Op   string // Function or operator, e.g. "_+_" or "size", when known
+	Want string // Expected type, when known
+	Got  string // Actual type, or the argument types of a failed overload
+	Err  error  // Underlying evaluator error
+}
+
+func (e *ErrTypeMismatch) Error() string { return e.Err.Error() }
+func (e *ErrTypeMismatch) Unwrap() error { return e.Err }
+
+// classifyError converts a raw evaluator or navigator error into one of the
+// structured error types when the built-in evaluator or navigator described
+// it. root is used to look up candidate keys for ErrNoSuchKey. Other errors,
+// including those from custom evaluators, are returned unchanged.
+func (e *Engine) classifyError(expr string, root interface{}, err error) error {
+	var ee *cel.ExprError
+	if !errors.As(err, &ee) {
+		return err
+	}
+	switch ee.Kind {
+	case cel.KindParse:
+		return &ErrParse{Expr: expr, Pos: ee.Pos, Msg: ee.Msg, Err: err}
+	case cel.KindNoSuchKey:
+		return &ErrNoSuchKey{
+			Path:       ee.Parent,
+			Key:        ee.Key,
+			Pos:        ee.Pos,
+			Candidates: suggest.Closest(ee.Key, e.keysAt(root, ee.Parent), maxKeyCandidates),
+			Err:        err,
+		}
+	case cel.KindTypeMismatch:
+		return &ErrTypeMismatch{Op: ee.Op, Want: ee.Want, Got: ee.Got, Err: err}
+	default:
+		return err
+	}
+}
+
+// keysAt returns the sorted map keys of the node at path ("" or "_" is the root).
+func (e *Engine) keysAt(root interface{}, path string) []string {
+	node := root
+	if p := strings.TrimSpace(path); p != "" && p != "_" {
+		resolved, err := e.navigator().NodeAtPath(root, p)
+		if err != nil {
+			return nil
+		}
+		node = resolved
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type errEvaluator struct{ err error }
+
+func (e errEvaluator) Evaluate(string, interface{}) (interface{}, error) { return nil, e.err }
+
+func TestEvaluateNoSuchKeyError(t *testing.T) {
+	engine, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root := map[string]interface{}{
+		"spec": map[string]interface{}{"name": "a", "names": []interface{}{}, "replicas": 2},
+	}
+	_, err = engine.Evaluate("_.spec.nmae", root)
+	var nsk *ErrNoSuchKey
+	if !errors.As(err, &nsk) {
+		t.Fatalf("expected *ErrNoSuchKey, got %T: %v", err, err)
+	}
+	if nsk.Key != "nmae" || nsk.Path != "_.spec" || nsk.Pos != 7 {
+		t.Fatalf("unexpected ErrNoSuchKey: %+v", nsk)
+	}
+	if want := []string{"name", "names"}; !reflect.DeepEqual(nsk.Candidates, want) {
+		t.Fatalf("Candidates = %v, want %v", nsk.Candidates, want)
+	}
+}
+
+func TestEvaluateParseError(t *testing.T) {
+	engine, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	_, err = engine.Evaluate("_.a +* 1", map[string]interface{}{"a": 1})
+	var pe *ErrParse
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ErrParse, got %T: %v", err, err)
+	}
+	if pe.Pos < 4 || pe.Pos >= 8 {
+		t.Fatalf("Pos = %d, want the stray operator", pe.Pos)
+	}
+	if pe.Msg == "" || pe.Error() != pe.Err.Error() {
+		t.Fatalf("unexpected ErrParse: %+v", pe)
+	}
+}
+
+func TestEvaluateTypeMismatchError(t *testing.T) {
+	engine, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root := map[string]interface{}{"a": 1, "items": []interface{}{map[string]interface{}{"n": "x"}}}
+	tests := []struct {
+		expr string
+		want ErrTypeMismatch
+	}{
+		// Runtime: _ is dyn, so the overload is only rejected during evaluation.
+		{`_.a + "x"`, ErrTypeMismatch{Op: "_+_", Got: "int, string"}},
+		// Check time: the argument type is known statically.
+		{`size(1)`, ErrTypeMismatch{Op: "size", Got: "int"}},
+	}
+	for _, tt := range tests {
+		_, err := engine.Evaluate(tt.expr, root)
+		var tm *ErrTypeMismatch
+		if !errors.As(err, &tm) {
+			t.Fatalf("%q: expected *ErrTypeMismatch, got %T: %v", tt.expr, err, err)
+		}
+		if tm.Op != tt.want.Op || tm.Want != tt.want.Want || tm.Got != tt.want.Got {
+			t.Fatalf("%q: got %+v, want %+v", tt.expr, *tm, tt.want)
+		}
+		if tm.Error() != tm.Err.Error() {
+			t.Fatalf("%q: original error text not preserved: %v", tt.expr, err)
+		}
+	}
+
+	_, err = engine.EvaluateWhere(`_.n`, root["items"])
+	var tm *ErrTypeMismatch
+	if !errors.As(err, &tm) || tm.Want != "bool" || tm.Got != "string" {
+		t.Fatalf("expected a bool mismatch from the where filter, got %T: %v", err, err)
+	}
+}
+
+func TestEvaluateNoSuchKeyIndexError(t *testing.T) {
+	engine, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root := map[string]interface{}{"labels": map[string]interface{}{"tier": "front"}}
+	_, err = engine.Evaluate(`_.labels["tire"]`, root)
+	var nsk *ErrNoSuchKey
+	if !errors.As(err, &nsk) {
+		t.Fatalf("expected *ErrNoSuchKey, got %T: %v", err, err)
+	}
+	if nsk.Key != "tire" || nsk.Path != "_.labels" || nsk.Pos != 10 {
+		t.Fatalf("unexpected ErrNoSuchKey: key=%q path=%q pos=%d", nsk.Key, nsk.Path, nsk.Pos)
+	}
+	if len(nsk.Candidates) != 1 || nsk.Candidates[0] != "tier" {
+		t.Fatalf("Candidates = %v", nsk.Candidates)
+	}
+}
+
+func TestEvaluateUnrecognizedErrorUnchanged(t *testing.T) {
+	raw := errors.New("division by zero")
+	engine, err := New(WithEvaluator(errEvaluator{err: raw}))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if _, err := engine.Evaluate("_", nil); err != raw {
+		t.Fatalf("expected original error, got %v", err)
+	}
+}
+
+func TestNodeAtPathNoSuchKeyError(t *testing.T) {
+	engine, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	root := map[string]interface{}{"region": "us", "regions": map[string]interface{}{}}
+	_, err = engine.NodeAtPath(root, "regoin")
+	var nsk *ErrNoSuchKey
+	if !errors.As(err, &nsk) {
+		t.Fatalf("expected *ErrNoSuchKey, got %T: %v", err, err)
+	}
+	if nsk.Key != "regoin" || nsk.Pos != 0 || len(nsk.Candidates) == 0 || nsk.Candidates[0] != "region" {
+		t.Fatalf("unexpected ErrNoSuchKey: %+v", nsk)
+	}
+}
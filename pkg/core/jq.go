@@ -0,0 +1,28 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/oakwood-commons/kvx/internal/jq"
+)
+
+// EvaluateJQ runs the jq program expr against root, as --query-lang jq does
+// for --expression. A program that outputs exactly one value returns it;
+// one that outputs none or several returns them as a list, so ".items[]"
+// renders like "[.items[]]". Programs that do not parse return *ErrParse;
+// errors the program raises, e.g. with error/1, keep jq's wording.
+//
+// Root may hold any data the loaders return: integers of every size, times,
+// and binary values are converted as in JSON output before the program sees
+// them. The root itself is never modified.
+func EvaluateJQ(expr string, root interface{}) (interface{}, error) {
+	result, err := jq.Evaluate(expr, root)
+	if err != nil {
+		var se *jq.SyntaxError
+		if errors.As(err, &se) {
+			return nil, &ErrParse{Expr: expr, Pos: se.Pos, Msg: se.Msg, Err: err}
+		}
+		return nil, err
+	}
+	return result, nil
+}
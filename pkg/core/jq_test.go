@@ -0,0 +1,54 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateJQ(t *testing.T) {
+	root := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "size": int64(3)},
+			map[string]interface{}{"name": "b", "size": int64(5)},
+		},
+	}
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{".items[1].name", "b"},
+		{"[.items[].size] | add", 8},
+		{".items[] | .name", []interface{}{"a", "b"}},
+		{".items | map(select(.size > 4)) | length", 1},
+		{".missing", nil},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateJQ(tt.expr, root)
+		if err != nil {
+			t.Fatalf("EvaluateJQ(%q) error: %v", tt.expr, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EvaluateJQ(%q) = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateJQParseError(t *testing.T) {
+	_, err := EvaluateJQ(".items | map(.name", nil)
+	var pe *ErrParse
+	if !errors.As(err, &pe) {
+		t.Fatalf("error = %v, want *ErrParse", err)
+	}
+	if pe.Pos != 18 || pe.Msg != "unexpected EOF" || pe.Expr != ".items | map(.name" {
+		t.Errorf("ErrParse = %+v", pe)
+	}
+
+	_, err = EvaluateJQ(`.name | error("bad: \(.)")`, map[string]interface{}{"name": "x"})
+	if err == nil || err.Error() != "error: bad: x" {
+		t.Errorf("error = %v, want the program's error", err)
+	}
+	if errors.As(err, &pe) {
+		t.Errorf("runtime error reported as *ErrParse")
+	}
+}
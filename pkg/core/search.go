@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/oakwood-commons/kvx/internal/completion"
+	"github.com/oakwood-commons/kvx/internal/navigator"
+	"github.com/oakwood-commons/kvx/internal/search"
+)
+
+// SearchOptions configures Search. The zero value matches keys and values
+// containing the query, ignoring case, like the TUI search (F3).
+type SearchOptions struct {
+	// Fuzzy also matches keys and values holding the characters of the
+	// query in order, e.g. "usnm" matches "username".
+	Fuzzy bool
+	// Regex treats the query as a regular expression (RE2 syntax); prefix
+	// it with (?i) to ignore case.
+	Regex bool
+	// Limit stops the search after this many results (0 = no limit).
+	Limit int
+	// Scope is the path of the subtree to search, e.g. "_.items" or
+	// "spec.containers[0]" ("" = the whole node). Result paths still start
+	// at node.
+	Scope string
+}
+
+// SearchResult is a key/value pair found by Search.
+type SearchResult struct {
+	Path  string      // _-rooted path of the value, e.g. "_.items[0].name" or `_["bad-key"]`
+	Key   string      // Local key, e.g. "name", "[0]", or `["bad-key"]`
+	Value string      // Value as displayed in tables
+	Node  interface{} // The value itself
+}
+
+// Search finds the key/value pairs below node whose key or value matches
+// query, with the same rules and result order as the TUI search: maps are
+// walked depth first in sorted key order, maps and lists also match by their
+// compact JSON, and array elements match by value only. A plain query of the form "..key" lists every value stored under key
+// at any depth. An empty query finds nothing.
+func Search(node interface{}, query string, opts SearchOptions) ([]SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	if opts.Fuzzy && opts.Regex {
+		return nil, errors.New("search: Fuzzy and Regex cannot be combined")
+	}
+
+	scope := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(opts.Scope), "_"), ".")
+	if scope != "" {
+		sub, err := navigator.Resolve(node, scope)
+		if err != nil {
+			return nil, fmt.Errorf("search scope %q: %w", opts.Scope, err)
+		}
+		node = sub
+	}
+
+	var found []search.Result
+	switch {
+	case opts.Regex:
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		found, _ = search.Walk(node, re.MatchString, opts.Limit)
+	case opts.Fuzzy:
+		found, _ = search.Walk(node, func(text string) bool {
+			_, _, ok := completion.Match(text, query, completion.MatchFuzzy)
+			return ok
+		}, opts.Limit)
+	default:
+		if base, key, ok := navigator.SplitDescent(query); ok && base == "" {
+			found, _ = search.Descent(node, key, opts.Limit)
+		} else {
+			found, _ = search.Walk(node, search.Substring(query), opts.Limit)
+		}
+	}
+
+	results := make([]SearchResult, len(found))
+	for i, r := range found {
+		path := r.Path
+		switch {
+		case scope == "":
+		case strings.HasPrefix(path, "["):
+			path = scope + path
+		default:
+			path = scope + "." + path
+		}
+		results[i] = SearchResult{Path: search.RootedPath(path), Key: r.Key, Value: r.Value, Node: r.Node}
+	}
+	return results, nil
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func searchDoc() map[string]interface{} {
+	return map[string]interface{}{
+		"meta": map[string]interface{}{"owner": "ops"},
+		"items": []interface{}{
+			map[string]interface{}{"name": "username", "id": 1.0},
+			map[string]interface{}{"name": "group", "id": 2.0},
+		},
+		"bad-key": "x",
+	}
+}
+
+func searchPaths(results []SearchResult) []string {
+	paths := make([]string, len(results))
+	for i, r := range results {
+		paths[i] = r.Path
+	}
+	return paths
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		want  []string
+	}{
+		{name: "substring ignores case", query: "USER", want: []string{"_.items", "_.items[0]", "_.items[0].name"}},
+		{name: "fuzzy", query: "usnm", opts: SearchOptions{Fuzzy: true}, want: []string{"_.items", "_.items[0]", "_.items[0].name"}},
+		{name: "regex", query: "^(owner|id)$", opts: SearchOptions{Regex: true}, want: []string{"_.items[0].id", "_.items[1].id", "_.meta.owner"}},
+		{name: "limit", query: "^(owner|id)$", opts: SearchOptions{Regex: true, Limit: 2}, want: []string{"_.items[0].id", "_.items[1].id"}},
+		{name: "scope", query: "name", opts: SearchOptions{Scope: "_.items[1]"}, want: []string{"_.items[1].name"}},
+		{name: "scope without root", query: "owner", opts: SearchOptions{Scope: "meta"}, want: []string{"_.meta.owner"}},
+		{name: "descent", query: "..id", want: []string{"_.items[0].id", "_.items[1].id"}},
+		{name: "bracket key", query: "bad", want: []string{`_["bad-key"]`}},
+		{name: "no match", query: "zzz", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Search(searchDoc(), tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Search error: %v", err)
+			}
+			if got := searchPaths(results); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Search(%q) paths = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchResultFields(t *testing.T) {
+	results, err := Search(searchDoc(), "owner", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	meta := searchDoc()["meta"]
+	want := []SearchResult{
+		{Path: "_.meta", Key: "meta", Value: `{"owner":"ops"}`, Node: meta},
+		{Path: "_.meta.owner", Key: "owner", Value: "ops", Node: "ops"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("Search results = %#v, want %#v", results, want)
+	}
+}
+
+func TestSearchEmptyQuery(t *testing.T) {
+	results, err := Search(searchDoc(), "  ", SearchOptions{})
+	if err != nil || results != nil {
+		t.Fatalf("Search(empty) = %v, %v; want nil, nil", results, err)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	for name, opts := range map[string]SearchOptions{
+		"fuzzy and regex": {Fuzzy: true, Regex: true},
+		"bad regex":       {Regex: true},
+		"unknown scope":   {Scope: "_.missing"},
+	} {
+		query := "name"
+		if name == "bad regex" {
+			query = "("
+		}
+		if _, err := Search(searchDoc(), query, opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package format renders values as display text the way kvx does, so
+// components built on kvx show the same values as its tables and lists:
+// strings as they are, numbers in their shortest form, and maps and lists as
+// compact JSON.
+//
+//	format.Stringify(map[string]any{"a": []any{1, 2}}, format.StringifyOptions{})
+//	// {"a":[1,2]}
+//	format.Stringify(map[string]any{"a": []any{1, 2}}, format.StringifyOptions{MaxDepth: 1})
+//	// {"a":[2 items]}
+package format
+
+import "github.com/oakwood-commons/kvx/internal/formatter"
+
+// StringifyOptions adjusts Stringify. The zero value renders values like the
+// value column of kvx tables.
+type StringifyOptions = formatter.StringifyOptions
+
+// Stringify returns the display text of v. Line breaks in strings are
+// escaped as \n, so the text fits on one line.
+func Stringify(v any, opts StringifyOptions) string {
+	return formatter.StringifyWith(v, opts)
+}
@@ -0,0 +1,54 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oakwood-commons/kvx/pkg/core"
+)
+
+func TestStringify(t *testing.T) {
+	node := map[string]any{
+		"name":  "kvx",
+		"ratio": 0.125,
+		"tags":  []any{"a", "b"},
+		"meta":  map[string]any{"owner": map[string]any{"team": "core"}},
+	}
+
+	tests := []struct {
+		name string
+		v    any
+		opts StringifyOptions
+		want string
+	}{
+		{"defaults", node, StringifyOptions{}, `{"meta":{"owner":{"team":"core"}},"name":"kvx","ratio":0.125,"tags":["a","b"]}`},
+		{"max depth", node, StringifyOptions{MaxDepth: 1}, `{"meta":{1 keys},"name":"kvx","ratio":0.125,"tags":[2 items]}`},
+		{"max depth 2", node, StringifyOptions{MaxDepth: 2}, `{"meta":{"owner":{1 keys}},"name":"kvx","ratio":0.125,"tags":["a","b"]}`},
+		{"float format", 2.5, StringifyOptions{FloatFormat: "%.2f"}, "2.50"},
+		{"nested float format", []any{1.0, 2}, StringifyOptions{FloatFormat: "%.1f"}, "[1.0,2]"},
+		{"shortest float", 1e6, StringifyOptions{}, "1e+06"},
+		{"null", nil, StringifyOptions{}, ""},
+		{"null text", nil, StringifyOptions{NullText: "-"}, "-"},
+		{"nested null", []any{nil}, StringifyOptions{NullText: "-"}, "[null]"},
+		{"max len", "abcdefghij", StringifyOptions{MaxLen: 6}, "abc..."},
+		{"newlines", "a\nb", StringifyOptions{MaxDepth: 1}, `a\nb`},
+		{"struct", struct {
+			Name string         `json:"name"`
+			Meta map[string]int `json:"meta"`
+		}{"kvx", map[string]int{"a": 1}}, StringifyOptions{MaxDepth: 1}, `{"meta":{1 keys},"name":"kvx"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Stringify(tt.v, tt.opts))
+		})
+	}
+}
+
+func TestStringify_MatchesEngine(t *testing.T) {
+	engine, err := core.New()
+	assert.NoError(t, err)
+	for _, v := range []any{"text", 42, 3.14, true, map[string]any{"b": 1, "a": []any{"x"}}, []byte{0xff, 0x00}} {
+		assert.Equal(t, engine.Stringify(v), Stringify(v, StringifyOptions{}))
+	}
+}
@@ -85,6 +85,13 @@ func NewCELProvider() (Provider, error) {
 	return completion.NewCELProvider()
 }
 
+// NewJQProvider creates a jq program completion provider, built on gojq.
+// It completes object keys by running the program typed so far, along with
+// jq builtins, formats such as @base64, and bound $variables.
+func NewJQProvider() Provider {
+	return completion.NewJQProvider()
+}
+
 var customProvider Provider
 
 // SetProvider allows host applications to inject a custom Provider implementation.
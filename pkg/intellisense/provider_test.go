@@ -110,6 +110,20 @@ func TestCELProvider_EvaluateType(t *testing.T) {
 	assert.NotEmpty(t, typeStr)
 }
 
+func TestJQProvider(t *testing.T) {
+	provider := NewJQProvider()
+	assert.NotEmpty(t, provider.DiscoverFunctions())
+
+	data := map[string]any{"items": []any{map[string]any{"name": "a"}}}
+	completions := provider.FilterCompletions(".items[0].n", CompletionContext{CurrentNode: data})
+	require.Len(t, completions, 1)
+	assert.Equal(t, ".items[0].name", completions[0].Text)
+
+	result, err := provider.Evaluate("[.items[].name]", data)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a"}, result)
+}
+
 type mockProvider struct{}
 
 func (m *mockProvider) DiscoverFunctions() []FunctionMetadata                    { return nil }
@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
+	"github.com/oakwood-commons/kvx/internal/yamlsource"
+)
+
+// Document is a loaded root together with what was recorded about its source
+// while parsing. It belongs to the caller: nothing is kept by the loader, so
+// a document and its recordings are freed together.
+type Document struct {
+	// Root is the single parsed document, or a slice for multi-document input.
+	Root interface{}
+	// Order is the key order of every JSON or YAML object in Root, or nil
+	// when it was not requested.
+	Order *keyorder.Order
+	// Positions is where each value of Root was written, with the comments
+	// written alongside it, or nil when it was not requested.
+	Positions *sourcepos.Index
+	// YAML is the parsed node tree of YAML input, or nil when it was not
+	// requested. It holds no documents when the input was not YAML.
+	YAML *yamlsource.Source
+	// Format is the format the input was parsed as: "JSON", "YAML",
+	// "multi-doc YAML", "NDJSON", or "TOML"; "" for JWT input.
+	Format string
+}
+
+// DocumentOptions selects what LoadDocument records besides the data.
+type DocumentOptions struct {
+	// KeyOrder records the original key order of JSON and YAML objects.
+	KeyOrder bool
+	// Positions records the line and column of every key and list item and,
+	// for YAML and TOML, the comments written with them.
+	Positions bool
+	// YAMLSource keeps the node tree of YAML input so values can be written
+	// back out as they were written.
+	YAMLSource bool
+	// Logger receives fallback parse attempts. The zero value discards them.
+	Logger logr.Logger
+}
+
+// LoadDocument parses input like LoadRoot and records what opts asks for.
+func LoadDocument(input string, opts DocumentOptions) (*Document, error) {
+	rec := newRecording(opts)
+	results, err := loadData(input, rec, opts.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return rec.document(results), nil
+}
+
+// LoadFileDocument reads and parses a file like LoadFile and records what
+// opts asks for.
+func LoadFileDocument(path string, opts DocumentOptions) (*Document, error) {
+	rec := newRecording(opts)
+	results, err := loadFile(path, rec, opts.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return rec.document(results), nil
+}
+
+// recording collects what parsers record about the source. A nil recording
+// records nothing.
+type recording struct {
+	opts       DocumentOptions
+	file       string // file the input was read from, for positions
+	lineOffset int    // lines trimmed before the parsed text
+	order      *keyorder.Order
+	positions  *sourcepos.Index
+	yaml       *yamlsource.Source
+	format     formatName // parser that read the input
+}
+
+func newRecording(opts DocumentOptions) *recording {
+	return &recording{opts: opts}
+}
+
+// fresh returns an empty recording for one parse attempt, so a parser that
+// fails part way leaves nothing behind.
+func (r *recording) fresh() *recording {
+	if r == nil {
+		return nil
+	}
+	attempt := &recording{opts: r.opts, file: r.file, lineOffset: r.lineOffset}
+	if r.opts.KeyOrder {
+		attempt.order = keyorder.NewOrder()
+	}
+	if r.opts.Positions {
+		attempt.positions = sourcepos.NewIndex(r.file, r.lineOffset)
+	}
+	if r.opts.YAMLSource {
+		attempt.yaml = yamlsource.NewSource()
+	}
+	return attempt
+}
+
+// keep adopts what attempt recorded.
+func (r *recording) keep(attempt *recording) {
+	if r != nil && attempt != nil {
+		*r = *attempt
+	}
+}
+
+// setFormat records the format the input was parsed as.
+func (r *recording) setFormat(name formatName) {
+	if r != nil {
+		r.format = name
+	}
+}
+
+func (r *recording) keyOrder() *keyorder.Order {
+	if r == nil {
+		return nil
+	}
+	return r.order
+}
+
+func (r *recording) sourcePositions() *sourcepos.Index {
+	if r == nil {
+		return nil
+	}
+	return r.positions
+}
+
+func (r *recording) yamlSource() *yamlsource.Source {
+	if r == nil {
+		return nil
+	}
+	return r.yaml
+}
+
+func (r *recording) document(results []interface{}) *Document {
+	format := r.format
+	if format == fmtMultiDocYAML && len(results) == 1 {
+		format = fmtYAML
+	}
+	return &Document{Root: rootOf(results), Order: r.order, Positions: r.positions, YAML: r.yaml, Format: string(format)}
+}
@@ -0,0 +1,87 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertionKeys(doc *Document, v interface{}) []string {
+	return keyorder.Sorter{Mode: keyorder.Insertion, Doc: doc.Order}.Keys(v.(map[string]interface{}))
+}
+
+func TestLoadDocument_KeyOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"json", `{"zeta": 1, "alpha": {"y": 1, "x": 2}, "mid": [{"q": 1, "p": 2}]}`},
+		{"yaml", "zeta: 1\nalpha:\n  y: 1\n  x: 2\nmid:\n  - q: 1\n    p: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := LoadDocument(tt.input, DocumentOptions{KeyOrder: true})
+			require.NoError(t, err)
+			root := doc.Root.(map[string]interface{})
+			assert.Equal(t, []string{"zeta", "alpha", "mid"}, insertionKeys(doc, root))
+			assert.Equal(t, []string{"y", "x"}, insertionKeys(doc, root["alpha"]))
+			assert.Equal(t, []string{"q", "p"}, insertionKeys(doc, root["mid"].([]interface{})[0]))
+		})
+	}
+}
+
+func TestLoadDocument_KeyOrderPerDocument(t *testing.T) {
+	ndjson, err := LoadDocument("{\"b\": 1, \"a\": 2}\n{\"d\": 1, \"c\": 2}", DocumentOptions{KeyOrder: true})
+	require.NoError(t, err)
+	docs := ndjson.Root.([]interface{})
+	assert.Equal(t, []string{"b", "a"}, insertionKeys(ndjson, docs[0]))
+	assert.Equal(t, []string{"d", "c"}, insertionKeys(ndjson, docs[1]))
+
+	other, err := LoadDocument(`{"b": 1, "a": 2}`, DocumentOptions{KeyOrder: true})
+	require.NoError(t, err)
+	// A document's order says nothing about the maps of another document.
+	assert.Equal(t, []string{"a", "b"}, insertionKeys(other, docs[0]))
+}
+
+func TestLoadDocument_Format(t *testing.T) {
+	for input, want := range map[string]string{
+		`{"a": 1}`:                 "JSON",
+		"a: 1\n":                   "YAML",
+		"a: 1\n---\nb: 2\n":        "multi-doc YAML",
+		"{\"a\": 1}\n{\"a\": 2}\n": "NDJSON",
+		"[server]\nport = 80\n":    "TOML",
+	} {
+		doc, err := LoadDocument(input, DocumentOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, want, doc.Format, input)
+	}
+}
+
+func TestLoadDocument_JSONMatchesLoadRoot(t *testing.T) {
+	input := `{"n": 9007199254740993, "f": 1.5, "list": [], "dup": 1, "dup": 2, "s": null}`
+	want, err := LoadRoot(input)
+	require.NoError(t, err)
+	doc, err := LoadDocument(input, DocumentOptions{KeyOrder: true})
+	require.NoError(t, err)
+	assert.Equal(t, want, doc.Root)
+
+	_, err = decodeJSON([]byte(`{"a": 1} x`), newRecording(DocumentOptions{KeyOrder: true}).fresh(), 1)
+	require.Error(t, err)
+}
+
+func TestLoadFileDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"zeta": 1, "alpha": 2}`), 0o600))
+
+	doc, err := LoadFileDocument(path, DocumentOptions{KeyOrder: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zeta", "alpha"}, insertionKeys(doc, doc.Root))
+
+	doc, err = LoadFileDocument(path, DocumentOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, doc.Order)
+}
@@ -42,8 +42,9 @@ var (
 	tomlKeyValuePattern = regexp.MustCompile(`^(?:[a-zA-Z_][a-zA-Z0-9_-]*|"[^"]+"|'[^']+')+(?:\.(?:[a-zA-Z_][a-zA-Z0-9_-]*|"[^"]+"|'[^']+'))*\s*=\s*.+$`)
 )
 
-// parseFunc is a parser that returns parsed data or an error.
-type parseFunc func(string) ([]interface{}, error)
+// parseFunc is a parser that returns parsed data or an error. It records what
+// rec asks for about the source while parsing; rec may be nil.
+type parseFunc func(string, *recording) ([]interface{}, error)
 
 // candidate pairs a format name with a lazy parser. The parser is only
 // invoked when the candidate is actually attempted.
@@ -53,13 +54,17 @@ type candidate struct {
 }
 
 // tryParsers attempts each candidate in order. On the first success it
-// returns the result. On failure it logs the error at V(1) and continues.
-// If all candidates fail, it returns the collected error messages.
-func tryParsers(input string, candidates []candidate, lgr logr.Logger) ([]interface{}, error) {
+// returns the result and keeps what that parser recorded in rec. On failure
+// it logs the error at V(1) and continues. If all candidates fail, it returns
+// the collected error messages.
+func tryParsers(input string, candidates []candidate, rec *recording, lgr logr.Logger) ([]interface{}, error) {
 	var errs []string
 	for _, c := range candidates {
-		result, err := c.parse(input)
+		attempt := rec.fresh()
+		result, err := c.parse(input, attempt)
 		if err == nil {
+			rec.keep(attempt)
+			rec.setFormat(c.name)
 			return result, nil
 		}
 		lgr.V(1).Info("parse attempt failed, trying next format",
@@ -89,12 +94,21 @@ func LoadData(input string) ([]interface{}, error) {
 // LoadDataWithLogger is like LoadData but accepts a logger for
 // recording fallback parse attempts.
 func LoadDataWithLogger(input string, lgr logr.Logger) ([]interface{}, error) {
+	return loadData(input, nil, lgr)
+}
+
+func loadData(input string, rec *recording, lgr logr.Logger) ([]interface{}, error) {
 	// Normalize line endings: \r\n → \n, then standalone \r → \n
 	// This handles Windows line endings and carriage returns from
 	// progress indicators (e.g., CLI tools that overwrite lines).
 	input = strings.ReplaceAll(input, "\r\n", "\n")
 	input = strings.ReplaceAll(input, "\r", "\n")
-	input = strings.TrimSpace(input)
+	trimmed := strings.TrimSpace(input)
+	if rec != nil {
+		// Keep recorded line numbers relative to the original input.
+		rec.lineOffset = strings.Count(input[:strings.Index(input, trimmed)], "\n")
+	}
+	input = trimmed
 	if input == "" {
 		return nil, fmt.Errorf("empty input")
 	}
@@ -109,7 +123,7 @@ func LoadDataWithLogger(input string, lgr logr.Logger) ([]interface{}, error) {
 	// by all remaining formats as fallbacks.
 	candidates := buildCandidates(input)
 
-	return tryParsers(input, candidates, lgr)
+	return tryParsers(input, candidates, rec, lgr)
 }
 
 // extToFormat maps common file extensions to format names.
@@ -226,14 +240,20 @@ func LoadRoot(input string) (interface{}, error) {
 
 // LoadRootWithLogger is like LoadRoot but accepts a logger.
 func LoadRootWithLogger(input string, lgr logr.Logger) (interface{}, error) {
-	results, err := LoadDataWithLogger(input, lgr)
+	results, err := loadData(input, nil, lgr)
 	if err != nil {
 		return nil, err
 	}
+	return rootOf(results), nil
+}
+
+// rootOf returns the single document of results, or results itself for
+// multi-document input.
+func rootOf(results []interface{}) interface{} {
 	if len(results) == 1 {
-		return results[0], nil
+		return results[0]
 	}
-	return results, nil
+	return results
 }
 
 // LoadRootBytes parses input bytes into a single root node.
@@ -256,10 +276,21 @@ func LoadFile(path string) (interface{}, error) {
 
 // LoadFileWithLogger is like LoadFile but accepts a logger.
 func LoadFileWithLogger(path string, lgr logr.Logger) (interface{}, error) {
+	results, err := loadFile(path, nil, lgr)
+	if err != nil {
+		return nil, err
+	}
+	return rootOf(results), nil
+}
+
+func loadFile(path string, rec *recording, lgr logr.Logger) ([]interface{}, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if rec != nil {
+		rec.file = path
+	}
 
 	// Normalize line endings: \r\n → \n, then standalone \r → \n
 	// This handles Windows line endings and carriage returns from
@@ -273,18 +304,11 @@ func LoadFileWithLogger(path string, lgr logr.Logger) (interface{}, error) {
 		lgr.V(1).Info("file extension detected, trying preferred format",
 			"ext", ext, "format", string(fmtName))
 		candidates := parsersForFormat(fmtName, input)
-		results, parseErr := tryParsers(input, candidates, lgr)
-		if parseErr != nil {
-			return nil, parseErr
-		}
-		if len(results) == 1 {
-			return results[0], nil
-		}
-		return results, nil
+		return tryParsers(input, candidates, rec, lgr)
 	}
 
 	// No recognized extension — fall back to content heuristics.
-	return LoadRootWithLogger(input, lgr)
+	return loadData(input, rec, lgr)
 }
 
 // LoadObject accepts an already parsed object (maps, slices, structs, etc.).
@@ -389,62 +413,82 @@ func normalizeCELType(value interface{}) (interface{}, error) {
 }
 
 // loadJSON parses a single JSON object or array and wraps it in []interface{}
-func loadJSON(input string) ([]interface{}, error) {
-	var data interface{}
-	if err := json.Unmarshal([]byte(input), &data); err != nil {
+func loadJSON(input string, rec *recording) ([]interface{}, error) {
+	data, err := decodeJSON([]byte(input), rec, 1)
+	if err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 	return []interface{}{data}, nil
 }
 
 // loadYAML parses a single YAML document and wraps it in []interface{}
-func loadYAML(input string) ([]interface{}, error) {
+func loadYAML(input string, rec *recording) ([]interface{}, error) {
+	// Parse once into the node tree: the data is decoded from it, and the
+	// recorders read key order, positions, and comments from it.
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(input), &node); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
 	var data interface{}
-	if err := yaml.Unmarshal([]byte(input), &data); err != nil {
+	if err := node.Decode(&data); err != nil {
 		return nil, fmt.Errorf("invalid YAML: %w", err)
 	}
+	rec.keyOrder().RecordYAML(&node, data)
+	rec.sourcePositions().RecordYAML(&node, data)
+	rec.yamlSource().Add(&node)
 	return []interface{}{data}, nil
 }
 
 // loadMultiDocYAML parses YAML with multiple documents (separated by ---) and returns []interface{}
-func loadMultiDocYAML(input string) ([]interface{}, error) {
+func loadMultiDocYAML(input string, rec *recording) ([]interface{}, error) {
 	var results []interface{}
+	var nodes []*yaml.Node
 	decoder := yaml.NewDecoder(strings.NewReader(input))
 
 	for {
 		var doc interface{}
-		if err := decoder.Decode(&doc); err != nil {
+		node := new(yaml.Node)
+		if err := decoder.Decode(node); err != nil {
 			if err.Error() == "EOF" {
 				break
 			}
 			return nil, fmt.Errorf("invalid multi-document YAML: %w", err)
 		}
+		if err := node.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("invalid multi-document YAML: %w", err)
+		}
+		rec.keyOrder().RecordYAML(node, doc)
+		rec.sourcePositions().RecordYAML(node, doc)
 		if doc != nil {
 			results = append(results, doc)
+			nodes = append(nodes, node)
 		}
 	}
 
 	if len(results) == 0 {
 		return nil, fmt.Errorf("no documents found in multi-document YAML")
 	}
+	for _, node := range nodes {
+		rec.yamlSource().Add(node)
+	}
 	return results, nil
 }
 
 // loadNDJSON parses newline-delimited JSON and returns []interface{}
 // Lines that are valid JSON objects become map/array elements.
 // Lines that are not valid JSON are treated as plain strings.
-func loadNDJSON(input string) ([]interface{}, error) {
+func loadNDJSON(input string, rec *recording) ([]interface{}, error) {
 	lines := strings.Split(input, "\n")
 	results := make([]interface{}, 0, len(lines))
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
 		if line == "" {
 			continue
 		}
 
-		var obj interface{}
-		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		obj, err := decodeJSON([]byte(raw), rec, i+1)
+		if err != nil {
 			// If JSON parsing fails, treat the line as a plain string
 			results = append(results, line)
 			continue
@@ -521,10 +565,11 @@ func isLikelyTOML(input string) bool {
 }
 
 // loadTOML parses TOML content and wraps it in []interface{}
-func loadTOML(input string) ([]interface{}, error) {
+func loadTOML(input string, rec *recording) ([]interface{}, error) {
 	var data interface{}
 	if err := toml.Unmarshal([]byte(input), &data); err != nil {
 		return nil, fmt.Errorf("invalid TOML: %w", err)
 	}
+	rec.sourcePositions().RecordTOML([]byte(input), data)
 	return []interface{}{data}, nil
 }
@@ -2,9 +2,11 @@ package loader
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-logr/logr"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -797,3 +799,54 @@ func TestExtToFormat_Unknown(t *testing.T) {
 	_, ok := extToFormat(".xyz")
 	assert.False(t, ok)
 }
+
+func TestLoadFileDocument_RecordsSourcePositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("a: 1\nlist:\n  - x\n  - y\n"), 0o644))
+	doc, err := LoadFileDocument(path, DocumentOptions{Positions: true})
+	require.NoError(t, err)
+
+	list := doc.Root.(map[string]interface{})["list"]
+	pos, ok := doc.Positions.Lookup(list, "1")
+	require.True(t, ok)
+	assert.Equal(t, sourcepos.Position{File: path, Line: 4, Column: 5}, pos)
+}
+
+func TestLoadDocument_SourcePositionsCountTrimmedLines(t *testing.T) {
+	doc, err := LoadDocument("\n\n{\"a\": 1,\n \"b\": 2}\n", DocumentOptions{Positions: true})
+	require.NoError(t, err)
+	pos, ok := doc.Positions.Lookup(doc.Root, "b")
+	require.True(t, ok)
+	assert.Equal(t, sourcepos.Position{Line: 4, Column: 2}, pos)
+
+	doc, err = LoadDocument("{\"n\": 1}\n{\"n\": 2}\n", DocumentOptions{Positions: true})
+	require.NoError(t, err)
+	pos, ok = doc.Positions.Lookup(doc.Root.([]interface{})[1], "n")
+	require.True(t, ok, "NDJSON lines are positioned on their own line")
+	assert.Equal(t, 2, pos.Line)
+}
+
+func TestLoadDocument_JSONSourcePositions(t *testing.T) {
+	doc, err := LoadDocument("{\n  \"a\": {\"b\": [1,\n    {\"c\": 2}]},\n  \"z\": \"x\"\n}", DocumentOptions{Positions: true})
+	require.NoError(t, err)
+	root := doc.Root.(map[string]interface{})
+	lookup := func(container interface{}, key string) sourcepos.Position {
+		pos, ok := doc.Positions.Lookup(container, key)
+		require.True(t, ok, "no position for %q", key)
+		return pos
+	}
+	assert.Equal(t, sourcepos.Position{Line: 2, Column: 3}, lookup(root, "a"))
+	assert.Equal(t, sourcepos.Position{Line: 4, Column: 3}, lookup(root, "z"))
+	a := root["a"].(map[string]interface{})
+	assert.Equal(t, sourcepos.Position{Line: 2, Column: 9}, lookup(a, "b"))
+	b := a["b"].([]interface{})
+	assert.Equal(t, sourcepos.Position{Line: 2, Column: 15}, lookup(b, "0"))
+	assert.Equal(t, sourcepos.Position{Line: 3, Column: 5}, lookup(b, "1"))
+}
+
+func TestLoadDocument_RecordsNothingByDefault(t *testing.T) {
+	doc, err := LoadDocument(`{"a": 1}`, DocumentOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, doc.Order)
+	assert.Nil(t, doc.Positions)
+}
@@ -0,0 +1,178 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+	"github.com/oakwood-commons/kvx/internal/sourcepos"
+)
+
+// unmarshalJSON decodes JSON like json.Unmarshal into an interface{} but
+// without routing every number through float64. Numbers float64 holds exactly
+// stay float64 as before; every other number (integers beyond 2^53, decimals
+// with more precision than float64) is kept as json.Number so it is written
+// back out unchanged.
+func unmarshalJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return normalizeNumbers(v), nil
+}
+
+// decodeJSON decodes data like unmarshalJSON. When rec asks for key order
+// or positions it reads the token stream instead and records them in the
+// same pass; firstLine is the line data starts on within the parsed text.
+func decodeJSON(data []byte, rec *recording, firstLine int) (interface{}, error) {
+	d := jsonDecoder{order: rec.keyOrder(), positions: rec.sourcePositions()}
+	if d.order == nil && d.positions == nil {
+		return unmarshalJSON(data)
+	}
+	d.dec = json.NewDecoder(bytes.NewReader(data))
+	d.dec.UseNumber()
+	if d.positions != nil {
+		d.lines = sourcepos.NewLines(data, firstLine)
+	}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+type jsonDecoder struct {
+	dec       *json.Decoder
+	order     *keyorder.Order
+	positions *sourcepos.Index
+	lines     *sourcepos.Lines
+}
+
+// next returns the position of the next token, when positions are recorded.
+func (d *jsonDecoder) next() sourcepos.Position {
+	if d.lines == nil {
+		return sourcepos.Position{}
+	}
+	return d.lines.Next(d.dec.InputOffset())
+}
+
+func (d *jsonDecoder) value() (interface{}, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Number:
+		return numberValue(t), nil
+	case json.Delim:
+		if t == '[' {
+			return d.array()
+		}
+		return d.object()
+	}
+	return tok, nil
+}
+
+func (d *jsonDecoder) object() (interface{}, error) {
+	m := make(map[string]interface{})
+	var keys []string
+	positions := make(map[string]sourcepos.Position)
+	for d.dec.More() {
+		pos := d.next()
+		kt, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := kt.(string)
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		// Later duplicates win, as they do in encoding/json, but keep the
+		// place of the first in the key order.
+		if _, dup := m[key]; !dup {
+			keys = append(keys, key)
+		}
+		m[key] = v
+		positions[key] = pos
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+	d.order.Record(m, keys)
+	d.positions.Record(m, positions, nil)
+	return m, nil
+}
+
+func (d *jsonDecoder) array() (interface{}, error) {
+	arr := []interface{}{}
+	positions := make(map[string]sourcepos.Position)
+	for d.dec.More() {
+		positions[strconv.Itoa(len(arr))] = d.next()
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+	d.positions.Record(arr, positions, nil)
+	return arr, nil
+}
+
+// normalizeNumbers replaces json.Number values in place (maps and slices keep
+// their identity) and returns the normalized value.
+func normalizeNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		return numberValue(t)
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalizeNumbers(e)
+		}
+	}
+	return v
+}
+
+// numberValue is n as a float64 when that is exact, and n itself otherwise.
+func numberValue(n json.Number) interface{} {
+	if f, err := n.Float64(); err == nil && floatExact(n, f) {
+		return f
+	}
+	return n
+}
+
+// floatExact reports whether f, written in its shortest form, denotes the
+// same value as n (so 0.1 and 19.90 qualify, 9007199254740993 does not).
+func floatExact(n json.Number, f float64) bool {
+	short := strconv.FormatFloat(f, 'g', -1, 64)
+	if short == n.String() {
+		// Most numbers are already written in shortest form; only spellings
+		// such as 19.90 or 1e3 need an exact comparison.
+		return true
+	}
+	want, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return false
+	}
+	got, ok := new(big.Rat).SetString(short)
+	return ok && want.Cmp(got) == 0
+}
@@ -0,0 +1,52 @@
+package loader
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRoot_PreservesLargeNumbers(t *testing.T) {
+	root, err := LoadRoot(`{"id": 9007199254740993, "price": 12345678901234567.891, "huge": 123456789012345678901234567890, "n": 1.5, "count": 3, "ratio": 0.1}`)
+	require.NoError(t, err)
+	m := root.(map[string]interface{})
+
+	assert.Equal(t, json.Number("9007199254740993"), m["id"])
+	assert.Equal(t, json.Number("12345678901234567.891"), m["price"])
+	assert.Equal(t, json.Number("123456789012345678901234567890"), m["huge"])
+	assert.Equal(t, 1.5, m["n"])
+	assert.Equal(t, float64(3), m["count"], "exactly representable numbers stay float64")
+	assert.Equal(t, 0.1, m["ratio"])
+}
+
+func TestLoadRoot_NDJSONPreservesLargeNumbers(t *testing.T) {
+	root, err := LoadRoot("{\"id\": 9007199254740993}\n{\"id\": 1}\n")
+	require.NoError(t, err)
+	items := root.([]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, json.Number("9007199254740993"), items[0].(map[string]interface{})["id"])
+	assert.Equal(t, float64(1), items[1].(map[string]interface{})["id"])
+}
+
+func TestUnmarshalJSON_RejectsTrailingData(t *testing.T) {
+	_, err := unmarshalJSON([]byte(`{"a": 1} x`))
+	assert.Error(t, err)
+}
+
+func TestFloatExact(t *testing.T) {
+	for in, want := range map[string]bool{
+		"0.1":                    true,
+		"3":                      true,
+		"19.90":                  true,
+		"1e3":                    true,
+		"-0.5":                   true,
+		"9007199254740993":       false,
+		"0.10000000000000000001": false,
+	} {
+		f, err := json.Number(in).Float64()
+		require.NoError(t, err, in)
+		assert.Equal(t, want, floatExact(json.Number(in), f), in)
+	}
+}
@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/oakwood-commons/kvx/internal/keyorder"
+)
+
+// NDJSONStream reads newline-delimited JSON one record at a time, for inputs
+// too large to read whole. Records decode like LoadData decodes NDJSON:
+// lines that are not valid JSON become plain strings and blank lines are
+// skipped.
+type NDJSONStream struct {
+	r    *bufio.Reader
+	rec  *recording
+	line int
+}
+
+// NewNDJSONStream returns a stream of the records of r. Of opts, only
+// KeyOrder applies; source positions are not recorded.
+func NewNDJSONStream(r io.Reader, opts DocumentOptions) *NDJSONStream {
+	s := &NDJSONStream{r: bufio.NewReaderSize(r, 64*1024)}
+	if opts.KeyOrder {
+		s.rec = &recording{order: keyorder.NewOrder()}
+	}
+	return s
+}
+
+// Order returns the key order of the records read so far, or nil without
+// DocumentOptions.KeyOrder. It grows as records are read.
+func (s *NDJSONStream) Order() *keyorder.Order {
+	return s.rec.keyOrder()
+}
+
+// Next returns the next record, or io.EOF after the last one. Lines have no
+// length limit; a last line without a newline is still a record.
+func (s *NDJSONStream) Next() (interface{}, error) {
+	for {
+		raw, err := s.r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if len(raw) == 0 && err != nil {
+			return nil, io.EOF
+		}
+		s.line++
+		line := bytes.TrimSpace(raw)
+		if len(line) == 0 {
+			if err != nil {
+				return nil, io.EOF
+			}
+			continue
+		}
+		obj, decodeErr := decodeJSON(line, s.rec, s.line)
+		if decodeErr != nil {
+			return string(line), nil
+		}
+		return obj, nil
+	}
+}
+
+// Line returns the number of the line the last record was read from.
+func (s *NDJSONStream) Line() int {
+	return s.line
+}
@@ -0,0 +1,53 @@
+package loader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readStream(t *testing.T, s *NDJSONStream) []interface{} {
+	t.Helper()
+	var records []interface{}
+	for {
+		rec, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	input := "{\"id\":1}\r\n\n[1,2]\nnot json\n{\"id\":" + strings.Repeat("9", 100000) + "}\n{\"id\":3}"
+	s := NewNDJSONStream(strings.NewReader(input), DocumentOptions{})
+	records := readStream(t, s)
+
+	require.Len(t, records, 5)
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, records[0])
+	assert.Equal(t, []interface{}{float64(1), float64(2)}, records[1])
+	assert.Equal(t, "not json", records[2], "lines that are not JSON become strings, as with LoadData")
+	assert.Contains(t, records[3], "id", "lines longer than the read buffer decode whole")
+	assert.Equal(t, map[string]interface{}{"id": float64(3)}, records[4], "the last line needs no newline")
+	assert.Equal(t, 6, s.Line())
+	assert.Nil(t, s.Order())
+}
+
+func TestNDJSONStream_KeyOrder(t *testing.T) {
+	s := NewNDJSONStream(strings.NewReader(`{"zeta":1,"alpha":2}`), DocumentOptions{KeyOrder: true})
+	records := readStream(t, s)
+
+	require.Len(t, records, 1)
+	keys, ok := s.Order().Keys(records[0].(map[string]interface{}))
+	require.True(t, ok)
+	assert.Equal(t, []string{"zeta", "alpha"}, keys)
+}
+
+func TestNDJSONStream_Empty(t *testing.T) {
+	assert.Empty(t, readStream(t, NewNDJSONStream(strings.NewReader("\n\n"), DocumentOptions{})))
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"strings"
+	"time"
 
 	"github.com/oakwood-commons/kvx/internal/ui"
 )
@@ -18,6 +19,7 @@ type Config struct {
 	Theme              ui.Theme
 	ThemeName          string // Alternative to Theme: set a built-in theme by name (dark, warm, cool)
 	ExpressionProvider ExpressionProvider
+	QueryLang          string // Expression bar language: "cel" (default) or "jq", which runs typed jq programs with gojq
 	Menu               *ui.MenuConfig
 	InfoPopup          *ui.InfoPopupConfig
 	AllowEditInput     *bool
@@ -40,8 +42,45 @@ type Config struct {
 	AllowDecode                *bool               // Whether Enter/Right can decode serialized scalars (default: true)
 	AutoDecode                 string              // Auto-decode mode: "" (manual only), "lazy" (on navigate), "eager" (at load)
 	DisplaySchema              *DisplaySchema      // Optional display schema for rich TUI rendering (list/detail/status views)
+	SchemaDocs                 *SchemaDoc          // Optional schema titles and descriptions shown for the selected key (see ParseSchemaDocs)
 	KeyMode                    string              // Keybinding mode: "vim" (default), "emacs", or "function"
+	CompletionMatchMode        string              // Expression completion matching: "fuzzy" (default) or "prefix"
 	Done                       <-chan StatusResult // Optional channel for async completion in status view mode
+	StatusController           *StatusController   // Optional controller updating the status view from goroutines (see NewStatusController)
+	Notify                     bool                // Show a desktop notification when the status view finishes while the terminal is unfocused
+	Bell                       string              // Ring the terminal bell on: "never" (default), "error", or "always"
+	VisualBell                 bool                // Flash the screen instead of sounding the bell
+	IdleTimeout                time.Duration       // Exit after this long without input (0: never)
+	IdlePrint                  bool                // On an idle exit, print the current expression result like F10
+	Kiosk                      bool                // Read-only: navigation and search only, no editing, output on quit, clipboard, or shell-outs
+	Refresh                    func() (any, error) // Reads the data again every RefreshInterval; the view re-evaluates the current expression on it
+	RefreshInterval            time.Duration       // How often Refresh is called (0: never)
+	Watch                      string              // File whose changes reload the data, keeping the current path and row; read with Refresh when set, else with core.LoadFile
+	DataController             *DataController     // Optional controller pushing new data into the running viewer from goroutines (see NewDataController)
+	OnQuit                     func(QuitResult)    // Called when the viewer exits, with what the session did (e.g. for usage analytics)
+	OnEvent                    func(Event)         // Called as the user navigates, searches, and evaluates expressions; runs on the viewer's update loop, so it must not block
+}
+
+// Visit records that the user opened a path, or an expression, in the viewer.
+type Visit = ui.Visit
+
+// Event is what Config.OnEvent receives: a navigation, search, or evaluation
+// in the running viewer, with the fields of its Kind set.
+type Event = ui.Event
+
+// EventKind says what an Event reports.
+type EventKind = ui.EventKind
+
+// Event kinds.
+const (
+	EventNavigate = ui.EventNavigate // The view moved to Path
+	EventSearch   = ui.EventSearch   // A deep search for Query below Path found Results
+	EventEvaluate = ui.EventEvaluate // Expr was evaluated in Duration; Error is set when it failed
+)
+
+// QuitResult is what Config.OnQuit receives when the viewer exits.
+type QuitResult struct {
+	Visits []Visit // Paths opened during the session, oldest first; a path appears again each time it is revisited
 }
 
 // DefaultConfig returns a baseline TUI config with the same defaults as the CLI.
@@ -115,7 +154,14 @@ func (c Config) Apply() {
 	if c.ExpressionProvider != nil {
 		ui.SetExpressionProvider(c.ExpressionProvider)
 	}
+	if err := ui.SetQueryLang(strings.ToLower(strings.TrimSpace(c.QueryLang))); err != nil {
+		// An unknown language keeps the default CEL expression bar
+		_ = ui.SetQueryLang(ui.QueryLangCEL)
+	}
 	if c.Menu != nil {
 		ui.SetMenuConfig(*c.Menu)
 	}
+	if c.Kiosk {
+		ui.SetMenuConfig(ui.KioskMenu(ui.CurrentMenuConfig()))
+	}
 }
@@ -182,6 +182,27 @@ func TestConfig_Apply_NoTheme(t *testing.T) {
 	}
 }
 
+// TestConfig_Apply_QueryLang tests that QueryLang switches the expression bar
+// to jq, and that an unknown language keeps CEL
+func TestConfig_Apply_QueryLang(t *testing.T) {
+	defer ui.ResetExpressionProvider()
+
+	Config{QueryLang: "jq"}.Apply()
+	if got := ui.QueryLang(); got != ui.QueryLangJQ {
+		t.Errorf("QueryLang jq: got %q", got)
+	}
+
+	Config{}.Apply()
+	if got := ui.QueryLang(); got != ui.QueryLangCEL {
+		t.Errorf("empty QueryLang should select CEL: got %q", got)
+	}
+
+	Config{QueryLang: "xpath"}.Apply()
+	if got := ui.QueryLang(); got != ui.QueryLangCEL {
+		t.Errorf("unknown QueryLang should keep CEL: got %q", got)
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 	if cfg.AppName != "kvx" {
@@ -3,12 +3,16 @@ package tui
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/oakwood-commons/kvx/internal/formatter"
 	"github.com/oakwood-commons/kvx/internal/ui"
 )
 
-// DisplaySchema controls how the interactive TUI renders arrays of objects.
-// When present, arrays matching the schema render as a scrollable card list
+// DisplaySchema controls how the interactive TUI renders collections of
+// objects: arrays, and maps keyed by ID. When present, collections matching
+// the schema render as a scrollable card list
 // (title + subtitle + badges) instead of the default KEY/VALUE table, and
 // drilling into an item shows a sectioned detail view.
 //
@@ -18,12 +22,34 @@ import (
 //   - extracted from JSON Schema vendor extensions (x-kvx-*) via [ParseSchemaWithDisplay]
 type DisplaySchema = ui.DisplaySchema
 
-// ListDisplayConfig controls the card-list rendering for arrays of objects.
+// ListDisplayConfig controls the card-list rendering for collections of
+// objects. The title of an element of a map defaults to its key, which any
+// field can name as [ListKeyField].
 type ListDisplayConfig = ui.ListDisplayConfig
 
 // DetailDisplayConfig controls how a single object is rendered in detail view.
 type DetailDisplayConfig = ui.DetailDisplayConfig
 
+// ListKeyField is the pseudo-field naming the map key of an element of a
+// map-valued collection, for use in any ListDisplayConfig field.
+const ListKeyField = ui.ListKeyField
+
+// ValueStates maps the values of a field to semantic states by numeric
+// thresholds or by value (see ListDisplayConfig.BadgeStates).
+type ValueStates = ui.ValueStates
+
+// StateThreshold maps the numbers below a bound to a state.
+type StateThreshold = ui.StateThreshold
+
+// Semantic states for ValueStates, which pick a value's theme color and
+// badge icon.
+const (
+	StateOK      = formatter.StateOK
+	StateInfo    = formatter.StateInfo
+	StateWarning = formatter.StateWarning
+	StateError   = formatter.StateError
+)
+
 // DetailSection defines a group of fields rendered together with a specific layout.
 type DetailSection = ui.DetailSection
 
@@ -42,6 +68,26 @@ type StatusKeyBindings = ui.StatusKeyBindings
 // StatusResult carries the outcome of an async operation for the status screen.
 type StatusResult = ui.StatusResult
 
+// StatusController updates the status screen from goroutines: its message,
+// lines, fields, and progress, and finishes it. Pass one created with
+// [NewStatusController] in Config.StatusController.
+type StatusController = ui.StatusController
+
+// NewStatusController returns a controller for the status screen.
+func NewStatusController() *StatusController {
+	return ui.NewStatusController()
+}
+
+// DataController replaces the data of a running viewer from goroutines,
+// keeping the path, selected row, and column filters where they still
+// apply. Pass one created with [NewDataController] in Config.DataController.
+type DataController = ui.DataController
+
+// NewDataController returns a controller for the viewer's data.
+func NewDataController() *DataController {
+	return ui.NewDataController()
+}
+
 // DoneBehavior constants for StatusDisplayConfig.
 const (
 	DoneBehaviorExitAfterDelay = ui.DoneBehaviorExitAfterDelay
@@ -112,10 +158,11 @@ func extractDisplaySchemaFromJSONSchema(raw map[string]any) *DisplaySchema {
 		target = raw
 	}
 
-	// Check for any x-kvx-* key
+	// Check for any x-kvx-* key; x-kvx-key names array element keys for
+	// kvx patch and is not a display hint.
 	hasExtension := false
 	for k := range target {
-		if len(k) > 5 && k[:5] == "x-kvx" {
+		if len(k) > 5 && k[:5] == "x-kvx" && k != "x-kvx-key" {
 			hasExtension = true
 			break
 		}
@@ -156,6 +203,12 @@ func extractDisplaySchemaFromJSONSchema(raw map[string]any) *DisplaySchema {
 		if v, ok := listRaw["arrayStyle"].(string); ok {
 			list.ArrayStyle = v
 		}
+		if v, ok := listRaw["badgeStates"]; ok {
+			// Round-trip through JSON to decode the nested mappings.
+			if data, err := json.Marshal(v); err == nil {
+				_ = json.Unmarshal(data, &list.BadgeStates)
+			}
+		}
 		ds.List = list
 	}
 
@@ -195,6 +248,10 @@ func extractDisplaySchemaFromJSONSchema(raw map[string]any) *DisplaySchema {
 				detail.Sections = append(detail.Sections, section)
 			}
 		}
+		// Property titles and descriptions label the detail view's fields.
+		if properties, _ := findProperties(raw); properties != nil {
+			detail.Labels, detail.Descriptions = propertyDocs(properties)
+		}
 		ds.Detail = detail
 	}
 
@@ -225,6 +282,21 @@ func parseStatusExtension(raw map[string]any) *StatusDisplayConfig {
 	if v, ok := raw["doneDelay"].(string); ok {
 		sc.DoneDelay = v
 	}
+	if v, ok := raw["deadlineField"].(string); ok {
+		sc.DeadlineField = v
+	}
+	if v, ok := raw["deadlineLabel"].(string); ok {
+		sc.DeadlineLabel = v
+	}
+	if v, ok := raw["expiredMessage"].(string); ok {
+		sc.ExpiredMessage = v
+	}
+	if v, ok := raw["failOnExpiry"].(bool); ok {
+		sc.FailOnExpiry = v
+	}
+	if v, ok := raw["qrField"].(string); ok {
+		sc.QRField = v
+	}
 	if dfRaw, ok := raw["displayFields"].([]any); ok {
 		for _, dRaw := range dfRaw {
 			dMap, ok := dRaw.(map[string]any)
@@ -274,10 +346,43 @@ func parseStatusExtension(raw map[string]any) *StatusDisplayConfig {
 	return sc
 }
 
+// validateValueStates checks that a state mapping names only known states.
+func validateValueStates(s *ValueStates) error {
+	if s == nil {
+		return nil
+	}
+	check := func(state string) error {
+		if !formatter.ValidState(state) {
+			return fmt.Errorf("unknown state %q (expected ok, info, warning, or error)", state)
+		}
+		return nil
+	}
+	for i, t := range s.Thresholds {
+		if err := check(t.State); err != nil {
+			return fmt.Errorf("thresholds[%d]: %w", i, err)
+		}
+	}
+	if s.Else != "" {
+		if err := check(s.Else); err != nil {
+			return fmt.Errorf("else: %w", err)
+		}
+	}
+	for _, v := range slices.Sorted(maps.Keys(s.Values)) {
+		if err := check(s.Values[v]); err != nil {
+			return fmt.Errorf("values[%q]: %w", v, err)
+		}
+	}
+	return nil
+}
+
 // validateDisplaySchema checks that a display schema has the minimum required fields.
 func validateDisplaySchema(ds *DisplaySchema) error {
-	if ds.List != nil && ds.List.TitleField == "" {
-		return fmt.Errorf("display schema: list.titleField is required")
+	if ds.List != nil {
+		for _, field := range slices.Sorted(maps.Keys(ds.List.BadgeStates)) {
+			if err := validateValueStates(ds.List.BadgeStates[field]); err != nil {
+				return fmt.Errorf("display schema: list.badgeStates.%s: %w", field, err)
+			}
+		}
 	}
 	if ds.Detail != nil {
 		for i, s := range ds.Detail.Sections {
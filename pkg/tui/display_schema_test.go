@@ -107,16 +107,18 @@ func TestParseDisplaySchema_InvalidJSON(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestParseDisplaySchema_ListMissingTitleField(t *testing.T) {
+func TestParseDisplaySchema_ListWithoutTitleField(t *testing.T) {
+	// Without a titleField, maps keyed by ID are titled by their keys.
 	doc := `{
 		"displaySchema": "v1",
 		"list": {
 			"subtitleField": "desc"
 		}
 	}`
-	_, err := ParseDisplaySchema([]byte(doc))
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "titleField")
+	ds, err := ParseDisplaySchema([]byte(doc))
+	require.NoError(t, err)
+	assert.Empty(t, ds.List.TitleField)
+	assert.Equal(t, "desc", ds.List.SubtitleField)
 }
 
 func TestParseDisplaySchema_InvalidLayout(t *testing.T) {
@@ -149,6 +151,42 @@ func TestParseDisplaySchema_EmptySectionFields(t *testing.T) {
 	assert.Contains(t, err.Error(), "fields")
 }
 
+func TestParseDisplaySchema_BadgeStates(t *testing.T) {
+	doc := `{
+		"displaySchema": "v1",
+		"list": {
+			"titleField": "name",
+			"badgeFields": ["status", "latency"],
+			"badgeStates": {
+				"status": {"values": {"running": "ok", "failed": "error"}},
+				"latency": {
+					"thresholds": [{"below": 100, "state": "ok"}, {"below": 300, "state": "warning"}],
+					"else": "error"
+				}
+			}
+		}
+	}`
+	ds, err := ParseDisplaySchema([]byte(doc))
+	require.NoError(t, err)
+	require.Len(t, ds.List.BadgeStates, 2)
+	assert.Equal(t, StateError, ds.List.BadgeStates["status"].State("failed"))
+	assert.Equal(t, StateWarning, ds.List.BadgeStates["latency"].State("250"))
+	assert.Equal(t, StateError, ds.List.BadgeStates["latency"].State("300"))
+}
+
+func TestParseDisplaySchema_BadgeStatesUnknownState(t *testing.T) {
+	doc := `{
+		"displaySchema": "v1",
+		"list": {
+			"titleField": "name",
+			"badgeStates": {"latency": {"thresholds": [{"below": 100, "state": "green"}]}}
+		}
+	}`
+	_, err := ParseDisplaySchema([]byte(doc))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `list.badgeStates.latency: thresholds[0]: unknown state "green"`)
+}
+
 // ---------------------------------------------------------------------------
 // ParseSchemaWithDisplay (JSON Schema with x-kvx-* extensions)
 // ---------------------------------------------------------------------------
@@ -161,7 +199,8 @@ func TestParseSchemaWithDisplay_XKvxExtensions(t *testing.T) {
 		"x-kvx-list": {
 			"titleField": "name",
 			"subtitleField": "desc",
-			"badgeFields": ["status"]
+			"badgeFields": ["status"],
+			"badgeStates": {"status": {"values": {"inactive": "warning"}}}
 		},
 		"x-kvx-detail": {
 			"titleField": "name",
@@ -198,6 +237,7 @@ func TestParseSchemaWithDisplay_XKvxExtensions(t *testing.T) {
 	assert.Equal(t, "name", ds.List.TitleField)
 	assert.Equal(t, "desc", ds.List.SubtitleField)
 	assert.Equal(t, []string{"status"}, ds.List.BadgeFields)
+	assert.Equal(t, StateWarning, ds.List.BadgeStates["status"].State("inactive"))
 	require.NotNil(t, ds.Detail)
 	require.Len(t, ds.Detail.Sections, 2)
 }
@@ -401,6 +441,11 @@ func TestParseSchemaWithDisplay_StatusExtension(t *testing.T) {
 			"titleField": "title",
 			"messageField": "msg",
 			"waitMessage": "Please wait...",
+			"deadlineField": "expires_in",
+			"deadlineLabel": "Code expires in",
+			"expiredMessage": "Code expired",
+			"failOnExpiry": true,
+			"qrField": "url",
 			"actions": [
 				{
 					"label": "Copy token",
@@ -424,6 +469,11 @@ func TestParseSchemaWithDisplay_StatusExtension(t *testing.T) {
 	assert.Equal(t, "title", ds.Status.TitleField)
 	assert.Equal(t, "msg", ds.Status.MessageField)
 	assert.Equal(t, "Please wait...", ds.Status.WaitMessage)
+	assert.Equal(t, "expires_in", ds.Status.DeadlineField)
+	assert.Equal(t, "Code expires in", ds.Status.DeadlineLabel)
+	assert.Equal(t, "Code expired", ds.Status.ExpiredMessage)
+	assert.True(t, ds.Status.FailOnExpiry)
+	assert.Equal(t, "url", ds.Status.QRField)
 	require.Len(t, ds.Status.Actions, 1)
 	assert.Equal(t, "Copy token", ds.Status.Actions[0].Label)
 }
@@ -1,8 +1,8 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"charm.land/lipgloss/v2"
@@ -10,7 +10,6 @@ import (
 	"github.com/oakwood-commons/kvx/internal/navigator"
 	"github.com/oakwood-commons/kvx/internal/ui"
 	"github.com/oakwood-commons/kvx/pkg/core"
-	"gopkg.in/yaml.v3"
 )
 
 // ArrayStyle constants control how array indices are displayed.
@@ -175,10 +174,41 @@ type TableOptions struct {
 	// Set to -1 for unlimited, or a positive number to cap at that many lines.
 	MaxValueLines *int
 
+	// WrapValues wraps long values in the KEY/VALUE table view onto
+	// continuation lines instead of truncating them. Fields whose
+	// ColumnHints entry has Wrap set wrap either way. Like MaxValueLines,
+	// wrapping counts toward the line cap.
+	WrapValues bool
+
 	// Schema provides a display schema for automatic column derivation and
 	// schema-aware rendering. When set and ColumnOrder/HiddenColumns are
 	// empty, DeriveTableOptionsFromSchema is called to populate them.
 	Schema *DisplaySchema
+
+	// Summary adds a footer row under columnar tables. Keys are field names;
+	// values are an aggregate: "count", "sum", "avg", "min", "max", or an
+	// expression evaluated by the configured expression provider with '_'
+	// set to the rendered array, e.g. "size(_.filter(r, r.paid))".
+	// Failed expressions show "error" in their cell.
+	Summary map[string]string
+
+	// WidthPercentile sizes columnar table columns to this percentile of
+	// their value widths (e.g. 90) instead of the longest value, so a few
+	// long outliers are truncated rather than starving other columns.
+	// 0 sizes to the longest value.
+	WidthPercentile int
+
+	// TypeInference infers the type of each columnar table column (int,
+	// float, bool, date, or string) from its values: numeric columns
+	// without a ColumnHints alignment are right-aligned, and SortBy
+	// compares numbers by value (2 before 10) and dates by time.
+	TypeInference bool
+
+	// SortBy sorts the rows of columnar tables by this field, in natural
+	// order or as its inferred type with TypeInference. SortDesc sorts
+	// in descending order. An unknown field leaves rows in input order.
+	SortBy   string
+	SortDesc bool
 }
 
 // RenderTable renders a two-column key/value table for the given node.
@@ -245,13 +275,7 @@ func RenderTable(node any, opts TableOptions) string {
 	}
 
 	th := ui.CurrentTheme()
-	formatter.SetTableTheme(formatter.TableColors{
-		HeaderFG:       th.HeaderFG,
-		HeaderBG:       th.HeaderBG,
-		KeyColor:       th.KeyColor,
-		ValueColor:     th.ValueColor,
-		SeparatorColor: th.SeparatorColor,
-	})
+	applyFormatterTheme()
 
 	// Apply per-call MaxValueLines override if provided.
 	// NOTE: This temporarily mutates the package-level formatter global and is
@@ -262,6 +286,17 @@ func RenderTable(node any, opts TableOptions) string {
 		formatter.SetMaxValueLines(*opts.MaxValueLines)
 		defer formatter.SetMaxValueLines(prev)
 	}
+	// Same caveat for per-call wrapping.
+	if opts.WrapValues {
+		prev := formatter.WrapValues()
+		formatter.SetWrapValues(true)
+		defer formatter.SetWrapValues(prev)
+	}
+	if wrapKeys := wrapKeysFromHints(opts.ColumnHints); len(wrapKeys) > 0 {
+		prev := formatter.WrapKeys()
+		formatter.SetWrapKeys(wrapKeys)
+		defer formatter.SetWrapKeys(prev)
+	}
 
 	// Auto-detect terminal width if not specified
 	termWidth := opts.Width
@@ -521,6 +556,12 @@ func renderColumnarTable(node any, opts TableOptions, termWidth int) string {
 
 	th := ui.CurrentTheme()
 
+	summary, _ := formatter.SummaryRow(columns, rows, node, opts.Summary, ui.EvaluateExpression)
+	widthRows := rows
+	if summary != nil {
+		widthRows = append(rows[:len(rows):len(rows)], summary)
+	}
+
 	// Determine row number style
 	rowNumStyle := opts.ArrayStyle
 	if rowNumStyle == "" {
@@ -549,7 +590,7 @@ func renderColumnarTable(node any, opts TableOptions, termWidth int) string {
 				allHidden = append(allHidden, name)
 			}
 		}
-		naturalContentWidth := formatter.CalculateNaturalColumnarWidthWithHints(columns, rows, showRowNum, len(rows), fmtHintsForWidth, allHidden)
+		naturalContentWidth := formatter.CalculateNaturalColumnarWidthWithPercentile(columns, widthRows, showRowNum, len(rows), fmtHintsForWidth, allHidden, opts.WidthPercentile)
 		naturalTableWidth := naturalContentWidth + 2 // +2 for side borders
 		if naturalTableWidth < termWidth && !HasFlexColumn(opts.ColumnHints) {
 			tableWidth = naturalTableWidth
@@ -604,17 +645,31 @@ func renderColumnarTable(node any, opts TableOptions, termWidth int) string {
 				DisplayName: h.DisplayName,
 				Hidden:      h.Hidden,
 				Flex:        h.Flex,
+				Enum:        h.Enum,
 			}
 		}
 	}
 
+	// Badge states of the display schema color their columns' cells.
+	if opts.Schema != nil && opts.Schema.List != nil {
+		for field, states := range opts.Schema.List.BadgeStates {
+			if fmtHints == nil {
+				fmtHints = map[string]formatter.ColumnHint{}
+			}
+			h := fmtHints[field]
+			h.States = states
+			fmtHints[field] = h
+		}
+	}
+
 	// Fall back to list rendering when columns would be truncated to unreadable widths.
 	// This intentionally returns plain list output even when Bordered is true,
 	// because an unreadable truncated table inside a border is worse than a
 	// readable list without one.
 	if !formatter.IsColumnarReadable(columns, rows, contentWidth, fmtHints, formatter.IsColumnarReadableOpts{
-		HiddenColumns:  hiddenCols,
-		RowNumberStyle: rowNumStyle,
+		HiddenColumns:   hiddenCols,
+		RowNumberStyle:  rowNumStyle,
+		WidthPercentile: opts.WidthPercentile,
 	}) {
 		return formatter.FormatAsList(node, formatter.ListOptions{
 			NoColor:       opts.NoColor,
@@ -627,12 +682,17 @@ func renderColumnarTable(node any, opts TableOptions, termWidth int) string {
 	// filterColumns matches HiddenColumns correctly and the formatter
 	// applies DisplayName overrides in a single place.
 	tableView := formatter.RenderColumnarTable(columns, rows, formatter.ColumnarOptions{
-		NoColor:        opts.NoColor,
-		TotalWidth:     contentWidth,
-		RowNumberStyle: rowNumStyle,
-		ColumnOrder:    opts.ColumnOrder,
-		HiddenColumns:  hiddenCols,
-		ColumnHints:    fmtHints,
+		NoColor:         opts.NoColor,
+		TotalWidth:      contentWidth,
+		RowNumberStyle:  rowNumStyle,
+		ColumnOrder:     opts.ColumnOrder,
+		HiddenColumns:   hiddenCols,
+		ColumnHints:     fmtHints,
+		Summary:         summary,
+		WidthPercentile: opts.WidthPercentile,
+		TypeInference:   opts.TypeInference,
+		SortBy:          opts.SortBy,
+		SortDesc:        opts.SortDesc,
 	})
 
 	if !opts.Bordered {
@@ -767,27 +827,105 @@ func renderStandardTable(node any, opts TableOptions, termWidth int) string {
 // RenderList renders data in a vertical list format.
 // Arrays of objects display each element with an index header and indented properties.
 // Maps display as key/value pairs. Scalars display as "value: <v>".
+// Keys, values, and headers use the same theme colors as RenderTable.
 //
 // This is the counterpart to RenderTable: use RenderTable for columnar output
-// and RenderList for vertical per-object output.
+// and RenderList for vertical per-object output. Set Guides to expand nested
+// values with tree-style indentation guides, Bullet to mark array elements,
+// and MaxValueLen to shorten long value previews.
 //
-//	fmt.Print(tui.RenderList(data, tui.ListOptions{}))
+//	fmt.Print(tui.RenderList(data, tui.ListOptions{Guides: true, Bullet: "•", MaxValueLen: 40}))
 func RenderList(node any, opts ListOptions) string {
+	applyFormatterTheme()
 	return formatter.FormatAsList(node, opts)
 }
 
+// KeyValueOptions configures RenderKeyValue.
+type KeyValueOptions struct {
+	// NoColor disables color output.
+	NoColor bool
+
+	// Width caps each line at this many display cells; longer values are
+	// truncated, or wrapped when their hint has Wrap. 0 leaves lines unbounded.
+	Width int
+
+	// Order lists the fields shown first. The other fields follow by hint
+	// Priority (highest first), then in the active key order.
+	Order []string
+
+	// ColumnHints label (DisplayName), hide (Hidden), cap (MaxWidth), wrap
+	// (Wrap), and check (Enum) the fields, keyed by the original field name.
+	// Hints parsed by [ParseSchema] give schema titles and declaration order.
+	ColumnHints map[string]ColumnHint
+
+	// Formatters turn the values of fields into their display text, keyed by
+	// the original field name, e.g. to show a timestamp as an age.
+	Formatters map[string]func(any) string
+}
+
+// RenderKeyValue renders a single record vertically, one aligned
+// "label: value" line per field, for describe-style output. Structs are
+// rendered by their JSON fields; nested values are shown as compact JSON.
+//
+//	fmt.Print(tui.RenderKeyValue(pod, tui.KeyValueOptions{
+//		ColumnHints: map[string]tui.ColumnHint{"name": {DisplayName: "Name"}},
+//		Formatters:  map[string]func(any) string{"created": age},
+//	}))
+func RenderKeyValue(record any, opts KeyValueOptions) string {
+	applyFormatterTheme()
+	var hints map[string]formatter.ColumnHint
+	if len(opts.ColumnHints) > 0 {
+		hints = make(map[string]formatter.ColumnHint, len(opts.ColumnHints))
+		for name, h := range opts.ColumnHints {
+			hints[name] = formatter.ColumnHint{
+				MaxWidth:    h.MaxWidth,
+				Priority:    h.Priority,
+				DisplayName: h.DisplayName,
+				Hidden:      h.Hidden,
+				Wrap:        h.Wrap,
+				Enum:        h.Enum,
+			}
+		}
+	}
+	return formatter.FormatKeyValue(record, formatter.KeyValueOptions{
+		NoColor:    opts.NoColor,
+		Width:      opts.Width,
+		Order:      opts.Order,
+		Hints:      hints,
+		Formatters: opts.Formatters,
+	})
+}
+
+// applyFormatterTheme styles formatter output with the current theme colors.
+func applyFormatterTheme() {
+	th := ui.CurrentTheme()
+	formatter.SetTableTheme(formatter.TableColors{
+		HeaderFG:       th.HeaderFG,
+		HeaderBG:       th.HeaderBG,
+		KeyColor:       th.KeyColor,
+		ValueColor:     th.ValueColor,
+		SeparatorColor: th.SeparatorColor,
+		OKColor:        th.StatusSuccess,
+		ErrorColor:     th.StatusError,
+	})
+}
+
 // ListOptions controls list output formatting.
 type ListOptions = formatter.ListOptions
 
 // TreeOptions controls ASCII tree output formatting.
 type TreeOptions = formatter.TreeOptions
 
+// TreeNode describes a node passed to the TreeOptions Filter and Label callbacks.
+type TreeNode = formatter.TreeNode
+
 // MermaidOptions controls Mermaid diagram output formatting.
 type MermaidOptions = formatter.MermaidOptions
 
 // RenderTree renders data as an ASCII tree structure.
 // Maps become branches with keys as labels, arrays show indexed children,
-// and scalar values are displayed inline at leaves.
+// and scalar values are displayed inline at leaves. The options mirror the
+// --tree-* flags; Filter and Label hide or relabel individual nodes.
 //
 //	fmt.Print(tui.RenderTree(data, tui.TreeOptions{}))
 func RenderTree(node any, opts TreeOptions) string {
@@ -796,13 +934,43 @@ func RenderTree(node any, opts TreeOptions) string {
 
 // RenderMermaid renders data as a Mermaid flowchart diagram.
 // Maps become nodes with edges to child nodes, arrays show indexed children,
-// and scalar values are displayed as node labels.
+// and scalar values are displayed as node labels. The options mirror the
+// --mermaid-direction and --tree-* flags. Node IDs are hashed from each
+// node's path, so unchanged nodes keep their IDs between runs.
 //
-//	fmt.Print(tui.RenderMermaid(data, tui.MermaidOptions{}))
+//	fmt.Print(tui.RenderMermaid(data, tui.MermaidOptions{Direction: "LR"}))
 func RenderMermaid(node any, opts MermaidOptions) string {
 	return formatter.FormatAsMermaid(node, opts)
 }
 
+// MermaidNodeID returns the ID RenderMermaid gives the node at path, e.g.
+// "_" for the root, "_.items[0].name", or `_.labels["app-name"]`, for
+// styling or linking nodes. TreeNode.Path is written the same way.
+func MermaidNodeID(path string) string {
+	return formatter.MermaidNodeID(path)
+}
+
+// SerializeOptions control the layout of JSON and YAML output: indentation,
+// key sorting, single-line JSON, and the trailing newline. They mirror the
+// --indent, --sort-keys, --compact, and --no-trailing-newline flags.
+type SerializeOptions = formatter.SerializeOptions
+
+// RenderJSON renders data as JSON laid out by opts. Binary values are
+// written as base64 strings.
+//
+//	s, err := tui.RenderJSON(data, tui.SerializeOptions{Compact: true})
+func RenderJSON(node any, opts SerializeOptions) (string, error) {
+	return formatter.FormatJSON(node, opts)
+}
+
+// RenderYAML renders data as YAML laid out by opts; Compact does not apply
+// to YAML.
+//
+//	s, err := tui.RenderYAML(data, tui.SerializeOptions{Indent: 4})
+func RenderYAML(node any, opts SerializeOptions) (string, error) {
+	return formatter.FormatYAML(node, formatter.YAMLFormatOptions{SerializeOptions: opts})
+}
+
 // Render formats data according to the given OutputFormat.
 //
 // FormatTable, FormatList, and FormatAuto accept TableOptions for fine-tuning
@@ -839,26 +1007,156 @@ func Render(node any, format OutputFormat, opts TableOptions) string {
 	case FormatMermaid:
 		return RenderMermaid(node, MermaidOptions{})
 	case FormatAuto:
-		return RenderTable(node, opts)
+		return RenderAuto(node, opts)
 	}
 
 	return RenderTable(node, opts)
 }
 
+// RenderAuto renders node like the kvx CLI's default output: a columnar table
+// for arrays of objects when their columns fit, a KEY/VALUE table for other
+// objects, and one line per value for scalars and arrays of scalars.
+//
+// When columns would be truncated to unreadable widths, the lowest Priority
+// columns (see ColumnHints) are hidden until the rest fit; when no set of
+// columns fits, the array is rendered as a list instead. A single object with
+// a Schema that has a Detail config is rendered as a detail view.
+//
+//	fmt.Print(tui.RenderAuto(pods, tui.TableOptions{Bordered: true, ColumnHints: hints}))
+func RenderAuto(node any, opts TableOptions) string {
+	if opts.Schema != nil && opts.Schema.Detail != nil {
+		if _, ok := node.(map[string]any); ok {
+			return RenderSchemaView(node, opts.Schema, opts.Width, opts.NoColor)
+		}
+	}
+	if lines, ok := scalarLines(node); ok {
+		return lines
+	}
+	opts = withSchemaColumns(opts)
+	columnarMode := opts.ColumnarMode
+	if columnarMode == "" {
+		columnarMode = ColumnarModeAuto
+	}
+	if shouldUseColumnarRendering(node, columnarMode) {
+		opts.HiddenColumns = append(opts.HiddenColumns[:len(opts.HiddenColumns):len(opts.HiddenColumns)], columnsToDrop(node, opts)...)
+	}
+	return RenderTable(node, opts)
+}
+
+// scalarLines renders a scalar, or an array of scalars, one value per line.
+func scalarLines(node any) (string, bool) {
+	var values []any
+	switch v := node.(type) {
+	case map[string]any:
+		return "", false
+	case []any:
+		if len(v) == 0 {
+			return "", false
+		}
+		for _, elem := range v {
+			if isContainerValue(elem) {
+				return "", false
+			}
+		}
+		values = v
+	default:
+		if isContainerValue(node) {
+			return "", false
+		}
+		values = []any{node}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteString(formatter.StringifyPreserveNewlines(v))
+		b.WriteString("\n")
+	}
+	return b.String(), true
+}
+
+func isContainerValue(v any) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return false
+	}
+	switch rv.Kind() { //nolint:exhaustive // only containers matter
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// withSchemaColumns fills empty ColumnOrder and HiddenColumns from Schema.
+func withSchemaColumns(opts TableOptions) TableOptions {
+	if opts.Schema == nil {
+		return opts
+	}
+	order, hidden := DeriveTableOptionsFromSchema(opts.Schema)
+	if len(opts.ColumnOrder) == 0 && len(order) > 0 {
+		opts.ColumnOrder = order
+	}
+	if len(opts.HiddenColumns) == 0 && len(hidden) > 0 {
+		opts.HiddenColumns = hidden
+	}
+	return opts
+}
+
+// columnsToDrop returns the lowest priority columns to hide so the columnar
+// table of node is readable at the width of opts, or nil when it already is
+// or no set of columns would be.
+func columnsToDrop(node any, opts TableOptions) []string {
+	columns, rows := navigator.ExtractColumnarData(node, opts.ColumnOrder)
+	if columns == nil {
+		return nil
+	}
+	width := opts.Width
+	if width <= 0 {
+		width, _ = DetectTerminalSize()
+	}
+	if opts.Bordered {
+		width -= 2
+	}
+	hidden := opts.HiddenColumns
+	var hints map[string]formatter.ColumnHint
+	if len(opts.ColumnHints) > 0 {
+		hints = make(map[string]formatter.ColumnHint, len(opts.ColumnHints))
+		for name, h := range opts.ColumnHints {
+			hints[name] = formatter.ColumnHint{
+				MaxWidth:    h.MaxWidth,
+				Priority:    h.Priority,
+				DisplayName: h.DisplayName,
+				Hidden:      h.Hidden,
+				Flex:        h.Flex,
+			}
+			if h.Hidden {
+				hidden = append(hidden[:len(hidden):len(hidden)], name)
+			}
+		}
+	}
+	rowNumStyle := opts.ArrayStyle
+	if rowNumStyle == "" {
+		rowNumStyle = ArrayStyleNumbered
+	}
+	return formatter.ColumnsToDropForReadability(columns, rows, width, hints, formatter.IsColumnarReadableOpts{
+		HiddenColumns:   hidden,
+		RowNumberStyle:  rowNumStyle,
+		WidthPercentile: opts.WidthPercentile,
+	})
+}
+
 func renderYAML(node any) string {
-	b, err := yaml.Marshal(node)
+	s, err := formatter.FormatYAML(node, formatter.YAMLFormatOptions{SerializeOptions: SerializeOptions{Indent: 4}})
 	if err != nil {
 		return fmt.Sprintf("yaml marshal error: %v\n", err)
 	}
-	return string(b)
+	return s
 }
 
 func renderJSON(node any) string {
-	b, err := json.MarshalIndent(node, "", "  ")
+	s, err := formatter.FormatJSON(node, SerializeOptions{})
 	if err != nil {
 		return fmt.Sprintf("json marshal error: %v\n", err)
 	}
-	return string(b) + "\n"
+	return s
 }
 
 func renderTOML(node any) string {
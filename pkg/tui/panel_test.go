@@ -1,10 +1,15 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"charm.land/lipgloss/v2"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/oakwood-commons/kvx/internal/formatter"
+	"github.com/oakwood-commons/kvx/internal/ui"
 )
 
 func TestRenderDataPanel(t *testing.T) {
@@ -143,6 +148,24 @@ func TestRenderList(t *testing.T) {
 	}
 }
 
+func TestRenderList_UsesThemeColors(t *testing.T) {
+	formatter.SetTableTheme(formatter.TableColors{KeyColor: lipgloss.Color("1")})
+	t.Cleanup(applyFormatterTheme)
+
+	out := RenderList(map[string]any{"name": "kvx"}, ListOptions{})
+	want := lipgloss.NewStyle().Foreground(ui.CurrentTheme().KeyColor).Render("name")
+	assert.Contains(t, out, want, "keys use the theme key color like RenderTable")
+}
+
+func TestRenderList_Guides(t *testing.T) {
+	node := map[string]any{
+		"name": "kvx",
+		"meta": map[string]any{"team": "core", "tags": []any{"a", "b"}},
+	}
+	out := RenderList(node, ListOptions{NoColor: true, Guides: true, Bullet: "-", MaxValueLen: 5})
+	assert.Equal(t, "meta\n├─ tags\n│  ├─ - a\n│  └─ - b\n└─ team: core\nname: kvx\n", out)
+}
+
 func TestRenderTree(t *testing.T) {
 	node := map[string]any{"a": map[string]any{"b": 1}, "c": 2}
 	out := RenderTree(node, TreeOptions{})
@@ -151,6 +174,17 @@ func TestRenderTree(t *testing.T) {
 	}
 }
 
+func TestRenderTree_Callbacks(t *testing.T) {
+	node := map[string]any{"name": "kvx", "_internal": map[string]any{"id": 1}}
+	out := RenderTree(node, TreeOptions{
+		Filter: func(n TreeNode) bool { return !strings.HasPrefix(n.Key, "_") },
+		Label:  func(n TreeNode) string { return strings.ToUpper(n.Label) },
+	})
+	assert.Contains(t, out, "NAME: kvx")
+	assert.NotContains(t, out, "_internal")
+	assert.NotContains(t, out, "id")
+}
+
 func TestRenderMermaid(t *testing.T) {
 	node := map[string]any{"root": map[string]any{"child": "value"}}
 	out := RenderMermaid(node, MermaidOptions{})
@@ -305,6 +339,74 @@ func TestRenderTable_ArrayOfObjects_ColumnarAlways(t *testing.T) {
 	}
 }
 
+func TestRenderTable_Summary(t *testing.T) {
+	node := []any{
+		map[string]any{"name": "a", "amount": 10, "paid": true},
+		map[string]any{"name": "b", "amount": 2.5, "paid": false},
+	}
+	out := RenderTable(node, TableOptions{
+		NoColor:      true,
+		Width:        80,
+		ColumnarMode: "always",
+		ColumnOrder:  []string{"name", "amount", "paid"},
+		Summary: map[string]string{
+			"amount": "sum",
+			"paid":   "size(_.filter(r, r.paid))",
+		},
+	})
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	last := strings.Fields(lines[len(lines)-1])
+	assert.Equal(t, []string{"Σ", "12.5", "1"}, last)
+}
+
+func TestRenderTable_WidthPercentile(t *testing.T) {
+	node := []any{
+		map[string]any{"id": 1, "note": "short"},
+		map[string]any{"id": 2, "note": "short"},
+		map[string]any{"id": 3, "note": "short"},
+		map[string]any{"id": 4, "note": strings.Repeat("z", 70)},
+	}
+	opts := TableOptions{NoColor: true, Width: 120, Bordered: true, ColumnarMode: "always"}
+	full := RenderTable(node, opts)
+	opts.WidthPercentile = 75
+	narrow := RenderTable(node, opts)
+
+	assert.Contains(t, full, strings.Repeat("z", 70))
+	assert.NotContains(t, narrow, strings.Repeat("z", 9))
+	assert.Contains(t, narrow, "zzzzz...")
+	assert.Less(t, lipgloss.Width(strings.Split(narrow, "\n")[0]), lipgloss.Width(strings.Split(full, "\n")[0]))
+}
+
+func TestRenderTable_TypeInference(t *testing.T) {
+	node := []any{
+		map[string]any{"name": "item10", "size": 10, "seen": "2024-03-01"},
+		map[string]any{"name": "item2", "size": 2, "seen": "2023-12-25"},
+		map[string]any{"name": "item1", "size": 100, "seen": "2024-01-15"},
+	}
+	opts := TableOptions{NoColor: true, Width: 80, ColumnarMode: "always", ArrayStyle: "none", ColumnOrder: []string{"name", "size", "seen"}}
+	rowsOf := func(out string) []string {
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		return lines[2:]
+	}
+
+	assert.Contains(t, rowsOf(RenderTable(node, opts))[0], "item10  10 ", "numbers are left-aligned without inference")
+
+	opts.SortBy, opts.TypeInference = "size", true
+	rows := rowsOf(RenderTable(node, opts))
+	assert.Equal(t, "item2", strings.Fields(rows[0])[0], "2 sorts before 10")
+	assert.Contains(t, rows[0], "     2", "numbers are right-aligned")
+
+	opts.SortBy, opts.SortDesc = "seen", true
+	assert.Equal(t, "item10", strings.Fields(rowsOf(RenderTable(node, opts))[0])[0])
+
+	opts.SortBy, opts.SortDesc = "name", false
+	var names []string
+	for _, row := range rowsOf(RenderTable(node, opts)) {
+		names = append(names, strings.Fields(row)[0])
+	}
+	assert.Equal(t, []string{"item1", "item2", "item10"}, names)
+}
+
 func TestRenderTable_WithColumnOrder(t *testing.T) {
 	node := []any{
 		map[string]any{"z": 1, "a": 2, "m": 3},
@@ -661,6 +763,26 @@ func TestRenderTable_PerCallMaxValueLines(t *testing.T) {
 	assert.Equal(t, 10, MaxValueLines())
 }
 
+func TestRenderTable_WrapValues(t *testing.T) {
+	long := "the quick brown fox jumps over the lazy dog and keeps on running"
+	node := map[string]any{"desc": long, "note": long}
+
+	out := RenderTable(node, TableOptions{NoColor: true, Width: 50, WrapValues: true})
+	assert.Contains(t, out, "running")
+	assert.NotContains(t, out, "...")
+
+	out = RenderTable(node, TableOptions{
+		NoColor:     true,
+		Width:       50,
+		ColumnHints: map[string]ColumnHint{"note": {Wrap: true}},
+	})
+	assert.Equal(t, 1, strings.Count(out, "running"), "only the hinted key wraps")
+
+	// Globals are restored after RenderTable returns.
+	assert.False(t, formatter.WrapValues())
+	assert.Empty(t, formatter.WrapKeys())
+}
+
 func TestRenderCardList(t *testing.T) {
 	schema := &DisplaySchema{
 		Detail: &DetailDisplayConfig{
@@ -947,3 +1069,83 @@ func TestRender_ListFormatPassesColumnOrder(t *testing.T) {
 	assert.Equal(t, "apple: a", lines[1])
 	assert.Equal(t, "zebra: z", lines[2])
 }
+
+func TestRenderJSONAndYAML_SerializeOptions(t *testing.T) {
+	node := map[string]any{"b": 1, "a": []any{"x"}}
+
+	out, err := RenderJSON(node, SerializeOptions{Compact: true, NoTrailingNewline: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":["x"],"b":1}`, out)
+
+	out, err = RenderYAML(node, SerializeOptions{Indent: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, "a:\n    - x\nb: 1\n", out)
+}
+
+func TestRenderKeyValue(t *testing.T) {
+	hints, err := ParseSchema([]byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "title": "Name"},
+			"replicas": {"type": "integer", "title": "Replicas"},
+			"legacy": {"type": "string", "deprecated": true}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	out := RenderKeyValue(map[string]any{"replicas": 3, "name": "web", "legacy": "x", "extra": true}, KeyValueOptions{
+		NoColor:     true,
+		ColumnHints: hints,
+		Formatters:  map[string]func(any) string{"replicas": func(v any) string { return fmt.Sprintf("%v/3", v) }},
+	})
+	assert.Equal(t, "Name:     web\nReplicas: 3/3\nextra:    true\n", out)
+}
+
+func TestRenderAuto(t *testing.T) {
+	long := strings.Repeat("x", 30)
+	rows := []any{
+		map[string]any{"name": "web-1", "image": long, "note": long},
+		map[string]any{"name": "web-2", "image": long, "note": long},
+	}
+
+	t.Run("columnar when readable", func(t *testing.T) {
+		out := RenderAuto(rows, TableOptions{NoColor: true, Width: 120})
+		assert.Contains(t, out, "image")
+		assert.Contains(t, out, "note")
+		assert.Contains(t, out, "web-1")
+	})
+
+	t.Run("drops low priority columns", func(t *testing.T) {
+		out := RenderAuto(rows, TableOptions{
+			NoColor:     true,
+			Width:       50,
+			ColumnOrder: []string{"name", "image", "note"},
+			ColumnHints: map[string]ColumnHint{"name": {Priority: 10}, "image": {Priority: 5}},
+		})
+		assert.Contains(t, out, "image")
+		assert.NotContains(t, out, "note")
+		assert.Contains(t, out, long, "the kept columns are not truncated")
+	})
+
+	t.Run("list when no columns fit", func(t *testing.T) {
+		out := RenderAuto(rows, TableOptions{NoColor: true, Width: 12})
+		assert.Equal(t, RenderList(rows, ListOptions{NoColor: true, ArrayStyle: ArrayStyleNumbered}), out)
+	})
+
+	t.Run("key value for objects", func(t *testing.T) {
+		out := RenderAuto(map[string]any{"name": "web-1"}, TableOptions{NoColor: true, Width: 80})
+		assert.Contains(t, out, "KEY")
+		assert.Contains(t, out, "web-1")
+	})
+
+	t.Run("scalars one per line", func(t *testing.T) {
+		assert.Equal(t, "a\nb\n", RenderAuto([]any{"a", "b"}, TableOptions{Width: 80}))
+		assert.Equal(t, "42\n", RenderAuto(42, TableOptions{Width: 80}))
+	})
+
+	t.Run("Render auto format", func(t *testing.T) {
+		opts := TableOptions{NoColor: true, Width: 50, ColumnHints: map[string]ColumnHint{"name": {Priority: 10}}}
+		assert.Equal(t, RenderAuto(rows, opts), Render(rows, FormatAuto, opts))
+	})
+}
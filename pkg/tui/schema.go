@@ -3,9 +3,13 @@ package tui
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 
 	"charm.land/lipgloss/v2"
+
+	"github.com/oakwood-commons/kvx/internal/ui"
 )
 
 // ColumnHint provides display hints for a specific column in columnar table rendering.
@@ -47,6 +51,17 @@ type ColumnHint struct {
 	// Derived from JSON Schema when a property has no maxLength, enum,
 	// or format constraint (i.e. MaxWidth == 0). Can also be set manually.
 	Flex bool
+
+	// Wrap wraps this field's value onto continuation lines in KEY/VALUE
+	// tables instead of truncating it with "...".
+	// Derived from the x-kvx-wrap: true property extension.
+	Wrap bool
+
+	// Enum lists the values the column allows; cells holding any other
+	// value are drawn in a warning color. Values are compared by their
+	// displayed text.
+	// Derived from JSON Schema enum.
+	Enum []any
 }
 
 // HasFlexColumn reports whether any visible (non-hidden) hint has Flex set.
@@ -61,16 +76,29 @@ func HasFlexColumn(hints map[string]ColumnHint) bool {
 	return false
 }
 
+// wrapKeysFromHints returns the sorted names of the hints with Wrap set.
+func wrapKeysFromHints(hints map[string]ColumnHint) []string {
+	var keys []string
+	for name, h := range hints {
+		if h.Wrap {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ParseSchema extracts [ColumnHint] values from a standard JSON Schema document.
 // It reads the schema's properties (for objects) or items.properties (for arrays
 // of objects) and derives display hints from standard JSON Schema fields:
 //
 //   - title → DisplayName
 //   - maxLength → MaxWidth
-//   - enum → MaxWidth (longest enum value length)
+//   - enum → MaxWidth (longest enum value length) and Enum
 //   - format (date, date-time, uuid, uri, email, ipv4, ipv6) → MaxWidth
 //   - type (integer, number) → Align "right"
 //   - deprecated: true → Hidden
+//   - x-kvx-wrap: true → Wrap
 //   - required array → Priority boost (+10 for required properties)
 //   - Property declaration order → Priority tiebreaker (first declared = highest)
 //
@@ -154,6 +182,7 @@ func parseSchemaObject(raw map[string]any) (map[string]ColumnHint, error) { //no
 
 		// enum → MaxWidth (longest value)
 		if enumVals, ok := propMap["enum"].([]any); ok && len(enumVals) > 0 {
+			hint.Enum = enumVals
 			maxEnum := 0
 			for _, v := range enumVals {
 				s := fmt.Sprintf("%v", v)
@@ -184,6 +213,11 @@ func parseSchemaObject(raw map[string]any) (map[string]ColumnHint, error) { //no
 			hint.Hidden = true
 		}
 
+		// x-kvx-wrap → Wrap
+		if wrap, ok := propMap["x-kvx-wrap"].(bool); ok && wrap {
+			hint.Wrap = true
+		}
+
 		// Ensure MaxWidth is at least as wide as the display header so the
 		// column title is not truncated by a width cap derived from data
 		// (e.g. enum values shorter than the header text).
@@ -220,6 +254,163 @@ func parseSchemaObject(raw map[string]any) (map[string]ColumnHint, error) { //no
 	return hints, nil
 }
 
+// ParseSchemaColumnOrder returns the column order a JSON Schema declares
+// with the x-kvx-columnOrder extension, on the object schema (items, for an
+// array of objects). Pass it as [TableOptions].ColumnOrder alongside the
+// hints from [ParseSchema]. Returns nil when the schema declares no order.
+func ParseSchemaColumnOrder(schemaJSON []byte) ([]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if schemaType, _ := raw["type"].(string); schemaType == "array" {
+		items, _ := raw["items"].(map[string]any)
+		raw = items
+	}
+	return extractStringArray(raw["x-kvx-columnOrder"]), nil
+}
+
+// ParseSchemaDetail returns a detail layout for the objects a JSON Schema
+// describes (items, for an array of objects), for schemas without an
+// x-kvx-detail extension: property titles label the fields, descriptions
+// are shown under them, x-kvx-columnOrder orders them, and deprecated
+// properties are hidden, as in tables. The TUI opens
+// it for the selected array element with the detail key. Returns nil when
+// the schema declares no properties.
+func ParseSchemaDetail(schemaJSON []byte) (*DetailDisplayConfig, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	properties, _ := findProperties(raw)
+	if properties == nil {
+		return nil, nil
+	}
+	detail := &DetailDisplayConfig{}
+	detail.Labels, detail.Descriptions = propertyDocs(properties)
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		if prop, ok := properties[name].(map[string]any); ok && prop["deprecated"] == true {
+			detail.HiddenFields = append(detail.HiddenFields, name)
+		}
+	}
+	order, _ := ParseSchemaColumnOrder(schemaJSON)
+	if len(order) > 0 {
+		detail.Sections = []DetailSection{{Fields: order}}
+	}
+	return detail, nil
+}
+
+// SchemaDoc holds the titles and descriptions of a JSON Schema, shaped like
+// the documents it describes (see [ParseSchemaDocs]).
+type SchemaDoc = ui.SchemaDoc
+
+// ParseSchemaDocs returns the titles and descriptions of a JSON Schema's
+// properties, array items, and additionalProperties, nested like the
+// documents it describes. The TUI shows the description of the selected key
+// in the status bar and those of the current node in the help overlay.
+// Returns nil when the schema documents nothing.
+func ParseSchemaDocs(schemaJSON []byte) (*SchemaDoc, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return schemaDoc(raw), nil
+}
+
+// schemaDoc returns the documentation of the schema node s, or nil when
+// neither s nor anything under it has a title or description.
+func schemaDoc(s map[string]any) *SchemaDoc {
+	doc := &SchemaDoc{}
+	doc.Title, _ = s["title"].(string)
+	doc.Description, _ = s["description"].(string)
+	if props, ok := s["properties"].(map[string]any); ok {
+		for name, p := range props {
+			prop, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			if child := schemaDoc(prop); child != nil {
+				if doc.Properties == nil {
+					doc.Properties = map[string]*SchemaDoc{}
+				}
+				doc.Properties[name] = child
+			}
+		}
+	}
+	if items, ok := s["items"].(map[string]any); ok {
+		doc.Items = schemaDoc(items)
+	}
+	if additional, ok := s["additionalProperties"].(map[string]any); ok {
+		doc.Additional = schemaDoc(additional)
+	}
+	if doc.Title == "" && doc.Description == "" && doc.Properties == nil && doc.Items == nil && doc.Additional == nil {
+		return nil
+	}
+	return doc
+}
+
+// propertyDocs returns the titles and descriptions of properties by name,
+// or nil maps when none has one.
+func propertyDocs(properties map[string]any) (labels, descriptions map[string]string) {
+	for name, p := range properties {
+		prop, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if title, ok := prop["title"].(string); ok && title != "" {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[name] = title
+		}
+		if desc, ok := prop["description"].(string); ok && desc != "" {
+			if descriptions == nil {
+				descriptions = map[string]string{}
+			}
+			descriptions[name] = desc
+		}
+	}
+	return labels, descriptions
+}
+
+// ParseSchemaArrayKeys returns the x-kvx-key values of a JSON Schema: the
+// field that identifies the elements of an array, such as
+//
+//	{"type": "array", "x-kvx-key": "name", "items": {...}}
+//
+// Keys are collected from every array schema in the document, sorted by
+// property name at each level, without duplicates.
+func ParseSchemaArrayKeys(schemaJSON []byte) ([]string, error) {
+	var raw any
+	if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	var keys []string
+	collectArrayKeys(raw, &keys)
+	return keys, nil
+}
+
+func collectArrayKeys(node any, keys *[]string) {
+	switch n := node.(type) {
+	case map[string]any:
+		if k, ok := n["x-kvx-key"].(string); ok && k != "" && !slices.Contains(*keys, k) {
+			*keys = append(*keys, k)
+		}
+		names := make([]string, 0, len(n))
+		for name := range n {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			collectArrayKeys(n[name], keys)
+		}
+	case []any:
+		for _, e := range n {
+			collectArrayKeys(e, keys)
+		}
+	}
+}
+
 // findProperties locates the properties map and required list from a schema.
 // Handles both object schemas and array-of-objects schemas.
 func findProperties(schema map[string]any) (map[string]any, []string) {
@@ -47,8 +47,9 @@ func TestParseSchema_BasicProperties(t *testing.T) {
 	// description: deprecated→Hidden
 	assert.True(t, hints["description"].Hidden)
 
-	// status: enum→MaxWidth (longest value "inactive" = 8)
+	// status: enum→MaxWidth (longest value "inactive" = 8) and Enum
 	assert.Equal(t, 8, hints["status"].MaxWidth)
+	assert.Equal(t, []any{"active", "inactive", "pending"}, hints["status"].Enum)
 }
 
 func TestParseSchema_NumberType(t *testing.T) {
@@ -254,3 +255,125 @@ func TestParseSchema_HiddenColumnNotFlex(t *testing.T) {
 	assert.True(t, hints["internal_code"].Hidden, "deprecated column should be hidden")
 	assert.False(t, hints["internal_code"].Flex, "hidden column should not be flex")
 }
+
+func TestParseSchema_Wrap(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" },
+			"description": { "type": "string", "x-kvx-wrap": true }
+		}
+	}`
+
+	hints, err := ParseSchema([]byte(schema))
+	require.NoError(t, err)
+
+	assert.True(t, hints["description"].Wrap)
+	assert.False(t, hints["name"].Wrap)
+	assert.Equal(t, []string{"description"}, wrapKeysFromHints(hints))
+}
+
+func TestParseSchemaColumnOrder(t *testing.T) {
+	order, err := ParseSchemaColumnOrder([]byte(`{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"x-kvx-columnOrder": ["name", "tier", "status"],
+			"properties": {"status": {"deprecated": true}}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "tier", "status"}, order)
+
+	order, err = ParseSchemaColumnOrder([]byte(`{"type": "object", "x-kvx-columnOrder": ["b", "a"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, order)
+
+	order, err = ParseSchemaColumnOrder([]byte(`{"type": "array"}`))
+	require.NoError(t, err)
+	assert.Nil(t, order)
+
+	_, err = ParseSchemaColumnOrder([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestParseSchemaDetail(t *testing.T) {
+	detail, err := ParseSchemaDetail([]byte(`{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"x-kvx-columnOrder": ["name", "tier"],
+			"properties": {
+				"name": {"type": "string", "title": "Name", "description": "Display name"},
+				"tier": {"type": "string", "description": "Support tier"},
+				"code": {"type": "string", "deprecated": true}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, detail)
+	assert.Equal(t, map[string]string{"name": "Name"}, detail.Labels)
+	assert.Equal(t, map[string]string{"name": "Display name", "tier": "Support tier"}, detail.Descriptions)
+	assert.Equal(t, []string{"code"}, detail.HiddenFields)
+	assert.Equal(t, []DetailSection{{Fields: []string{"name", "tier"}}}, detail.Sections)
+
+	detail, err = ParseSchemaDetail([]byte(`{"type": "array", "items": {"type": "string"}}`))
+	require.NoError(t, err)
+	assert.Nil(t, detail)
+
+	_, err = ParseSchemaDetail([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestParseSchemaDocs(t *testing.T) {
+	doc, err := ParseSchemaDocs([]byte(`{
+		"type": "array",
+		"title": "Users",
+		"items": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "description": "Display name"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string", "description": "A label"}},
+				"age": {"type": "integer"}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Equal(t, "Users", doc.Title)
+	assert.Equal(t, "Display name", doc.Child("0").Child("name").Description)
+	assert.Equal(t, "A label", doc.Child("0").Child("labels").Child("team").Description)
+	assert.NotContains(t, doc.Items.Properties, "age", "undocumented properties are left out")
+
+	doc, err = ParseSchemaDocs([]byte(`{"type": "object", "properties": {"a": {"type": "string"}}}`))
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+
+	_, err = ParseSchemaDocs([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestParseSchemaArrayKeys(t *testing.T) {
+	schema := []byte(`{
+		"type": "array",
+		"x-kvx-key": "name",
+		"items": {
+			"type": "object",
+			"properties": {
+				"ports": {"type": "array", "x-kvx-key": "port"},
+				"env": {"type": "array", "x-kvx-key": "name"}
+			}
+		}
+	}`)
+	keys, err := ParseSchemaArrayKeys(schema)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "port"}, keys)
+
+	// x-kvx-key is not a display hint.
+	_, display, err := ParseSchemaWithDisplay(schema)
+	require.NoError(t, err)
+	assert.Nil(t, display)
+
+	_, err = ParseSchemaArrayKeys([]byte(`[`))
+	assert.Error(t, err)
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/google/cel-go/cel"
 	"golang.org/x/term"
 
+	"github.com/oakwood-commons/kvx/internal/termcolor"
 	"github.com/oakwood-commons/kvx/internal/ui"
+	"github.com/oakwood-commons/kvx/pkg/core"
 )
 
 // defaultFallbackTermWidth is used when terminal size cannot be detected.
@@ -87,9 +89,19 @@ func NewCELExpressionProvider(env *cel.Env, exampleHints map[string]string) Expr
 
 // Run starts the shell-backed Bubble Tea TUI with the provided root data and config.
 // Host applications can pass optional tea.ProgramOption values to control IO.
+// NO_COLOR, FORCE_COLOR, CLICOLOR and CLICOLOR_FORCE are honored; set
+// cfg.NoColor to turn color off regardless of the environment.
 func Run(root interface{}, cfg Config, opts ...tea.ProgramOption) error {
 	cfg.Apply()
 
+	mode := termcolor.ModeAuto
+	if cfg.NoColor {
+		mode = termcolor.ModeNever
+	}
+	profile := termcolor.Detect(mode, true, os.Environ())
+	cfg.NoColor = termcolor.Plain(profile)
+	opts = append([]tea.ProgramOption{tea.WithColorProfile(profile)}, opts...)
+
 	appName := strings.TrimSpace(cfg.AppName)
 	if appName == "" {
 		appName = "kvx"
@@ -126,6 +138,10 @@ func Run(root interface{}, cfg Config, opts ...tea.ProgramOption) error {
 		if cfg.AllowIntellisense != nil {
 			m.AllowIntellisense = *cfg.AllowIntellisense
 		}
+		if cfg.CompletionMatchMode != "" {
+			// An unknown mode keeps the default fuzzy matching
+			_ = m.SetCompletionMatchMode(cfg.CompletionMatchMode)
+		}
 		if strings.TrimSpace(cfg.KeyHeader) != "" {
 			m.KeyHeader = cfg.KeyHeader
 		}
@@ -160,15 +176,61 @@ func Run(root interface{}, cfg Config, opts ...tea.ProgramOption) error {
 		if cfg.DisplaySchema != nil {
 			m.DisplaySchema = cfg.DisplaySchema
 		}
+		if cfg.SchemaDocs != nil {
+			m.SchemaDocs = cfg.SchemaDocs
+		}
 		if cfg.Done != nil {
 			m.DoneChan = cfg.Done
 		}
+		if cfg.StatusController != nil {
+			m.StatusController = cfg.StatusController
+		}
+		if cfg.Notify {
+			m.Notify = true
+		}
+		if cfg.Bell != "" && ui.IsValidBellMode(cfg.Bell) {
+			m.Bell = ui.BellMode(cfg.Bell)
+		}
+		if cfg.VisualBell {
+			m.VisualBell = true
+		}
+		if cfg.IdleTimeout > 0 {
+			m.IdleTimeout = cfg.IdleTimeout
+			m.IdlePrint = cfg.IdlePrint
+		}
+		if cfg.RefreshInterval > 0 && cfg.Refresh != nil {
+			m.Refresh = cfg.Refresh
+			m.RefreshInterval = cfg.RefreshInterval
+		}
+		if cfg.Watch != "" {
+			m.WatchPath = cfg.Watch
+			m.WatchLoad = cfg.Refresh
+			if m.WatchLoad == nil {
+				path := cfg.Watch
+				m.WatchLoad = func() (any, error) { return core.LoadFile(path) }
+			}
+		}
+		if cfg.DataController != nil {
+			m.DataController = cfg.DataController
+		}
+		if cfg.OnQuit != nil {
+			onQuit := cfg.OnQuit
+			m.OnExitVisits = func(visits []ui.Visit) {
+				onQuit(QuitResult{Visits: visits})
+			}
+		}
+		if cfg.OnEvent != nil {
+			m.OnEvent = cfg.OnEvent
+		}
 		if cfg.ExpressionProvider != nil {
 			m.ExprProvider = cfg.ExpressionProvider
 		}
 		if cfg.KeyMode != "" && ui.IsValidKeyMode(cfg.KeyMode) {
 			m.KeyMode = ui.KeyMode(cfg.KeyMode)
 		}
+		if cfg.Kiosk {
+			m.SetKiosk(true)
+		}
 	}
 
 	return ui.RunModel(appName, root, helpTitle, helpText, cfg.DebugEnabled, cfg.DebugSink, cfg.InitialExpr, cfg.Width, cfg.Height, cfg.StartKeys, cfg.NoColor, cfg.ExprModeEntryHelp, cfg.FunctionHelpOverrides, configure, opts...)
@@ -230,6 +292,10 @@ func RenderSnapshot(root interface{}, cfg Config) string {
 		if cfg.AllowIntellisense != nil {
 			m.AllowIntellisense = *cfg.AllowIntellisense
 		}
+		if cfg.CompletionMatchMode != "" {
+			// An unknown mode keeps the default fuzzy matching
+			_ = m.SetCompletionMatchMode(cfg.CompletionMatchMode)
+		}
 		if cfg.AllowDecode != nil {
 			m.AllowDecode = *cfg.AllowDecode
 		}
@@ -239,12 +305,18 @@ func RenderSnapshot(root interface{}, cfg Config) string {
 		if cfg.DisplaySchema != nil {
 			m.DisplaySchema = cfg.DisplaySchema
 		}
+		if cfg.SchemaDocs != nil {
+			m.SchemaDocs = cfg.SchemaDocs
+		}
 		if cfg.ExpressionProvider != nil {
 			m.ExprProvider = cfg.ExpressionProvider
 		}
 		if cfg.KeyMode != "" && ui.IsValidKeyMode(cfg.KeyMode) {
 			m.KeyMode = ui.KeyMode(cfg.KeyMode)
 		}
+		if cfg.Kiosk {
+			m.SetKiosk(true)
+		}
 	}
 
 	return ui.RenderModelSnapshot(root, snapCfg)
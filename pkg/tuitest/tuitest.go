@@ -0,0 +1,127 @@
+// Package tuitest helps golden-test UIs built on kvx: it renders TUI
+// frames without a terminal, normalizes them so they compare the same on
+// every machine, and diffs them against golden files.
+//
+//	func TestViewer(t *testing.T) {
+//		frame := tuitest.Render(root, tui.Config{Width: 80, Height: 20, StartKeys: []string{"/alice"}})
+//		tuitest.AssertGolden(t, tuitest.GoldenFile(t), frame)
+//	}
+//
+// Run the tests with UPDATE_GOLDEN=1 to write the golden files from the
+// current frames.
+package tuitest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/oakwood-commons/kvx/pkg/tui"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden write the
+// golden files instead of comparing against them, when set to 1.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Render renders the frame the TUI shows for root with cfg, after cfg's
+// StartKeys, and normalizes it. Width and Height default to 80x24.
+func Render(root interface{}, cfg tui.Config) string {
+	return Normalize(tui.RenderSnapshot(root, cfg))
+}
+
+// Normalize strips ANSI escape sequences, turns CRLF line endings into LF,
+// trims trailing spaces from each line, and drops trailing blank lines, so
+// frames rendered with or without color, or on another OS, compare equal.
+func Normalize(frame string) string {
+	frame = ansi.Strip(strings.ReplaceAll(frame, "\r\n", "\n"))
+	lines := strings.Split(frame, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// Diff returns a line diff of want and got, with "-" marking lines only in
+// want, "+" lines only in got, and two spaces lines in both. It returns ""
+// when they are equal.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
+
+// GoldenFile returns the default golden file of t:
+// testdata/<test name>.golden, with subtest separators kept as directories.
+func GoldenFile(t testing.TB) string {
+	t.Helper()
+	return filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+}
+
+// AssertGolden normalizes got and compares it with the golden file at path,
+// failing t with a diff when they differ. With UPDATE_GOLDEN=1 it writes
+// got to path instead.
+func AssertGolden(t testing.TB, path, got string) {
+	t.Helper()
+	got = Normalize(got)
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got+"\n"), 0o644); err != nil { //nolint:gosec // golden files are not secret
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with %s=1 to create it): %v", UpdateEnv, err)
+	}
+	if diff := Diff(Normalize(string(want)), got); diff != "" {
+		t.Errorf("frame differs from %s (-want +got):\n%s", path, diff)
+	}
+}
+
+// AssertFrame fails t with a diff when the normalized frames want and got
+// differ.
+func AssertFrame(t testing.TB, want, got string) {
+	t.Helper()
+	if diff := Diff(Normalize(want), Normalize(got)); diff != "" {
+		t.Errorf("frames differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,98 @@
+package tuitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oakwood-commons/kvx/pkg/tui"
+)
+
+// recordingT records the failures of an assertion under test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// Fatalf records the failure and stops the goroutine, like testing.T.
+func (r *recordingT) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// run runs assert in a goroutine of its own, which Fatalf may stop.
+func (r *recordingT) run(assert func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert()
+	}()
+	<-done
+}
+
+func sampleRoot() map[string]interface{} {
+	return map[string]interface{}{
+		"name":  "kvx",
+		"items": []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}},
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "╭─ a ─╮\n│ b\n╰", Normalize("\x1b[1m╭─ a ─╮\x1b[m  \r\n│ b \x1b[7m \x1b[m\r\n╰\n\n\n"))
+}
+
+func TestDiff(t *testing.T) {
+	assert.Empty(t, Diff("a\nb", "a\nb"))
+	assert.Equal(t, "  a\n- b\n+ B\n  c\n+ d\n", Diff("a\nb\nc", "a\nB\nc\nd"))
+}
+
+func TestRenderGolden(t *testing.T) {
+	frame := Render(sampleRoot(), tui.Config{Width: 50, Height: 10, StartKeys: []string{"j"}})
+	assert.NotContains(t, frame, "\x1b[")
+	AssertGolden(t, GoldenFile(t), frame)
+
+	// Color makes no difference once normalized.
+	AssertFrame(t, frame, tui.RenderSnapshot(sampleRoot(), tui.Config{Width: 50, Height: 10, StartKeys: []string{"j"}}))
+}
+
+func TestAssertGoldenReportsDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frame.golden")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\n"), 0o600))
+
+	rec := &recordingT{TB: t}
+	AssertGolden(rec, path, "one  \ntwo")
+	assert.Empty(t, rec.errors)
+
+	AssertGolden(rec, path, "one\n2")
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "  one\n- two\n+ 2\n")
+
+	t.Setenv(UpdateEnv, "1")
+	AssertGolden(rec, path, "one\n2\x1b[m   ")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "one\n2\n", string(data))
+
+	t.Setenv(UpdateEnv, "")
+	rec = &recordingT{TB: t}
+	rec.run(func() { AssertGolden(rec, filepath.Join(t.TempDir(), "missing.golden"), "x") })
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "run with UPDATE_GOLDEN=1 to create it")
+}
+
+func TestGoldenFile(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {
+		assert.Equal(t, filepath.Join("testdata", "TestGoldenFile", "sub.golden"), GoldenFile(t))
+	})
+}